@@ -0,0 +1,54 @@
+//go:build js || wasm
+
+package pages
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ForgeLogic/nojs/notify"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// NotificationsPage raises toasts through the Notifier shared with the toast host app.
+type NotificationsPage struct {
+	runtime.ComponentBase
+	Toasts *notify.Notifier `nojs:"inject"`
+
+	RenderCount int
+}
+
+func (c *NotificationsPage) OnParametersSet() {
+	c.RenderCount++
+}
+
+func (c *NotificationsPage) ShowSuccess() {
+	c.Toasts.Success("Changes saved.", notify.Options{})
+}
+
+func (c *NotificationsPage) ShowInfo() {
+	c.Toasts.Info("A new version is available.", notify.Options{})
+}
+
+func (c *NotificationsPage) ShowError() {
+	c.Toasts.Error("Could not reach the server.", notify.Options{})
+}
+
+func (c *NotificationsPage) ShowSticky() {
+	c.Toasts.Info("This one stays until you dismiss it.", notify.Options{Duration: -1})
+}
+
+func (c *NotificationsPage) ShowBurst() {
+	for i := 1; i <= 5; i++ {
+		c.Toasts.Info(fmt.Sprintf("Notification %d of 5", i), notify.Options{})
+	}
+}
+
+// SaveInBackground raises its toasts from a goroutine, as a handler waiting on the network would.
+func (c *NotificationsPage) SaveInBackground() {
+	c.Toasts.Info("Saving...", notify.Options{Duration: 2 * time.Second})
+	go func() {
+		time.Sleep(2 * time.Second)
+		c.Toasts.Success("Saved in the background.", notify.Options{})
+	}()
+}
@@ -3,8 +3,10 @@
 package shared
 
 import (
+	"errors"
 	"fmt"
 
+	router "github.com/ForgeLogic/nojs-router"
 	"github.com/ForgeLogic/nojs/events"
 	"github.com/ForgeLogic/nojs/runtime"
 	"github.com/ForgeLogic/nojs/vdom"
@@ -15,8 +17,12 @@ import (
 //
 // Props:
 //   - Href: The path to navigate to (e.g., "/about", "/users/123")
+//   - Exact: Only mark the link active on Href itself, not on the paths below it
 //   - Children: The content to display inside the link (text, other components, etc.)
 //
+// The <a> carries the class "active" while Href is the current route (see
+// router.Engine.IsActive); the link watches path changes from OnMount.
+//
 // Example usage in a template:
 //
 //	<RouterLink Href="/about">
@@ -30,8 +36,34 @@ type RouterLink struct {
 	// Href is the destination path for navigation
 	Href string
 
+	// Exact limits the active state to Href itself; the home link ("/") needs it
+	Exact bool
+
 	// Children contains the content projected into the link
 	Children []*vdom.VNode
+
+	Router *router.Engine `nojs:"inject"`
+
+	// Active is set while Href is the current route
+	Active bool `nojs:"state"`
+
+	stopWatching func()
+}
+
+func (c *RouterLink) OnMount() {
+	c.Active = c.Router.IsActive(c.Href, c.Exact)
+	c.stopWatching = c.Router.SubscribePathChange(func(string) {
+		if active := c.Router.IsActive(c.Href, c.Exact); active != c.Active {
+			c.Active = active
+			c.StateHasChanged()
+		}
+	})
+}
+
+func (c *RouterLink) OnUnmount() {
+	if c.stopWatching != nil {
+		c.stopWatching()
+	}
 }
 
 // HandleClick is called when the link is clicked.
@@ -43,8 +75,10 @@ func (c *RouterLink) HandleClick(e events.ClickEventArgs) {
 	println("[RouterLink.HandleClick] Href value: ", c.Href)
 	println("[RouterLink.HandleClick] c pointer:", fmt.Sprintf("%p", c))
 
-	// Use the framework's client-side router to navigate
-	if err := c.Navigate(c.Href); err != nil {
+	// Use the framework's client-side router to navigate. Clicking the link of the current
+	// page, or clicking again before the page has changed, is not an error.
+	err := c.Navigate(c.Href)
+	if err != nil && !errors.Is(err, router.ErrAlreadyCurrent) && !errors.Is(err, router.ErrAlreadyNavigating) {
 		println("[RouterLink] Navigation error:", err.Error())
 	}
 }
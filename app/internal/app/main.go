@@ -5,12 +5,18 @@ package main
 import (
 	sharedlayouts "github.com/ForgeLogic/app/internal/app/components/shared/layouts"
 	"github.com/ForgeLogic/app/internal/app/context"
+	"github.com/ForgeLogic/nojs"
 	router "github.com/ForgeLogic/nojs-router"
 	"github.com/ForgeLogic/nojs/console"
+	"github.com/ForgeLogic/nojs/notify"
+	"github.com/ForgeLogic/nojs/notify/toast"
 	"github.com/ForgeLogic/nojs/runtime"
 )
 
 func main() {
+	// Report startup panics to the bootstrap loader so it can show its error UI
+	defer runtime.RecoverBootPanic()
+
 	// Create shared layout context
 	mainLayoutCtx := &context.MainLayoutCtx{
 		Title: "My App",
@@ -21,29 +27,41 @@ func main() {
 		MainLayoutCtx: mainLayoutCtx,
 	}
 
-	// Create the router engine first (it will be passed as navigation manager to renderer)
+	// Create the router engine (the renderer is injected by nojs.Run)
 	routerEngine := router.NewEngine(nil)
 
-	// Create the renderer with the engine as the navigation manager
-	renderer := runtime.NewRenderer(routerEngine, "#app")
-
-	// Set the renderer on the engine so it can render components
-	routerEngine.SetRenderer(renderer)
-
 	// Register routes with their components and layouts
 	registerRoutes(routerEngine, mainLayout, mainLayoutCtx)
 
 	// Create AppShell to wrap the router's page rendering
 	appShell := router.NewAppShell(mainLayout)
-	renderer.SetCurrentComponent(appShell, "app-shell")
-	renderer.ReRender()
 
-	// Initialize the router with a callback to update AppShell when navigation occurs
-	err := routerEngine.Start(func(chain []runtime.Component, key string) {
-		appShell.SetPage(chain, key)
+	// Toasts are raised by pages through the injected Notifier and rendered by a host
+	// mounted as its own app on #toasts, outside the layout
+	toasts := notify.New(notify.Config{})
+
+	// Mount the app; the router updates the AppShell when navigation occurs
+	_, err := nojs.Run(nojs.Options{
+		Name:          "app",
+		Mount:         "#app",
+		Root:          appShell,
+		Navigation:    routerEngine,
+		OnRouteChange: appShell.SetPage,
+		Provide:       []any{toasts},
+	})
+	if err != nil {
+		console.Error("Failed to start app:", err.Error())
+		panic(err)
+	}
+
+	_, err = nojs.Run(nojs.Options{
+		Name:    "toasts",
+		Mount:   "#toasts",
+		Root:    &toast.ToastHost{},
+		Provide: []any{toasts},
 	})
 	if err != nil {
-		console.Error("Failed to start router:", err.Error())
+		console.Error("Failed to start toast host:", err.Error())
 		panic(err)
 	}
 
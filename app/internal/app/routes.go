@@ -65,6 +65,13 @@ func registerRoutes(routerEngine *router.Engine, mainLayout *sharedlayouts.MainL
 				{Factory: func(p map[string]string) runtime.Component { return &pages.SlotsPage{} }, TypeID: SlotsPage_TypeID},
 			},
 		},
+		{
+			Path: "/notifications",
+			Chain: []router.ComponentMetadata{
+				{Factory: ml, TypeID: MainLayout_TypeID},
+				{Factory: func(p map[string]string) runtime.Component { return &pages.NotificationsPage{} }, TypeID: NotificationsPage_TypeID},
+			},
+		},
 		{
 			Path: "/router/{id}",
 			Chain: []router.ComponentMetadata{
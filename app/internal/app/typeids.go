@@ -9,14 +9,15 @@ const (
 	MainLayout_TypeID uint32 = 100
 
 	// Pages
-	LandingPage_TypeID      uint32 = 200
-	CounterPage_TypeID      uint32 = 300
-	LifecyclePage_TypeID    uint32 = 400
-	FormsPage_TypeID        uint32 = 500
-	ConditionalsPage_TypeID uint32 = 600
-	ListsPage_TypeID        uint32 = 700
-	SlotsPage_TypeID        uint32 = 800
-	RouterParamsPage_TypeID uint32 = 900
+	LandingPage_TypeID       uint32 = 200
+	CounterPage_TypeID       uint32 = 300
+	LifecyclePage_TypeID     uint32 = 400
+	FormsPage_TypeID         uint32 = 500
+	ConditionalsPage_TypeID  uint32 = 600
+	ListsPage_TypeID         uint32 = 700
+	SlotsPage_TypeID         uint32 = 800
+	RouterParamsPage_TypeID  uint32 = 900
+	NotificationsPage_TypeID uint32 = 1100
 
 	// Shared
 	PageNotFound_TypeID uint32 = 1000
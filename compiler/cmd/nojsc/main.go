@@ -4,6 +4,8 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	compiler "github.com/ForgeLogic/nojs-compiler"
 )
@@ -11,15 +13,89 @@ import (
 func main() {
 	inDir := flag.String("in", ".", "The source directory to scan for *.gt.html files.")
 	devMode := flag.Bool("dev", false, "Enable development mode (warnings, verbose errors, panic on lifecycle failures)")
+	strict := flag.Bool("strict", false, "Fail compilation when the HTML parser relocates or drops template markup (reported as a warning in dev mode).")
+	loaderOut := flag.String("loader", "", "If set, also write the bootstrap loader script to this path (e.g., ./wwwroot/nojs-loader.js).")
+	loaderWasm := flag.String("loader-wasm", "main.wasm", "URL of the WASM module, used by the loader.")
+	loaderMount := flag.String("loader-mount", "#app", "CSS selector of the mount element, used by the loader.")
+	loaderBeacon := flag.String("loader-beacon", "", "Optional URL the loader POSTs boot failures to.")
+	loaderFallback := flag.String("loader-fallback", "", "Optional HTML file shown when the browser lacks WebAssembly support.")
+	preloadAssets := flag.String("preload-assets", "", "Directory preloaded asset URLs resolve against (e.g., ./wwwroot); declared assets must exist in it.")
+	preloadManifest := flag.String("preload-manifest", "", "If set, write the route->preload assets manifest (JSON) to this path.")
+	preloadShell := flag.String("preload-shell", "", "HTML shell to generate per-route shells from (e.g., ./wwwroot/index.html).")
+	preloadShellOut := flag.String("preload-shell-out", "", "If set, write one shell per typed route, with its preload links, to this directory.")
+	swOut := flag.String("sw", "", "If set, also write the offline-first service worker script to this path (e.g., ./wwwroot/sw.js).")
+	swWasm := flag.String("sw-wasm", "main.wasm", "URL of the WASM module, precached by the service worker.")
+	swWasmFile := flag.String("sw-wasm-file", "", "Compiled WASM module whose contents version the service worker caches (e.g., ./wwwroot/main.wasm).")
+	swBuildHash := flag.String("sw-build-hash", "", "Version of the service worker caches; overrides the hash of -sw-wasm-file.")
+	swShells := flag.String("sw-shells", "index.html", "Comma-separated HTML shells precached by the service worker; offline navigations fall back to the first.")
 	flag.Parse()
 
 	fmt.Printf("Starting compilation...\nSource directory: %s\n", *inDir)
 	if *devMode {
 		fmt.Printf("Development mode: ENABLED\n")
 	}
-	err := compiler.Compile(*inDir, *devMode)
+	err := compiler.CompileWithOptions(*inDir, compiler.Options{DevMode: *devMode, Strict: *strict})
 	if err != nil {
-		log.Fatalf("Compilation failed: %v", err)
+		fmt.Fprintf(os.Stderr, "Compilation failed:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	if *loaderOut != "" {
+		cfg := compiler.DefaultLoaderConfig()
+		cfg.WasmURL = *loaderWasm
+		cfg.MountSelector = *loaderMount
+		cfg.BeaconURL = *loaderBeacon
+		if *loaderFallback != "" {
+			fallback, err := os.ReadFile(*loaderFallback)
+			if err != nil {
+				log.Fatalf("Failed to read loader fallback HTML: %v", err)
+			}
+			cfg.FallbackHTML = string(fallback)
+		}
+		if err := compiler.WriteLoader(*loaderOut, cfg); err != nil {
+			log.Fatalf("Failed to write loader: %v", err)
+		}
+		fmt.Printf("Generated bootstrap loader: %s\n", *loaderOut)
+	}
+
+	if *preloadManifest != "" || *preloadShellOut != "" {
+		err := compiler.WritePreloads(*inDir, compiler.PreloadConfig{
+			AssetsDir:    *preloadAssets,
+			ManifestPath: *preloadManifest,
+			ShellPath:    *preloadShell,
+			ShellOutDir:  *preloadShellOut,
+		})
+		if err != nil {
+			log.Fatalf("Failed to generate preloads: %v", err)
+		}
+	}
+
+	if *swOut != "" {
+		cfg := compiler.DefaultServiceWorkerConfig()
+		cfg.WasmURL = *swWasm
+		cfg.Shells = strings.Split(*swShells, ",")
+		cfg.BuildHash = *swBuildHash
+		if cfg.BuildHash == "" {
+			if *swWasmFile == "" {
+				log.Fatalf("The service worker needs -sw-wasm-file or -sw-build-hash to version its caches")
+			}
+			hash, err := compiler.BuildHash(*swWasmFile)
+			if err != nil {
+				log.Fatalf("Failed to write service worker: %v", err)
+			}
+			cfg.BuildHash = hash
+		}
+		if *preloadManifest != "" {
+			assets, err := compiler.ManifestAssets(*preloadManifest)
+			if err != nil {
+				log.Fatalf("Failed to write service worker: %v", err)
+			}
+			cfg.Assets = assets
+		}
+		if err := compiler.WriteServiceWorker(*swOut, cfg); err != nil {
+			log.Fatalf("Failed to write service worker: %v", err)
+		}
+		fmt.Printf("Generated service worker (build %s): %s\n", cfg.BuildHash, *swOut)
 	}
 
 	fmt.Printf("🎉 Compilation completed successfully!\n")
@@ -1,52 +1,170 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
 	"go/format"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
 )
 
-// compileComponentTemplate reads a .gt.html template, parses it, generates Go code,
-// formats it, and writes the result to a .generated.go file next to the template.
-func compileComponentTemplate(comp componentInfo, componentMap map[string]componentInfo, inDir string, opts compileOptions) error {
+// warningOutput receives the template warnings of dev compiles. Tests capture it.
+var warningOutput io.Writer = os.Stderr
+
+// errorCollector gathers the errors of a template. Generation records an error for the
+// node or attribute that caused it and goes on with the rest, so one compile reports every
+// mistake in the template instead of only the first.
+type errorCollector struct {
+	errs []error
+	seen map[string]bool
+}
+
+// add records err, unless an error with the same message was recorded already: the nodes
+// of a {@define} block are generated once per {@render} of it.
+func (e *errorCollector) add(err error) {
+	msg := err.Error()
+	if e.seen[msg] {
+		return
+	}
+	if e.seen == nil {
+		e.seen = make(map[string]bool)
+	}
+	e.seen[msg] = true
+	e.errs = append(e.errs, err)
+}
+
+// err returns the recorded errors, joined, or nil when there are none.
+func (e *errorCollector) err() error {
+	return errors.Join(e.errs...)
+}
+
+// mapErr returns the code f generates for each item, stopping at the first error.
+func mapErr[T any](items []T, f func(T) (string, error)) ([]string, error) {
+	codes := make([]string, 0, len(items))
+	for _, item := range items {
+		code, err := f(item)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// compileComponentTemplate reads a .gt.html template, parses it, generates Go code and
+// formats it. It returns the path of the .generated.go file next to the template and its
+// source; the caller writes it once every generated file has been verified.
+func compileComponentTemplate(comp componentInfo, componentMap map[string]componentInfo, inDir string, opts compileOptions) (string, []byte, error) {
 	htmlContent, err := os.ReadFile(comp.Path)
 	if err != nil {
-		return fmt.Errorf("failed to read template file %s: %w", comp.Path, err)
+		return "", nil, fmt.Errorf("failed to read template file %s: %w", comp.Path, err)
 	}
 	htmlString := string(htmlContent)
 
+	// Drop {@* ... *@} compiler comments before anything reads the template
+	htmlString, err = stripTemplateComments(htmlString, comp.Path)
+	if err != nil {
+		return "", nil, err // Error message already includes template path and details
+	}
+
+	// Hide comments and <style>/<script> bodies from the directive preprocessors
+	htmlString, rawRegions := maskRawRegions(htmlString)
+
+	// Keep escaped braces (&#123;) from being decoded into bindings
+	htmlString = protectEscapedBraces(htmlString)
+
+	// Inline local {@define}/{@render} blocks before the other directives are processed
+	htmlString, err = preprocessBlocks(htmlString, comp.Path)
+	if err != nil {
+		return "", nil, err // Error message already includes template path and details
+	}
+
+	// Reject blocks that open in one element and close in another before they become placeholders
+	if err := checkDirectiveNesting(htmlString, comp.Path); err != nil {
+		return "", nil, err
+	}
+
 	// Preprocess conditional blocks with validation
 	htmlString, err = preprocessConditionals(htmlString, comp.Path)
 	if err != nil {
-		return err // Error message already includes template path and details
+		return "", nil, err // Error message already includes template path and details
+	}
+
+	// Preprocess switch blocks with validation
+	htmlString, err = preprocessSwitch(htmlString, comp.Path)
+	if err != nil {
+		return "", nil, err // Error message already includes template path and details
+	}
+
+	// Preprocess slot fallback blocks with validation
+	htmlString, err = preprocessSlots(htmlString, comp.Path)
+	if err != nil {
+		return "", nil, err // Error message already includes template path and details
 	}
 
 	// Preprocess for-loop blocks with validation
 	htmlString, err = preprocessFor(htmlString, comp.Path)
 	if err != nil {
-		return err // Error message already includes template path and details
+		return "", nil, err // Error message already includes template path and details
 	}
 
+	// Keep the HTML parser from dropping directives placed inside a <select>
+	htmlString = preprocessSelect(htmlString)
+
+	htmlString = restoreRawRegions(htmlString, rawRegions)
+
 	doc, err := html.Parse(strings.NewReader(htmlString))
 	if err != nil {
-		return fmt.Errorf("failed to parse HTML: %w", err)
+		return "", nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	// Located before the <go-select> placeholders are renamed, while the tags match the source
+	lines := newTemplateLines(comp.Path, htmlString, doc)
+
+	// Surface markup the HTML5 parser relocated or dropped instead of compiling it silently
+	if opts.DevMode || opts.Strict {
+		if issue := verifyTemplateStructure(htmlString, doc); issue != nil {
+			if opts.Strict {
+				return "", nil, fmt.Errorf("template structure error in %s", formatMarkupIssue(comp.Path, htmlString, issue))
+			}
+			fmt.Fprintf(warningOutput, "Warning in %s\n", formatMarkupIssue(comp.Path, htmlString, issue))
+		}
 	}
+	restoreSelectElements(doc)
 	bodyNode := findBody(doc)
 	if bodyNode == nil {
-		return fmt.Errorf("could not find <body> tag")
+		return "", nil, fmt.Errorf("could not find <body> tag")
 	}
 
 	rootElement := findFirstElementChild(bodyNode)
 	if rootElement == nil {
-		return fmt.Errorf("no element found inside <body> tag to compile")
+		return "", nil, fmt.Errorf("no element found inside <body> tag to compile")
+	}
+
+	if err := checkSingleRoot(rootElement, lines, comp.Path); err != nil {
+		return "", nil, err
+	}
+	if err := checkRootConditional(rootElement, lines, comp.Path); err != nil {
+		return "", nil, err
 	}
 
-	// Collect components used from other packages
-	usedPackages := collectUsedComponents(rootElement, componentMap, comp)
+	// Reject prop attributes the parser renamed before they silently go missing
+	if err := checkConsumedAttributes(rootElement, componentMap, lines, comp.Path); err != nil {
+		return "", nil, err
+	}
+
+	// Flag labels that would show an unset number or time as "Year: 0"
+	if opts.DevMode {
+		for _, warning := range checkZeroValuePlaceholders(rootElement, comp, string(htmlContent)) {
+			fmt.Fprintf(warningOutput, "Warning in %s\n", warning)
+		}
+	}
 
 	// Initialize template-wide component counter so every RenderChild key is unique
 	// regardless of where in the tree the component appears. Using sibling-position
@@ -54,19 +172,58 @@ func compileComponentTemplate(comp componentInfo, componentMap map[string]compon
 	// different parent containers (e.g. multiple RouterLinks each being the 3rd child
 	// of their respective parent divs all get "RouterLink_3").
 	opts.ComponentCounter = make(map[string]int)
+	opts.Imports = make(map[string]string)
+	opts.PackageAliases = collectUsedComponents(rootElement, componentMap, comp)
+	opts.Errors = &errorCollector{}
+	opts.Lines = lines
 
 	// Generate code for a single root node
 	generatedCode := generateNodeCode(rootElement, "c", componentMap, comp, htmlString, opts, nil)
+	if err := opts.Errors.err(); err != nil {
+		return "", nil, err
+	}
+	generatedCode = restoreEscapedBraces(generatedCode)
+	if strings.HasPrefix(generatedCode, fragmentPrefix) {
+		// A root {@if} renders one node per branch (see checkRootConditional), or none
+		generatedCode = fmt.Sprintf("func() *vdom.VNode {\nif nodes := %s; len(nodes) > 0 {\nreturn nodes[0]\n}\nreturn nil\n}()", generatedCode)
+	}
 
 	// Generate the ApplyProps method body
 	applyPropsBody := generateApplyPropsBody(comp)
 
-	// Build additional imports for cross-package components
+	// Generate PropsEqual for components marked //nojs:memo
+	propsEqualMethod, uncomparable := generatePropsEqualMethod(comp)
+	if uncomparable != "" && opts.DevMode {
+		fmt.Fprintf(warningOutput, "Warning in %s: component '%s' is marked %s, but its prop '%s' cannot be compared, so it renders every time its parent does. Pass a slice, or a pointer to the value, instead.\n",
+			comp.Path, comp.PascalName, memoMarker, uncomparable)
+	}
+
+	// Build additional imports for cross-package components and the types their props need
+	usedPackages := make(map[string]string, len(opts.Imports)+len(opts.PackageAliases))
+	for importPath, name := range opts.PackageAliases {
+		usedPackages[name] = importPath
+	}
+	for name, importPath := range opts.Imports {
+		if other, taken := usedPackages[name]; taken && other != importPath {
+			return "", nil, fmt.Errorf("compilation error in %s: the generated code refers to both %s and %s as '%s'. Give %s another import name in the Go file that uses it for a prop type", comp.Path, other, importPath, name, importPath)
+		}
+		usedPackages[name] = importPath
+	}
+	names := make([]string, 0, len(usedPackages))
+	for name := range usedPackages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 	var additionalImports strings.Builder
-	if len(usedPackages) > 0 {
+	if len(names) > 0 {
 		additionalImports.WriteString("\n")
-		for _, importPath := range usedPackages {
-			fmt.Fprintf(&additionalImports, "\t\"%s\"\n", importPath)
+		for _, name := range names {
+			importPath := usedPackages[name]
+			if name == path.Base(importPath) {
+				fmt.Fprintf(&additionalImports, "\t%s\n", strconv.Quote(importPath))
+			} else {
+				fmt.Fprintf(&additionalImports, "\t%s %s\n", name, strconv.Quote(importPath))
+			}
 		}
 	}
 
@@ -79,6 +236,7 @@ package %[2]s
 import (
 	"fmt"
 	"strconv" // Added for type conversions
+	"strings"
 
 	"github.com/ForgeLogic/nojs/console"
 	"github.com/ForgeLogic/nojs/events"
@@ -102,33 +260,83 @@ func (c *%[1]s) ApplyProps(source runtime.Component) {
 func (c *%[1]s) Render(r runtime.Renderer) *vdom.VNode {
 	_ = strconv.Itoa // Suppress unused import error if no props are converted
 	_ = fmt.Sprintf  // Suppress unused import error if no bindings are used
+	_ = strings.Builder{} // Suppress unused import error if no {classes} expressions are used
 	_ = console.Log  // Suppress unused import error if no loops use dev warnings
 	_ = events.AdaptNoArgEvent // Suppress unused import error if no event handlers are used
-
+%[8]s
 	return %[3]s
 }
-`
+%[7]s
+%[6]s`
 
-	source := fmt.Sprintf(template, comp.PascalName, comp.PackageName, generatedCode, applyPropsBody, additionalImports.String())
+	// Give the methods after Render, if any, back to the generated file
+	methods := generateHistoryStateMethods(comp) + propsEqualMethod
+	lineMarker := ""
+	if methods != "" {
+		lineMarker = generatedLineMarker
+	}
+	// A form is validated as the component: Bind does nothing once it is bound
+	prologue := ""
+	if comp.Schema.Form {
+		prologue = "\tc.Validator.Bind(c)\n"
+	}
+	source := []byte(fmt.Sprintf(template, comp.PascalName, comp.PackageName, generatedCode, applyPropsBody, additionalImports.String(), methods, lineMarker, prologue))
+	if generatedSourceHook != nil {
+		source = generatedSourceHook(comp, source)
+	}
 
 	// Format the generated source code
-	formattedSource, err := format.Source([]byte(source))
+	formattedSource, err := format.Source(source)
 	if err != nil {
-		return fmt.Errorf("failed to format generated code: %w", err)
+		return "", nil, fmt.Errorf("failed to format generated code: %w", err)
 	}
 
 	outFileName := fmt.Sprintf("%s.generated.go", comp.PascalName)
+	formattedSource = resetGeneratedLines(formattedSource, outFileName)
 
 	// Generate file in the same directory as the template
 	templateDir := filepath.Dir(comp.Path)
-	outFilePath := filepath.Join(templateDir, outFileName)
-	return os.WriteFile(outFilePath, formattedSource, 0644)
+	return filepath.Join(templateDir, outFileName), formattedSource, nil
+}
+
+// generateHistoryStateMethods generates the runtime.HistoryStateful methods of a component
+// with state fields tagged `nojs:"state,history=key"`, or "" when it has none. The router
+// calls them to keep those fields in the state of the browser history entry.
+func generateHistoryStateMethods(comp componentInfo) string {
+	var fields []propertyDescriptor
+	for _, field := range comp.Schema.State {
+		if field.HistoryKey != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].HistoryKey < fields[j].HistoryKey })
+
+	var save, restore strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&save, "\tif err := bag.Set(%q, c.%s); err != nil {\n\t\treturn err\n\t}\n", field.HistoryKey, field.Name)
+		fmt.Fprintf(&restore, "\tbag.Get(%q, &c.%s)\n", field.HistoryKey, field.Name)
+	}
+	return fmt.Sprintf(`
+// SaveHistoryState stores the history state fields of %[1]s in bag.
+// This method is generated automatically by the compiler.
+func (c *%[1]s) SaveHistoryState(bag runtime.HistoryBag) error {
+%[2]s	return nil
+}
+
+// RestoreHistoryState sets the history state fields of %[1]s found in bag.
+// This method is generated automatically by the compiler.
+func (c *%[1]s) RestoreHistoryState(bag runtime.HistoryBag) {
+%[3]s}
+`, comp.PascalName, save.String(), restore.String())
 }
 
 // generateApplyPropsBody generates the body of the ApplyProps method.
 // It creates assignment statements to copy all props from source to receiver.
 func generateApplyPropsBody(comp componentInfo) string {
-	if len(comp.Schema.Props) == 0 && comp.Schema.Slot == nil {
+	if len(comp.Schema.Props) == 0 && len(comp.Schema.slotFields()) == 0 {
 		return "\t// No props to copy"
 	}
 
@@ -154,10 +362,10 @@ func generateApplyPropsBody(comp componentInfo) string {
 			fmt.Sprintf("\tc.%s = src.%s", prop.Name, prop.Name))
 	}
 
-	// Copy slot content if exists
-	if comp.Schema.Slot != nil {
+	// Copy slot content, default and named
+	for _, slot := range comp.Schema.slotFields() {
 		assignments = append(assignments,
-			fmt.Sprintf("\tc.%s = src.%s", comp.Schema.Slot.Name, comp.Schema.Slot.Name))
+			fmt.Sprintf("\tc.%s = src.%s", slot.Name, slot.Name))
 	}
 
 	return strings.Join(assignments, "\n")
@@ -0,0 +1,245 @@
+package compiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// arithValue is a typed operand or sub-expression of an arithmetic binding.
+type arithValue struct {
+	Code   string // Go expression
+	GoType string // Numeric Go type; empty for an untyped constant (a literal or literals only)
+	Float  bool   // For untyped constants: a float constant
+	Zero   bool   // A literal zero, rejected as a divisor
+}
+
+// arithParser compiles the restricted expression grammar of text bindings:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | field | "(" expr ")"
+//
+// Fields are component fields, loop variables or their dot-paths, and must be numeric.
+type arithParser struct {
+	tokens  []string
+	pos     int
+	resolve func(field string) (expr string, goType string, nilChecks []string)
+	fail    func(format string, a ...any) // Panics with an arithError
+
+	nilChecks []string // Pointers read by the operands, collected for the nil-safe wrapper
+}
+
+// arithError carries a compile error out of the recursive descent of arithParser; it is
+// recovered by generateArithmeticBinding.
+type arithError struct{ err error }
+
+// generateArithmeticBinding compiles an arithmetic text binding such as {i + 1} or
+// {line.Qty * line.Price} into a Go expression. It returns the expression, its Go type and
+// the pointers it reads through. Operands are numeric; an integer operand mixed with a float
+// is converted to float64, and literal constants take the type of the other operand.
+func generateArithmeticBinding(source string, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (code string, goType string, nilChecks []string, err error) {
+	fail := func(format string, a ...any) {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		panic(arithError{fmt.Errorf("Compilation Error in %s:%d: Invalid expression '{%s}': %s\n%s"+
+			"Bindings support numeric fields, loop variables, number literals, + - * / and parentheses.\n",
+			currentComp.Path, lineNumber, source, fmt.Sprintf(format, a...), contextLines)})
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			failure, ok := r.(arithError)
+			if !ok {
+				panic(r)
+			}
+			code, goType, nilChecks, err = "", "", nil, failure.err
+		}
+	}()
+
+	tokens, err := tokenizeArithmetic(source)
+	if err != nil {
+		fail("%v", err)
+	}
+	p := &arithParser{
+		tokens: tokens,
+		resolve: func(field string) (string, string, []string) {
+			expr, goType, nilChecks, err := resolveTextBinding(field, receiver, currentComp, loopCtx)
+			if err != nil {
+				panic(arithError{err})
+			}
+			return expr, goType, nilChecks
+		},
+		fail: fail,
+	}
+	value := p.parseExpr()
+	if p.pos < len(p.tokens) {
+		fail("unexpected '%s'", p.tokens[p.pos])
+	}
+
+	goType = value.GoType
+	if goType == "" {
+		goType = "int"
+		if value.Float {
+			goType = "float64"
+		}
+	}
+	return value.Code, goType, p.nilChecks, nil
+}
+
+// tokenizeArithmetic splits an arithmetic binding into numbers, field paths, operators
+// and parentheses.
+func tokenizeArithmetic(source string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(source); {
+		ch := rune(source[i])
+		start := i
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+			continue
+		case strings.ContainsRune("+-*/()", ch):
+			i++
+		case unicode.IsDigit(ch):
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.') {
+				i++
+			}
+		case ch == '_' || unicode.IsLetter(ch):
+			for i < len(source) && (source[i] == '_' || source[i] == '.' || unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+		default:
+			return nil, fmt.Errorf("unexpected character '%c'", ch)
+		}
+		tokens = append(tokens, source[start:i])
+	}
+	return tokens, nil
+}
+
+func (p *arithParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *arithParser) parseExpr() arithValue {
+	left := p.parseTerm()
+	for op := p.peek(); op == "+" || op == "-"; op = p.peek() {
+		p.pos++
+		left = p.combine(left, op, p.parseTerm())
+	}
+	return left
+}
+
+func (p *arithParser) parseTerm() arithValue {
+	left := p.parseUnary()
+	for op := p.peek(); op == "*" || op == "/"; op = p.peek() {
+		p.pos++
+		left = p.combine(left, op, p.parseUnary())
+	}
+	return left
+}
+
+func (p *arithParser) parseUnary() arithValue {
+	if p.peek() != "-" {
+		return p.parsePrimary()
+	}
+	p.pos++
+	operand := p.parseUnary()
+	if strings.HasPrefix(operand.Code, "-") {
+		operand.Code = "(" + operand.Code + ")" // Avoid emitting the -- token
+	}
+	operand.Code = "-" + operand.Code
+	return operand
+}
+
+func (p *arithParser) parsePrimary() arithValue {
+	token := p.peek()
+	switch {
+	case token == "":
+		p.fail("expression ends unexpectedly")
+	case token == "(":
+		p.pos++
+		inner := p.parseExpr()
+		if p.peek() != ")" {
+			p.fail("missing ')'")
+		}
+		p.pos++
+		inner.Code = "(" + inner.Code + ")"
+		return inner
+	case unicode.IsDigit(rune(token[0])):
+		p.pos++
+		f, err := strconv.ParseFloat(token, 64)
+		if err != nil || strings.HasSuffix(token, ".") {
+			p.fail("invalid number '%s'", token)
+		}
+		return arithValue{Code: token, Float: strings.Contains(token, "."), Zero: f == 0}
+	case token == "_" || unicode.IsLetter(rune(token[0])):
+		p.pos++
+		expr, goType, nilChecks := p.resolve(token)
+		switch {
+		case goType == "string":
+			p.fail("'%s' is a string; strings cannot be combined with operators, use one binding per value ({a}{b})", token)
+		case goType == "":
+			p.fail("the type of '%s' could not be determined; arithmetic needs int or float operands", token)
+		case !isNumericType(goType):
+			p.fail("'%s' is a %s; arithmetic needs int or float operands", token, goType)
+		}
+		p.nilChecks = append(p.nilChecks, nilChecks...)
+		return arithValue{Code: expr, GoType: goType}
+	default:
+		p.fail("unexpected '%s'", token)
+	}
+	return arithValue{}
+}
+
+// combine type-checks a binary operation and returns its Go expression and type.
+func (p *arithParser) combine(left arithValue, op string, right arithValue) arithValue {
+	if op == "/" && right.Zero {
+		p.fail("division by zero")
+	}
+
+	result := arithValue{}
+	switch {
+	case left.GoType == "" && right.GoType == "":
+		result.Float = left.Float || right.Float
+	case left.GoType == "" || right.GoType == "":
+		typed, constant := left, right
+		if typed.GoType == "" {
+			typed, constant = right, left
+		}
+		result.GoType = typed.GoType
+		if constant.Float && !isFloatType(typed.GoType) {
+			result.GoType = "float64"
+		}
+	case left.GoType == right.GoType:
+		result.GoType = left.GoType
+	case isFloatType(left.GoType) || isFloatType(right.GoType):
+		result.GoType = "float64"
+	default:
+		p.fail("'%s' (%s) and '%s' (%s) have different integer types; use fields of the same type", left.Code, left.GoType, right.Code, right.GoType)
+	}
+
+	result.Code = fmt.Sprintf("%s %s %s", convertArith(left, result.GoType), op, convertArith(right, result.GoType))
+	return result
+}
+
+// convertArith converts a typed operand to goType when the two differ (an integer operand
+// promoted to float64). Untyped constants adapt to the other operand and stay as written.
+func convertArith(v arithValue, goType string) string {
+	if v.GoType == "" || v.GoType == goType {
+		return v.Code
+	}
+	return fmt.Sprintf("%s(%s)", goType, v.Code)
+}
+
+// isNumericType reports whether goType is a built-in integer or float type.
+func isNumericType(goType string) bool {
+	return isBuiltinType(goType) && goType != "string" && goType != "bool"
+}
+
+// isFloatType reports whether goType is float32 or float64.
+func isFloatType(goType string) bool {
+	return goType == "float32" || goType == "float64"
+}
@@ -1,42 +1,204 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"go/types"
+	"math"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/vdom"
 	"golang.org/x/net/html"
 )
 
 // generateTernaryExpression generates Go code for a ternary conditional expression.
-// Supports negation operator: if negated is true, inverts the condition.
-func generateTernaryExpression(negated bool, condition, trueVal, falseVal, receiver string, propDesc propertyDescriptor) string {
-	if negated {
-		// Swap true and false values for negation
-		trueVal, falseVal = falseVal, trueVal
-	}
+// condition is a Go bool expression; trueExpr and falseExpr are Go string expressions
+// (quoted literals or field references).
+func generateTernaryExpression(condition, trueExpr, falseExpr string) string {
 	return fmt.Sprintf(`func() string {
-		if %s.%s {
+		if %s {
 			return %s
 		}
 		return %s
-	}()`, receiver, propDesc.Name, strconv.Quote(trueVal), strconv.Quote(falseVal))
+	}()`, condition, trueExpr, falseExpr)
+}
+
+// generateTernaryFromMatch validates a ternaryExprRegex match and generates its Go expression.
+func generateTernaryFromMatch(match []string, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (string, error) {
+	// Validate the condition's fields are boolean fields
+	condition, err := generateBoolFieldCondition(match[1], receiver, currentComp, htmlSource, lineNumber)
+	if err != nil {
+		return "", err
+	}
+
+	trueExpr, err := resolveTernaryBranch(match[2], match[0], receiver, currentComp, htmlSource, lineNumber, loopCtx)
+	if err != nil {
+		return "", err
+	}
+	falseExpr, err := resolveTernaryBranch(match[3], match[0], receiver, currentComp, htmlSource, lineNumber, loopCtx)
+	if err != nil {
+		return "", err
+	}
+	return generateTernaryExpression(condition, trueExpr, falseExpr), nil
+}
+
+// generateBoolFieldCondition returns the Go expression for a condition of bool fields joined
+// by && and ||, with ! negation and parentheses ({IsA && !IsB ? ...}, disabled="{!IsValid}").
+// Each field is validated with validateBooleanCondition.
+func generateBoolFieldCondition(cond, receiver string, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	goExpr, err := generateBooleanExpression(cond, func(operand string) (string, error) {
+		propDesc, err := validateBooleanCondition(operand, currentComp, currentComp.Path, lineNumber, htmlSource)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.%s", receiver, propDesc.Name), nil
+	})
+	var rejected operandError
+	if errors.As(err, &rejected) {
+		return "", rejected.err
+	}
+	if err != nil {
+		return "", fmt.Errorf("Compilation Error in %s:%d: Invalid condition '%s': %v.\n%s",
+			currentComp.Path, lineNumber, strings.TrimSpace(cond), err, getContextLines(htmlSource, lineNumber, 2))
+	}
+	return goExpr, nil
+}
+
+// resolveTernaryBranch converts one ternary branch into a Go string expression.
+// A branch is a quoted literal ('active'), a string prop or state field (ActiveClass),
+// or, inside a loop, the value variable of an enclosing loop or one of its fields (item.Class).
+func resolveTernaryBranch(branch, expr, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (string, error) {
+	if strings.HasPrefix(branch, "'") {
+		return strconv.Quote(strings.Trim(branch, "'")), nil
+	}
+
+	contextLines := getContextLines(htmlSource, lineNumber, 2)
+	var goExpr, goType string
+	varName, fieldName, isField := strings.Cut(branch, ".")
+	scope := loopCtx.valueScope(varName)
+	switch {
+	case scope != nil && !isField:
+		goExpr, goType = branch, scope.ElementType
+	case scope != nil:
+		fieldType, err := resolveLoopFieldType(fieldName, currentComp, scope)
+		if err != nil {
+			return "", fmt.Errorf("Compilation Error in %s:%d: Ternary branch '%s' in %s: %v\n%s",
+				currentComp.Path, lineNumber, branch, expr, err, contextLines)
+		}
+		goExpr, goType = branch, fieldType
+	case isField:
+		return "", fmt.Errorf("Compilation Error in %s:%d: Ternary branch '%s' in %s refers to '%s', which is not a loop variable in scope.\n"+
+			"Branches must be quoted literals, component fields, or fields of the current loop variable.\n%s",
+			currentComp.Path, lineNumber, branch, expr, varName, contextLines)
+	default:
+		propDesc, exists := currentComp.Schema.Props[strings.ToLower(branch)]
+		if !exists {
+			// Also check state fields
+			propDesc, exists = currentComp.Schema.State[strings.ToLower(branch)]
+		}
+		if !exists {
+			allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+			return "", fmt.Errorf("Compilation Error in %s:%d: Ternary branch '%s' in %s is not a quoted literal or a field on component '%s'. Available fields: [%s]\n"+
+				"Use quotes for literal text: {Condition ? 'value1' : 'value2'}\n%s",
+				currentComp.Path, lineNumber, branch, expr, currentComp.PascalName, strings.Join(allFields, ", "), contextLines)
+		}
+		goExpr, goType = fmt.Sprintf("%s.%s", receiver, propDesc.Name), propDesc.GoType
+	}
+
+	if goType != "string" {
+		return "", fmt.Errorf("Compilation Error in %s:%d: Ternary branch '%s' in %s must be a string, found type '%s'.\n%s",
+			currentComp.Path, lineNumber, branch, expr, goType, contextLines)
+	}
+	return goExpr, nil
+}
+
+// validateTernarySyntax reports ternary-shaped expressions that the ternary grammar does not
+// accept (e.g., nested ternaries), which would otherwise surface as confusing binding errors.
+func validateTernarySyntax(text string, currentComp componentInfo, htmlSource string, lineNumber int) error {
+	for _, candidate := range ternaryLikeRegex.FindAllString(text, -1) {
+		if ternaryExprRegex.FindString(candidate) == candidate {
+			continue
+		}
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		if strings.Count(candidate, "?") > 1 {
+			return fmt.Errorf("Compilation Error in %s:%d: Nested ternary expressions are not supported: %s\n%s"+
+				"Compute the value in a method or field instead, or use {@if}/{@switch}.\n",
+				currentComp.Path, lineNumber, candidate, contextLines)
+		}
+		return fmt.Errorf("Compilation Error in %s:%d: Invalid ternary expression: %s\n%s"+
+			"Expected format: {FieldName ? 'value1' : 'value2'} or {!FieldName ? TrueField : FalseField}\n"+
+			"The condition must be a bool field; each branch is a quoted literal or a string field.\n",
+			currentComp.Path, lineNumber, candidate, contextLines)
+	}
+	return nil
 }
 
 // generateAttributesMap is a helper to create the Go map literal for an element's attributes.
-func generateAttributesMap(n *html.Node, receiver string, currentComp componentInfo, htmlSource string) string {
-	var attrs, eventHandlers []string
+// An attribute with an error is recorded in opts.Errors and left out, and the others are
+// still checked.
+func generateAttributesMap(n *html.Node, receiver string, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) string {
+	var attrs, eventHandlers, handlerKeys []string
+	// handlerKey names the method behind the handler of an event, so the patcher can keep
+	// its listener across renders (see vdom.HandlerKeysAttr)
+	handlerKey := func(handler, method string) {
+		event, _, _ := strings.Cut(handler, ": ")
+		handlerKeys = append(handlerKeys, fmt.Sprintf("%s: %s", event, strconv.Quote(currentComp.PascalName+"."+method)))
+	}
+	var classExpr string // {classes ...} expression, added only when it yields classes
+	var styleExpr string // [style] expression, added only when it yields declarations
 	for _, a := range n.Attr {
+		if a.Key == styleAttribute {
+			expr, err := generateStyleExpression(a.Val, n, receiver, currentComp, htmlSource, opts.Lines.attributeLine(n, styleAttribute), loopCtx)
+			if err != nil {
+				opts.Errors.add(err)
+				continue
+			}
+			styleExpr = expr
+			continue
+		}
+		if a.Key == "@bind" {
+			// Two-way binding: the value attribute plus the handler writing it back
+			attr, handler, err := generateBindAttributes(n, strings.TrimSpace(a.Val), receiver, currentComp, htmlSource, opts.Lines.attributeLine(n, a.Key))
+			if err != nil {
+				opts.Errors.add(err)
+				continue
+			}
+			attrs = append(attrs, attr)
+			eventHandlers = append(eventHandlers, handler)
+			handlerKey(handler, "bind:"+strings.TrimSpace(a.Val))
+			continue
+		}
 		if after, ok := strings.CutPrefix(a.Key, "@"); ok {
-			eventName := after
+			eventName, modifierList, _ := strings.Cut(after, ".")
 			handlerName := a.Val
-			lineNumber := findEventLineNumber(n, eventName, htmlSource)
+			lineNumber := opts.Lines.attributeLine(n, a.Key)
+
+			// A call such as @onclick="Select(user.ID)" binds the handler to its arguments
+			if match := methodCallRegex.FindStringSubmatch(strings.TrimSpace(handlerName)); match != nil {
+				adapted, err := generateHandlerCall(eventName, match[1], match[2], n, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+				if err == nil && modifierList != "" {
+					adapted, err = generateEventModifiers(adapted, after, modifierList, events.GetEventSignature(eventName), currentComp, htmlSource, lineNumber)
+				}
+				if err != nil {
+					opts.Errors.add(err)
+					continue
+				}
+				eventHandlers = append(eventHandlers, fmt.Sprintf(`%s: %s`, strconv.Quote(jsEventProp(eventName)), adapted))
+				handlerKey(eventHandlers[len(eventHandlers)-1], match[1])
+				continue
+			}
 
 			// Validate event handler signature (compile-time type safety!)
-			method := validateEventHandler(eventName, handlerName, n.Data, currentComp, currentComp.Path, lineNumber, htmlSource)
+			method, err := validateEventHandler(eventName, handlerName, n.Data, currentComp, currentComp.Path, lineNumber, htmlSource)
+			if err != nil {
+				opts.Errors.add(err)
+				continue
+			}
 
 			// Get the event signature to determine if we need an adapter
 			// Note: using full import path since 'events' is also a local variable name
@@ -46,17 +208,27 @@ func generateAttributesMap(n *html.Node, receiver string, currentComp componentI
 			handlerRef := fmt.Sprintf(`%s.%s`, receiver, handlerName)
 
 			// Convert @eventname to camelCase for JavaScript (e.g., "onclick" -> "onClick")
-			jsEventName := "on" + strings.ToUpper(eventName[2:3]) + eventName[3:]
+			jsEventName := jsEventProp(eventName)
+
+			// Handlers taking a leading runtime.Ctx use the Ctx variant of their adapter, which
+			// also receives the component the Ctx belongs to.
+			params := method.Params
+			adapterSuffix, adapterArgs := "", handlerRef
+			if handlerTakesCtx(method) {
+				params = params[1:]
+				adapterSuffix, adapterArgs = "Ctx", receiver+", "+handlerRef
+			}
 
 			// Determine which adapter to use based on event type and method signature
+			var adapted string
 			if eventName == "onclick" {
 				// onclick supports both func() and func(ClickEventArgs)
-				if len(method.Params) == 0 {
+				if len(params) == 0 {
 					// func() - use no-arg adapter
-					eventHandlers = append(eventHandlers, fmt.Sprintf(`"%s": events.AdaptNoArgEvent(%s)`, jsEventName, handlerRef))
-				} else if len(method.Params) == 1 && method.Params[0].Type == "events.ClickEventArgs" {
+					adapted = fmt.Sprintf(`events.AdaptNoArgEvent%s(%s)`, adapterSuffix, adapterArgs)
+				} else if len(params) == 1 && params[0].Type == "events.ClickEventArgs" {
 					// func(ClickEventArgs) - use click adapter
-					eventHandlers = append(eventHandlers, fmt.Sprintf(`"%s": events.AdaptClickEvent(%s)`, jsEventName, handlerRef))
+					adapted = fmt.Sprintf(`events.AdaptClickEvent%s(%s)`, adapterSuffix, adapterArgs)
 				}
 			} else if eventSig.RequiresArgs {
 				// Event requires arguments - use the appropriate adapter
@@ -72,22 +244,43 @@ func generateAttributesMap(n *html.Node, receiver string, currentComp componentI
 					adapterFunc = "events.AdaptFocusEvent"
 				case "events.FormEventArgs":
 					adapterFunc = "events.AdaptFormEvent"
+				case "events.DragEventArgs":
+					adapterFunc = "events.AdaptDragEvent"
+				case "events.WheelEventArgs":
+					adapterFunc = "events.AdaptWheelEvent"
+				case "events.TouchEventArgs":
+					adapterFunc = "events.AdaptTouchEvent"
+				case "events.ScrollEventArgs":
+					adapterFunc = "events.AdaptScrollEvent"
 				default:
-					fmt.Fprintf(os.Stderr, "Internal Error: Unknown event args type '%s'\n", eventSig.ArgsType)
-					os.Exit(1)
+					opts.Errors.add(fmt.Errorf("Internal Error: Unknown event args type '%s'\n", eventSig.ArgsType))
+					continue
 				}
-				eventHandlers = append(eventHandlers, fmt.Sprintf(`"%s": %s(%s)`, jsEventName, adapterFunc, handlerRef))
+				adapted = fmt.Sprintf(`%s%s(%s)`, adapterFunc, adapterSuffix, adapterArgs)
 			} else {
 				// Event requires no arguments - use the no-arg adapter
-				eventHandlers = append(eventHandlers, fmt.Sprintf(`"%s": events.AdaptNoArgEvent(%s)`, jsEventName, handlerRef))
+				adapted = fmt.Sprintf(`events.AdaptNoArgEvent%s(%s)`, adapterSuffix, adapterArgs)
 			}
-
-			// Mark that method is used (prevents unused warnings)
-			_ = method
+			if modifierList != "" {
+				if adapted, err = generateEventModifiers(adapted, after, modifierList, eventSig, currentComp, htmlSource, lineNumber); err != nil {
+					opts.Errors.add(err)
+					continue
+				}
+			}
+			eventHandlers = append(eventHandlers, fmt.Sprintf(`%s: %s`, strconv.Quote(jsEventName), adapted))
+			handlerKey(eventHandlers[len(eventHandlers)-1], handlerName)
 		} else {
+			// Selection follows the value bound on the <select>; a selected option would fight it
+			if n.Data == "option" && a.Key == "selected" {
+				lineNum := opts.Lines.attributeLine(n, a.Key)
+				fmt.Fprintf(warningOutput, "Warning in %s:%d: 'selected' on <option> is ignored. The selection follows the value bound on the enclosing <select>: <select value=\"{Field}\">.\n%s",
+					currentComp.Path, lineNum, getContextLines(htmlSource, lineNum, 2))
+				continue
+			}
+
 			// Check for inline conditional expressions in attribute values
 			attrValue := a.Val
-			lineNum := estimateLineNumber(htmlSource, fmt.Sprintf(`%s="%s"`, a.Key, attrValue))
+			lineNum := opts.Lines.attributeLine(n, a.Key)
 
 			// Check for malformed ternary expressions (mismatched braces)
 			openBraces := strings.Count(attrValue, "{")
@@ -97,76 +290,89 @@ func generateAttributesMap(n *html.Node, receiver string, currentComp componentI
 				// Check if this looks like an attempted ternary expression
 				if strings.Contains(attrValue, "?") && strings.Contains(attrValue, ":") && strings.Contains(attrValue, "'") {
 					contextLines := getContextLines(htmlSource, lineNum, 2)
-					fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Malformed expression in attribute '%s' - unclosed braces (found %d opening '{' but %d closing '}')\n%s\n"+
+					opts.Errors.add(fmt.Errorf("Compilation Error in %s:%d: Malformed expression in attribute '%s' - unclosed braces (found %d opening '{' but %d closing '}')\n%s\n"+
 						"This appears to be an incomplete ternary expression.\n"+
 						"Ternary expressions must be complete: {condition ? 'true' : 'false'}\n"+
-						"Expected format: {FieldName ? 'value1' : 'value2'} or {!FieldName ? 'value1' : 'value2'}\n",
-						currentComp.Path, lineNum, a.Key, openBraces, closeBraces, contextLines)
-					os.Exit(1)
+						"Expected format: {FieldName ? 'value1' : 'value2'} or {!FieldName ? TrueField : FalseField}\n",
+						currentComp.Path, lineNum, a.Key, openBraces, closeBraces, contextLines))
+					continue
+				}
+			}
+
+			// Pattern 0: Conditional class list or class object, valid as the whole class attribute only
+			if match := classesExprRegex.FindStringSubmatch(attrValue); match != nil && a.Key == "class" {
+				expr, err := generateClassesExpression(match[1], receiver, currentComp, htmlSource, lineNum, loopCtx)
+				if err != nil {
+					opts.Errors.add(err)
+					continue
+				}
+				classExpr = expr
+				continue
+			}
+			if body, ok, err := classObjectBody(attrValue); ok && a.Key == "class" {
+				if err != nil {
+					opts.Errors.add(fmt.Errorf("Compilation Error in %s:%d: Invalid class object: %v\n%s"+
+						"Expected format: class=\"{Active: 'is-active', HasError: 'error'} card\"\n",
+						currentComp.Path, lineNum, err, getContextLines(htmlSource, lineNum, 2)))
+					continue
+				}
+				expr, err := generateClassesExpression(body, receiver, currentComp, htmlSource, lineNum, loopCtx)
+				if err != nil {
+					opts.Errors.add(err)
+					continue
 				}
+				classExpr = expr
+				continue
+			}
+			if strings.Contains(attrValue, "{classes ") {
+				contextLines := getContextLines(htmlSource, lineNum, 2)
+				opts.Errors.add(fmt.Errorf("Compilation Error in %s:%d: {classes} expressions must be the whole value of a class attribute, found in '%s'.\n%s"+
+					"List always-on classes as quoted literals inside it: class=\"{classes 'card' Active:'is-active'}\"\n",
+					currentComp.Path, lineNum, a.Key, contextLines))
+				continue
 			}
 
 			// Pattern 1: Check for boolean shorthand syntax for boolean attributes
 			// This must come BEFORE general data binding to handle boolean attributes correctly
 			if match := booleanShorthandRegex.FindStringSubmatch(attrValue); match != nil && isBooleanAttribute(a.Key) {
-				negated := match[1] == "!"
-				condition := match[2]
-
-				// Validate condition is a boolean field
-				propDesc := validateBooleanCondition(condition, currentComp, currentComp.Path, lineNum, htmlSource)
-
-				// Generate conditional code: if negated, invert the condition
-				if negated {
-					attrs = append(attrs, fmt.Sprintf(`"%s": !%s.%s`, a.Key, receiver, propDesc.Name))
-				} else {
-					attrs = append(attrs, fmt.Sprintf(`"%s": %s.%s`, a.Key, receiver, propDesc.Name))
+				// Validate the condition's fields are boolean fields
+				condition, err := generateBoolFieldCondition(match[1], receiver, currentComp, htmlSource, lineNum)
+				if err != nil {
+					opts.Errors.add(err)
+					continue
 				}
+				attrs = append(attrs, fmt.Sprintf(`%s: %s`, strconv.Quote(a.Key), condition))
 				continue
 			}
 
 			// Pattern 2: Ternary expressions in attribute values
-			if ternaryExprRegex.MatchString(attrValue) {
-				// Replace all ternary expressions in the value
-				result := attrValue
-				ternaryMatches := ternaryExprRegex.FindAllStringSubmatch(attrValue, -1)
-
-				for _, match := range ternaryMatches {
-					fullMatch := match[0]
-					negated := match[1] == "!"
-					condition := match[2]
-					trueVal := match[3]
-					falseVal := match[4]
-
-					// Validate condition is a boolean field
-					propDesc := validateBooleanCondition(condition, currentComp, currentComp.Path, lineNum, htmlSource)
-
-					// Generate ternary expression
-					ternaryCode := generateTernaryExpression(negated, condition, trueVal, falseVal, receiver, propDesc)
-
-					// If the attribute value is only the ternary expression
-					if result == fullMatch {
-						attrs = append(attrs, fmt.Sprintf(`"%s": %s`, a.Key, ternaryCode))
-						result = ""
-						break
+			if err := validateTernarySyntax(attrValue, currentComp, htmlSource, lineNum); err != nil {
+				opts.Errors.add(err)
+				continue
+			}
+			if ternaryMatches := ternaryExprRegex.FindAllStringSubmatch(attrValue, -1); len(ternaryMatches) > 0 {
+				// If the attribute value is only the ternary expression, use it directly
+				if len(ternaryMatches) == 1 && ternaryMatches[0][0] == attrValue {
+					ternaryCode, err := generateTernaryFromMatch(ternaryMatches[0], receiver, currentComp, htmlSource, lineNum, loopCtx)
+					if err != nil {
+						opts.Errors.add(err)
+						continue
 					}
-
-					// Otherwise, replace in the string (for concatenation)
-					result = strings.Replace(result, fullMatch, "%s", 1)
+					attrs = append(attrs, fmt.Sprintf(`%s: %s`, strconv.Quote(a.Key), urlSafeAttribute(a.Key, ternaryCode, true)))
+					continue
 				}
 
-				// If there were other parts, wrap in fmt.Sprintf
-				if result != "" {
-					var args []string
-					for _, match := range ternaryMatches {
-						negated := match[1] == "!"
-						condition := match[2]
-						trueVal := match[3]
-						falseVal := match[4]
-						propDesc := validateBooleanCondition(condition, currentComp, currentComp.Path, lineNum, htmlSource)
-						args = append(args, generateTernaryExpression(negated, condition, trueVal, falseVal, receiver, propDesc))
-					}
-					attrs = append(attrs, fmt.Sprintf(`"%s": fmt.Sprintf(%s, %s)`, a.Key, strconv.Quote(result), strings.Join(args, ", ")))
+				// Otherwise, replace each ternary with a placeholder and wrap in fmt.Sprintf
+				result := escapeFormatText(attrValue)
+				args, err := mapErr(ternaryMatches, func(match []string) (string, error) {
+					result = strings.Replace(result, escapeFormatText(match[0]), "%s", 1)
+					return generateTernaryFromMatch(match, receiver, currentComp, htmlSource, lineNum, loopCtx)
+				})
+				if err != nil {
+					opts.Errors.add(err)
+					continue
 				}
+				attrs = append(attrs, fmt.Sprintf(`%s: %s`, strconv.Quote(a.Key), urlSafeAttribute(a.Key, fmt.Sprintf(`fmt.Sprintf(%s, %s)`, strconv.Quote(result), strings.Join(args, ", ")), true)))
 				continue
 			}
 
@@ -178,76 +384,137 @@ func generateAttributesMap(n *html.Node, receiver string, currentComp componentI
 				if len(matches) == 1 && matches[0][0] == attrValue {
 					fieldName := matches[0][1]
 
-					// Validate that the field exists (check both Props and State)
-					propDesc, exists := currentComp.Schema.Props[strings.ToLower(fieldName)]
-					if !exists {
-						propDesc, exists = currentComp.Schema.State[strings.ToLower(fieldName)]
+					// Generate direct field reference (nil-safe for nested pointer fields)
+					binding, err := resolveAttributeBinding(fieldName, receiver, currentComp, htmlSource, lineNum, opts, loopCtx, true)
+					if err != nil {
+						opts.Errors.add(err)
+						continue
 					}
-					if !exists {
-						allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
-						availableFields := strings.Join(allFields, ", ")
-						contextLines := getContextLines(htmlSource, lineNum, 2)
-						fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Property '%s' not found in component struct. Available fields: [%s]\n%s",
-							currentComp.Path, lineNum, fieldName, availableFields, contextLines)
-						os.Exit(1)
-					}
-
-					// Generate direct field reference
-					attrs = append(attrs, fmt.Sprintf(`"%s": %s.%s`, a.Key, receiver, propDesc.Name))
+					attrs = append(attrs, fmt.Sprintf(`%s: %s`, strconv.Quote(a.Key), urlSafeAttribute(a.Key, binding, false)))
 					continue
 				}
 
 				// Multiple bindings or mixed content (e.g., '{Base}/{Path}')
-				formatString := dataBindingRegex.ReplaceAllString(attrValue, "%v")
-				var args []string
-				for _, match := range matches {
-					fieldName := match[1]
-
-					// Validate that the field exists (check both Props and State)
-					propDesc, exists := currentComp.Schema.Props[strings.ToLower(fieldName)]
-					if !exists {
-						propDesc, exists = currentComp.Schema.State[strings.ToLower(fieldName)]
-					}
-					if !exists {
-						allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
-						availableFields := strings.Join(allFields, ", ")
-						contextLines := getContextLines(htmlSource, lineNum, 2)
-						fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Property '%s' not found in component struct. Available fields: [%s]\n%s",
-							currentComp.Path, lineNum, fieldName, availableFields, contextLines)
-						os.Exit(1)
-					}
-
-					args = append(args, fmt.Sprintf("%s.%s", receiver, propDesc.Name))
+				formatString := dataBindingRegex.ReplaceAllString(escapeFormatText(attrValue), "%v")
+				args, err := mapErr(matches, func(match []string) (string, error) {
+					return resolveAttributeBinding(match[1], receiver, currentComp, htmlSource, lineNum, opts, loopCtx, false)
+				})
+				if err != nil {
+					opts.Errors.add(err)
+					continue
 				}
-				attrs = append(attrs, fmt.Sprintf(`"%s": fmt.Sprintf(%s, %s)`, a.Key, strconv.Quote(formatString), strings.Join(args, ", ")))
+				attrs = append(attrs, fmt.Sprintf(`%s: %s`, strconv.Quote(a.Key), urlSafeAttribute(a.Key, fmt.Sprintf(`fmt.Sprintf(%s, %s)`, strconv.Quote(formatString), strings.Join(args, ", ")), true)))
 				continue
 			}
 
 			// Pattern 4: Regular static attribute
-			attrs = append(attrs, fmt.Sprintf(`"%s": "%s"`, a.Key, a.Val))
+			attrs = append(attrs, fmt.Sprintf(`%s: %s`, strconv.Quote(a.Key), strconv.Quote(a.Val)))
 		}
 	}
 
-	if len(attrs) == 0 && len(eventHandlers) == 0 {
+	allProps := append(attrs, eventHandlers...)
+	if len(handlerKeys) > 0 {
+		allProps = append(allProps, fmt.Sprintf("vdom.HandlerKeysAttr: map[string]string{%s}", strings.Join(handlerKeys, ", ")))
+	}
+	if classExpr != "" || styleExpr != "" {
+		// An empty class list or style map omits the attribute instead of rendering class=""
+		var optional strings.Builder
+		for _, attr := range []struct{ key, expr string }{{"class", classExpr}, {"style", styleExpr}} {
+			if attr.expr != "" {
+				fmt.Fprintf(&optional, "if value := %s; value != \"\" {\nattrs[%q] = value\n}\n", attr.expr, attr.key)
+			}
+		}
+		return fmt.Sprintf(`func() map[string]any {
+		attrs := map[string]any{%s}
+		%sreturn attrs
+	}()`, strings.Join(allProps, ", "), optional.String())
+	}
+	if len(allProps) == 0 {
 		return "nil"
 	}
-	allProps := append(attrs, eventHandlers...)
 	return fmt.Sprintf("map[string]any{%s}", strings.Join(allProps, ", "))
 }
 
+// urlSafeAttribute wraps expr, the bound value of attribute key, in vdom.SanitizeURL when the
+// attribute holds a URL (vdom.ContextOf), tagging the value for every output backend: the DOM
+// and RenderToString then receive a URL that cannot run script. Static values are written by
+// the template author and kept as they are. isString is set when expr is a string already.
+func urlSafeAttribute(key, expr string, isString bool) string {
+	if vdom.ContextOf(key) != vdom.URLContext {
+		return expr
+	}
+	if !isString {
+		expr = fmt.Sprintf("fmt.Sprint(%s)", expr)
+	}
+	return fmt.Sprintf("vdom.SanitizeURL(%s)", expr)
+}
+
+// resolveAttributeBinding returns the Go expression for a {FieldName}, {Field.Nested} or
+// {Method()} binding in an attribute value. Nested fields that read through pointers are
+// guarded: the binding yields the field type's zero value when typed is set (the binding is
+// the whole attribute value), or "" when it is formatted into a larger string. Inside a loop the
+// binding may also be the loop index, value, or a field of the value (<option value="{lang}">).
+func resolveAttributeBinding(fieldName, receiver string, currentComp componentInfo, htmlSource string, lineNum int, opts compileOptions, loopCtx *loopContext, typed bool) (string, error) {
+	if methodCallRegex.MatchString(fieldName) {
+		expr, _, err := resolveMethodBinding(fieldName, receiver, currentComp, htmlSource, lineNum)
+		return expr, err
+	}
+	rootName, _, isNested := strings.Cut(fieldName, ".")
+	if scope := loopCtx.scopeOf(rootName); scope != nil && (rootName == scope.ValueVar || !isNested) {
+		expr, _, _, err := resolveTextBinding(fieldName, receiver, currentComp, loopCtx)
+		return expr, err
+	}
+
+	if expr, _, ok, err := resolveFormBinding(fieldName, receiver, currentComp); ok {
+		if err != nil {
+			return "", fmt.Errorf("Compilation Error in %s:%d: %v\n%s", currentComp.Path, lineNum, err, getContextLines(htmlSource, lineNum, 2))
+		}
+		return expr, nil
+	}
+
+	// Validate that the field exists (check both Props and State)
+	propDesc, exists := currentComp.Schema.Props[strings.ToLower(rootName)]
+	if !exists {
+		propDesc, exists = currentComp.Schema.State[strings.ToLower(rootName)]
+	}
+	if !exists {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		availableFields := strings.Join(allFields, ", ")
+		contextLines := getContextLines(htmlSource, lineNum, 2)
+		return "", fmt.Errorf("Compilation Error in %s:%d: Property '%s' not found in component struct. Available fields: [%s]\n%s",
+			currentComp.Path, lineNum, rootName, availableFields, contextLines)
+	}
+	if !isNested {
+		return fmt.Sprintf("%s.%s", receiver, propDesc.Name), nil
+	}
+
+	fieldType, pointerPaths, err := resolveNestedFieldPath(fieldName, currentComp, filepath.Dir(currentComp.Path))
+	if err != nil {
+		contextLines := getContextLines(htmlSource, lineNum, 2)
+		return "", fmt.Errorf("Compilation Error in %s:%d: Field '%s' not resolvable on component '%s'. %v\n%s",
+			currentComp.Path, lineNum, fieldName, currentComp.PascalName, err, contextLines)
+	}
+	zero := `""`
+	if typed {
+		zero = zeroValueLiteral(fieldType)
+	}
+	expr := fmt.Sprintf("%s.%s", receiver, fieldName)
+	return generateNilSafeExpression(expr, pointerNilChecks(receiver, pointerPaths), zero, fieldName, currentComp, lineNum, opts), nil
+}
+
 // generateStructLiteral creates the { Field: value, ... } string.
 // If the component has a content slot, it collects child nodes and includes them in the struct literal.
-func generateStructLiteral(n *html.Node, compInfo componentInfo, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, templatePath string, opts compileOptions, loopCtx *loopContext) string {
+func generateStructLiteral(n *html.Node, compInfo componentInfo, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, templatePath string, opts compileOptions, loopCtx *loopContext) (string, error) {
 	var props []string
 
 	// Extract the original attribute names from the HTML source
-	originalAttrs, lineNumber := extractOriginalAttributesWithLineNumber(n, compInfo.LowercaseName, htmlSource)
+	sourceAttrs, lineNumber := opts.Lines.attributes(n)
 
-	for _, attr := range n.Attr {
+	for i, attr := range n.Attr {
 		// Get the original casing from the source
 		originalKey := attr.Key
-		if origName, found := originalAttrs[attr.Key]; found {
-			originalKey = origName
+		if sourceAttrs != nil {
+			originalKey = sourceAttrs[i].Name
 		}
 
 		// Check if the ORIGINAL attribute starts with a capital letter
@@ -256,85 +523,169 @@ func generateStructLiteral(n *html.Node, compInfo componentInfo, receiver string
 			lookupKey := strings.ToLower(originalKey)
 
 			if propDesc, ok := compInfo.Schema.Props[lookupKey]; ok {
-				valueStr := convertPropValue(attr.Val, propDesc.GoType, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+				valueStr, err := convertComponentPropValue(attr.Val, propDesc, compInfo, receiver, currentComp, htmlSource, lineNumber, opts, loopCtx)
+				if err != nil {
+					return "", err
+				}
 				props = append(props, fmt.Sprintf("%s: %s", propDesc.Name, valueStr))
 			} else {
 				// Attribute starts with capital letter but doesn't match any exported field
 				availableFields := strings.Join(getAvailableFieldNames(compInfo.Schema.Props), ", ")
 				contextLines := getContextLines(htmlSource, lineNumber, 2)
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Attribute '%s' does not match any exported field on component '%s'. Available fields: [%s]\n%s",
+				return "", fmt.Errorf("Compilation Error in %s:%d: Attribute '%s' does not match any exported field on component '%s'. Available fields: [%s]\n%s",
 					templatePath, lineNumber, originalKey, compInfo.PascalName, availableFields, contextLines)
-				os.Exit(1)
 			}
 		} else if propDesc, ok := compInfo.Schema.Props[attr.Key]; ok {
 			// Lowercase attribute that happens to match a field
-			valueStr := convertPropValue(attr.Val, propDesc.GoType, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+			valueStr, err := convertComponentPropValue(attr.Val, propDesc, compInfo, receiver, currentComp, htmlSource, lineNumber, opts, loopCtx)
+			if err != nil {
+				return "", err
+			}
 			props = append(props, fmt.Sprintf("%s: %s", propDesc.Name, valueStr))
 		}
 	}
 
-	// Handle content slot if component has one
-	if compInfo.Schema.Slot != nil {
-		slotContent := collectSlotChildren(n, receiver, componentMap, currentComp, compInfo.PascalName, templatePath, htmlSource, opts, loopCtx)
-		if slotContent == "" {
-			// Empty slot: compile to nil
-			props = append(props, fmt.Sprintf("%s: nil", compInfo.Schema.Slot.Name))
-		} else {
-			// Has children: compile to VNode slice
-			props = append(props, fmt.Sprintf("%s: %s", compInfo.Schema.Slot.Name, slotContent))
-		}
+	// Fill the content slots, default and named, from the children
+	slotProps, err := generateSlotProps(n, compInfo, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+	if err != nil {
+		return "", err
 	}
+	props = append(props, slotProps...)
 
 	if len(props) == 0 {
-		return "{}"
+		return "{}", nil
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(props, ", ")), nil
+}
+
+// checkConsumedAttributes verifies every prop attribute written on a component in the
+// template reaches the parsed node under its own name. Inside <svg> and <math> the HTML
+// parser re-cases some attribute names (viewbox becomes viewBox) and splits off namespaces,
+// so a prop attribute such as ViewBox would otherwise be dropped without an error: the
+// component receives the zero value. Prop attributes are those starting with a capital
+// letter and lowercase ones naming a prop.
+func checkConsumedAttributes(n *html.Node, componentMap map[string]componentInfo, lines *templateLines, templatePath string) error {
+	if n.Type == html.ElementNode {
+		if compInfo, isComponent := componentMap[n.Data]; isComponent {
+			sourceAttrs, lineNumber := lines.attributes(n)
+			for i, attr := range sourceAttrs {
+				lowerName := strings.ToLower(attr.Name)
+				_, namesProp := compInfo.Schema.Props[lowerName]
+				capitalized := attr.Name[0] >= 'A' && attr.Name[0] <= 'Z'
+				if parsed := attributeName(n.Attr[i]); (capitalized || namesProp) && parsed != lowerName {
+					return fmt.Errorf("template validation error in %s:%d: attribute '%s' on <%s> never reaches the component: the HTML parser read it as '%s'.\n"+
+						"  Inside <svg> and <math> the parser rewrites some attribute names, so the prop would silently keep its zero value.\n"+
+						"  Rename the prop (e.g. '%sValue') or move the component out of the SVG/MathML content",
+						templatePath, lineNumber, attr.Name, compInfo.PascalName, parsed, attr.Name)
+				}
+			}
+		}
 	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := checkConsumedAttributes(c, componentMap, lines, templatePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	return fmt.Sprintf("{%s}", strings.Join(props, ", "))
+// sourceAttribute is an attribute as written in the template source.
+type sourceAttribute struct {
+	Name  string // Original casing
+	Value string // Unescaped, as the parser reads it
+	Line  int    // Template line the attribute is written on
 }
 
-// extractOriginalAttributesWithLineNumber extracts the original attribute names and line number from the HTML source.
-// This is needed because the HTML parser lowercases all attributes.
-func extractOriginalAttributesWithLineNumber(n *html.Node, componentName, htmlSource string) (map[string]string, int) {
-	originalAttrs := make(map[string]string)
-	lineNumber := 1
+// sourceAttributeRegex matches one attribute of a start tag: its name and its value in
+// double quotes, single quotes or unquoted.
+var sourceAttributeRegex = regexp.MustCompile(`([^\s"'>/=]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'=<>` + "`" + `]+)))?`)
 
-	// Find the component tag in the HTML source (case-insensitive tag name)
-	// Pattern: <componentName attr1="..." attr2="..." ...>
-	pattern := fmt.Sprintf(`(?i)<%s\s+([^>]*)>`, regexp.QuoteMeta(componentName))
-	re := regexp.MustCompile(pattern)
-	matchIndex := re.FindStringSubmatchIndex(htmlSource)
+// sameAttributeValues reports whether attrs and parsed hold the same values in the same order.
+func sameAttributeValues(attrs []sourceAttribute, parsed []html.Attribute) bool {
+	if len(attrs) != len(parsed) {
+		return false
+	}
+	for i := range attrs {
+		if attrs[i].Value != parsed[i].Val {
+			return false
+		}
+	}
+	return true
+}
 
-	if len(matchIndex) < 4 {
-		return originalAttrs, lineNumber
+// attributeName returns the name of a parsed attribute, with its namespace prefix if any.
+func attributeName(attr html.Attribute) string {
+	if attr.Namespace != "" {
+		return attr.Namespace + ":" + attr.Key
 	}
+	return attr.Key
+}
 
-	// Calculate line number by counting newlines before the match
-	lineNumber = strings.Count(htmlSource[:matchIndex[0]], "\n") + 1
+// convertComponentPropValue generates the value of prop propDesc of the child component
+// compInfo. A prop whose type is a named type defined on string, bool or a numeric type
+// (type Label string) is converted as that built-in type and wrapped in a conversion to the
+// named type, so literals, bindings and mixed text all work: Label(fmt.Sprintf(...)).
+// Other types are handled by convertPropValue as written.
+func convertComponentPropValue(value string, propDesc propertyDescriptor, compInfo componentInfo, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, opts compileOptions, loopCtx *loopContext) (string, error) {
+	goType := propDesc.GoType
+	if !namedTypeRegex.MatchString(goType) || types.Universe.Lookup(goType) != nil || goType == "time.Duration" || goType == "time.Time" {
+		return convertPropValue(value, propDesc, compInfo, receiver, currentComp, htmlSource, lineNumber, opts, loopCtx)
+	}
 
-	// Extract the attribute string
-	attrString := htmlSource[matchIndex[2]:matchIndex[3]]
+	childDir := filepath.Dir(compInfo.Path)
+	basic, found := resolveNamedBasicType(goType, childDir)
+	if !found && opts.DevMode {
+		fmt.Fprintf(warningOutput, "Note in %s:%d: The type '%s' of prop '%s' on component '%s' could not be resolved; the value is passed as written.\n",
+			currentComp.Path, lineNumber, goType, propDesc.Name, compInfo.PascalName)
+	}
 
-	// Extract individual attributes with their original casing
-	// Pattern: attrName="value" or attrName='value'
-	attrPattern := regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9]*)\s*=\s*["']([^"']*)["']`)
-	attrMatches := attrPattern.FindAllStringSubmatch(attrString, -1)
+	switch {
+	case basic == "string" || basic == "bool" || isNumericType(basic):
+	default:
+		return convertPropValue(value, propDesc, compInfo, receiver, currentComp, htmlSource, lineNumber, opts, loopCtx)
+	}
+	// Converted as the built-in type, then wrapped in the named one
+	basicProp := propDesc
+	basicProp.GoType, basicProp.Type = basic, nil
+	converted, err := convertPropValue(value, basicProp, compInfo, receiver, currentComp, htmlSource, lineNumber, opts, loopCtx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s(%s)", namedTypeExpr(goType, compInfo, currentComp, childDir, opts), converted), nil
+}
 
-	for _, match := range attrMatches {
-		if len(match) >= 2 {
-			originalName := match[1]
-			lowercaseName := strings.ToLower(originalName)
-			originalAttrs[lowercaseName] = originalName
+// namedTypeExpr returns how the generated file of currentComp refers to goType, a named type
+// as written in the Go file of compInfo, recording in opts.Imports the package it needs.
+func namedTypeExpr(goType string, compInfo, currentComp componentInfo, childDir string, opts compileOptions) string {
+	alias, typeName, qualified := strings.Cut(goType, ".")
+	if !qualified {
+		if packageAlias, crossPackage := opts.PackageAliases[compInfo.ImportPath]; crossPackage {
+			return packageAlias + "." + goType // The package is imported for the component itself
 		}
+		return goType
 	}
-
-	return originalAttrs, lineNumber
+	importPath, _ := resolvePackageFromAlias(alias, childDir)
+	if importPath == currentComp.ImportPath {
+		return typeName
+	}
+	if packageAlias, imported := opts.PackageAliases[importPath]; imported {
+		return packageAlias + "." + typeName // The package of a rendered component
+	}
+	opts.Imports[alias] = importPath
+	return goType
 }
 
-// convertPropValue generates the Go code to convert a string to the target type.
-// It handles data binding expressions in attribute values, respecting loop context.
-func convertPropValue(value, goType string, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) string {
-	// Debug: uncomment to see what values are being converted
-	// fmt.Fprintf(os.Stderr, "[convertPropValue] value=%q goType=%q\n", value, goType)
+// propTimeLayouts are the layouts a time.Time prop literal may be written in.
+var propTimeLayouts = []string{time.RFC3339, time.DateOnly}
+
+// convertPropValue generates the Go code to convert the attribute value of prop, a prop of the
+// child component compInfo, to the prop's type. A value that is a whole {binding} of a field or
+// loop variable is passed as the bound expression, after checking its type against the prop.
+// Literals of built-in types, time.Duration and time.Time are parsed at compile time, so a
+// malformed value is a compile error rather than a zero value.
+func convertPropValue(value string, prop propertyDescriptor, compInfo componentInfo, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, opts compileOptions, loopCtx *loopContext) (string, error) {
+	goType := prop.GoType
 
 	// First, check if value is wrapped in braces {}: if so, extract and handle as expression
 	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
@@ -343,34 +694,23 @@ func convertPropValue(value, goType string, receiver string, currentComp compone
 
 		// For boolean literals, use as-is
 		if goCode == "true" || goCode == "false" {
-			return goCode
+			return goCode, nil
 		}
 
-		// For qualified names (e.g., modal.Information), use as-is
-		if strings.Contains(goCode, ".") && !strings.Contains(goCode, "(") {
-			return goCode
-		}
-
-		// For simple identifiers (component fields or loop variables)
+		// Fields and loop variables, and their fields, are resolved and type-checked
 		if !strings.Contains(goCode, " ") && !strings.Contains(goCode, "(") {
-			// Check if this is a loop variable
-			if loopCtx != nil && (goCode == loopCtx.ValueVar || goCode == loopCtx.IndexVar || strings.HasPrefix(goCode, loopCtx.ValueVar+".")) {
-				return goCode
-			}
-
-			// Check if it's a component field (props or state)
-			propDesc, inProps := currentComp.Schema.Props[strings.ToLower(goCode)]
-			if !inProps {
-				propDesc, inProps = currentComp.Schema.State[strings.ToLower(goCode)]
+			expr, sourceType, source, found, err := resolvePropBinding(goCode, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+			if err != nil {
+				return "", err
 			}
-			if inProps {
-				// It's a component field - add receiver prefix
-				return fmt.Sprintf("%s.%s", receiver, propDesc.Name)
+			if found {
+				return checkPropBindingType(expr, goCode, sourceType, source, prop, compInfo, currentComp, htmlSource, lineNumber)
 			}
 		}
 
-		// For everything else (e.g., method names, complex expressions), use as-is
-		return goCode
+		// For everything else (e.g., qualified constants like modal.Information, method names,
+		// complex expressions), use as-is
+		return goCode, nil
 	}
 
 	switch goType {
@@ -378,53 +718,27 @@ func convertPropValue(value, goType string, receiver string, currentComp compone
 		// Check if the value contains data binding expressions
 		if dataBindingRegex.MatchString(value) {
 			// Use generateTextExpression to handle bindings (including loop variables)
-			return generateTextExpression(value, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+			return generateTextExpression(value, receiver, currentComp, htmlSource, lineNumber, opts, loopCtx)
 		}
-		return strconv.Quote(value)
-	case "int":
+		return strconv.Quote(value), nil
+	case "bool", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+		"byte", "rune", "float32", "float64", "time.Duration", "time.Time":
 		// Check if the value contains data binding expressions (e.g., {UserId})
 		if dataBindingRegex.MatchString(value) {
 			// Extract the field name from the binding
 			matches := dataBindingRegex.FindStringSubmatch(value)
 			if len(matches) > 1 {
 				fieldName := matches[1]
-				// Check if this is a loop variable
-				if loopCtx != nil && fieldName == loopCtx.ValueVar {
-					// Direct reference to loop value variable
-					return fieldName
-				} else if loopCtx != nil && strings.HasPrefix(fieldName, loopCtx.ValueVar+".") {
-					// Reference to a field of the loop value (e.g., user.ID)
-					return fieldName
-				} else {
-					// Reference to component field
-					return fmt.Sprintf("%s.%s", receiver, fieldName)
+				// Check if this is a loop value variable or one of its fields (e.g., user.ID),
+				// of this loop or an enclosing one
+				if rootName, _, _ := strings.Cut(fieldName, "."); loopCtx.valueScope(rootName) != nil {
+					return fieldName, nil
 				}
+				// Reference to component field
+				return fmt.Sprintf("%s.%s", receiver, fieldName), nil
 			}
 		}
-		// Literal integer value
-		return fmt.Sprintf("func() int { i, _ := strconv.Atoi(\"%s\"); return i }()", value)
-	case "bool":
-		// Check if the value contains data binding expressions (e.g., {IsActive})
-		if dataBindingRegex.MatchString(value) {
-			// Extract the field name from the binding
-			matches := dataBindingRegex.FindStringSubmatch(value)
-			if len(matches) > 1 {
-				fieldName := matches[1]
-				// Check if this is a loop variable
-				if loopCtx != nil && fieldName == loopCtx.ValueVar {
-					// Direct reference to loop value variable
-					return fieldName
-				} else if loopCtx != nil && strings.HasPrefix(fieldName, loopCtx.ValueVar+".") {
-					// Reference to a field of the loop value
-					return fieldName
-				} else {
-					// Reference to component field
-					return fmt.Sprintf("%s.%s", receiver, fieldName)
-				}
-			}
-		}
-		// Literal boolean value
-		return fmt.Sprintf("func() bool { b, _ := strconv.ParseBool(\"%s\"); return b }()", value)
+		return convertPropLiteral(value, goType, prop.Name, currentComp, htmlSource, lineNumber, opts)
 	default:
 		// For unknown/custom types (enums, custom structs, etc.):
 		// - If value is a simple identifier, check if it's a method name (for function types)
@@ -432,20 +746,198 @@ func convertPropValue(value, goType string, receiver string, currentComp compone
 			// Check if this is a function type - if so, treat as method name
 			if strings.HasPrefix(goType, "func") {
 				// It's a function type - convert method name to receiver reference
-				return fmt.Sprintf("%s.%s", receiver, value)
+				return fmt.Sprintf("%s.%s", receiver, value), nil
 			}
 
 			// For non-function types, it might be a constant - use as-is
 			// (e.g., mypackage.SomeConstant will work, but SomeConstant alone might not)
-			return value
+			return value, nil
 		}
 
 		// If it contains a dot, it's likely a qualified name or constant - use as-is
 		if strings.Contains(value, ".") {
-			return value
+			return value, nil
 		}
 
 		// Default to string for unknown types
-		return strconv.Quote(value)
+		return strconv.Quote(value), nil
+	}
+}
+
+// convertPropLiteral parses the literal value of prop propName, of type goType (bool, a
+// number, time.Duration or time.Time), and returns it as a Go expression of that type.
+func convertPropLiteral(value, goType, propName string, currentComp componentInfo, htmlSource string, lineNumber int, opts compileOptions) (string, error) {
+	literal := strings.TrimSpace(value)
+	var converted string
+	var err error
+	switch goType {
+	case "bool":
+		var b bool
+		b, err = strconv.ParseBool(literal)
+		converted = strconv.FormatBool(b)
+	case "float32", "float64":
+		bits := 64
+		if goType == "float32" {
+			bits = 32
+		}
+		var f float64
+		f, err = strconv.ParseFloat(literal, bits)
+		if err == nil && (math.IsInf(f, 0) || math.IsNaN(f)) {
+			err = errors.New("not a finite number")
+		}
+		converted = strconv.FormatFloat(f, 'g', -1, bits)
+	case "time.Duration":
+		var d time.Duration
+		d, err = time.ParseDuration(literal)
+		converted = fmt.Sprintf("time.Duration(%d)", d)
+		opts.Imports["time"] = "time"
+	case "time.Time":
+		err = fmt.Errorf("expected an RFC 3339 time (%s) or a date (%s)", time.RFC3339, time.DateOnly)
+		for _, layout := range propTimeLayouts {
+			if t, parseErr := time.Parse(layout, literal); parseErr == nil {
+				converted, err = fmt.Sprintf("time.Unix(%d, %d).UTC()", t.Unix(), t.Nanosecond()), nil
+				break
+			}
+		}
+		opts.Imports["time"] = "time"
+	default:
+		// Integer types; int and uint are 64 bits wide on wasm as on the usual native targets
+		bits := 64
+		switch goType {
+		case "int8", "uint8", "byte":
+			bits = 8
+		case "int16", "uint16":
+			bits = 16
+		case "int32", "uint32", "rune":
+			bits = 32
+		}
+		if strings.HasPrefix(goType, "uint") || goType == "byte" {
+			var u uint64
+			u, err = strconv.ParseUint(literal, 0, bits)
+			converted = strconv.FormatUint(u, 10)
+		} else {
+			var i int64
+			i, err = strconv.ParseInt(literal, 0, bits)
+			converted = strconv.FormatInt(i, 10)
+		}
+	}
+	if err != nil {
+		var numErr *strconv.NumError
+		if errors.As(err, &numErr) {
+			err = numErr.Err
+		}
+		return "", fmt.Errorf("Compilation Error in %s:%d: Value '%s' of prop '%s' is not a valid %s: %v\n%s",
+			currentComp.Path, lineNumber, value, propName, goType, err, getContextLines(htmlSource, lineNumber, 2))
+	}
+	return converted, nil
+}
+
+// resolvePropBinding resolves the field path binding of a prop value to the Go expression that
+// reads it, its type as written in currentComp and its type-checked type (nil when unknown).
+// The binding is a loop variable, a field of a loop value variable (category.Products), or a
+// field of the component, nested fields included; found is false for anything else, such as
+// a qualified constant. A nested component field read through nil pointers yields the zero
+// value of its type.
+func resolvePropBinding(binding, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (expr, goType string, typ types.Type, found bool, err error) {
+	parts := strings.Split(binding, ".")
+	if scope := loopCtx.scopeOf(parts[0]); scope != nil {
+		switch {
+		case len(parts) == 1 && parts[0] == scope.ValueVar:
+			return binding, scope.ElementType, scope.Element, true, nil
+		case len(parts) == 1:
+			return binding, scope.IndexType, nil, true, nil
+		case parts[0] != scope.ValueVar:
+			return binding, "", nil, true, nil // A field of a map key; left to the Go compiler
+		case scope.Element != nil && currentComp.Qualifier != nil:
+			if fieldType, _, resolveErr := resolveTypedFieldPath(parts, scope.Element, currentComp.Qualifier); resolveErr == nil {
+				return binding, typeString(fieldType, currentComp.Qualifier), fieldType, true, nil
+			}
+		case len(parts) == 2:
+			if fieldType, resolveErr := resolveLoopFieldType(parts[1], currentComp, scope); resolveErr == nil {
+				return binding, fieldType, nil, true, nil
+			}
+		}
+		return binding, "", nil, true, nil
+	}
+
+	propDesc, exists := currentComp.Schema.Props[strings.ToLower(parts[0])]
+	if !exists {
+		propDesc, exists = currentComp.Schema.State[strings.ToLower(parts[0])]
+	}
+	if !exists {
+		return "", "", nil, false, nil
+	}
+	if len(parts) == 1 {
+		return fmt.Sprintf("%s.%s", receiver, propDesc.Name), propDesc.GoType, propDesc.Type, true, nil
+	}
+	if propDesc.Name != parts[0] {
+		return "", "", nil, false, nil // Not the field: a package name, as in modal.Information
+	}
+
+	fieldType, pointerPaths, err := resolveNestedFieldPath(binding, currentComp, filepath.Dir(currentComp.Path))
+	if err != nil {
+		return "", "", nil, false, fmt.Errorf("Compilation Error in %s:%d: Field '%s' not resolvable on component '%s'. %v\n%s",
+			currentComp.Path, lineNumber, binding, currentComp.PascalName, err, getContextLines(htmlSource, lineNumber, 2))
+	}
+	if propDesc.Type != nil && currentComp.Qualifier != nil {
+		typ, _, _ = resolveTypedFieldPath(parts, propDesc.Type, currentComp.Qualifier)
+	}
+	expr = fmt.Sprintf("%s.%s", receiver, binding)
+	if len(pointerPaths) > 0 {
+		conds := pointerNilChecks(receiver, pointerPaths)
+		for i := range conds {
+			conds[i] += " == nil"
+		}
+		expr = fmt.Sprintf("func() %s {\nif %s {\nreturn *new(%s)\n}\nreturn %s\n}()", fieldType, strings.Join(conds, " || "), fieldType, expr)
+	}
+	return expr, fieldType, typ, true, nil
+}
+
+// checkPropBindingType checks that binding, read by expr, can be passed to prop of the child
+// component compInfo. The type-checked types are compared when both are known, so aliases and
+// types of other packages are matched exactly; otherwise the types as written are. A number
+// of another numeric type is converted; any other mismatch is a compile error naming both
+// types. Written types that may name the same type differently (a named type and its
+// underlying type, or types of two packages) are left to the Go compiler.
+func checkPropBindingType(expr, binding, sourceType string, source types.Type, prop propertyDescriptor, compInfo, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	mismatch := func(from, to string) error {
+		return fmt.Errorf("Compilation Error in %s:%d: Binding '{%s}' is of type '%s', which cannot be passed to prop '%s' of type '%s' on component '%s'.\n%s",
+			currentComp.Path, lineNumber, binding, from, prop.Name, to, compInfo.PascalName, getContextLines(htmlSource, lineNumber, 2))
+	}
+
+	if source != nil && prop.Type != nil && isValidType(source) && isValidType(prop.Type) {
+		if types.AssignableTo(source, prop.Type) {
+			return expr, nil
+		}
+		sourceBasic, sourceIsBasic := source.Underlying().(*types.Basic)
+		targetBasic, targetIsBasic := types.Unalias(prop.Type).(*types.Basic)
+		if sourceIsBasic && targetIsBasic && sourceBasic.Info()&types.IsNumeric != 0 && targetBasic.Info()&types.IsNumeric != 0 {
+			return fmt.Sprintf("%s(%s)", prop.GoType, expr), nil
+		}
+		return "", mismatch(typeString(source, currentComp.Qualifier), typeString(prop.Type, currentComp.Qualifier))
+	}
+
+	canonical := func(goType string) string {
+		switch goType {
+		case "byte":
+			return "uint8"
+		case "rune":
+			return "int32"
+		}
+		return strings.ReplaceAll(goType, " ", "")
+	}
+	from, to := canonical(sourceType), canonical(prop.GoType)
+	switch {
+	case from == "" || from == to:
+		return expr, nil
+	case isBuiltinType(from) && isBuiltinType(to):
+		if isNumericType(from) && isNumericType(to) {
+			return fmt.Sprintf("%s(%s)", prop.GoType, expr), nil
+		}
+		return "", mismatch(sourceType, prop.GoType)
+	case compInfo.ImportPath == currentComp.ImportPath && !namedTypeRegex.MatchString(from) && !namedTypeRegex.MatchString(to):
+		// Composite types of one package ([]User, map[string]int) are equal only when written alike
+		return "", mismatch(sourceType, prop.GoType)
 	}
+	return expr, nil
 }
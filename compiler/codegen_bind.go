@@ -0,0 +1,122 @@
+package compiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// generateBindAttributes expands @bind="Field" on an <input>, <textarea> or <select> into the
+// attribute showing the field and the handler writing the control's value back to it:
+//
+//	<input @bind="Name">                   -> "value": c.Name, "onInput": ...
+//	<input type="checkbox" @bind="Done">   -> "checked": c.Done, "onChange": ...
+//	<select @bind="Language">              -> "value": c.Language, "onChange": ...
+//
+// The handler converts the value to the field's type, leaving the field unchanged when it
+// does not parse (a half-typed number), and re-renders the component. The field must be a
+// string, integer, float or, on a checkbox, bool prop or state field of the component.
+func generateBindAttributes(n *html.Node, fieldName, receiver string, currentComp componentInfo, htmlSource string, lineNumber int) (attr string, handler string, err error) {
+	fail := func(format string, a ...any) error {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), contextLines)
+	}
+
+	if n.Data != "input" && n.Data != "textarea" && n.Data != "select" {
+		return "", "", fail("@bind is only supported on <input>, <textarea> and <select>, not on <%s>.", n.Data)
+	}
+	inputType := "text"
+	for _, a := range n.Attr {
+		if a.Key == "type" && !strings.Contains(a.Val, "{") {
+			inputType = strings.ToLower(a.Val)
+		}
+	}
+	if n.Data == "input" && inputType == "radio" {
+		return "", "", fail("@bind is not supported on radio buttons: bind the group's value with value=\"{Field}\" and handle @onchange.")
+	}
+	checkbox := n.Data == "input" && inputType == "checkbox"
+
+	// The bound attribute and event must not be written out as well
+	attrName, eventName := "value", "oninput"
+	if checkbox {
+		attrName = "checked"
+	}
+	if checkbox || n.Data == "select" {
+		eventName = "onchange"
+	}
+	for _, a := range n.Attr {
+		if a.Key == attrName || a.Key == "@"+eventName {
+			return "", "", fail("@bind=\"%s\" already sets %s and @%s on <%s>; remove the %s attribute.", fieldName, attrName, eventName, n.Data, a.Key)
+		}
+	}
+
+	if strings.Contains(fieldName, ".") {
+		return "", "", fail("@bind=\"%s\" must name a field of component '%s', such as @bind=\"Name\".", fieldName, currentComp.PascalName)
+	}
+	desc, exists := currentComp.Schema.Props[strings.ToLower(fieldName)]
+	if !exists {
+		desc, exists = currentComp.Schema.State[strings.ToLower(fieldName)]
+	}
+	if !exists {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		return "", "", fail("@bind field '%s' not found on component '%s'. Available fields: [%s]", fieldName, currentComp.PascalName, strings.Join(allFields, ", "))
+	}
+
+	basic := desc.GoType
+	if !isBuiltinType(basic) {
+		basic, _ = resolveNamedBasicType(desc.GoType, filepath.Dir(currentComp.Path))
+	}
+	field := fmt.Sprintf("%s.%s", receiver, desc.Name)
+	convert := func(expr string) string {
+		if desc.GoType == basic {
+			return expr
+		}
+		return fmt.Sprintf("%s(%s)", desc.GoType, expr) // A named type: type Status string
+	}
+	var assign string
+	switch {
+	case checkbox && basic == "bool":
+		assign = fmt.Sprintf("%s = %s", field, convert("e.Checked"))
+	case checkbox:
+		return "", "", fail("@bind on a checkbox needs a bool field, but '%s' is %s.", desc.Name, desc.GoType)
+	case basic == "bool":
+		return "", "", fail("@bind with bool field '%s' needs <input type=\"checkbox\">.", desc.Name)
+	case basic == "string":
+		assign = fmt.Sprintf("%s = %s", field, convert("e.Value"))
+	case basic == "float32" || basic == "float64":
+		assign = fmt.Sprintf("if v, err := strconv.ParseFloat(e.Value, %s); err == nil {\n%s = %s(v)\n}", bitSize(basic), field, desc.GoType)
+	case strings.HasPrefix(basic, "uint") || basic == "byte":
+		assign = fmt.Sprintf("if v, err := strconv.ParseUint(e.Value, 10, %s); err == nil {\n%s = %s(v)\n}", bitSize(basic), field, desc.GoType)
+	case strings.HasPrefix(basic, "int") || basic == "rune":
+		assign = fmt.Sprintf("if v, err := strconv.ParseInt(e.Value, 10, %s); err == nil {\n%s = %s(v)\n}", bitSize(basic), field, desc.GoType)
+	default:
+		return "", "", fail("Field '%s' of type '%s' cannot be bound with @bind: only string, integer, float and bool fields can.", desc.Name, desc.GoType)
+	}
+
+	jsEventName := jsEventProp(eventName)
+	attr = fmt.Sprintf("%s: %s", strconv.Quote(attrName), field)
+	if currentComp.Schema.Form {
+		// A field already validated is checked again, so its message goes once it is fixed
+		assign += fmt.Sprintf("\n%s.Revalidate(%s)", receiver, strconv.Quote(desc.Name))
+	}
+	handler = fmt.Sprintf("%s: events.AdaptChangeEvent(func(e events.ChangeEventArgs) {\n%s\n%s.StateHasChanged()\n})", strconv.Quote(jsEventName), assign, receiver)
+	return attr, handler, nil
+}
+
+// bitSize returns the bit size strconv parses a value of the built-in numeric type basic with.
+func bitSize(basic string) string {
+	switch basic {
+	case "int8", "uint8", "byte":
+		return "8"
+	case "int16", "uint16":
+		return "16"
+	case "int32", "uint32", "rune", "float32":
+		return "32"
+	case "int64", "uint64", "float64":
+		return "64"
+	}
+	return "0" // int and uint: the platform's size
+}
@@ -0,0 +1,154 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+)
+
+// boolExprParser parses a template condition made of operands joined by && and ||, with !
+// negation and parentheses: IsLoggedIn && !(IsBanned || IsGuest). Operands are left to a
+// resolver, so each kind of condition decides what an operand may be (a bool field, or in
+// {@if} also a pointer field or a comparison such as Count > 0).
+type boolExprParser struct {
+	src     string
+	pos     int
+	resolve func(operand string) (string, error) // Returns the Go expression of one operand
+}
+
+// operandError is an operand the resolver of a condition rejected, as opposed to a condition
+// that does not parse. It holds the resolver's error, which is already a full compile error.
+type operandError struct{ err error }
+
+func (e operandError) Error() string { return e.err.Error() }
+func (e operandError) Unwrap() error { return e.err }
+
+// generateBooleanExpression returns the Go expression equivalent to the condition expr, with
+// each operand converted by resolve. && binds tighter than ||, as in Go. An error of resolve
+// is returned as an operandError.
+func generateBooleanExpression(expr string, resolve func(operand string) (string, error)) (string, error) {
+	p := &boolExprParser{src: expr, resolve: resolve}
+	goExpr, err := p.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return "", fmt.Errorf("unexpected '%s'", p.src[p.pos:])
+	}
+	return goExpr, nil
+}
+
+// parseOr parses operands joined by ||.
+func (p *boolExprParser) parseOr() (string, error) {
+	return p.parseJoined("||", p.parseAnd)
+}
+
+// parseAnd parses operands joined by &&.
+func (p *boolExprParser) parseAnd() (string, error) {
+	return p.parseJoined("&&", p.parseUnary)
+}
+
+// parseJoined parses one or more terms separated by operator.
+func (p *boolExprParser) parseJoined(operator string, parseTerm func() (string, error)) (string, error) {
+	first, err := parseTerm()
+	if err != nil {
+		return "", err
+	}
+	terms := []string{first}
+	for p.skipSpace(); strings.HasPrefix(p.src[p.pos:], operator); p.skipSpace() {
+		p.pos += len(operator)
+		term, err := parseTerm()
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " "+operator+" "), nil
+}
+
+// parseUnary parses a negation, a parenthesized condition or an operand.
+func (p *boolExprParser) parseUnary() (string, error) {
+	p.skipSpace()
+	rest := p.src[p.pos:]
+	switch {
+	case strings.HasPrefix(rest, "!") && !strings.HasPrefix(rest, "!="):
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return "", err
+		}
+		if !fieldPathRegex.MatchString(operand) && !isParenthesized(operand) {
+			operand = "(" + operand + ")"
+		}
+		return "!" + operand, nil
+	case strings.HasPrefix(rest, "("):
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if p.skipSpace(); !strings.HasPrefix(p.src[p.pos:], ")") {
+			return "", fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return "(" + inner + ")", nil
+	}
+	return p.parseOperand()
+}
+
+// parseOperand reads an operand up to the next &&, || or unmatched ')', skipping quoted
+// literals and the parentheses of calls such as len(Items).
+func (p *boolExprParser) parseOperand() (string, error) {
+	start, depth := p.pos, 0
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		if depth == 0 && (strings.HasPrefix(rest, "&&") || strings.HasPrefix(rest, "||") || rest[0] == ')') {
+			break
+		}
+		switch rest[0] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '\'', '"':
+			if end := strings.IndexByte(rest[1:], rest[0]); end >= 0 {
+				p.pos += end + 1
+			}
+		}
+		p.pos++
+	}
+	operand := strings.TrimSpace(p.src[start:p.pos])
+	if operand == "" {
+		return "", fmt.Errorf("missing operand at '%s'", p.src[start:])
+	}
+	goExpr, err := p.resolve(operand)
+	if err != nil {
+		return "", operandError{err}
+	}
+	return goExpr, nil
+}
+
+// skipSpace advances past whitespace.
+func (p *boolExprParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// isParenthesized reports whether expr is wrapped in one pair of matching parentheses.
+func isParenthesized(expr string) bool {
+	if !strings.HasPrefix(expr, "(") || !strings.HasSuffix(expr, ")") {
+		return false
+	}
+	depth := 0
+	for i, r := range expr {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 && i < len(expr)-1 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package compiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// classEntry is one entry of a {classes ...} expression: an always-on literal, or a class
+// added when a bool condition holds.
+type classEntry struct {
+	Negated   bool
+	Condition string // Empty for an always-on literal
+	Class     string // One or more space-separated classes
+}
+
+// parseClassesExpression splits the body of {classes ...} into its entries. Each entry is
+// either a quoted literal ('card') or a Condition:'class' pair, optionally negated (!Condition).
+func parseClassesExpression(body string) ([]classEntry, error) {
+	var entries []classEntry
+	rest := strings.TrimSpace(body)
+	for rest != "" {
+		match := classEntryRegex.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("unexpected '%s'; expected 'class' or Condition:'class'", rest)
+		}
+		entries = append(entries, classEntry{Negated: match[1] == "!", Condition: match[2], Class: match[3]})
+		rest = strings.TrimSpace(rest[len(match[0]):])
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no classes listed")
+	}
+	return entries, nil
+}
+
+// classObjectBody rewrites a class object binding, class="{Active: 'is-active', HasError:
+// 'error'} card", as the body of the equivalent {classes} list: static classes written
+// before or after the braces become always-on literals in place, and each Condition:'class'
+// pair of the object keeps its order. ok is false when value is not a class object; an
+// object entry without a condition is reported in err.
+func classObjectBody(value string) (body string, ok bool, err error) {
+	match := classObjectRegex.FindStringSubmatch(value)
+	if match == nil {
+		return "", false, nil
+	}
+	var parts []string
+	for _, class := range strings.Fields(match[1]) {
+		parts = append(parts, "'"+class+"'")
+	}
+	for _, pair := range strings.Split(strings.TrimSuffix(strings.TrimSpace(match[2]), ","), ",") {
+		entry := classEntryRegex.FindStringSubmatch(strings.TrimSpace(pair))
+		if entry == nil || entry[0] != strings.TrimSpace(pair) || entry[2] == "" {
+			return "", true, fmt.Errorf("unexpected '%s'; expected Condition: 'class'", strings.TrimSpace(pair))
+		}
+		parts = append(parts, entry[0])
+	}
+	for _, class := range strings.Fields(match[3]) {
+		parts = append(parts, "'"+class+"'")
+	}
+	return strings.Join(parts, " "), true, nil
+}
+
+// generateClassesExpression compiles the body of a {classes ...} expression into a Go
+// expression that appends the matching classes, in the order written, separated by single
+// spaces. Conditions must be bool component fields or, inside a loop, bool fields of the
+// loop variable. A class listed twice is a compile error.
+func generateClassesExpression(body, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (string, error) {
+	contextLines := getContextLines(htmlSource, lineNumber, 2)
+	entries, err := parseClassesExpression(body)
+	if err != nil {
+		return "", fmt.Errorf("Compilation Error in %s:%d: Invalid {classes} expression: %v\n%s"+
+			"Expected format: class=\"{classes Active:'is-active' !Enabled:'is-disabled' 'card'}\"\n",
+			currentComp.Path, lineNumber, err, contextLines)
+	}
+
+	var code strings.Builder
+	code.WriteString(`func() string {
+		var classes strings.Builder
+		add := func(class string) {
+			if classes.Len() > 0 {
+				classes.WriteByte(' ')
+			}
+			classes.WriteString(class)
+		}
+`)
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		classes := strings.Fields(entry.Class)
+		if len(classes) == 0 {
+			return "", fmt.Errorf("Compilation Error in %s:%d: Empty class literal in {classes} expression.\n%s",
+				currentComp.Path, lineNumber, contextLines)
+		}
+		for _, class := range classes {
+			if seen[class] {
+				return "", fmt.Errorf("Compilation Error in %s:%d: Class '%s' is listed more than once in {classes} expression.\n%s",
+					currentComp.Path, lineNumber, class, contextLines)
+			}
+			seen[class] = true
+		}
+		add := fmt.Sprintf("add(%s)\n", strconv.Quote(strings.Join(classes, " ")))
+
+		if entry.Condition == "" {
+			code.WriteString(add)
+			continue
+		}
+		condition, err := resolveClassCondition(entry.Condition, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+		if err != nil {
+			return "", err
+		}
+		if entry.Negated {
+			if negated, ok := strings.CutPrefix(condition, "!"); ok {
+				condition = negated // !Invalid.Email: the field is valid
+			} else {
+				condition = "!" + condition
+			}
+		}
+		fmt.Fprintf(&code, "if %s {\n%s}\n", condition, add)
+	}
+	code.WriteString("return classes.String()\n}()")
+	return code.String(), nil
+}
+
+// resolveClassCondition returns the Go expression for a {classes} condition: a bool prop or
+// state field, a bool field of the value variable of an enclosing loop (item.Selected), or
+// Invalid.Field on a form.
+func resolveClassCondition(condition, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (string, error) {
+	varName, fieldName, isField := strings.Cut(condition, ".")
+	scope := loopCtx.valueScope(varName)
+	if scope == nil {
+		if expr, goType, ok, err := resolveFormBinding(condition, receiver, currentComp); ok && (err != nil || goType != "bool") {
+			if err == nil {
+				err = fmt.Errorf("Condition '%s' must be a bool field, found type '%s'.", condition, goType)
+			}
+			return "", fmt.Errorf("Compilation Error in %s:%d: %v\n%s", currentComp.Path, lineNumber, err, getContextLines(htmlSource, lineNumber, 2))
+		} else if ok {
+			return expr, nil
+		}
+		if isField {
+			return "", fmt.Errorf("Compilation Error in %s:%d: Condition '%s' refers to '%s', which is not a loop variable in scope.\n%s",
+				currentComp.Path, lineNumber, condition, varName, getContextLines(htmlSource, lineNumber, 2))
+		}
+		propDesc, err := validateBooleanCondition(condition, currentComp, currentComp.Path, lineNumber, htmlSource)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.%s", receiver, propDesc.Name), nil
+	}
+
+	goType := scope.ElementType
+	if isField {
+		var err error
+		goType, err = resolveLoopFieldType(fieldName, currentComp, scope)
+		if err != nil {
+			return "", fmt.Errorf("Compilation Error in %s:%d: Condition '%s': %v\n%s",
+				currentComp.Path, lineNumber, condition, err, getContextLines(htmlSource, lineNumber, 2))
+		}
+	}
+	if goType != "bool" {
+		return "", fmt.Errorf("Compilation Error in %s:%d: Condition '%s' must be a bool field, found type '%s'.\n%s",
+			currentComp.Path, lineNumber, condition, goType, getContextLines(htmlSource, lineNumber, 2))
+	}
+	return condition, nil
+}
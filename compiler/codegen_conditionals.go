@@ -1,126 +1,334 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
 )
 
-// generateConditionalCode generates Go if/else blocks for conditional rendering.
+// comparisonConditionRegex matches a condition comparing a field path, or len() of one, with
+// a literal: Count > 0, User.Age >= 18, Status == 'active', len(Items) == 0.
+var comparisonConditionRegex = regexp.MustCompile(`^(?:len\(\s*([A-Za-z_][\w.]*)\s*\)|([A-Za-z_][\w.]*))\s*(==|!=|<=|>=|<|>)\s*(.+)$`)
+
+// numberLiteralRegex matches the number literals a condition may compare with.
+var numberLiteralRegex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// generateConditionalCode generates Go if/else blocks for conditional rendering. Each branch
+// may hold any number of nodes, so the IIFE returns a slice that parents spread like a loop's;
+// a branch without nodes, or no branch taken, yields an empty slice. An invalid condition is
+// recorded in opts.Errors, and its branch is still generated to report the errors inside it.
 func generateConditionalCode(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) string {
 	var code strings.Builder
 
 	// Generate IIFE (Immediately Invoked Function Expression)
-	code.WriteString("func() *vdom.VNode {\n")
+	code.WriteString(fragmentPrefix + " {\n")
 
 	// Process children of go-conditional wrapper
+	hasElse := false
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
-		if c.Type == html.ElementNode && c.Data == "go-if" {
-			// Extract and validate condition
-			cond := ""
-			for _, attr := range c.Attr {
-				if attr.Key == "data-cond" {
-					cond = attr.Val
-					break
-				}
-			}
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.Data {
+		case "go-if":
+			fmt.Fprintf(&code, "if %s {\n", ifCondition(c, receiver, currentComp, htmlSource, opts))
+		case "go-elseif":
+			fmt.Fprintf(&code, " else if %s {\n", ifCondition(c, receiver, currentComp, htmlSource, opts))
+		case "go-else":
+			code.WriteString(" else {\n")
+			hasElse = true
+		default:
+			continue
+		}
+		writeBranchNodes(&code, c, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+		code.WriteString("}")
+	}
 
-			propDesc, exists := currentComp.Schema.Props[strings.ToLower(cond)]
-			if !exists {
-				// Also check state fields
-				propDesc, exists = currentComp.Schema.State[strings.ToLower(cond)]
-			}
-			if !exists {
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: Condition '%s' not found on component '%s'.\n", currentComp.Path, cond, currentComp.PascalName)
-				os.Exit(1)
-			}
-			if propDesc.GoType != "bool" {
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: Condition '%s' must be a bool field, found type '%s'.\n", currentComp.Path, cond, propDesc.GoType)
-				os.Exit(1)
-			}
+	// Only add the fallback if there's no else branch: with one, every path already returns
+	if !hasElse {
+		code.WriteString("\nreturn []*vdom.VNode{}")
+	}
+	code.WriteString("\n}()")
+	return code.String()
+}
 
-			fmt.Fprintf(&code, "if %s.%s {\n", receiver, propDesc.Name)
-			foundContent := false
-			for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
-				childCode := generateNodeCode(cc, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
-				if childCode != "" {
-					code.WriteString("return ")
-					code.WriteString(childCode)
-					code.WriteString("\n")
-					foundContent = true
-					break
-				}
-			}
-			if !foundContent {
-				code.WriteString("return nil\n")
-			}
-			code.WriteString("}")
-		} else if c.Type == html.ElementNode && c.Data == "go-elseif" {
-			// Extract and validate condition
-			elseifCond := ""
-			for _, attr := range c.Attr {
-				if attr.Key == "data-cond" {
-					elseifCond = attr.Val
-					break
-				}
-			}
+// ifCondition returns the Go condition of a go-if or go-elseif placeholder, recording an
+// invalid one in opts.Errors.
+func ifCondition(n *html.Node, receiver string, currentComp componentInfo, htmlSource string, opts compileOptions) string {
+	goExpr, err := resolveIfCondition(conditionOf(n), receiver, currentComp, htmlSource, opts.Lines.line(n))
+	if err != nil {
+		opts.Errors.add(err)
+		return "false"
+	}
+	return goExpr
+}
 
-			propDesc, exists := currentComp.Schema.Props[strings.ToLower(elseifCond)]
-			if !exists {
-				// Also check state fields
-				propDesc, exists = currentComp.Schema.State[strings.ToLower(elseifCond)]
-			}
-			if !exists {
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: Condition '%s' not found on component '%s'.\n", currentComp.Path, elseifCond, currentComp.PascalName)
-				os.Exit(1)
-			}
-			if propDesc.GoType != "bool" {
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: Condition '%s' must be a bool field, found type '%s'.\n", currentComp.Path, elseifCond, propDesc.GoType)
-				os.Exit(1)
-			}
+// conditionOf returns the condition of a go-if or go-elseif placeholder.
+func conditionOf(n *html.Node) string {
+	for _, attr := range n.Attr {
+		if attr.Key == "data-cond" {
+			return attr.Val
+		}
+	}
+	return ""
+}
 
-			fmt.Fprintf(&code, " else if %s.%s {\n", receiver, propDesc.Name)
-			foundContent := false
-			for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
-				childCode := generateNodeCode(cc, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
-				if childCode != "" {
-					code.WriteString("return ")
-					code.WriteString(childCode)
-					code.WriteString("\n")
-					foundContent = true
-					break
-				}
-			}
-			if !foundContent {
-				code.WriteString("return nil\n")
-			}
-			code.WriteString("}")
-		} else if c.Type == html.ElementNode && c.Data == "go-else" {
-			code.WriteString(" else {\n")
-			foundContent := false
-			for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
-				childCode := generateNodeCode(cc, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
-				if childCode != "" {
-					code.WriteString("return ")
-					code.WriteString(childCode)
-					code.WriteString("\n")
-					foundContent = true
-					break
-				}
-			}
-			if !foundContent {
-				code.WriteString("return nil\n")
+// writeBranchNodes writes the body of a conditional branch: it collects the nodes of every
+// child of branch, spreading loops and other fragments, and returns them.
+func writeBranchNodes(code *strings.Builder, branch *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) {
+	code.WriteString("branchNodes := []*vdom.VNode{}\n")
+	for cc := branch.FirstChild; cc != nil; cc = cc.NextSibling {
+		childCode := generateNodeCode(cc, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+		switch {
+		case childCode == "":
+			continue
+		case strings.HasPrefix(childCode, fragmentPrefix):
+			fmt.Fprintf(code, "branchNodes = append(branchNodes, %s...)\n", childCode)
+		default:
+			fmt.Fprintf(code, "branchNodes = append(branchNodes, %s)\n", childCode)
+		}
+	}
+	code.WriteString("return branchNodes\n")
+}
+
+// checkSingleRoot rejects a template with nodes besides its root element, which Render
+// would drop: it returns a single root. Comments and whitespace around the root are fine.
+func checkSingleRoot(root *html.Node, lines *templateLines, templatePath string) error {
+	for c := root.Parent.FirstChild; c != nil; c = c.NextSibling {
+		if c == root || c.Type == html.CommentNode || (c.Type == html.TextNode && strings.TrimSpace(c.Data) == "") {
+			continue
+		}
+		found := describeRootNode(c)
+		if c.Type == html.TextNode {
+			found = fmt.Sprintf("the text %q", strings.TrimSpace(c.Data))
+		}
+		return fmt.Errorf("template validation error in %s:%d: the template has more than one root: %s besides %s, and Render returns a single element.\n"+
+			"  Wrap them in one element; <div style=\"display: contents\"> keeps the layout of a grid or flex parent", templatePath, lines.line(c), found, describeRootNode(root))
+	}
+	return nil
+}
+
+// describeRootNode names an element at the root of a template as written: its tag, or the
+// block it stands for.
+func describeRootNode(n *html.Node) string {
+	switch n.Data {
+	case "go-conditional":
+		return "an {@if} block"
+	case "go-for":
+		return "a {@for} block"
+	case "go-switch":
+		return "a {@switch} block"
+	}
+	return "<" + n.Data + ">"
+}
+
+// checkRootConditional rejects an {@if} at the root of a template whose branches render more
+// than one node: Render returns a single root, the first node of the branch taken.
+func checkRootConditional(root *html.Node, lines *templateLines, templatePath string) error {
+	if root.Data != "go-conditional" {
+		return nil
+	}
+	for branch := root.FirstChild; branch != nil; branch = branch.NextSibling {
+		nodes := 0
+		for c := branch.FirstChild; c != nil; c = c.NextSibling {
+			switch {
+			case c.Type == html.CommentNode, c.Type == html.TextNode && strings.TrimSpace(c.Data) == "":
+				continue
+			case c.Type == html.ElementNode && (c.Data == "go-for" || c.Data == "go-conditional" || c.Data == "go-switch" || c.Data == "go-slot"):
+				nodes += 2 // May render any number of nodes
+			default:
+				nodes++
 			}
-			code.WriteString("}\n")
-			// Don't add the fallback return nil after else block
-			code.WriteString("}()")
-			return code.String()
+		}
+		if nodes > 1 {
+			cond := conditionOf(root.FirstChild)
+			lineNumber := lines.line(root)
+			return fmt.Errorf("template validation error in %s:%d: the {@if %s} at the root of the template must render a single element in each branch.\n"+
+				"  Wrap the content of the branches in one element, or move the {@if} inside the root element", templatePath, lineNumber, cond)
 		}
 	}
+	return nil
+}
 
-	// Only add fallback return nil if there's no else branch
-	code.WriteString("\nreturn nil\n}()")
-	return code.String()
+// resolveIfCondition validates an {@if}/{@else if} condition and returns it as a Go expression.
+// A condition joins operands with && and ||, with ! negation and parentheses
+// ({@if IsLoggedIn && !IsBanned}); resolveIfOperand converts each operand.
+func resolveIfCondition(cond, receiver string, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	goExpr, err := generateBooleanExpression(cond, func(operand string) (string, error) {
+		return resolveIfOperand(operand, receiver, currentComp, htmlSource, lineNumber)
+	})
+	var rejected operandError
+	if errors.As(err, &rejected) {
+		return "", rejected.err
+	}
+	if err != nil {
+		return "", fmt.Errorf("Compilation Error in %s:%d: Invalid condition '%s': %v.\n%s",
+			currentComp.Path, lineNumber, cond, err, getContextLines(htmlSource, lineNumber, 2))
+	}
+	return goExpr, nil
+}
+
+// resolveIfOperand validates one operand of an {@if} condition and returns it as a Go
+// expression. Bool fields are used as-is; pointer fields are the idiomatic nil guard and test
+// for non-nil, so {@if User} protects bindings such as {User.Name} in its branch. Comparisons
+// with a literal ({@if Count > 0}) are handled by resolveComparisonCondition.
+func resolveIfOperand(cond, receiver string, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	if match := comparisonConditionRegex.FindStringSubmatch(strings.TrimSpace(cond)); match != nil {
+		return resolveComparisonCondition(cond, match, receiver, currentComp, htmlSource, lineNumber)
+	}
+	propDesc, exists := currentComp.Schema.Props[strings.ToLower(cond)]
+	if !exists {
+		// Also check state fields
+		propDesc, exists = currentComp.Schema.State[strings.ToLower(cond)]
+	}
+	if !exists {
+		return "", fmt.Errorf("Compilation Error in %s:%d: Condition '%s' not found on component '%s'.\n", currentComp.Path, lineNumber, cond, currentComp.PascalName)
+	}
+	switch {
+	case propDesc.GoType == "bool":
+		return fmt.Sprintf("%s.%s", receiver, propDesc.Name), nil
+	case strings.HasPrefix(propDesc.GoType, "*"):
+		return fmt.Sprintf("%s.%s != nil", receiver, propDesc.Name), nil
+	default:
+		return "", fmt.Errorf("Compilation Error in %s:%d: Condition '%s' must be a bool or pointer field, found type '%s'.\n", currentComp.Path, lineNumber, cond, propDesc.GoType)
+	}
+}
+
+// resolveComparisonCondition validates a comparison matched by comparisonConditionRegex and
+// returns it as a Go expression. The operands must both be strings, numbers or bools (bools
+// only with == and !=); a field read through a nil pointer makes the condition false.
+func resolveComparisonCondition(cond string, match []string, receiver string, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	fail := func(format string, args ...any) (string, error) {
+		return "", fmt.Errorf("Compilation Error in %s:%d: Condition '%s': %s\n%s",
+			currentComp.Path, lineNumber, cond, fmt.Sprintf(format, args...), getContextLines(htmlSource, lineNumber, 2))
+	}
+
+	lenOf, path, operator, literal := match[1], match[2], match[3], strings.TrimSpace(match[4])
+	if lenOf != "" {
+		path = lenOf
+	}
+	expr, goType, typeInfo, nilChecks, err := resolveConditionOperand(path, receiver, currentComp)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	kind, integer := comparisonKind(goType, typeInfo)
+	if lenOf != "" {
+		if !hasLength(goType, typeInfo) && kind != "string" {
+			return fail("len() needs a slice, map or string field, found '%s' of type '%s'.", path, goType)
+		}
+		expr, goType, kind, integer = fmt.Sprintf("len(%s)", expr), "int", "number", true
+	}
+
+	literalExpr, literalKind := literal, ""
+	switch {
+	case len(literal) >= 2 && (literal[0] == '\'' || literal[0] == '"') && literal[len(literal)-1] == literal[0]:
+		literalExpr, literalKind = strconv.Quote(literal[1:len(literal)-1]), "string"
+	case literal == "true" || literal == "false":
+		literalKind = "bool"
+	case numberLiteralRegex.MatchString(literal):
+		literalKind = "number"
+	default:
+		return fail("the right operand '%s' must be a literal: a quoted string ('active'), a number or true/false.", literal)
+	}
+
+	switch {
+	case kind == "":
+		return fail("'%s' has type '%s', which cannot be compared; comparisons need string, numeric or bool operands.", path, goType)
+	case kind != literalKind:
+		return fail("cannot compare %s field '%s' (type '%s') with %s literal %s.", kind, path, goType, literalKind, literal)
+	case kind == "bool" && operator != "==" && operator != "!=":
+		return fail("bool values can only be compared with == and !=.")
+	case integer && strings.Contains(literal, "."):
+		return fail("cannot compare integer field '%s' (type '%s') with the fractional literal %s.", path, goType, literal)
+	}
+
+	comparison := fmt.Sprintf("%s %s %s", expr, operator, literalExpr)
+	for i := len(nilChecks) - 1; i >= 0; i-- {
+		comparison = fmt.Sprintf("%s != nil && %s", nilChecks[i], comparison)
+	}
+	return comparison, nil
+}
+
+// resolveConditionOperand resolves the field path a comparison reads to its Go expression, its
+// type, written and type-checked (nil when unknown), and the pointers read along the path.
+func resolveConditionOperand(path, receiver string, currentComp componentInfo) (string, string, types.Type, []string, error) {
+	rootName, _, isNested := strings.Cut(path, ".")
+	propDesc, exists := currentComp.Schema.Props[strings.ToLower(rootName)]
+	if !exists {
+		// Also check state fields
+		propDesc, exists = currentComp.Schema.State[strings.ToLower(rootName)]
+	}
+	if !exists {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		return "", "", nil, nil, fmt.Errorf("field '%s' not found on component '%s'. Available fields: [%s]", rootName, currentComp.PascalName, strings.Join(allFields, ", "))
+	}
+	if !isNested {
+		return fmt.Sprintf("%s.%s", receiver, propDesc.Name), propDesc.GoType, propDesc.Type, nil, nil
+	}
+	if rootType := fieldRootType(rootName, currentComp); rootType != nil {
+		fieldType, pointerPaths, err := resolveTypedFieldPath(strings.Split(path, "."), rootType, currentComp.Qualifier)
+		if err != nil {
+			return "", "", nil, nil, err
+		}
+		return fmt.Sprintf("%s.%s", receiver, path), typeString(fieldType, currentComp.Qualifier), fieldType, pointerNilChecks(receiver, pointerPaths), nil
+	}
+	goType, pointerPaths, err := resolveNestedFieldPath(path, currentComp, filepath.Dir(currentComp.Path))
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	return fmt.Sprintf("%s.%s", receiver, path), goType, nil, pointerNilChecks(receiver, pointerPaths), nil
+}
+
+// hasLength reports whether len() applies to a slice, array or map field of type goType,
+// type-checked as t (nil when unknown).
+func hasLength(goType string, t types.Type) bool {
+	if t != nil {
+		switch t.Underlying().(type) {
+		case *types.Slice, *types.Array, *types.Map:
+			return true
+		}
+		return false
+	}
+	return strings.HasPrefix(goType, "[") || strings.HasPrefix(goType, "map[")
+}
+
+// comparisonKind classifies a comparison operand of type goType, type-checked as t (nil when
+// unknown), as "string", "number" or "bool", and reports whether it is an integer. Other
+// types yield "".
+func comparisonKind(goType string, t types.Type) (kind string, integer bool) {
+	if t != nil {
+		basic, ok := t.Underlying().(*types.Basic)
+		switch {
+		case !ok:
+			return "", false
+		case basic.Info()&types.IsString != 0:
+			return "string", false
+		case basic.Info()&types.IsBoolean != 0:
+			return "bool", false
+		case basic.Info()&types.IsInteger != 0:
+			return "number", true
+		case basic.Info()&types.IsFloat != 0:
+			return "number", false
+		}
+		return "", false
+	}
+	switch goType {
+	case "string":
+		return "string", false
+	case "bool":
+		return "bool", false
+	case "float32", "float64":
+		return "number", false
+	}
+	if isBuiltinType(goType) {
+		return "number", true
+	}
+	return "", false
 }
@@ -0,0 +1,115 @@
+package compiler
+
+import "strings"
+
+// bindingFilter is one stage of the filter pipeline of a text binding, such as number 2 in
+// {Price | number 2}.
+type bindingFilter struct {
+	Name string
+	Args []string // Unquoted arguments
+}
+
+// bindingFilterArity maps the filters a text binding may use to their number of arguments.
+var bindingFilterArity = map[string]int{
+	"printf":  1,
+	"date":    1,
+	"number":  1,
+	"upper":   0,
+	"lower":   0,
+	"default": 1,
+}
+
+// supportedFilters lists the filter names for error messages.
+const supportedFilters = "printf, date, number, upper, lower, default"
+
+// parseFilterPipeline splits the filters written after the value of a text binding, as
+// matched by textBindingRegex: ` | date "Jan 2" | default:'—'`. Arguments follow the filter
+// name separated by spaces, quoted with " or ' when they hold spaces, or come as a single
+// quoted argument after a colon (date:'Jan 2').
+func parseFilterPipeline(pipeline string) []bindingFilter {
+	var filters []bindingFilter
+	for _, stage := range splitOutsideQuotes(pipeline, '|') {
+		stage = strings.TrimSpace(stage)
+		if stage == "" {
+			continue
+		}
+		nameEnd := strings.IndexFunc(stage, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+		})
+		if nameEnd < 0 {
+			nameEnd = len(stage)
+		}
+		filter := bindingFilter{Name: stage[:nameEnd]}
+		rest := strings.TrimSpace(stage[nameEnd:])
+		if arg, ok := strings.CutPrefix(rest, ":"); ok {
+			filter.Args = []string{unquoteFilterArg(strings.TrimSpace(arg))}
+		} else {
+			for _, arg := range splitOutsideQuotes(rest, ' ') {
+				if arg = strings.TrimSpace(arg); arg != "" {
+					filter.Args = append(filter.Args, unquoteFilterArg(arg))
+				}
+			}
+		}
+		filters = append(filters, filter)
+	}
+	return filters
+}
+
+// splitOutsideQuotes splits s at each sep that is not inside a '...' or "..." literal.
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '\'' || s[i] == '"':
+			quote = s[i]
+		case s[i] == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquoteFilterArg strips the quotes around a filter argument.
+func unquoteFilterArg(arg string) string {
+	if len(arg) >= 2 && (arg[0] == '\'' || arg[0] == '"') && arg[len(arg)-1] == arg[0] {
+		return arg[1 : len(arg)-1]
+	}
+	return arg
+}
+
+// splitDefaultFilter validates the filters of a text binding and separates the default
+// filter, which must come last, from the formatting filters before it. It reports unknown
+// filters and wrong argument counts as compile errors.
+func splitDefaultFilter(filters []bindingFilter, fieldName string, currentComp componentInfo, htmlSource string, lineNumber int) (formatting []bindingFilter, fallback string, hasDefault bool, err error) {
+	for _, filter := range filters {
+		arity, known := bindingFilterArity[filter.Name]
+		if !known {
+			return nil, "", false, textBindingError(currentComp, htmlSource, lineNumber, "Unknown filter '%s' on '%s'. Supported filters: %s\n", filter.Name, fieldName, supportedFilters)
+		}
+		if len(filter.Args) != arity {
+			return nil, "", false, textBindingError(currentComp, htmlSource, lineNumber, "The %s filter on '%s' takes %d argument(s), but %d were given\n",
+				filter.Name, fieldName, arity, len(filter.Args))
+		}
+	}
+	for _, filter := range filters {
+		if filter.Name != "default" {
+			formatting = append(formatting, filter)
+			continue
+		}
+		if hasDefault {
+			return nil, "", false, textBindingError(currentComp, htmlSource, lineNumber, "The default filter on '%s' is given twice\n", fieldName)
+		}
+		fallback, hasDefault = filter.Args[0], true
+	}
+	if hasDefault && filters[len(filters)-1].Name != "default" {
+		return nil, "", false, textBindingError(currentComp, htmlSource, lineNumber, "The default filter on '%s' must come last, after the formatting filters\n", fieldName)
+	}
+	return formatting, fallback, hasDefault, nil
+}
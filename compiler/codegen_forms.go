@@ -0,0 +1,42 @@
+package compiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// formValidatorType is the type a component embeds to validate its fields as a form; see
+// package github.com/ForgeLogic/nojs/forms.
+const formValidatorType = "forms.Validator"
+
+// resolveFormBinding resolves Errors.Field and Invalid.Field on a component embedding a
+// forms.Validator, which the Validator has no fields for: Errors.Email is the message of the
+// Email field, a string, and Invalid.Email whether it has one, a bool. ok is false for any
+// other binding, and for components with fields of their own named Errors or Invalid.
+func resolveFormBinding(binding, receiver string, currentComp componentInfo) (expr, goType string, ok bool, err error) {
+	root, field, isNested := strings.Cut(binding, ".")
+	if !currentComp.Schema.Form || !isNested || (root != "Errors" && root != "Invalid") {
+		return "", "", false, nil
+	}
+	if _, own := currentComp.Schema.Props[strings.ToLower(root)]; own {
+		return "", "", false, nil
+	}
+	if _, own := currentComp.Schema.State[strings.ToLower(root)]; own {
+		return "", "", false, nil
+	}
+
+	desc, exists := currentComp.Schema.Props[strings.ToLower(field)]
+	if !exists {
+		desc, exists = currentComp.Schema.State[strings.ToLower(field)]
+	}
+	if !exists || desc.Name != field {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		return "", "", true, fmt.Errorf("'%s' names no field of component '%s', whose Validator checks its fields. Available fields: [%s]",
+			binding, currentComp.PascalName, strings.Join(allFields, ", "))
+	}
+	if root == "Errors" {
+		return fmt.Sprintf("%s.ErrorFor(%s)", receiver, strconv.Quote(field)), "string", true, nil
+	}
+	return fmt.Sprintf("!%s.IsValid(%s)", receiver, strconv.Quote(field)), "bool", true, nil
+}
@@ -0,0 +1,117 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// generateHandlerCall validates an event attribute written as a call, @onclick="Select(user.ID)",
+// and returns its adapter expression. Each argument is a loop variable, a field of a loop value
+// variable or a field of the component, and must match the type of the method parameter it is
+// passed to; the method may also take a leading runtime.Ctx. The event arguments themselves are
+// not passed. Arguments are evaluated while rendering and bound as parameters of a function that
+// builds the handler, so every loop iteration gets a handler holding its own values.
+func generateHandlerCall(eventName, handlerName, argList string, n *html.Node, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (string, error) {
+	fail := func(format string, a ...any) error {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), contextLines)
+	}
+
+	if _, err := validateEventOnTag(eventName, n.Data, currentComp.Path, lineNumber, htmlSource); err != nil {
+		return "", err
+	}
+	method, exists := currentComp.Schema.Methods[handlerName]
+	if !exists {
+		return "", fail("Handler method '%s' not found on component '%s'.\nAvailable methods: %s",
+			handlerName, currentComp.PascalName, getAvailableMethodNames(currentComp.Schema.Methods))
+	}
+
+	var args []string
+	if strings.TrimSpace(argList) != "" {
+		args = strings.Split(argList, ",")
+	}
+	params := method.Params
+	takesCtx := handlerTakesCtx(method)
+	if takesCtx {
+		params = params[1:]
+	}
+	if len(args) != len(params) {
+		return "", fail("Handler '%s' for '@%s' is called with %d argument(s) but takes %d: func(c *%s) %s(%s)",
+			handlerName, eventName, len(args), len(params), currentComp.PascalName, handlerName, formatParams(method.Params))
+	}
+
+	// func(arg0 T0, ...) func() { return func() { c.Method(arg0, ...) } }(value0, ...)
+	var boundParams, callArgs, values []string
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		value, goType, err := resolveHandlerArgument(arg, receiver, currentComp, loopCtx)
+		if err != nil {
+			return "", fail("Argument '%s' of handler '%s': %v", arg, handlerName, err)
+		}
+		// An unknown type is left to the Go compiler, which checks the argument against the
+		// parameter it is bound to.
+		if goType != "" && goType != params[i].Type {
+			return "", fail("Argument '%s' of handler '%s' has type '%s', but parameter '%s' is '%s'.",
+				arg, handlerName, goType, params[i].Name, params[i].Type)
+		}
+		name := fmt.Sprintf("arg%d", i)
+		boundParams = append(boundParams, name+" "+params[i].Type)
+		callArgs = append(callArgs, name)
+		values = append(values, value)
+	}
+
+	if takesCtx {
+		callArgs = append([]string{"ctx"}, callArgs...)
+		return fmt.Sprintf(`events.AdaptNoArgEventCtx(%s, func(%s) func(runtime.Ctx) { return func(ctx runtime.Ctx) { %s.%s(%s) } }(%s))`,
+			receiver, strings.Join(boundParams, ", "), receiver, handlerName, strings.Join(callArgs, ", "), strings.Join(values, ", ")), nil
+	}
+	return fmt.Sprintf(`events.AdaptNoArgEvent(func(%s) func() { return func() { %s.%s(%s) } }(%s))`,
+		strings.Join(boundParams, ", "), receiver, handlerName, strings.Join(callArgs, ", "), strings.Join(values, ", ")), nil
+}
+
+// resolveHandlerArgument resolves an argument of a handler call to the Go expression reading it
+// and its Go type (empty when it cannot be determined).
+func resolveHandlerArgument(arg, receiver string, currentComp componentInfo, loopCtx *loopContext) (string, string, error) {
+	if scope := loopCtx.scopeOf(arg); scope != nil {
+		if arg == scope.ValueVar {
+			return arg, scope.ElementType, nil
+		}
+		return arg, scope.IndexType, nil
+	}
+	if varName, field, ok := strings.Cut(arg, "."); ok {
+		scope := loopCtx.valueScope(varName)
+		if scope == nil {
+			return "", "", fmt.Errorf("'%s' is not a loop variable in scope", varName)
+		}
+		goType, err := resolveLoopFieldType(field, currentComp, scope)
+		if err != nil {
+			return "", "", err
+		}
+		return arg, goType, nil
+	}
+
+	desc, exists := currentComp.Schema.Props[strings.ToLower(arg)]
+	if !exists {
+		desc, exists = currentComp.Schema.State[strings.ToLower(arg)]
+	}
+	if !exists {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		if loopCtx != nil {
+			return "", "", fmt.Errorf("not a loop variable (loops have: %s) or a component field (available: %s)",
+				strings.Join(loopCtx.variables(), ", "), strings.Join(allFields, ", "))
+		}
+		return "", "", fmt.Errorf("not a field of component '%s' (available: %s)", currentComp.PascalName, strings.Join(allFields, ", "))
+	}
+	return fmt.Sprintf("%s.%s", receiver, desc.Name), desc.GoType, nil
+}
+
+// formatParams formats a method's parameters as they appear in its signature.
+func formatParams(params []paramDescriptor) string {
+	parts := make([]string, len(params))
+	for i, p := range params {
+		parts[i] = p.Name + " " + p.Type
+	}
+	return strings.Join(parts, ", ")
+}
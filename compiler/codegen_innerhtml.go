@@ -0,0 +1,72 @@
+package compiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// innerHTMLAttribute is the directive setting an element's markup: <div [innerhtml]="{Body}">.
+// The HTML parser lowercases it, so [innerHTML] is accepted as well.
+const innerHTMLAttribute = "[innerhtml]"
+
+// takeInnerHTMLAttribute removes the [innerhtml]="..." attribute from n and returns its value.
+func takeInnerHTMLAttribute(n *html.Node) (string, bool) {
+	for i, attr := range n.Attr {
+		if attr.Key == innerHTMLAttribute {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// generateInnerHTMLExpression validates an [innerhtml]="{Field}" binding on n and returns the
+// string expression to pass to vdom.RawHTML. The field must be a string prop or state field
+// of the component, and n must be an element that can hold markup and has nothing else
+// inside it: its children or text would conflict with the markup replacing them.
+func generateInnerHTMLExpression(value string, n *html.Node, receiver string, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	fail := func(format string, a ...any) error {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), contextLines)
+	}
+
+	switch n.Data {
+	case "input", "textarea", "select", "option", "style", "script", "img", "br", "hr", "wbr", "area", "col", "embed", "source", "track":
+		return "", fail("[innerhtml] cannot be used on <%s>: use it on an element that holds markup, such as <div> or <article>.", n.Data)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode || (c.Type == html.TextNode && strings.TrimSpace(c.Data) != "") {
+			return "", fail("<%s [innerhtml]> must be empty: its content is replaced by the markup, so children or text inside it would never show.", n.Data)
+		}
+	}
+
+	match := dataBindingRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || match[0] != strings.TrimSpace(value) || strings.ContainsAny(match[1], ".(") {
+		return "", fail("[innerhtml]=\"%s\" must bind a field of component '%s', such as [innerhtml]=\"{Body}\".", value, currentComp.PascalName)
+	}
+	fieldName := match[1]
+	desc, exists := currentComp.Schema.Props[strings.ToLower(fieldName)]
+	if !exists {
+		desc, exists = currentComp.Schema.State[strings.ToLower(fieldName)]
+	}
+	if !exists {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		return "", fail("[innerhtml] field '%s' not found on component '%s'. Available fields: [%s]", fieldName, currentComp.PascalName, strings.Join(allFields, ", "))
+	}
+
+	basic := desc.GoType
+	if !isBuiltinType(basic) {
+		basic, _ = resolveNamedBasicType(desc.GoType, filepath.Dir(currentComp.Path))
+	}
+	if basic != "string" {
+		return "", fail("[innerhtml] field '%s' must be a string, found '%s'.", desc.Name, desc.GoType)
+	}
+	field := fmt.Sprintf("%s.%s", receiver, desc.Name)
+	if desc.GoType != "string" {
+		return fmt.Sprintf("string(%s)", field), nil // A named type: type Markup string
+	}
+	return field, nil
+}
@@ -0,0 +1,204 @@
+package compiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// templateLines knows the template line of each node, for the errors about the node and the
+// line directive of its generated code: go build errors and stack traces in the Render method
+// then point at the template instead of the generated file.
+//
+// Elements are matched to the start tags of the source by aligning the parsed tree with the
+// tokenized template, as the markup check does, so the same tag written twice gets two lines.
+// Text nodes are matched, in document order, to the text tokens they start with. A node with
+// no counterpart in the source, like a <tbody> the HTML parser adds, is on the line of its
+// parent.
+type templateLines struct {
+	file     string                       // Base name of the template, which sits next to the generated file
+	elements map[*html.Node]sourceElement // Start tag of each element found in the source
+	texts    map[*html.Node]int           // Line of each text node found in the source
+}
+
+// sourceText is a text token of the template that is not only whitespace.
+type sourceText struct {
+	Text string // Unescaped, without surrounding whitespace
+	Line int    // Line of the first character that is not whitespace
+}
+
+// newTemplateLines locates the nodes of doc, parsed from htmlSource, the source of the
+// template at path after directive preprocessing. Preprocessing keeps the line of every
+// directive, so the lines are those of the template as written.
+func newTemplateLines(path, htmlSource string, doc *html.Node) *templateLines {
+	t := &templateLines{
+		file:     filepath.Base(path),
+		elements: make(map[*html.Node]sourceElement),
+		texts:    make(map[*html.Node]int),
+	}
+
+	source, _ := scanSourceElements(htmlSource)
+	parsed := collectParsedElements(doc)
+	toParsed, _ := alignElements(source, parsed)
+	for i, j := range toParsed {
+		if j >= 0 {
+			t.elements[parsed[j].Node] = source[i]
+		}
+	}
+
+	texts := scanSourceTexts(htmlSource)
+	next := 0
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text := strings.TrimSpace(n.Data)
+			for i := next; text != "" && i < len(texts); i++ {
+				if strings.HasPrefix(text, texts[i].Text) {
+					t.texts[n] = texts[i].Line
+					next = i + 1
+					break
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return t
+}
+
+// scanSourceTexts tokenizes src and returns its text tokens that are not only whitespace.
+func scanSourceTexts(src string) []sourceText {
+	var texts []sourceText
+	line := 1
+	z := html.NewTokenizer(strings.NewReader(src))
+	for tt := z.Next(); tt != html.ErrorToken; tt = z.Next() {
+		raw := string(z.Raw())
+		if tt == html.TextToken {
+			if text := strings.TrimSpace(string(z.Text())); text != "" {
+				leading := raw[:len(raw)-len(strings.TrimLeft(raw, " \t\r\n\f"))]
+				texts = append(texts, sourceText{Text: text, Line: line + strings.Count(leading, "\n")})
+			}
+		}
+		line += strings.Count(raw, "\n")
+	}
+	return texts
+}
+
+// line returns the template line n starts on.
+func (t *templateLines) line(n *html.Node) int {
+	for ; n != nil; n = n.Parent {
+		if el, ok := t.elements[n]; ok {
+			return el.Line
+		}
+		if line, ok := t.texts[n]; ok {
+			return line
+		}
+	}
+	return 1
+}
+
+// textLine returns the template line of the text of element n: the line of its first text
+// child that is not only whitespace, or the line of n when it has none.
+func (t *templateLines) textLine(n *html.Node) int {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && strings.TrimSpace(c.Data) != "" {
+			return t.line(c)
+		}
+	}
+	return t.line(n)
+}
+
+// attributes returns the attributes of the start tag of n as written, in the order of
+// n.Attr, and the line of the tag. The attributes are nil when the tag is not in the source
+// or its attribute values differ from the parsed ones.
+func (t *templateLines) attributes(n *html.Node) ([]sourceAttribute, int) {
+	el, ok := t.elements[n]
+	if !ok {
+		return nil, t.line(n)
+	}
+	attrs := writtenAttributes(el)
+	if !sameAttributeValues(attrs, n.Attr) {
+		return nil, el.Line
+	}
+	return attrs, el.Line
+}
+
+// attributeLine returns the template line attribute name (case-insensitive) of n is written
+// on, or the line of n when n has no such attribute in the source.
+func (t *templateLines) attributeLine(n *html.Node, name string) int {
+	if el, ok := t.elements[n]; ok {
+		for _, attr := range writtenAttributes(el) {
+			if strings.EqualFold(attr.Name, name) {
+				return attr.Line
+			}
+		}
+	}
+	return t.line(n)
+}
+
+// writtenAttributes returns the attributes of the start tag of el with their original casing.
+func writtenAttributes(el sourceElement) []sourceAttribute {
+	start := len("<") + len(el.Name)
+	var attrs []sourceAttribute
+	for _, m := range sourceAttributeRegex.FindAllStringSubmatchIndex(el.Raw[start:], -1) {
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return el.Raw[start+m[2*i] : start+m[2*i+1]]
+		}
+		attrs = append(attrs, sourceAttribute{
+			Name:  group(1),
+			Value: html.UnescapeString(group(2) + group(3) + group(4)),
+			Line:  el.Line + strings.Count(el.Raw[:start+m[0]], "\n"),
+		})
+	}
+	return attrs
+}
+
+// directive returns the inline line directive placing the code of n on its template line.
+func (t *templateLines) directive(n *html.Node) string {
+	return fmt.Sprintf("/*line %s:%d*/", t.file, t.line(n))
+}
+
+// withLineDirective puts directive in front of code, and of each of its lines that has no
+// directive of a child node, so a multi-line node does not run past its template line; lines
+// that only close a block are left alone. A fragment keeps its fragmentPrefix, which the
+// callers look for, and gets the directive after it.
+func withLineDirective(code, directive string) string {
+	if code == "" || directive == "" {
+		return code
+	}
+	lines := strings.Split(code, "\n")
+	for i := 1; i < len(lines); i++ {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed != "" && !strings.HasPrefix(trimmed, "}") && !strings.HasPrefix(trimmed, ")") && !strings.HasPrefix(lines[i], "/*line ") {
+			lines[i] = directive + lines[i]
+		}
+	}
+	code = strings.Join(lines, "\n")
+	if rest, ok := strings.CutPrefix(code, fragmentPrefix); ok {
+		return fragmentPrefix + " " + directive + rest
+	}
+	return directive + code
+}
+
+// generatedLineMarker stands where the line directive restoring the positions of the
+// generated file goes, after the Render method; resetGeneratedLines fills it in once the
+// file is formatted and its line numbers are known.
+const generatedLineMarker = "//nojs:generated-lines"
+
+// resetGeneratedLines replaces generatedLineMarker in the formatted source of the file named
+// outFileName with a line directive giving the lines after it back to that file.
+func resetGeneratedLines(source []byte, outFileName string) []byte {
+	lines := strings.Split(string(source), "\n")
+	for i, line := range lines {
+		if line == generatedLineMarker {
+			lines[i] = fmt.Sprintf("//line %s:%d", outFileName, i+2)
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
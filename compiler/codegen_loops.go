@@ -1,16 +1,21 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"go/types"
+	"maps"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
 )
 
-// generateForLoopCode generates Go for...range loop code for list rendering.
-func generateForLoopCode(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions) string {
+// generateForLoopCode generates Go for...range loop code for list rendering. loopCtx is the
+// scope of the enclosing loop, or nil for an outermost loop.
+func generateForLoopCode(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) (string, error) {
 	// Extract loop variables from data attributes
 	indexVar := ""
 	valueVar := ""
@@ -32,34 +37,55 @@ func generateForLoopCode(n *html.Node, receiver string, componentMap map[string]
 
 	// Validate that we have the required attributes
 	if valueVar == "" || rangeExpr == "" || trackByExpr == "" {
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s: Invalid {@for} directive - missing required attributes.\n", currentComp.Path)
-		os.Exit(1)
+		return "", fmt.Errorf("Compilation Error in %s: Invalid {@for} directive - missing required attributes.\n", currentComp.Path)
 	}
 
-	// Validate that the range expression exists on the component
-	propDesc, exists := currentComp.Schema.Props[strings.ToLower(rangeExpr)]
-	if !exists {
-		// Also check state fields
-		propDesc, exists = currentComp.Schema.State[strings.ToLower(rangeExpr)]
+	// Validate that the range expression exists on the component, or on the element of an
+	// enclosing loop (category.Products), and resolve its Go form
+	var rangeGoExpr, rangeType string
+	var rangeTypeInfo types.Type
+	var nilChecks []string
+	var err error
+	if rootName, _, _ := strings.Cut(rangeExpr, "."); loopCtx.scopeOf(rootName) != nil {
+		rangeGoExpr, rangeType, rangeTypeInfo, nilChecks, err = resolveLoopRangeExpression(rangeExpr, currentComp, loopCtx)
+	} else {
+		rangeGoExpr, rangeType, rangeTypeInfo, nilChecks, err = resolveRangeExpression(rangeExpr, receiver, currentComp)
 	}
-	if !exists {
-		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
-		availableFields := strings.Join(allFields, ", ")
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s: Field '%s' not found on component '%s'. Available fields: [%s]\n",
-			currentComp.Path, rangeExpr, currentComp.PascalName, availableFields)
-		os.Exit(1)
+	if err != nil {
+		return "", err
 	}
 
-	// Validate that the field is a slice type
-	if !strings.HasPrefix(propDesc.GoType, "[]") {
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s: Field '%s' must be a slice or array type for {@for} directive, found type '%s'.\n",
-			currentComp.Path, rangeExpr, propDesc.GoType)
-		os.Exit(1)
+	// Validate that the field is a slice or a map type (or a pointer to one)
+	collectionType := strings.TrimPrefix(rangeType, "*")
+	keyType, elementType, isMap := splitMapType(collectionType)
+	if !isMap && !strings.HasPrefix(collectionType, "[]") {
+		return "", fmt.Errorf("Compilation Error in %s: Field '%s' must be a slice, array or map type for {@for} directive, found type '%s'.\n",
+			currentComp.Path, rangeExpr, rangeType)
+	}
+	if isMap && !isOrderedKey(keyType, mapKey(rangeTypeInfo)) {
+		// Entries are rendered in key order, so that re-renders do not shuffle them
+		return "", fmt.Errorf("Compilation Error in %s: Field '%s' has type '%s': {@for} over a map needs string or number keys, which are rendered in sorted order.\n",
+			currentComp.Path, rangeExpr, rangeType)
 	}
+	if strings.HasPrefix(rangeType, "*") {
+		// Pointer to a collection: check the pointer itself, then range over what it points to
+		nilChecks = append(nilChecks, rangeGoExpr)
+		rangeGoExpr = "(*" + rangeGoExpr + ")"
+	}
+	if !isMap {
+		elementType = strings.TrimPrefix(collectionType, "[]")
+	}
+	if strings.HasPrefix(elementType, "*[]") {
+		return "", fmt.Errorf("Compilation Error in %s: Field '%s' has type '%s': {@for} elements cannot be pointers to slices.\n"+
+			"  Use a slice of slices ([][]T) or of pointers to structs ([]*T).\n",
+			currentComp.Path, rangeExpr, rangeType)
+	}
+	pointerElements := strings.HasPrefix(elementType, "*")
+	elementTypeInfo := collectionElement(rangeTypeInfo)
 
 	// Validate trackBy expression
 	// Supports two formats:
-	// 1. Bare variable: "id" (for primitive types like string, int)
+	// 1. Bare variable: "id" (for primitive types like string, int), or the key of a map entry
 	// 2. Dot-notation: "user.ID" (for struct fields)
 	trackByParts := strings.Split(trackByExpr, ".")
 
@@ -69,13 +95,16 @@ func generateForLoopCode(n *html.Node, receiver string, componentMap map[string]
 		// Bare variable format: trackBy id
 		trackByVar = trackByParts[0]
 
-		// Verify the variable matches the loop value variable
-		if trackByVar != valueVar {
-			fmt.Fprintf(os.Stderr, "Compilation Error in %s: trackBy variable '%s' must match the loop value variable '%s'.\n"+
+		// Verify the variable matches the loop value variable, or the key variable of a map
+		if trackByVar != valueVar && !(isMap && trackByVar == indexVar && indexVar != "_") {
+			mapHint := ""
+			if isMap {
+				mapHint = fmt.Sprintf("  For map keys, name the key variable and use it: {@for key, %s := range %s trackBy key}\n", valueVar, rangeExpr)
+			}
+			return "", fmt.Errorf("Compilation Error in %s: trackBy variable '%s' must match the loop value variable '%s'.\n"+
 				"  For bare variables, use: trackBy %s\n"+
-				"  For struct fields, use: trackBy %s.FieldName\n",
-				currentComp.Path, trackByVar, valueVar, valueVar, valueVar)
-			os.Exit(1)
+				"  For struct fields, use: trackBy %s.FieldName\n%s",
+				currentComp.Path, trackByVar, valueVar, valueVar, valueVar, mapHint)
 		}
 	} else if len(trackByParts) >= 2 {
 		// Dot-notation format: trackBy user.ID (or nested: user.Profile.ID)
@@ -84,113 +113,410 @@ func generateForLoopCode(n *html.Node, receiver string, componentMap map[string]
 
 		// Verify the variable matches the loop value variable
 		if trackByVar != valueVar {
-			fmt.Fprintf(os.Stderr, "Compilation Error in %s: trackBy variable '%s' must match the loop value variable '%s'.\n"+
+			return "", fmt.Errorf("Compilation Error in %s: trackBy variable '%s' must match the loop value variable '%s'.\n"+
 				"  For bare variables, use: trackBy %s\n"+
 				"  For struct fields, use: trackBy %s.FieldName\n",
 				currentComp.Path, trackByVar, valueVar, valueVar, valueVar)
-			os.Exit(1)
 		}
 
-		// Extract element type from slice type: "[]User" -> "User"
-		elementType := strings.TrimPrefix(propDesc.GoType, "[]")
-
-		// Validate that the trackBy field exists on the element type
-		// We need to inspect the element type's struct definition
-		goFilePath := filepath.Join(filepath.Dir(currentComp.Path), strings.ToLower(currentComp.PascalName)+".go")
-		elementSchema, err := inspectStructInFile(goFilePath, elementType)
-		if err != nil {
-			// If we can't find the struct in the component file, it might be defined elsewhere
-			// For now, we'll skip validation with a warning
-			fmt.Fprintf(os.Stderr, "Warning in %s: Could not validate trackBy field '%s' on type '%s': %v\n",
-				currentComp.Path, trackByField, elementType, err)
-		} else {
-			// Check if the trackBy field exists on the element type (case-insensitive lookup)
-			// For nested fields, only validate the first part
-			firstField := strings.Split(trackByField, ".")[0]
-			propDescField, exists := elementSchema.Props[strings.ToLower(firstField)]
-			if !exists {
-				availableFields := strings.Join(getAvailableFieldNames(elementSchema.Props), ", ")
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: trackBy identifier '%s' not found on type '%s'.\nAvailable fields: [%s]\n",
-					currentComp.Path, trackByField, elementType, availableFields)
-				os.Exit(1)
-			}
-
-			// Verify exact case match - the field name in the template must match the actual struct field
-			if propDescField.Name != firstField {
-				availableFields := strings.Join(getAvailableFieldNames(elementSchema.Props), ", ")
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: trackBy identifier '%s' not found on type '%s'.\nAvailable fields: [%s]\n",
-					currentComp.Path, trackByField, elementType, availableFields)
-				os.Exit(1)
-			}
+		// Validate that the trackBy field exists on the element type, segment by segment
+		if err := validateTrackByField(trackByField, elementType, elementTypeInfo, currentComp); err != nil {
+			return "", err
 		}
 	} else {
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s: trackBy expression '%s' must be in one of these formats:\n"+
+		return "", fmt.Errorf("Compilation Error in %s: trackBy expression '%s' must be in one of these formats:\n"+
 			"  - Bare variable: trackBy %s (for primitive types)\n"+
 			"  - Struct field: trackBy %s.FieldName (for struct types)\n",
 			currentComp.Path, trackByExpr, valueVar, valueVar)
-		os.Exit(1)
 	}
 
+	// Generated variables are named after the value variable; nested loops add their depth,
+	// so an inner loop reusing the outer variable's name declares distinct variables
+	varPrefix := valueVar
+	if loopCtx != nil {
+		varPrefix = fmt.Sprintf("%s_%d", valueVar, loopCtx.depth()+1)
+	}
+	nodesVar := varPrefix + "_nodes"
+
 	// Generate the loop body - collect child VNodes
 	var code strings.Builder
 
 	// Generate IIFE that returns a slice of VNodes
 	code.WriteString("func() []*vdom.VNode {\n")
-	fmt.Fprintf(&code, "\tvar %s_nodes []*vdom.VNode\n", valueVar)
+	fmt.Fprintf(&code, "\tvar %s []*vdom.VNode\n", nodesVar)
+
+	// A range reached through a nil pointer renders nothing instead of panicking
+	if len(nilChecks) > 0 {
+		conds := make([]string, len(nilChecks))
+		for i, check := range nilChecks {
+			conds[i] = check + " == nil"
+		}
+		fmt.Fprintf(&code, "\tif %s {\n", strings.Join(conds, " || "))
+		if opts.DevMode {
+			code.WriteString("\t\t" + generateNilWarning(rangeExpr, currentComp, opts.Lines.line(n)))
+		}
+		fmt.Fprintf(&code, "\t\treturn %s\n", nodesVar)
+		code.WriteString("\t}\n\n")
+	}
 
 	// Add development warning if enabled
 	if opts.DevMode {
 		code.WriteString("\t// Development warning for empty slice\n")
-		fmt.Fprintf(&code, "\tif len(%s.%s) == 0 {\n", receiver, propDesc.Name)
-		fmt.Fprintf(&code, "\t\tconsole.Warn(\"[@for] Rendering empty list for '%s' in %s. Consider using {@if} to handle empty state.\")\n",
-			propDesc.Name, currentComp.PascalName)
+		fmt.Fprintf(&code, "\tif len(%s) == 0 {\n", rangeGoExpr)
+		fmt.Fprintf(&code, "\t\t%s\n", generateDevWarning(fmt.Sprintf(
+			"[@for] Rendering empty list for '%s' in %s. Consider using {@if} to handle empty state.", rangeExpr, currentComp.PascalName)))
 		code.WriteString("\t}\n\n")
 	}
 
-	// Generate the for loop
-	fmt.Fprintf(&code, "\tfor %s, %s := range %s.%s {\n", indexVar, valueVar, receiver, propDesc.Name)
+	// Generate the for loop. The dev warning for a nil element names its index, so the
+	// index is bound even when the template discards it.
+	loopIndex := indexVar
+	indexType, position := "int", "index"
+	if isMap {
+		// Maps are iterated in key order: Go randomizes map order, which would reorder the
+		// rendered entries on every render
+		if indexVar == "_" {
+			loopIndex = varPrefix + "_key"
+		}
+		indexType, position = keyType, "key"
+		opts.Imports["maps"], opts.Imports["slices"] = "maps", "slices"
+		fmt.Fprintf(&code, "\tfor _, %s := range slices.Sorted(maps.Keys(%s)) {\n", loopIndex, rangeGoExpr)
+		if valueVar != "_" {
+			fmt.Fprintf(&code, "\t\t%s := %s[%s]\n", valueVar, rangeGoExpr, loopIndex)
+		}
+	} else {
+		if pointerElements && opts.DevMode && indexVar == "_" {
+			loopIndex = varPrefix + "_index"
+		}
+		fmt.Fprintf(&code, "\tfor %s, %s := range %s {\n", loopIndex, valueVar, rangeGoExpr)
+	}
+
+	// Skip nil elements of a pointer slice or map, so bindings and trackBy keys never dereference nil
+	if pointerElements {
+		fmt.Fprintf(&code, "\t\tif %s == nil {\n", valueVar)
+		if opts.DevMode {
+			fmt.Fprintf(&code, "\t\t\tconsole.Warn(fmt.Sprint(%s, %s, %s))\n", strconv.Quote("[@for] Skipping nil element at "+position+" "),
+				loopIndex, strconv.Quote(fmt.Sprintf(" of '%s' in %s.", rangeExpr, currentComp.PascalName)))
+		}
+		code.WriteString("\t\t\tcontinue\n")
+		code.WriteString("\t\t}\n")
+	}
 
-	// Create loop context for child nodes
-	loopCtx := &loopContext{
-		IndexVar: indexVar,
-		ValueVar: valueVar,
+	// Create loop context for child nodes, inside the scope of the enclosing loop
+	bodyCtx := &loopContext{
+		IndexVar:    indexVar,
+		IndexType:   indexType,
+		ValueVar:    valueVar,
+		ElementType: elementType,
+		Element:     elementTypeInfo,
+		TrackBy:     trackByExpr,
+		Occurrences: make(map[string]int),
+		Parent:      loopCtx,
 	}
 
 	// Generate code for each child node in the loop body
-	// Use a counter to ensure unique variable names for each child element
-	childCounter := 0
+	var childCodes []string
 	for c := n.FirstChild; c != nil; c = c.NextSibling {
 		if c.Type == html.ElementNode || (c.Type == html.TextNode && strings.TrimSpace(c.Data) != "") {
-			childCode := generateNodeCode(c, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
-			if childCode != "" {
-				childVarName := fmt.Sprintf("%s_child_%d", valueVar, childCounter)
-				fmt.Fprintf(&code, "\t\t%s := %s\n", childVarName, childCode)
-				fmt.Fprintf(&code, "\t\tif %s != nil {\n", childVarName)
-				fmt.Fprintf(&code, "\t\t\t%s_nodes = append(%s_nodes, %s)\n", valueVar, valueVar, childVarName)
-				code.WriteString("\t\t}\n")
-				childCounter++
+			if childCode := generateNodeCode(c, receiver, componentMap, currentComp, htmlSource, opts, bodyCtx); childCode != "" {
+				childCodes = append(childCodes, childCode)
+			}
+		}
+	}
+
+	// An iteration rendering a single node keys it with its trackBy value, so the patcher
+	// moves the item's DOM node when the list is reordered instead of re-patching the nodes
+	// that follow an insertion
+	keyed := len(childCodes) == 1 && !strings.HasPrefix(childCodes[0], fragmentPrefix)
+
+	// Use a counter to ensure unique variable names for each child element
+	childCounter := 0
+	for _, childCode := range childCodes {
+		if strings.HasPrefix(childCode, fragmentPrefix) {
+			// Nested loops, conditionals and multi-node switches yield a slice; spread it
+			fmt.Fprintf(&code, "\t\t%s = append(%s, %s...)\n", nodesVar, nodesVar, childCode)
+			continue
+		}
+		childVarName := fmt.Sprintf("%s_child_%d", varPrefix, childCounter)
+		fmt.Fprintf(&code, "\t\t%s := %s\n", childVarName, childCode)
+		fmt.Fprintf(&code, "\t\tif %s != nil {\n", childVarName)
+		if keyed {
+			fmt.Fprintf(&code, "\t\t\t%s.Key = %s\n", childVarName, trackByExpr)
+		}
+		fmt.Fprintf(&code, "\t\t\t%s = append(%s, %s)\n", nodesVar, nodesVar, childVarName)
+		code.WriteString("\t\t}\n")
+		childCounter++
+	}
+
+	// Let authors know when keys were disambiguated for repeated components
+	if opts.DevMode {
+		for _, compName := range slices.Sorted(maps.Keys(bodyCtx.Occurrences)) {
+			if count := bodyCtx.Occurrences[compName]; count > 1 {
+				fmt.Fprintf(warningOutput, "Note in %s: <%s> is used %d times per {@for %s} iteration; keys were disambiguated as %s_<trackBy>_<n>.\n",
+					currentComp.Path, compName, count, valueVar, compName)
 			}
 		}
 	}
 
 	code.WriteString("\t}\n")
-	fmt.Fprintf(&code, "\treturn %s_nodes\n", valueVar)
+	fmt.Fprintf(&code, "\treturn %s\n", nodesVar)
 	code.WriteString("}()")
 
-	return code.String()
+	return code.String(), nil
 }
 
-// extractTrackByFromParent walks up the node tree to find a go-for parent and extracts its trackBy expression.
-func extractTrackByFromParent(n *html.Node) string {
-	for p := n.Parent; p != nil; p = p.Parent {
-		if p.Type == html.ElementNode && p.Data == "go-for" {
-			// Found the parent loop node, extract trackBy
-			for _, attr := range p.Attr {
-				if attr.Key == "data-trackby" {
-					return attr.Val
-				}
+// resolveRangeExpression validates the {@for} range expression and returns its Go expression,
+// its type, written and type-checked (nil when unknown), and the nil checks needed to
+// evaluate it. The expression is a prop or state field (Items) or a nested field
+// (User.Orders); nested fields reached through pointers yield a nil check per pointer.
+func resolveRangeExpression(rangeExpr, receiver string, currentComp componentInfo) (string, string, types.Type, []string, error) {
+	rootName, _, isNested := strings.Cut(rangeExpr, ".")
+	propDesc, exists := currentComp.Schema.Props[strings.ToLower(rootName)]
+	if !exists {
+		// Also check state fields
+		propDesc, exists = currentComp.Schema.State[strings.ToLower(rootName)]
+	}
+	if !exists {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		availableFields := strings.Join(allFields, ", ")
+		return "", "", nil, nil, fmt.Errorf("Compilation Error in %s: Field '%s' not found on component '%s'. Available fields: [%s]\n",
+			currentComp.Path, rangeExpr, currentComp.PascalName, availableFields)
+	}
+	if !isNested {
+		return fmt.Sprintf("%s.%s", receiver, propDesc.Name), propDesc.GoType, propDesc.Type, nil, nil
+	}
+	if rootType := fieldRootType(rootName, currentComp); rootType != nil {
+		fieldType, pointerPaths, err := resolveTypedFieldPath(strings.Split(rangeExpr, "."), rootType, currentComp.Qualifier)
+		if err != nil {
+			return "", "", nil, nil, unresolvableRangeError(rangeExpr, currentComp, err)
+		}
+		return fmt.Sprintf("%s.%s", receiver, rangeExpr), typeString(fieldType, currentComp.Qualifier), fieldType, pointerNilChecks(receiver, pointerPaths), nil
+	}
+
+	fieldType, pointerPaths, err := resolveNestedFieldPath(rangeExpr, currentComp, filepath.Dir(currentComp.Path))
+	if err != nil {
+		return "", "", nil, nil, unresolvableRangeError(rangeExpr, currentComp, err)
+	}
+	return fmt.Sprintf("%s.%s", receiver, rangeExpr), fieldType, nil, pointerNilChecks(receiver, pointerPaths), nil
+}
+
+// unresolvableRangeError is the compile error of a nested range expression (Cart.Items) whose
+// path does not resolve, listing the fields available where it went wrong.
+func unresolvableRangeError(rangeExpr string, currentComp componentInfo, err error) error {
+	msg := fmt.Sprintf("Compilation Error in %s: Field '%s' not resolvable on component '%s'. %v\n",
+		currentComp.Path, rangeExpr, currentComp.PascalName, err)
+	if nestedFields := getAvailableNestedFields(rangeExpr, currentComp, filepath.Dir(currentComp.Path)); len(nestedFields) > 0 {
+		msg += fmt.Sprintf("Available fields on %s: [%s]\n", strings.SplitN(rangeExpr, ".", 2)[0], strings.Join(nestedFields, ", "))
+	}
+	return errors.New(msg)
+}
+
+// resolveLoopRangeExpression is resolveRangeExpression for a range over the value variable
+// of an enclosing loop (rows of a [][]T) or over a field of it (category.Products), resolved
+// against the element type of that loop.
+func resolveLoopRangeExpression(rangeExpr string, currentComp componentInfo, loopCtx *loopContext) (string, string, types.Type, []string, error) {
+	parts := strings.Split(rangeExpr, ".")
+	scope := loopCtx.valueScope(parts[0])
+	if scope == nil {
+		return "", "", nil, nil, fmt.Errorf("Compilation Error in %s: Field '%s' must be a slice or array type for {@for} directive, found the loop index '%s'.\n",
+			currentComp.Path, rangeExpr, parts[0])
+	}
+	if len(parts) == 1 {
+		return rangeExpr, scope.ElementType, scope.Element, nil, nil
+	}
+	if scope.Element != nil && currentComp.Qualifier != nil {
+		fieldType, pointerPaths, err := resolveTypedFieldPath(parts, scope.Element, currentComp.Qualifier)
+		if err != nil {
+			return "", "", nil, nil, fmt.Errorf("Compilation Error in %s: Field '%s' not resolvable on loop variable '%s' of type '%s'. %v\n",
+				currentComp.Path, rangeExpr, scope.ValueVar, scope.ElementType, err)
+		}
+		// Nil elements of the enclosing loop are skipped, so the loop variable itself needs no check
+		var nilChecks []string
+		for _, path := range pointerPaths {
+			if path != scope.ValueVar {
+				nilChecks = append(nilChecks, path)
 			}
 		}
+		return rangeExpr, typeString(fieldType, currentComp.Qualifier), fieldType, nilChecks, nil
+	}
+
+	// Without type information only a direct field of the element can be resolved
+	fieldType, err := resolveLoopFieldType(parts[1], currentComp, scope)
+	if err == nil && len(parts) > 2 {
+		err = fmt.Errorf("nested field paths need the type-checked element type '%s'", scope.ElementType)
+	}
+	if err != nil {
+		return "", "", nil, nil, fmt.Errorf("Compilation Error in %s: Field '%s' not resolvable on loop variable '%s'. %v\n",
+			currentComp.Path, rangeExpr, scope.ValueVar, err)
+	}
+	return rangeExpr, fieldType, nil, nil, nil
+}
+
+// collectionElement returns the element type of the slice or map t, or of the slice or map
+// t points to, or nil.
+func collectionElement(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	switch collection := types.Unalias(derefType(t)).Underlying().(type) {
+	case *types.Slice:
+		return collection.Elem()
+	case *types.Map:
+		return collection.Elem()
+	}
+	return nil
+}
+
+// mapKey returns the key type of the map t, or of the map t points to, or nil.
+func mapKey(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	if m, ok := types.Unalias(derefType(t)).Underlying().(*types.Map); ok {
+		return m.Key()
+	}
+	return nil
+}
+
+// splitMapType splits a map type as written, e.g. "map[string]Product", into its key and
+// element types. It reports false for any other type.
+func splitMapType(goType string) (key, elem string, ok bool) {
+	rest, ok := strings.CutPrefix(goType, "map[")
+	if !ok {
+		return "", "", false
+	}
+	depth := 1
+	for i, r := range rest {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				return rest[:i], rest[i+1:], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// isOrderedKey reports whether map keys of type key, written and type-checked (nil when
+// unknown), can be sorted with the < operator.
+func isOrderedKey(key string, keyInfo types.Type) bool {
+	if keyInfo != nil {
+		basic, ok := keyInfo.Underlying().(*types.Basic)
+		return ok && basic.Info()&types.IsOrdered != 0
+	}
+	return isBuiltinType(key) && key != "bool"
+}
+
+// validateTrackByField checks that field, the dotted path after the loop variable in a
+// trackBy (ID, or Profile.ID), names a field at each segment, starting on the loop's
+// element type. The type-checked element type is walked when there is one; otherwise the
+// struct declarations are looked up in the files of the component's package, and in the
+// package of each qualified type on the way (models.User). A missing field is an error
+// listing the fields of the type it was looked up on; a type that cannot be found at all
+// only skips the check, with a warning.
+func validateTrackByField(field, elementType string, elementTypeInfo types.Type, currentComp componentInfo) error {
+	segments := strings.Split(field, ".")
+	notFound := func(typeName string, available []string) error {
+		return fmt.Errorf("Compilation Error in %s: trackBy identifier '%s' not found on type '%s'.\nAvailable fields: [%s]\n",
+			currentComp.Path, field, typeName, strings.Join(available, ", "))
+	}
+
+	if elementTypeInfo != nil {
+		t := elementTypeInfo
+		for _, segment := range segments {
+			t = derefType(t)
+			found := lookupField(t, segment)
+			if found == nil {
+				return notFound(typeString(t, currentComp.Qualifier), exportedFields(t))
+			}
+			t = found.Type()
+		}
+		return nil
+	}
+
+	dir := filepath.Dir(currentComp.Path)
+	currentType := elementType
+	for _, segment := range segments {
+		currentType = strings.TrimPrefix(currentType, "*")
+		if isBuiltinType(currentType) || strings.HasPrefix(currentType, "[]") || strings.HasPrefix(currentType, "map[") {
+			return fmt.Errorf("Compilation Error in %s: trackBy identifier '%s': cannot access field '%s' on type '%s'.\n",
+				currentComp.Path, field, segment, currentType)
+		}
+		// A qualified type is declared in its own package, whose imports name the types of
+		// its fields; an unqualified one in the package of the type before it
+		structName := currentType
+		if alias, name, qualified := strings.Cut(currentType, "."); qualified {
+			packagePath, _ := resolvePackageFromAlias(alias, dir)
+			pkgDir := ""
+			if packagePath != "" {
+				pkgDir = findPackageDir(packagePath)
+			}
+			if pkgDir == "" {
+				fmt.Fprintf(warningOutput, "Warning in %s: Could not validate trackBy field '%s' on type '%s': package '%s' not found\n",
+					currentComp.Path, field, currentType, alias)
+				return nil
+			}
+			dir, structName = pkgDir, name
+		}
+		available, err := getStructFields(dir, structName)
+		if err != nil {
+			fmt.Fprintf(warningOutput, "Warning in %s: Could not validate trackBy field '%s' on type '%s': %v\n",
+				currentComp.Path, field, currentType, err)
+			return nil
+		}
+		if !slices.Contains(available, segment) {
+			return notFound(currentType, available)
+		}
+		fieldType, err := findStructFieldTypeInDir(dir, structName, segment)
+		if err != nil {
+			return notFound(currentType, available)
+		}
+		currentType = fieldType
+	}
+	return nil
+}
+
+// derefType returns the type t points to, or t itself when it is not a pointer.
+func derefType(t types.Type) types.Type {
+	if pointer, ok := types.Unalias(t).(*types.Pointer); ok {
+		return pointer.Elem()
+	}
+	return t
+}
+
+// componentKey returns the Go string expression keying the occurrence-th <name> component
+// of a loop iteration: the trackBy values of the loop and of every enclosing loop, so that
+// items sharing an ID under different outer items never share a cached instance.
+func (l *loopContext) componentKey(name string, occurrence int) string {
+	var trackBys []string
+	for scope := l; scope != nil; scope = scope.Parent {
+		trackBys = append([]string{fmt.Sprintf("fmt.Sprint(%s)", scope.TrackBy)}, trackBys...)
+	}
+	return fmt.Sprintf("%s + %s + %s", strconv.Quote(name+"_"), strings.Join(trackBys, ` + "_" + `), strconv.Quote(fmt.Sprintf("_%d", occurrence)))
+}
+
+// resolveLoopFieldType returns the Go type of fieldName on the element type of the current
+// loop of loopCtx (e.g., the type of post.Status when ranging over []Post).
+func resolveLoopFieldType(fieldName string, currentComp componentInfo, loopCtx *loopContext) (string, error) {
+	if loopCtx.Element != nil && currentComp.Qualifier != nil {
+		field := lookupField(loopCtx.Element, fieldName)
+		if field == nil {
+			availableFields := strings.Join(exportedFields(derefType(loopCtx.Element)), ", ")
+			return "", fmt.Errorf("field '%s' not found on type '%s'. Available fields: [%s]", fieldName, loopCtx.ElementType, availableFields)
+		}
+		return typeString(field.Type(), currentComp.Qualifier), nil
+	}
+	goFilePath := filepath.Join(filepath.Dir(currentComp.Path), strings.ToLower(currentComp.PascalName)+".go")
+	elementSchema, err := inspectStructInFile(goFilePath, strings.TrimPrefix(loopCtx.ElementType, "*"))
+	if err != nil {
+		return "", fmt.Errorf("could not resolve type '%s': %v", loopCtx.ElementType, err)
+	}
+	propDesc, exists := elementSchema.Props[strings.ToLower(fieldName)]
+	if !exists || propDesc.Name != fieldName {
+		availableFields := strings.Join(getAvailableFieldNames(elementSchema.Props), ", ")
+		return "", fmt.Errorf("field '%s' not found on type '%s'. Available fields: [%s]", fieldName, loopCtx.ElementType, availableFields)
 	}
-	return ""
+	return propDesc.GoType, nil
 }
@@ -0,0 +1,116 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// memoMarker in the doc comment of a component's struct makes the compiler generate a
+// PropsEqual method, which turns the component into a runtime.Memoized one:
+//
+//	// PriceTable renders thousands of rows.
+//	//nojs:memo
+//	type PriceTable struct { ... }
+const memoMarker = "//nojs:memo"
+
+// hasMemoMarker reports whether doc holds a //nojs:memo line.
+func hasMemoMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == memoMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// generatePropsEqualMethod generates the PropsEqual method of a component marked
+// //nojs:memo, or "" for other components. A prop whose type cannot be compared makes the
+// method report false every time; its name is returned as uncomparable.
+func generatePropsEqualMethod(comp componentInfo) (method, uncomparable string) {
+	if !comp.Schema.Memo {
+		return "", ""
+	}
+
+	props := make([]propertyDescriptor, 0, len(comp.Schema.Props)+1+len(comp.Schema.Slots))
+	for _, prop := range comp.Schema.Props {
+		props = append(props, prop)
+	}
+	sort.Slice(props, func(i, j int) bool { return props[i].Name < props[j].Name })
+	props = append(props, comp.Schema.slotFields()...)
+
+	var comparisons []string
+	for _, prop := range props {
+		comparison, ok := propComparison(prop)
+		if !ok {
+			uncomparable = prop.Name
+			comparisons = nil
+			break
+		}
+		if comparison != "" {
+			comparisons = append(comparisons, comparison)
+		}
+	}
+
+	body := "\treturn " + strings.Join(comparisons, " &&\n\t\t")
+	switch {
+	case uncomparable != "":
+		body = fmt.Sprintf("\t// %s cannot be compared, so the component renders every time\n\treturn false", uncomparable)
+	case len(comparisons) == 0:
+		body = "\treturn true"
+	}
+	return fmt.Sprintf(`
+// PropsEqual reports whether other carries the props %[1]s last rendered with, so
+// RenderChild can reuse its last render (see runtime.Memoized).
+// This method is generated automatically by the compiler.
+func (c *%[1]s) PropsEqual(other runtime.Component) bool {
+	o, ok := other.(*%[1]s)
+	if !ok {
+		return false
+	}
+	_ = o // Suppress unused variable warning if no props to compare
+%[2]s
+}
+`, comp.PascalName, body), uncomparable
+}
+
+// propComparison returns the expression comparing prop between the receiver c and o, or ""
+// for a func prop: callbacks are applied without a render. It reports false when the
+// prop's type cannot be compared. Interfaces count as such, since == panics on some
+// dynamic types.
+func propComparison(prop propertyDescriptor) (string, bool) {
+	equal := fmt.Sprintf("c.%[1]s == o.%[1]s", prop.Name)
+	sameSlice := fmt.Sprintf("runtime.SameSlice(c.%[1]s, o.%[1]s)", prop.Name)
+
+	if prop.Type == nil {
+		// Without type information only the shape of the declared type is known
+		switch {
+		case strings.HasPrefix(prop.GoType, "[]"):
+			return sameSlice, true
+		case strings.HasPrefix(prop.GoType, "func("):
+			return "", true
+		case strings.HasPrefix(prop.GoType, "*"), isBuiltinType(prop.GoType):
+			return equal, true
+		}
+		return "", false
+	}
+
+	switch t := prop.Type.Underlying().(type) {
+	case *types.Signature:
+		return "", true
+	case *types.Slice:
+		return sameSlice, true
+	case *types.Interface:
+		return "", false
+	default:
+		if types.Comparable(t) {
+			return equal, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,41 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ForgeLogic/nojs/events"
+)
+
+// generateEventModifiers wraps adapted, the adapter expression of an event attribute written
+// as @attribute (onsubmit.prevent), in events.Modify with the modifiers in modifierList:
+//
+//	@onsubmit.prevent="Save"     -> events.Modify(events.AdaptFormEvent(c.Save), events.Prevent)
+//	@onkeydown.enter="Submit"    -> events.Modify(events.AdaptKeyboardEvent(c.Submit), events.Enter)
+//
+// Unknown modifiers, modifiers listed twice and key modifiers on events without a key are
+// compile errors.
+func generateEventModifiers(adapted, attribute, modifierList string, eventSig *events.EventSignature, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	fail := func(format string, a ...any) error {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), contextLines)
+	}
+
+	args := []string{adapted}
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(modifierList, ".") {
+		modifier, ok := events.ModifierNamed(name)
+		if !ok {
+			return "", fail("Unknown event modifier '.%s' in @%s. Supported modifiers: .%s", name, attribute, strings.Join(events.ModifierNames(), ", ."))
+		}
+		if seen[name] {
+			return "", fail("Event modifier '.%s' is listed more than once in @%s.", name, attribute)
+		}
+		seen[name] = true
+		if modifier.Key() != "" && eventSig.ArgsType != "events.KeyboardEventArgs" {
+			return "", fail("Key modifier '.%s' only applies to keyboard events (@onkeydown, @onkeyup, @onkeypress), not @%s.", name, eventSig.EventName)
+		}
+		args = append(args, "events."+strings.ToUpper(name[:1])+name[1:])
+	}
+	return fmt.Sprintf("events.Modify(%s)", strings.Join(args, ", ")), nil
+}
@@ -0,0 +1,77 @@
+package compiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// generateNilSafeExpression wraps expr in an IIFE that returns zero instead of dereferencing
+// a nil pointer. nilChecks are the Go expressions of the pointers expr reads through (e.g.,
+// "c.User"); without any, expr is returned unchanged. In dev mode the first nil encountered
+// logs a warning naming the binding and its template line.
+func generateNilSafeExpression(expr string, nilChecks []string, zero string, binding string, currentComp componentInfo, lineNumber int, opts compileOptions) string {
+	if len(nilChecks) == 0 {
+		return expr
+	}
+
+	conds := make([]string, len(nilChecks))
+	for i, check := range nilChecks {
+		conds[i] = check + " == nil"
+	}
+
+	var code strings.Builder
+	code.WriteString("func() any {\n")
+	fmt.Fprintf(&code, "if %s {\n", strings.Join(conds, " || "))
+	if opts.DevMode {
+		code.WriteString(generateNilWarning(binding, currentComp, lineNumber))
+	}
+	fmt.Fprintf(&code, "return %s\n", zero)
+	code.WriteString("}\n")
+	fmt.Fprintf(&code, "return %s\n", expr)
+	code.WriteString("}()")
+	return code.String()
+}
+
+// generateNilWarning returns the dev-mode statement reporting a binding that hit a nil pointer.
+// The warning is logged once per binding site, not on every render.
+func generateNilWarning(binding string, currentComp componentInfo, lineNumber int) string {
+	site := fmt.Sprintf("%s:%d", filepath.Base(currentComp.Path), lineNumber)
+	message := fmt.Sprintf("[Binding] '%s' in %s reads through a nil pointer; rendering the zero value until it is set.", binding, site)
+	return fmt.Sprintf("console.WarnOnce(%s, %s)\n", strconv.Quote(site+":"+binding), strconv.Quote(message))
+}
+
+// pointerNilChecks turns the pointer prefixes reported by resolveNestedFieldPath into Go
+// expressions on receiver (e.g., ["User"] -> ["c.User"]).
+func pointerNilChecks(receiver string, pointerPaths []string) []string {
+	checks := make([]string, len(pointerPaths))
+	for i, path := range pointerPaths {
+		checks[i] = fmt.Sprintf("%s.%s", receiver, path)
+	}
+	return checks
+}
+
+// zeroValueLiteral returns the Go literal for the zero value of a built-in type, used when an
+// attribute binding reads through a nil pointer. Other types fall back to an empty string.
+func zeroValueLiteral(goType string) string {
+	switch {
+	case goType == "bool":
+		return "false"
+	case goType == "string" || !isBuiltinType(goType):
+		return `""`
+	default:
+		return "0"
+	}
+}
+
+// typedZeroLiteral is like zeroValueLiteral but keeps numeric zeros typed (e.g., float64(0)),
+// so a text binding formatted with a numeric verb renders "0" instead of a fmt type error
+// when it reads through a nil pointer.
+func typedZeroLiteral(goType string) string {
+	zero := zeroValueLiteral(goType)
+	if zero == "0" {
+		return fmt.Sprintf("%s(0)", goType)
+	}
+	return zero
+}
@@ -1,8 +1,8 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -11,20 +11,48 @@ import (
 )
 
 // generateNodeCode recursively generates Go vdom calls.
-// loopCtx can be nil if not inside a loop.
+// loopCtx can be nil if not inside a loop. A node with an error generates nothing: the error
+// is recorded in opts.Errors and the generation goes on with its siblings. The code of each
+// node starts with a line directive pointing at the node in the template.
 func generateNodeCode(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) string {
+	// Found before the children, which come after n in the template
+	directive := opts.Lines.directive(n)
+	code, err := generateNode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+	if err != nil {
+		opts.Errors.add(err)
+		return ""
+	}
+	return withLineDirective(code, directive)
+}
+
+// generateNode is generateNodeCode, returning the error of n itself. Errors in the children
+// of n are recorded by the generateNodeCode of each child.
+func generateNode(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) (string, error) {
+	// HTML comments are not rendered
+	if n.Type == html.CommentNode {
+		return "", nil
+	}
+
 	if n.Type == html.TextNode {
 		content := strings.TrimSpace(n.Data)
 		if content == "" {
-			return ""
+			return "", nil
+		}
+
+		// CSS and script source is raw text: no binding processing
+		if isRawTextElement(n.Parent) {
+			return fmt.Sprintf("vdom.Text(%s)", strconv.Quote(n.Data)), nil
 		}
 
 		// Generate the text expression (handles data binding, ternaries, static text, etc.)
-		lineNum := estimateTextNodeLineNumber(htmlSource, n.Data)
-		textExpr := generateTextExpression(content, receiver, currentComp, htmlSource, lineNum, loopCtx)
+		lineNum := opts.Lines.line(n)
+		textExpr, err := generateTextExpression(content, receiver, currentComp, htmlSource, lineNum, opts, loopCtx)
+		if err != nil {
+			return "", err
+		}
 
 		// Wrap in vdom.Text() call to create a proper text VNode
-		return fmt.Sprintf("vdom.Text(%s)", textExpr)
+		return fmt.Sprintf("vdom.Text(%s)", textExpr), nil
 	}
 
 	if n.Type == html.ElementNode {
@@ -32,67 +60,118 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 
 		// 0. Handle conditional placeholder nodes
 		if tagName == "go-conditional" {
-			return generateConditionalCode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+			return generateConditionalCode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx), nil
 		}
 		if tagName == "go-if" || tagName == "go-elseif" || tagName == "go-else" {
 			// These are handled within go-conditional processing
-			return ""
+			return "", nil
+		}
+
+		// 0.25. Handle switch placeholder nodes
+		if tagName == "go-switch" {
+			return generateSwitchCode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+		}
+		if tagName == "go-case" {
+			// These are handled within go-switch processing
+			return "", nil
+		}
+
+		// 0.4. Handle slot fallback placeholder nodes
+		if tagName == "go-slot" {
+			return generateSlotFallbackCode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
 		}
 
 		// 0.5. Handle for-loop placeholder nodes
 		if tagName == "go-for" {
-			return generateForLoopCode(n, receiver, componentMap, currentComp, htmlSource, opts)
+			return generateForLoopCode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
 		}
 
 		// 1. Handle Custom Components
 		if compInfo, isComponent := componentMap[tagName]; isComponent {
-			propsStr := generateStructLiteral(n, compInfo, receiver, componentMap, currentComp, htmlSource, currentComp.Path, opts, loopCtx)
+			propsStr, err := generateStructLiteral(n, compInfo, receiver, componentMap, currentComp, htmlSource, currentComp.Path, opts, loopCtx)
+			if err != nil {
+				return "", err
+			}
 
-			// Generate key: if inside a loop, include trackBy value for uniqueness
+			// Generate key, a Go string expression: if inside a loop, include trackBy value for uniqueness
 			var key string
 			if loopCtx != nil {
-				// Inside a loop: use trackBy expression to ensure unique keys
-				// Extract trackBy from the parent go-for node
-				trackByExpr := extractTrackByFromParent(n)
-				if trackByExpr != "" {
-					// Use the trackBy value in the key
-					key = fmt.Sprintf(`%s_" + fmt.Sprintf("%%v", %s) + "`, compInfo.PascalName, trackByExpr)
-				} else {
-					// Fallback: use a template-wide counter so keys are unique across the whole template
-					count := opts.ComponentCounter[compInfo.PascalName]
-					opts.ComponentCounter[compInfo.PascalName]++
-					key = fmt.Sprintf("%s_%d", compInfo.PascalName, count)
-				}
+				// Inside a loop: use the trackBy values in the key, plus the component's occurrence
+				// index within the iteration so two <UserCard> in one loop body never share a
+				// cached instance
+				occurrence := loopCtx.Occurrences[compInfo.PascalName]
+				loopCtx.Occurrences[compInfo.PascalName]++
+				key = loopCtx.componentKey(compInfo.PascalName, occurrence)
 			} else {
 				// Not in a loop: use a template-wide counter so keys are unique across the whole template
 				// (sibling-position would give the same key to components at the same depth in different
 				// parent containers, e.g. multiple RouterLinks each at position 3 all become RouterLink_3)
 				count := opts.ComponentCounter[compInfo.PascalName]
 				opts.ComponentCounter[compInfo.PascalName]++
-				key = fmt.Sprintf("%s_%d", compInfo.PascalName, count)
+				key = strconv.Quote(fmt.Sprintf("%s_%d", compInfo.PascalName, count))
 			}
 
 			// Determine if we need a qualified name (cross-package reference)
 			var componentRef string
-			if compInfo.PackageName != currentComp.PackageName {
-				// Cross-package: use qualified name
-				componentRef = fmt.Sprintf("%s.%s", compInfo.PackageName, compInfo.PascalName)
+			if alias, crossPackage := opts.PackageAliases[compInfo.ImportPath]; crossPackage {
+				// Cross-package: use qualified name, with the name the package is imported under
+				componentRef = fmt.Sprintf("%s.%s", alias, compInfo.PascalName)
 			} else {
 				// Same package: use unqualified name
 				componentRef = compInfo.PascalName
 			}
 
-			return fmt.Sprintf(`r.RenderChild("%s", &%s%s)`, key, componentRef, propsStr)
+			return fmt.Sprintf(`r.RenderChild(%s, &%s%s)`, key, componentRef, propsStr), nil
 		}
 
 		// 1.5. Check if this is a PascalCase tag that looks like a component but wasn't found
 		// Note: The HTML parser lowercases tag names, so we need to find the original casing in htmlSource
 		originalTagName := findOriginalTagName(n, tagName, htmlSource)
 		if isComponentTag(originalTagName) {
-			lineNumber := estimateLineNumber(htmlSource, fmt.Sprintf("<%s", originalTagName))
-			errorMsg := generateMissingComponentError(originalTagName, componentMap, currentComp, htmlSource, currentComp.Path, lineNumber)
-			fmt.Fprint(os.Stderr, errorMsg)
-			os.Exit(1)
+			return "", errors.New(generateMissingComponentError(originalTagName, componentMap, currentComp, htmlSource, currentComp.Path, opts.Lines.line(n)))
+		}
+
+		// 1.7. Transitions: generate the element without the transition attributes and wrap it
+		if name, timeout, ok := takeTransitionAttributes(n); ok {
+			args, err := generateTransitionArgs(name, timeout, n, currentComp, htmlSource, opts.Lines.attributeLine(n, "transition"))
+			if err != nil {
+				return "", err
+			}
+			elementCode := generateNodeCode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+			return fmt.Sprintf("vdom.WithTransition(%s, %s)", elementCode, args), nil
+		}
+
+		// 1.75. Bind element refs: generate the element without the ref attribute and wrap it
+		if refAttr, ok := takeRefAttribute(n); ok {
+			refExpr, err := generateRefExpression(refAttr, n, receiver, currentComp, htmlSource, opts.Lines.attributeLine(n, "ref"), loopCtx)
+			if err != nil {
+				return "", err
+			}
+			elementCode := generateNodeCode(n, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+			return fmt.Sprintf("vdom.WithRef(%s, %s)", elementCode, refExpr), nil
+		}
+
+		// 1.8. Raw markup: the element is generated without the directive and with the bound
+		// field as its innerHTML
+		if value, ok := takeInnerHTMLAttribute(n); ok {
+			htmlExpr, err := generateInnerHTMLExpression(value, n, receiver, currentComp, htmlSource, opts.Lines.attributeLine(n, innerHTMLAttribute))
+			if err != nil {
+				return "", err
+			}
+			attrsMapStr := generateAttributesMap(n, receiver, currentComp, htmlSource, opts, loopCtx)
+			return fmt.Sprintf("vdom.RawHTML(%s, %s, %s)", strconv.Quote(tagName), attrsMapStr, htmlExpr), nil
+		}
+
+		// 1.9. Raw text elements: the <style>/<script> body is passed through verbatim as content
+		if isRawTextElement(n) {
+			var rawText strings.Builder
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.TextNode {
+					rawText.WriteString(c.Data)
+				}
+			}
+			attrsMapStr := generateAttributesMap(n, receiver, currentComp, htmlSource, opts, loopCtx)
+			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, strconv.Quote(rawText.String())), nil
 		}
 
 		// 2. Handle Standard HTML Elements
@@ -110,19 +189,19 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 				if matches := dataBindingRegex.FindStringSubmatch(trimmed); len(matches) > 0 {
 					fieldName := matches[1]
 
-					// Check if this references the slot field
-					if currentComp.Schema.Slot != nil && strings.ToLower(fieldName) == currentComp.Schema.Slot.LowercaseName {
+					// Check if this references a slot field, the default one or a named one
+					if slot, isSlot := currentComp.Schema.slotField(strings.ToLower(fieldName)); isSlot {
 						// This is a slot spread
 						hasSlotSpread = true
 
 						// Generate dev warning if enabled
 						if opts.DevMode {
-							warningCode := fmt.Sprintf("func() []*vdom.VNode {\nif len(%s.%s) == 0 {\nconsole.Warn(\"[Slot] Rendering empty content slot '%s' in component '%s'. Parent provided no content.\")\n}\nreturn %s.%s\n}()...",
-								receiver, currentComp.Schema.Slot.Name, currentComp.Schema.Slot.Name, currentComp.PascalName, receiver, currentComp.Schema.Slot.Name)
+							warningCode := fmt.Sprintf("func() []*vdom.VNode {\nif len(%s.%s) == 0 {\n%s\n}\nreturn %s.%s\n}()...",
+								receiver, slot.Name, emptySlotWarning(slot.Name, currentComp), receiver, slot.Name)
 							childrenCode = append(childrenCode, warningCode)
 						} else {
 							// No dev warning: just spread the slot
-							childrenCode = append(childrenCode, fmt.Sprintf("%s.%s...", receiver, currentComp.Schema.Slot.Name))
+							childrenCode = append(childrenCode, fmt.Sprintf("%s.%s...", receiver, slot.Name))
 						}
 						continue
 					}
@@ -139,8 +218,8 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 							hasSlotSpread = true
 
 							if opts.DevMode {
-								warningCode := fmt.Sprintf("func() []*vdom.VNode {\nif len(%s.%s) == 0 {\nconsole.Warn(\"[Slot] Rendering empty content slot '%s' in component '%s'. Parent provided no content.\")\n}\nreturn %s.%s\n}()...",
-									receiver, propDesc.Name, propDesc.Name, currentComp.PascalName, receiver, propDesc.Name)
+								warningCode := fmt.Sprintf("func() []*vdom.VNode {\nif len(%s.%s) == 0 {\n%s\n}\nreturn %s.%s\n}()...",
+									receiver, propDesc.Name, emptySlotWarning(propDesc.Name, currentComp), receiver, propDesc.Name)
 								childrenCode = append(childrenCode, warningCode)
 							} else {
 								childrenCode = append(childrenCode, fmt.Sprintf("%s.%s...", receiver, propDesc.Name))
@@ -152,6 +231,10 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 			}
 			childCode := generateNodeCode(c, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
 			if childCode != "" {
+				// An {@if} or a {@switch} with multi-node branches yields a slice, just like a loop
+				if strings.HasPrefix(childCode, fragmentPrefix) {
+					hasForLoop = true
+				}
 				childrenCode = append(childrenCode, childCode)
 			}
 		}
@@ -162,8 +245,8 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 			// Generate code that collects all children into a slice
 			childrenStr = "func() []*vdom.VNode {\nvar allChildren []*vdom.VNode\n"
 			for _, code := range childrenCode {
-				// Check if this looks like a for loop return (starts with "func")
-				if strings.HasPrefix(strings.TrimSpace(code), "func()") {
+				// Check if this looks like a for loop return (an IIFE returning a slice)
+				if strings.HasPrefix(strings.TrimSpace(code), fragmentPrefix) {
 					// For loop or slot with dev warning returns []*vdom.VNode, need spread operator
 					if !strings.HasSuffix(code, "...") {
 						childrenStr += fmt.Sprintf("allChildren = append(allChildren, %s...)\n", code)
@@ -183,22 +266,22 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 			childrenStr = strings.Join(childrenCode, ", ")
 		}
 
-		attrsMapStr := generateAttributesMap(n, receiver, currentComp, htmlSource)
+		attrsMapStr := generateAttributesMap(n, receiver, currentComp, htmlSource, opts, loopCtx)
 
 		switch tagName {
 		case "div":
-			return fmt.Sprintf("vdom.Div(%s, %s)", attrsMapStr, childrenStr)
+			return fmt.Sprintf("vdom.Div(%s, %s)", attrsMapStr, childrenStr), nil
 		case "ul", "ol":
 			// Handle spread operator for ul/ol elements
 			if hasForLoop || hasSlotSpread {
 				// childrenStr ends with "...", need to wrap in an IIFE that returns a slice
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "..."))
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "...")), nil
 			} else {
 				// Regular children list
 				if childrenStr == "" {
-					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr)
+					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr), nil
 				}
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr)
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr), nil
 			}
 		case "p", "button", "li", "h1", "h2", "h3", "h4", "h5", "h6":
 			textContent := ""
@@ -216,9 +299,11 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 			fullText := textBuilder.String()
 			if fullText != "" {
 				// Handle data binding and inline conditionals in the text content
-				// Estimate line number by searching for the text in the HTML source
-				lineNum := estimateLineNumber(htmlSource, fullText)
-				textContent = generateTextExpression(fullText, receiver, currentComp, htmlSource, lineNum, loopCtx)
+				lineNum := opts.Lines.textLine(n)
+				var err error
+				if textContent, err = generateTextExpression(fullText, receiver, currentComp, htmlSource, lineNum, opts, loopCtx); err != nil {
+					return "", err
+				}
 			} else {
 				textContent = `""` // Default to empty string if no text node
 			}
@@ -229,20 +314,23 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 				// If there are child elements (e.g. <span>), render as a full VNode with children
 				// so that inline elements are not silently dropped.
 				if hasElementChildren {
+					if hasForLoop || hasSlotSpread {
+						return fmt.Sprintf("vdom.NewVNode(\"p\", %s, %s, \"\")", attrsMapStr, strings.TrimSuffix(childrenStr, "...")), nil
+					}
 					if childrenStr == "" {
-						return fmt.Sprintf("vdom.NewVNode(\"p\", %s, nil, \"\")", attrsMapStr)
+						return fmt.Sprintf("vdom.NewVNode(\"p\", %s, nil, \"\")", attrsMapStr), nil
 					}
-					return fmt.Sprintf("vdom.NewVNode(\"p\", %s, []*vdom.VNode{%s}, \"\")", attrsMapStr, childrenStr)
+					return fmt.Sprintf("vdom.NewVNode(\"p\", %s, []*vdom.VNode{%s}, \"\")", attrsMapStr, childrenStr), nil
 				}
-				return fmt.Sprintf("vdom.Paragraph(%s, %s)", textContent, attrsMapStr)
+				return fmt.Sprintf("vdom.Paragraph(%s, %s)", textContent, attrsMapStr), nil
 			case "button":
 				// Always use children for button content (childrenStr already contains vdom.Text(...)
 				// for any plain-text children). Passing textContent as well would create redundant
 				// dual-storage (both Content and Children set), which breaks patch updates.
 				if childrenStr == "" {
-					return fmt.Sprintf("vdom.Button(\"\", %s)", attrsMapStr)
+					return fmt.Sprintf("vdom.Button(\"\", %s)", attrsMapStr), nil
 				}
-				return fmt.Sprintf("vdom.Button(\"\", %s, %s)", attrsMapStr, childrenStr)
+				return fmt.Sprintf("vdom.Button(\"\", %s, %s)", attrsMapStr, childrenStr), nil
 			case "li":
 				// For li elements, check if there are child components/elements (not just text)
 				hasElementChildren := false
@@ -255,31 +343,31 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 
 				if !hasElementChildren {
 					// Only text content - render with text parameter
-					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent)
+					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent), nil
 				}
 
 				// Has component or element children - render them properly
 				if hasForLoop || hasSlotSpread {
-					return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "..."))
+					return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "...")), nil
 				} else {
-					return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr)
+					return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr), nil
 				}
 			default:
 				// For h1-h6, use NewVNode directly with text content
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent)
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent), nil
 			}
 		case "input":
 			// Handle input element
-			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr)
+			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr), nil
 		case "select":
 			// Handle select element with option children
 			if hasForLoop || hasSlotSpread {
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "..."))
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "...")), nil
 			} else {
 				if childrenStr == "" {
-					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr)
+					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr), nil
 				}
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr)
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr), nil
 			}
 		case "option":
 			// Handle option element
@@ -292,29 +380,36 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 			}
 			fullText := textBuilder.String()
 			if fullText != "" {
-				lineNum := estimateLineNumber(htmlSource, fullText)
-				textContent = generateTextExpression(fullText, receiver, currentComp, htmlSource, lineNum, loopCtx)
+				lineNum := opts.Lines.textLine(n)
+				var err error
+				if textContent, err = generateTextExpression(fullText, receiver, currentComp, htmlSource, lineNum, opts, loopCtx); err != nil {
+					return "", err
+				}
 			} else {
 				textContent = `""`
 			}
-			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent)
+			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent), nil
 		case "textarea":
 			// Handle textarea element
-			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr)
+			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr), nil
 		case "form":
 			// Handle form element with children
 			if hasForLoop || hasSlotSpread {
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "..."))
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "...")), nil
 			} else {
 				if childrenStr == "" {
-					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr)
+					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr), nil
 				}
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr)
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr), nil
 			}
-		case "nav", "a", "span", "section", "article", "header", "footer", "main", "aside":
-			// Handle semantic HTML5 elements and inline elements with children
+		case "img", "br", "hr", "wbr", "area", "col", "embed", "source", "track":
+			// Void elements — no children or text content
+			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr), nil
+		default:
+			// Any other element (<a>, <span>, <table>, <label>, <svg>, ...) keeps its tag, with
+			// its children, or its text when it has no child elements
 			if hasForLoop || hasSlotSpread {
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "..."))
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, %s, \"\")", strconv.Quote(tagName), attrsMapStr, strings.TrimSuffix(childrenStr, "...")), nil
 			} else {
 				if childrenStr == "" {
 					// Check if there's text content - concatenate all text nodes
@@ -327,23 +422,27 @@ func generateNodeCode(n *html.Node, receiver string, componentMap map[string]com
 					}
 					fullText := textBuilder.String()
 					if fullText != "" {
-						lineNum := estimateLineNumber(htmlSource, fullText)
-						textContent = generateTextExpression(fullText, receiver, currentComp, htmlSource, lineNum, loopCtx)
-						return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent)
+						lineNum := opts.Lines.textLine(n)
+						var err error
+						if textContent, err = generateTextExpression(fullText, receiver, currentComp, htmlSource, lineNum, opts, loopCtx); err != nil {
+							return "", err
+						}
+						return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, %s)", strconv.Quote(tagName), attrsMapStr, textContent), nil
 					}
-					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr)
+					return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr), nil
 				}
-				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr)
+				return fmt.Sprintf("vdom.NewVNode(%s, %s, []*vdom.VNode{%s}, \"\")", strconv.Quote(tagName), attrsMapStr, childrenStr), nil
 			}
-		case "img", "br", "hr", "wbr":
-			// Void elements — no children or text content
-			return fmt.Sprintf("vdom.NewVNode(%s, %s, nil, \"\")", strconv.Quote(tagName), attrsMapStr)
-		default:
-			return `vdom.Div(nil)` // Default to an empty div for unknown tags
 		}
 	}
 
-	return ""
+	return "", nil
+}
+
+// isRawTextElement reports whether n is a <style> or <script> element, whose text
+// content must not be scanned for bindings.
+func isRawTextElement(n *html.Node) bool {
+	return n != nil && n.Type == html.ElementNode && (n.Data == "style" || n.Data == "script")
 }
 
 // isComponentTag checks if a tag name follows the component naming convention (PascalCase).
@@ -381,3 +480,9 @@ func findOriginalTagName(n *html.Node, lowercasedName string, htmlSource string)
 	// Fallback: return the lowercased name
 	return lowercasedName
 }
+
+// emptySlotWarning returns the dev-mode warning logged when the slot field is rendered empty.
+func emptySlotWarning(slotName string, currentComp componentInfo) string {
+	return generateDevWarning(fmt.Sprintf("[Slot] Rendering empty content slot '%s' in component '%s'. Parent provided no content.",
+		slotName, currentComp.PascalName))
+}
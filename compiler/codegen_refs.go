@@ -0,0 +1,45 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// takeRefAttribute removes the ref="..." attribute from n and returns its value.
+func takeRefAttribute(n *html.Node) (string, bool) {
+	for i, attr := range n.Attr {
+		if attr.Key == "ref" {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// generateRefExpression validates a ref="FieldName" binding and returns the *vdom.ElementRef
+// expression to pass to vdom.WithRef. Outside loops the field must be a vdom.ElementRef;
+// inside a {@for} it must be a vdom.ElementRefs, keyed by the loop's trackBy value.
+func generateRefExpression(fieldName string, n *html.Node, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (string, error) {
+
+	refDesc, exists := currentComp.Schema.Refs[strings.ToLower(fieldName)]
+	if !exists || refDesc.Name != fieldName {
+		return "", fmt.Errorf("Compilation Error in %s:%d: ref=\"%s\" on <%s> does not match a field of type vdom.ElementRef or vdom.ElementRefs on component '%s'.\n",
+			currentComp.Path, lineNumber, fieldName, n.Data, currentComp.PascalName)
+	}
+
+	if loopCtx == nil {
+		if refDesc.GoType != "vdom.ElementRef" {
+			return "", fmt.Errorf("Compilation Error in %s:%d: ref=\"%s\" is outside a {@for} block, so field '%s' must be of type vdom.ElementRef, found '%s'.\n",
+				currentComp.Path, lineNumber, fieldName, fieldName, refDesc.GoType)
+		}
+		return fmt.Sprintf("&%s.%s", receiver, refDesc.Name), nil
+	}
+
+	if refDesc.GoType != "vdom.ElementRefs" {
+		return "", fmt.Errorf("Compilation Error in %s:%d: ref=\"%s\" is inside a {@for} block, so field '%s' must be of type vdom.ElementRefs (one ref per trackBy key), found '%s'.\n",
+			currentComp.Path, lineNumber, fieldName, fieldName, refDesc.GoType)
+	}
+	return fmt.Sprintf("%s.%s.For(%s)", receiver, refDesc.Name, loopCtx.TrackBy), nil
+}
@@ -0,0 +1,168 @@
+package compiler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// slotElement wraps the content a parent passes to a named slot of a component:
+// <Card><slot name="header"><h2>Hi</h2></slot><p>Body</p></Card>. Content outside any
+// <slot> goes to the default slot.
+const slotElement = "slot"
+
+// slotFields returns the content slot fields of the schema: the default slot first, then
+// the named slots ordered by name.
+func (s componentSchema) slotFields() []propertyDescriptor {
+	var fields []propertyDescriptor
+	if s.Slot != nil {
+		fields = append(fields, *s.Slot)
+	}
+	for _, name := range s.slotNames() {
+		fields = append(fields, s.Slots[name])
+	}
+	return fields
+}
+
+// slotNames returns the names of the named slots, sorted.
+func (s componentSchema) slotNames() []string {
+	names := make([]string, 0, len(s.Slots))
+	for name := range s.Slots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// slotField returns the slot field, default or named, whose lowercased field name is
+// lowercaseName, as written in a {Field} spread of the component's own template.
+func (s componentSchema) slotField(lowercaseName string) (propertyDescriptor, bool) {
+	for _, field := range s.slotFields() {
+		if field.LowercaseName == lowercaseName {
+			return field, true
+		}
+	}
+	return propertyDescriptor{}, false
+}
+
+// generateSlotProps generates the slot fields of the struct literal for component compInfo
+// rendered by element n. The children wrapped in <slot name="..."> fill that named slot, the
+// others fill the default slot; a slot given no content is nil. A <slot> naming no slot of
+// the component, and content for a default slot the component does not have, are errors.
+func generateSlotProps(n *html.Node, compInfo componentInfo, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) ([]string, error) {
+	fail := func(at *html.Node, format string, a ...any) error {
+		lineNumber := opts.Lines.line(at)
+		return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), getContextLines(htmlSource, lineNumber, 2))
+	}
+	available := func() string {
+		names := compInfo.Schema.slotNames()
+		if len(names) == 0 {
+			return "none"
+		}
+		return strings.Join(names, ", ")
+	}
+
+	var defaultContent []*html.Node
+	named := make(map[string][]*html.Node)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.Data != slotElement {
+			defaultContent = append(defaultContent, c)
+			continue
+		}
+		name := ""
+		for _, attr := range c.Attr {
+			if attr.Key == "name" {
+				name = attr.Val
+			}
+		}
+		if name == "" {
+			return nil, fail(c, "<slot> inside <%s> needs the name of the slot it fills, as in <slot name=\"header\">. Available slots: [%s]", compInfo.PascalName, available())
+		}
+		if _, exists := compInfo.Schema.Slots[name]; !exists {
+			return nil, fail(c, "Component '%s' has no slot '%s'. Available slots: [%s]", compInfo.PascalName, name, available())
+		}
+		if _, filled := named[name]; filled {
+			return nil, fail(c, "Slot '%s' of component '%s' is filled twice; put all its content in one <slot name=\"%s\">.", name, compInfo.PascalName, name)
+		}
+		named[name] = nil
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			named[name] = append(named[name], child)
+		}
+	}
+
+	if compInfo.Schema.Slot == nil {
+		for _, c := range defaultContent {
+			if c.Type == html.ElementNode || (c.Type == html.TextNode && strings.TrimSpace(c.Data) != "") {
+				return nil, fail(c, "Component '%s' has no default slot for content outside a <slot> element. Available slots: [%s]", compInfo.PascalName, available())
+			}
+		}
+	}
+
+	var props []string
+	fill := func(field propertyDescriptor, content []*html.Node) error {
+		code, err := collectSlotChildren(content, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+		if err != nil {
+			return err
+		}
+		if code == "" {
+			// Empty slot: compile to nil
+			code = "nil"
+		}
+		props = append(props, fmt.Sprintf("%s: %s", field.Name, code))
+		return nil
+	}
+	if compInfo.Schema.Slot != nil {
+		if err := fill(*compInfo.Schema.Slot, defaultContent); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range compInfo.Schema.slotNames() {
+		if err := fill(compInfo.Schema.Slots[name], named[name]); err != nil {
+			return nil, err
+		}
+	}
+	return props, nil
+}
+
+// generateSlotFallbackCode generates the code of a <go-slot> placeholder, written
+// {@slot Body}<p>No content</p>{@endslot}: a fragment returning the content of the slot, or
+// the fallback markup when the parent passed none. Unlike a plain {Body} spread, an empty
+// slot is expected here and gets no dev-mode warning.
+func generateSlotFallbackCode(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) (string, error) {
+	field := ""
+	for _, attr := range n.Attr {
+		if attr.Key == "data-field" {
+			field = attr.Val
+		}
+	}
+	slot, ok := currentComp.Schema.slotField(strings.ToLower(field))
+	if !ok {
+		var fields []string
+		for _, s := range currentComp.Schema.slotFields() {
+			fields = append(fields, s.Name)
+		}
+		available := "none"
+		if len(fields) > 0 {
+			available = strings.Join(fields, ", ")
+		}
+		lineNumber := opts.Lines.line(n)
+		return "", fmt.Errorf("Compilation Error in %s:%d: {@slot %s} does not name a content slot of component '%s'. Slot fields: [%s]\n%s",
+			currentComp.Path, lineNumber, field, currentComp.PascalName, available, getContextLines(htmlSource, lineNumber, 2))
+	}
+
+	var content []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		content = append(content, c)
+	}
+	fallback, err := collectSlotChildren(content, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+	if err != nil {
+		return "", err
+	}
+	if fallback == "" {
+		fallback = "nil"
+	}
+	return fmt.Sprintf("%s {\nif len(%s.%s) > 0 {\nreturn %s.%s\n}\nreturn %s\n}()",
+		fragmentPrefix, receiver, slot.Name, receiver, slot.Name, fallback), nil
+}
@@ -0,0 +1,69 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// styleAttribute is the directive binding an element's style to a map: [style]="{Styles}".
+const styleAttribute = "[style]"
+
+// styleMapType is the type a [style] binding must have.
+const styleMapType = "map[string]string"
+
+// generateStyleExpression validates a [style]="{Field}" binding on n and returns the Go
+// expression of the style attribute: vdom.FormatStyle of the bound map. The field is a
+// map[string]string prop or state field or, inside a loop, the loop variable or one of its
+// fields ({row.Styles}). A plain style attribute on the same element is a compile error,
+// since the two would overwrite each other.
+func generateStyleExpression(value string, n *html.Node, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) (string, error) {
+	fail := func(format string, a ...any) error {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), contextLines)
+	}
+
+	for _, a := range n.Attr {
+		if a.Key == "style" {
+			return "", fail("<%s> has both style and [style]; move the static declarations into the bound map.", n.Data)
+		}
+	}
+	match := dataBindingRegex.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || match[0] != strings.TrimSpace(value) || strings.Contains(match[1], "(") {
+		return "", fail("[style]=\"%s\" must bind a %s field, such as [style]=\"{Styles}\".", value, styleMapType)
+	}
+	binding := match[1]
+
+	varName, fieldName, isField := strings.Cut(binding, ".")
+	if scope := loopCtx.valueScope(varName); scope != nil {
+		goType := scope.ElementType
+		if isField {
+			var err error
+			goType, err = resolveLoopFieldType(fieldName, currentComp, scope)
+			if err != nil {
+				return "", fail("[style]=\"%s\": %v", value, err)
+			}
+		}
+		if goType != styleMapType {
+			return "", fail("[style] binding '%s' must be a %s, found '%s'.", binding, styleMapType, goType)
+		}
+		return fmt.Sprintf("vdom.FormatStyle(%s)", binding), nil
+	}
+
+	if isField {
+		return "", fail("[style] binding '%s' must name a field of component '%s' or a loop variable in scope.", binding, currentComp.PascalName)
+	}
+	desc, exists := currentComp.Schema.Props[strings.ToLower(binding)]
+	if !exists {
+		desc, exists = currentComp.Schema.State[strings.ToLower(binding)]
+	}
+	if !exists {
+		allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+		return "", fail("[style] field '%s' not found on component '%s'. Available fields: [%s]", binding, currentComp.PascalName, strings.Join(allFields, ", "))
+	}
+	if desc.GoType != styleMapType {
+		return "", fail("[style] field '%s' must be a %s, found '%s'.", desc.Name, styleMapType, desc.GoType)
+	}
+	return fmt.Sprintf("vdom.FormatStyle(%s.%s)", receiver, desc.Name), nil
+}
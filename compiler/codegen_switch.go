@@ -0,0 +1,188 @@
+package compiler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// fragmentPrefix is how generated IIFEs returning multiple nodes start.
+// Parents spread such children instead of appending them as a single node.
+const fragmentPrefix = "func() []*vdom.VNode"
+
+// switchCase is one {@case}/{@default} branch of a {@switch} block.
+type switchCase struct {
+	Value     string // Go literal for the case value; empty for {@default}
+	IsDefault bool
+	Nodes     []string // Generated code for each significant child node
+}
+
+// generateSwitchCode generates a Go switch statement inside an IIFE for a <go-switch> node.
+// If every branch renders at most one node, the IIFE returns a single *vdom.VNode (nil when
+// nothing matches). Otherwise it returns []*vdom.VNode and the parent spreads it.
+func generateSwitchCode(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) (string, error) {
+	expr := ""
+	for _, attr := range n.Attr {
+		if attr.Key == "data-expr" {
+			expr = attr.Val
+		}
+	}
+
+	goExpr, goType, err := resolveSwitchExpression(expr, receiver, currentComp, loopCtx)
+	if err != nil {
+		return "", err
+	}
+
+	// Collect branches and validate case literals
+	var cases []switchCase
+	seen := make(map[string]bool)
+	fragment := false
+	hasDefault := false
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.CommentNode || (c.Type == html.TextNode && strings.TrimSpace(c.Data) == "") {
+			continue
+		}
+		if c.Type != html.ElementNode || c.Data != "go-case" {
+			return "", fmt.Errorf("Compilation Error in %s: Content inside {@switch %s} must be placed in a {@case} or {@default} branch.\n",
+				currentComp.Path, expr)
+		}
+
+		var sc switchCase
+		for _, attr := range c.Attr {
+			switch attr.Key {
+			case "data-default":
+				sc.IsDefault = true
+				hasDefault = true
+			case "data-value":
+				if sc.Value, err = switchCaseLiteral(attr.Val, goType, expr, currentComp); err != nil {
+					return "", err
+				}
+			}
+		}
+		if !sc.IsDefault {
+			if seen[sc.Value] {
+				return "", fmt.Errorf("Compilation Error in %s: Duplicate {@case %s} in {@switch %s}.\n",
+					currentComp.Path, sc.Value, expr)
+			}
+			seen[sc.Value] = true
+		}
+
+		for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+			childCode := generateNodeCode(cc, receiver, componentMap, currentComp, htmlSource, opts, loopCtx)
+			if childCode == "" {
+				continue
+			}
+			if strings.HasPrefix(childCode, fragmentPrefix) {
+				fragment = true
+			}
+			sc.Nodes = append(sc.Nodes, childCode)
+		}
+		if len(sc.Nodes) > 1 {
+			fragment = true
+		}
+		cases = append(cases, sc)
+	}
+
+	var code strings.Builder
+	if fragment {
+		code.WriteString(fragmentPrefix + " {\n")
+	} else {
+		code.WriteString("func() *vdom.VNode {\n")
+	}
+	fmt.Fprintf(&code, "switch %s {\n", goExpr)
+	for _, sc := range cases {
+		if sc.IsDefault {
+			code.WriteString("default:\n")
+		} else {
+			fmt.Fprintf(&code, "case %s:\n", sc.Value)
+		}
+
+		if !fragment {
+			if len(sc.Nodes) == 0 {
+				code.WriteString("return nil\n")
+			} else {
+				fmt.Fprintf(&code, "return %s\n", sc.Nodes[0])
+			}
+			continue
+		}
+
+		code.WriteString("var caseNodes []*vdom.VNode\n")
+		for _, node := range sc.Nodes {
+			if strings.HasPrefix(node, fragmentPrefix) {
+				fmt.Fprintf(&code, "caseNodes = append(caseNodes, %s...)\n", node)
+			} else {
+				fmt.Fprintf(&code, "caseNodes = append(caseNodes, %s)\n", node)
+			}
+		}
+		code.WriteString("return caseNodes\n")
+	}
+	code.WriteString("}\n")
+	// With a default branch every path already returns
+	if !hasDefault {
+		code.WriteString("return nil\n")
+	}
+	code.WriteString("}()")
+	return code.String(), nil
+}
+
+// resolveSwitchExpression validates the {@switch} expression and returns the Go expression
+// to switch on together with its type. The expression must be a string or int prop or state
+// field, or a field of the value variable of an enclosing loop (e.g., post.Status).
+func resolveSwitchExpression(expr string, receiver string, currentComp componentInfo, loopCtx *loopContext) (string, string, error) {
+	varName, fieldName, isField := strings.Cut(expr, ".")
+
+	var goExpr, goType string
+	if isField {
+		scope := loopCtx.valueScope(varName)
+		if scope == nil {
+			return "", "", fmt.Errorf("Compilation Error in %s: {@switch %s} refers to '%s', which is not a loop variable in scope.\n",
+				currentComp.Path, expr, varName)
+		}
+		fieldType, err := resolveLoopFieldType(fieldName, currentComp, scope)
+		if err != nil {
+			return "", "", fmt.Errorf("Compilation Error in %s: {@switch %s}: %v\n", currentComp.Path, expr, err)
+		}
+		goExpr, goType = expr, fieldType
+	} else {
+		propDesc, exists := currentComp.Schema.Props[strings.ToLower(expr)]
+		if !exists {
+			// Also check state fields
+			propDesc, exists = currentComp.Schema.State[strings.ToLower(expr)]
+		}
+		if !exists {
+			allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+			return "", "", fmt.Errorf("Compilation Error in %s: Field '%s' not found on component '%s'. Available fields: [%s]\n",
+				currentComp.Path, expr, currentComp.PascalName, strings.Join(allFields, ", "))
+		}
+		goExpr, goType = fmt.Sprintf("%s.%s", receiver, propDesc.Name), propDesc.GoType
+	}
+
+	if goType != "string" && goType != "int" {
+		return "", "", fmt.Errorf("Compilation Error in %s: {@switch %s} requires a string or int field, found type '%s'.\n",
+			currentComp.Path, expr, goType)
+	}
+	return goExpr, goType, nil
+}
+
+// switchCaseLiteral converts a {@case} value into a Go literal matching the switch type.
+// String cases use single quotes ({@case 'draft'}); int cases are bare integers ({@case 2}).
+func switchCaseLiteral(value, goType, expr string, currentComp componentInfo) (string, error) {
+	value = strings.TrimSpace(value)
+	switch goType {
+	case "string":
+		if len(value) < 2 || !strings.HasPrefix(value, "'") || !strings.HasSuffix(value, "'") {
+			return "", fmt.Errorf("Compilation Error in %s: {@case %s} in {@switch %s} must be a single-quoted string literal (e.g., {@case 'draft'}).\n",
+				currentComp.Path, value, expr)
+		}
+		return strconv.Quote(value[1 : len(value)-1]), nil
+	default: // int
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("Compilation Error in %s: {@case %s} in {@switch %s} must be an int literal.\n",
+				currentComp.Path, value, expr)
+		}
+		return strconv.Itoa(i), nil
+	}
+}
@@ -1,9 +1,11 @@
 package compiler
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -12,7 +14,7 @@ import (
 
 // generateTextExpression handles data binding in text nodes.
 // loopCtx can be nil if not inside a loop.
-func generateTextExpression(text string, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, loopCtx *loopContext) string {
+func generateTextExpression(text string, receiver string, currentComp componentInfo, htmlSource string, lineNumber int, opts compileOptions, loopCtx *loopContext) (string, error) {
 	// Check for malformed ternary expressions (opening { with ternary pattern but no closing })
 	// Count opening and closing braces to detect mismatches
 	openBraces := strings.Count(text, "{")
@@ -23,186 +25,375 @@ func generateTextExpression(text string, receiver string, currentComp componentI
 		// Check if this looks like an attempted ternary expression
 		if strings.Contains(text, "?") && strings.Contains(text, ":") && strings.Contains(text, "'") {
 			contextLines := getContextLines(htmlSource, lineNumber, 2)
-			fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Malformed expression - unclosed braces (found %d opening '{' but %d closing '}')\n%s\n"+
+			return "", fmt.Errorf("Compilation Error in %s:%d: Malformed expression - unclosed braces (found %d opening '{' but %d closing '}')\n%s\n"+
 				"This appears to be an incomplete ternary expression.\n"+
 				"Ternary expressions must be complete: {condition ? 'true' : 'false'}\n"+
-				"Expected format: {FieldName ? 'value1' : 'value2'} or {!FieldName ? 'value1' : 'value2'}\n",
+				"Expected format: {FieldName ? 'value1' : 'value2'} or {!FieldName ? TrueField : FalseField}\n",
 				currentComp.Path, lineNumber, openBraces, closeBraces, contextLines)
-			os.Exit(1)
 		}
 	}
 
 	// Check for ternary expressions first
+	if err := validateTernarySyntax(text, currentComp, htmlSource, lineNumber); err != nil {
+		return "", err
+	}
 	ternaryMatches := ternaryExprRegex.FindAllStringSubmatch(text, -1)
 
 	if len(ternaryMatches) > 0 {
-		// Handle ternary expressions
-		result := text
+		// If the text contains only the ternary expression, return it directly
+		if len(ternaryMatches) == 1 && ternaryMatches[0][0] == text {
+			return generateTernaryFromMatch(ternaryMatches[0], receiver, currentComp, htmlSource, lineNumber, loopCtx)
+		}
 
+		// Otherwise, replace each ternary with a placeholder and wrap in fmt.Sprintf
+		result := escapeFormatText(text)
+		var args []string
 		for _, match := range ternaryMatches {
-			fullMatch := match[0]
-			negated := match[1] == "!"
-			condition := match[2]
-			trueVal := match[3]
-			falseVal := match[4]
+			result = strings.Replace(result, escapeFormatText(match[0]), "%s", 1)
+			arg, err := generateTernaryFromMatch(match, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, arg)
+		}
+
+		return fmt.Sprintf(`fmt.Sprintf(%s, %s)`, strconv.Quote(result), strings.Join(args, ", ")), nil
+	}
+
+	// Original data binding logic, extended with type-aware format verbs and filters
+	matches := textBindingRegex.FindAllStringSubmatchIndex(text, -1)
+
+	if len(matches) == 0 {
+		return strconv.Quote(text), nil // It's just a static string
+	}
 
-			// Validate condition is a boolean field
-			propDesc := validateBooleanCondition(condition, currentComp, currentComp.Path, lineNumber, htmlSource)
+	var formatString strings.Builder
+	var args []string
+	last := 0
 
-			// Generate ternary expression
-			ternaryCode := generateTernaryExpression(negated, condition, trueVal, falseVal, receiver, propDesc)
+	for _, m := range matches {
+		// Static text between bindings is copied with its '%' escaped for fmt.Sprintf
+		formatString.WriteString(escapeFormatText(text[last:m[0]]))
+		last = m[1]
 
-			// If the text contains only the ternary expression, return it directly
-			if result == fullMatch {
-				return ternaryCode
+		fieldName := strings.TrimSpace(text[m[2]:m[3]])
+		filters, fallback, hasDefault, err := splitDefaultFilter(parseFilterPipeline(text[m[4]:m[5]]), fieldName, currentComp, htmlSource, lineNumber)
+		if err != nil {
+			return "", err
+		}
+		if usesFormatters(filters) {
+			opts.Imports["formatters"] = "github.com/ForgeLogic/nojs/formatters"
+		}
+
+		var expr, goType string
+		var nilChecks []string
+		if fieldPathRegex.MatchString(fieldName) {
+			expr, goType, nilChecks, err = resolveTextBinding(fieldName, receiver, currentComp, loopCtx)
+		} else if methodCallRegex.MatchString(fieldName) {
+			expr, goType, err = resolveMethodBinding(fieldName, receiver, currentComp, htmlSource, lineNumber)
+		} else {
+			expr, goType, nilChecks, err = generateArithmeticBinding(fieldName, receiver, currentComp, htmlSource, lineNumber, loopCtx)
+		}
+		if err != nil {
+			return "", err
+		}
+		if hasDefault {
+			arg, err := generateDefaultTextExpression(expr, goType, filters, fallback, nilChecks, fieldName, currentComp, htmlSource, lineNumber)
+			if err != nil {
+				return "", err
 			}
+			formatString.WriteString("%s")
+			args = append(args, arg)
+			continue
+		}
+		verb, arg, zero, err := formatTextBinding(expr, goType, filters, fieldName, currentComp, htmlSource, lineNumber)
+		if err != nil {
+			return "", err
+		}
+		formatString.WriteString(verb)
+		args = append(args, generateNilSafeExpression(arg, nilChecks, zero, fieldName, currentComp, lineNumber, opts))
+	}
+	formatString.WriteString(escapeFormatText(text[last:]))
 
-			// Otherwise, replace the match with a placeholder for fmt.Sprintf
-			result = strings.Replace(result, fullMatch, "%s", 1)
+	return fmt.Sprintf(`fmt.Sprintf(%s, %s)`, strconv.Quote(formatString.String()), strings.Join(args, ", ")), nil
+}
+
+// resolveTextBinding resolves a text binding's field name to the Go expression that reads it,
+// its Go type (empty when it cannot be determined), and the pointers the expression reads
+// through. Unknown fields are reported as compile errors.
+func resolveTextBinding(fieldName string, receiver string, currentComp componentInfo, loopCtx *loopContext) (string, string, []string, error) {
+	// Check if this is a variable of this loop or an enclosing one first
+	if scope := loopCtx.scopeOf(fieldName); scope != nil {
+		if fieldName == scope.ValueVar {
+			// Reference loop value variable
+			return fieldName, scope.ElementType, nil, nil
+		}
+		// Reference loop index variable, or the key of a map entry
+		return fieldName, scope.IndexType, nil, nil
+	}
+	// Check if it's a field access on a loop value variable (e.g., user.Name).
+	// Nil elements of a pointer slice are skipped by the loop, so no guard is needed.
+	if varName, field, ok := strings.Cut(fieldName, "."); ok {
+		if scope := loopCtx.valueScope(varName); scope != nil {
+			// Deeper paths are left to the Go compiler and formatted with %v
+			goType, err := resolveLoopFieldType(field, currentComp, scope)
+			if err != nil {
+				goType = ""
+			}
+			return fieldName, goType, nil, nil
 		}
+	}
 
-		// If there are other parts of the text, wrap in fmt.Sprintf
-		var args []string
-		for _, match := range ternaryMatches {
-			negated := match[1] == "!"
-			condition := match[2]
-			trueVal := match[3]
-			falseVal := match[4]
-			propDesc := validateBooleanCondition(condition, currentComp, currentComp.Path, lineNumber, htmlSource)
-			args = append(args, generateTernaryExpression(negated, condition, trueVal, falseVal, receiver, propDesc))
+	// Messages of a form's fields (Errors.Email)
+	if expr, goType, ok, err := resolveFormBinding(fieldName, receiver, currentComp); ok {
+		if err != nil {
+			return "", "", nil, fmt.Errorf("Compilation Error in %s: %v\n", currentComp.Path, err)
 		}
+		return expr, goType, nil, nil
+	}
 
-		return fmt.Sprintf(`fmt.Sprintf(%s, %s)`, strconv.Quote(result), strings.Join(args, ", "))
+	// Check if this is a nested field access (e.g., Ctx.Title)
+	if strings.Contains(fieldName, ".") {
+		rootField := strings.ToLower(strings.SplitN(fieldName, ".", 2)[0])
+		_, inProps := currentComp.Schema.Props[rootField]
+		_, inState := currentComp.Schema.State[rootField]
+
+		// Check if root field exists on component
+		if inProps || inState {
+			// Resolve the nested field type
+			componentDir := filepath.Dir(currentComp.Path)
+			goType, pointerPaths, err := resolveNestedFieldPath(fieldName, currentComp, componentDir)
+			if err != nil {
+				// Try to get available fields on the nested type for better error message
+				nestedFields := getAvailableNestedFields(fieldName, currentComp, componentDir)
+				allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+
+				var msg string
+				if len(nestedFields) > 0 {
+					msg = fmt.Sprintf("Compilation Error in %s: Field '%s' not resolvable on component '%s'. %v\n\nAvailable component fields: [%s]\nAvailable fields on %s: [%s]\n",
+						currentComp.Path, fieldName, currentComp.PascalName, err, strings.Join(allFields, ", "),
+						strings.SplitN(fieldName, ".", 2)[0], strings.Join(nestedFields, ", "))
+				} else {
+					msg = fmt.Sprintf("Compilation Error in %s: Field '%s' not resolvable on component '%s'. %v\nAvailable fields: [%s]\n",
+						currentComp.Path, fieldName, currentComp.PascalName, err, strings.Join(allFields, ", "))
+				}
+				return "", "", nil, errors.New(msg)
+			}
+			// Use nested field access as-is, guarded against nil pointers along the path
+			return fmt.Sprintf("%s.%s", receiver, fieldName), goType, pointerNilChecks(receiver, pointerPaths), nil
+		}
 	}
 
-	// Original data binding logic
-	matches := dataBindingRegex.FindAllStringSubmatch(text, -1)
+	// Type-safety check: does the field exist on the component struct (props or state)?
+	propDesc, inProps := currentComp.Schema.Props[strings.ToLower(fieldName)]
+	stateDesc, inState := currentComp.Schema.State[strings.ToLower(fieldName)]
 
-	if len(matches) == 0 {
-		return strconv.Quote(text) // It's just a static string
+	if !inProps && !inState {
+		// If we're in a loop, provide more context in the error
+		if loopCtx != nil {
+			allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
+			return "", "", nil, fmt.Errorf("Compilation Error in %s: Field '%s' not found.\n"+
+				"  - Not a loop variable (loops have: %s)\n"+
+				"  - Not a component field (available: %s)\n"+
+				"  - For loop item fields, use: %s.FieldName\n",
+				currentComp.Path, fieldName,
+				strings.Join(loopCtx.variables(), ", "),
+				strings.Join(allFields, ", "),
+				loopCtx.ValueVar)
+		}
+		return "", "", nil, fmt.Errorf("Compilation Error in %s: Field '%s' not found on component '%s' for data binding.\n",
+			currentComp.Path, fieldName, currentComp.PascalName)
+	}
+	// Use the schema's correctly-cased field name, not the raw template expression,
+	// so that e.g. {id} in the template correctly emits c.ID (not c.id).
+	desc := stateDesc
+	if inProps {
+		desc = propDesc
 	}
+	return fmt.Sprintf("%s.%s", receiver, desc.Name), desc.GoType, nil, nil
+}
 
-	formatString := dataBindingRegex.ReplaceAllString(text, "%v")
-	var args []string
+// resolveMethodBinding resolves a {Method()} binding to a call of the component's method and
+// returns the call with its result type. The method must take no parameters and return
+// exactly one value; anything else is reported as a compile error.
+func resolveMethodBinding(binding, receiver string, currentComp componentInfo, htmlSource string, lineNumber int) (string, string, error) {
+	match := methodCallRegex.FindStringSubmatch(binding)
+	name, args := match[1], strings.TrimSpace(match[2])
+	method, exists := currentComp.Schema.Methods[name]
+	if !exists {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		return "", "", fmt.Errorf("Compilation Error in %s:%d: Method '%s' not found on component '%s' for data binding.\n%s\nAvailable methods: %s\n",
+			currentComp.Path, lineNumber, name, currentComp.PascalName, contextLines, getAvailableMethodNames(currentComp.Schema.Methods))
+	}
+	if args != "" || len(method.Params) > 0 {
+		var params []string
+		for _, param := range method.Params {
+			params = append(params, param.Type)
+		}
+		return "", "", textBindingError(currentComp, htmlSource, lineNumber, "Method '%s' takes (%s), but a bound method must take no parameters: {%s()}\n",
+			name, strings.Join(params, ", "), name)
+	}
+	if len(method.Returns) != 1 {
+		return "", "", textBindingError(currentComp, htmlSource, lineNumber, "Method '%s' returns %d values, but a bound method must return exactly one\n",
+			name, len(method.Returns))
+	}
+	return fmt.Sprintf("%s.%s()", receiver, name), method.Returns[0], nil
+}
 
-	for _, match := range matches {
-		fieldName := match[1]
+// formatTextBinding picks the fmt verb for a text binding from its Go type and its formatting
+// filters, returning the verb, the argument expression, and the value rendered when the
+// binding reads through a nil pointer:
+//
+//	{Price}                     -> %g           (float32, float64)
+//	{Active}                    -> %t           (bool)
+//	{Price|printf:'%.2f'}       -> %.2f         (any type; the layout must hold exactly one verb)
+//	{CreatedAt | date "Jan 2"}  -> %s           (time.Time, via CreatedAt.Format("Jan 2"))
+//	{Price | number 2}          -> %s           (integer and float types, via formatters.Number)
+//	{Title | upper}             -> %s           (string types, via formatters.Upper; lower alike)
+//
+// Strings, integers, and types that cannot be resolved keep %v. A time.Time without a date
+// filter is a compile error, since its %v form is Go's debugging layout. Filters apply in
+// order, each after the first formatting the string the previous ones rendered:
+// {CreatedAt | date "Mon" | upper}.
+func formatTextBinding(expr string, goType string, filters []bindingFilter, fieldName string, currentComp componentInfo, htmlSource string, lineNumber int) (string, string, string, error) {
+	fail := func(format string, a ...any) (string, string, string, error) {
+		return "", "", "", textBindingError(currentComp, htmlSource, lineNumber, format, a...)
+	}
+	baseType := strings.TrimPrefix(goType, "*")
+
+	if len(filters) == 0 {
+		switch baseType {
+		case "float32", "float64":
+			return "%g", expr, typedZeroLiteral(baseType), nil
+		case "bool":
+			return "%t", expr, "false", nil
+		case "time.Time":
+			return fail("Field '%s' is a time.Time and needs an explicit format.\n"+
+				"Use the date filter with a Go time layout: {%s|date:'2006-01-02'}\n", fieldName, fieldName)
+		}
+		return "%v", expr, `""`, nil
+	}
 
-		// Check if this is a loop variable first
-		if loopCtx != nil {
-			if fieldName == loopCtx.IndexVar {
-				// Reference loop index variable
-				args = append(args, fieldName)
-				continue
+	verb, arg, zero := "%v", expr, typedZeroLiteral(baseType)
+	if baseType != goType && filters[0].Name != "printf" && filters[0].Name != "date" {
+		arg = "*" + arg // The formatters take the value the pointer field points to
+	}
+	for i, filter := range filters {
+		if i > 0 && verb != "%s" {
+			// Later filters work on the string rendered so far
+			arg = fmt.Sprintf("fmt.Sprintf(%s, %s)", strconv.Quote(verb), arg)
+			zero = fmt.Sprintf("fmt.Sprintf(%s, %s)", strconv.Quote(verb), zero)
+		}
+		if i > 0 {
+			verb, baseType = "%s", "string"
+		}
+
+		switch filter.Name {
+		case "printf":
+			layout := filter.Args[0]
+			if strings.Count(strings.ReplaceAll(layout, "%%", ""), "%") != 1 {
+				return fail("Invalid printf filter on '%s': layout '%s' must contain exactly one fmt verb (e.g., {%s|printf:'%%.2f'})\n", fieldName, layout, fieldName)
 			}
-			if fieldName == loopCtx.ValueVar {
-				// Reference loop value variable
-				args = append(args, fieldName)
-				continue
+			verb = layout
+
+		case "date":
+			if baseType != "" && baseType != "time.Time" {
+				return fail("The date filter on '%s' requires a time.Time field, but the field is %s\n", fieldName, cmp.Or(goType, baseType))
 			}
-			// Check if it's a field access on the loop value variable (e.g., user.Name)
-			if strings.Contains(fieldName, ".") {
-				parts := strings.SplitN(fieldName, ".", 2)
-				varName := parts[0]
-				if varName == loopCtx.ValueVar {
-					// This is a field access on the loop value variable
-					// Just use it as-is (e.g., user.Name)
-					args = append(args, fieldName)
-					continue
-				}
+			if filter.Args[0] == "" {
+				return fail("The date filter on '%s' needs a Go time layout (e.g., {%s|date:'2006-01-02'})\n", fieldName, fieldName)
 			}
-		}
+			verb, arg, zero = "%s", fmt.Sprintf("%s.Format(%s)", arg, strconv.Quote(filter.Args[0])), `""`
 
-		// Check if this is a nested field access (e.g., Ctx.Title)
-		if strings.Contains(fieldName, ".") {
-			rootField := strings.ToLower(strings.SplitN(fieldName, ".", 2)[0])
+		case "number":
+			decimals, err := strconv.Atoi(filter.Args[0])
+			if err != nil || decimals < 0 {
+				return fail("The number filter on '%s' needs a count of decimals (e.g., {%s | number 2}), got '%s'\n", fieldName, fieldName, filter.Args[0])
+			}
+			if baseType != "" && zeroKind(baseType, currentComp) != "number" {
+				return fail("The number filter on '%s' requires an integer or float field, but the field is %s\n", fieldName, baseType)
+			}
+			// The zero is formatted here: a named numeric type has no typed zero literal
+			verb, arg = "%s", fmt.Sprintf("formatters.Number(%s, %d)", arg, decimals)
+			zero = strconv.Quote(strconv.FormatFloat(0, 'f', decimals, 64))
 
-			// Check if root field exists on component
-			if _, inProps := currentComp.Schema.Props[rootField]; inProps {
-				// Resolve the nested field type
-				componentDir := filepath.Dir(currentComp.Path)
-				_, err := resolveNestedFieldType(fieldName, currentComp, componentDir)
-				if err != nil {
-					// Try to get available fields on the nested type for better error message
-					nestedFields := getAvailableNestedFields(fieldName, currentComp, componentDir)
-					allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
-
-					var msg string
-					if len(nestedFields) > 0 {
-						msg = fmt.Sprintf("Compilation Error in %s: Field '%s' not resolvable on component '%s'. %v\n\nAvailable component fields: [%s]\nAvailable fields on %s: [%s]\n",
-							currentComp.Path, fieldName, currentComp.PascalName, err, strings.Join(allFields, ", "),
-							strings.SplitN(fieldName, ".", 2)[0], strings.Join(nestedFields, ", "))
-					} else {
-						msg = fmt.Sprintf("Compilation Error in %s: Field '%s' not resolvable on component '%s'. %v\nAvailable fields: [%s]\n",
-							currentComp.Path, fieldName, currentComp.PascalName, err, strings.Join(allFields, ", "))
-					}
-					fmt.Fprint(os.Stderr, msg)
-					os.Exit(1)
-				}
-				// Use nested field access as-is
-				args = append(args, fmt.Sprintf("%s.%s", receiver, fieldName))
-				continue
-			} else if _, inState := currentComp.Schema.State[rootField]; inState {
-				// Resolve the nested field type
-				componentDir := filepath.Dir(currentComp.Path)
-				_, err := resolveNestedFieldType(fieldName, currentComp, componentDir)
-				if err != nil {
-					// Try to get available fields on the nested type for better error message
-					nestedFields := getAvailableNestedFields(fieldName, currentComp, componentDir)
-					allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
-
-					var msg string
-					if len(nestedFields) > 0 {
-						msg = fmt.Sprintf("Compilation Error in %s: Field '%s' not resolvable on component '%s'. %v\n\nAvailable component fields: [%s]\nAvailable fields on %s: [%s]\n",
-							currentComp.Path, fieldName, currentComp.PascalName, err, strings.Join(allFields, ", "),
-							strings.SplitN(fieldName, ".", 2)[0], strings.Join(nestedFields, ", "))
-					} else {
-						msg = fmt.Sprintf("Compilation Error in %s: Field '%s' not resolvable on component '%s'. %v\nAvailable fields: [%s]\n",
-							currentComp.Path, fieldName, currentComp.PascalName, err, strings.Join(allFields, ", "))
-					}
-					fmt.Fprint(os.Stderr, msg)
-					os.Exit(1)
-				}
-				// Use nested field access as-is
-				args = append(args, fmt.Sprintf("%s.%s", receiver, fieldName))
-				continue
+		case "upper", "lower":
+			if baseType != "" && zeroKind(baseType, currentComp) != "string" {
+				return fail("The %s filter on '%s' requires a string field, but the field is %s\n", filter.Name, fieldName, baseType)
 			}
+			verb, arg, zero = "%s", fmt.Sprintf("formatters.%s%s(%s)", strings.ToUpper(filter.Name[:1]), filter.Name[1:], arg), `""`
 		}
+	}
+	return verb, arg, zero, nil
+}
 
-		// Type-safety check: does the field exist on the component struct (props or state)?
-		propDesc, inProps := currentComp.Schema.Props[strings.ToLower(fieldName)]
-		stateDesc, inState := currentComp.Schema.State[strings.ToLower(fieldName)]
+// usesFormatters reports whether any of filters compiles to a call into the formatters
+// package.
+func usesFormatters(filters []bindingFilter) bool {
+	for _, filter := range filters {
+		if filter.Name == "number" || filter.Name == "upper" || filter.Name == "lower" {
+			return true
+		}
+	}
+	return false
+}
 
-		if !inProps && !inState {
-			// If we're in a loop, provide more context in the error
-			if loopCtx != nil {
-				allFields := append(getAvailableFieldNames(currentComp.Schema.Props), getAvailableFieldNames(currentComp.Schema.State)...)
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: Field '%s' not found.\n"+
-					"  - Not a loop variable (loop has: %s, %s)\n"+
-					"  - Not a component field (available: %s)\n"+
-					"  - For loop item fields, use: %s.FieldName\n",
-					currentComp.Path, fieldName,
-					loopCtx.IndexVar, loopCtx.ValueVar,
-					strings.Join(allFields, ", "),
-					loopCtx.ValueVar)
-			} else {
-				fmt.Fprintf(os.Stderr, "Compilation Error in %s: Field '%s' not found on component '%s' for data binding.\n",
-					currentComp.Path, fieldName, currentComp.PascalName)
-			}
-			os.Exit(1)
+// generateDefaultTextExpression generates a text binding with a default filter: the string
+// expression renders fallback when the value is its type's zero value (0, "", false, a zero
+// time.Time, nil) or is read through a nil pointer, and the formatted value otherwise:
+//
+//	{Year|default:'—'}                      -> "—" while Year == 0
+//	{Price|printf:'%.2f'|default:'n/a'}     -> "n/a" while Price == 0
+//	{CreatedAt|date:'Jan 2'|default:'—'}    -> "—" while CreatedAt.IsZero()
+func generateDefaultTextExpression(expr, goType string, filters []bindingFilter, fallback string, nilChecks []string, fieldName string, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	var code strings.Builder
+	code.WriteString("func() string {\n")
+	if len(nilChecks) > 0 {
+		conds := make([]string, len(nilChecks))
+		for i, check := range nilChecks {
+			conds[i] = check + " == nil"
 		}
-		// Use the schema's correctly-cased field name, not the raw template expression,
-		// so that e.g. {id} in the template correctly emits c.ID (not c.id).
-		var resolvedName string
-		if inProps {
-			resolvedName = propDesc.Name
-		} else {
-			resolvedName = stateDesc.Name
+		fmt.Fprintf(&code, "if %s {\nreturn %s\n}\n", strings.Join(conds, " || "), strconv.Quote(fallback))
+	}
+
+	switch kind := zeroKind(goType, currentComp); kind {
+	case "time":
+		if len(filters) == 0 || filters[0].Name != "date" {
+			return "", textBindingError(currentComp, htmlSource, lineNumber, "Field '%s' is a time.Time and needs an explicit format before its default.\n"+
+				"Use the date filter with a Go time layout: {%s|date:'2006-01-02'|default:'%s'}\n", fieldName, fieldName, fallback)
+		}
+		verb, arg, _, err := formatTextBinding("v", goType, filters, fieldName, currentComp, htmlSource, lineNumber)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&code, "if v := %s; !v.IsZero() {\nreturn fmt.Sprintf(%s, %s)\n}\n", expr, strconv.Quote(verb), arg)
+	case "":
+		return "", textBindingError(currentComp, htmlSource, lineNumber, "The default filter on '%s' needs a number, string, bool, pointer or time.Time field, but the type is %s\n"+
+			"Guard the binding with {@if} instead.\n", fieldName, cmp.Or(goType, "unknown"))
+	default:
+		value := "v"
+		if kind == "pointer" {
+			value = "*v" // Rendered through the pointer once it is set
+		}
+		verb, arg, _, err := formatTextBinding(value, strings.TrimPrefix(goType, "*"), filters, fieldName, currentComp, htmlSource, lineNumber)
+		if err != nil {
+			return "", err
+		}
+		set := "v != " + zeroKindLiteral(kind)
+		if kind == "bool" {
+			set = "v"
 		}
-		args = append(args, fmt.Sprintf("%s.%s", receiver, resolvedName))
+		fmt.Fprintf(&code, "if v := %s; %s {\nreturn fmt.Sprintf(%s, %s)\n}\n", expr, set, strconv.Quote(verb), arg)
 	}
 
-	return fmt.Sprintf(`fmt.Sprintf(%s, %s)`, strconv.Quote(formatString), strings.Join(args, ", "))
+	fmt.Fprintf(&code, "return %s\n}()", strconv.Quote(fallback))
+	return code.String(), nil
+}
+
+// textBindingError returns a compile error in a text binding, with the lines around it.
+func textBindingError(currentComp componentInfo, htmlSource string, lineNumber int, format string, a ...any) error {
+	contextLines := getContextLines(htmlSource, lineNumber, 2)
+	return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), contextLines)
 }
 
 // generateSlotTextNodeError generates a detailed error message for unwrapped text in slot content.
@@ -251,24 +442,29 @@ func generateTextExpression(text string, receiver string, currentComp componentI
 // 		componentTagLine, componentName, componentName)
 
 // 	// Print to stderr and exit
-// 	fmt.Fprint(os.Stderr, errorMsg.String())
-// 	os.Exit(1)
+// 	fmt.Fprint(errorOutput, errorMsg.String())
+// 	exit(1)
 // }
 
-// collectSlotChildren collects child nodes for content projection and generates VNode slice code.
-// Returns empty string if no children, otherwise returns Go code for []*vdom.VNode{...}.
-// Validates that slot content does not contain unwrapped text nodes.
-func collectSlotChildren(n *html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, componentName string, templatePath string, htmlSource string, opts compileOptions, loopCtx *loopContext) string {
+// collectSlotChildren generates the VNode slice code of the nodes passed as the content of
+// one slot. Returns empty string if no children, otherwise returns Go code for a []*vdom.VNode.
+func collectSlotChildren(children []*html.Node, receiver string, componentMap map[string]componentInfo, currentComp componentInfo, htmlSource string, opts compileOptions, loopCtx *loopContext) (string, error) {
 	var childrenCode []string
 
 	// Collect all children (elements and text nodes)
-	for c := n.FirstChild; c != nil; c = c.NextSibling {
+	for _, c := range children {
+		if c.Type == html.CommentNode {
+			continue
+		}
 		if c.Type == html.TextNode {
 			// Check if this is meaningful text (not just whitespace)
 			trimmed := strings.TrimSpace(c.Data)
 			if trimmed != "" {
 				// Convert text node to pure text VNode using vdom.Text()
-				textExpr := generateTextExpression(trimmed, receiver, currentComp, htmlSource, estimateTextNodeLineNumber(htmlSource, c.Data), loopCtx)
+				textExpr, err := generateTextExpression(trimmed, receiver, currentComp, htmlSource, opts.Lines.line(c), opts, loopCtx)
+				if err != nil {
+					return "", err
+				}
 				childrenCode = append(childrenCode, fmt.Sprintf(`vdom.Text(%s)`, textExpr))
 			}
 			// Skip whitespace-only text nodes
@@ -282,8 +478,23 @@ func collectSlotChildren(n *html.Node, receiver string, componentMap map[string]
 	}
 
 	if len(childrenCode) == 0 {
-		return "" // No children, will compile to nil
+		return "", nil // No children, will compile to nil
+	}
+
+	// Loops and conditionals yield slices: append them all into one
+	if slices.ContainsFunc(childrenCode, func(code string) bool { return strings.HasPrefix(code, fragmentPrefix) }) {
+		var b strings.Builder
+		b.WriteString(fragmentPrefix + " {\nvar slotNodes []*vdom.VNode\n")
+		for _, code := range childrenCode {
+			if strings.HasPrefix(code, fragmentPrefix) {
+				fmt.Fprintf(&b, "slotNodes = append(slotNodes, %s...)\n", code)
+			} else {
+				fmt.Fprintf(&b, "slotNodes = append(slotNodes, %s)\n", code)
+			}
+		}
+		b.WriteString("return slotNodes\n}()")
+		return b.String(), nil
 	}
 
-	return fmt.Sprintf("[]*vdom.VNode{%s}", strings.Join(childrenCode, ", "))
+	return fmt.Sprintf("[]*vdom.VNode{%s}", strings.Join(childrenCode, ", ")), nil
 }
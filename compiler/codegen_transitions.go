@@ -0,0 +1,55 @@
+package compiler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// transitionNamePattern is the name of a transition="..." attribute: it prefixes the CSS
+// classes -enter and -leave, so it must be a valid class name.
+var transitionNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// takeTransitionAttributes removes the transition="..." attribute from n, along with its
+// transition-timeout="..." attribute, and returns their values.
+func takeTransitionAttributes(n *html.Node) (name, timeout string, ok bool) {
+	kept := n.Attr[:0]
+	for _, attr := range n.Attr {
+		switch attr.Key {
+		case "transition":
+			name, ok = attr.Val, true
+		case "transition-timeout":
+			timeout = attr.Val
+		default:
+			kept = append(kept, attr)
+		}
+	}
+	n.Attr = kept
+	return name, timeout, ok
+}
+
+// generateTransitionArgs validates a transition="name" attribute on n, with its optional
+// transition-timeout="300ms", and returns the name and timeout arguments of
+// vdom.WithTransition. The timeout is parsed here, so generated code passes nanoseconds.
+func generateTransitionArgs(name, timeout string, n *html.Node, currentComp componentInfo, htmlSource string, lineNumber int) (string, error) {
+	fail := func(format string, a ...any) error {
+		contextLines := getContextLines(htmlSource, lineNumber, 2)
+		return fmt.Errorf("Compilation Error in %s:%d: %s\n%s", currentComp.Path, lineNumber, fmt.Sprintf(format, a...), contextLines)
+	}
+
+	if !transitionNamePattern.MatchString(name) {
+		return "", fail("transition=\"%s\" on <%s> must be a CSS class name prefix, such as \"fade\".", name, n.Data)
+	}
+	var d time.Duration
+	if timeout != "" {
+		var err error
+		d, err = time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return "", fail("transition-timeout=\"%s\" on <%s> must be a positive duration, such as \"300ms\".", timeout, n.Data)
+		}
+	}
+	return fmt.Sprintf("%s, %d", strconv.Quote(name), int64(d)), nil
+}
@@ -1,16 +1,29 @@
 package compiler
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 )
 
+// Options configures a compilation.
+type Options struct {
+	DevMode bool // Enable development mode (warnings, verbose errors, panic on lifecycle failures)
+	Strict  bool // Fail on template markup the HTML parser relocates or drops instead of warning
+}
+
 // Compile is the main entry point for the nojs AOT compiler.
 // It discovers all *.gt.html component templates under srcDir, inspects
 // their corresponding Go structs, and writes a *.generated.go file next
 // to each template.
 func Compile(srcDir string, devMode bool) error {
-	opts := compileOptions{DevMode: devMode}
+	return CompileWithOptions(srcDir, Options{DevMode: devMode})
+}
+
+// CompileWithOptions is Compile with the full set of compiler options.
+func CompileWithOptions(srcDir string, options Options) error {
+	opts := compileOptions{DevMode: options.DevMode, Strict: options.Strict}
 
 	// Convert srcDir to absolute path for consistent path handling
 	absSrcDir, err := filepath.Abs(srcDir)
@@ -24,6 +37,9 @@ func Compile(srcDir string, devMode bool) error {
 		return fmt.Errorf("failed to discover or inspect components: %w", err)
 	}
 	fmt.Printf("Discovered and inspected %d component templates.\n", len(components))
+	if err := checkReservedPropNames(components, opts.Strict); err != nil {
+		return err
+	}
 
 	componentMap := make(map[string]componentInfo)
 	for _, comp := range components {
@@ -31,10 +47,38 @@ func Compile(srcDir string, devMode bool) error {
 	}
 
 	// Step 2: Loop through each discovered component and compile its template.
+	// Every template is compiled even after one fails, so all errors are reported in one run.
+	generated := make(map[string][]byte, len(components))
+	outPaths := make([]string, 0, len(components))
+	var errs []error
 	for _, comp := range components {
-		if err := compileComponentTemplate(comp, componentMap, absSrcDir, opts); err != nil {
-			return fmt.Errorf("failed to compile template for %s: %w", comp.PascalName, err)
+		outPath, source, err := compileComponentTemplate(comp, componentMap, absSrcDir, opts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to compile template for %s: %w", comp.PascalName, err))
+			continue
 		}
+		generated[outPath] = source
+		outPaths = append(outPaths, outPath)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	// Step 3: Check the generated files compile for both wasm and native builds, then write them.
+	if len(generated) > 0 {
+		if err := verifyPlatforms(absSrcDir, generated); err != nil {
+			return err
+		}
+	}
+	for _, outPath := range outPaths {
+		if err := os.WriteFile(outPath, generated[outPath], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", outPath, err)
+		}
+	}
+
+	// Step 4: Generate typed params helpers from any routes definition files.
+	if err := compileRouteDefinitions(absSrcDir); err != nil {
+		return fmt.Errorf("failed to compile route definitions: %w", err)
 	}
 	return nil
 }
@@ -0,0 +1,33 @@
+// Package compilertest checks the errors the compiler reports for template fixtures. A
+// fixture is a directory of templates and component files under the testdata directory of
+// the test's package, which go tooling and nojsc leave alone.
+//
+//	err := compilertest.CompileFixture("undefined", compiler.Options{})
+//	compilertest.AssertErrorContains(t, err, "StatusList.gt.html:5:", "calls an undefined block")
+package compilertest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+)
+
+// CompileFixture compiles the fixture in testdata/dir with opts and returns the error.
+func CompileFixture(dir string, opts compiler.Options) error {
+	return compiler.CompileWithOptions(filepath.Join("testdata", dir), opts)
+}
+
+// AssertErrorContains fails the test unless err is set and contains every want.
+func AssertErrorContains(t testing.TB, err error, want ...string) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("expected the compilation to fail")
+	}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("expected the error to contain %q, got:\n%s", w, err)
+		}
+	}
+}
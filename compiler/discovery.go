@@ -7,6 +7,8 @@ import (
 	"go/token"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 
 	"golang.org/x/net/html"
@@ -19,7 +21,8 @@ func discoverAndInspectComponents(rootDir string) ([]componentInfo, error) {
 
 	// Step 1: Load all packages in the module, configured for WASM.
 	cfg := &packages.Config{
-		Mode: packages.NeedName | packages.NeedFiles, // Request file info
+		// File info, plus the import graph the typeLoader type-checks field types from
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedModule,
 		Dir:  rootDir,
 		Env:  append(os.Environ(), "GOOS=js", "GOARCH=wasm"),
 	}
@@ -28,15 +31,20 @@ func discoverAndInspectComponents(rootDir string) ([]componentInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load packages: %w", err)
 	}
+	typeLoader := newTypeLoader(pkgs)
 
 	// Step 2: Iterate through the loaded packages.
 	for _, pkg := range pkgs {
-		if len(pkg.GoFiles) == 0 {
-			continue // Skip packages that are empty for the js/wasm target.
+		if len(pkg.GoFiles) == 0 && len(pkg.IgnoredFiles) == 0 {
+			continue // Skip directories without Go files.
 		}
 
-		// All files in a package share the same directory.
-		packageDir := filepath.Dir(pkg.GoFiles[0])
+		// All files in a package share the same directory. A package whose files are all
+		// excluded from js/wasm is still scanned, so its templates are reported below.
+		packageDir := pkg.Dir
+		if packageDir == "" {
+			packageDir = filepath.Dir(append(pkg.GoFiles, pkg.IgnoredFiles...)[0])
+		}
 
 		// Step 3: Scan the package's directory for component templates (*.gt.html).
 		files, err := os.ReadDir(packageDir)
@@ -53,16 +61,16 @@ func discoverAndInspectComponents(rootDir string) ([]componentInfo, error) {
 			// We found a component template.
 			templatePath := filepath.Join(packageDir, file.Name())
 			pascalName := strings.TrimSuffix(file.Name(), ".gt.html")
-			goFilePath := filepath.Join(packageDir, strings.ToLower(pascalName)+".go")
 
+			goFilePath, err := findComponentStruct(pkg, pascalName, templatePath)
+			if err != nil {
+				return nil, err
+			}
 			schema, err := inspectGoFile(goFilePath, pascalName)
 			if err != nil {
-				fmt.Printf("Warning: could not inspect Go file %s: %v\n", goFilePath, err)
-				schema = componentSchema{
-					Props:   make(map[string]propertyDescriptor),
-					Methods: make(map[string]methodDescriptor),
-				}
+				return nil, fmt.Errorf("compilation error: could not inspect Go file %s for component '%s': %w", goFilePath, pascalName, err)
 			}
+			qualifier := attachFieldTypes(&schema, typeLoader.load(pkg.PkgPath), pascalName, goFilePath)
 
 			components = append(components, componentInfo{
 				Path:          templatePath,
@@ -71,6 +79,7 @@ func discoverAndInspectComponents(rootDir string) ([]componentInfo, error) {
 				PackageName:   pkg.Name,    // Use the package name from the loader.
 				ImportPath:    pkg.PkgPath, // Full import path (e.g., "github.com/ForgeLogic/nojs/appcomponents")
 				Schema:        schema,
+				Qualifier:     qualifier,
 			})
 
 			// Validate that component name doesn't conflict with HTML tags
@@ -87,23 +96,149 @@ func discoverAndInspectComponents(rootDir string) ([]componentInfo, error) {
 	return components, nil
 }
 
-// collectUsedComponents walks the HTML tree and collects all components used from other packages.
-// Returns a map of package name to import path.
+// findComponentStruct returns the file declaring the exported struct of the component whose
+// template is templatePath. The conventional file (usercard.go for UserCard.gt.html) is
+// searched first, then every other file of the package built for js/wasm. When no file
+// declares it, the error explains what was searched and names the likely cause: a struct
+// that is unexported, excluded from js/wasm by build constraints, misspelled, or in a file
+// that does not parse.
+func findComponentStruct(pkg *packages.Package, structName, templatePath string) (string, error) {
+	conventional := strings.ToLower(structName) + ".go"
+	files := make([]string, 0, len(pkg.GoFiles))
+	for _, path := range pkg.GoFiles {
+		if filepath.Base(path) == conventional {
+			files = append([]string{path}, files...)
+		} else {
+			files = append(files, path)
+		}
+	}
+
+	var (
+		scanned     []string
+		parseErrors []string
+		structs     = make(map[string]string) // Struct name -> declaring file, across all files
+	)
+	for _, path := range files {
+		scanned = append(scanned, filepath.Base(path))
+		names, err := structNamesInFile(path)
+		if err != nil {
+			parseErrors = append(parseErrors, err.Error())
+			continue
+		}
+		for _, name := range names {
+			if name == structName {
+				return path, nil
+			}
+			structs[name] = path
+		}
+	}
+
+	var hints []string
+	for _, path := range pkg.IgnoredFiles {
+		names, err := structNamesInFile(path)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if name == structName {
+				hints = append(hints, fmt.Sprintf("'%s' is declared in %s, which build constraints exclude from the js/wasm build. Check the //go:build line of that file.",
+					structName, filepath.Base(path)))
+			}
+		}
+	}
+	for name, path := range structs {
+		if name != structName && strings.EqualFold(name, structName) && !ast.IsExported(name) {
+			hints = append(hints, fmt.Sprintf("'%s' in %s is unexported. Rename it to '%s'; component structs must be exported.",
+				name, filepath.Base(path), structName))
+		}
+	}
+	for _, parseError := range parseErrors {
+		hints = append(hints, "A file of the package could not be parsed: "+parseError)
+	}
+	if len(hints) == 0 {
+		if similar := findSimilarStructs(structName, structs); len(similar) > 0 {
+			hints = append(hints, fmt.Sprintf("Did you mean: %s? The template name must match the struct name exactly.", strings.Join(similar, ", ")))
+		} else {
+			hints = append(hints, fmt.Sprintf("Declare 'type %s struct' in %s, next to the template.", structName, conventional))
+		}
+	}
+
+	if len(scanned) == 0 {
+		scanned = []string{"(none)"}
+	}
+	return "", fmt.Errorf("compilation error: no exported struct '%s' found for template %s.\n"+
+		"Searched the js/wasm files of package %s: %s\n\n%s",
+		structName, templatePath, pkg.Name, strings.Join(scanned, ", "), strings.Join(hints, "\n"))
+}
+
+// structNamesInFile returns the names of the struct types declared in a Go file.
+func structNamesInFile(path string) ([]string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		if typeSpec, ok := n.(*ast.TypeSpec); ok {
+			if _, ok := typeSpec.Type.(*ast.StructType); ok {
+				names = append(names, typeSpec.Name.Name)
+			}
+		}
+		return true
+	})
+	return names, nil
+}
+
+// findSimilarStructs returns up to 3 struct names close to structName (edit distance <= 2,
+// ignoring case), closest first.
+func findSimilarStructs(structName string, structs map[string]string) []string {
+	const threshold = 2
+	var names []string
+	for name := range structs {
+		if levenshteinDistance(strings.ToLower(structName), strings.ToLower(name)) <= threshold {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		di := levenshteinDistance(strings.ToLower(structName), strings.ToLower(names[i]))
+		dj := levenshteinDistance(strings.ToLower(structName), strings.ToLower(names[j]))
+		if di != dj {
+			return di < dj
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > 3 {
+		names = names[:3]
+	}
+	for i, name := range names {
+		names[i] = "'" + name + "'"
+	}
+	return names
+}
+
+// generatedImportNames are the package names the generated file imports whatever the template,
+// or may import for the expressions it holds; a component package by one of these names is
+// imported under another.
+var generatedImportNames = map[string]bool{
+	"fmt": true, "strconv": true, "strings": true, "console": true, "events": true, "runtime": true, "vdom": true,
+	"time": true, "maps": true, "slices": true, "formatters": true,
+}
+
+// collectUsedComponents walks the HTML tree and collects the packages of the components it
+// uses from other packages. Returns a map of import path to the name the generated code refers
+// to the package by: its package name, unless a package imported before it (in import path
+// order) or by every generated file has that name, in which case a number is appended
+// (admin, admin2).
 func collectUsedComponents(n *html.Node, componentMap map[string]componentInfo, currentComp componentInfo) map[string]string {
-	usedPackages := make(map[string]string)
+	packageNames := make(map[string]string) // Import path -> package name
 
 	var walk func(*html.Node)
 	walk = func(node *html.Node) {
 		if node.Type == html.ElementNode {
-			tagName := node.Data
-			// Check if this is a component
-			if compInfo, isComponent := componentMap[tagName]; isComponent {
-				// Check if it's from a different package
-				if compInfo.PackageName != currentComp.PackageName {
-					// Need to import this package
-					// Store mapping: package name -> full import path
-					usedPackages[compInfo.PackageName] = compInfo.ImportPath
-				}
+			// Components of another package are told apart by import path: two packages can
+			// share a name, and one can share the name of the current package
+			if compInfo, isComponent := componentMap[node.Data]; isComponent && compInfo.ImportPath != currentComp.ImportPath {
+				packageNames[compInfo.ImportPath] = compInfo.PackageName
 			}
 		}
 
@@ -112,13 +247,33 @@ func collectUsedComponents(n *html.Node, componentMap map[string]componentInfo,
 			walk(c)
 		}
 	}
-
 	walk(n)
-	return usedPackages
+
+	importPaths := make([]string, 0, len(packageNames))
+	for importPath := range packageNames {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+
+	aliases := make(map[string]string, len(importPaths))
+	taken := make(map[string]bool)
+	for _, importPath := range importPaths {
+		name := packageNames[importPath]
+		alias := name
+		for i := 2; taken[alias] || generatedImportNames[alias]; i++ {
+			alias = fmt.Sprintf("%s%d", name, i)
+		}
+		taken[alias] = true
+		aliases[importPath] = alias
+	}
+	return aliases
 }
 
 // extractTypeName extracts the type name from an AST expression.
-// Handles simple types (int, string, bool), slice types ([]User), pointer types (*User), and function types.
+// Handles simple types (int, string, bool), slice types ([]User), map types
+// (map[string]User), pointer types (*User), generic instantiations (List[User]) and
+// function types. Aliases are written as declared;
+// attachFieldTypes replaces them with the aliased type once the package is type-checked.
 func extractTypeName(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.Ident:
@@ -128,6 +283,9 @@ func extractTypeName(expr ast.Expr) string {
 		// Slice or array type like "[]User"
 		elemType := extractTypeName(t.Elt)
 		return "[]" + elemType
+	case *ast.MapType:
+		// Map type like "map[string]User"
+		return "map[" + extractTypeName(t.Key) + "]" + extractTypeName(t.Value)
 	case *ast.StarExpr:
 		// Pointer type like "*User"
 		elemType := extractTypeName(t.X)
@@ -137,6 +295,16 @@ func extractTypeName(expr ast.Expr) string {
 		if ident, ok := t.X.(*ast.Ident); ok {
 			return ident.Name + "." + t.Sel.Name
 		}
+	case *ast.IndexExpr:
+		// Generic instantiation like "List[User]"
+		return extractTypeName(t.X) + "[" + extractTypeName(t.Index) + "]"
+	case *ast.IndexListExpr:
+		// Generic instantiation with several type arguments like "Pair[string, int]"
+		args := make([]string, len(t.Indices))
+		for i, index := range t.Indices {
+			args[i] = extractTypeName(index)
+		}
+		return extractTypeName(t.X) + "[" + strings.Join(args, ", ") + "]"
 	case *ast.FuncType:
 		// Function type like "func(result ModalResult)" or "func() string"
 		// We return a simplified representation that starts with "func"
@@ -257,6 +425,16 @@ func inspectStructInFile(path, structName string) (componentSchema, error) {
 	return schema, nil
 }
 
+// historyKeyOption returns the key of the history=key option of a nojs tag value.
+func historyKeyOption(value string) (string, bool) {
+	for _, option := range strings.Split(value, ",") {
+		if key, ok := strings.CutPrefix(strings.TrimSpace(option), "history="); ok {
+			return strings.TrimSpace(key), true
+		}
+	}
+	return "", false
+}
+
 // inspectGoFile parses a Go file and extracts the prop schema for a given struct.
 func inspectGoFile(path, structName string) (componentSchema, error) {
 	schema := componentSchema{
@@ -264,47 +442,95 @@ func inspectGoFile(path, structName string) (componentSchema, error) {
 		State:   make(map[string]propertyDescriptor),
 		Methods: make(map[string]methodDescriptor),
 		Slot:    nil,
+		Slots:   make(map[string]propertyDescriptor),
+		Refs:    make(map[string]propertyDescriptor),
 	}
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, path, nil, 0)
+	node, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
 	if err != nil {
 		return schema, err
 	}
 
-	var slotFields []propertyDescriptor // Track all slot fields for validation
+	var slotFields []propertyDescriptor    // Track all slot fields for validation
+	historyKeys := make(map[string]string) // History key -> field, to reject duplicates
+	var tagErr error
 
 	ast.Inspect(node, func(n ast.Node) bool {
+		// The struct's doc comment opts into memoization; a lone spec's is on the declaration
+		if genDecl, ok := n.(*ast.GenDecl); ok && genDecl.Tok == token.TYPE {
+			for _, spec := range genDecl.Specs {
+				if typeSpec := spec.(*ast.TypeSpec); typeSpec.Name.Name == structName {
+					schema.Memo = hasMemoMarker(typeSpec.Doc) || (len(genDecl.Specs) == 1 && hasMemoMarker(genDecl.Doc))
+				}
+			}
+		}
+
 		// Inspect for struct fields (Props)
 		if typeSpec, ok := n.(*ast.TypeSpec); ok && typeSpec.Name.Name == structName {
 			if structType, ok := typeSpec.Type.(*ast.StructType); ok {
 				for _, field := range structType.Fields.List {
+					if len(field.Names) == 0 && extractTypeName(field.Type) == formValidatorType {
+						schema.Form = true
+					}
 					if len(field.Names) > 0 && field.Names[0].IsExported() {
 						fieldName := field.Names[0].Name
 						goType := extractTypeName(field.Type)
 
 						// Check if field is marked as state via struct tag
 						isState := false
+						historyKey := ""
+						slotName := ""
 						if field.Tag != nil {
 							tag := field.Tag.Value
 							// Parse struct tag - remove surrounding backticks
 							if len(tag) >= 2 {
 								tag = tag[1 : len(tag)-1]
 							}
-							// Check for nojs:"state" tag
-							if strings.Contains(tag, `nojs:"state"`) {
+							// Check for nojs:"state" tag, with its options. Injected fields (nojs:"inject", and
+							// nojs:"inject:key" for context values) are set by the renderer, so like state they
+							// are bindable but never copied as props.
+							value := reflect.StructTag(tag).Get("nojs")
+							name, _, _ := strings.Cut(value, ",")
+							if name == "state" || name == "inject" || strings.HasPrefix(name, "inject:") {
 								isState = true
 							}
+							if after, ok := strings.CutPrefix(name, "slot:"); ok {
+								if after == "" || goType != "[]*vdom.VNode" {
+									tagErr = fmt.Errorf("field '%s' of component '%s': a named slot is a []*vdom.VNode field tagged with its name, as in `nojs:\"slot:header\"`", fieldName, structName)
+								} else if other, ok := schema.Slots[after]; ok {
+									tagErr = fmt.Errorf("fields '%s' and '%s' of component '%s' are both named slot %q", other.Name, fieldName, structName, after)
+								}
+								slotName = after
+							}
+							if key, ok := historyKeyOption(value); ok {
+								if name != "state" || key == "" {
+									tagErr = fmt.Errorf("field '%s' of component '%s': the history option needs a state field and a key, as in `nojs:\"state,history=key\"`", fieldName, structName)
+								}
+								historyKey = key
+							}
 						}
 
 						propDesc := propertyDescriptor{
 							Name:          fieldName,
 							LowercaseName: strings.ToLower(fieldName),
 							GoType:        goType,
+							HistoryKey:    historyKey,
+						}
+						if historyKey != "" {
+							if other, ok := historyKeys[historyKey]; ok {
+								tagErr = fmt.Errorf("fields '%s' and '%s' of component '%s' use the same history key %q", other, fieldName, structName, historyKey)
+							}
+							historyKeys[historyKey] = fieldName
 						}
 
-						// Check if this is a content slot field ([]*vdom.VNode)
-						if goType == "[]*vdom.VNode" {
+						// Check if this is a content slot field ([]*vdom.VNode), named by its tag or the default one
+						if slotName != "" {
+							schema.Slots[slotName] = propDesc
+						} else if goType == "[]*vdom.VNode" {
 							slotFields = append(slotFields, propDesc)
+						} else if goType == "vdom.ElementRef" || goType == "vdom.ElementRefs" {
+							// DOM ref field - populated by the renderer, not passed by parents
+							schema.Refs[strings.ToLower(fieldName)] = propDesc
 						} else if !isState {
 							// Regular prop field - only add if not marked as state
 							schema.Props[strings.ToLower(fieldName)] = propDesc
@@ -338,18 +564,21 @@ func inspectGoFile(path, structName string) (componentSchema, error) {
 		return true
 	})
 
-	// Validate single slot constraint
+	if tagErr != nil {
+		return schema, tagErr
+	}
+
+	// Validate single default slot constraint
 	if len(slotFields) > 1 {
 		var fieldNames []string
 		for _, sf := range slotFields {
 			fieldNames = append(fieldNames, sf.Name)
 		}
-		fmt.Fprintf(os.Stderr, "Compilation Error: could not inspect Go file %s: component '%s' has multiple content slot fields: [%s]. Only one []*vdom.VNode field is allowed per component\n",
+		return schema, fmt.Errorf("Compilation Error: could not inspect Go file %s: component '%s' has multiple content slot fields: [%s]. Only one []*vdom.VNode field is the default slot; name the others with a tag, as in `nojs:\"slot:header\"`\n",
 			path, structName, strings.Join(fieldNames, ", "))
-		os.Exit(1)
 	}
 
-	// Set the single slot field if found
+	// Set the default slot field if found
 	if len(slotFields) == 1 {
 		schema.Slot = &slotFields[0]
 	}
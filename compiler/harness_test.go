@@ -0,0 +1,298 @@
+//go:build !wasm
+// +build !wasm
+
+package compiler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// harnessModule is the module path of the temporary module the scenarios are compiled in.
+const harnessModule = "nojsharness.test"
+
+// compileScenario is one end-to-end compiler test: the sources of a package, what compiling
+// it must report, and a test run against the generated code.
+type compileScenario struct {
+	name    string
+	files   map[string]string // Path relative to the scenario directory -> content; subdirectories are packages of their own
+	options Options
+	wantErr []string // Substrings of the compile error; empty when the compile must succeed
+	warns   []string // Substrings of the dev warnings the compile must print
+	imports []string // Import paths the test body uses besides testing, rendertest and vdom
+	test    string   // Body of func TestScenario(t *testing.T) in the scenario package
+}
+
+// harnessRunner is the package, next to the scenarios in the temporary module, whose test
+// runs the TestScenario function of every scenario that built.
+const harnessRunner = "harnessrun"
+
+// scenarioTestHeader is the file wrapped around a scenario's test body. It is not a _test.go
+// file: the runner package imports TestScenario, so all scenarios share one test binary.
+// Besides rendertest, the body can use textOf, findTag and findAllTags.
+const scenarioTestHeader = `package %s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+%s)
+
+var _ = rendertest.FireEvent
+
+// textOf returns the text under n, concatenated in document order and trimmed.
+func textOf(n *vdom.VNode) string {
+	return strings.TrimSpace(rendertest.TextOf(n))
+}
+
+// findAllTags returns the elements named tag under root, in document order.
+func findAllTags(root *vdom.VNode, tag string) []*vdom.VNode {
+	var found []*vdom.VNode
+	var walk func(*vdom.VNode)
+	walk = func(n *vdom.VNode) {
+		if n == nil {
+			return
+		}
+		if n.Tag == tag {
+			found = append(found, n)
+		}
+		for _, child := range n.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	return found
+}
+
+// findTag returns the first element named tag under root; it fails t if there is none.
+func findTag(t *testing.T, root *vdom.VNode, tag string) *vdom.VNode {
+	t.Helper()
+	found := findAllTags(root, tag)
+	if len(found) == 0 {
+		t.Fatalf("no <%%s> in:\n%%s", tag, rendertest.FormatVNode(root))
+	}
+	return found[0]
+}
+
+func TestScenario(t *testing.T) {
+%s
+}
+`
+
+// runCompileScenarios compiles every scenario with the real compiler inside one temporary
+// module that replaces the nojs packages with this checkout, then builds the generated code
+// of all successful scenarios and runs their tests from a single test binary: linking one
+// binary per scenario would take most of the run. Packages come from the shared build cache,
+// so only the scenario packages are compiled on each run.
+func runCompileScenarios(t *testing.T, scenarios []compileScenario) {
+	t.Helper()
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	nojsDir, err := filepath.Abs(filepath.Join("..", "nojs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	moduleDir := t.TempDir()
+	goMod := fmt.Sprintf("module %s\n\ngo 1.25.1\n\nrequire github.com/ForgeLogic/nojs v0.0.0\n\nreplace github.com/ForgeLogic/nojs => %s\n", harnessModule, nojsDir)
+	if err := os.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The module is outside any workspace and must not reach the network
+	t.Setenv("GOWORK", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "off")
+
+	// Verifying the generated files of each scenario as it compiles would take most of the
+	// run, so they are collected and verified together once all scenarios are compiled
+	generated := make(map[string][]byte)
+	verifyPlatforms = func(srcDir string, files map[string][]byte) error {
+		for path, source := range files {
+			generated[path] = source
+		}
+		return nil
+	}
+	defer func() { verifyPlatforms = verifyGeneratedPlatforms }()
+
+	var executed []string // Names of the scenarios whose test runs
+	for _, sc := range scenarios {
+		dir := filepath.Join(moduleDir, sc.name)
+		if err := writeScenarioFiles(dir, sc.files); err != nil {
+			t.Fatalf("%s: %v", sc.name, err)
+		}
+		if !compileScenarioSources(t, sc, dir) || sc.test == "" {
+			continue
+		}
+		pkgName, err := scenarioPackageName(dir)
+		if err != nil {
+			t.Fatalf("%s: %v", sc.name, err)
+		}
+		var imports strings.Builder
+		for _, path := range sc.imports {
+			fmt.Fprintf(&imports, "\t%q\n", path)
+		}
+		source := fmt.Sprintf(scenarioTestHeader, pkgName, imports.String(), sc.test)
+		if err := os.WriteFile(filepath.Join(dir, "scenario_harness.go"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		executed = append(executed, sc.name)
+	}
+	if len(executed) == 0 {
+		return
+	}
+	if err := verifyGeneratedPlatforms(moduleDir, generated); err != nil {
+		t.Errorf("generated code does not compile on every platform: %v", err)
+	}
+
+	// A scenario whose generated code does not build is reported alone, and left out of the
+	// runner so that the others still run
+	build := exec.Command(goTool, "build", "-json", "./...")
+	build.Dir = moduleDir
+	buildOut, _ := build.CombinedOutput() // Failures are reported per scenario below
+	outputs, failed := parseTestEvents(buildOut)
+	var imports, runs strings.Builder
+	for i, name := range executed {
+		if !failed[name] {
+			fmt.Fprintf(&imports, "\ts%d %q\n", i, harnessModule+"/"+name)
+			fmt.Fprintf(&runs, "\tt.Run(%q, s%d.TestScenario)\n", name, i)
+		}
+	}
+	runner := fmt.Sprintf("package %s\n\nimport (\n\t\"testing\"\n\n%s)\n\nfunc TestScenarios(t *testing.T) {\n%s}\n", harnessRunner, imports.String(), runs.String())
+	if err := writeScenarioFiles(filepath.Join(moduleDir, harnessRunner), map[string]string{"runner_test.go": runner}); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goTool, "test", "-json", "./"+harnessRunner)
+	cmd.Dir = moduleDir
+	out, _ := cmd.CombinedOutput() // Failures are reported per scenario below
+	testOutputs, testFailed := parseTestEvents(out)
+	for name, output := range testOutputs {
+		outputs[name] += output
+		failed[name] = failed[name] || testFailed[name]
+	}
+	for _, name := range executed {
+		t.Run(name, func(t *testing.T) {
+			if failed[name] {
+				t.Errorf("generated code failed its test:\n%s", outputs[name])
+			} else if _, ran := outputs[name]; !ran {
+				t.Errorf("go test did not run the scenario:\n%s", out)
+			}
+		})
+	}
+}
+
+// compileScenarioSources compiles dir and checks the result against the scenario. It
+// reports whether the compile succeeded.
+func compileScenarioSources(t *testing.T, sc compileScenario, dir string) bool {
+	t.Helper()
+	var warnings bytes.Buffer
+	warningOutput = &warnings
+	defer func() { warningOutput = os.Stderr }()
+	err := CompileWithOptions(dir, sc.options)
+
+	t.Run(sc.name+"/compile", func(t *testing.T) {
+		switch {
+		case len(sc.wantErr) == 0 && err != nil:
+			t.Fatalf("expected the scenario to compile, got %v", err)
+		case len(sc.wantErr) > 0 && err == nil:
+			t.Fatalf("expected the compile to fail with %q", sc.wantErr)
+		}
+		for _, want := range sc.wantErr {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected the error to contain %q, got:\n%v", want, err)
+			}
+		}
+		for _, want := range sc.warns {
+			if !strings.Contains(warnings.String(), want) {
+				t.Errorf("expected the warnings to contain %q, got:\n%s", want, warnings.String())
+			}
+		}
+	})
+	return err == nil
+}
+
+// writeScenarioFiles writes files under dir, creating subdirectories as needed.
+func writeScenarioFiles(dir string, files map[string]string) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scenarioPackageName returns the package name declared by the Go files directly in dir.
+func scenarioPackageName(dir string) (string, error) {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, path := range matches {
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		return file.Name.Name, nil
+	}
+	return "", fmt.Errorf("no Go files in %s", dir)
+}
+
+// testEvent is the subset of a go build -json or go test -json event the harness reads.
+type testEvent struct {
+	Action     string
+	Package    string
+	ImportPath string // Set on build events
+	Test       string
+	Output     string
+}
+
+// parseTestEvents groups the output of go build -json or go test -json by scenario and
+// reports which scenarios failed: a package of theirs did not build, or their test failed.
+// Build events name a scenario by import path, test events by the subtest of the runner.
+func parseTestEvents(out []byte) (outputs map[string]string, failed map[string]bool) {
+	outputs = make(map[string]string)
+	failed = make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var event testEvent
+		if json.Unmarshal(scanner.Bytes(), &event) != nil {
+			continue // Not an event: go vet or linker output
+		}
+		var name string
+		if event.ImportPath != "" {
+			importPath, _, _ := strings.Cut(event.ImportPath, " ") // "path [path.test]"
+			name, _, _ = strings.Cut(strings.TrimPrefix(importPath, harnessModule+"/"), "/")
+		} else {
+			_, subtest, _ := strings.Cut(event.Test, "/") // "TestScenarios/<name>/..."
+			name, _, _ = strings.Cut(subtest, "/")
+		}
+		if name == "" || name == harnessRunner {
+			continue // The runner itself: its failure shows in the subtest that failed
+		}
+		outputs[name] += event.Output
+		if event.Action == "build-fail" || (event.Action == "fail" && event.Test == "TestScenarios/"+name) {
+			failed[name] = true
+		}
+	}
+	return outputs, failed
+}
@@ -2,100 +2,12 @@ package compiler
 
 import (
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
 
 	"golang.org/x/net/html"
 )
 
-// estimateLineNumber tries to find the approximate line number where text appears in HTML source.
-// For better accuracy with expressions containing special characters, it searches for distinctive parts.
-func estimateLineNumber(htmlSource, text string) int {
-	lines := strings.Split(htmlSource, "\n")
-
-	// First try: exact match
-	for i, line := range lines {
-		if strings.Contains(line, text) {
-			return i + 1
-		}
-	}
-
-	// Second try: if text contains ternary pattern, search for the ternary part
-	if strings.Contains(text, "?") && strings.Contains(text, ":") {
-		// Extract a distinctive part (the condition before '?')
-		if idx := strings.Index(text, "?"); idx > 0 {
-			// Get text around the '?' for better matching
-			start := strings.LastIndex(text[:idx], "{")
-			if start >= 0 {
-				searchText := text[start : idx+1] // Include { up to ?
-				for i, line := range lines {
-					if strings.Contains(line, searchText) {
-						return i + 1
-					}
-				}
-			}
-		}
-	}
-
-	// Fallback: search for any significant substring
-	trimmed := strings.TrimSpace(text)
-	if len(trimmed) > 10 {
-		searchText := trimmed[:10]
-		for i, line := range lines {
-			if strings.Contains(line, searchText) {
-				return i + 1
-			}
-		}
-	}
-
-	return 1 // Default to line 1 if not found
-}
-
-// estimateTextNodeLineNumber estimates the line number where a text node appears in the source.
-func estimateTextNodeLineNumber(htmlSource string, textContent string) int {
-	// Simple heuristic: find the line containing the trimmed text
-	trimmed := strings.TrimSpace(textContent)
-	if trimmed == "" {
-		return 0
-	}
-
-	lines := strings.Split(htmlSource, "\n")
-	for i, line := range lines {
-		if strings.Contains(line, trimmed) {
-			return i + 1 // 1-indexed
-		}
-	}
-	return 0
-}
-
-// // estimateComponentTagLineNumber finds the line number of a component tag that contains specific text.
-// // This is more accurate than estimateLineNumber for finding the right occurrence.
-// func estimateComponentTagLineNumber(htmlSource string, n *html.Node, componentName string) int {
-// 	// Try to find unique characteristics of this specific component usage
-// 	// Look for attributes that make it unique
-// 	var uniqueAttr string
-// 	for _, attr := range n.Attr {
-// 		if attr.Key == "title" || strings.HasPrefix(attr.Key, "Title") {
-// 			uniqueAttr = attr.Val
-// 			break
-// 		}
-// 	}
-
-// 	searchPattern := fmt.Sprintf("<%s", componentName)
-// 	if uniqueAttr != "" {
-// 		// Search for the tag with this specific attribute
-// 		lines := strings.Split(htmlSource, "\n")
-// 		for i, line := range lines {
-// 			if strings.Contains(line, searchPattern) && strings.Contains(line, uniqueAttr) {
-// 				return i + 1 // 1-indexed
-// 			}
-// 		}
-// 	}
-
-// 	// Fallback to simple search
-// 	return estimateLineNumber(htmlSource, searchPattern)
-// }
-
 // getSourceLine returns the source line at the given line number (1-indexed).
 // func getSourceLine(htmlSource string, lineNum int) string {
 // 	lines := strings.Split(htmlSource, "\n")
@@ -156,25 +68,10 @@ func getAvailableMethodNames(methods map[string]methodDescriptor) string {
 	return strings.Join(names, ", ")
 }
 
-// findEventLineNumber finds the line number where an event attribute is defined.
-func findEventLineNumber(n *html.Node, eventName, htmlSource string) int {
-	// Look for the event attribute pattern: @eventName="..."
-	// We need to find the element's tag and then the specific event attribute
-	tagName := n.Data
-
-	// Create a pattern to find this specific element with the event attribute
-	// This is a simplified approach - it finds the first occurrence
-	pattern := fmt.Sprintf(`(?i)<%s[^>]*@%s\s*=`, regexp.QuoteMeta(tagName), regexp.QuoteMeta(eventName))
-	re := regexp.MustCompile(pattern)
-	matchIndex := re.FindStringIndex(htmlSource)
-
-	if matchIndex == nil {
-		return 1 // Default to line 1 if not found
-	}
-
-	// Count newlines before the match to get the line number
-	lineNumber := strings.Count(htmlSource[:matchIndex[0]], "\n") + 1
-	return lineNumber
+// jsEventProp converts an event name to the camelCase key its handler is stored under in a
+// VNode's event map (e.g., "onclick" -> "onClick").
+func jsEventProp(eventName string) string {
+	return "on" + strings.ToUpper(eventName[2:3]) + eventName[3:]
 }
 
 // findBody finds the <body> node in the parsed HTML.
@@ -222,3 +119,16 @@ func findFirstElementChild(n *html.Node) *html.Node {
 
 // 	return count
 // }
+
+// generateDevWarning returns the dev-mode console.Warn call logging message. The message is
+// emitted as a quoted Go literal, never as a format string, so quotes, backslashes, '%' and
+// newlines in the names it mentions cannot break the generated file or the warning.
+func generateDevWarning(message string) string {
+	return fmt.Sprintf("console.Warn(%s)", strconv.Quote(message))
+}
+
+// escapeFormatText escapes the '%' of static text copied into a generated fmt.Sprintf format
+// string, so it prints literally instead of being read as a verb.
+func escapeFormatText(text string) string {
+	return strings.ReplaceAll(text, "%", "%%")
+}
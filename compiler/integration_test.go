@@ -0,0 +1,3305 @@
+//go:build !wasm
+// +build !wasm
+
+package compiler
+
+import "testing"
+
+// TestCompiler_GeneratedCodeRuns compiles each scenario with the real compiler and runs a
+// test against the generated Render, so codegen changes are checked on executed code.
+func TestCompiler_GeneratedCodeRuns(t *testing.T) {
+	runCompileScenarios(t, []compileScenario{
+		{
+			name: "clickcounter",
+			files: map[string]string{
+				"counter.go": `package clickcounter
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Counter struct {
+	runtime.ComponentBase
+	Count int
+}
+
+func (c *Counter) Increment() {
+	c.Count++
+	c.StateHasChanged()
+}
+`,
+				"Counter.gt.html": `<div>
+    <p>Count: {Count}</p>
+    <button @onclick="Increment">Add</button>
+</div>
+`,
+			},
+			test: `
+	renderer := rendertest.NewTestRenderer(&Counter{})
+	root := renderer.RenderRoot()
+
+	rendertest.FireEvent(t, findTag(t, root, "button"), "click", nil)
+	rendertest.FireEvent(t, findTag(t, renderer.GetCurrentVDOM(), "button"), "click", nil)
+
+	if got := textOf(findTag(t, renderer.GetCurrentVDOM(), "p")); got != "Count: 2" {
+		t.Errorf("expected Count: 2 after two clicks, got %q", got)
+	}`,
+		},
+		{
+			name: "inputbinding",
+			files: map[string]string{
+				"greeter.go": `package inputbinding
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Greeter struct {
+	runtime.ComponentBase
+	Name string
+}
+
+func (c *Greeter) Edit(e events.ChangeEventArgs) {
+	c.Name = e.Value
+	c.StateHasChanged()
+}
+`,
+				"Greeter.gt.html": `<div>
+    <input type="text" value="{Name}" @oninput="Edit" />
+    <p>Hello, {Name}!</p>
+</div>
+`,
+			},
+			imports: []string{"github.com/ForgeLogic/nojs/events"},
+			test: `
+	renderer := rendertest.NewTestRenderer(&Greeter{})
+	root := renderer.RenderRoot()
+
+	rendertest.FireEvent(t, findTag(t, root, "input"), "input", events.ChangeEventArgs{Value: "Ada"})
+
+	root = renderer.GetCurrentVDOM()
+	if got := textOf(findTag(t, root, "p")); got != "Hello, Ada!" {
+		t.Errorf("expected the greeting to follow the input, got %q", got)
+	}
+	if got := findTag(t, root, "input").Attributes["value"]; got != "Ada" {
+		t.Errorf("expected the input value Ada, got %v", got)
+	}`,
+		},
+		{
+			name: "looptrackby",
+			files: map[string]string{
+				"list.go": `package looptrackby
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Item struct {
+	ID   int
+	Name string
+}
+
+type TodoList struct {
+	runtime.ComponentBase
+	Items []Item
+}
+`,
+				"TodoList.gt.html": `<ul>
+    {@for i, item := range Items trackBy item.ID}
+        <li>{i}: {item.Name}</li>
+    {@endfor}
+</ul>
+`,
+			},
+			test: `
+	list := &TodoList{Items: []Item{{ID: 1, Name: "write"}, {ID: 2, Name: "test"}}}
+	renderer := rendertest.NewTestRenderer(list)
+	renderer.RenderRoot()
+
+	list.Items = append([]Item{{ID: 3, Name: "plan"}}, list.Items...)
+	renderer.ReRender()
+
+	items := findAllTags(renderer.GetCurrentVDOM(), "li")
+	want := []string{"0: plan", "1: write", "2: test"}
+	if len(items) != len(want) {
+		t.Fatalf("expected %d items, got %d", len(want), len(items))
+	}
+	for i, item := range items {
+		if got := textOf(item); got != want[i] {
+			t.Errorf("item %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+	if keys := []any{items[0].Key, items[1].Key, items[2].Key}; keys[0] != 3 || keys[1] != 1 || keys[2] != 2 {
+		t.Errorf("expected the items keyed by ID 3, 1, 2, got %v", keys)
+	}`,
+		},
+		{
+			name: "nestedslices",
+			files: map[string]string{
+				"board.go": `package nestedslices
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Board struct {
+	runtime.ComponentBase
+	Squares [][]string
+}
+`,
+				"Board.gt.html": `<div>
+    {@for _, rank := range Squares trackBy rank}
+        <ul>
+            {@for file, piece := range rank trackBy piece}
+                <li>{file}{piece}</li>
+            {@endfor}
+        </ul>
+    {@endfor}
+</div>
+`,
+			},
+			test: `
+	board := &Board{Squares: [][]string{{"K", "Q"}, {"P"}}}
+	root := rendertest.NewTestRenderer(board).RenderRoot()
+
+	ranks := findAllTags(root, "ul")
+	if len(ranks) != 2 || textOf(ranks[0]) != "0K1Q" || textOf(ranks[1]) != "0P" {
+		t.Errorf("expected the ranks 0K1Q and 0P, got:\n%s", rendertest.FormatVNode(root))
+	}`,
+		},
+		{
+			name: "nestedrows",
+			files: map[string]string{
+				"grid.go": `package nestedrows
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Row struct {
+	ID    int
+	Cells []string
+}
+
+type Grid struct {
+	runtime.ComponentBase
+	Title string
+	Rows  []Row
+}
+`,
+				"Grid.gt.html": `<div>
+    {@for r, row := range Rows trackBy row.ID}
+        <ul>
+            {@for col, cell := range row.Cells trackBy cell}
+                <li>{Title} {r}.{col}={cell}</li>
+            {@endfor}
+        </ul>
+    {@endfor}
+</div>
+`,
+			},
+			test: `
+	grid := &Grid{Title: "T", Rows: []Row{{ID: 1, Cells: []string{"a", "b"}}, {ID: 2, Cells: []string{"c"}}}}
+	root := rendertest.NewTestRenderer(grid).RenderRoot()
+
+	want := []string{"T 0.0=a", "T 0.1=b", "T 1.0=c"}
+	cells := findAllTags(root, "li")
+	if len(cells) != len(want) {
+		t.Fatalf("expected %d cells, got:\n%s", len(want), rendertest.FormatVNode(root))
+	}
+	for i, cell := range cells {
+		if got := textOf(cell); got != want[i] {
+			t.Errorf("cell %d: expected %q, got %q", i, want[i], got)
+		}
+	}`,
+		},
+		{
+			name: "nestedloopfield",
+			files: map[string]string{
+				"coursemenu.go": `package nestedloopfield
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Dish struct{ Name string }
+
+type Course struct {
+	Name   string
+	Dishes []Dish
+}
+
+type CourseMenu struct {
+	runtime.ComponentBase
+	Courses []Course
+}
+`,
+				"CourseMenu.gt.html": `<div>
+    {@for _, course := range Courses trackBy course.Name}
+        {@for _, dish := range course.Plates trackBy dish.Name}
+            <p>{dish.Name}</p>
+        {@endfor}
+    {@endfor}
+</div>
+`,
+			},
+			wantErr: []string{"CourseMenu.gt.html", "Field 'course.Plates' not resolvable on loop variable 'course' of type 'Course'"},
+		},
+		{
+			name: "nestedrange",
+			files: map[string]string{
+				"checkout.go": `package nestedrange
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type LineItem struct {
+	SKU      string
+	Quantity int
+}
+
+type Cart struct {
+	Owner string
+	Items []LineItem
+}
+
+type Checkout struct {
+	runtime.ComponentBase
+	Cart Cart
+}
+`,
+				"Checkout.gt.html": `<ul>
+    {@for _, item := range Cart.Items trackBy item.SKU}
+        <li>{item.SKU} x{item.Quantity}</li>
+    {@endfor}
+</ul>
+`,
+			},
+			test: `
+	checkout := &Checkout{Cart: Cart{Items: []LineItem{{SKU: "pen", Quantity: 2}, {SKU: "ink", Quantity: 1}}}}
+	root := rendertest.NewTestRenderer(checkout).RenderRoot()
+
+	items := findAllTags(root, "li")
+	if len(items) != 2 || textOf(items[0]) != "pen x2" || textOf(items[1]) != "ink x1" {
+		t.Errorf("expected the cart items in order, got:\n%s", rendertest.FormatVNode(root))
+	}`,
+		},
+		{
+			name: "nestedrangetypo",
+			files: map[string]string{
+				"checkout.go": `package nestedrangetypo
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type LineItem struct{ SKU string }
+
+type Cart struct {
+	Owner string
+	Items []LineItem
+}
+
+type Checkout struct {
+	runtime.ComponentBase
+	Cart Cart
+}
+`,
+				"Checkout.gt.html": `<ul>
+    {@for _, item := range Cart.Lines trackBy item.SKU}
+        <li>{item.SKU}</li>
+    {@endfor}
+</ul>
+`,
+			},
+			wantErr: []string{"Field 'Cart.Lines' not resolvable on component 'Checkout'", "Available fields on Cart: [Owner, Items]"},
+		},
+		{
+			name: "maprange",
+			files: map[string]string{
+				"inventory.go": `package maprange
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Shelf struct{ Aisle int }
+
+type Inventory struct {
+	runtime.ComponentBase
+	Stock   map[string]int
+	Shelves map[string]*Shelf
+}
+`,
+				"Inventory.gt.html": `<div>
+    <ul>
+        {@for name, count := range Stock trackBy name}
+            <li>{name}: {count}</li>
+        {@endfor}
+    </ul>
+    <ol>
+        {@for _, shelf := range Shelves trackBy shelf.Aisle}
+            <li>{shelf.Aisle}</li>
+        {@endfor}
+    </ol>
+</div>
+`,
+			},
+			test: `
+	inventory := &Inventory{
+		Stock:   map[string]int{"pears": 3, "apples": 5},
+		Shelves: map[string]*Shelf{"b": {Aisle: 2}, "a": {Aisle: 1}, "c": nil},
+	}
+	renderer := rendertest.NewTestRenderer(inventory)
+	renderer.RenderRoot()
+
+	inventory.Stock["figs"] = 0
+	renderer.ReRender()
+
+	root := renderer.GetCurrentVDOM()
+	want := []string{"apples: 5", "figs: 0", "pears: 3"}
+	items := findAllTags(findTag(t, root, "ul"), "li")
+	if len(items) != len(want) {
+		t.Fatalf("expected %d entries, got:\n%s", len(want), rendertest.FormatVNode(root))
+	}
+	for i, item := range items {
+		if got := textOf(item); got != want[i] {
+			t.Errorf("entry %d: expected %q in key order, got %q", i, want[i], got)
+		}
+	}
+	shelves := findAllTags(findTag(t, root, "ol"), "li")
+	if len(shelves) != 2 || textOf(shelves[0]) != "1" || textOf(shelves[1]) != "2" {
+		t.Errorf("expected the shelves in key order without the nil one, got:\n%s", rendertest.FormatVNode(root))
+	}`,
+		},
+		{
+			name: "maprangekeys",
+			files: map[string]string{
+				"flags.go": `package maprangekeys
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Flags struct {
+	runtime.ComponentBase
+	Labels map[bool]string
+}
+`,
+				"Flags.gt.html": `<ul>
+    {@for on, label := range Labels trackBy on}
+        <li>{label}</li>
+    {@endfor}
+</ul>
+`,
+			},
+			wantErr: []string{"Field 'Labels' has type 'map[bool]string': {@for} over a map needs string or number keys"},
+		},
+		{
+			name: "conditionals",
+			files: map[string]string{
+				"inbox.go": `package conditionals
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Inbox struct {
+	runtime.ComponentBase
+	Messages    []string
+	HasMessages bool
+	Unread      bool
+}
+`,
+				"Inbox.gt.html": `<section>
+    {@if Unread}
+        <span>New mail</span>
+    {@endif}
+    {@if HasMessages}
+        <ul>
+            {@for _, m := range Messages trackBy m}
+                <li>{m}</li>
+            {@endfor}
+        </ul>
+    {@else}
+        <p>Inbox empty</p>
+    {@endif}
+</section>
+`,
+			},
+			test: `
+	inbox := &Inbox{}
+	renderer := rendertest.NewTestRenderer(inbox)
+	root := renderer.RenderRoot()
+	if len(findAllTags(root, "span")) != 0 || textOf(findTag(t, root, "p")) != "Inbox empty" {
+		t.Fatalf("expected only the empty state, got:\n%s", rendertest.FormatVNode(root))
+	}
+
+	inbox.Messages = []string{"hi"}
+	inbox.HasMessages = true
+	inbox.Unread = true
+	renderer.ReRender()
+
+	root = renderer.GetCurrentVDOM()
+	if len(findAllTags(root, "span")) != 1 || len(findAllTags(root, "li")) != 1 || len(findAllTags(root, "p")) != 0 {
+		t.Errorf("expected the unread marker and one message, got:\n%s", rendertest.FormatVNode(root))
+	}`,
+		},
+		{
+			name: "ifcomparisons",
+			files: map[string]string{
+				"dashboard.go": `package ifcomparisons
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Profile struct{ Age int }
+
+type Dashboard struct {
+	runtime.ComponentBase
+	Count  int
+	Status string
+	User   *Profile
+	Items  []string
+}
+`,
+				"Dashboard.gt.html": `<div>
+    {@if Count > 0}
+        <span>positive</span>
+    {@else if Count == 0}
+        <span>zero</span>
+    {@else}
+        <span>negative</span>
+    {@endif}
+    {@if Status == 'active'}
+        <p>Active</p>
+    {@endif}
+    {@if User.Age >= 18}
+        <h2>Adult</h2>
+    {@endif}
+    {@if len(Items) == 0}
+        <h3>Empty</h3>
+    {@endif}
+</div>
+`,
+			},
+			test: `
+	dashboard := &Dashboard{}
+	renderer := rendertest.NewTestRenderer(dashboard)
+	root := renderer.RenderRoot()
+	if textOf(findTag(t, root, "span")) != "zero" || len(findAllTags(root, "p")) != 0 ||
+		len(findAllTags(root, "h2")) != 0 || len(findAllTags(root, "h3")) != 1 {
+		t.Fatalf("expected only zero and Empty, got:\n%s", rendertest.FormatVNode(root))
+	}
+
+	dashboard.Count = -2
+	dashboard.Status = "active"
+	dashboard.User = &Profile{Age: 30}
+	dashboard.Items = []string{"a"}
+	renderer.ReRender()
+
+	root = renderer.GetCurrentVDOM()
+	if textOf(findTag(t, root, "span")) != "negative" || len(findAllTags(root, "p")) != 1 ||
+		len(findAllTags(root, "h2")) != 1 || len(findAllTags(root, "h3")) != 0 {
+		t.Errorf("expected negative, Active and Adult, got:\n%s", rendertest.FormatVNode(root))
+	}`,
+		},
+		{
+			name: "ifmixedtypes",
+			files: map[string]string{
+				"gauge.go": `package ifmixedtypes
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Gauge struct {
+	runtime.ComponentBase
+	Level int
+}
+`,
+				"Gauge.gt.html": `<div>
+    {@if Level == 'high'}
+        <span>High</span>
+    {@endif}
+</div>
+`,
+			},
+			wantErr: []string{"Gauge.gt.html:2", "cannot compare number field 'Level' (type 'int') with string literal 'high'"},
+		},
+		{
+			name: "iflenoperand",
+			files: map[string]string{
+				"gauge.go": `package iflenoperand
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Meter struct {
+	runtime.ComponentBase
+	Level int
+}
+`,
+				"Meter.gt.html": `<div>
+    <span>Meter</span>
+    {@if len(Level) > 2}
+        <span>High</span>
+    {@endif}
+</div>
+`,
+			},
+			wantErr: []string{"Meter.gt.html:3", "len() needs a slice, map or string field, found 'Level' of type 'int'"},
+		},
+		{
+			name: "logicalconditions",
+			files: map[string]string{
+				"access.go": `package logicalconditions
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Access struct {
+	runtime.ComponentBase
+	IsLoggedIn bool
+	IsAdmin    bool
+	HasError   bool
+	IsEmpty    bool
+	Count      int
+}
+`,
+				"Access.gt.html": `<div>
+    {@if IsLoggedIn && IsAdmin}
+        <h1>Admin</h1>
+    {@endif}
+    {@if HasError || IsEmpty}
+        <h2>Nothing to show</h2>
+    {@endif}
+    {@if !(IsLoggedIn || IsAdmin) && Count > 0}
+        <h3>Anonymous</h3>
+    {@endif}
+    <button disabled="{!IsLoggedIn || HasError}" class="{IsAdmin && !HasError ? 'admin' : 'user'}">Go</button>
+</div>
+`,
+			},
+			test: `
+	access := &Access{Count: 1}
+	renderer := rendertest.NewTestRenderer(access)
+	root := renderer.RenderRoot()
+	button := findTag(t, root, "button")
+	if len(findAllTags(root, "h1")) != 0 || len(findAllTags(root, "h2")) != 0 || len(findAllTags(root, "h3")) != 1 ||
+		button.Attributes["disabled"] != true || button.Attributes["class"] != "user" {
+		t.Fatalf("expected the anonymous view, got:\n%s", rendertest.FormatVNode(root))
+	}
+
+	access.IsLoggedIn, access.IsAdmin, access.IsEmpty = true, true, true
+	renderer.ReRender()
+
+	root = renderer.GetCurrentVDOM()
+	button = findTag(t, root, "button")
+	if len(findAllTags(root, "h1")) != 1 || len(findAllTags(root, "h2")) != 1 || len(findAllTags(root, "h3")) != 0 ||
+		button.Attributes["disabled"] != false || button.Attributes["class"] != "admin" {
+		t.Errorf("expected the admin view, got:\n%s", rendertest.FormatVNode(root))
+	}`,
+		},
+		{
+			name: "logicalconditiontype",
+			files: map[string]string{
+				"toolbar.go": `package logicalconditiontype
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Toolbar struct {
+	runtime.ComponentBase
+	IsLocked bool
+	Count    int
+}
+`,
+				"Toolbar.gt.html": `<div>
+    <button disabled="{IsLocked || Count}">Save</button>
+</div>
+`,
+			},
+			wantErr: []string{"Toolbar.gt.html:2", "Condition 'Count' must be a bool field, found type 'int'"},
+		},
+		{
+			name: "logicalconditionparens",
+			files: map[string]string{
+				"toolbar.go": `package logicalconditionparens
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Sidebar struct {
+	runtime.ComponentBase
+	IsOpen   bool
+	IsPinned bool
+}
+`,
+				"Sidebar.gt.html": `<div>
+    {@if (IsOpen || IsPinned}
+        <span>Visible</span>
+    {@endif}
+</div>
+`,
+			},
+			wantErr: []string{"Sidebar.gt.html:2", "Invalid condition '(IsOpen || IsPinned': missing ')'"},
+		},
+		{
+			name: "ifmultinode",
+			files: map[string]string{
+				"notice.go": `package ifmultinode
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Notice struct {
+	runtime.ComponentBase
+	Expanded bool
+	Verbose  bool
+	Lines    []string
+}
+`,
+				"Notice.gt.html": `<div>
+    {@if Expanded}
+        <h2>Details</h2>
+        <p>Everything you need to know.</p>
+    {@else}
+        <h2>Summary</h2>
+    {@endif}
+    <ul>
+        {@for _, line := range Lines trackBy line}
+        {@if Verbose}
+        <li>Line</li>
+        <li>{line}</li>
+        {@endif}
+        {@endfor}
+    </ul>
+    <footer>
+        {@if Verbose}
+        {@endif}
+        <span>End</span>
+        <p>{@if Verbose}<span>Verbose</span>{@endif}</p>
+    </footer>
+</div>
+`,
+			},
+			test: `
+	notice := &Notice{Lines: []string{"a", "b"}}
+	renderer := rendertest.NewTestRenderer(notice)
+	root := renderer.RenderRoot()
+	if len(root.Children) != 3 || root.Children[0].Tag != "h2" || textOf(root.Children[0]) != "Summary" ||
+		len(findAllTags(root, "li")) != 0 {
+		t.Fatalf("expected the summary and an empty list, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if footer := findTag(t, root, "footer"); len(footer.Children) != 2 || footer.Children[0].Tag != "span" || len(footer.Children[1].Children) != 0 {
+		t.Errorf("expected the empty branch to leave only the span, got:\n%s", rendertest.FormatVNode(footer))
+	}
+
+	notice.Expanded, notice.Verbose = true, true
+	renderer.ReRender()
+
+	root = renderer.GetCurrentVDOM()
+	if len(root.Children) != 4 || root.Children[0].Tag != "h2" || root.Children[1].Tag != "p" {
+		t.Fatalf("expected the heading and the paragraph, got:\n%s", rendertest.FormatVNode(root))
+	}
+	items := findAllTags(root, "li")
+	if len(items) != 4 || textOf(items[1]) != "a" || textOf(items[3]) != "b" {
+		t.Errorf("expected two items per line, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if len(findAllTags(root, "span")) != 2 {
+		t.Errorf("expected the branch inside the paragraph, got:\n%s", rendertest.FormatVNode(root))
+	}`,
+		},
+		{
+			name: "ifrootbranches",
+			files: map[string]string{
+				"banner.go": `package ifrootbranches
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Banner struct {
+	runtime.ComponentBase
+	Visible bool
+}
+`,
+				"Banner.gt.html": `{@if Visible}
+    <h2>Sale</h2>
+    <p>Everything must go.</p>
+{@endif}
+`,
+			},
+			wantErr: []string{"Banner.gt.html:1", "{@if Visible} at the root of the template must render a single element"},
+		},
+		{
+			name: "methodbinding",
+			files: map[string]string{
+				"card.go": `package methodbinding
+
+import (
+	"fmt"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Card struct {
+	runtime.ComponentBase
+	First string
+	Last  string
+	Price float64
+	Tags  []string
+}
+
+func (c *Card) FullName() string { return c.First + " " + c.Last }
+
+func (c *Card) FormattedPrice() string { return fmt.Sprintf("$%.2f", c.Price) }
+
+func (c *Card) TagCount() int { return len(c.Tags) }
+
+func (c *Card) Slug() string { return "/people/" + c.Last }
+`,
+				"Card.gt.html": `<div>
+    <h2 title="{FullName()}">{FullName()}</h2>
+    <span>{FormattedPrice()} with {TagCount()} tags</span>
+    <a href="{Slug()}" class="card-{TagCount()}">Profile</a>
+    <ul>
+        {@for _, tag := range Tags trackBy tag}
+        <li>{tag} of {FullName()}</li>
+        {@endfor}
+    </ul>
+</div>
+`,
+			},
+			test: `
+	card := &Card{First: "Ada", Last: "Lovelace", Price: 12.5, Tags: []string{"math"}}
+	root := rendertest.NewTestRenderer(card).RenderRoot()
+	heading := findTag(t, root, "h2")
+	if textOf(heading) != "Ada Lovelace" || heading.Attributes["title"] != "Ada Lovelace" {
+		t.Errorf("expected the full name, got:\n%s", rendertest.FormatVNode(heading))
+	}
+	if got := textOf(findTag(t, root, "span")); got != "$12.50 with 1 tags" {
+		t.Errorf("expected the computed values, got %q", got)
+	}
+	link := findTag(t, root, "a")
+	if link.Attributes["href"] != "/people/Lovelace" || link.Attributes["class"] != "card-1" {
+		t.Errorf("expected the computed attributes, got:\n%s", rendertest.FormatVNode(link))
+	}
+	if got := textOf(findTag(t, root, "li")); got != "math of Ada Lovelace" {
+		t.Errorf("expected the method called inside the loop, got %q", got)
+	}`,
+		},
+		{
+			name: "methodbindingunknown",
+			files: map[string]string{
+				"badge.go": `package methodbindingunknown
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Badge struct {
+	runtime.ComponentBase
+	Name string
+}
+
+func (b *Badge) Initials() string { return b.Name[:1] }
+`,
+				"Badge.gt.html": `<div>
+    <span>{Initals()}</span>
+</div>
+`,
+			},
+			wantErr: []string{"Badge.gt.html:2", "Method 'Initals' not found on component 'Badge'", "Available methods: Initials"},
+		},
+		{
+			name: "methodbindingparams",
+			files: map[string]string{
+				"greeter.go": `package methodbindingparams
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Greeter struct {
+	runtime.ComponentBase
+	Name string
+}
+
+func (g *Greeter) Greeting(prefix string) string { return prefix + g.Name }
+`,
+				"Greeter.gt.html": `<div>
+    <a href="#" title="{Greeting()}">Hi</a>
+</div>
+`,
+			},
+			wantErr: []string{"Greeter.gt.html:2", "Method 'Greeting' takes (string), but a bound method must take no parameters"},
+		},
+		{
+			name: "bindingformatters",
+			files: map[string]string{
+				"invoice.go": `package bindingformatters
+
+import (
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Cents int
+
+type Invoice struct {
+	runtime.ComponentBase
+	Title    string
+	Customer *Customer
+	Issued   time.Time
+	Total    float64
+	Items    Cents
+	Discount *float64
+}
+
+type Customer struct {
+	Email string
+}
+`,
+				"Invoice.gt.html": `<div>
+    <h1>{Title | upper}</h1>
+    <span class="email">{Customer.Email | lower}</span>
+    <span class="issued">{Issued | date "Jan 2, 2006"} ({Issued | date "Mon" | upper})</span>
+    <span class="total">{Total | number 2} for {Items | number 0} items</span>
+    <span class="discount">{Discount | number 1 | default '—'}</span>
+    <span class="legacy">{Total|printf:'%.1f'|default:'n/a'}</span>
+</div>
+`,
+			},
+			test: `
+	invoice := &Invoice{Title: "March invoice", Issued: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC), Total: 1234.5, Items: 3}
+	renderer := rendertest.NewTestRenderer(invoice)
+	root := renderer.RenderRoot()
+	spans := findAllTags(root, "span")
+	want := []string{"", "Mar 4, 2024 (MON)", "1234.50 for 3 items", "—", "1234.5"}
+	if textOf(findTag(t, root, "h1")) != "MARCH INVOICE" || len(spans) != len(want) {
+		t.Fatalf("expected the formatted invoice, got:\n%s", rendertest.FormatVNode(root))
+	}
+	for i, span := range spans {
+		if got := textOf(span); got != want[i] {
+			t.Errorf("expected %s to render %q, got %q", span.Attributes["class"], want[i], got)
+		}
+	}
+
+	discount := 12.25
+	invoice.Customer, invoice.Discount = &Customer{Email: "Ada@Example.com"}, &discount
+	renderer.ReRender()
+
+	spans = findAllTags(renderer.GetCurrentVDOM(), "span")
+	if textOf(spans[0]) != "ada@example.com" || textOf(spans[3]) != "12.2" {
+		t.Errorf("expected the email and the discount, got:\n%s", rendertest.FormatVNode(renderer.GetCurrentVDOM()))
+	}`,
+			imports: []string{"time"},
+		},
+		{
+			name: "bindingformatterunknown",
+			files: map[string]string{
+				"headline.go": `package bindingformatterunknown
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Headline struct {
+	runtime.ComponentBase
+	Text string
+}
+`,
+				"Headline.gt.html": `<div>
+    <span>{Text | capitalize}</span>
+</div>
+`,
+			},
+			wantErr: []string{"Headline.gt.html:2", "Unknown filter 'capitalize' on 'Text'. Supported filters: printf, date, number, upper, lower, default"},
+		},
+		{
+			name: "bindingformatterargs",
+			files: map[string]string{
+				"gauge.go": `package bindingformatterargs
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Gauge struct {
+	runtime.ComponentBase
+	Level float64
+}
+`,
+				"Gauge.gt.html": `<div>
+    <span>{Level | number}</span>
+</div>
+`,
+			},
+			wantErr: []string{"Gauge.gt.html:2", "The number filter on 'Level' takes 1 argument(s), but 0 were given"},
+		},
+		{
+			name: "bindingformattertype",
+			files: map[string]string{
+				"tally.go": `package bindingformattertype
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Tally struct {
+	runtime.ComponentBase
+	Count int
+}
+`,
+				"Tally.gt.html": `<div>
+    <span>{Count | upper}</span>
+</div>
+`,
+			},
+			wantErr: []string{"Tally.gt.html:2", "The upper filter on 'Count' requires a string field, but the field is int"},
+		},
+		{
+			name: "switchcase",
+			files: map[string]string{
+				"status.go": `package switchcase
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Status struct {
+	runtime.ComponentBase
+	State string
+}
+`,
+				"Status.gt.html": `<div>
+    {@switch State}
+    {@case 'ok'}
+        <span>All good</span>
+    {@case 'down'}
+        <span>Outage</span>
+    {@endswitch}
+</div>
+`,
+			},
+			test: `
+	status := &Status{State: "down"}
+	renderer := rendertest.NewTestRenderer(status)
+	if got := textOf(renderer.RenderRoot()); got != "Outage" {
+		t.Errorf("expected Outage, got %q", got)
+	}
+
+	status.State = "unknown"
+	renderer.ReRender()
+	if spans := findAllTags(renderer.GetCurrentVDOM(), "span"); len(spans) != 0 {
+		t.Errorf("expected no case for an unknown state, got %d spans", len(spans))
+	}`,
+		},
+		{
+			name: "slotlayout",
+			files: map[string]string{
+				"layout.go": `package slotlayout
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type PageShell struct {
+	runtime.ComponentBase
+	Heading     string
+	BodyContent []*vdom.VNode
+}
+
+type NewsStory struct {
+	runtime.ComponentBase
+	Text string
+}
+`,
+				"PageShell.gt.html": `<div class="shell">
+    <h1>{Heading}</h1>
+    <main>
+        {BodyContent}
+    </main>
+</div>
+`,
+				"NewsStory.gt.html": `<PageShell Heading="News">
+    <p>{Text}</p>
+</PageShell>
+`,
+			},
+			test: `
+	story := &NewsStory{Text: "first"}
+	renderer := rendertest.NewTestRenderer(story)
+	renderer.RenderRoot()
+
+	story.Text = "second"
+	renderer.ReRender()
+
+	root := renderer.GetCurrentVDOM()
+	if got := textOf(findTag(t, root, "h1")); got != "News" {
+		t.Errorf("expected the layout heading News, got %q", got)
+	}
+	if got := textOf(findTag(t, findTag(t, root, "main"), "p")); got != "second" {
+		t.Errorf("expected the slot to show the current text, got %q", got)
+	}`,
+		},
+		{
+			name: "trackbyotherfile",
+			files: map[string]string{
+				"roster.go": `package trackbyotherfile
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Roster struct {
+	runtime.ComponentBase
+	Users []User
+}
+`,
+				"user.go": `package trackbyotherfile
+
+type User struct {
+	ID   int
+	Name string
+}
+`,
+				"Roster.gt.html": `<ul>
+    {@for _, user := range Users trackBy user.IDD}
+        <li>{user.Name}</li>
+    {@endfor}
+</ul>
+`,
+			},
+			wantErr: []string{"trackBy identifier 'IDD' not found on type 'User'", "Available fields: [ID, Name]"},
+		},
+		{
+			name: "trackbyotherpackage",
+			files: map[string]string{
+				"roster.go": `package trackbyotherpackage
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"nojsharness.test/trackbyotherpackage/models"
+)
+
+type Roster struct {
+	runtime.ComponentBase
+	Users []*models.User
+}
+`,
+				"models/user.go": `package models
+
+type User struct {
+	Name    string
+	Profile Profile
+}
+
+type Profile struct {
+	ID    string
+	Email string
+}
+`,
+				"Roster.gt.html": `<ul>
+    {@for _, user := range Users trackBy user.Profile.ID}
+        <li>{user.Name}</li>
+    {@endfor}
+</ul>
+`,
+			},
+			test: `
+	roster := &Roster{Users: []*models.User{{Name: "Ada", Profile: models.Profile{ID: "a"}}}}
+	renderer := rendertest.NewTestRenderer(roster)
+	if got := textOf(findTag(t, renderer.RenderRoot(), "li")); got != "Ada" {
+		t.Errorf("expected the user, got %q", got)
+	}`,
+			imports: []string{"nojsharness.test/trackbyotherpackage/models"},
+		},
+		{
+			name: "trackbynestedtypo",
+			files: map[string]string{
+				"roster.go": `package trackbynestedtypo
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"nojsharness.test/trackbynestedtypo/models"
+)
+
+type Roster struct {
+	runtime.ComponentBase
+	Users []models.User
+}
+`,
+				"models/user.go": `package models
+
+type User struct {
+	Name    string
+	Profile *Profile
+}
+
+type Profile struct {
+	ID    string
+	Email string
+}
+`,
+				"Roster.gt.html": `<ul>
+    {@for _, user := range Users trackBy user.Profile.IDD}
+        <li>{user.Name}</li>
+    {@endfor}
+</ul>
+`,
+			},
+			wantErr: []string{"trackBy identifier 'Profile.IDD' not found on type 'models.Profile'", "Available fields: [ID, Email]"},
+		},
+		{
+			name: "crosspackage",
+			files: map[string]string{
+				"page.go": `package crosspackage
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Shop struct {
+	runtime.ComponentBase
+	InStock bool
+}
+`,
+				"Shop.gt.html": `<section>
+    <StockBadge Label="Widgets" Available="{InStock}" />
+</section>
+`,
+				"widgets/badge.go": `package widgets
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type StockBadge struct {
+	runtime.ComponentBase
+	Label     string
+	Available bool
+}
+`,
+				"widgets/StockBadge.gt.html": `<span class="{classes 'badge' Available:'in-stock'}">{Label}</span>
+`,
+			},
+			test: `
+	shop := &Shop{}
+	renderer := rendertest.NewTestRenderer(shop)
+	renderer.RenderRoot()
+
+	shop.InStock = true
+	renderer.ReRender()
+
+	badge := findTag(t, renderer.GetCurrentVDOM(), "span")
+	if got := badge.Attributes["class"]; got != "badge in-stock" {
+		t.Errorf("expected the badge classes to follow the prop, got %v", got)
+	}
+	if got := textOf(badge); got != "Widgets" {
+		t.Errorf("expected the label Widgets, got %q", got)
+	}`,
+		},
+		{
+			name: "crosspackagealiases",
+			files: map[string]string{
+				"dashboard.go": `package admin
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Dashboard struct {
+	runtime.ComponentBase
+	Owner string
+}
+`,
+				"Dashboard.gt.html": `<main>
+    <UserBadge Name="{Owner}" Role="owner"></UserBadge>
+    <AuditLog Entries="3"></AuditLog>
+    <EventList Title="Upcoming"></EventList>
+</main>
+`,
+				"users/admin/badge.go": `package admin
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Role string
+
+type UserBadge struct {
+	runtime.ComponentBase
+	Name string
+	Role Role
+}
+`,
+				"users/admin/UserBadge.gt.html": `<span class="badge">{Name} ({Role})</span>
+`,
+				"audit/admin/log.go": `package admin
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type AuditLog struct {
+	runtime.ComponentBase
+	Entries int
+}
+`,
+				"audit/admin/AuditLog.gt.html": `<p class="audit">{Entries} entries</p>
+`,
+				"calendar/events/list.go": `package events
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type EventList struct {
+	runtime.ComponentBase
+	Title string
+}
+`,
+				"calendar/events/EventList.gt.html": `<h2>{Title}</h2>
+`,
+			},
+			test: `
+	dashboard := &Dashboard{Owner: "Ada"}
+	renderer := rendertest.NewTestRenderer(dashboard)
+	renderer.RenderRoot()
+
+	root := renderer.GetCurrentVDOM()
+	if got := textOf(findTag(t, root, "span")); got != "Ada (owner)" {
+		t.Errorf("expected the badge of the users admin package, got %q", got)
+	}
+	if got := textOf(findTag(t, root, "p")); got != "3 entries" {
+		t.Errorf("expected the log of the audit admin package, got %q", got)
+	}
+	if got := textOf(findTag(t, root, "h2")); got != "Upcoming" {
+		t.Errorf("expected the list of the events package, got %q", got)
+	}`,
+		},
+		{
+			name: "formatting",
+			files: map[string]string{
+				"invoice.go": `package formatting
+
+import (
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Invoice struct {
+	runtime.ComponentBase
+	Total  float64
+	Issued time.Time
+	Note   *string
+}
+`,
+				"Invoice.gt.html": `<div>
+    <p class="total">{Total|printf:'%.2f'}</p>
+    <p class="issued">{Issued|date:'2006-01-02'}</p>
+    <p class="note">{Note|default:'none'}</p>
+</div>
+`,
+			},
+			imports: []string{"time"},
+			test: `
+	invoice := &Invoice{Total: 12.5, Issued: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)}
+	root := rendertest.NewTestRenderer(invoice).RenderRoot()
+
+	want := []string{"12.50", "2024-03-09", "none"}
+	values := findAllTags(root, "p")
+	if len(values) != len(want) {
+		t.Fatalf("expected %d values, got:\n%s", len(want), rendertest.FormatVNode(root))
+	}
+	for i, value := range values {
+		if got := textOf(value); got != want[i] {
+			t.Errorf("%v: expected %q, got %q", value.Attributes["class"], want[i], got)
+		}
+	}`,
+		},
+		{
+			name:    "zerovaluewarning",
+			options: Options{DevMode: true},
+			files: map[string]string{
+				"score.go": `package zerovaluewarning
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Score struct {
+	runtime.ComponentBase
+	Points int
+}
+`,
+				"Score.gt.html": `<p>Points: {Points}</p>
+`,
+			},
+			warns: []string{"Score.gt.html:1: '{Points}' follows the label 'Points:'"},
+			test: `
+	if got := textOf(rendertest.NewTestRenderer(&Score{Points: 7}).RenderRoot()); got != "Points: 7" {
+		t.Errorf("expected Points: 7, got %q", got)
+	}`,
+		},
+		{
+			name:    "loopkeysnote",
+			options: Options{DevMode: true},
+			files: map[string]string{
+				"team.go": `package loopkeysnote
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Member struct {
+	ID   int
+	Name string
+}
+
+type Team struct {
+	runtime.ComponentBase
+	Members []Member
+}
+`,
+				"Team.gt.html": `<ul>
+    {@for _, m := range Members trackBy m.ID}
+        <li>
+            <Tag Label="{m.Name}"></Tag>
+            <Avatar Name="{m.Name}"></Avatar>
+            <Tag Label="lead"></Tag>
+            <Avatar Name="lead"></Avatar>
+        </li>
+    {@endfor}
+</ul>
+`,
+				"tag.go": `package loopkeysnote
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Tag struct {
+	runtime.ComponentBase
+	Label string
+}
+`,
+				"Tag.gt.html": `<span>{Label}</span>
+`,
+				"avatar.go": `package loopkeysnote
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Avatar struct {
+	runtime.ComponentBase
+	Name string
+}
+`,
+				"Avatar.gt.html": `<img alt="{Name}" />
+`,
+			},
+			// One note per repeated component, sorted by name
+			warns: []string{"<Avatar> is used 2 times per {@for m} iteration; keys were disambiguated as Avatar_<trackBy>_<n>.\nNote in ", "<Tag> is used 2 times"},
+			test: `
+	team := &Team{Members: []Member{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Linus"}}}
+	root := rendertest.NewTestRenderer(team).RenderRoot()
+	if got := len(findAllTags(root, "span")); got != 4 {
+		t.Errorf("expected two tags per member, got %d", got)
+	}`,
+		},
+		{
+			name: "unknownfield",
+			files: map[string]string{
+				"card.go": `package unknownfield
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Card struct {
+	runtime.ComponentBase
+	Title string
+}
+`,
+				"Card.gt.html": `<h2>{Titel}</h2>
+`,
+			},
+			wantErr: []string{"Card.gt.html", "Field 'Titel' not found on component 'Card'"},
+		},
+		{
+			name: "missinghandler",
+			files: map[string]string{
+				"toggle.go": `package missinghandler
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Toggle struct {
+	runtime.ComponentBase
+	On bool
+}
+`,
+				"Toggle.gt.html": `<button @onclick="Flip">Toggle</button>
+`,
+			},
+			wantErr: []string{"Toggle.gt.html:1", "Handler method 'Flip' not found on component 'Toggle'"},
+		},
+		{
+			name: "unknownprop",
+			files: map[string]string{
+				"widgets.go": `package unknownprop
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Avatar struct {
+	runtime.ComponentBase
+	URL string
+}
+
+type Profile struct {
+	runtime.ComponentBase
+}
+`,
+				"Avatar.gt.html": `<img src="{URL}" />
+`,
+				"Profile.gt.html": `<div><Avatar Src="me.png" /></div>
+`,
+			},
+			wantErr: []string{"Profile.gt.html:1", "Attribute 'Src' does not match any exported field on component 'Avatar'", "Available fields: [URL]"},
+		},
+		{
+			name: "bindinput",
+			files: map[string]string{
+				"signup.go": `package bindinput
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Plan string
+
+type Signup struct {
+	runtime.ComponentBase
+	Name   string
+	Age    int
+	Weight float64
+	Agreed bool
+	Tier   Plan
+	Notes  string
+}
+`,
+				"Signup.gt.html": `<form>
+    <input type="text" @bind="Name" />
+    <input type="number" @bind="Age" />
+    <input type="number" @bind="Weight" />
+    <input type="checkbox" @bind="Agreed" />
+    <select @bind="Tier">
+        <option value="free">Free</option>
+        <option value="pro">Pro</option>
+    </select>
+    <textarea @bind="Notes"></textarea>
+</form>
+`,
+			},
+			imports: []string{"github.com/ForgeLogic/nojs/events"},
+			test: `
+	signup := &Signup{Age: 30}
+	renderer := rendertest.NewTestRenderer(signup)
+	root := renderer.RenderRoot()
+	inputs := findAllTags(root, "input")
+	if len(inputs) != 4 {
+		t.Fatalf("expected 4 inputs, got %d", len(inputs))
+	}
+
+	rendertest.FireEvent(t, inputs[0], "input", events.ChangeEventArgs{Value: "Ada"})
+	rendertest.FireEvent(t, inputs[1], "input", events.ChangeEventArgs{Value: "36"})
+	rendertest.FireEvent(t, inputs[1], "input", events.ChangeEventArgs{Value: "3x"})
+	rendertest.FireEvent(t, inputs[2], "input", events.ChangeEventArgs{Value: "61.5"})
+	rendertest.FireEvent(t, inputs[3], "change", events.ChangeEventArgs{Checked: true})
+	rendertest.FireEvent(t, findTag(t, root, "select"), "change", events.ChangeEventArgs{Value: "pro"})
+	rendertest.FireEvent(t, findTag(t, root, "textarea"), "input", events.ChangeEventArgs{Value: "hi"})
+
+	if signup.Name != "Ada" || signup.Age != 36 || signup.Weight != 61.5 || !signup.Agreed || signup.Tier != "pro" || signup.Notes != "hi" {
+		t.Errorf("expected the fields to follow the controls, got %+v", *signup)
+	}
+	root = renderer.GetCurrentVDOM()
+	inputs = findAllTags(root, "input")
+	if got := inputs[0].Attributes["value"]; got != "Ada" {
+		t.Errorf("expected the text input to show Ada, got %v", got)
+	}
+	if got := inputs[1].Attributes["value"]; got != 36 {
+		t.Errorf("expected the unparsable age to be ignored, got %v", got)
+	}
+	if got := inputs[3].Attributes["checked"]; got != true {
+		t.Errorf("expected the checkbox to be checked, got %v", got)
+	}
+	if got := findTag(t, root, "select").Attributes["value"]; got != Plan("pro") {
+		t.Errorf("expected the select value pro, got %v", got)
+	}`,
+		},
+		{
+			name: "bindunknownfield",
+			files: map[string]string{
+				"search.go": `package bindunknownfield
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Search struct {
+	runtime.ComponentBase
+	Query string
+}
+`,
+				"Search.gt.html": `<div>
+    <input type="text" @bind="Term" />
+</div>
+`,
+			},
+			wantErr: []string{"Search.gt.html:2", "@bind field 'Term' not found on component 'Search'", "Available fields: [Query]"},
+		},
+		{
+			name: "bindnoncontrol",
+			files: map[string]string{
+				"search.go": `package bindnoncontrol
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Search struct {
+	runtime.ComponentBase
+	Query string
+}
+`,
+				"Search.gt.html": `<div @bind="Query"></div>
+`,
+			},
+			wantErr: []string{"Search.gt.html:1", "@bind is only supported on <input>, <textarea> and <select>, not on <div>"},
+		},
+		{
+			name: "bindunsupportedtype",
+			files: map[string]string{
+				"search.go": `package bindunsupportedtype
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Filter struct{ Term string }
+
+type Search struct {
+	runtime.ComponentBase
+	Active bool
+	Filter Filter
+}
+`,
+				"Search.gt.html": `<div>
+    <input type="text" @bind="Filter" />
+</div>
+`,
+			},
+			wantErr: []string{"Search.gt.html:2", "Field 'Filter' of type 'Filter' cannot be bound with @bind"},
+		},
+		{
+			name: "bindboolnocheckbox",
+			files: map[string]string{
+				"search.go": `package bindboolnocheckbox
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Search struct {
+	runtime.ComponentBase
+	Active bool
+}
+`,
+				"Search.gt.html": `<div>
+    <input type="text" @bind="Active" />
+</div>
+`,
+			},
+			wantErr: []string{"Search.gt.html:2", "@bind with bool field 'Active' needs <input type=\"checkbox\">"},
+		},
+		{
+			name: "genericelements",
+			files: map[string]string{
+				"report.go": `package genericelements
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Report struct {
+	runtime.ComponentBase
+	Total int
+}
+`,
+				"Report.gt.html": `<div>
+    <details>
+        <summary>Totals</summary>
+        <table>
+            <tbody>
+                <tr><td><label>Total</label></td><td>{Total}</td></tr>
+            </tbody>
+        </table>
+    </details>
+    <svg viewBox="0 0 10 10"><circle cx="5" cy="5" r="4"></circle></svg>
+</div>
+`,
+			},
+			test: `
+	renderer := rendertest.NewTestRenderer(&Report{Total: 7})
+	renderer.RenderRoot()
+	root := renderer.GetCurrentVDOM()
+
+	for _, tag := range []string{"details", "summary", "table", "tbody", "tr", "label", "svg", "circle"} {
+		findTag(t, root, tag)
+	}
+	if cells := findAllTags(root, "td"); len(cells) != 2 || textOf(cells[1]) != "7" {
+		t.Errorf("expected two cells ending in 7, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if got := findTag(t, root, "circle").Attributes["r"]; got != "4" {
+		t.Errorf("expected circle r=4, got %v", got)
+	}
+`,
+		},
+		{
+			name: "svgicon",
+			files: map[string]string{
+				"icon.go": `package svgicon
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type HomeIcon struct {
+	runtime.ComponentBase
+	Path string
+}
+`,
+				"HomeIcon.gt.html": `<svg viewBox="0 0 24 24" preserveAspectRatio="xMidYMid meet">
+    <defs>
+        <linearGradient id="fill" gradientUnits="userSpaceOnUse"><stop offset="0"></stop></linearGradient>
+    </defs>
+    <path d="{Path}" stroke-width="2"></path>
+</svg>
+`,
+			},
+			test: `
+	renderer := rendertest.NewTestRenderer(&HomeIcon{Path: "M3 12l9-9 9 9"})
+	renderer.RenderRoot()
+	root := renderer.GetCurrentVDOM()
+
+	svg := findTag(t, root, "svg")
+	if svg.Attributes["viewBox"] != "0 0 24 24" || svg.Attributes["preserveAspectRatio"] != "xMidYMid meet" {
+		t.Errorf("expected camelCase svg attributes, got %v", svg.Attributes)
+	}
+	if got := findTag(t, root, "linearGradient").Attributes["gradientUnits"]; got != "userSpaceOnUse" {
+		t.Errorf("expected gradientUnits on <linearGradient>, got %v", got)
+	}
+	path := findTag(t, root, "path")
+	if path.Attributes["d"] != "M3 12l9-9 9 9" || path.Attributes["stroke-width"] != "2" {
+		t.Errorf("expected the bound path, got %v", path.Attributes)
+	}
+`,
+		},
+		{
+			name: "formvalidation",
+			files: map[string]string{
+				"signup.go": `package formvalidation
+
+import (
+	"github.com/ForgeLogic/nojs/forms"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Signup struct {
+	runtime.ComponentBase
+	forms.Validator
+	Email string ` + "`" + `nojs:"state" validate:"required,email"` + "`" + `
+}
+
+func (c *Signup) Submit() {
+	c.Validate()
+	c.StateHasChanged()
+}
+`,
+				"Signup.gt.html": `<form>
+    <input type="email" class="{classes 'field' Invalid.Email:'invalid' !Invalid.Email:'ok'}" @bind="Email" />
+    <span class="error">{Errors.Email}</span>
+    <button type="button" @onclick="Submit" title="{Errors.Email}">Sign up</button>
+</form>
+`,
+			},
+			imports: []string{"github.com/ForgeLogic/nojs/events"},
+			test: `
+	signup := &Signup{}
+	renderer := rendertest.NewTestRenderer(signup)
+	root := renderer.RenderRoot()
+	if got := findTag(t, root, "input").Attributes["class"]; got != "field ok" || textOf(findTag(t, root, "span")) != "" {
+		t.Fatalf("expected no error before validation, got class %q", got)
+	}
+
+	rendertest.FireEvent(t, findTag(t, root, "input"), "input", events.ChangeEventArgs{Value: "ada@"})
+	if !signup.IsValid("Email") {
+		t.Error("expected the field not flagged while it is first typed")
+	}
+	rendertest.FireEvent(t, findTag(t, root, "button"), "click", nil)
+	root = renderer.GetCurrentVDOM()
+	if got := findTag(t, root, "input").Attributes["class"]; got != "field invalid" {
+		t.Errorf("expected the invalid class after submit, got %q", got)
+	}
+	if got := textOf(findTag(t, root, "span")); got != "Enter a valid email address." || findTag(t, root, "button").Attributes["title"] != got {
+		t.Errorf("expected the message of the field, got %q", got)
+	}
+
+	rendertest.FireEvent(t, findTag(t, root, "input"), "input", events.ChangeEventArgs{Value: "ada@example.com"})
+	if root = renderer.GetCurrentVDOM(); textOf(findTag(t, root, "span")) != "" {
+		t.Errorf("expected the message gone once the field is fixed, got:\n%s", rendertest.FormatVNode(root))
+	}
+`,
+		},
+		{
+			name: "formvalidationunknownfield",
+			files: map[string]string{
+				"signup.go": `package formvalidationunknownfield
+
+import (
+	"github.com/ForgeLogic/nojs/forms"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Signup struct {
+	runtime.ComponentBase
+	forms.Validator
+	Email string ` + "`" + `nojs:"state" validate:"required,email"` + "`" + `
+}
+`,
+				"Signup.gt.html": `<form>
+    <input class="{classes Invalid.Emial:'invalid'}" @bind="Email" />
+</form>
+`,
+			},
+			wantErr: []string{"Signup.gt.html:2", "'Invalid.Emial' names no field of component 'Signup'", "Available fields: [Email]"},
+		},
+		{
+			name: "multipleroots",
+			files: map[string]string{
+				"card.go": `package multipleroots
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Card struct {
+	runtime.ComponentBase
+}
+`,
+				"Card.gt.html": `<!-- A title and its body -->
+<h2>Title</h2>
+<p>Body</p>
+`,
+			},
+			wantErr: []string{"Card.gt.html:3", "more than one root: <p> besides <h2>", `<div style="display: contents">`},
+		},
+		{
+			name: "multiplerootsafterblock",
+			files: map[string]string{
+				"card.go": `package multiplerootsafterblock
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Card struct {
+	runtime.ComponentBase
+	Open bool
+}
+`,
+				"Card.gt.html": `<section>
+    <h2>Title</h2>
+</section>
+{@if Open}
+    <p>Body</p>
+{@endif}
+`,
+			},
+			wantErr: []string{"Card.gt.html:4", "more than one root: an {@if} block besides <section>"},
+		},
+		{
+			name: "innerhtml",
+			files: map[string]string{
+				"article.go": `package innerhtml
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Markup string
+
+type ArticleView struct {
+	runtime.ComponentBase
+	Title string
+	Body  Markup
+}
+`,
+				"ArticleView.gt.html": `<div>
+    <h1>{Title}</h1>
+    <article class="post" [innerHTML]="{Body}"></article>
+</div>
+`,
+			},
+			test: `
+	view := &ArticleView{Title: "Notes", Body: "<p>Hello <em>world</em></p>"}
+	renderer := rendertest.NewTestRenderer(view)
+	renderer.RenderRoot()
+
+	article := findTag(t, renderer.GetCurrentVDOM(), "article")
+	if article.UnsafeInnerHTML != "<p>Hello <em>world</em></p>" || len(article.Children) != 0 || article.Content != "" {
+		t.Errorf("expected the body as raw markup, got %+v", article)
+	}
+	if article.Attributes["class"] != "post" || len(article.Attributes) != 1 {
+		t.Errorf("expected only the class attribute, got %v", article.Attributes)
+	}
+`,
+		},
+		{
+			name: "innerhtmlwithchildren",
+			files: map[string]string{
+				"article.go": `package innerhtmlwithchildren
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type ArticleView struct {
+	runtime.ComponentBase
+	Body string
+}
+`,
+				"ArticleView.gt.html": `<div>
+    <article [innerhtml]="{Body}"><p>Loading</p></article>
+</div>
+`,
+			},
+			wantErr: []string{"ArticleView.gt.html:2", "<article [innerhtml]> must be empty"},
+		},
+		{
+			name: "innerhtmlnotstring",
+			files: map[string]string{
+				"article.go": `package innerhtmlnotstring
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type ArticleView struct {
+	runtime.ComponentBase
+	Words int
+}
+`,
+				"ArticleView.gt.html": `<div>
+    <article [innerhtml]="{Words}"></article>
+</div>
+`,
+			},
+			wantErr: []string{"ArticleView.gt.html:2", "[innerhtml] field 'Words' must be a string, found 'int'"},
+		},
+		{
+			name: "transition",
+			files: map[string]string{
+				"toast.go": `package transition
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type ToastList struct {
+	runtime.ComponentBase
+	Messages []string
+}
+`,
+				"ToastList.gt.html": `<ul>
+    {@for _, m := range Messages trackBy m}
+        <li class="toast" transition="fade" transition-timeout="300ms">{m}</li>
+    {@endfor}
+    <li transition="slide">end</li>
+</ul>
+`,
+			},
+			test: `
+	renderer := rendertest.NewTestRenderer(&ToastList{Messages: []string{"Saved"}})
+	renderer.RenderRoot()
+	root := renderer.GetCurrentVDOM()
+
+	toast := findTag(t, root, "li")
+	if toast.Transition == nil || toast.Transition.Enter != "fade-enter" || toast.Transition.Leave != "fade-leave" || toast.Transition.Timeout != 300*time.Millisecond {
+		t.Errorf("expected the fade transition with a 300ms timeout, got %+v", toast.Transition)
+	}
+	if len(toast.Attributes) != 1 || toast.Attributes["class"] != "toast" {
+		t.Errorf("expected the transition attributes left out, got %v", toast.Attributes)
+	}
+	if end := root.Children[len(root.Children)-1]; end.Transition == nil || end.Transition.Timeout != 0 {
+		t.Errorf("expected the slide transition with the default timeout, got %+v", end.Transition)
+	}
+`,
+			imports: []string{"time"},
+		},
+		{
+			name: "transitionbadtimeout",
+			files: map[string]string{
+				"toast.go": `package transitionbadtimeout
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Toast struct {
+	runtime.ComponentBase
+}
+`,
+				"Toast.gt.html": `<div>
+    <p transition="fade" transition-timeout="soon">Saved</p>
+</div>
+`,
+			},
+			wantErr: []string{"Toast.gt.html:2", `transition-timeout="soon" on <p> must be a positive duration`},
+		},
+		{
+			name: "classobjectandstyle",
+			files: map[string]string{
+				"board.go": `package classobjectandstyle
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Task struct {
+	ID     int
+	Name   string
+	Done   bool
+	Styles map[string]string
+}
+
+type TaskBoard struct {
+	runtime.ComponentBase
+	IsActive bool
+	HasError bool
+	Styles   map[string]string
+	Tasks    []Task
+}
+`,
+				"TaskBoard.gt.html": `<section class="board {IsActive: 'active', HasError: 'error'} wide" [style]="{Styles}">
+    <ul>
+        {@for _, task := range Tasks trackBy task.ID}
+            <li class="{task.Done: 'done', !task.Done: 'open'}" [style]="{task.Styles}">{task.Name}</li>
+        {@endfor}
+    </ul>
+</section>
+`,
+			},
+			test: `
+	board := &TaskBoard{
+		IsActive: true,
+		Styles:   map[string]string{"width": "50%", "color": "red", "border": "1px solid"},
+		Tasks:    []Task{{ID: 1, Name: "a", Done: true, Styles: map[string]string{"order": "2"}}, {ID: 2, Name: "b"}},
+	}
+	renderer := rendertest.NewTestRenderer(board)
+	renderer.RenderRoot()
+	root := renderer.GetCurrentVDOM()
+
+	section := findTag(t, root, "section")
+	if got := section.Attributes["class"]; got != "board active wide" {
+		t.Errorf("expected class %q, got %q", "board active wide", got)
+	}
+	if got := section.Attributes["style"]; got != "border: 1px solid; color: red; width: 50%" {
+		t.Errorf("expected sorted declarations, got %q", got)
+	}
+	items := findAllTags(root, "li")
+	if items[0].Attributes["class"] != "done" || items[1].Attributes["class"] != "open" {
+		t.Errorf("expected loop classes done and open, got %v and %v", items[0].Attributes, items[1].Attributes)
+	}
+	if items[0].Attributes["style"] != "order: 2" {
+		t.Errorf("expected the item style, got %v", items[0].Attributes["style"])
+	}
+	if _, ok := items[1].Attributes["style"]; ok {
+		t.Errorf("expected no style attribute for an empty map, got %v", items[1].Attributes["style"])
+	}
+`,
+		},
+		{
+			name: "classobjectnotbool",
+			files: map[string]string{
+				"board.go": `package classobjectnotbool
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type TaskBoard struct {
+	runtime.ComponentBase
+	Count int
+}
+`,
+				"TaskBoard.gt.html": `<section class="{Count: 'busy'} board">
+</section>
+`,
+			},
+			wantErr: []string{"TaskBoard.gt.html:1", "Count"},
+		},
+		{
+			name: "stylenotmap",
+			files: map[string]string{
+				"board.go": `package stylenotmap
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type TaskBoard struct {
+	runtime.ComponentBase
+	Styles string
+}
+`,
+				"TaskBoard.gt.html": `<section [style]="{Styles}">
+</section>
+`,
+			},
+			wantErr: []string{"TaskBoard.gt.html:1", "[style] field 'Styles' must be a map[string]string, found 'string'"},
+		},
+		{
+			name: "eventmodifiers",
+			files: map[string]string{
+				"form.go": `package eventmodifiers
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type NoteForm struct {
+	runtime.ComponentBase
+	Saved     bool
+	Submitted int
+	Inits     int
+}
+
+func (f *NoteForm) Save(e events.FormEventArgs) {
+	f.Saved = e.IsDefaultPrevented()
+}
+
+func (f *NoteForm) Submit(e events.KeyboardEventArgs) {
+	f.Submitted++
+}
+
+func (f *NoteForm) Init() {
+	f.Inits++
+}
+`,
+				"NoteForm.gt.html": `<form @onsubmit.prevent="Save">
+    <input @onkeydown.enter.stop="Submit" />
+    <div @onclick.once="Init">Start</div>
+</form>
+`,
+			},
+			imports: []string{"github.com/ForgeLogic/nojs/events"},
+			test: `
+	form := &NoteForm{}
+	renderer := rendertest.NewTestRenderer(form)
+	renderer.RenderRoot()
+	root := renderer.GetCurrentVDOM()
+
+	rendertest.FireEvent(t, findTag(t, root, "form"), "submit", events.FormEventArgs{})
+	if !form.Saved {
+		t.Error("expected Save to receive a prevented submit event")
+	}
+
+	input := findTag(t, root, "input")
+	for _, key := range []string{"a", "Enter", "Shift", "Enter"} {
+		rendertest.FireEvent(t, input, "keydown", events.KeyboardEventArgs{Key: key})
+	}
+	if form.Submitted != 2 {
+		t.Errorf("expected 2 submits for 2 Enter presses, got %d", form.Submitted)
+	}
+
+	start := findTag(t, root, "div")
+	rendertest.FireEvent(t, start, "click", nil)
+	rendertest.FireEvent(t, start, "click", nil)
+	if form.Inits != 1 {
+		t.Errorf("expected Init to run once, got %d", form.Inits)
+	}
+`,
+		},
+		{
+			name: "eventmodifierunknown",
+			files: map[string]string{
+				"form.go": `package eventmodifierunknown
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type NoteForm struct {
+	runtime.ComponentBase
+}
+
+func (f *NoteForm) Init() {}
+`,
+				"NoteForm.gt.html": `<div>
+    <button @onclick.capture="Init">Start</button>
+</div>
+`,
+			},
+			wantErr: []string{"NoteForm.gt.html:2", "Unknown event modifier '.capture' in @onclick.capture. Supported modifiers: .enter, .escape, .once, .prevent, .stop"},
+		},
+		{
+			name: "eventmodifierkeyonclick",
+			files: map[string]string{
+				"form.go": `package eventmodifierkeyonclick
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type NoteForm struct {
+	runtime.ComponentBase
+}
+
+func (f *NoteForm) Init() {}
+`,
+				"NoteForm.gt.html": `<div>
+    <button @onclick.enter="Init">Start</button>
+</div>
+`,
+			},
+			wantErr: []string{"NoteForm.gt.html:2", "Key modifier '.enter' only applies to keyboard events"},
+		},
+		{
+			name: "dragdropevents",
+			files: map[string]string{
+				"board.go": `package dragdropevents
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Card struct {
+	ID    string
+	Title string
+}
+
+type Kanban struct {
+	runtime.ComponentBase
+	Todo     []Card
+	Done     []Card
+	Dragged  string
+	Editing  string
+	Menu     bool
+	LoadMore bool
+	Zoom     float64
+	Touches  int
+}
+
+func (k *Kanban) StartDrag(e events.DragEventArgs) { k.Dragged = e.Text }
+
+func (k *Kanban) AllowDrop(e events.DragEventArgs) {}
+
+func (k *Kanban) DropDone(e events.DragEventArgs) {
+	for i, card := range k.Todo {
+		if card.ID == e.Text {
+			k.Todo = append(k.Todo[:i], k.Todo[i+1:]...)
+			k.Done = append(k.Done, card)
+			break
+		}
+	}
+}
+
+func (k *Kanban) Edit(e events.MouseEventArgs) { k.Editing = "card" }
+
+func (k *Kanban) OpenMenu(e events.MouseEventArgs) { k.Menu = true }
+
+func (k *Kanban) Scrolled(e events.ScrollEventArgs) { k.LoadMore = e.NearBottom(50) }
+
+func (k *Kanban) Wheel(e events.WheelEventArgs) { k.Zoom += e.DeltaY }
+
+func (k *Kanban) Touch(e events.TouchEventArgs) { k.Touches = len(e.Touches) }
+`,
+				"Kanban.gt.html": `<div @onwheel="Wheel">
+    <ul class="todo" @onscroll="Scrolled">
+        {@for _, card := range Todo trackBy card.ID}
+            <li draggable="true" @ondragstart="StartDrag" @ondblclick="Edit" @oncontextmenu.prevent="OpenMenu" @ontouchstart="Touch">{card.Title}</li>
+        {@endfor}
+    </ul>
+    <ul class="done" @ondragover.prevent="AllowDrop" @ondrop="DropDone">
+        {@for _, card := range Done trackBy card.ID}
+            <li>{card.Title}</li>
+        {@endfor}
+    </ul>
+</div>
+`,
+			},
+			imports: []string{"github.com/ForgeLogic/nojs/events"},
+			test: `
+	board := &Kanban{Todo: []Card{{ID: "a", Title: "Write"}, {ID: "b", Title: "Ship"}}}
+	renderer := rendertest.NewTestRenderer(board)
+	renderer.RenderRoot()
+	root := renderer.GetCurrentVDOM()
+
+	item := findAllTags(root, "li")[1]
+	drag := events.DragEventArgs{}
+	drag.SetText("b")
+	rendertest.FireEvent(t, item, "dragstart", drag)
+	rendertest.FireEvent(t, item, "dblclick", events.MouseEventArgs{})
+	rendertest.FireEvent(t, item, "contextmenu", events.MouseEventArgs{})
+	rendertest.FireEvent(t, item, "touchstart", events.TouchEventArgs{Touches: []events.TouchPoint{{Identifier: 1}, {Identifier: 2}}})
+	lists := findAllTags(root, "ul")
+	rendertest.FireEvent(t, lists[1], "dragover", events.DragEventArgs{})
+	rendertest.FireEvent(t, lists[1], "drop", events.DragEventArgs{Text: board.Dragged})
+	rendertest.FireEvent(t, lists[0], "scroll", events.ScrollEventArgs{ScrollTop: 460, ScrollHeight: 800, ClientHeight: 300})
+	rendertest.FireEvent(t, root, "wheel", events.WheelEventArgs{DeltaY: 120})
+
+	if board.Dragged != "b" || len(board.Done) != 1 || board.Done[0].Title != "Ship" || len(board.Todo) != 1 {
+		t.Errorf("expected card b dropped on done, got todo %v, done %v", board.Todo, board.Done)
+	}
+	if board.Editing != "card" || !board.Menu || board.Touches != 2 {
+		t.Errorf("expected dblclick, contextmenu and touch handled, got %+v", board)
+	}
+	if !board.LoadMore || board.Zoom != 120 {
+		t.Errorf("expected scroll near the bottom and a wheel delta, got LoadMore=%v Zoom=%v", board.LoadMore, board.Zoom)
+	}
+`,
+		},
+		{
+			name: "dragwrongargs",
+			files: map[string]string{
+				"board.go": `package dragwrongargs
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Kanban struct {
+	runtime.ComponentBase
+}
+
+func (k *Kanban) Drop(e events.MouseEventArgs) {}
+`,
+				"Kanban.gt.html": `<div>
+    <ul @ondrop="Drop"></ul>
+</div>
+`,
+			},
+			wantErr: []string{"Kanban.gt.html:2", "Expected: func(c *Kanban) Drop(e events.DragEventArgs)"},
+		},
+		{
+			name: "eventtargetdataset",
+			files: map[string]string{
+				"list.go": `package eventtargetdataset
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Item struct {
+	ID   int
+	Name string
+}
+
+type ItemList struct {
+	runtime.ComponentBase
+	Items    []Item
+	Selected string
+}
+
+func (l *ItemList) Select(e events.ClickEventArgs) {
+	l.Selected = e.TargetTagName + ":" + e.Dataset["id"]
+}
+`,
+				"ItemList.gt.html": `<ul>
+    {@for _, item := range Items trackBy item.ID}
+        <li><button data-id="{item.ID}" @onclick="Select">{item.Name}</button></li>
+    {@endfor}
+</ul>
+`,
+			},
+			imports: []string{"github.com/ForgeLogic/nojs/events"},
+			test: `
+	list := &ItemList{Items: []Item{{ID: 7, Name: "seven"}, {ID: 9, Name: "nine"}}}
+	renderer := rendertest.NewTestRenderer(list)
+	renderer.RenderRoot()
+
+	button := findAllTags(renderer.GetCurrentVDOM(), "button")[1]
+	if got := button.Attributes["data-id"]; got != 9 {
+		t.Fatalf("expected data-id 9 on the second button, got %v", got)
+	}
+	target := events.EventTarget{TargetTagName: "button", Dataset: map[string]string{"id": "9"}}
+	rendertest.FireEvent(t, button, "click", events.ClickEventArgs{EventTarget: target})
+
+	if list.Selected != "button:9" {
+		t.Errorf("expected the handler to see button:9, got %q", list.Selected)
+	}
+`,
+		},
+		{
+			name: "handlerarguments",
+			files: map[string]string{
+				"list.go": `package handlerarguments
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type User struct {
+	ID   int
+	Name string
+}
+
+type UserList struct {
+	runtime.ComponentBase
+	Users    []User
+	Default  int
+	Selected int
+	Removed  string
+	Position int
+}
+
+func (l *UserList) SelectUser(id int) {
+	l.Selected = id
+}
+
+func (l *UserList) Remove(ctx runtime.Ctx, user User, i int) {
+	l.Removed = user.Name
+	l.Position = i
+}
+`,
+				"UserList.gt.html": `<div>
+    {@for i, user := range Users trackBy user.ID}
+        <button @onclick="SelectUser(user.ID)">{user.Name}</button>
+        <span @onclick.once="Remove(user, i)">x</span>
+    {@endfor}
+    <p @onclick="SelectUser(Default)">Reset</p>
+</div>
+`,
+			},
+			test: `
+	list := &UserList{Users: []User{{ID: 7, Name: "Ada"}, {ID: 9, Name: "Linus"}}, Default: 1}
+	renderer := rendertest.NewTestRenderer(list)
+	renderer.RenderRoot()
+	root := renderer.GetCurrentVDOM()
+
+	rendertest.FireEvent(t, findAllTags(root, "button")[1], "click", nil)
+	if list.Selected != 9 {
+		t.Errorf("expected the second button to select user 9, got %d", list.Selected)
+	}
+	rendertest.FireEvent(t, findAllTags(root, "button")[0], "click", nil)
+	if list.Selected != 7 {
+		t.Errorf("expected the first button to select user 7, got %d", list.Selected)
+	}
+
+	rendertest.FireEvent(t, findAllTags(root, "span")[1], "click", nil)
+	if list.Removed != "Linus" || list.Position != 1 {
+		t.Errorf("expected Remove(Linus, 1), got Remove(%s, %d)", list.Removed, list.Position)
+	}
+
+	rendertest.FireEvent(t, findTag(t, root, "p"), "click", nil)
+	if list.Selected != 1 {
+		t.Errorf("expected the field argument to select 1, got %d", list.Selected)
+	}
+`,
+		},
+		{
+			name: "handlerargumentwrongtype",
+			files: map[string]string{
+				"list.go": `package handlerargumentwrongtype
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type User struct {
+	ID   int
+	Name string
+}
+
+type UserList struct {
+	runtime.ComponentBase
+	Users []User
+}
+
+func (l *UserList) SelectUser(id int) {}
+`,
+				"UserList.gt.html": `<div>
+    {@for _, user := range Users trackBy user.ID}
+        <button @onclick="SelectUser(user.Name)">{user.Name}</button>
+    {@endfor}
+</div>
+`,
+			},
+			wantErr: []string{"UserList.gt.html:3", "Argument 'user.Name' of handler 'SelectUser' has type 'string', but parameter 'id' is 'int'."},
+		},
+		{
+			name: "handlerargumentunknown",
+			files: map[string]string{
+				"list.go": `package handlerargumentunknown
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type UserList struct {
+	runtime.ComponentBase
+	Users []string
+}
+
+func (l *UserList) SelectUser(name string) {}
+`,
+				"UserList.gt.html": `<div>
+    {@for _, user := range Users trackBy user}
+        <button @onclick="SelectUser(name)">{user}</button>
+    {@endfor}
+</div>
+`,
+			},
+			wantErr: []string{"Argument 'name' of handler 'SelectUser': not a loop variable (loops have: user) or a component field (available: Users)"},
+		},
+		{
+			name: "handlerargumentcount",
+			files: map[string]string{
+				"list.go": `package handlerargumentcount
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type UserList struct {
+	runtime.ComponentBase
+	Name string
+}
+
+func (l *UserList) Rename(old, name string) {}
+`,
+				"UserList.gt.html": `<button @onclick="Rename(Name)">Rename</button>
+`,
+			},
+			wantErr: []string{"Handler 'Rename' for '@onclick' is called with 1 argument(s) but takes 2: func(c *UserList) Rename(old string, name string)"},
+		},
+		{
+			name: "memoizedchild",
+			files: map[string]string{
+				"shop.go": `package memoizedchild
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type Shop struct {
+	runtime.ComponentBase
+	Title    string
+	Currency string
+	Prices   []int
+}
+
+// PriceList stands in for a heavy component that only depends on its props.
+//nojs:memo
+type PriceList struct {
+	runtime.ComponentBase
+	Currency string
+	Prices   []int
+	OnPick   func(price int)
+	Renders  int ` + "`nojs:\"state\"`" + `
+}
+
+func (c *PriceList) RenderCount() int {
+	c.Renders++
+	return c.Renders
+}
+
+//nojs:memo
+type Panel struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+	Renders     int ` + "`nojs:\"state\"`" + `
+}
+
+func (c *Panel) RenderCount() int {
+	c.Renders++
+	return c.Renders
+}
+`,
+				"Shop.gt.html": `<div>
+    <h1>{Title}</h1>
+    <PriceList Currency="{Currency}" Prices="{Prices}"></PriceList>
+    <Panel><p>{Title}</p></Panel>
+</div>
+`,
+				"PriceList.gt.html": `<p>{Currency} #{RenderCount()}</p>
+`,
+				"Panel.gt.html": `<section data-renders="{RenderCount()}">{BodyContent}</section>
+`,
+			},
+			test: `
+	shop := &Shop{Title: "Shop", Currency: "EUR", Prices: []int{3, 5}}
+	renderer := rendertest.NewTestRenderer(shop)
+	renderer.RenderRoot()
+	list := renderer.GetChild("PriceList_0").(*PriceList)
+	panel := renderer.GetChild("Panel_0").(*Panel)
+
+	shop.Title = "Sale"
+	renderer.ReRender()
+	if list.Renders != 1 {
+		t.Errorf("expected unchanged props to reuse the list's render, got %d renders", list.Renders)
+	}
+	if panel.Renders != 2 {
+		t.Errorf("expected new slot content to render the panel, got %d renders", panel.Renders)
+	}
+	if got := textOf(findTag(t, renderer.GetCurrentVDOM(), "h1")); got != "Sale" {
+		t.Errorf("expected the parent to render, got %q", got)
+	}
+
+	shop.Prices = append([]int(nil), shop.Prices...)
+	renderer.ReRender()
+	shop.Currency = "USD"
+	renderer.ReRender()
+	if got := textOf(findTag(t, renderer.GetCurrentVDOM(), "p")); list.Renders != 3 || got != "USD #3" {
+		t.Errorf("expected a new slice and a new currency to render the list, got %d renders showing %q", list.Renders, got)
+	}
+
+	list.Invalidate()
+	renderer.ReRender()
+	list.StateHasChanged()
+	renderer.Flush()
+	renderer.ReRender()
+	if list.Renders != 5 {
+		t.Errorf("expected Invalidate and StateHasChanged to render the list once each, got %d renders", list.Renders)
+	}`,
+		},
+		{
+			name:    "memoizeduncomparable",
+			options: Options{DevMode: true},
+			files: map[string]string{
+				"labels.go": `package memoizeduncomparable
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Page struct {
+	runtime.ComponentBase
+	Labels map[string]string
+}
+
+//nojs:memo
+type LabelList struct {
+	runtime.ComponentBase
+	Labels  map[string]string
+	Renders int ` + "`nojs:\"state\"`" + `
+}
+
+func (c *LabelList) RenderCount() int {
+	c.Renders++
+	return c.Renders
+}
+`,
+				"Page.gt.html": `<div><LabelList Labels="{Labels}"></LabelList></div>
+`,
+				"LabelList.gt.html": `<p>{RenderCount()}</p>
+`,
+			},
+			warns: []string{"component 'LabelList' is marked //nojs:memo, but its prop 'Labels' cannot be compared"},
+			test: `
+	renderer := rendertest.NewTestRenderer(&Page{Labels: map[string]string{"a": "A"}})
+	renderer.RenderRoot()
+	renderer.ReRender()
+
+	if got := renderer.GetChild("LabelList_0").(*LabelList).Renders; got != 2 {
+		t.Errorf("expected the list to render every time, got %d renders", got)
+	}`,
+		},
+		{
+			name: "contextinjection",
+			files: map[string]string{
+				"shell.go": `package contextinjection
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Theme struct {
+	Name string
+}
+
+type Shell struct {
+	runtime.ComponentBase
+	Theme *Theme
+}
+
+func (c *Shell) OnMount() {
+	runtime.ProvideContext(c, "theme", c.Theme)
+}
+
+type Sidebar struct {
+	runtime.ComponentBase
+	Heading string
+}
+
+type ThemeBadge struct {
+	runtime.ComponentBase
+	Label string
+	Theme *Theme ` + "`nojs:\"inject:theme\"`" + `
+}
+`,
+				"Shell.gt.html": `<div><Sidebar Heading="Menu"></Sidebar></div>
+`,
+				"Sidebar.gt.html": `<nav><h2>{Heading}</h2><ThemeBadge Label="Theme"></ThemeBadge></nav>
+`,
+				"ThemeBadge.gt.html": `<span>{Label}: {Theme.Name}</span>
+`,
+			},
+			test: `
+	shell := &Shell{Theme: &Theme{Name: "dark"}}
+	renderer := rendertest.NewTestRenderer(shell)
+	shell.OnMount()
+	root := renderer.RenderRoot()
+	if got := textOf(findTag(t, root, "span")); got != "Theme: dark" {
+		t.Errorf("expected the badge to show the shell's theme, got %q", got)
+	}
+
+	runtime.ProvideContext(shell, "theme", &Theme{Name: "light"})
+	renderer.ReRender()
+	if got := textOf(findTag(t, renderer.GetCurrentVDOM(), "span")); got != "Theme: light" {
+		t.Errorf("expected the badge to show the new theme, got %q", got)
+	}`,
+			imports: []string{"github.com/ForgeLogic/nojs/runtime"},
+		},
+		{
+			name: "multipleerrors",
+			files: map[string]string{
+				"inbox.go": `package multipleerrors
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Inbox struct {
+	runtime.ComponentBase
+	Count  int
+	Unread bool
+}
+`,
+				"Inbox.gt.html": `<div>
+    <p>{Missing}</p>
+    <button @onclick="Nope">Refresh</button>
+    {@if Archived}
+        <p>{Count}</p>
+    {@endif}
+    <Sidebar></Sidebar>
+</div>
+`,
+				"outbox.go": `package multipleerrors
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Outbox struct {
+	runtime.ComponentBase
+	Count int
+}
+`,
+				"Outbox.gt.html": `<div>
+    {@for _, m := range Count trackBy m}
+        <p>{m}</p>
+    {@endfor}
+</div>
+`,
+			},
+			wantErr: []string{
+				"failed to compile template for Inbox",
+				"Field 'Missing' not found",
+				"Handler method 'Nope' not found on component 'Inbox'",
+				"Condition 'Archived' not found on component 'Inbox'",
+				"Component '<Sidebar>' not found",
+				"failed to compile template for Outbox",
+				"Field 'Count'",
+			},
+		},
+		{
+			name: "linedirectives",
+			files: map[string]string{
+				"report.go": `package linedirectives
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Report struct {
+	runtime.ComponentBase
+	Rows []int
+}
+
+func (r *Report) Total() int {
+	return r.Rows[len(r.Rows)-1]
+}
+`,
+				"Report.gt.html": `<div>
+    <h1>Report</h1>
+    <span>{Total()}</span>
+</div>
+`,
+			},
+			test: `
+	report := &Report{}
+	rendertest.NewTestRenderer(report).RenderRoot()
+	var p *runtime.ComponentPanic
+	if !errors.As(runtime.LastRenderError(report), &p) {
+		t.Fatal("expected rendering an empty report to panic")
+	}
+	if !strings.Contains(p.Stack, "Report.gt.html:3") {
+		t.Errorf("expected the stack trace to point at line 3 of the template, got:\n%s", p.Stack)
+	}`,
+			imports: []string{"errors", "github.com/ForgeLogic/nojs/runtime"},
+		},
+		{
+			name: "duplicatetags",
+			files: map[string]string{
+				"badge.go": `package duplicatetags
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Badge struct {
+	runtime.ComponentBase
+	Label string
+}
+`,
+				"Badge.gt.html": `<span>{Label}</span>
+`,
+				"page.go": `package duplicatetags
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Page struct {
+	runtime.ComponentBase
+	Ready bool
+}
+`,
+				"Page.gt.html": `<div>
+    <Badge title="Hi"></Badge>
+    <button @onclick="Save">Save</button>
+    <Badge Title="Hi"></Badge>
+    <button @onclick="Save">Save</button>
+    {@if Ready}<p>Ready</p>{@endif}
+    {@if Loaded}<p>Ready</p>{@endif}
+</div>
+`,
+			},
+			wantErr: []string{
+				"Page.gt.html:3: Handler method 'Save' not found",
+				"Page.gt.html:4: Attribute 'Title' does not match any exported field on component 'Badge'",
+				"Page.gt.html:5: Handler method 'Save' not found",
+				"Page.gt.html:7: Condition 'Loaded' not found",
+			},
+		},
+		{
+			name: "namedslots",
+			files: map[string]string{
+				"card.go": `package namedslots
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type Card struct {
+	runtime.ComponentBase
+	Body   []*vdom.VNode
+	Header []*vdom.VNode ` + "`nojs:\"slot:header\"`" + `
+	Footer []*vdom.VNode ` + "`nojs:\"slot:footer\"`" + `
+}
+
+type Profile struct {
+	runtime.ComponentBase
+	Name string
+}
+`,
+				"Card.gt.html": `<section class="card">
+    <header>{Header}</header>
+    <div class="body">{Body}</div>
+    <footer>{Footer}</footer>
+</section>
+`,
+				"Profile.gt.html": `<Card>
+    <slot name="header"><h2>{Name}</h2></slot>
+    <p>Member since 2020</p>
+</Card>
+`,
+			},
+			test: `
+	profile := &Profile{Name: "Ada"}
+	renderer := rendertest.NewTestRenderer(profile)
+	renderer.RenderRoot()
+
+	profile.Name = "Grace"
+	renderer.ReRender()
+
+	root := renderer.GetCurrentVDOM()
+	if got := textOf(findTag(t, findTag(t, root, "header"), "h2")); got != "Grace" {
+		t.Errorf("expected the header slot to show the current name, got %q", got)
+	}
+	if got := textOf(findTag(t, root, "p")); got != "Member since 2020" {
+		t.Errorf("expected the default slot content in the body, got %q", got)
+	}
+	if footer := findTag(t, root, "footer"); len(footer.Children) != 0 {
+		t.Errorf("expected the unfilled footer slot to be empty, got %d children", len(footer.Children))
+	}`,
+		},
+		{
+			name: "namedslotunknown",
+			files: map[string]string{
+				"card.go": `package namedslotunknown
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type Card struct {
+	runtime.ComponentBase
+	Header []*vdom.VNode ` + "`nojs:\"slot:header\"`" + `
+}
+
+type Page struct {
+	runtime.ComponentBase
+}
+`,
+				"Card.gt.html": `<section>{Header}</section>
+`,
+				"Page.gt.html": `<Card>
+    <slot name="title"><h2>Hi</h2></slot>
+</Card>
+`,
+			},
+			wantErr: []string{"Page.gt.html:2: Component 'Card' has no slot 'title'. Available slots: [header]"},
+		},
+		{
+			name: "namedslotduplicate",
+			files: map[string]string{
+				"card.go": `package namedslotduplicate
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type Card struct {
+	runtime.ComponentBase
+	Header []*vdom.VNode ` + "`nojs:\"slot:header\"`" + `
+	Title  []*vdom.VNode ` + "`nojs:\"slot:header\"`" + `
+}
+`,
+				"Card.gt.html": `<section>{Header}{Title}</section>
+`,
+			},
+			wantErr: []string{`fields 'Header' and 'Title' of component 'Card' are both named slot "header"`},
+		},
+		{
+			name: "slotfallback",
+			files: map[string]string{
+				"card.go": `package slotfallback
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type Card struct {
+	runtime.ComponentBase
+	Body   []*vdom.VNode
+	Footer []*vdom.VNode ` + "`nojs:\"slot:footer\"`" + `
+}
+
+type Page struct {
+	runtime.ComponentBase
+	Message string
+}
+`,
+				"Card.gt.html": `<section class="card">
+    <div class="body">{@slot Body}<p>No content</p>{@endslot}</div>
+    <footer>{@slot Footer}<span>Default footer</span>{@endslot}</footer>
+</section>
+`,
+				"Page.gt.html": `<div>
+    <Card>
+        <p>{Message}</p>
+        <slot name="footer"><span>Custom footer</span></slot>
+    </Card>
+    <Card></Card>
+</div>
+`,
+			},
+			test: `
+	page := &Page{Message: "Hello"}
+	renderer := rendertest.NewTestRenderer(page)
+	renderer.RenderRoot()
+
+	cards := findAllTags(renderer.GetCurrentVDOM(), "section")
+	if len(cards) != 2 {
+		t.Fatalf("expected 2 cards, got %d", len(cards))
+	}
+	if got := textOf(findTag(t, cards[0], "p")); got != "Hello" {
+		t.Errorf("expected the provided body, got %q", got)
+	}
+	if got := textOf(findTag(t, cards[0], "span")); got != "Custom footer" {
+		t.Errorf("expected the provided footer, got %q", got)
+	}
+	if got := textOf(findTag(t, cards[1], "p")); got != "No content" {
+		t.Errorf("expected the fallback body, got %q", got)
+	}
+	if got := textOf(findTag(t, cards[1], "span")); got != "Default footer" {
+		t.Errorf("expected the fallback footer, got %q", got)
+	}`,
+		},
+		{
+			name: "slotfallbackunknown",
+			files: map[string]string{
+				"card.go": `package slotfallbackunknown
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type Card struct {
+	runtime.ComponentBase
+	Body []*vdom.VNode
+}
+`,
+				"Card.gt.html": `<section>
+    {@slot Content}<p>No content</p>{@endslot}
+</section>
+`,
+			},
+			wantErr: []string{"Card.gt.html:2: {@slot Content} does not name a content slot of component 'Card'. Slot fields: [Body]"},
+		},
+		{
+			name: "propconversions",
+			files: map[string]string{
+				"gauge.go": `package propconversions
+
+import (
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+type Percent float32
+
+type Gauge struct {
+	runtime.ComponentBase
+	Price   float64
+	Ratio   Percent
+	Level   uint8
+	Offset  int64
+	Timeout time.Duration
+	Since   time.Time
+	Score   float64
+	Enabled bool
+}
+
+type Dashboard struct {
+	runtime.ComponentBase
+	Hits int
+}
+`,
+				"Gauge.gt.html": `<div>
+    <span>{Price} {Ratio} {Level} {Offset} {Timeout} {Score} {Enabled}</span>
+    <span>{Since | date "2006-01-02 15:04"}</span>
+</div>
+`,
+				"Dashboard.gt.html": `<Gauge Price="19.99" Ratio="0.5" Level="0xff" Offset="-42" Timeout="1m30s" Since="2024-03-04T10:30:00Z" Score="{Hits}" Enabled="true"></Gauge>
+`,
+			},
+			test: `
+	dashboard := &Dashboard{Hits: 3}
+	renderer := rendertest.NewTestRenderer(dashboard)
+	root := renderer.RenderRoot()
+
+	spans := findAllTags(root, "span")
+	if len(spans) != 2 {
+		t.Fatalf("expected the gauge, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if got := textOf(spans[0]); got != "19.99 0.5 255 -42 1m30s 3 true" {
+		t.Errorf("expected the converted literals and binding, got %q", got)
+	}
+	if got := textOf(spans[1]); got != "2024-03-04 10:30" {
+		t.Errorf("expected the converted time, got %q", got)
+	}`,
+		},
+		{
+			name: "propconversionmalformed",
+			files: map[string]string{
+				"gauge.go": `package propconversionmalformed
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Gauge struct {
+	runtime.ComponentBase
+	Price float64
+	Level uint8
+}
+
+type Page struct {
+	runtime.ComponentBase
+}
+`,
+				"Gauge.gt.html": `<span>{Price} {Level}</span>
+`,
+				"Page.gt.html": `<div>
+    <Gauge Price="abc"></Gauge>
+    <Gauge Level="300"></Gauge>
+</div>
+`,
+			},
+			wantErr: []string{
+				"Page.gt.html:2: Value 'abc' of prop 'Price' is not a valid float64: invalid syntax",
+				"Page.gt.html:3: Value '300' of prop 'Level' is not a valid uint8: value out of range",
+			},
+		},
+		{
+			name: "propbindingtype",
+			files: map[string]string{
+				"gauge.go": `package propbindingtype
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Gauge struct {
+	runtime.ComponentBase
+	Price float64
+}
+
+type Page struct {
+	runtime.ComponentBase
+	Label string
+}
+`,
+				"Gauge.gt.html": `<span>{Price}</span>
+`,
+				"Page.gt.html": `<div>
+    <Gauge Price="{Label}"></Gauge>
+</div>
+`,
+			},
+			wantErr: []string{"Page.gt.html:2: Binding '{Label}' is of type 'string', which cannot be passed to prop 'Price' of type 'float64' on component 'Gauge'."},
+		},
+		{
+			name: "compositeprops",
+			files: map[string]string{
+				"models.go": `package compositeprops
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type User struct {
+	Name string
+}
+
+type Category struct {
+	ID       int
+	Products []string
+}
+
+type Session struct {
+	Owner User
+}
+
+type UserTable struct {
+	runtime.ComponentBase
+	Rows     []User
+	Selected *User
+	Counts   map[string]int
+	Owner    User
+}
+
+func (t *UserTable) RowCount() int { return len(t.Rows) }
+
+func (t *UserTable) CountKeys() int { return len(t.Counts) }
+
+type ProductList struct {
+	runtime.ComponentBase
+	Items []string
+}
+
+type Directory struct {
+	runtime.ComponentBase
+	Users      []User
+	Current    *User
+	Counts     map[string]int
+	Session    *Session
+	Categories []Category
+}
+`,
+				"UserTable.gt.html": `<div>
+    <span class="rows">{RowCount()}</span>
+    <span class="counts">{CountKeys()}</span>
+    <span class="owner">{Owner.Name}</span>
+</div>
+`,
+				"ProductList.gt.html": `<ul>
+    {@for _, item := range Items trackBy item}
+    <li>{item}</li>
+    {@endfor}
+</ul>
+`,
+				"Directory.gt.html": `<div>
+    <UserTable Rows="{Users}" Selected="{Current}" Counts="{Counts}" Owner="{Session.Owner}"></UserTable>
+    {@for _, category := range Categories trackBy category.ID}
+    <ProductList Items="{category.Products}"></ProductList>
+    {@endfor}
+</div>
+`,
+			},
+			test: `
+	dir := &Directory{
+		Users:      []User{{Name: "Ada"}, {Name: "Grace"}},
+		Counts:     map[string]int{"admins": 1},
+		Categories: []Category{{ID: 1, Products: []string{"Pen", "Ink"}}},
+	}
+	renderer := rendertest.NewTestRenderer(dir)
+	root := renderer.RenderRoot()
+
+	spans := findAllTags(root, "span")
+	if len(spans) != 3 || textOf(spans[0]) != "2" || textOf(spans[1]) != "1" || textOf(spans[2]) != "" {
+		t.Fatalf("expected the table to receive the users, counts and a zero owner, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if items := findAllTags(root, "li"); len(items) != 2 || textOf(items[1]) != "Ink" {
+		t.Errorf("expected the products of the category, got:\n%s", rendertest.FormatVNode(root))
+	}
+
+	dir.Session = &Session{Owner: User{Name: "Linus"}}
+	renderer.ReRender()
+	if got := textOf(findAllTags(renderer.GetCurrentVDOM(), "span")[2]); got != "Linus" {
+		t.Errorf("expected the session owner, got %q", got)
+	}`,
+		},
+		{
+			name: "compositepropmismatch",
+			files: map[string]string{
+				"models.go": `package compositepropmismatch
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type User struct {
+	Name string
+}
+
+type Product struct {
+	Title string
+}
+
+type UserTable struct {
+	runtime.ComponentBase
+	Caption string
+	Rows    []User
+}
+
+type Catalog struct {
+	runtime.ComponentBase
+	Products []Product
+}
+`,
+				"UserTable.gt.html": `<div>{Caption}</div>
+`,
+				"Catalog.gt.html": `<section>
+    <UserTable Rows="{Products}"></UserTable>
+</section>
+`,
+			},
+			wantErr: []string{"Catalog.gt.html:2: Binding '{Products}' is of type '[]Product', which cannot be passed to prop 'Rows' of type '[]User' on component 'UserTable'."},
+		},
+		{
+			name: "escapedliterals",
+			files: map[string]string{
+				"note.go": `package escapedliterals
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Note struct {
+	runtime.ComponentBase
+	Count int
+}
+`,
+				"Note.gt.html": `<div title='Say "hi"' data-path="C:\temp\new" data-json='{"a": [1, "\u00e9"]}' data-lines="one
+two" data-entity="Fish &amp; Chips &quot;fresh&quot;" data-template="&lbrace;name&rbrace;">
+    <p>Fish &amp; Chips &lt;3 &copy; 100% \n "quoted"</p>
+    <span>{Count}% of C:\temp &amp; more</span>
+    <em>&#123;Count&#x7D; is {Count}</em>
+</div>
+`,
+			},
+			test: `
+	root := rendertest.NewTestRenderer(&Note{Count: 5}).RenderRoot()
+
+	wantAttrs := map[string]string{
+		"title":         "Say \"hi\"",
+		"data-path":     "C:\\temp\\new",
+		"data-json":     "{\"a\": [1, \"\\u00e9\"]}",
+		"data-lines":    "one\ntwo",
+		"data-entity":   "Fish & Chips \"fresh\"",
+		"data-template": "{name}",
+	}
+	for key, want := range wantAttrs {
+		if got := root.Attributes[key]; got != want {
+			t.Errorf("expected %s to be %q, got %q", key, want, got)
+		}
+	}
+	if got := textOf(findTag(t, root, "p")); got != "Fish & Chips <3 \u00a9 100% \\n \"quoted\"" {
+		t.Errorf("expected the decoded paragraph, got %q", got)
+	}
+	if got := textOf(findTag(t, root, "span")); got != "5% of C:\\temp & more" {
+		t.Errorf("expected the bound text with its literals, got %q", got)
+	}
+	if got := textOf(findTag(t, root, "em")); got != "{Count} is 5" {
+		t.Errorf("expected escaped braces to stay text, got %q", got)
+	}`,
+		},
+		{
+			name: "templatecomments",
+			files: map[string]string{
+				"page.go": `package templatecomments
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+type Card struct {
+	runtime.ComponentBase
+	Body []*vdom.VNode
+}
+
+type Banner struct {
+	runtime.ComponentBase
+	Show bool
+}
+
+type Page struct {
+	runtime.ComponentBase
+	Name   string
+	Mode   string
+	Markup string
+}
+`,
+				"Card.gt.html": `<section>{Body}</section>
+`,
+				"Banner.gt.html": `{@if Show}
+    <!-- shown when set -->
+    <strong>On</strong>
+{@else}
+    <em>Off</em>
+{@endif}
+`,
+				"Page.gt.html": `{@* The page. {@if Missing} and {Unknown} are ignored here,
+   as is <Card> markup. *@}
+<div>
+    <!-- {@if Name} in an HTML comment is not a directive -->
+    <p>Hello <!-- greeting -->{Name}</p>
+    {@switch Mode}
+    <!-- the modes -->
+    {@case 'a'}
+    <span>A</span>
+    {@default}
+    <span>Other</span>
+    {@endswitch}
+    <Card>
+        <!-- card body -->
+        <b>Body</b>
+    </Card>
+    <article [innerhtml]="{Markup}"><!-- replaced by the markup --></article>
+    <ul>{@* one item for now {@for} *@}<li>One</li></ul>
+</div>
+`,
+			},
+			test: `
+	root := rendertest.NewTestRenderer(&Page{Name: "Ada", Mode: "a", Markup: "<i>x</i>"}).RenderRoot()
+
+	if len(root.Children) != 5 {
+		t.Fatalf("expected comments to render nothing, got %d children", len(root.Children))
+	}
+	if got := textOf(findTag(t, root, "p")); got != "Hello Ada" {
+		t.Errorf("expected the text around the comment to join, got %q", got)
+	}
+	if got := textOf(findTag(t, root, "span")); got != "A" {
+		t.Errorf("expected the matching case, got %q", got)
+	}
+	if section := findTag(t, root, "section"); len(section.Children) != 1 || textOf(section.Children[0]) != "Body" {
+		t.Errorf("expected only the <b> in the slot, got %d children", len(section.Children))
+	}
+	if items := findTag(t, root, "ul").Children; len(items) != 1 {
+		t.Errorf("expected one list item, got %d", len(items))
+	}
+
+	banner := rendertest.NewTestRenderer(&Banner{Show: true}).RenderRoot()
+	if banner.Tag != "strong" {
+		t.Errorf("expected the commented branch to render <strong>, got <%s>", banner.Tag)
+	}`,
+		},
+		{
+			name: "templatecommentunclosed",
+			files: map[string]string{
+				"note.go": `package templatecommentunclosed
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Note struct {
+	runtime.ComponentBase
+}
+`,
+				"Note.gt.html": `<div>
+    <p>Text</p>
+    {@* forgot to close
+</div>
+`,
+			},
+			wantErr: []string{"Note.gt.html:3: comment {@* is not closed with *@}"},
+		},
+		{
+			name: "handlerkeys",
+			files: map[string]string{
+				"counter.go": `package handlerkeys
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Counter struct {
+	runtime.ComponentBase
+	Count int
+	Label string
+	Items []string
+}
+
+func (c *Counter) Increment()         { c.Count++ }
+func (c *Counter) Remove(item string) {}
+`,
+				"Counter.gt.html": `<div>
+    <button @onclick="Increment">{Count}</button>
+    <input type="text" @bind="Label" />
+    <ul>
+    {@for _, item := range Items trackBy item}
+        <li @onclick="Remove(item)">{item}</li>
+    {@endfor}
+    </ul>
+</div>
+`,
+			},
+			test: `
+	root := rendertest.NewTestRenderer(&Counter{Items: []string{"a", "b"}}).RenderRoot()
+
+	wantKeys := map[string]map[string]string{
+		"button": {"onClick": "Counter.Increment"},
+		"input":  {"onInput": "Counter.bind:Label"},
+		"li":     {"onClick": "Counter.Remove"},
+	}
+	for tag, want := range wantKeys {
+		node := findTag(t, root, tag)
+		if _, ok := node.Attributes[vdom.HandlerKeysAttr]; ok {
+			t.Errorf("<%s>: expected the handler keys to be left out of the attributes", tag)
+		}
+		for attr, key := range want {
+			if got := node.HandlerKeys[attr]; got != key {
+				t.Errorf("<%s>: expected %s to have the handler key %q, got %q (%v)", tag, attr, key, got, node.HandlerKeys)
+			}
+		}
+	}`,
+		},
+	})
+}
@@ -0,0 +1,38 @@
+//go:build !wasm
+// +build !wasm
+
+package compiler
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestLineDirectives_BuildErrorNamesTemplate verifies a build error in the code generated for
+// a template node is reported at the node's line in the template, not in the generated file.
+func TestLineDirectives_BuildErrorNamesTemplate(t *testing.T) {
+	// Arrange
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	generatedSourceHook = func(comp componentInfo, source []byte) []byte {
+		return []byte(strings.Replace(string(source), `"%v", c.Label`, `"%v", c.Caption`, 1))
+	}
+	t.Cleanup(func() { generatedSourceHook = nil })
+	if err := compileProbe(t); err != nil {
+		t.Fatalf("expected the fixture to compile, got %v", err)
+	}
+
+	// Act
+	out, err := exec.Command(goTool, "build", "./"+probeDir).CombinedOutput()
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected the generated code to fail to build")
+	}
+	if !strings.Contains(string(out), "Probe.gt.html:2") || !strings.Contains(string(out), "c.Caption undefined") {
+		t.Errorf("expected the error to point at line 2 of the template, got:\n%s", out)
+	}
+}
@@ -0,0 +1,220 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// LoaderConfig configures the generated bootstrap loader script.
+// The loader feature-detects WebAssembly support, shows FallbackHTML inside the mount
+// element when the browser cannot run the app, and otherwise loads wasm_exec.js and the
+// module while LoadingHTML is displayed.
+type LoaderConfig struct {
+	WasmURL       string // URL of the compiled module (e.g., "main.wasm")
+	WasmExecURL   string // URL of Go's wasm_exec.js support script
+	MountSelector string // CSS selector of the mount element (e.g., "#app")
+	LoadingHTML   string // Shown while the module downloads and instantiates
+	FallbackHTML  string // Shown when WebAssembly or fetch is unavailable
+	ErrorHTML     string // Shown when the download, instantiation or Go boot fails
+	BeaconURL     string // Optional: failures are POSTed here as JSON (empty disables reporting)
+}
+
+// DefaultLoaderConfig returns the loader configuration matching the default app layout.
+func DefaultLoaderConfig() LoaderConfig {
+	return LoaderConfig{
+		WasmURL:       "main.wasm",
+		WasmExecURL:   "wasm_exec.js",
+		MountSelector: "#app",
+		LoadingHTML:   `<div class="nojs-loading">Loading…</div>`,
+		FallbackHTML:  `<div class="nojs-fallback">This application requires WebAssembly, which is not available in your browser. Please use an up-to-date browser or enable WebAssembly.</div>`,
+		ErrorHTML:     `<div class="nojs-error">Something went wrong while starting the application. Please reload the page.</div>`,
+	}
+}
+
+// GenerateLoader renders the bootstrap loader script for cfg.
+func GenerateLoader(cfg LoaderConfig) ([]byte, error) {
+	if strings.TrimSpace(cfg.WasmURL) == "" {
+		return nil, fmt.Errorf("loader: WasmURL must not be empty")
+	}
+	if strings.TrimSpace(cfg.WasmExecURL) == "" {
+		return nil, fmt.Errorf("loader: WasmExecURL must not be empty")
+	}
+	if strings.TrimSpace(cfg.MountSelector) == "" {
+		return nil, fmt.Errorf("loader: MountSelector must not be empty")
+	}
+
+	var buf bytes.Buffer
+	if err := loaderTemplate.Execute(&buf, cfg); err != nil {
+		return nil, fmt.Errorf("loader: failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteLoader generates the loader script for cfg and writes it to path.
+func WriteLoader(path string, cfg LoaderConfig) error {
+	source, err := GenerateLoader(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, source, 0644)
+}
+
+// jsString encodes s as a JavaScript string literal. json.Marshal escapes <, > and &,
+// so HTML snippets cannot terminate an inline <script> block.
+func jsString(s string) (string, error) {
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+var loaderTemplate = template.Must(template.New("loader").Funcs(template.FuncMap{"js": jsString}).Parse(`// Code generated by the nojs AOT compiler. DO NOT EDIT.
+// nojs bootstrap loader: detects WebAssembly support, shows a fallback when it is
+// missing, and otherwise loads wasm_exec.js and the app module.
+(function () {
+    "use strict";
+
+    var config = {
+        wasmURL: {{js .WasmURL}},
+        wasmExecURL: {{js .WasmExecURL}},
+        mountSelector: {{js .MountSelector}},
+        loadingHTML: {{js .LoadingHTML}},
+        fallbackHTML: {{js .FallbackHTML}},
+        errorHTML: {{js .ErrorHTML}},
+        beaconURL: {{js .BeaconURL}}
+    };
+
+    var failed = false;
+
+    function show(html) {
+        var mount = document.querySelector(config.mountSelector);
+        if (mount) {
+            mount.innerHTML = html;
+        }
+    }
+
+    function report(reason, detail) {
+        if (!config.beaconURL) {
+            return;
+        }
+        var payload = JSON.stringify({
+            reason: reason,
+            detail: String(detail || ""),
+            userAgent: navigator.userAgent,
+            url: location.href
+        });
+        try {
+            if (navigator.sendBeacon) {
+                navigator.sendBeacon(config.beaconURL, payload);
+            } else if (typeof fetch === "function") {
+                fetch(config.beaconURL, { method: "POST", body: payload, keepalive: true });
+            }
+        } catch (e) {
+            // Reporting must never break the fallback UI
+        }
+    }
+
+    function fail(reason, detail, html) {
+        if (failed) {
+            return;
+        }
+        failed = true;
+        console.error("[nojs] Boot failed (" + reason + "):", detail);
+        show(html);
+        report(reason, detail);
+    }
+
+    // Returns the reason the browser cannot run the app, or "" if it can.
+    function unsupportedReason() {
+        if (typeof WebAssembly !== "object" || typeof WebAssembly.instantiate !== "function") {
+            return "no-webassembly";
+        }
+        if (typeof fetch !== "function" || typeof Promise !== "function") {
+            return "no-fetch";
+        }
+        return "";
+    }
+
+    function bootError(reason, detail) {
+        var err = new Error(String(detail));
+        err.nojsReason = reason;
+        return err;
+    }
+
+    function download() {
+        return fetch(config.wasmURL).then(function (response) {
+            if (!response.ok) {
+                throw bootError("network", "HTTP " + response.status + " fetching " + config.wasmURL);
+            }
+            return response;
+        }, function (err) {
+            throw bootError("network", err);
+        });
+    }
+
+    function instantiate(response, importObject) {
+        var fromBuffer = function () {
+            return response.arrayBuffer().then(function (bytes) {
+                return WebAssembly.instantiate(bytes, importObject);
+            }, function (err) {
+                throw bootError("network", err);
+            });
+        };
+        if (typeof WebAssembly.instantiateStreaming !== "function") {
+            return fromBuffer();
+        }
+        // Streaming needs the application/wasm MIME type; fall back to buffering otherwise.
+        var copy = response.clone();
+        return WebAssembly.instantiateStreaming(response, importObject).catch(function () {
+            response = copy;
+            return fromBuffer();
+        });
+    }
+
+    function start() {
+        var go = new Go();
+        download().then(function (response) {
+            return instantiate(response, go.importObject);
+        }).then(function (result) {
+            go.run(result.instance);
+            console.log("WebAssembly module loaded.");
+        }).catch(function (err) {
+            fail(err.nojsReason || "instantiate", err, config.errorHTML);
+        });
+    }
+
+    // Called from Go (runtime.ReportBootError) when startup fails after instantiation.
+    window.nojsBootError = function (message) {
+        fail("boot", message, config.errorHTML);
+    };
+
+    function boot() {
+        var reason = unsupportedReason();
+        if (reason) {
+            fail(reason, "", config.fallbackHTML);
+            return;
+        }
+
+        show(config.loadingHTML);
+
+        var script = document.createElement("script");
+        script.src = config.wasmExecURL;
+        script.onload = start;
+        script.onerror = function () {
+            fail("network", "failed to load " + config.wasmExecURL, config.errorHTML);
+        };
+        document.head.appendChild(script);
+    }
+
+    if (document.readyState === "loading") {
+        document.addEventListener("DOMContentLoaded", boot);
+    } else {
+        boot();
+    }
+})();
+`))
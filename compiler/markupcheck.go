@@ -0,0 +1,459 @@
+package compiler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// html.Parse applies the HTML5 tree-construction rules, which silently repair markup the
+// author may not have meant: a <div> inside a <p> closes the paragraph, content inside a
+// <table> outside a cell is moved in front of the table, a <td> outside a table is dropped,
+// and <Comp /> leaves a non-void element open. The passes below compare the tree the parser
+// built with the nesting written in the source and report the first element that ended up
+// somewhere else.
+
+// markupIssue is an element whose place in the parsed tree differs from the source.
+type markupIssue struct {
+	Line    int    // Source line of the element (1-based)
+	Tag     string // Element tag as written in the source
+	Problem string // What happened to the element
+	Reason  string // The HTML5 parsing rule most likely responsible
+}
+
+// sourceElement is an element start tag found by scanning the template source.
+type sourceElement struct {
+	Tag         string // Lowercased, as the parser sees it
+	Name        string // As written, e.g. PreloadNav
+	Line        int
+	Raw         string // Start tag as written, with its attributes
+	Parent      int    // Index of the enclosing sourceElement, -1 at the top level
+	SelfClosing bool   // Written as <tag /> although tag is not a void element
+}
+
+// parsedElement is an element of the tree built by html.Parse.
+type parsedElement struct {
+	Tag    string
+	Node   *html.Node
+	Parent int // Index of the enclosing parsedElement, -1 at the top level
+}
+
+// voidElements never have content or an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "param": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// optionalEndTags are elements whose end tag may be omitted; the parser closes them
+// implicitly, so leaving them open is not an imbalance.
+var optionalEndTags = map[string]bool{
+	"p": true, "li": true, "dt": true, "dd": true, "option": true, "optgroup": true,
+	"tr": true, "td": true, "th": true, "thead": true, "tbody": true, "tfoot": true,
+	"colgroup": true, "caption": true, "rb": true, "rt": true, "rp": true,
+}
+
+// closesParagraph lists the start tags that implicitly close an open <p>.
+var closesParagraph = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "center": true,
+	"details": true, "dialog": true, "dir": true, "div": true, "dl": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hgroup": true, "hr": true,
+	"main": true, "menu": true, "nav": true, "ol": true, "p": true, "pre": true,
+	"section": true, "summary": true, "table": true, "ul": true, "li": true, "dd": true,
+	"dt": true, "listing": true, "xmp": true,
+}
+
+// impliedEndTags lists, per start tag, the open elements it closes implicitly when they are
+// innermost (a <li> closes the previous <li>), the end tags authors routinely leave out.
+var impliedEndTags = map[string][]string{
+	"li":       {"li"},
+	"dt":       {"dt", "dd"},
+	"dd":       {"dt", "dd"},
+	"option":   {"option"},
+	"optgroup": {"option", "optgroup"},
+	"tr":       {"td", "th", "tr"},
+	"td":       {"td", "th"},
+	"th":       {"td", "th"},
+	"thead":    {"td", "th", "tr", "thead", "tbody", "tfoot"},
+	"tbody":    {"td", "th", "tr", "thead", "tbody", "tfoot"},
+	"tfoot":    {"td", "th", "tr", "thead", "tbody", "tfoot"},
+	"p":        {"p"},
+}
+
+// tableParts are the elements that are only valid inside a <table>.
+var tableParts = map[string]bool{
+	"caption": true, "colgroup": true, "col": true, "thead": true, "tbody": true,
+	"tfoot": true, "tr": true, "td": true, "th": true,
+}
+
+// tableContent lists, per table-context element, the children the parser keeps in place.
+var tableContent = map[string]map[string]bool{
+	"table": {"caption": true, "colgroup": true, "col": true, "thead": true, "tbody": true, "tfoot": true, "tr": true, "td": true, "th": true, "script": true, "style": true, "template": true},
+	"thead": {"tr": true, "td": true, "th": true, "script": true, "style": true, "template": true},
+	"tbody": {"tr": true, "td": true, "th": true, "script": true, "style": true, "template": true},
+	"tfoot": {"tr": true, "td": true, "th": true, "script": true, "style": true, "template": true},
+	"tr":    {"td": true, "th": true, "script": true, "style": true, "template": true},
+}
+
+// verifyTemplateStructure compares the tree html.Parse built from src (the template after
+// directive preprocessing) with the nesting written in src. It returns the first element
+// the parser relocated or dropped, or the first unbalanced tag, by source line; nil when
+// the parsed tree matches the source.
+func verifyTemplateStructure(src string, doc *html.Node) *markupIssue {
+	source, issues := scanSourceElements(src)
+	parsed := collectParsedElements(doc)
+	issues = append(issues, compareStructure(source, parsed)...)
+
+	var first *markupIssue
+	for i := range issues {
+		if first == nil || issues[i].Line < first.Line {
+			first = &issues[i]
+		}
+	}
+	return first
+}
+
+// scanSourceElements tokenizes src and records every element with the parent the source
+// gives it, closing only the elements HTML lets authors leave open. It also reports stray
+// end tags and elements that are never closed.
+func scanSourceElements(src string) ([]sourceElement, []markupIssue) {
+	var (
+		elements []sourceElement
+		issues   []markupIssue
+		stack    []int // Indexes of open elements
+		line     = 1
+	)
+	top := func() string {
+		if len(stack) == 0 {
+			return ""
+		}
+		return elements[stack[len(stack)-1]].Tag
+	}
+	popWhile := func(tags ...string) {
+		for len(stack) > 0 {
+			found := false
+			for _, tag := range tags {
+				if top() == tag {
+					found = true
+				}
+			}
+			if !found {
+				return
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if z.Err() != io.EOF {
+				return elements, issues
+			}
+			break
+		}
+		// TagName lowercases the token in place, so keep the raw text first
+		raw := string(z.Raw())
+		tokenLine := line
+		line += strings.Count(raw, "\n")
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if isDocumentTag(tag) {
+				continue
+			}
+
+			// Implied end tags authors routinely rely on
+			popWhile(impliedEndTags[tag]...)
+
+			parent := -1
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			elements = append(elements, sourceElement{
+				Tag:         tag,
+				Name:        writtenTagName(raw, tag),
+				Line:        tokenLine,
+				Raw:         raw,
+				Parent:      parent,
+				SelfClosing: tt == html.SelfClosingTagToken && !voidElements[tag],
+			})
+			// <tag /> is taken as the author meant it: closed
+			if tt == html.StartTagToken && !voidElements[tag] {
+				stack = append(stack, len(elements)-1)
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if isDocumentTag(tag) || voidElements[tag] {
+				continue
+			}
+			open := -1
+			for i := len(stack) - 1; i >= 0; i-- {
+				if elements[stack[i]].Tag == tag {
+					open = i
+					break
+				}
+			}
+			if open < 0 {
+				// The conditional preprocessor closes every branch kind it might have
+				// opened, so stray </go-*> tags are expected
+				if !strings.HasPrefix(tag, "go-") {
+					name := writtenTagName(raw, tag)
+					issues = append(issues, markupIssue{
+						Line:    tokenLine,
+						Tag:     name,
+						Problem: fmt.Sprintf("end tag </%s> has no matching start tag", name),
+						Reason:  "the HTML parser ignores end tags that close nothing",
+					})
+				}
+				continue
+			}
+			for _, idx := range stack[open+1:] {
+				el := elements[idx]
+				if !optionalEndTags[el.Tag] && !strings.HasPrefix(el.Tag, "go-") {
+					issues = append(issues, markupIssue{
+						Line:    el.Line,
+						Tag:     el.Name,
+						Problem: fmt.Sprintf("<%s> is not closed before </%s> on line %d", el.Name, writtenTagName(raw, tag), tokenLine),
+						Reason:  fmt.Sprintf("the HTML parser closes it at that end tag; check for a missing </%s>", el.Name),
+					})
+				}
+			}
+			stack = stack[:open]
+		}
+	}
+
+	// Report the innermost element left open: it is closest to the missing end tag
+	for i := len(stack) - 1; i >= 0; i-- {
+		el := elements[stack[i]]
+		if !optionalEndTags[el.Tag] && !strings.HasPrefix(el.Tag, "go-") {
+			issues = append(issues, markupIssue{
+				Line:    el.Line,
+				Tag:     el.Name,
+				Problem: fmt.Sprintf("<%s> is never closed", el.Name),
+				Reason:  "the HTML parser closes it at the end of the template, so everything after it is nested inside it",
+			})
+			break
+		}
+	}
+	return elements, issues
+}
+
+// writtenTagName returns the tag name as written in the raw start or end tag, falling
+// back to the lowercased tag.
+func writtenTagName(raw, tag string) string {
+	name := strings.TrimLeft(raw, "</")
+	if end := strings.IndexAny(name, " \t\r\n/>"); end >= 0 {
+		name = name[:end]
+	}
+	if !strings.EqualFold(name, tag) {
+		return tag
+	}
+	return name
+}
+
+// isDocumentTag reports whether tag is one of the document-level elements html.Parse
+// always creates; they are left out of the comparison.
+func isDocumentTag(tag string) bool {
+	return tag == "html" || tag == "head" || tag == "body"
+}
+
+// collectParsedElements lists the elements of doc in document order, skipping the
+// document-level elements html.Parse wraps every template in.
+func collectParsedElements(doc *html.Node) []parsedElement {
+	var elements []parsedElement
+	var walk func(n *html.Node, parent int)
+	walk = func(n *html.Node, parent int) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if isDocumentTag(c.Data) {
+				walk(c, parent)
+				continue
+			}
+			elements = append(elements, parsedElement{Tag: c.Data, Node: c, Parent: parent})
+			walk(c, len(elements)-1)
+		}
+	}
+	walk(doc, -1)
+	return elements
+}
+
+// compareStructure aligns the source and parsed element sequences by tag and reports each
+// source element the parser dropped, moved elsewhere, or placed under a different parent.
+// Parsed elements with no source counterpart (an implied <tbody>, the empty <p> a stray
+// </p> creates) are transparent: their children count as children of their parent.
+func compareStructure(source []sourceElement, parsed []parsedElement) []markupIssue {
+	toParsed, toSource := alignElements(source, parsed)
+
+	// matchedParent climbs past parsed elements the source has no counterpart for
+	matchedParent := func(j int) int {
+		p := parsed[j].Parent
+		for p >= 0 && toSource[p] < 0 {
+			p = parsed[p].Parent
+		}
+		return p
+	}
+
+	var issues []markupIssue
+	for i, el := range source {
+		j := toParsed[i]
+		if j < 0 {
+			problem := fmt.Sprintf("<%s> is dropped by the HTML parser", el.Name)
+			for k, p := range parsed {
+				if toSource[k] < 0 && p.Tag == el.Tag {
+					problem = fmt.Sprintf("<%s> is moved elsewhere by the HTML parser", el.Name)
+					break
+				}
+			}
+			issues = append(issues, markupIssue{Line: el.Line, Tag: el.Name, Problem: problem, Reason: explainMove(source, toSource, i, -1)})
+			continue
+		}
+
+		expected := -1
+		if el.Parent >= 0 {
+			expected = toParsed[el.Parent]
+			if expected < 0 {
+				continue // The parent itself is already reported
+			}
+		}
+		if actual := matchedParent(j); actual != expected {
+			where := "the top level of the template"
+			if actual >= 0 {
+				owner := source[toSource[actual]]
+				where = fmt.Sprintf("<%s> (line %d)", owner.Name, owner.Line)
+			}
+			issues = append(issues, markupIssue{
+				Line:    el.Line,
+				Tag:     el.Name,
+				Problem: fmt.Sprintf("<%s> is moved by the HTML parser into %s", el.Name, where),
+				Reason:  explainMove(source, toSource, i, actual),
+			})
+		}
+	}
+	return issues
+}
+
+// alignElements matches source and parsed elements by tag with a longest common
+// subsequence, returning the parsed index of each source element and the source index of
+// each parsed element (-1 when unmatched). The common prefix and suffix are matched
+// directly, so well-formed templates cost linear time.
+func alignElements(source []sourceElement, parsed []parsedElement) (toParsed, toSource []int) {
+	toParsed = make([]int, len(source))
+	toSource = make([]int, len(parsed))
+	for i := range toParsed {
+		toParsed[i] = -1
+	}
+	for j := range toSource {
+		toSource[j] = -1
+	}
+	match := func(i, j int) {
+		toParsed[i] = j
+		toSource[j] = i
+	}
+
+	lo := 0
+	for lo < len(source) && lo < len(parsed) && source[lo].Tag == parsed[lo].Tag {
+		match(lo, lo)
+		lo++
+	}
+	hiS, hiP := len(source), len(parsed)
+	for hiS > lo && hiP > lo && source[hiS-1].Tag == parsed[hiP-1].Tag {
+		hiS--
+		hiP--
+		match(hiS, hiP)
+	}
+
+	n, m := hiS-lo, hiP-lo
+	if n == 0 || m == 0 {
+		return toParsed, toSource
+	}
+	// lcs[i][j] is the LCS length of source[lo+i:hiS] and parsed[lo+j:hiP]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if source[lo+i].Tag == parsed[lo+j].Tag {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case source[lo+i].Tag == parsed[lo+j].Tag:
+			match(lo+i, lo+j)
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return toParsed, toSource
+}
+
+// explainMove names the HTML5 parsing rule most likely responsible for moving or dropping
+// source element i; actual is its parent in the parsed tree (-1 for none or dropped).
+func explainMove(source []sourceElement, toSource []int, i, actual int) string {
+	el := source[i]
+
+	if actual >= 0 {
+		if owner := source[toSource[actual]]; owner.SelfClosing {
+			return fmt.Sprintf("<%s /> does not close a non-void element in HTML: <%s> stays open and swallows the markup after it; write <%s></%s>",
+				owner.Name, owner.Name, owner.Name, owner.Name)
+		}
+	}
+	if el.Parent < 0 {
+		if tableParts[el.Tag] {
+			return fmt.Sprintf("<%s> is only valid inside a <table>; outside one the HTML parser ignores the tag and keeps only its content", el.Tag)
+		}
+		return "the HTML parser applied an HTML5 nesting rule to it"
+	}
+
+	parent := source[el.Parent]
+	switch {
+	case parent.Tag == "p" && closesParagraph[el.Tag]:
+		return fmt.Sprintf("a <p> cannot contain a <%s>: the HTML parser closes the paragraph before it, so the <%s> and the content after it leave the <p>; use a <div> instead of the <p>, or a <span> instead of the <%s>",
+			el.Tag, el.Tag, el.Tag)
+	case tableContent[parent.Tag] != nil && !tableContent[parent.Tag][el.Tag]:
+		return fmt.Sprintf("a <%s> may only contain table sections, rows and cells: the HTML parser moves other content in front of the table (foster parenting); put it inside a <td>", parent.Tag)
+	case tableParts[el.Tag] && !hasAncestor(source, i, "table"):
+		return fmt.Sprintf("<%s> is only valid inside a <table>; outside one the HTML parser ignores the tag and keeps only its content", el.Tag)
+	case parent.Tag == "select" && el.Tag != "option" && el.Tag != "optgroup" && el.Tag != "hr":
+		return fmt.Sprintf("a <select> may only contain <option> and <optgroup>; the HTML parser ignores the <%s> tag", el.Tag)
+	case (el.Tag == "a" || el.Tag == "button" || el.Tag == "form" || el.Tag == "nobr") && hasAncestor(source, i, el.Tag):
+		return fmt.Sprintf("<%s> cannot be nested inside another <%s>: the HTML parser closes the outer one first", el.Tag, el.Tag)
+	}
+	return "the HTML parser applied an HTML5 nesting rule to it"
+}
+
+// hasAncestor reports whether source element i is nested, in the source, inside a tag
+// element.
+func hasAncestor(source []sourceElement, i int, tag string) bool {
+	for p := source[i].Parent; p >= 0; p = source[p].Parent {
+		if source[p].Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// formatMarkupIssue renders issue with its template position and surrounding lines.
+func formatMarkupIssue(templatePath, src string, issue *markupIssue) string {
+	return fmt.Sprintf("%s:%d: %s.\nLikely cause: %s.%s",
+		templatePath, issue.Line, issue.Problem, issue.Reason, getContextLines(src, issue.Line, 2))
+}
@@ -0,0 +1,162 @@
+package compiler
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	goruntime "runtime"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// generatedSymbols lists, by import path, the framework symbols the code generator may emit
+// into a generated file. Generated files carry no build tags so that components and their
+// tests compile natively, which holds only while each of these symbols is declared for both
+// js/wasm and native builds (as a stub where the feature only makes sense in a browser).
+// Add a symbol here whenever a codegen change starts emitting it; verifyGeneratedPlatforms
+// fails the compilation when one of them lacks a declaration on either platform.
+var generatedSymbols = map[string][]string{
+	"github.com/ForgeLogic/nojs/console":    {"Log", "Warn", "WarnOnce"},
+	"github.com/ForgeLogic/nojs/formatters": {"Number", "Upper", "Lower"},
+	"github.com/ForgeLogic/nojs/events": {
+		"AdaptNoArgEvent", "AdaptClickEvent", "AdaptChangeEvent", "AdaptKeyboardEvent",
+		"AdaptMouseEvent", "AdaptFocusEvent", "AdaptFormEvent",
+		"AdaptNoArgEventCtx", "AdaptClickEventCtx", "AdaptChangeEventCtx", "AdaptKeyboardEventCtx",
+		"AdaptMouseEventCtx", "AdaptFocusEventCtx", "AdaptFormEventCtx",
+		"AdaptDragEvent", "AdaptWheelEvent", "AdaptTouchEvent", "AdaptScrollEvent",
+		"AdaptDragEventCtx", "AdaptWheelEventCtx", "AdaptTouchEventCtx", "AdaptScrollEventCtx",
+		"Modify", "Prevent", "Stop", "Once", "Enter", "Escape",
+	},
+	"github.com/ForgeLogic/nojs/runtime": {"Component", "Renderer", "HistoryBag", "Ctx", "SameSlice"},
+	"github.com/ForgeLogic/nojs/vdom": {
+		"VNode", "NewVNode", "Div", "Paragraph", "Button", "Text", "WithRef", "ElementRef", "ElementRefs", "SanitizeURL",
+		"RawHTML", "FormatStyle", "WithTransition", "HandlerKeysAttr",
+	},
+}
+
+// generatedSourceHook, when set, rewrites each generated file before it is verified and
+// written. Tests use it to emit code the generator itself never produces.
+var generatedSourceHook func(comp componentInfo, source []byte) []byte
+
+// verifyPlatforms checks the generated files of a compilation before they are written. The
+// scenario harness replaces it to verify the files of all its scenarios in one pass: each
+// verification type-checks the whole tree once per platform.
+var verifyPlatforms = verifyGeneratedPlatforms
+
+// buildPlatform is a target the generated files must compile for.
+type buildPlatform struct {
+	Name string
+	Env  []string
+}
+
+// generatedFilePlatforms returns the platforms generated files are verified against: the
+// browser build and the native build that runs the component tests.
+func generatedFilePlatforms() []buildPlatform {
+	return []buildPlatform{
+		{Name: "js/wasm", Env: append(os.Environ(), "GOOS=js", "GOARCH=wasm")},
+		{
+			Name: fmt.Sprintf("native (%s/%s)", goruntime.GOOS, goruntime.GOARCH),
+			Env:  append(os.Environ(), "GOOS="+goruntime.GOOS, "GOARCH="+goruntime.GOARCH),
+		},
+	}
+}
+
+// undefinedQualifiedRegex matches the type checker's report of a missing package member.
+var undefinedQualifiedRegex = regexp.MustCompile(`undefined: ([a-zA-Z_][a-zA-Z0-9_]*\.[a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// verifyGeneratedPlatforms type-checks the packages under srcDir for every platform of
+// generatedFilePlatforms, with generated (file path -> source) laid over the files on disk,
+// before anything is written. It fails with an internal consistency error when a symbol of
+// generatedSymbols is missing on a platform, or when a generated file references a framework
+// symbol that is declared on one platform only. Other errors are left to the Go build, which
+// reports them against the written files.
+func verifyGeneratedPlatforms(srcDir string, generated map[string][]byte) error {
+	frameworkPaths := make([]string, 0, len(generatedSymbols))
+	frameworkNames := make(map[string]bool)
+	for path := range generatedSymbols {
+		frameworkPaths = append(frameworkPaths, path)
+		frameworkNames[path[strings.LastIndex(path, "/")+1:]] = true
+	}
+	sort.Strings(frameworkPaths)
+
+	platforms := generatedFilePlatforms()
+	missingOn := make(map[string][]string)      // Symbol -> platforms missing it
+	undefinedOn := make(map[[2]string][]string) // (generated file, symbol) -> platforms missing it
+	for _, platform := range platforms {
+		cfg := &packages.Config{
+			Mode:    packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes,
+			Dir:     srcDir,
+			Env:     platform.Env,
+			Overlay: generated,
+		}
+		pkgs, err := packages.Load(cfg, append([]string{"./..."}, frameworkPaths...)...)
+		if err != nil {
+			return fmt.Errorf("failed to type-check generated code for %s: %w", platform.Name, err)
+		}
+
+		loaded := make(map[string]*packages.Package)
+		for _, pkg := range pkgs {
+			loaded[pkg.PkgPath] = pkg
+		}
+		for _, path := range frameworkPaths {
+			pkg := loaded[path]
+			for _, name := range generatedSymbols[path] {
+				if pkg == nil || pkg.Types == nil || pkg.Types.Scope().Lookup(name) == nil {
+					symbol := fmt.Sprintf("%s.%s", path[strings.LastIndex(path, "/")+1:], name)
+					missingOn[symbol] = append(missingOn[symbol], platform.Name)
+				}
+			}
+		}
+
+		for _, pkg := range pkgs {
+			for _, pkgErr := range pkg.Errors {
+				file := pkgErr.Pos
+				if i := strings.Index(file, ".go:"); i >= 0 {
+					file = file[:i+len(".go")]
+				} else if i := strings.Index(file, ".gt.html:"); i >= 0 {
+					// The line directives of a Render method point at its template
+					file = file[:i] + ".generated.go"
+				}
+				match := undefinedQualifiedRegex.FindStringSubmatch(pkgErr.Msg)
+				if _, isGenerated := generated[file]; !isGenerated || match == nil {
+					continue
+				}
+				if qualifier, _, _ := strings.Cut(match[1], "."); frameworkNames[qualifier] {
+					key := [2]string{file, match[1]}
+					undefinedOn[key] = append(undefinedOn[key], platform.Name)
+				}
+			}
+		}
+	}
+
+	symbols := make([]string, 0, len(missingOn))
+	for symbol := range missingOn {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	if len(symbols) > 0 {
+		symbol := symbols[0]
+		return fmt.Errorf("internal consistency error: generated code may call %s, which is not declared for %s builds.\n"+
+			"  Generated files have no build tags, so %s needs an implementation or stub on every platform",
+			symbol, strings.Join(missingOn[symbol], " and "), symbol)
+	}
+
+	uses := make([][2]string, 0, len(undefinedOn))
+	for key, missing := range undefinedOn {
+		if len(missing) < len(platforms) {
+			uses = append(uses, key)
+		}
+	}
+	sort.Slice(uses, func(i, j int) bool {
+		return uses[i][0] < uses[j][0] || (uses[i][0] == uses[j][0] && uses[i][1] < uses[j][1])
+	})
+	if len(uses) > 0 {
+		use := uses[0]
+		return fmt.Errorf("internal consistency error in %s: generated code calls %s, which is not declared for %s builds.\n"+
+			"  Generated files have no build tags, so %s needs an implementation or stub on every platform",
+			use[0], use[1], strings.Join(undefinedOn[use], " and "), use[1])
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+//go:build !wasm
+// +build !wasm
+
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// probeDir is the fixture package the platform contract tests compile.
+const probeDir = "testdata/platformcheck"
+
+// compileProbe compiles the fixture and removes the generated file afterwards.
+func compileProbe(t *testing.T) error {
+	t.Helper()
+	t.Cleanup(func() { os.Remove(filepath.Join(probeDir, "Probe.generated.go")) })
+	return CompileWithOptions(probeDir, Options{})
+}
+
+// TestVerifyGeneratedPlatforms_AcceptsGeneratedCode verifies the code the generator emits
+// passes the dual-platform check.
+func TestVerifyGeneratedPlatforms_AcceptsGeneratedCode(t *testing.T) {
+	// Act
+	err := compileProbe(t)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected the fixture to compile, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(probeDir, "Probe.generated.go")); err != nil {
+		t.Errorf("expected the generated file to be written: %v", err)
+	}
+}
+
+// TestVerifyGeneratedPlatforms_RejectsWasmOnlyCall verifies a generated file calling an API
+// that exists only in js/wasm builds fails the compilation, naming the symbol, and is not
+// written.
+func TestVerifyGeneratedPlatforms_RejectsWasmOnlyCall(t *testing.T) {
+	// Arrange
+	generatedSourceHook = func(comp componentInfo, source []byte) []byte {
+		return append(source, "\nvar _ = runtime.NewRenderer\n"...)
+	}
+	t.Cleanup(func() { generatedSourceHook = nil })
+
+	// Act
+	err := compileProbe(t)
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected the wasm-only call to fail the compilation")
+	}
+	for _, want := range []string{"internal consistency error", "Probe.generated.go", "runtime.NewRenderer", "native"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to mention %q, got:\n%v", want, err)
+		}
+	}
+	if _, statErr := os.Stat(filepath.Join(probeDir, "Probe.generated.go")); !os.IsNotExist(statErr) {
+		t.Error("expected no generated file to be written")
+	}
+}
+
+// TestVerifyGeneratedPlatforms_RejectsWasmOnlyDeclaredSymbol verifies a symbol the generator
+// declares it may emit must exist in native builds too.
+func TestVerifyGeneratedPlatforms_RejectsWasmOnlyDeclaredSymbol(t *testing.T) {
+	// Arrange
+	const runtimePath = "github.com/ForgeLogic/nojs/runtime"
+	declared := generatedSymbols[runtimePath]
+	generatedSymbols[runtimePath] = append(append([]string(nil), declared...), "NewRenderer")
+	t.Cleanup(func() { generatedSymbols[runtimePath] = declared })
+
+	// Act
+	err := compileProbe(t)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "generated code may call runtime.NewRenderer") {
+		t.Errorf("expected the declared wasm-only symbol to be reported, got %v", err)
+	}
+}
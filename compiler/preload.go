@@ -0,0 +1,409 @@
+package compiler
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// preloadMarker declares assets a component needs in its doc comment, as a type followed
+// by one or more URLs:
+//
+//	//nojs:preload image /img/hero.jpg
+//	//nojs:preload font /fonts/inter.woff2
+//	type HomePage struct { ... }
+const preloadMarker = "//nojs:preload"
+
+// preloadTag marks an unexported field whose struct tag declares one asset:
+//
+//	_ struct{} `nojs:"preload" as:"image" href:"/img/hero.jpg"`
+const preloadTag = "preload"
+
+// chainMarker names the components a typed route renders, layouts first, in the route
+// constant's doc comment of a routes definition file:
+//
+//	//nojs:chain MainLayout BlogPostPage
+//	BlogPost = "/blog/{year:int}/{slug}"
+const chainMarker = "//nojs:chain"
+
+// preloadAssetTypes lists the supported asset types, which are used as the `as` value of
+// the emitted <link rel="preload">.
+var preloadAssetTypes = map[string]bool{
+	"image":  true,
+	"font":   true,
+	"style":  true,
+	"script": true,
+	"fetch":  true,
+}
+
+// componentTagRegex finds element names in a template, to build the component usage graph.
+var componentTagRegex = regexp.MustCompile(`<([A-Za-z][A-Za-z0-9-]*)`)
+
+// fingerprintRegex matches the hash segment build pipelines insert before an extension
+// (e.g., hero.3f9a1c2b.jpg).
+var fingerprintRegex = regexp.MustCompile(`^[0-9a-fA-F]{6,}$`)
+
+// PreloadAsset is an asset a route preloads.
+type PreloadAsset struct {
+	Href string `json:"href"`
+	As   string `json:"as"`
+}
+
+// RoutePreloads lists the assets of every component a typed route renders.
+type RoutePreloads struct {
+	Name    string         `json:"name"`
+	Pattern string         `json:"pattern"`
+	Assets  []PreloadAsset `json:"assets"`
+}
+
+// PreloadConfig configures WritePreloads.
+type PreloadConfig struct {
+	// AssetsDir is the directory asset URLs resolve against (e.g., ./wwwroot). When set,
+	// every declared asset must exist in it, and a fingerprinted copy (hero.3f9a1c2b.jpg
+	// for /hero.jpg) is preloaded in place of the declared name.
+	AssetsDir string
+
+	// ManifestPath, if set, receives the route->assets list as JSON, for servers that send
+	// Link headers instead of serving per-route shells.
+	ManifestPath string
+
+	// ShellPath is the HTML shell (e.g., ./wwwroot/index.html) the per-route shells are
+	// generated from. Required with ShellOutDir.
+	ShellPath string
+
+	// ShellOutDir, if set, receives one shell per route, named <RouteName>.html, with the
+	// route's preload links inserted before </head>.
+	ShellOutDir string
+}
+
+// preloadDeclaration is an asset declared by a component, with its source position for errors.
+type preloadDeclaration struct {
+	Asset    PreloadAsset
+	Position string
+}
+
+// WritePreloads aggregates the preload declarations of the components reachable from each
+// typed route under srcDir and writes the manifest and per-route shells configured in cfg.
+func WritePreloads(srcDir string, cfg PreloadConfig) error {
+	if cfg.ShellOutDir != "" && cfg.ShellPath == "" {
+		return fmt.Errorf("preload shells need a shell template (ShellPath)")
+	}
+
+	routes, err := CollectPreloads(srcDir, cfg.AssetsDir)
+	if err != nil {
+		return err
+	}
+
+	if cfg.ManifestPath != "" {
+		data, err := json.MarshalIndent(routes, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(cfg.ManifestPath, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write preload manifest: %w", err)
+		}
+		fmt.Printf("Generated preload manifest for %d route(s): %s\n", len(routes), cfg.ManifestPath)
+	}
+
+	if cfg.ShellOutDir != "" {
+		shell, err := os.ReadFile(cfg.ShellPath)
+		if err != nil {
+			return fmt.Errorf("failed to read shell template: %w", err)
+		}
+		if err := os.MkdirAll(cfg.ShellOutDir, 0755); err != nil {
+			return err
+		}
+		for _, route := range routes {
+			out, err := InjectPreloadLinks(string(shell), route.Assets)
+			if err != nil {
+				return fmt.Errorf("%s: %w", cfg.ShellPath, err)
+			}
+			if err := os.WriteFile(filepath.Join(cfg.ShellOutDir, route.Name+".html"), []byte(out), 0644); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Generated %d route shell(s) in %s\n", len(routes), cfg.ShellOutDir)
+	}
+	return nil
+}
+
+// CollectPreloads returns, for every typed route under srcDir that names its component
+// chain, the deduplicated assets declared by the chain's components and by every component
+// their templates use, directly or through other components. Layout assets therefore
+// appear for every route rendered inside that layout.
+//
+// Unknown asset types, unknown chain components and, when assetsDir is set, assets missing
+// from assetsDir are errors.
+func CollectPreloads(srcDir string, assetsDir string) ([]RoutePreloads, error) {
+	absSrcDir, err := filepath.Abs(srcDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path for srcDir: %w", err)
+	}
+
+	components, err := discoverAndInspectComponents(absSrcDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover or inspect components: %w", err)
+	}
+	componentMap := make(map[string]componentInfo)
+	for _, comp := range components {
+		componentMap[comp.LowercaseName] = comp
+	}
+
+	declarations := make(map[string][]preloadDeclaration)
+	usages := make(map[string][]string)
+	for _, comp := range components {
+		if declarations[comp.LowercaseName], err = parsePreloadDeclarations(comp); err != nil {
+			return nil, err
+		}
+		if usages[comp.LowercaseName], err = componentUsages(comp, componentMap); err != nil {
+			return nil, err
+		}
+	}
+
+	defFiles, err := findRouteDefinitionFiles(absSrcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []RoutePreloads
+	for _, path := range defFiles {
+		_, routes, isDefinition, err := parseRouteDefinitions(path)
+		if err != nil {
+			return nil, err
+		}
+		if !isDefinition {
+			continue
+		}
+		for _, route := range routes {
+			if len(route.Chain) == 0 {
+				continue
+			}
+			assets, err := routeAssets(route, declarations, usages, assetsDir)
+			if err != nil {
+				return nil, fmt.Errorf("%s: route %s: %w", path, route.Name, err)
+			}
+			result = append(result, RoutePreloads{Name: route.Name, Pattern: route.Pattern, Assets: assets})
+		}
+	}
+	return result, nil
+}
+
+// routeAssets walks the route's chain and the components they use, depth-first in template
+// order, collecting each asset once.
+func routeAssets(route routeDefinition, declarations map[string][]preloadDeclaration, usages map[string][]string, assetsDir string) ([]PreloadAsset, error) {
+	assets := []PreloadAsset{}
+	seenAssets := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	var visit func(name string)
+	var resolveErr error
+	visit = func(name string) {
+		if visited[name] || resolveErr != nil {
+			return
+		}
+		visited[name] = true
+		for _, decl := range declarations[name] {
+			asset, err := resolvePreloadAsset(decl, assetsDir)
+			if err != nil {
+				resolveErr = err
+				return
+			}
+			if seenAssets[asset.Href] {
+				continue
+			}
+			seenAssets[asset.Href] = true
+			assets = append(assets, asset)
+		}
+		for _, used := range usages[name] {
+			visit(used)
+		}
+	}
+
+	for _, name := range route.Chain {
+		if _, ok := declarations[strings.ToLower(name)]; !ok {
+			return nil, fmt.Errorf("%s names unknown component '%s'", chainMarker, name)
+		}
+		visit(strings.ToLower(name))
+	}
+	return assets, resolveErr
+}
+
+// resolvePreloadAsset checks that a local asset exists under assetsDir and returns it with
+// the href of its fingerprinted copy, if the build produced one. Absolute URLs and assets
+// declared without an assetsDir are returned as declared.
+func resolvePreloadAsset(decl preloadDeclaration, assetsDir string) (PreloadAsset, error) {
+	asset := decl.Asset
+	if assetsDir == "" || strings.Contains(asset.Href, "://") || strings.HasPrefix(asset.Href, "//") {
+		return asset, nil
+	}
+
+	rel := filepath.FromSlash(strings.TrimPrefix(asset.Href, "/"))
+	ext := filepath.Ext(rel)
+	candidates, err := filepath.Glob(filepath.Join(assetsDir, strings.TrimSuffix(rel, ext)+".*"+ext))
+	if err != nil {
+		return asset, err
+	}
+	var fingerprinted []string
+	for _, candidate := range candidates {
+		hash := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(candidate), strings.TrimSuffix(filepath.Base(rel), ext)+"."), ext)
+		if fingerprintRegex.MatchString(hash) {
+			fingerprinted = append(fingerprinted, candidate)
+		}
+	}
+
+	switch {
+	case len(fingerprinted) == 1:
+		dir := strings.TrimSuffix(asset.Href, filepath.Base(rel))
+		asset.Href = dir + filepath.Base(fingerprinted[0])
+		return asset, nil
+	case len(fingerprinted) > 1:
+		sort.Strings(fingerprinted)
+		return asset, fmt.Errorf("%s: asset %s has several fingerprinted copies in %s: %s",
+			decl.Position, asset.Href, assetsDir, strings.Join(fingerprinted, ", "))
+	}
+
+	if _, err := os.Stat(filepath.Join(assetsDir, rel)); err != nil {
+		return asset, fmt.Errorf("%s: preloaded asset %s not found in %s", decl.Position, asset.Href, assetsDir)
+	}
+	return asset, nil
+}
+
+// parsePreloadDeclarations reads the assets a component declares, from //nojs:preload lines
+// in its struct's doc comment and from unexported fields tagged `nojs:"preload"`.
+func parsePreloadDeclarations(comp componentInfo) ([]preloadDeclaration, error) {
+	goFilePath := filepath.Join(filepath.Dir(comp.Path), strings.ToLower(comp.PascalName)+".go")
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, goFilePath, nil, parser.ParseComments)
+	if err != nil {
+		// The component's Go file is reported by discovery; it simply declares nothing here
+		return nil, nil
+	}
+
+	var decls []preloadDeclaration
+	var declErr error
+	add := func(pos token.Pos, assetType, href string) {
+		position := fset.Position(pos).String()
+		if !preloadAssetTypes[assetType] {
+			declErr = fmt.Errorf("%s: unknown preload asset type '%s' in component '%s' (supported: font, image, script, style, fetch)",
+				position, assetType, comp.PascalName)
+			return
+		}
+		if href == "" {
+			declErr = fmt.Errorf("%s: preload declaration in component '%s' has no URL", position, comp.PascalName)
+			return
+		}
+		decls = append(decls, preloadDeclaration{Asset: PreloadAsset{Href: href, As: assetType}, Position: position})
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if typeSpec.Name.Name != comp.PascalName {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				doc = genDecl.Doc
+			}
+			if doc != nil {
+				for _, c := range doc.List {
+					rest, ok := strings.CutPrefix(c.Text, preloadMarker)
+					if !ok {
+						continue
+					}
+					fields := strings.Fields(rest)
+					if len(fields) < 2 {
+						add(c.Pos(), strings.Join(fields, ""), "")
+						continue
+					}
+					for _, href := range fields[1:] {
+						add(c.Pos(), fields[0], href)
+					}
+				}
+			}
+
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range structType.Fields.List {
+				if field.Tag == nil || len(field.Names) == 0 || field.Names[0].IsExported() {
+					continue
+				}
+				tagValue, err := strconv.Unquote(field.Tag.Value)
+				if err != nil {
+					continue
+				}
+				tag := reflect.StructTag(tagValue)
+				if tag.Get("nojs") != preloadTag {
+					continue
+				}
+				add(field.Pos(), tag.Get("as"), tag.Get("href"))
+			}
+		}
+	}
+	return decls, declErr
+}
+
+// componentUsages returns the components comp's template uses, in template order.
+func componentUsages(comp componentInfo, componentMap map[string]componentInfo) ([]string, error) {
+	source, err := os.ReadFile(comp.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file %s: %w", comp.Path, err)
+	}
+	// An unclosed compiler comment is reported when the template is compiled
+	stripped, _ := stripTemplateComments(string(source), comp.Path)
+	masked, _ := maskRawRegions(stripped)
+
+	var used []string
+	seen := make(map[string]bool)
+	for _, match := range componentTagRegex.FindAllStringSubmatch(masked, -1) {
+		name := strings.ToLower(match[1])
+		if _, ok := componentMap[name]; ok && !seen[name] && name != comp.LowercaseName {
+			seen[name] = true
+			used = append(used, name)
+		}
+	}
+	return used, nil
+}
+
+// InjectPreloadLinks inserts a <link rel="preload"> for each asset before the shell's
+// </head>, skipping assets the shell already references. Fonts get the crossorigin
+// attribute browsers require to reuse the preloaded response.
+func InjectPreloadLinks(shell string, assets []PreloadAsset) (string, error) {
+	headEnd := strings.Index(strings.ToLower(shell), "</head>")
+	if headEnd < 0 {
+		return "", fmt.Errorf("shell has no </head> to insert preload links before")
+	}
+
+	var links strings.Builder
+	for _, asset := range assets {
+		href := vdom.EscapeAttribute(asset.Href)
+		if strings.Contains(shell, `href="`+href+`"`) {
+			continue
+		}
+		fmt.Fprintf(&links, `    <link rel="preload" href="%s" as="%s"`, href, vdom.EscapeAttribute(asset.As))
+		if asset.As == "font" {
+			links.WriteString(" crossorigin")
+		}
+		links.WriteString(">\n")
+	}
+	return shell[:headEnd] + links.String() + shell[headEnd:], nil
+}
@@ -3,7 +3,11 @@ package compiler
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // preprocessFor preprocesses template source to extract for-loop blocks and replace them with placeholder nodes.
@@ -11,11 +15,11 @@ import (
 // Syntax: {@for index, value := range SliceName trackBy uniqueKeyExpression}{@endfor}
 // The index can be _ to ignore it: {@for _, value := range SliceName trackBy uniqueKeyExpression}
 func preprocessFor(src string, templatePath string) (string, error) {
-	// Regex to match ONLY: {@for i, user := range Users trackBy user.ID} or {@for _, user := range Users trackBy user.ID}
-	reFor := regexp.MustCompile(`\{\@for\s+([a-zA-Z_][a-zA-Z0-9_]*)\s*,\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*:=\s*range\s+([a-zA-Z_][a-zA-Z0-9_]*)\s+trackBy\s+([a-zA-Z0-9_.]+)\}`)
+	// Regex to match ONLY: {@for i, user := range Users trackBy user.ID} or {@for _, order := range User.Orders trackBy order.ID}
+	reFor := regexp.MustCompile(`\{\@for\s+([a-zA-Z_][a-zA-Z0-9_]*)\s*,\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*:=\s*range\s+([a-zA-Z_][a-zA-Z0-9_.]*)\s+trackBy\s+([a-zA-Z0-9_.]+)\}`)
 
 	// Regex to detect INVALID syntax: {@for user := range Users trackBy user.ID} (missing index/underscore)
-	reForInvalid := regexp.MustCompile(`\{\@for\s+([a-zA-Z_][a-zA-Z0-9_]*)\s*:=\s*range\s+([a-zA-Z_][a-zA-Z0-9_]*)\s+trackBy\s+([a-zA-Z0-9_.]+)\}`)
+	reForInvalid := regexp.MustCompile(`\{\@for\s+([a-zA-Z_][a-zA-Z0-9_]*)\s*:=\s*range\s+([a-zA-Z_][a-zA-Z0-9_.]*)\s+trackBy\s+([a-zA-Z0-9_.]+)\}`)
 
 	reEndFor := regexp.MustCompile(`\{\@endfor\}`)
 
@@ -131,13 +135,14 @@ func preprocessConditionals(src string, templatePath string) (string, error) {
 		}
 	}
 
+	// Conditions are escaped, as comparisons hold quotes and angle brackets (Status == 'on', Count > 0)
 	src = reIf.ReplaceAllStringFunc(src, func(m string) string {
 		cond := reIf.FindStringSubmatch(m)[1]
-		return fmt.Sprintf("<go-conditional><go-if data-cond=\"%s\">", cond)
+		return fmt.Sprintf("<go-conditional><go-if data-cond=\"%s\">", html.EscapeString(cond))
 	})
 	src = reElseIf.ReplaceAllStringFunc(src, func(m string) string {
 		cond := reElseIf.FindStringSubmatch(m)[1]
-		return fmt.Sprintf("</go-if><go-elseif data-cond=\"%s\">", cond)
+		return fmt.Sprintf("</go-if><go-elseif data-cond=\"%s\">", html.EscapeString(cond))
 	})
 	// Handle {@else} - it closes the previous branch and opens go-else
 	src = reElse.ReplaceAllString(src, func() string {
@@ -149,3 +154,228 @@ func preprocessConditionals(src string, templatePath string) (string, error) {
 	src = reEndIf.ReplaceAllString(src, "</go-if></go-elseif></go-else></go-conditional>")
 	return src, nil
 }
+
+// preprocessSwitch preprocesses template source to turn switch blocks into placeholder nodes.
+// Syntax: {@switch Field}{@case 'a'}...{@case 'b'}...{@default}...{@endswitch}
+// Unlike {@if}, the directives are paired with an explicit stack so that switches can nest
+// inside case bodies without a stray closing tag ending the outer case.
+func preprocessSwitch(src string, templatePath string) (string, error) {
+	reDirective := regexp.MustCompile(`\{\@(switch\s+[^}]+|case\s+[^}]+|default|endswitch)\}`)
+
+	type openSwitch struct {
+		line       int
+		caseOpen   bool // A <go-case> or <go-default> is currently open
+		hasDefault bool
+	}
+	var stack []*openSwitch
+
+	lineOf := func(offset int) int {
+		return strings.Count(src[:offset], "\n") + 1
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, loc := range reDirective.FindAllStringSubmatchIndex(src, -1) {
+		out.WriteString(src[last:loc[0]])
+		last = loc[1]
+
+		directive := src[loc[2]:loc[3]]
+		line := lineOf(loc[0])
+		keyword := strings.Fields(directive)[0]
+		arg := strings.TrimSpace(strings.TrimPrefix(directive, keyword))
+
+		switch keyword {
+		case "switch":
+			if !regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`).MatchString(arg) {
+				return "", fmt.Errorf("template syntax error in %s:%d: invalid {@switch} expression '%s'.\n"+
+					"  The switch expression must be a field (e.g., {@switch Status}) or a loop variable field (e.g., {@switch post.Status})",
+					templatePath, line, arg)
+			}
+			stack = append(stack, &openSwitch{line: line})
+			fmt.Fprintf(&out, `<go-switch data-expr="%s">`, arg)
+		case "case", "default":
+			if len(stack) == 0 {
+				return "", fmt.Errorf("template syntax error in %s:%d: {@%s} outside of a {@switch} block", templatePath, line, keyword)
+			}
+			sw := stack[len(stack)-1]
+			if sw.hasDefault {
+				return "", fmt.Errorf("template syntax error in %s:%d: {@%s} after {@default}; {@default} must be the last branch of the {@switch} opened at line %d",
+					templatePath, line, keyword, sw.line)
+			}
+			if sw.caseOpen {
+				out.WriteString("</go-case>")
+			}
+			sw.caseOpen = true
+			if keyword == "default" {
+				sw.hasDefault = true
+				out.WriteString("<go-case data-default=\"true\">")
+			} else {
+				fmt.Fprintf(&out, `<go-case data-value="%s">`, strings.ReplaceAll(arg, `"`, "&quot;"))
+			}
+		case "endswitch":
+			if len(stack) == 0 {
+				return "", fmt.Errorf("template validation error in %s:%d: {@endswitch} without matching {@switch}", templatePath, line)
+			}
+			sw := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if sw.caseOpen {
+				out.WriteString("</go-case>")
+			}
+			out.WriteString("</go-switch>")
+		}
+	}
+	out.WriteString(src[last:])
+
+	if len(stack) > 0 {
+		return "", fmt.Errorf("template validation error in %s: {@switch} at line %d has no matching {@endswitch}",
+			templatePath, stack[len(stack)-1].line)
+	}
+	return out.String(), nil
+}
+
+// slotDirectiveRegex matches the {@slot Field} and {@endslot} directives.
+var slotDirectiveRegex = regexp.MustCompile(`\{\@(slot\s+[^}]+|endslot)\}`)
+
+// preprocessSlots turns slot fallback blocks into placeholder nodes.
+// Syntax: {@slot Body}<p>No content</p>{@endslot} renders the content slot Body, or the
+// markup between the directives when the parent passed no content for it.
+func preprocessSlots(src string, templatePath string) (string, error) {
+	lineOf := func(offset int) int {
+		return strings.Count(src[:offset], "\n") + 1
+	}
+
+	var open []int // Lines of the open {@slot} directives
+	var out strings.Builder
+	last := 0
+	for _, loc := range slotDirectiveRegex.FindAllStringSubmatchIndex(src, -1) {
+		out.WriteString(src[last:loc[0]])
+		last = loc[1]
+
+		directive := src[loc[2]:loc[3]]
+		line := lineOf(loc[0])
+		if directive == "endslot" {
+			if len(open) == 0 {
+				return "", fmt.Errorf("template validation error in %s:%d: {@endslot} without matching {@slot}", templatePath, line)
+			}
+			open = open[:len(open)-1]
+			out.WriteString("</go-slot>")
+			continue
+		}
+
+		field := strings.TrimSpace(strings.TrimPrefix(directive, "slot"))
+		if !regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString(field) {
+			return "", fmt.Errorf("template syntax error in %s:%d: invalid {@slot} field '%s'.\n"+
+				"  The directive names a content slot field of the component (e.g., {@slot Body}<p>No content</p>{@endslot})",
+				templatePath, line, field)
+		}
+		open = append(open, line)
+		fmt.Fprintf(&out, `<go-slot data-field="%s">`, field)
+	}
+	out.WriteString(src[last:])
+
+	if len(open) > 0 {
+		return "", fmt.Errorf("template validation error in %s: {@slot} at line %d has no matching {@endslot}",
+			templatePath, open[len(open)-1])
+	}
+	return out.String(), nil
+}
+
+// selectTagRegex matches <select> start and end tags.
+var selectTagRegex = regexp.MustCompile(`(?i)<(/?)select\b`)
+
+// preprocessSelect renames <select> elements to <go-select> placeholders. Inside a select the
+// HTML5 parser drops every element other than <option>, <optgroup> and a few others, so the
+// <go-for> of an option loop (or a <go-conditional>) would vanish and its options end up
+// outside the loop. restoreSelectElements renames the parsed elements back.
+func preprocessSelect(src string) string {
+	return selectTagRegex.ReplaceAllString(src, "<${1}go-select")
+}
+
+// restoreSelectElements turns the <go-select> placeholders of the parsed tree back into
+// <select> elements.
+func restoreSelectElements(n *html.Node) {
+	if n.Type == html.ElementNode && n.Data == "go-select" {
+		n.Data, n.DataAtom = "select", atom.Select
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		restoreSelectElements(c)
+	}
+}
+
+// templateCommentRegex matches a compiler comment, {@* ... *@}, which may span lines.
+var templateCommentRegex = regexp.MustCompile(`(?s)\{@\*.*?\*@\}`)
+
+// stripTemplateComments removes every {@* ... *@} compiler comment from src. Unlike an HTML
+// comment, a compiler comment never reaches the parser or the rendered page, and anything
+// inside it (directives, bindings, markup) is ignored. Only the comment's newlines are kept,
+// so line numbers in later errors stay accurate. Comments do not nest.
+func stripTemplateComments(src, templatePath string) (string, error) {
+	stripped := templateCommentRegex.ReplaceAllStringFunc(src, func(m string) string {
+		return strings.Repeat("\n", strings.Count(m, "\n"))
+	})
+	if i := strings.Index(stripped, "{@*"); i >= 0 {
+		return "", fmt.Errorf("template syntax error in %s:%d: comment {@* is not closed with *@}", templatePath, strings.Count(stripped[:i], "\n")+1)
+	}
+	return stripped, nil
+}
+
+// rawRegionRegex matches template regions that must reach the HTML parser verbatim:
+// HTML comments and the bodies of <style> and <script> elements. CSS rules such as
+// .grid { grid-template: ... } and example code in comments would otherwise be
+// scanned as bindings or directives.
+var rawRegionRegex = regexp.MustCompile(`(?is)<!--.*?-->|<style\b[^>]*>.*?</style\s*>|<script\b[^>]*>.*?</script\s*>`)
+
+// rawPlaceholderRegex matches placeholders produced by maskRawRegions.
+var rawPlaceholderRegex = regexp.MustCompile("\x00([0-9]+)\n*\x00")
+
+// maskRawRegions replaces every raw region with a placeholder so that the directive
+// preprocessors skip it. Placeholders keep the region's newlines, so line numbers in
+// preprocessor errors stay accurate. Use restoreRawRegions to put the regions back.
+func maskRawRegions(src string) (string, []string) {
+	var regions []string
+	masked := rawRegionRegex.ReplaceAllStringFunc(src, func(m string) string {
+		regions = append(regions, m)
+		return fmt.Sprintf("\x00%d%s\x00", len(regions)-1, strings.Repeat("\n", strings.Count(m, "\n")))
+	})
+	return masked, regions
+}
+
+// escapedBraceRegex matches the character references of { and } (&#123;, &#x7b;, &lbrace;,
+// &lcub; and their closing counterparts).
+var escapedBraceRegex = regexp.MustCompile(`&(?:#0*(123|125);|#[xX]0*(7[bBdD]);|(lbrace|lcub|rbrace|rcub);)`)
+
+// Escaped braces are carried through parsing and code generation as these references, which
+// the HTML parser does not know and leaves as written.
+const (
+	escapedOpenBrace  = "&nojs-lbrace;"
+	escapedCloseBrace = "&nojs-rbrace;"
+)
+
+// protectEscapedBraces replaces the character references of braces in src with
+// escapedOpenBrace and escapedCloseBrace. The parser would decode &#123;Name&#125; to {Name},
+// which the code generator would then read as a binding: a template author escapes a brace
+// to get the character itself. restoreEscapedBraces puts the braces into the generated code.
+func protectEscapedBraces(src string) string {
+	return escapedBraceRegex.ReplaceAllStringFunc(src, func(m string) string {
+		match := escapedBraceRegex.FindStringSubmatch(m)
+		switch strings.ToLower(match[1] + match[2] + match[3]) {
+		case "123", "7b", "lbrace", "lcub":
+			return escapedOpenBrace
+		}
+		return escapedCloseBrace
+	})
+}
+
+// restoreEscapedBraces turns the references left by protectEscapedBraces in the generated
+// code, where they only appear inside string literals, back into braces.
+func restoreEscapedBraces(code string) string {
+	return strings.NewReplacer(escapedOpenBrace, "{", escapedCloseBrace, "}").Replace(code)
+}
+
+// restoreRawRegions puts the regions removed by maskRawRegions back into src.
+func restoreRawRegions(src string, regions []string) string {
+	return rawPlaceholderRegex.ReplaceAllStringFunc(src, func(m string) string {
+		index, _ := strconv.Atoi(rawPlaceholderRegex.FindStringSubmatch(m)[1])
+		return regions[index]
+	})
+}
@@ -0,0 +1,291 @@
+package compiler
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// templateBlock is a local template block declared with {@define name(params)}...{@enddefine}.
+type templateBlock struct {
+	Name   string
+	Params []string
+	Body   string // Markup between the directives, parameters not yet substituted
+	Line   int    // Line of the {@define} directive
+}
+
+var (
+	// blockDirectiveRegex matches the {@define}, {@enddefine} and {@render} directives.
+	blockDirectiveRegex = regexp.MustCompile(`\{\@(define|enddefine|render)\b([^}]*)\}`)
+
+	// blockSignatureRegex matches the name and parenthesized list of a {@define} or {@render}.
+	blockSignatureRegex = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\(([^()]*)\)\s*$`)
+
+	// blockArgumentRegex matches a {@render} argument: a field, loop variable or dot-path.
+	blockArgumentRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+	// blockLoopVarsRegex matches the variables a {@for} declares inside a block body.
+	blockLoopVarsRegex = regexp.MustCompile(`\{\@for\s+([a-zA-Z_][a-zA-Z0-9_]*)\s*,\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*:=`)
+
+	// blockExpressionRegex matches a {...} binding or directive inside a block body.
+	blockExpressionRegex = regexp.MustCompile(`\{[^{}]*\}`)
+)
+
+// reservedBlockParams are words of the template expression syntax a parameter cannot shadow.
+var reservedBlockParams = map[string]bool{
+	"if": true, "else": true, "for": true, "range": true, "trackBy": true, "switch": true,
+	"case": true, "default": true, "classes": true, "define": true, "render": true,
+}
+
+// preprocessBlocks inlines local template blocks. Blocks are declared anywhere in the template
+// with {@define badge(status)}...{@enddefine} and invoked with {@render badge(user.Status)};
+// each call is replaced by the block body with its parameters substituted by the arguments,
+// so the bindings of the body are validated at every call site against the argument's type.
+// There is no runtime indirection: the result is the template as if the body had been
+// written out at each call.
+//
+// Definitions are replaced by their newlines and the inlined bodies are folded onto the line
+// of the call, so line numbers in later errors still point into the written template.
+func preprocessBlocks(src string, templatePath string) (string, error) {
+	lineOf := func(offset int) int {
+		return strings.Count(src[:offset], "\n") + 1
+	}
+
+	// Collect the definitions and cut them out of the template
+	blocks := make(map[string]*templateBlock)
+	var open *templateBlock
+	bodyStart, defineStart := 0, 0
+	var out strings.Builder
+	last := 0
+	for _, loc := range blockDirectiveRegex.FindAllStringSubmatchIndex(src, -1) {
+		keyword := src[loc[2]:loc[3]]
+		line := lineOf(loc[0])
+		switch keyword {
+		case "define":
+			if open != nil {
+				return "", fmt.Errorf("template syntax error in %s:%d: {@define} inside the block '%s' defined at line %d; blocks cannot be nested",
+					templatePath, line, open.Name, open.Line)
+			}
+			block, err := parseBlockDefinition(src[loc[4]:loc[5]], line, templatePath)
+			if err != nil {
+				return "", err
+			}
+			if previous, exists := blocks[block.Name]; exists {
+				return "", fmt.Errorf("template syntax error in %s:%d: block '%s' is already defined at line %d",
+					templatePath, line, block.Name, previous.Line)
+			}
+			blocks[block.Name] = block
+			open = block
+			out.WriteString(src[last:loc[0]])
+			defineStart, bodyStart = loc[0], loc[1]
+		case "enddefine":
+			if open == nil {
+				return "", fmt.Errorf("template validation error in %s:%d: {@enddefine} without matching {@define}", templatePath, line)
+			}
+			open.Body = src[bodyStart:loc[0]]
+			if err := checkBlockBody(open, templatePath); err != nil {
+				return "", err
+			}
+			out.WriteString(strings.Repeat("\n", strings.Count(src[defineStart:loc[1]], "\n")))
+			last = loc[1]
+			open = nil
+		}
+	}
+	if open != nil {
+		return "", fmt.Errorf("template validation error in %s: {@define %s} at line %d has no matching {@enddefine}",
+			templatePath, open.Name, open.Line)
+	}
+	out.WriteString(src[last:])
+
+	stripped := out.String()
+	return expandBlockCalls(stripped, blocks, nil, func(offset int) int {
+		return strings.Count(stripped[:offset], "\n") + 1
+	}, templatePath)
+}
+
+// parseBlockDefinition parses the signature of a {@define} directive.
+func parseBlockDefinition(signature string, line int, templatePath string) (*templateBlock, error) {
+	match := blockSignatureRegex.FindStringSubmatch(signature)
+	if match == nil {
+		return nil, fmt.Errorf("template syntax error in %s:%d: invalid {@define%s}.\n"+
+			"  Correct syntax: {@define name(param1, param2)}...{@enddefine}",
+			templatePath, line, signature)
+	}
+	block := &templateBlock{Name: match[1], Line: line}
+	for _, param := range splitBlockList(match[2]) {
+		switch {
+		case !blockSignatureIdent(param):
+			return nil, fmt.Errorf("template syntax error in %s:%d: invalid parameter '%s' in {@define %s}; parameters are plain names",
+				templatePath, line, param, block.Name)
+		case reservedBlockParams[param]:
+			return nil, fmt.Errorf("template syntax error in %s:%d: parameter '%s' of block '%s' is a reserved word",
+				templatePath, line, param, block.Name)
+		}
+		for _, existing := range block.Params {
+			if existing == param {
+				return nil, fmt.Errorf("template syntax error in %s:%d: parameter '%s' is declared twice in {@define %s}",
+					templatePath, line, param, block.Name)
+			}
+		}
+		block.Params = append(block.Params, param)
+	}
+	return block, nil
+}
+
+// checkBlockBody rejects loop variables that would shadow a parameter of the block.
+func checkBlockBody(block *templateBlock, templatePath string) error {
+	for _, loc := range blockLoopVarsRegex.FindAllStringSubmatchIndex(block.Body, -1) {
+		for _, group := range [][2]int{{loc[2], loc[3]}, {loc[4], loc[5]}} {
+			name := block.Body[group[0]:group[1]]
+			for _, param := range block.Params {
+				if name == param {
+					return fmt.Errorf("template syntax error in %s:%d: the {@for} loop variable '%s' shadows the parameter of block '%s'; rename one of them",
+						templatePath, block.Line+strings.Count(block.Body[:loc[0]], "\n"), name, block.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// expandBlockCalls replaces every {@render} in src with the body of its block. calls holds the
+// blocks being expanded, outermost first, to reject recursion; lineOf maps an offset in src to
+// its template line.
+func expandBlockCalls(src string, blocks map[string]*templateBlock, calls []*templateBlock, lineOf func(offset int) int, templatePath string) (string, error) {
+	var out strings.Builder
+	last := 0
+	for _, loc := range blockDirectiveRegex.FindAllStringSubmatchIndex(src, -1) {
+		if src[loc[2]:loc[3]] != "render" {
+			continue
+		}
+		line := lineOf(loc[0])
+		match := blockSignatureRegex.FindStringSubmatch(src[loc[4]:loc[5]])
+		if match == nil {
+			return "", fmt.Errorf("template syntax error in %s:%d: invalid {@render%s}.\n"+
+				"  Correct syntax: {@render name(arg1, arg2)}",
+				templatePath, line, src[loc[4]:loc[5]])
+		}
+		name, args := match[1], splitBlockList(match[2])
+
+		block, exists := blocks[name]
+		if !exists {
+			return "", fmt.Errorf("template validation error in %s:%d: {@render %s(...)} calls an undefined block.\n"+
+				"  Defined blocks: %s",
+				templatePath, line, name, describeBlocks(blocks))
+		}
+		if len(args) != len(block.Params) {
+			return "", fmt.Errorf("template validation error in %s:%d: {@render %s(...)} passes %d argument(s), but the block defined at line %d takes %d: %s(%s)",
+				templatePath, line, name, len(args), block.Line, len(block.Params), name, strings.Join(block.Params, ", "))
+		}
+		for _, call := range calls {
+			if call == block {
+				chain := make([]string, 0, len(calls)+1)
+				for _, c := range calls {
+					chain = append(chain, fmt.Sprintf("%s (line %d)", c.Name, c.Line))
+				}
+				chain = append(chain, block.Name)
+				return "", fmt.Errorf("template validation error in %s:%d: block '%s' defined at line %d renders itself (%s); blocks cannot be recursive",
+					templatePath, line, block.Name, block.Line, strings.Join(chain, " -> "))
+			}
+		}
+		for _, arg := range args {
+			if !blockArgumentRegex.MatchString(arg) {
+				return "", fmt.Errorf("template validation error in %s:%d: invalid argument '%s' in {@render %s(...)}; arguments are fields, loop variables or their fields (e.g., user.Status)",
+					templatePath, line, arg, name)
+			}
+		}
+
+		body := substituteBlockParams(block.Body, block.Params, args)
+		inlined, err := expandBlockCalls(body, blocks, append(calls, block), func(offset int) int {
+			return block.Line + strings.Count(body[:offset], "\n")
+		}, templatePath)
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(src[last:loc[0]])
+		out.WriteString(strings.ReplaceAll(inlined, "\n", " "))
+		last = loc[1]
+	}
+	out.WriteString(src[last:])
+	return out.String(), nil
+}
+
+// substituteBlockParams replaces the parameters of a block body by the call arguments. Only the
+// expressions inside {...} are rewritten, and within them only names that start a path: the
+// Status in user.Status, quoted literals and directive keywords are left alone.
+func substituteBlockParams(body string, params, args []string) string {
+	values := make(map[string]string, len(params))
+	for i, param := range params {
+		values[param] = args[i]
+	}
+	return blockExpressionRegex.ReplaceAllStringFunc(body, func(expr string) string {
+		var out strings.Builder
+		for i := 0; i < len(expr); {
+			ch := expr[i]
+			switch {
+			case ch == '\'' || ch == '"':
+				end := strings.IndexByte(expr[i+1:], ch)
+				if end < 0 {
+					out.WriteString(expr[i:])
+					return out.String()
+				}
+				out.WriteString(expr[i : i+end+2])
+				i += end + 2
+			case ch == '_' || isASCIILetter(ch):
+				start := i
+				for i < len(expr) && (expr[i] == '_' || isASCIILetter(expr[i]) || (expr[i] >= '0' && expr[i] <= '9')) {
+					i++
+				}
+				word := expr[start:i]
+				prev := byte(0)
+				if start > 0 {
+					prev = expr[start-1]
+				}
+				if value, ok := values[word]; ok && prev != '.' && prev != '@' {
+					word = value
+				}
+				out.WriteString(word)
+			default:
+				out.WriteByte(ch)
+				i++
+			}
+		}
+		return out.String()
+	})
+}
+
+// splitBlockList splits a comma-separated parameter or argument list; an empty list yields nil.
+func splitBlockList(list string) []string {
+	if strings.TrimSpace(list) == "" {
+		return nil
+	}
+	parts := strings.Split(list, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// blockSignatureIdent reports whether name is a valid block parameter name.
+func blockSignatureIdent(name string) bool {
+	return name != "" && !strings.Contains(name, ".") && blockArgumentRegex.MatchString(name)
+}
+
+// describeBlocks lists the defined blocks with their lines, for undefined-block errors.
+func describeBlocks(blocks map[string]*templateBlock) string {
+	if len(blocks) == 0 {
+		return "none"
+	}
+	described := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		described = append(described, fmt.Sprintf("%s(%s) at line %d", block.Name, strings.Join(block.Params, ", "), block.Line))
+	}
+	sort.Strings(described)
+	return strings.Join(described, ", ")
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
@@ -0,0 +1,167 @@
+package compiler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// nestingDirectiveRegex matches the directives the preprocessors turn into placeholder
+// elements, with the branches and end directives that belong to them. Like the preprocessors,
+// it requires an argument after if, for, switch, case and slot, so a literal "{@if}" in text is skipped.
+var nestingDirectiveRegex = regexp.MustCompile(`\{\@(?:(else if|if|for|switch|case|slot)\s[^}]*|(else|endif|endfor|default|endswitch|endslot))\}`)
+
+// nestingDirectiveOpener maps each branch and end directive to the directive that opens its block.
+var nestingDirectiveOpener = map[string]string{
+	"else if": "if", "else": "if", "endif": "if",
+	"endfor": "for",
+	"case":   "switch", "default": "switch", "endswitch": "switch",
+	"endslot": "slot",
+}
+
+// nestingBlock is an open {@if}, {@for}, {@switch} or {@slot} during checkDirectiveNesting.
+type nestingBlock struct {
+	Keyword  string
+	Line     int
+	Elements []int // Open elements (indexes into the scanned elements) at the directive
+}
+
+// nestingElement is an element scanned by checkDirectiveNesting.
+type nestingElement struct {
+	Tag       string
+	Line      int
+	ClosedAt  int // Line where the element was closed, 0 while open
+	ClosedTag string
+}
+
+// checkDirectiveNesting rejects blocks that are not opened and closed inside the same
+// element, such as {@if Cond}</div><div>{@endif}. The preprocessors replace each block by a
+// placeholder element ({@if} by <go-conditional>, {@for} by <go-for>); a block that crosses an
+// element boundary yields a placeholder the HTML parser closes early or moves, and the
+// generated code then wraps the wrong markup without any error. Branches ({@else}, {@case})
+// must be in the same element as the directive that opens their block. src is the template
+// after the local blocks are inlined, so directive lines are template lines.
+func checkDirectiveNesting(src string, templatePath string) error {
+	var (
+		elements []nestingElement
+		stack    []int // Indexes of open elements
+		blocks   []nestingBlock
+		line     = 1
+	)
+	closeTop := func(atLine int, by string) {
+		el := &elements[stack[len(stack)-1]]
+		el.ClosedAt, el.ClosedTag = atLine, by
+		stack = stack[:len(stack)-1]
+	}
+
+	// check reports whether the open elements at a branch or end directive are the ones open
+	// at the start of its block. Elements with an optional end tag opened inside the block
+	// (an unclosed <li> of a {@for}) are closed by the directive, as the parser would.
+	check := func(block nestingBlock, keyword string, atLine int) error {
+		for len(stack) > len(block.Elements) && optionalEndTags[elements[stack[len(stack)-1]].Tag] {
+			closeTop(atLine, "{@"+keyword+"}")
+		}
+		common := 0
+		for common < len(stack) && common < len(block.Elements) && stack[common] == block.Elements[common] {
+			common++
+		}
+		if common == len(stack) && common == len(block.Elements) {
+			return nil
+		}
+
+		var detail string
+		if common < len(block.Elements) {
+			el := elements[block.Elements[common]]
+			detail = fmt.Sprintf("the <%s> opened on line %d encloses the {@%s}, but %s on line %d closes it before the {@%s}",
+				el.Tag, el.Line, block.Keyword, el.ClosedTag, el.ClosedAt, keyword)
+		} else {
+			el := elements[stack[common]]
+			detail = fmt.Sprintf("the <%s> opened on line %d, after the {@%s}, is still open at the {@%s}",
+				el.Tag, el.Line, block.Keyword, keyword)
+		}
+		return fmt.Errorf("template validation error in %s:%d: {@%s} on line %d and its {@%s} on line %d are not inside the same element: %s.\n"+
+			"  Directives must wrap complete elements: open and close a block, and place its branches, inside the same parent element, e.g. {@if Cond}<div>...</div>{@endif}.\n"+
+			"  Otherwise the HTML parser moves the placeholder elements the directives compile to, and the block wraps the wrong markup",
+			templatePath, block.Line, block.Keyword, block.Line, keyword, atLine, detail)
+	}
+
+	z := html.NewTokenizer(strings.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return nil // At the end, unclosed blocks are left to the preprocessors to report
+		}
+		// TagName lowercases the token in place, so keep the raw text first
+		raw := string(z.Raw())
+		tokenLine := line
+		line += strings.Count(raw, "\n")
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if isDocumentTag(tag) {
+				continue
+			}
+			for implied := impliedEndTags[tag]; len(stack) > 0; {
+				top := elements[stack[len(stack)-1]].Tag
+				found := false
+				for _, t := range implied {
+					found = found || t == top
+				}
+				if !found {
+					break
+				}
+				closeTop(tokenLine, "<"+tag+">")
+			}
+			elements = append(elements, nestingElement{Tag: tag, Line: tokenLine})
+			if tt == html.StartTagToken && !voidElements[tag] {
+				stack = append(stack, len(elements)-1)
+			}
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if isDocumentTag(tag) || voidElements[tag] {
+				continue
+			}
+			for i := len(stack) - 1; i >= 0; i-- {
+				if elements[stack[i]].Tag == tag {
+					for len(stack) > i {
+						closeTop(tokenLine, "</"+tag+">")
+					}
+					break
+				}
+			}
+
+		case html.TextToken:
+			for _, loc := range nestingDirectiveRegex.FindAllStringSubmatchIndex(raw, -1) {
+				group := 2 // The keyword is in the first group when the directive takes an argument
+				if loc[2] < 0 {
+					group = 4
+				}
+				keyword := raw[loc[group]:loc[group+1]]
+				atLine := tokenLine + strings.Count(raw[:loc[0]], "\n")
+				opener, closes := nestingDirectiveOpener[keyword]
+				if !closes {
+					blocks = append(blocks, nestingBlock{Keyword: keyword, Line: atLine, Elements: append([]int(nil), stack...)})
+					continue
+				}
+				if len(blocks) == 0 {
+					continue
+				}
+				if blocks[len(blocks)-1].Keyword != opener {
+					return nil // Mismatched directives are reported by the preprocessors
+				}
+				if err := check(blocks[len(blocks)-1], keyword, atLine); err != nil {
+					return err
+				}
+				if strings.HasPrefix(keyword, "end") {
+					blocks = blocks[:len(blocks)-1]
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package compiler
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// routesDefinitionFile is the conventional name of a typed routes definition file.
+// The file must also carry the routesMarker comment so an unrelated file that happens
+// to share the name is never picked up by accident.
+const routesDefinitionFile = "routes.nojs.go"
+
+// routesMarker marks a Go file as a typed routes definition.
+const routesMarker = "//nojs:routes"
+
+// routeDefinition describes one typed route declared in a routes definition file.
+// It is declared as an exported string constant holding the route pattern:
+//
+//	const BlogPost = "/blog/{year:int}/{slug}"
+type routeDefinition struct {
+	Name    string       // Constant name (e.g., "BlogPost")
+	Pattern string       // Route pattern as written (e.g., "/blog/{year:int}/{slug}")
+	Doc     string       // Doc comment of the constant, if any
+	Params  []routeParam // Params in path order
+	Chain   []string     // Components the route renders, from a //nojs:chain line (used for preloads)
+}
+
+// routeParam describes a single typed path parameter.
+type routeParam struct {
+	Name      string // Param name in the pattern (e.g., "year")
+	FieldName string // Exported field name in the Params struct (e.g., "Year")
+	Kind      string // One of: string, int, int64, uuid
+	Segment   int    // Index of the path segment holding the param
+}
+
+// supportedRouteParamKinds maps a param kind to the Go type of its struct field.
+var supportedRouteParamKinds = map[string]string{
+	"string": "string",
+	"int":    "int",
+	"int64":  "int64",
+	"uuid":   "string",
+}
+
+// compileRouteDefinitions finds every routes definition file under srcDir and writes
+// a routes.generated.go file next to each one.
+func compileRouteDefinitions(srcDir string) error {
+	defFiles, err := findRouteDefinitionFiles(srcDir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range defFiles {
+		pkgName, routes, isDefinition, err := parseRouteDefinitions(path)
+		if err != nil {
+			return err
+		}
+		if !isDefinition {
+			fmt.Printf("Warning: %s has no %s marker comment; skipping typed route generation.\n", path, routesMarker)
+			continue
+		}
+
+		source, err := generateRoutesCode(pkgName, routes)
+		if err != nil {
+			return fmt.Errorf("failed to generate typed routes for %s: %w", path, err)
+		}
+
+		outPath := filepath.Join(filepath.Dir(path), "routes.generated.go")
+		if err := os.WriteFile(outPath, source, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Generated %d typed route(s) from %s.\n", len(routes), path)
+	}
+	return nil
+}
+
+// findRouteDefinitionFiles returns the paths of the routes definition files under srcDir.
+func findRouteDefinitionFiles(srcDir string) ([]string, error) {
+	var defFiles []string
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == routesDefinitionFile {
+			defFiles = append(defFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan for route definitions: %w", err)
+	}
+	return defFiles, nil
+}
+
+// parseRouteDefinitions reads a routes definition file and returns its package name and
+// declared routes. isDefinition is false if the file lacks the marker comment.
+func parseRouteDefinitions(path string) (pkgName string, routes []routeDefinition, isDefinition bool, err error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return "", nil, false, fmt.Errorf("failed to parse route definitions %s: %w", path, err)
+	}
+
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if strings.TrimSpace(c.Text) == routesMarker {
+				isDefinition = true
+			}
+		}
+	}
+	if !isDefinition {
+		return file.Name.Name, nil, false, nil
+	}
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec := spec.(*ast.ValueSpec)
+			for i, name := range valueSpec.Names {
+				if !name.IsExported() || i >= len(valueSpec.Values) {
+					continue
+				}
+				lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				pattern, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return "", nil, true, fmt.Errorf("%s: route %s: %w", fset.Position(lit.Pos()), name.Name, err)
+				}
+
+				params, err := parseRoutePattern(pattern)
+				if err != nil {
+					return "", nil, true, fmt.Errorf("%s: route %s: %w", fset.Position(lit.Pos()), name.Name, err)
+				}
+
+				doc := valueSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				routes = append(routes, routeDefinition{
+					Name:    name.Name,
+					Pattern: pattern,
+					Doc:     strings.TrimSpace(doc.Text()),
+					Params:  params,
+					Chain:   parseRouteChain(doc),
+				})
+			}
+		}
+	}
+
+	if len(routes) == 0 {
+		return "", nil, true, fmt.Errorf("route definitions %s declare no routes (expected exported string constants)", path)
+	}
+	return file.Name.Name, routes, true, nil
+}
+
+// parseRouteChain returns the component names listed on the //nojs:chain line of a route's
+// doc comment, if any. Directive lines are not part of doc.Text(), so the chain never
+// leaks into generated documentation.
+func parseRouteChain(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	for _, c := range doc.List {
+		if rest, ok := strings.CutPrefix(c.Text, chainMarker); ok {
+			return strings.Fields(rest)
+		}
+	}
+	return nil
+}
+
+// routeParamNameRegex matches the param names routeParamFieldName turns into Go field names.
+var routeParamNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+// parseRoutePattern extracts the typed params of a pattern like "/blog/{year:int}/{slug}".
+// Params without an explicit type are strings.
+func parseRoutePattern(pattern string) ([]routeParam, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("pattern %q must start with '/'", pattern)
+	}
+
+	var params []routeParam
+	seen := make(map[string]bool)
+	for i, seg := range splitPattern(pattern) {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			if strings.ContainsAny(seg, "{}") {
+				return nil, fmt.Errorf("pattern %q: malformed segment %q (params must span a whole segment)", pattern, seg)
+			}
+			continue
+		}
+
+		name, kind, hasKind := strings.Cut(strings.Trim(seg, "{}"), ":")
+		if !hasKind {
+			kind = "string"
+		}
+		if _, ok := supportedRouteParamKinds[kind]; !ok {
+			return nil, fmt.Errorf("pattern %q: param %q has unsupported type %q (supported: string, int, int64, uuid)", pattern, name, kind)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("pattern %q: empty param name", pattern)
+		}
+		if !routeParamNameRegex.MatchString(name) {
+			// The name becomes a Go field name in the generated code
+			return nil, fmt.Errorf("pattern %q: param %q must start with a letter and contain only letters, digits, '_' and '-'", pattern, name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("pattern %q: duplicate param %q", pattern, name)
+		}
+		seen[name] = true
+
+		params = append(params, routeParam{
+			Name:      name,
+			FieldName: routeParamFieldName(name),
+			Kind:      kind,
+			Segment:   i,
+		})
+	}
+	return params, nil
+}
+
+// splitPattern splits a route pattern into its path segments.
+func splitPattern(pattern string) []string {
+	trimmed := strings.Trim(pattern, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// routeParamFieldName converts a param name to an exported Go field name
+// ("year" -> "Year", "user_id" -> "UserID").
+func routeParamFieldName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	var b strings.Builder
+	for _, part := range parts {
+		switch strings.ToLower(part) {
+		case "id", "uuid", "url":
+			b.WriteString(strings.ToUpper(part))
+		default:
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// generateRoutesCode generates the typed Params struct and helpers for each route.
+func generateRoutesCode(pkgName string, routes []routeDefinition) ([]byte, error) {
+	var code strings.Builder
+
+	fmt.Fprintf(&code, `// Code generated by the nojs AOT compiler. DO NOT EDIT.
+package %s
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+var _ = strconv.Atoi     // Suppress unused import error if no route has numeric params
+var _ = url.PathUnescape // Suppress unused import error if no route has params
+`, pkgName)
+
+	for _, route := range routes {
+		writeRouteCode(&code, route)
+	}
+
+	code.WriteString(`
+// nojsSplitPath splits a route path into its segments, ignoring leading and trailing slashes.
+func nojsSplitPath(path string) []string {
+	trimmed := strings.Trim(path, "/")
+	if trimmed == "" {
+		return nil
+	}
+	return strings.Split(trimmed, "/")
+}
+
+// nojsIsUUID reports whether s is a canonical 8-4-4-4-12 hex UUID.
+func nojsIsUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+`)
+
+	formatted, err := format.Source([]byte(code.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// writeRouteCode writes the Params struct, parse/build helpers and factory glue for one route.
+func writeRouteCode(code *strings.Builder, route routeDefinition) {
+	paramsType := route.Name + "Params"
+	segments := splitPattern(route.Pattern)
+
+	// Params struct
+	fmt.Fprintf(code, "\n// %s holds the typed path params of the %s route (%q).\n", paramsType, route.Name, route.Pattern)
+	if route.Doc != "" {
+		code.WriteString("//\n")
+		for _, line := range strings.Split(route.Doc, "\n") {
+			fmt.Fprintf(code, "// %s\n", line)
+		}
+	}
+	fmt.Fprintf(code, "type %s struct {\n", paramsType)
+	for _, p := range route.Params {
+		fmt.Fprintf(code, "\t%s %s\n", p.FieldName, supportedRouteParamKinds[p.Kind])
+	}
+	code.WriteString("}\n")
+
+	// ParamsFromMap: the conversion used by the router glue
+	fmt.Fprintf(code, "\n// %sFromMap converts the router's string params into %s.\n", paramsType, paramsType)
+	fmt.Fprintf(code, "func %sFromMap(params map[string]string) (%s, error) {\n", paramsType, paramsType)
+	fmt.Fprintf(code, "\tvar p %s\n", paramsType)
+	for _, p := range route.Params {
+		fmt.Fprintf(code, "\traw%s, ok := params[%q]\n", p.FieldName, p.Name)
+		fmt.Fprintf(code, "\tif !ok {\n\t\treturn p, fmt.Errorf(\"route %s: missing param %%q\", %q)\n\t}\n", route.Name, p.Name)
+		writeParamConversion(code, route.Name, p, "raw"+p.FieldName)
+	}
+	code.WriteString("\treturn p, nil\n}\n")
+
+	// ParsePath
+	fmt.Fprintf(code, "\n// Parse%sPath extracts %s from a concrete path such as %q.\n", route.Name, paramsType, exampleRoutePath(route))
+	fmt.Fprintf(code, "func Parse%sPath(path string) (%s, error) {\n", route.Name, paramsType)
+	fmt.Fprintf(code, "\tsegs := nojsSplitPath(path)\n")
+	fmt.Fprintf(code, "\tif len(segs) != %d {\n\t\treturn %s{}, fmt.Errorf(\"route %s: path %%q does not match %%q\", path, %s)\n\t}\n",
+		len(segments), paramsType, route.Name, route.Name)
+	fmt.Fprintf(code, "\tparams := make(map[string]string, %d)\n", len(route.Params))
+	paramAt := make(map[int]routeParam)
+	for _, p := range route.Params {
+		paramAt[p.Segment] = p
+	}
+	for i, seg := range segments {
+		if p, ok := paramAt[i]; ok {
+			fmt.Fprintf(code, "\tseg%d, err := url.PathUnescape(segs[%d])\n", i, i)
+			fmt.Fprintf(code, "\tif err != nil {\n\t\treturn %s{}, fmt.Errorf(\"route %s: param %%q: %%w\", %q, err)\n\t}\n", paramsType, route.Name, p.Name)
+			fmt.Fprintf(code, "\tparams[%q] = seg%d\n", p.Name, i)
+		} else {
+			fmt.Fprintf(code, "\tif segs[%d] != %q {\n\t\treturn %s{}, fmt.Errorf(\"route %s: path %%q does not match %%q\", path, %s)\n\t}\n",
+				i, seg, paramsType, route.Name, route.Name)
+		}
+	}
+	fmt.Fprintf(code, "\treturn %sFromMap(params)\n}\n", paramsType)
+
+	// BuildPath
+	fmt.Fprintf(code, "\n// Build%sPath builds the concrete path for p; it is the inverse of Parse%sPath.\n", route.Name, route.Name)
+	fmt.Fprintf(code, "func Build%sPath(p %s) string {\n", route.Name, paramsType)
+	if len(route.Params) == 0 {
+		code.WriteString("\t_ = p\n")
+	}
+	var parts []string
+	for i, seg := range segments {
+		if p, ok := paramAt[i]; ok {
+			switch p.Kind {
+			case "int":
+				parts = append(parts, fmt.Sprintf("strconv.Itoa(p.%s)", p.FieldName))
+			case "int64":
+				parts = append(parts, fmt.Sprintf("strconv.FormatInt(p.%s, 10)", p.FieldName))
+			default:
+				parts = append(parts, fmt.Sprintf("url.PathEscape(p.%s)", p.FieldName))
+			}
+		} else {
+			parts = append(parts, strconv.Quote(seg))
+		}
+	}
+	if len(parts) == 0 {
+		code.WriteString("\treturn \"/\"\n}\n")
+	} else {
+		fmt.Fprintf(code, "\treturn \"/\" + strings.Join([]string{%s}, \"/\")\n}\n", strings.Join(parts, ", "))
+	}
+
+	// Validate + Factory glue for the router
+	fmt.Fprintf(code, "\n// Validate%sParams reports whether the router's string params convert to %s.\n", route.Name, paramsType)
+	code.WriteString("// Assign it to router.Route.Validate so malformed params fail navigation as route-not-found.\n")
+	fmt.Fprintf(code, "func Validate%sParams(params map[string]string) error {\n\t_, err := %sFromMap(params)\n\treturn err\n}\n", route.Name, paramsType)
+
+	fmt.Fprintf(code, "\n// %sFactory adapts a typed factory to the router's ComponentFactory signature.\n", route.Name)
+	fmt.Fprintf(code, "// It returns nil when the params do not convert; pair it with Validate%sParams so that never happens.\n", route.Name)
+	fmt.Fprintf(code, "func %sFactory(factory func(p %s) runtime.Component) func(params map[string]string) runtime.Component {\n", route.Name, paramsType)
+	fmt.Fprintf(code, "\treturn func(params map[string]string) runtime.Component {\n\t\tp, err := %sFromMap(params)\n\t\tif err != nil {\n\t\t\treturn nil\n\t\t}\n\t\treturn factory(p)\n\t}\n}\n", paramsType)
+}
+
+// writeParamConversion writes the code converting a raw string param into its typed field.
+func writeParamConversion(code *strings.Builder, routeName string, p routeParam, rawVar string) {
+	switch p.Kind {
+	case "int":
+		fmt.Fprintf(code, "\tif v, err := strconv.Atoi(%s); err == nil {\n\t\tp.%s = v\n\t} else {\n", rawVar, p.FieldName)
+		fmt.Fprintf(code, "\t\treturn p, fmt.Errorf(\"route %s: param %%q must be an int, got %%q\", %q, %s)\n\t}\n", routeName, p.Name, rawVar)
+	case "int64":
+		fmt.Fprintf(code, "\tif v, err := strconv.ParseInt(%s, 10, 64); err == nil {\n\t\tp.%s = v\n\t} else {\n", rawVar, p.FieldName)
+		fmt.Fprintf(code, "\t\treturn p, fmt.Errorf(\"route %s: param %%q must be an int64, got %%q\", %q, %s)\n\t}\n", routeName, p.Name, rawVar)
+	case "uuid":
+		fmt.Fprintf(code, "\tif !nojsIsUUID(%s) {\n", rawVar)
+		fmt.Fprintf(code, "\t\treturn p, fmt.Errorf(\"route %s: param %%q must be a UUID, got %%q\", %q, %s)\n\t}\n", routeName, p.Name, rawVar)
+		fmt.Fprintf(code, "\tp.%s = %s\n", p.FieldName, rawVar)
+	default:
+		fmt.Fprintf(code, "\tp.%s = %s\n", p.FieldName, rawVar)
+	}
+}
+
+// exampleRoutePath renders a pattern with placeholder values, for doc comments.
+func exampleRoutePath(route routeDefinition) string {
+	segments := splitPattern(route.Pattern)
+	for _, p := range route.Params {
+		switch p.Kind {
+		case "int", "int64":
+			segments[p.Segment] = "42"
+		case "uuid":
+			segments[p.Segment] = "123e4567-e89b-12d3-a456-426614174000"
+		default:
+			segments[p.Segment] = p.Name
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,237 @@
+package compiler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// ServiceWorkerConfig configures the generated offline-first service worker.
+// The worker precaches the app when it installs and serves it cache-first, so the app
+// starts without a network. Other same-origin GET requests go to the network first and
+// fall back to the response cached the last time they succeeded; that cache is kept across
+// builds.
+//
+// Caches are named after BuildHash: a new build installs next to the running one and
+// waits until the page asks it to take over (see the nojs/sw package), after which the
+// caches of older builds are deleted.
+type ServiceWorkerConfig struct {
+	WasmURL     string   // URL of the compiled module (e.g., "main.wasm")
+	WasmExecURL string   // URL of Go's wasm_exec.js support script
+	Shells      []string // HTML shells to precache; navigations fall back to the first one offline
+	Assets      []string // Further URLs to precache (e.g., the fingerprinted assets of the preload manifest)
+	BuildHash   string   // Version of the build the caches are named after (see BuildHash)
+	CachePrefix string   // Prefix of the cache names; caches of other prefixes are left alone
+}
+
+// DefaultServiceWorkerConfig returns the service worker configuration matching the default
+// app layout. BuildHash must still be set.
+func DefaultServiceWorkerConfig() ServiceWorkerConfig {
+	return ServiceWorkerConfig{
+		WasmURL:     "main.wasm",
+		WasmExecURL: "wasm_exec.js",
+		Shells:      []string{"index.html"},
+		CachePrefix: "nojs",
+	}
+}
+
+// GenerateServiceWorker renders the service worker script for cfg.
+func GenerateServiceWorker(cfg ServiceWorkerConfig) ([]byte, error) {
+	if strings.TrimSpace(cfg.WasmURL) == "" {
+		return nil, fmt.Errorf("service worker: WasmURL must not be empty")
+	}
+	if strings.TrimSpace(cfg.WasmExecURL) == "" {
+		return nil, fmt.Errorf("service worker: WasmExecURL must not be empty")
+	}
+	if len(cfg.Shells) == 0 {
+		return nil, fmt.Errorf("service worker: Shells must not be empty")
+	}
+	if strings.TrimSpace(cfg.BuildHash) == "" {
+		return nil, fmt.Errorf("service worker: BuildHash must not be empty")
+	}
+	if strings.TrimSpace(cfg.CachePrefix) == "" {
+		return nil, fmt.Errorf("service worker: CachePrefix must not be empty")
+	}
+
+	// Precached in this order, each URL once
+	seen := make(map[string]bool)
+	var precache []string
+	for _, url := range append(append([]string{cfg.WasmURL, cfg.WasmExecURL}, cfg.Shells...), cfg.Assets...) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			precache = append(precache, url)
+		}
+	}
+
+	data := struct {
+		ServiceWorkerConfig
+		CacheName string
+		Precache  []string
+	}{cfg, cfg.CachePrefix + "-" + cfg.BuildHash, precache}
+
+	var buf bytes.Buffer
+	if err := serviceWorkerTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("service worker: failed to render template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteServiceWorker generates the service worker script for cfg and writes it to path.
+func WriteServiceWorker(path string, cfg ServiceWorkerConfig) error {
+	source, err := GenerateServiceWorker(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, source, 0644)
+}
+
+// BuildHash returns a short hash of the contents of files (e.g., the compiled module), to
+// use as ServiceWorkerConfig.BuildHash: it changes exactly when one of the files does.
+func BuildHash(files ...string) (string, error) {
+	if len(files) == 0 {
+		return "", fmt.Errorf("service worker: no files to hash")
+	}
+	hash := sha256.New()
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("service worker: failed to hash build: %w", err)
+		}
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
+
+// ManifestAssets returns the same-origin asset URLs listed in the preload manifest at
+// path (see PreloadConfig.ManifestPath), sorted and without duplicates. Absolute URLs are
+// left out: a service worker can only precache them with CORS.
+func ManifestAssets(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("service worker: failed to read preload manifest: %w", err)
+	}
+	var routes []RoutePreloads
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("service worker: invalid preload manifest %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	var assets []string
+	for _, route := range routes {
+		for _, asset := range route.Assets {
+			if strings.Contains(asset.Href, "://") || strings.HasPrefix(asset.Href, "//") || seen[asset.Href] {
+				continue
+			}
+			seen[asset.Href] = true
+			assets = append(assets, asset.Href)
+		}
+	}
+	sort.Strings(assets)
+	return assets, nil
+}
+
+var serviceWorkerTemplate = template.Must(template.New("sw").Funcs(template.FuncMap{"js": jsString}).Parse(`// Code generated by the nojs AOT compiler. DO NOT EDIT.
+// nojs service worker: precaches the app and serves it cache-first, so it starts offline.
+// A new build waits until the page sends SKIP_WAITING (sw.SkipWaiting in Go).
+"use strict";
+
+var CACHE_PREFIX = {{js .CachePrefix}} + "-";
+var CACHE_NAME = {{js .CacheName}};
+var DATA_CACHE_NAME = CACHE_PREFIX + "data"; // Kept across builds
+var SHELL_URL = {{js (index .Shells 0)}};
+var PRECACHE_URLS = [
+{{- range $i, $url := .Precache}}{{if $i}},{{end}}
+    {{js $url}}
+{{- end}}
+];
+
+var precached = {};
+PRECACHE_URLS.forEach(function (url) {
+    precached[new URL(url, self.location).href] = true;
+});
+
+self.addEventListener("install", function (event) {
+    event.waitUntil(caches.open(CACHE_NAME).then(function (cache) {
+        // Revalidate with the server: a build must not precache another build's files
+        return cache.addAll(PRECACHE_URLS.map(function (url) {
+            return new Request(url, { cache: "reload" });
+        }));
+    }));
+});
+
+self.addEventListener("activate", function (event) {
+    event.waitUntil(caches.keys().then(function (names) {
+        return Promise.all(names.filter(function (name) {
+            return name.indexOf(CACHE_PREFIX) === 0 && name !== CACHE_NAME && name !== DATA_CACHE_NAME;
+        }).map(function (name) {
+            return caches.delete(name);
+        }));
+    }).then(function () {
+        return self.clients.claim();
+    }));
+});
+
+self.addEventListener("message", function (event) {
+    if (event.data && event.data.type === "SKIP_WAITING") {
+        self.skipWaiting();
+    }
+});
+
+// Precached files: cache first, then the network.
+function fromPrecache(request) {
+    return caches.match(request, { cacheName: CACHE_NAME }).then(function (cached) {
+        return cached || fetch(request);
+    });
+}
+
+// Navigations: the network, then the cached page, then the shell.
+function navigate(request) {
+    return fetch(request).catch(function () {
+        return caches.match(request, { cacheName: CACHE_NAME }).then(function (cached) {
+            return cached || caches.match(SHELL_URL, { cacheName: CACHE_NAME });
+        });
+    });
+}
+
+// Other requests: the network, keeping a copy of each success for offline use. Without a
+// network or a copy the request fails, and the app serves its offline fallback.
+function fromNetwork(request) {
+    return fetch(request).then(function (response) {
+        if (response.ok) {
+            var copy = response.clone();
+            caches.open(DATA_CACHE_NAME).then(function (cache) {
+                cache.put(request, copy);
+            });
+        }
+        return response;
+    }, function (err) {
+        return caches.match(request, { cacheName: DATA_CACHE_NAME }).then(function (cached) {
+            if (cached) {
+                return cached;
+            }
+            throw err;
+        });
+    });
+}
+
+self.addEventListener("fetch", function (event) {
+    var request = event.request;
+    var url = new URL(request.url);
+    if (request.method !== "GET" || url.origin !== self.location.origin) {
+        return;
+    }
+    if (precached[url.href]) {
+        event.respondWith(fromPrecache(request));
+    } else if (request.mode === "navigate") {
+        event.respondWith(navigate(request));
+    } else {
+        event.respondWith(fromNetwork(request));
+    }
+});
+`))
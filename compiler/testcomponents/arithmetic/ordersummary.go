@@ -0,0 +1,25 @@
+package arithmetic
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// OrderLine is one line of an order, tracked by SKU.
+type OrderLine struct {
+	SKU   string
+	Name  string
+	Qty   int
+	Price float64
+}
+
+// OrderSummary computes numbering, line totals and paging in its bindings with the
+// restricted arithmetic grammar, and ranges over float and bool values.
+type OrderSummary struct {
+	runtime.ComponentBase
+	Lines    []OrderLine
+	Total    float64
+	TaxRate  float64
+	Page     int
+	PageSize int
+	Count    int
+	Weights  []float64
+	Checks   []bool
+}
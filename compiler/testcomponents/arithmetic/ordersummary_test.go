@@ -0,0 +1,122 @@
+//go:build !wasm
+// +build !wasm
+
+package arithmetic
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// newOrderSummary returns a two-line order on the second of three pages.
+func newOrderSummary() *OrderSummary {
+	return &OrderSummary{
+		Lines: []OrderLine{
+			{SKU: "A-1", Name: "Pen", Qty: 3, Price: 1.5},
+			{SKU: "B-2", Name: "Pad", Qty: 2, Price: 4.25},
+		},
+		Total:    13,
+		TaxRate:  0.21,
+		Page:     1,
+		PageSize: 10,
+		Count:    25,
+		Weights:  []float64{1.5},
+		Checks:   []bool{true, false},
+	}
+}
+
+// TestOrderSummary_EmitsGoArithmetic verifies arithmetic bindings compile to the equivalent Go
+// expression, with an int operand converted where it meets a float.
+func TestOrderSummary_EmitsGoArithmetic(t *testing.T) {
+	// Arrange
+	source, err := os.ReadFile("OrderSummary.generated.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated component: %v", err)
+	}
+
+	// Act
+	generated := string(source)
+
+	// Assert
+	for _, want := range []string{
+		`fmt.Sprintf("%v. %v: %v x %g = %.2f", i+1, line.Name, line.Qty, line.Price, float64(line.Qty)*line.Price)`,
+		`fmt.Sprintf("With tax: %.2f", c.Total*(1+c.TaxRate))`,
+		`fmt.Sprintf("Page %v of %v", c.Page+1, (c.Count+c.PageSize-1)/c.PageSize)`,
+		`fmt.Sprintf("%g kg = %g g", weight, weight*1000)`,
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("Expected generated code to contain %s", want)
+		}
+	}
+}
+
+// TestOrderSummary_NumbersLinesAndComputesTotals verifies 1-based numbering and per-line
+// totals inside the loop.
+func TestOrderSummary_NumbersLinesAndComputesTotals(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(newOrderSummary())
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	items := root.Children[0].Children
+	if len(items) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(items))
+	}
+	for i, want := range []string{"1. Pen: 3 x 1.5 = 4.50", "2. Pad: 2 x 4.25 = 8.50"} {
+		if got := rendertest.TextOf(items[i]); got != want {
+			t.Errorf("line %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+// TestOrderSummary_ComponentScopeExpressions verifies arithmetic on component fields, with
+// parentheses, integer division and unary minus.
+func TestOrderSummary_ComponentScopeExpressions(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(newOrderSummary())
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	for i, want := range map[int]string{
+		1: "Total: 13.00",
+		2: "With tax: 15.73",
+		3: "Page 2 of 3",
+		4: "Offset: -10",
+	} {
+		if got := rendertest.TextOf(root.Children[i]); got != want {
+			t.Errorf("child %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+// TestOrderSummary_FloatAndBoolLoopValues verifies loops over []float64 and []bool render
+// their values with %g and %t, and follow state changes.
+func TestOrderSummary_FloatAndBoolLoopValues(t *testing.T) {
+	// Arrange
+	summary := newOrderSummary()
+	renderer := rendertest.NewTestRenderer(summary)
+	renderer.RenderRoot()
+
+	// Act
+	summary.Checks[1] = true
+	summary.StateHasChanged()
+	root := renderer.WaitForRender(t)
+
+	// Assert
+	if got := rendertest.TextOf(root.Children[5].Children[0]); got != "1.5 kg = 1500 g" {
+		t.Errorf("expected the float loop value and its product, got %q", got)
+	}
+	checks := root.Children[6].Children
+	for i, want := range []string{"Step 1: true", "Step 2: true"} {
+		if got := rendertest.TextOf(checks[i]); got != want {
+			t.Errorf("step %d: expected %q, got %q", i, want, got)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+//go:build !wasm
+// +build !wasm
+
+package blocks
+
+import (
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs-compiler/compilertest"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// newTeamBoard renders a board whose team is active, with one active and one away member.
+func newTeamBoard() *vdom.VNode {
+	board := &TeamBoard{
+		Status: "active",
+		Owner: Member{
+			Name:    "Ada",
+			Tags:    []string{"lead", "ops"},
+			Profile: Profile{Home: Address{Street: "1 Main St", City: "Springfield"}},
+		},
+		Members: []Member{
+			{ID: 1, Name: "Grace", Status: "active"},
+			{ID: 2, Name: "Linus", Status: "away"},
+		},
+	}
+	return rendertest.NewTestRenderer(board).RenderRoot()
+}
+
+// badgeOf returns the class and text of a badge rendered by the badge block.
+func badgeOf(t *testing.T, n *vdom.VNode) (string, string) {
+	t.Helper()
+	if n == nil || n.Tag != "span" || len(n.Children) != 1 {
+		t.Fatalf("expected a badge <span>, got:\n%s", rendertest.FormatVNode(n))
+	}
+	class, _ := n.Attributes["class"].(string)
+	return class, n.Children[0].Content
+}
+
+// TestTeamBoard_BlockOutsideLoop verifies blocks called at the top level are inlined with
+// their arguments, including a nested field argument and a block holding a loop and a
+// component.
+func TestTeamBoard_BlockOutsideLoop(t *testing.T) {
+	// Act
+	header := newTeamBoard().Children[0]
+
+	// Assert
+	if class, text := badgeOf(t, header.Children[1]); class != "badge ok" || text != "active" {
+		t.Errorf("expected the team badge 'badge ok'/'active', got %q/%q", class, text)
+	}
+	if got := header.Children[2].Content; got != "1 Main St, Springfield" {
+		t.Errorf("expected the address from Owner.Profile.Home, got %q", got)
+	}
+	if got := header.Children[3].Children[0].Content; got != "active" {
+		t.Errorf("expected the chip component to get the label argument, got %q", got)
+	}
+	if tags := header.Children[4].Children; len(tags) != 2 || tags[0].Content != "lead" || tags[1].Content != "ops" {
+		t.Errorf("expected the tag loop over Owner.Tags, got:\n%s", rendertest.FormatVNode(header.Children[4]))
+	}
+}
+
+// TestTeamBoard_BlockInsideLoop verifies a block called inside a loop binds its parameter to
+// the loop variable of each iteration.
+func TestTeamBoard_BlockInsideLoop(t *testing.T) {
+	// Act
+	members := newTeamBoard().Children[1].Children
+
+	// Assert
+	if len(members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(members))
+	}
+	want := [][2]string{{"badge ok", "active"}, {"badge off", "away"}}
+	for i, member := range members {
+		class, text := badgeOf(t, member.Children[1])
+		if class != want[i][0] || text != want[i][1] {
+			t.Errorf("member %d: expected badge %q/%q, got %q/%q", i, want[i][0], want[i][1], class, text)
+		}
+	}
+}
+
+// TestBlocks_UndefinedBlock verifies a call to an undefined block reports the call line and
+// the blocks that are defined.
+func TestBlocks_UndefinedBlock(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("undefined", compiler.Options{})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err,
+		"StatusList.gt.html:5: {@render bagde(...)} calls an undefined block",
+		"Defined blocks: badge(status) at line 2")
+}
+
+// TestBlocks_ArityMismatch verifies a call with the wrong number of arguments reports both the
+// call and the definition lines.
+func TestBlocks_ArityMismatch(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("arity", compiler.Options{})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err,
+		"StatusList.gt.html:7: {@render badge(...)} passes 1 argument(s), but the block defined at line 2 takes 2: badge(status, label)")
+}
+
+// TestBlocks_Recursion verifies blocks rendering each other are rejected.
+func TestBlocks_Recursion(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("recursive", compiler.Options{})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err,
+		"StatusList.gt.html:6: block 'badge' defined at line 2 renders itself (badge (line 2) -> label (line 5) -> badge)")
+}
@@ -0,0 +1,9 @@
+package blocks
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// StatusChip is a child component rendered from inside a template block.
+type StatusChip struct {
+	runtime.ComponentBase
+	Label string
+}
@@ -0,0 +1,32 @@
+package blocks
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Address is the nested field passed to the address block.
+type Address struct {
+	Street string
+	City   string
+}
+
+// Profile holds a member's address.
+type Profile struct {
+	Home Address
+}
+
+// Member is one team member.
+type Member struct {
+	ID      int
+	Name    string
+	Status  string
+	Tags    []string
+	Profile Profile
+}
+
+// TeamBoard renders the same status badge for the team and for every member through a local
+// template block.
+type TeamBoard struct {
+	runtime.ComponentBase
+	Status  string
+	Owner   Member
+	Members []Member
+}
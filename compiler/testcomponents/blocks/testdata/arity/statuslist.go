@@ -0,0 +1,16 @@
+package arity
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Item is one listed entry.
+type Item struct {
+	ID     int
+	Status string
+}
+
+// StatusList calls a two-parameter block with one argument.
+type StatusList struct {
+	runtime.ComponentBase
+	Status string
+	Items  []Item
+}
@@ -0,0 +1,16 @@
+package recursive
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Item is one listed entry.
+type Item struct {
+	ID     int
+	Status string
+}
+
+// StatusList defines two blocks that render each other.
+type StatusList struct {
+	runtime.ComponentBase
+	Status string
+	Items  []Item
+}
@@ -0,0 +1,16 @@
+package undefined
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Item is one listed entry.
+type Item struct {
+	ID     int
+	Status string
+}
+
+// StatusList calls a block under a misspelled name.
+type StatusList struct {
+	runtime.ComponentBase
+	Status string
+	Items  []Item
+}
@@ -0,0 +1,21 @@
+package classlist
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Task is a list entry tracked by ID.
+type Task struct {
+	ID   int
+	Name string
+	Done bool
+}
+
+// TaskCard composes its class attributes from independent bool conditions with
+// {classes ...}, on component fields and on loop variable fields.
+type TaskCard struct {
+	runtime.ComponentBase
+	Title    string
+	Active   bool
+	Disabled bool
+	Enabled  bool
+	Tasks    []Task
+}
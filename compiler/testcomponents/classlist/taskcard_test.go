@@ -0,0 +1,76 @@
+//go:build !wasm
+// +build !wasm
+
+package classlist
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestTaskCard_NoConditionMatches_OmitsClass verifies an empty class list omits the attribute
+// instead of rendering class="", while always-on literals remain.
+func TestTaskCard_NoConditionMatches_OmitsClass(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&TaskCard{Title: "Inbox", Enabled: true})
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	if got := root.Attributes["class"]; got != "card" {
+		t.Errorf("expected root class 'card', got %v", got)
+	}
+	span := root.Children[0]
+	if _, exists := span.Attributes["class"]; exists {
+		t.Errorf("expected no class attribute on the span, got %q", span.Attributes["class"])
+	}
+	if span.Attributes["title"] != "status" {
+		t.Errorf("expected other attributes to be kept, got %v", span.Attributes)
+	}
+}
+
+// TestTaskCard_AllConditionsMatch_JoinsInOrder verifies matching classes are joined with
+// single spaces in the order written, with no trailing whitespace.
+func TestTaskCard_AllConditionsMatch_JoinsInOrder(t *testing.T) {
+	// Arrange
+	card := &TaskCard{Title: "Inbox", Active: true, Disabled: true}
+	renderer := rendertest.NewTestRenderer(card)
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	if got := root.Attributes["class"]; got != "card is-active is-disabled is-readonly" {
+		t.Errorf("expected all root classes, got %q", got)
+	}
+	if got := root.Children[0].Attributes["class"]; got != "badge badge-on badge-off" {
+		t.Errorf("expected all span classes, got %q", got)
+	}
+}
+
+// TestTaskCard_LoopScopedConditions verifies conditions on loop variable fields are
+// evaluated per item and follow state changes.
+func TestTaskCard_LoopScopedConditions(t *testing.T) {
+	// Arrange
+	card := &TaskCard{Tasks: []Task{{ID: 1, Name: "Write", Done: true}, {ID: 2, Name: "Review"}}}
+	renderer := rendertest.NewTestRenderer(card)
+	renderer.RenderRoot()
+
+	// Act
+	card.Tasks[1].Done = true
+	card.StateHasChanged()
+	root := renderer.WaitForRender(t)
+
+	// Assert
+	items := root.Children[1].Children
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	for i, item := range items {
+		if got := item.Attributes["class"]; got != "task is-done" {
+			t.Errorf("item %d: expected 'task is-done', got %q", i, got)
+		}
+	}
+}
@@ -5,12 +5,12 @@ package conditionalform
 import (
 	"testing"
 
-	"github.com/ForgeLogic/nojs-compiler/testcomponents"
+	"github.com/ForgeLogic/nojs/rendertest"
 )
 
 // previewDiv extracts the conditional div (live-preview or live-preview muted)
 // from the rendered root, failing the test if the structure is unexpected.
-func previewDiv(t *testing.T, renderer *testcomponents.TestRenderer) string {
+func previewDiv(t *testing.T, renderer *rendertest.TestRenderer) string {
 	t.Helper()
 	root := renderer.GetCurrentVDOM()
 	if root == nil {
@@ -39,7 +39,7 @@ func previewDiv(t *testing.T, renderer *testcomponents.TestRenderer) string {
 // initial render (no name entered) the muted placeholder branch is rendered.
 func TestConditionalForm_InitialRender_ShowsMutedPlaceholder(t *testing.T) {
 	comp := &ConditionalForm{}
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	renderer.RenderRoot()
 
 	class := previewDiv(t, renderer)
@@ -52,7 +52,7 @@ func TestConditionalForm_InitialRender_ShowsMutedPlaceholder(t *testing.T) {
 // types a name the live-preview branch is rendered with the correct content.
 func TestConditionalForm_TypeName_ShowsLivePreview(t *testing.T) {
 	comp := &ConditionalForm{}
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	renderer.RenderRoot()
 
 	comp.SetName("Alice")
@@ -98,7 +98,7 @@ func TestConditionalForm_TypeName_ShowsLivePreview(t *testing.T) {
 // patchChildren call removed that text node.
 func TestConditionalForm_TypeThenClear_RestoresMutedPlaceholder(t *testing.T) {
 	comp := &ConditionalForm{}
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	renderer.RenderRoot()
 
 	// Step 1: type a name
@@ -135,7 +135,7 @@ func TestConditionalForm_TypeThenClear_RestoresMutedPlaceholder(t *testing.T) {
 // type-then-clear cycles continue to toggle the conditional correctly.
 func TestConditionalForm_MultipleTypeClearCycles(t *testing.T) {
 	comp := &ConditionalForm{}
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	renderer.RenderRoot()
 
 	for i, name := range []string{"Alice", "", "Bob", "", "Charlie", ""} {
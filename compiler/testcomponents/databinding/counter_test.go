@@ -6,7 +6,7 @@ package databinding
 import (
 	"testing"
 
-	"github.com/ForgeLogic/nojs-compiler/testcomponents"
+	"github.com/ForgeLogic/nojs/rendertest"
 )
 
 // TestDataBinding_InitialRender verifies that data binding correctly
@@ -19,7 +19,7 @@ func TestDataBinding_InitialRender(t *testing.T) {
 	}
 
 	// Attach the test renderer
-	renderer := testcomponents.NewTestRenderer(counter)
+	renderer := rendertest.NewTestRenderer(counter)
 
 	// Act: Perform initial render
 	vnode := renderer.RenderRoot()
@@ -63,7 +63,7 @@ func TestDataBinding_StateUpdate(t *testing.T) {
 		Label: "Initial",
 	}
 
-	renderer := testcomponents.NewTestRenderer(counter)
+	renderer := rendertest.NewTestRenderer(counter)
 	vnode1 := renderer.RenderRoot()
 
 	// Verify initial state
@@ -99,7 +99,7 @@ func TestDataBinding_MultipleUpdates(t *testing.T) {
 		Label: "Start",
 	}
 
-	renderer := testcomponents.NewTestRenderer(counter)
+	renderer := rendertest.NewTestRenderer(counter)
 	renderer.RenderRoot()
 
 	// Act & Assert: Multiple increments
@@ -134,8 +134,8 @@ func TestDataBinding_RenderIsolation(t *testing.T) {
 	counter1 := &Counter{Count: 10, Label: "First"}
 	counter2 := &Counter{Count: 20, Label: "Second"}
 
-	renderer1 := testcomponents.NewTestRenderer(counter1)
-	renderer2 := testcomponents.NewTestRenderer(counter2)
+	renderer1 := rendertest.NewTestRenderer(counter1)
+	renderer2 := rendertest.NewTestRenderer(counter2)
 
 	// Act: Render both
 	vnode1 := renderer1.RenderRoot()
@@ -163,3 +163,28 @@ func TestDataBinding_RenderIsolation(t *testing.T) {
 		t.Errorf("Counter2 should still be 20, got: %s", vnode2.Children[0].Content)
 	}
 }
+
+// TestDataBinding_BatchedUpdates verifies that StateHasChanged calls made before a flush
+// share one render, which shows every update.
+func TestDataBinding_BatchedUpdates(t *testing.T) {
+	// Arrange
+	counter := &Counter{Count: 0, Label: "Start"}
+	renderer := rendertest.NewTestRenderer(counter)
+	renderer.RenderRoot()
+
+	// Act: Three state changes, each calling StateHasChanged
+	counter.Increment()
+	counter.Increment()
+	counter.SetLabel("Batched")
+	renderer.Flush()
+
+	// Assert
+	if got := len(renderer.Renders()); got != 2 {
+		t.Errorf("Expected the initial render plus 1 batched render, got %d renders", got)
+	}
+	vnode := renderer.GetCurrentVDOM()
+	if vnode.Children[0].Content != "Count: 2" || vnode.Children[1].Content != "Label: Batched" {
+		t.Errorf("Expected 'Count: 2' and 'Label: Batched', got '%s' and '%s'",
+			vnode.Children[0].Content, vnode.Children[1].Content)
+	}
+}
@@ -0,0 +1,84 @@
+//go:build !wasm
+// +build !wasm
+
+package deeptree
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// TestReplyThread_5000Deep_MeasuredWithoutOverflow verifies that a 5000-level component tree
+// can be measured and checked against the depth limit without recursion.
+func TestReplyThread_5000Deep_MeasuredWithoutOverflow(t *testing.T) {
+	// Arrange
+	thread := &ReplyThread{Level: 1, MaxDepth: 5000, Text: "reply"}
+	renderer := rendertest.NewTestRenderer(thread)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert
+	// Each level is a <div> holding a <p>, so the deepest node sits at 5001
+	if got := vdom.Depth(vnode); got != 5001 {
+		t.Fatalf("Expected tree depth 5001, got %d", got)
+	}
+	var depthErr *vdom.DepthError
+	if !errors.As(vdom.CheckDepth(vnode), &depthErr) {
+		t.Fatal("Expected CheckDepth to report the tree as too deep")
+	}
+	if !strings.HasPrefix(depthErr.Path, "div.reply > div.reply") {
+		t.Errorf("Expected the path to start at the root thread, got %q", depthErr.Path)
+	}
+	if renderer.GetChild("reply_5000") == nil {
+		t.Error("Expected the deepest reply to be rendered as a child component")
+	}
+}
+
+// TestReplyThread_5000Deep_ReRenderKeepsChildren verifies that re-rendering the deep tree
+// reuses the cached child instances and picks up changed props at every level.
+func TestReplyThread_5000Deep_ReRenderKeepsChildren(t *testing.T) {
+	// Arrange
+	thread := &ReplyThread{Level: 1, MaxDepth: 5000, Text: "reply"}
+	renderer := rendertest.NewTestRenderer(thread)
+	renderer.RenderRoot()
+	deepest := renderer.GetChild("reply_5000")
+
+	// Act
+	thread.Text = "edited"
+	renderer.ReRender()
+
+	// Assert
+	if renderer.GetChild("reply_5000") != deepest {
+		t.Error("Expected the deepest reply instance to be reused")
+	}
+	var last *vdom.VNode
+	vdom.Walk(renderer.GetCurrentVDOM(), func(n *vdom.VNode, depth int) bool {
+		if n.Tag == "p" {
+			last = n
+		}
+		return true
+	})
+	if last == nil || last.Content != "edited #5000" {
+		t.Errorf("Expected the deepest comment to read 'edited #5000', got %+v", last)
+	}
+}
+
+// TestReplyThread_WithinLimit_PassesCheck verifies normal-sized trees are unaffected.
+func TestReplyThread_WithinLimit_PassesCheck(t *testing.T) {
+	// Arrange
+	thread := &ReplyThread{Level: 1, MaxDepth: 50, Text: "reply"}
+	renderer := rendertest.NewTestRenderer(thread)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert
+	if err := vdom.CheckDepth(vnode); err != nil {
+		t.Errorf("Expected a 50-level thread to pass the depth check, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package deeptree
+
+import (
+	"fmt"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// ReplyThread renders a comment with its reply nested inside it, down to MaxDepth levels.
+// Its Render is written by hand so the tests can build arbitrarily deep trees.
+type ReplyThread struct {
+	runtime.ComponentBase
+	Level    int
+	MaxDepth int
+	Text     string
+}
+
+// Render renders the comment and, below MaxDepth, the next ReplyThread level as a child.
+func (c *ReplyThread) Render(r runtime.Renderer) *vdom.VNode {
+	children := []*vdom.VNode{vdom.NewVNode("p", nil, nil, fmt.Sprintf("%s #%d", c.Text, c.Level))}
+	if c.Level < c.MaxDepth {
+		reply := &ReplyThread{Level: c.Level + 1, MaxDepth: c.MaxDepth, Text: c.Text}
+		children = append(children, r.RenderChild(fmt.Sprintf("reply_%d", c.Level+1), reply))
+	}
+	return vdom.NewVNode("div", map[string]any{"class": "reply"}, children, "")
+}
+
+// ApplyProps copies the props of a freshly built instance onto the cached one.
+func (c *ReplyThread) ApplyProps(source runtime.Component) {
+	if s, ok := source.(*ReplyThread); ok {
+		c.MaxDepth = s.MaxDepth
+		c.Text = s.Text
+	}
+}
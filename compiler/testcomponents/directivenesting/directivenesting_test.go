@@ -0,0 +1,79 @@
+//go:build !wasm
+// +build !wasm
+
+package directivenesting
+
+import (
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs-compiler/compilertest"
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestTaskPanel_BlocksWrapCompleteElements verifies blocks that wrap complete elements keep
+// compiling and render the markup they wrap, in place.
+func TestTaskPanel_BlocksWrapCompleteElements(t *testing.T) {
+	// Arrange
+	panel := &TaskPanel{
+		Title:    "Today",
+		Tasks:    []Task{{ID: 1, Name: "Write"}, {ID: 2, Name: "Review"}},
+		HasTasks: true,
+		ShowHint: true,
+	}
+
+	// Act
+	root := rendertest.NewTestRenderer(panel).RenderRoot()
+
+	// Assert
+	if len(root.Children) != 3 {
+		t.Fatalf("expected the heading, the list and the footer, got:\n%s", rendertest.FormatVNode(root))
+	}
+	list, footer := root.Children[1], root.Children[2]
+	if list.Tag != "ul" || len(list.Children) != 2 || list.Children[1].Content != "Review" {
+		t.Errorf("expected the task list with two tasks, got:\n%s", rendertest.FormatVNode(list))
+	}
+	if footer.Tag != "div" || len(footer.Children) != 1 || footer.Children[0].Tag != "span" {
+		t.Errorf("expected the hint inside the footer, got:\n%s", rendertest.FormatVNode(footer))
+	}
+}
+
+// TestTaskPanel_ElseBranch verifies the else branch replaces the list without touching its
+// siblings.
+func TestTaskPanel_ElseBranch(t *testing.T) {
+	// Act
+	root := rendertest.NewTestRenderer(&TaskPanel{Title: "Today"}).RenderRoot()
+
+	// Assert
+	if len(root.Children) != 3 || root.Children[1].Tag != "p" || root.Children[1].Content != "Nothing to do" {
+		t.Fatalf("expected the heading, the empty message and the footer, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if footer := root.Children[2]; len(footer.Children) != 0 {
+		t.Errorf("expected no hint in the footer, got:\n%s", rendertest.FormatVNode(footer))
+	}
+}
+
+// TestDirectiveNesting_SplitAcrossSiblings verifies a block opened in one element and closed in
+// its sibling reports both directive lines and the element that separates them.
+func TestDirectiveNesting_SplitAcrossSiblings(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("siblings", compiler.Options{})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err,
+		"Panel.gt.html:3: {@if} on line 3 and its {@endif} on line 6 are not inside the same element",
+		"the <div> opened on line 2 encloses the {@if}, but </div> on line 4 closes it before the {@endif}",
+		"Directives must wrap complete elements")
+}
+
+// TestDirectiveNesting_HalfTableRow verifies a block that starts in the middle of one table row
+// and ends in the next is rejected.
+func TestDirectiveNesting_HalfTableRow(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("halfrow", compiler.Options{})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err,
+		"ScoreTable.gt.html:4: {@if} on line 4 and its {@endif} on line 9 are not inside the same element",
+		"the <tr> opened on line 2 encloses the {@if}, but </tr> on line 6 closes it before the {@endif}")
+}
@@ -0,0 +1,19 @@
+package directivenesting
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Task is one listed task.
+type Task struct {
+	ID   int
+	Name string
+}
+
+// TaskPanel wraps complete elements in its blocks: the list or the empty message, and the hint
+// inside the footer.
+type TaskPanel struct {
+	runtime.ComponentBase
+	Title    string
+	Tasks    []Task
+	HasTasks bool
+	ShowHint bool
+}
@@ -0,0 +1,9 @@
+package halfrow
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// ScoreTable opens its block in the middle of one row and closes it in the next.
+type ScoreTable struct {
+	runtime.ComponentBase
+	ShowScore bool
+}
@@ -0,0 +1,9 @@
+package siblings
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Panel opens its block in the header and closes it in the body.
+type Panel struct {
+	runtime.ComponentBase
+	Collapsed bool
+}
@@ -0,0 +1,248 @@
+//go:build !wasm
+// +build !wasm
+
+package escaping
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+	"golang.org/x/tools/go/packages"
+)
+
+// TestNotice_StaticStringsRenderVerbatim verifies quotes, backslashes, percent signs and
+// newlines in the template reach the rendered attributes and text unchanged.
+func TestNotice_StaticStringsRenderVerbatim(t *testing.T) {
+	// Act
+	root := rendertest.NewTestRenderer(&Notice{Percent: 42, Active: true}).RenderRoot()
+
+	// Assert
+	want := map[string]string{"title": `Say "hi" \o/`, "data-pattern": `\d+%d`, "data-note": "line one\nline two"}
+	for name, value := range want {
+		if got := root.Attributes[name]; got != value {
+			t.Errorf("expected %s=%q, got %q", name, value, got)
+		}
+	}
+	if len(root.Children) != 4 {
+		t.Fatalf("expected four children, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if got := root.Children[0].Content; got != "42% done, 100% sure" {
+		t.Errorf("expected the progress text, got %q", got)
+	}
+	if span := root.Children[1]; len(span.Children) != 1 || span.Children[0].Content != "on at 50% full" {
+		t.Errorf("expected the state text, got:\n%s", rendertest.FormatVNode(span))
+	}
+	if got := root.Children[2].Attributes["style"]; got != "width: 42%" {
+		t.Errorf("expected the bar width, got %q", got)
+	}
+	if got := root.Children[3].Attributes["data-mode"]; got != "on 100%s" {
+		t.Errorf("expected the mode, got %q", got)
+	}
+}
+
+// attack breaks out of a quoted attribute into a new element.
+const attack = `"><img src=x onerror=alert(1)>`
+
+// newAttackedProfile returns a Profile carrying attack in every field, with script URLs.
+func newAttackedProfile() *Profile {
+	return &Profile{Name: attack, Bio: attack + "</div>", Website: "javascript:alert(1)", Avatar: " JaVa\tScRiPt:alert(1)"}
+}
+
+// TestProfile_DOMValuesAreInert verifies the values the DOM renderer sets keep text and
+// attributes verbatim, which setAttribute and textContent never parse, and sanitize URLs.
+func TestProfile_DOMValuesAreInert(t *testing.T) {
+	// Act
+	root := rendertest.NewTestRenderer(newAttackedProfile()).RenderRoot()
+
+	// Assert
+	dom := func(n *vdom.VNode, name string) string {
+		return vdom.DOMValue(vdom.ContextOf(name), fmt.Sprint(n.Attributes[name]))
+	}
+	if got := dom(root, "title"); got != attack {
+		t.Errorf("expected the title verbatim, got %q", got)
+	}
+	if len(root.Children) != 4 {
+		t.Fatalf("expected four children, got:\n%s", rendertest.FormatVNode(root))
+	}
+	if got := root.Children[0].Content; got != attack {
+		t.Errorf("expected the heading text verbatim, got %q", got)
+	}
+	if got := dom(root.Children[1], "href"); got != vdom.UnsafeURL {
+		t.Errorf("expected the javascript: website to be replaced, got %q", got)
+	}
+	if got := dom(root.Children[2], "href"); got != "/users/"+attack {
+		t.Errorf("expected the relative profile URL to be kept, got %q", got)
+	}
+	if got := dom(root.Children[3], "src"); got != vdom.UnsafeURL {
+		t.Errorf("expected the obfuscated javascript: avatar to be replaced, got %q", got)
+	}
+}
+
+// TestProfile_RenderToStringEscapes verifies the HTML of the same component holds no markup
+// from its fields: every value is escaped for its context and script URLs are replaced.
+func TestProfile_RenderToStringEscapes(t *testing.T) {
+	// Act
+	root := rendertest.NewTestRenderer(newAttackedProfile()).RenderRoot()
+	out := vdom.RenderToString(root)
+
+	// Assert
+	for _, unsafe := range []string{"<img src=x", "</div></div>", "javascript:", "JaVa"} {
+		if strings.Contains(out, unsafe) {
+			t.Errorf("expected no %q in the output, got:\n%s", unsafe, out)
+		}
+	}
+	escaped := "&#34;&gt;&lt;img src=x onerror=alert(1)&gt;"
+	for _, want := range []string{
+		`title="` + escaped + `"`,
+		`<h2>"&gt;&lt;img src=x onerror=alert(1)&gt;</h2>`, // Quotes need no escaping in text
+		`<a class="website" href="` + vdom.UnsafeURL + `">`,
+		`<img src="` + vdom.UnsafeURL + `">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the output, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestProfile_ShellOutputEscapes verifies the HTML the compiler writes into shells escapes the
+// same input with the same policy.
+func TestProfile_ShellOutputEscapes(t *testing.T) {
+	// Act
+	shell, err := compiler.InjectPreloadLinks("<html><head></head></html>", []compiler.PreloadAsset{{Href: attack, As: "image"}})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("InjectPreloadLinks: %v", err)
+	}
+	if strings.Contains(shell, "<img") || !strings.Contains(shell, `href="`+vdom.EscapeAttribute(attack)+`"`) {
+		t.Errorf("expected the href escaped, got:\n%s", shell)
+	}
+}
+
+// nastyStrings are strings that break generated code spliced together without quoting.
+var nastyStrings = []string{`"`, `\`, `\"`, `%`, `%d`, `%!v(MISSING)`, `a%%b`, "line\nbreak", "`", `\n`, `'`}
+
+// nastyTemplate is a component template with s in every place the generator copies template
+// text into generated code. Loops are included for the dev-mode warnings.
+const nastyTemplate = `<section title="%[1]s" data-mixed="%[1]s {Count}" data-pick="{Flag ? 'a' : 'b'}%[1]s">
+    <p>{Count}%[2]s</p>
+    <span>{Flag ? 'a' : 'b'} %[2]s</span>
+    <ul>
+        {@for _, item := range Items trackBy item.ID}
+        <li title="%[1]s">{item.Name}</li>
+        {@endfor}
+    </ul>
+</section>
+`
+
+// nastySource is the Go struct of nastyTemplate.
+const nastySource = `package nasty
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+type Item struct {
+	ID   int
+	Name string
+}
+
+type Nasty struct {
+	runtime.ComponentBase
+	Count int
+	Flag  bool
+	Items []*Item
+}
+`
+
+// TestCompile_NastyStrings compiles a component per nasty string, in dev mode, and checks the
+// generated file holds the string unchanged in its static attribute and escaped in its format
+// strings, and that the generated packages type-check for js/wasm and native builds.
+func TestCompile_NastyStrings(t *testing.T) {
+	t.Cleanup(func() { os.RemoveAll("testdata") })
+	var dirs []string
+	for i, s := range nastyStrings {
+		dir := filepath.Join("testdata", fmt.Sprintf("nasty%d", i))
+		dirs = append(dirs, "./"+filepath.ToSlash(dir))
+		t.Run(strconv.Quote(s), func(t *testing.T) {
+			// Arrange
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			template := fmt.Sprintf(nastyTemplate, html.EscapeString(s), html.EscapeString(s))
+			if err := os.WriteFile(filepath.Join(dir, "Nasty.gt.html"), []byte(template), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "nasty.go"), []byte(nastySource), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// Act
+			err := compiler.CompileWithOptions(dir, compiler.Options{DevMode: true})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("expected the component to compile, got %v", err)
+			}
+			literals := stringLiterals(t, filepath.Join(dir, "Nasty.generated.go"))
+			if !literals[s] {
+				t.Errorf("expected the static title %q in the generated code", s)
+			}
+			if format := strings.ReplaceAll(s, "%", "%%") + " %v"; !literals[format] {
+				t.Errorf("expected the mixed attribute format %q in the generated code", format)
+			}
+		})
+	}
+	assertTypeChecks(t, "js/wasm", []string{"GOOS=js", "GOARCH=wasm"}, dirs)
+	assertTypeChecks(t, "native", nil, dirs)
+}
+
+// assertTypeChecks type-checks the packages in dirs with env added to the environment, and
+// reports every error against platform.
+func assertTypeChecks(t *testing.T, platform string, env []string, dirs []string) {
+	t.Helper()
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedSyntax | packages.NeedTypes,
+		Env:  append(os.Environ(), env...),
+	}
+	pkgs, err := packages.Load(cfg, dirs...)
+	if err != nil {
+		t.Fatalf("failed to load the generated packages for %s: %v", platform, err)
+	}
+	if len(pkgs) != len(dirs) {
+		t.Errorf("expected %d generated packages for %s, got %d", len(dirs), platform, len(pkgs))
+	}
+	for _, pkg := range pkgs {
+		for _, pkgErr := range pkg.Errors {
+			t.Errorf("expected the generated code to type-check for %s: %v", platform, pkgErr)
+		}
+	}
+}
+
+// stringLiterals parses the Go file at path and returns the values of its string literals.
+func stringLiterals(t *testing.T, path string) map[string]bool {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatalf("expected the generated file to parse: %v", err)
+	}
+	literals := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if value, err := strconv.Unquote(lit.Value); err == nil {
+				literals[value] = true
+			}
+		}
+		return true
+	})
+	return literals
+}
@@ -0,0 +1,11 @@
+package escaping
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Notice carries quotes, backslashes, percent signs and newlines in its static attributes
+// and next to its bindings, which the generated code must reproduce verbatim.
+type Notice struct {
+	runtime.ComponentBase
+	Percent int
+	Active  bool
+}
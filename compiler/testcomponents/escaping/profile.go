@@ -0,0 +1,13 @@
+package escaping
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Profile renders user-supplied strings into text, plain attributes and URL attributes,
+// which every output backend must escape or sanitize for its context.
+type Profile struct {
+	runtime.ComponentBase
+	Name    string
+	Bio     string
+	Website string
+	Avatar  string
+}
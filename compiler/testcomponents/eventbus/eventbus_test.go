@@ -0,0 +1,83 @@
+//go:build !wasm
+// +build !wasm
+
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// mountCatalog renders a Catalog and mounts its Results child, which is where the
+// listener is registered.
+func mountCatalog(t *testing.T) (*rendertest.TestRenderer, *Results) {
+	t.Helper()
+	renderer := rendertest.NewTestRenderer(&Catalog{Items: []string{"go", "golang", "rust"}})
+	renderer.RenderRoot()
+	results, ok := renderer.GetChild("Results_0").(*Results)
+	if !ok {
+		t.Fatalf("Expected Results_0 to be a *Results, got %T", renderer.GetChild("Results_0"))
+	}
+	results.OnMount()
+	renderer.ReRender()
+	return renderer, results
+}
+
+// searchInput returns the <input> rendered by QueryBox.
+func searchInput(root *vdom.VNode) *vdom.VNode {
+	header := root.Children[0]
+	return header.Children[0].Children[0]
+}
+
+// matches returns the texts of the rendered result items.
+func matches(root *vdom.VNode) []string {
+	list := root.Children[1].Children[0].Children[1]
+	var texts []string
+	for _, item := range list.Children {
+		if len(item.Children) == 1 {
+			texts = append(texts, item.Children[0].Content)
+		} else {
+			texts = append(texts, item.Content)
+		}
+	}
+	return texts
+}
+
+// TestCatalog_QueryBoxReachesResultsInAnotherBranch verifies that typing in QueryBox
+// updates Results through the event, with no callback passed down by Catalog.
+func TestCatalog_QueryBoxReachesResultsInAnotherBranch(t *testing.T) {
+	// Arrange
+	renderer, results := mountCatalog(t)
+
+	// Act
+	rendertest.FireEvent(t, searchInput(renderer.GetCurrentVDOM()), "input", events.ChangeEventArgs{Value: "go"})
+
+	// Assert
+	if results.Query != "go" {
+		t.Errorf("Expected Results.Query to be %q, got %q", "go", results.Query)
+	}
+	got := matches(renderer.GetCurrentVDOM())
+	if len(got) != 2 || got[0] != "go" || got[1] != "golang" {
+		t.Errorf("Expected matches [go golang], got %v", got)
+	}
+}
+
+// TestCatalog_DestroyedResultsStopListening verifies that the listener registered in
+// OnMount is removed once Results is destroyed.
+func TestCatalog_DestroyedResultsStopListening(t *testing.T) {
+	// Arrange
+	renderer, results := mountCatalog(t)
+	runtime.Destroy(results)
+
+	// Act
+	rendertest.FireEvent(t, searchInput(renderer.GetCurrentVDOM()), "input", events.ChangeEventArgs{Value: "rust"})
+
+	// Assert
+	if results.Query != "" {
+		t.Errorf("Expected a destroyed Results to ignore the event, got Query %q", results.Query)
+	}
+}
@@ -0,0 +1,54 @@
+package eventbus
+
+import (
+	"strings"
+
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// SearchChanged carries the query typed in QueryBox to whoever listens, wherever it is.
+var SearchChanged = runtime.DefineEvent[string]("search-changed")
+
+// QueryBox emits SearchChanged as the user types.
+type QueryBox struct {
+	runtime.ComponentBase
+	Query string `nojs:"state"`
+}
+
+// Type updates the query and announces it.
+func (c *QueryBox) Type(e events.ChangeEventArgs) {
+	c.Query = e.Value
+	runtime.Emit(SearchChanged, c.Query)
+}
+
+// Results lists the items matching the latest query. It sits in another branch of the
+// layout than QueryBox and receives the query through SearchChanged.
+type Results struct {
+	runtime.ComponentBase
+	Items   []string
+	Query   string   `nojs:"state"`
+	Matches []string `nojs:"state"`
+}
+
+// OnMount starts listening; the listener is removed when Results is destroyed.
+func (c *Results) OnMount() {
+	c.filter("")
+	runtime.Listen(c, SearchChanged, c.filter)
+}
+
+func (c *Results) filter(query string) {
+	c.Query = query
+	c.Matches = c.Matches[:0]
+	for _, item := range c.Items {
+		if strings.Contains(item, query) {
+			c.Matches = append(c.Matches, item)
+		}
+	}
+}
+
+// Catalog places QueryBox in the header and Results in the main section.
+type Catalog struct {
+	runtime.ComponentBase
+	Items []string
+}
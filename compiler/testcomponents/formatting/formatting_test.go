@@ -0,0 +1,163 @@
+//go:build !wasm
+// +build !wasm
+
+package formatting
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestReceipt_EmitsFormatVerbsPerType verifies the format strings the compiler emits for
+// each bound field type.
+func TestReceipt_EmitsFormatVerbsPerType(t *testing.T) {
+	// Arrange
+	source, err := os.ReadFile("Receipt.generated.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated component: %v", err)
+	}
+
+	// Act
+	generated := string(source)
+
+	// Assert
+	for _, want := range []string{
+		`fmt.Sprintf("Label: %v", c.Label)`,
+		`fmt.Sprintf("Count: %v", c.Count)`,
+		`fmt.Sprintf("Total: %g", c.Total)`,
+		`fmt.Sprintf("Rounded: %.2f", c.Total)`,
+		`fmt.Sprintf("Ratio: %g", c.Ratio)`,
+		`fmt.Sprintf("Paid: %t", c.Paid)`,
+		`fmt.Sprintf("Created: %s", c.CreatedAt.Format("2006-01-02"))`,
+		`fmt.Sprintf("Shipping: %.2f", func() any {`,
+		`fmt.Sprintf("%v%% complete", c.Count)`,
+		`fmt.Sprintf("%v: %g", line.Name, line.Price)`,
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("Expected generated code to contain %s", want)
+		}
+	}
+}
+
+// TestReceipt_RendersFormattedValues verifies the rendered text for each bound field type.
+func TestReceipt_RendersFormattedValues(t *testing.T) {
+	// Arrange
+	receipt := &Receipt{
+		Label:     "Order",
+		Count:     3,
+		Total:     1234567.5,
+		Ratio:     0.1,
+		Paid:      true,
+		CreatedAt: time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC),
+		Shipping:  &Shipping{Cost: 4.5},
+		Lines:     []Line{{Name: "Tea", Price: 2.25}},
+	}
+	renderer := rendertest.NewTestRenderer(receipt)
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	want := []string{
+		"Label: Order",
+		"Count: 3",
+		"Total: 1.2345675e+06",
+		"Rounded: 1234567.50",
+		"Ratio: 0.1",
+		"Paid: true",
+		"Created: 2026-03-14",
+		"Shipping: 4.50",
+		"3% complete",
+	}
+	for i, text := range want {
+		if got := rendertest.TextOf(root.Children[i]); got != text {
+			t.Errorf("Child %d: expected %q, got %q", i, text, got)
+		}
+	}
+	if got := rendertest.TextOf(root.Children[len(want)].Children[0]); got != "Tea: 2.25" {
+		t.Errorf("Expected loop item 'Tea: 2.25', got %q", got)
+	}
+}
+
+// TestReceipt_NilShipping_RendersTypedZero verifies that a printf-formatted binding through a
+// nil pointer renders the formatted zero value rather than a fmt type error.
+func TestReceipt_NilShipping_RendersTypedZero(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&Receipt{})
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	if got := rendertest.TextOf(root.Children[7]); got != "Shipping: 0.00" {
+		t.Errorf("Expected 'Shipping: 0.00', got %q", got)
+	}
+	if got := rendertest.TextOf(root.Children[5]); got != "Paid: false" {
+		t.Errorf("Expected 'Paid: false', got %q", got)
+	}
+}
+
+// TestOverview_ZeroValues_RenderDefaults verifies the default filter renders its fallback for
+// the zero value of each type, and for a binding read through a nil pointer.
+func TestOverview_ZeroValues_RenderDefaults(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&Overview{})
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	want := []string{
+		"Year: —",
+		"Price: n/a",
+		"Name: Anonymous",
+		"Active: no",
+		"Updated: never",
+		"Score: none",
+		"Shipping: free",
+	}
+	for i, text := range want {
+		if got := rendertest.TextOf(root.Children[i]); got != text {
+			t.Errorf("Child %d: expected %q, got %q", i, text, got)
+		}
+	}
+}
+
+// TestOverview_SetValues_RenderFormatted verifies a value other than the zero value renders
+// as it would without the default filter, through the formatting filter when there is one.
+func TestOverview_SetValues_RenderFormatted(t *testing.T) {
+	// Arrange
+	overview := &Overview{
+		Year:      2026,
+		Price:     9.5,
+		Name:      "Ada",
+		Active:    true,
+		UpdatedAt: time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC),
+		Score:     7,
+		Shipping:  &Shipping{Cost: 4.5},
+	}
+	renderer := rendertest.NewTestRenderer(overview)
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	want := []string{
+		"Year: 2026",
+		"Price: 9.50",
+		"Name: Ada",
+		"Active: true",
+		"Updated: 2026-03-14",
+		"Score: 7",
+		"Shipping: 4.50",
+	}
+	for i, text := range want {
+		if got := rendertest.TextOf(root.Children[i]); got != text {
+			t.Errorf("Child %d: expected %q, got %q", i, text, got)
+		}
+	}
+}
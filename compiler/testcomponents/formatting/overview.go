@@ -0,0 +1,23 @@
+package formatting
+
+import (
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Points is a named number, compared to its zero value like the built-in types.
+type Points int
+
+// Overview binds one field of each type with a default filter, which renders the fallback
+// while the field holds its zero value.
+type Overview struct {
+	runtime.ComponentBase
+	Year      int
+	Price     float64
+	Name      string
+	Active    bool
+	UpdatedAt time.Time
+	Score     Points
+	Shipping  *Shipping
+}
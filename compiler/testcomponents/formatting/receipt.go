@@ -0,0 +1,32 @@
+package formatting
+
+import (
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Line is an entry of Receipt.Lines.
+type Line struct {
+	Name  string
+	Price float64
+}
+
+// Shipping is reached through a pointer that may be nil.
+type Shipping struct {
+	Cost float64
+}
+
+// Receipt binds one field of each formatted type in text: strings and ints keep %v,
+// floats use %g, booleans %t, and times must go through the date filter.
+type Receipt struct {
+	runtime.ComponentBase
+	Label     string
+	Count     int
+	Total     float64
+	Ratio     float32
+	Paid      bool
+	CreatedAt time.Time
+	Shipping  *Shipping
+	Lines     []Line
+}
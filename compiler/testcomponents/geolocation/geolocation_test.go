@@ -0,0 +1,104 @@
+//go:build !wasm
+// +build !wasm
+
+package geolocation
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/browser"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// message returns the text of the locator's status paragraph.
+func message(root *vdom.VNode) string {
+	return root.Children[1].Content
+}
+
+// mountLocator renders a StoreLocator and mounts it, starting its watch.
+func mountLocator() (*StoreLocator, *rendertest.TestRenderer) {
+	locator := &StoreLocator{}
+	renderer := rendertest.NewTestRenderer(locator)
+	renderer.RenderRoot()
+	locator.OnMount()
+	return locator, renderer
+}
+
+// TestStoreLocator_RendersWatchedPositions verifies the locator renders the scripted position
+// and follows the positions pushed while it watches.
+func TestStoreLocator_RendersWatchedPositions(t *testing.T) {
+	// Arrange
+	fake := browser.UseFakeGeolocation(browser.PermissionGranted,
+		browser.GeolocationResult{Position: browser.Position{Latitude: 52.37, Longitude: 4.89}})
+	t.Cleanup(fake.Uninstall)
+	_, renderer := mountLocator()
+	first := message(renderer.WaitForRender(t))
+
+	// Act
+	fake.Push(browser.GeolocationResult{Position: browser.Position{Latitude: 48.85, Longitude: 2.35}})
+
+	// Assert
+	if first != "52.37, 4.89" {
+		t.Errorf("expected the first position to be rendered, got %q", first)
+	}
+	if got := message(renderer.WaitForRender(t)); got != "48.85, 2.35" {
+		t.Errorf("expected the pushed position to be rendered, got %q", got)
+	}
+}
+
+// TestStoreLocator_RendersDeniedState verifies a denied permission renders the error state.
+func TestStoreLocator_RendersDeniedState(t *testing.T) {
+	// Arrange
+	fake := browser.UseFakeGeolocation(browser.PermissionDenied)
+	t.Cleanup(fake.Uninstall)
+
+	// Act
+	_, renderer := mountLocator()
+
+	// Assert
+	want := "Location access is blocked. Allow it in the browser settings to see stores near you."
+	if got := message(renderer.WaitForRender(t)); got != want {
+		t.Errorf("expected the denied state, got %q", got)
+	}
+	if fake.Watching() != 0 {
+		t.Errorf("expected no active watch after a denial, got %d", fake.Watching())
+	}
+}
+
+// TestStoreLocator_RendersTimeout verifies a failed fix renders the error message.
+func TestStoreLocator_RendersTimeout(t *testing.T) {
+	// Arrange
+	fake := browser.UseFakeGeolocation(browser.PermissionGranted,
+		browser.GeolocationResult{Err: &browser.GeolocationError{Kind: browser.ErrTimeout}})
+	t.Cleanup(fake.Uninstall)
+
+	// Act
+	_, renderer := mountLocator()
+
+	// Assert
+	if got := message(renderer.WaitForRender(t)); got != browser.ErrTimeout.Error() {
+		t.Errorf("expected the timeout message, got %q", got)
+	}
+}
+
+// TestStoreLocator_DestroyStopsWatch verifies destroying the locator stops its watch.
+func TestStoreLocator_DestroyStopsWatch(t *testing.T) {
+	// Arrange
+	fake := browser.UseFakeGeolocation(browser.PermissionGranted)
+	t.Cleanup(fake.Uninstall)
+	locator, _ := mountLocator()
+
+	// Act
+	runtime.Destroy(locator)
+	fake.Push(browser.GeolocationResult{Position: browser.Position{Latitude: 1, Longitude: 2}})
+
+	// Assert
+	if fake.Watching() != 0 {
+		t.Errorf("expected the watch to stop with the component, %d still active", fake.Watching())
+	}
+	if locator.Located {
+		t.Error("expected no position to reach the destroyed component")
+	}
+}
@@ -0,0 +1,41 @@
+package geolocation
+
+import (
+	"errors"
+
+	"github.com/ForgeLogic/nojs/browser"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// StoreLocator follows the device position with a geolocation watch started on mount and
+// stopped with the component.
+type StoreLocator struct {
+	runtime.ComponentBase
+	Located   bool
+	Latitude  float64
+	Longitude float64
+	Denied    bool
+	Failed    bool
+	Problem   string
+}
+
+func (c *StoreLocator) OnMount() {
+	if _, err := browser.Geolocation.Watch(c, browser.PositionOptions{HighAccuracy: true}, c.moved, c.failed); err != nil {
+		c.failed(err)
+	}
+}
+
+func (c *StoreLocator) moved(pos browser.Position) {
+	c.Located, c.Failed = true, false
+	c.Latitude, c.Longitude = pos.Latitude, pos.Longitude
+	c.StateHasChanged()
+}
+
+func (c *StoreLocator) failed(err error) {
+	if errors.Is(err, browser.ErrPermissionDenied) {
+		c.Denied = true
+	} else {
+		c.Failed, c.Problem = true, err.Error()
+	}
+	c.StateHasChanged()
+}
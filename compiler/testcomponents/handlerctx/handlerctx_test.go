@@ -0,0 +1,86 @@
+//go:build !wasm
+// +build !wasm
+
+package handlerctx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// status returns the text of the form's status paragraph.
+func status(root *vdom.VNode) string {
+	return root.Children[3].Content
+}
+
+// newSaveForm renders a SaveForm whose save operations are buffered for the test.
+func newSaveForm() (*SaveForm, *rendertest.TestRenderer, *vdom.VNode) {
+	form := &SaveForm{Pending: make(chan func() error, 1)}
+	renderer := rendertest.NewTestRenderer(form)
+	return form, renderer, renderer.RenderRoot()
+}
+
+// TestSaveForm_SafeUpdateRendersWhileAlive verifies an operation completing after the event
+// applies its result and re-renders the form through the handler context.
+func TestSaveForm_SafeUpdateRendersWhileAlive(t *testing.T) {
+	// Arrange
+	form, renderer, root := newSaveForm()
+	rendertest.FireEvent(t, root.Children[0], "input", events.ChangeEventArgs{Value: "Draft 1"})
+	rendertest.FireEvent(t, root.Children[1], "click", events.ClickEventArgs{})
+	complete := <-form.Pending
+
+	// Act
+	err := complete()
+
+	// Assert
+	if err != nil {
+		t.Errorf("expected the handler context to be live, got %v", err)
+	}
+	if got := status(renderer.GetCurrentVDOM()); got != "Saved Draft 1" {
+		t.Errorf("expected status 'Saved Draft 1' to be rendered, got %q", got)
+	}
+}
+
+// TestSaveForm_SafeUpdateAfterDestroyDoesNothing verifies an operation completing after the
+// form was destroyed sees a cancelled context and leaves the form untouched.
+func TestSaveForm_SafeUpdateAfterDestroyDoesNothing(t *testing.T) {
+	// Arrange
+	form, renderer, root := newSaveForm()
+	rendertest.FireEvent(t, root.Children[1], "click", events.ClickEventArgs{})
+	complete := <-form.Pending
+	rendered := renderer.WaitForRender(t)
+
+	// Act
+	runtime.Destroy(form)
+	err := complete()
+
+	// Assert
+	if err != context.Canceled {
+		t.Errorf("expected the handler context to be cancelled, got %v", err)
+	}
+	if form.Status != "Saving" {
+		t.Errorf("expected SafeUpdate to skip the update, got status %q", form.Status)
+	}
+	if renderer.GetCurrentVDOM() != rendered {
+		t.Error("expected no re-render after the form was destroyed")
+	}
+}
+
+// TestSaveForm_CtxNavigates verifies a handler taking only the context can navigate.
+func TestSaveForm_CtxNavigates(t *testing.T) {
+	// Arrange
+	_, renderer, root := newSaveForm()
+
+	// Act
+	rendertest.FireEvent(t, root.Children[2], "click", nil)
+
+	// Assert
+	if got := renderer.Navigations(); len(got) != 1 || got[0] != "/" {
+		t.Errorf("expected navigation to /, got %v", got)
+	}
+}
@@ -0,0 +1,38 @@
+package handlerctx
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// SaveForm saves a draft asynchronously, with handlers taking the extended runtime.Ctx form.
+type SaveForm struct {
+	runtime.ComponentBase
+	Draft  string
+	Status string
+
+	// Pending receives the save operations started by Save; tests complete them and get the
+	// error of the handler context at completion
+	Pending chan func() error
+}
+
+// Edit mixes the extended signature with a non-click event.
+func (c *SaveForm) Edit(ctx runtime.Ctx, e events.ChangeEventArgs) {
+	c.Draft = e.Value
+}
+
+// Save starts a save that completes later, outside the event, and reports its result
+// through SafeUpdate.
+func (c *SaveForm) Save(ctx runtime.Ctx, e events.ClickEventArgs) {
+	c.Status = "Saving"
+	draft := c.Draft
+	c.Pending <- func() error {
+		ctx.SafeUpdate(func() { c.Status = "Saved " + draft })
+		return ctx.Err()
+	}
+}
+
+// Cancel leaves the form.
+func (c *SaveForm) Cancel(ctx runtime.Ctx) {
+	ctx.Navigate("/")
+}
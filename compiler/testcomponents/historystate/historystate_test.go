@@ -0,0 +1,101 @@
+//go:build !wasm
+// +build !wasm
+
+package historystate
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// mapBag is a runtime.HistoryBag keeping JSON values in a map, as the router's bag does.
+type mapBag map[string]json.RawMessage
+
+func (b mapBag) Get(key string, v any) bool {
+	raw, ok := b[key]
+	return ok && json.Unmarshal(raw, v) == nil
+}
+
+func (b mapBag) Set(key string, v any) error {
+	raw, err := json.Marshal(v)
+	b[key] = raw
+	return err
+}
+
+// Verify the generated methods make Wizard history-stateful.
+var _ runtime.HistoryStateful = (*Wizard)(nil)
+
+// TestWizard_SavesTaggedFields verifies only the fields tagged with a history key are saved,
+// under their keys.
+func TestWizard_SavesTaggedFields(t *testing.T) {
+	// Arrange
+	wizard := &Wizard{Title: "Signup", Step: 2, Answers: map[string]string{"name": "Ada"}, Draft: "typing"}
+	bag := mapBag{}
+
+	// Act
+	err := wizard.SaveHistoryState(bag)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := mapBag{"wizard.step": json.RawMessage(`2`), "wizard.answers": json.RawMessage(`{"name":"Ada"}`)}
+	if !reflect.DeepEqual(bag, want) {
+		t.Errorf("expected %s, got %s", want, bag)
+	}
+}
+
+// TestWizard_RestoresTaggedFields verifies a new instance gets the saved fields back before
+// its first render, and keeps its own value for keys the bag lacks.
+func TestWizard_RestoresTaggedFields(t *testing.T) {
+	// Arrange
+	saved := &Wizard{Step: 3, Answers: map[string]string{"plan": "pro"}, Draft: "lost"}
+	bag := mapBag{}
+	if err := saved.SaveHistoryState(bag); err != nil {
+		t.Fatal(err)
+	}
+	delete(bag, "wizard.answers")
+
+	// Act
+	restored := &Wizard{Answers: map[string]string{}}
+	restored.RestoreHistoryState(bag)
+
+	// Assert
+	if restored.Step != 3 {
+		t.Errorf("expected step 3, got %d", restored.Step)
+	}
+	if restored.Answers == nil || len(restored.Answers) != 0 {
+		t.Errorf("expected the answers to keep their value, got %v", restored.Answers)
+	}
+	if restored.Draft != "" {
+		t.Errorf("expected the draft not to be restored, got %q", restored.Draft)
+	}
+}
+
+// TestHistoryKey_Invalid verifies history keys on a prop or used twice are rejected.
+func TestHistoryKey_Invalid(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    string
+	}{
+		{"duplicatekey", `fields 'Name' and 'Email' of component 'Signup' use the same history key "form"`},
+		{"propkey", "field 'Name' of component 'Signup': the history option needs a state field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			// Act
+			err := compiler.CompileWithOptions(filepath.Join("testdata", tt.fixture), compiler.Options{})
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package duplicatekey
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Signup keeps two fields under the same history key.
+type Signup struct {
+	runtime.ComponentBase
+	Name  string `nojs:"state,history=form"`
+	Email string `nojs:"state,history=form"`
+}
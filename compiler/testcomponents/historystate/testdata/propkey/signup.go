@@ -0,0 +1,9 @@
+package propkey
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Signup marks a prop, which its parent owns, as history state.
+type Signup struct {
+	runtime.ComponentBase
+	Name string `nojs:"history=name"`
+}
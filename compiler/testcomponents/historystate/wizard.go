@@ -0,0 +1,14 @@
+package historystate
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Wizard is a multi-step form whose step and answers are kept in router history state, so
+// a reload or back/forward navigation returns to the same point of the flow. Draft is plain
+// state and is not kept.
+type Wizard struct {
+	runtime.ComponentBase
+	Title   string
+	Step    int               `nojs:"state,history=wizard.step"`
+	Answers map[string]string `nojs:"state,history=wizard.answers"`
+	Draft   string            `nojs:"state"`
+}
@@ -0,0 +1,92 @@
+//go:build !wasm
+// +build !wasm
+
+package loader
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden loader files")
+
+// TestGenerateLoader_Golden compares the generated loader against golden files
+// for the supported configuration variants. Run with -update to refresh them.
+func TestGenerateLoader_Golden(t *testing.T) {
+	withBeacon := compiler.DefaultLoaderConfig()
+	withBeacon.BeaconURL = "https://example.com/boot-failures"
+
+	custom := compiler.DefaultLoaderConfig()
+	custom.WasmURL = "/static/app.wasm"
+	custom.WasmExecURL = "/static/wasm_exec.js"
+	custom.MountSelector = "#root"
+	custom.FallbackHTML = `<p class="notice">Please upgrade your browser.</p><script>alert(1)</script>`
+
+	cases := []struct {
+		name string
+		cfg  compiler.LoaderConfig
+	}{
+		{"default", compiler.DefaultLoaderConfig()},
+		{"beacon", withBeacon},
+		{"custom", custom},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := compiler.GenerateLoader(tc.cfg)
+			if err != nil {
+				t.Fatalf("GenerateLoader failed: %v", err)
+			}
+
+			golden := filepath.Join("testdata", tc.name+".golden.js")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Generated loader does not match %s (run with -update to refresh)", golden)
+			}
+		})
+	}
+}
+
+// TestGenerateLoader_EscapesHTML verifies that configured HTML cannot break out of
+// an inline <script> block.
+func TestGenerateLoader_EscapesHTML(t *testing.T) {
+	cfg := compiler.DefaultLoaderConfig()
+	cfg.FallbackHTML = `</script><script>alert(1)</script>`
+
+	got, err := compiler.GenerateLoader(cfg)
+	if err != nil {
+		t.Fatalf("GenerateLoader failed: %v", err)
+	}
+	if strings.Contains(string(got), "</script>") {
+		t.Error("Expected </script> in configured HTML to be escaped")
+	}
+}
+
+// TestGenerateLoader_RejectsIncompleteConfig verifies required settings are validated.
+func TestGenerateLoader_RejectsIncompleteConfig(t *testing.T) {
+	for _, mutate := range []func(*compiler.LoaderConfig){
+		func(c *compiler.LoaderConfig) { c.WasmURL = "" },
+		func(c *compiler.LoaderConfig) { c.WasmExecURL = " " },
+		func(c *compiler.LoaderConfig) { c.MountSelector = "" },
+	} {
+		cfg := compiler.DefaultLoaderConfig()
+		mutate(&cfg)
+		if _, err := compiler.GenerateLoader(cfg); err == nil {
+			t.Errorf("Expected an error for incomplete config %+v", cfg)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Code generated by the nojs AOT compiler. DO NOT EDIT.
+// nojs bootstrap loader: detects WebAssembly support, shows a fallback when it is
+// missing, and otherwise loads wasm_exec.js and the app module.
+(function () {
+    "use strict";
+
+    var config = {
+        wasmURL: "/static/app.wasm",
+        wasmExecURL: "/static/wasm_exec.js",
+        mountSelector: "#root",
+        loadingHTML: "\u003cdiv class=\"nojs-loading\"\u003eLoading…\u003c/div\u003e",
+        fallbackHTML: "\u003cp class=\"notice\"\u003ePlease upgrade your browser.\u003c/p\u003e\u003cscript\u003ealert(1)\u003c/script\u003e",
+        errorHTML: "\u003cdiv class=\"nojs-error\"\u003eSomething went wrong while starting the application. Please reload the page.\u003c/div\u003e",
+        beaconURL: ""
+    };
+
+    var failed = false;
+
+    function show(html) {
+        var mount = document.querySelector(config.mountSelector);
+        if (mount) {
+            mount.innerHTML = html;
+        }
+    }
+
+    function report(reason, detail) {
+        if (!config.beaconURL) {
+            return;
+        }
+        var payload = JSON.stringify({
+            reason: reason,
+            detail: String(detail || ""),
+            userAgent: navigator.userAgent,
+            url: location.href
+        });
+        try {
+            if (navigator.sendBeacon) {
+                navigator.sendBeacon(config.beaconURL, payload);
+            } else if (typeof fetch === "function") {
+                fetch(config.beaconURL, { method: "POST", body: payload, keepalive: true });
+            }
+        } catch (e) {
+            // Reporting must never break the fallback UI
+        }
+    }
+
+    function fail(reason, detail, html) {
+        if (failed) {
+            return;
+        }
+        failed = true;
+        console.error("[nojs] Boot failed (" + reason + "):", detail);
+        show(html);
+        report(reason, detail);
+    }
+
+    // Returns the reason the browser cannot run the app, or "" if it can.
+    function unsupportedReason() {
+        if (typeof WebAssembly !== "object" || typeof WebAssembly.instantiate !== "function") {
+            return "no-webassembly";
+        }
+        if (typeof fetch !== "function" || typeof Promise !== "function") {
+            return "no-fetch";
+        }
+        return "";
+    }
+
+    function bootError(reason, detail) {
+        var err = new Error(String(detail));
+        err.nojsReason = reason;
+        return err;
+    }
+
+    function download() {
+        return fetch(config.wasmURL).then(function (response) {
+            if (!response.ok) {
+                throw bootError("network", "HTTP " + response.status + " fetching " + config.wasmURL);
+            }
+            return response;
+        }, function (err) {
+            throw bootError("network", err);
+        });
+    }
+
+    function instantiate(response, importObject) {
+        var fromBuffer = function () {
+            return response.arrayBuffer().then(function (bytes) {
+                return WebAssembly.instantiate(bytes, importObject);
+            }, function (err) {
+                throw bootError("network", err);
+            });
+        };
+        if (typeof WebAssembly.instantiateStreaming !== "function") {
+            return fromBuffer();
+        }
+        // Streaming needs the application/wasm MIME type; fall back to buffering otherwise.
+        var copy = response.clone();
+        return WebAssembly.instantiateStreaming(response, importObject).catch(function () {
+            response = copy;
+            return fromBuffer();
+        });
+    }
+
+    function start() {
+        var go = new Go();
+        download().then(function (response) {
+            return instantiate(response, go.importObject);
+        }).then(function (result) {
+            go.run(result.instance);
+            console.log("WebAssembly module loaded.");
+        }).catch(function (err) {
+            fail(err.nojsReason || "instantiate", err, config.errorHTML);
+        });
+    }
+
+    // Called from Go (runtime.ReportBootError) when startup fails after instantiation.
+    window.nojsBootError = function (message) {
+        fail("boot", message, config.errorHTML);
+    };
+
+    function boot() {
+        var reason = unsupportedReason();
+        if (reason) {
+            fail(reason, "", config.fallbackHTML);
+            return;
+        }
+
+        show(config.loadingHTML);
+
+        var script = document.createElement("script");
+        script.src = config.wasmExecURL;
+        script.onload = start;
+        script.onerror = function () {
+            fail("network", "failed to load " + config.wasmExecURL, config.errorHTML);
+        };
+        document.head.appendChild(script);
+    }
+
+    if (document.readyState === "loading") {
+        document.addEventListener("DOMContentLoaded", boot);
+    } else {
+        boot();
+    }
+})();
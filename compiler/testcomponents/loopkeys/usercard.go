@@ -0,0 +1,18 @@
+package loopkeys
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// UserCard renders a user either compactly or in full.
+// Expanded is internal state that must survive re-renders of the same card.
+type UserCard struct {
+	runtime.ComponentBase
+	Name     string
+	Compact  bool
+	Expanded bool `nojs:"state"`
+}
+
+// Toggle flips the card's expanded state.
+func (c *UserCard) Toggle() {
+	c.Expanded = !c.Expanded
+	c.StateHasChanged()
+}
@@ -0,0 +1,16 @@
+package loopkeys
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// User is a list item tracked by ID.
+type User struct {
+	ID   int
+	Name string
+}
+
+// UserList renders two differently-propped UserCard instances per user,
+// which must receive distinct keys within a single loop iteration.
+type UserList struct {
+	runtime.ComponentBase
+	Users []User
+}
@@ -0,0 +1,80 @@
+//go:build !wasm
+// +build !wasm
+
+package loopkeys
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// TestUserList_DuplicateComponentPerIteration_DistinctInstances verifies that two usages of
+// the same component inside one loop iteration get distinct keys, and therefore distinct
+// instances, instead of collapsing into a single cached instance.
+func TestUserList_DuplicateComponentPerIteration_DistinctInstances(t *testing.T) {
+	// Arrange
+	userList := &UserList{Users: []User{{ID: 42, Name: "Ada"}, {ID: 7, Name: "Linus"}}}
+	renderer := rendertest.NewTestRenderer(userList)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert: both variants are rendered for every user
+	if len(vnode.Children) != 4 {
+		t.Fatalf("Expected 4 cards (2 per user), got %d", len(vnode.Children))
+	}
+	assertCard(t, vnode.Children[0], "compact collapsed", "Ada")
+	assertCard(t, vnode.Children[1], "full collapsed", "Ada")
+	assertCard(t, vnode.Children[2], "compact collapsed", "Linus")
+	assertCard(t, vnode.Children[3], "full collapsed", "Linus")
+
+	// Assert: keys are disambiguated by occurrence index and map to different instances
+	compact, _ := renderer.GetChild("UserCard_42_0").(*UserCard)
+	full, _ := renderer.GetChild("UserCard_42_1").(*UserCard)
+	if compact == nil || full == nil {
+		t.Fatal("Expected cached instances under keys UserCard_42_0 and UserCard_42_1")
+	}
+	if compact == full {
+		t.Fatal("Expected distinct instances for the two cards of one iteration")
+	}
+	if !compact.Compact || full.Compact {
+		t.Errorf("Expected instance props to be kept apart, got compact=%v full=%v", compact.Compact, full.Compact)
+	}
+}
+
+// TestUserList_DuplicateComponentPerIteration_PatchesIndependently verifies that state and
+// prop changes reach each instance independently across re-renders of the same data.
+func TestUserList_DuplicateComponentPerIteration_PatchesIndependently(t *testing.T) {
+	// Arrange
+	userList := &UserList{Users: []User{{ID: 42, Name: "Ada"}}}
+	renderer := rendertest.NewTestRenderer(userList)
+	renderer.RenderRoot()
+	compact := renderer.GetChild("UserCard_42_0").(*UserCard)
+
+	// Act: change state of one card, then change the item itself
+	compact.Expanded = true
+	userList.Users[0].Name = "Ada Lovelace"
+	userList.StateHasChanged()
+	vnode := renderer.GetCurrentVDOM()
+
+	// Assert: only the compact card is expanded, both show the new name
+	assertCard(t, vnode.Children[0], "compact expanded", "Ada Lovelace")
+	assertCard(t, vnode.Children[1], "full collapsed", "Ada Lovelace")
+
+	// Assert: the same instances were reused (keys are stable across re-renders)
+	if renderer.GetChild("UserCard_42_0") != compact {
+		t.Error("Expected the compact card instance to be reused after re-render")
+	}
+}
+
+func assertCard(t *testing.T, card *vdom.VNode, wantClass, wantName string) {
+	t.Helper()
+	if card.Attributes["class"] != wantClass {
+		t.Errorf("Expected class %q, got %q", wantClass, card.Attributes["class"])
+	}
+	if len(card.Children) != 1 || card.Children[0].Content != wantName {
+		t.Errorf("Expected a single text child %q, got %+v", wantName, card.Children)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build !wasm
+// +build !wasm
+
+package markupcheck
+
+import (
+	"path/filepath"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs-compiler/compilertest"
+)
+
+// TestStrict_DivInsideParagraph verifies a <div> the parser moves out of a <p> is reported
+// with the paragraph rule.
+func TestStrict_DivInsideParagraph(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("paragraph", compiler.Options{Strict: true})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err, filepath.Join("paragraph", "ParagraphBox.gt.html")+":4",
+		"<div> is moved by the HTML parser", "a <p> cannot contain a <div>")
+}
+
+// TestStrict_UnclosedDiv verifies an element that is never closed is reported at its start tag.
+func TestStrict_UnclosedDiv(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("unclosed", compiler.Options{Strict: true})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err, filepath.Join("unclosed", "UnclosedCard.gt.html")+":1",
+		"<div> is never closed", "closes it at the end of the template")
+}
+
+// TestStrict_CellOutsideTable verifies a <td> outside a table is reported as dropped.
+func TestStrict_CellOutsideTable(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("straycell", compiler.Options{Strict: true})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err, filepath.Join("straycell", "StrayCell.gt.html")+":3",
+		"<td> is dropped by the HTML parser", "only valid inside a <table>")
+}
+
+// TestNonStrict_CompilesRepairedMarkup verifies the check never fails a default build.
+func TestNonStrict_CompilesRepairedMarkup(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("paragraph", compiler.Options{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected the default build to succeed, got %v", err)
+	}
+}
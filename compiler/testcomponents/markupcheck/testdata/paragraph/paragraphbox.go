@@ -0,0 +1,9 @@
+package paragraph
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// ParagraphBox nests a <div> inside a <p>, which the HTML parser splits apart.
+type ParagraphBox struct {
+	runtime.ComponentBase
+	Message string
+}
@@ -0,0 +1,10 @@
+package straycell
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// StrayCell uses a <td> outside any table, which the HTML parser drops.
+type StrayCell struct {
+	runtime.ComponentBase
+	Label string
+	Value string
+}
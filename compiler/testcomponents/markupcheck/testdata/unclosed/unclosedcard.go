@@ -0,0 +1,10 @@
+package unclosed
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// UnclosedCard leaves its outer <div> unclosed.
+type UnclosedCard struct {
+	runtime.ComponentBase
+	Title string
+	Text  string
+}
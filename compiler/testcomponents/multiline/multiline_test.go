@@ -4,7 +4,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/ForgeLogic/nojs-compiler/testcomponents"
+	"github.com/ForgeLogic/nojs/rendertest"
 	"github.com/ForgeLogic/nojs/vdom"
 )
 
@@ -18,7 +18,7 @@ func TestMultiLineBasicRendering(t *testing.T) {
 	}
 
 	// Create a test renderer and perform initial render
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	vnode := renderer.RenderRoot()
 
 	// Verify the root element is a div
@@ -166,7 +166,7 @@ func TestMultiLineDataBinding(t *testing.T) {
 				Count:   tc.count,
 			}
 
-			renderer := testcomponents.NewTestRenderer(comp)
+			renderer := rendertest.NewTestRenderer(comp)
 			vnode := renderer.RenderRoot()
 			tc.checkFn(t, vnode)
 		})
@@ -181,7 +181,7 @@ func TestMultiLineWhitespacePreservation(t *testing.T) {
 		Count:   1,
 	}
 
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	vnode := renderer.RenderRoot()
 
 	// Multi-line h1 should preserve leading/trailing whitespace
@@ -212,35 +212,31 @@ func TestMultiLineWhitespacePreservation(t *testing.T) {
 	}
 }
 
-// TestMultiLineExactWhitespacePattern verifies the exact whitespace pattern in multi-line tags.
+// TestMultiLineExactWhitespacePattern verifies the exact whitespace pattern in multi-line tags,
+// including after a state change re-renders them.
 func TestMultiLineExactWhitespacePattern(t *testing.T) {
+	// Arrange
 	comp := &MultilineText{
-		Title:   "TestTitle",
-		Message: "TestMessage",
-		Count:   123,
-	}
-
-	renderer := testcomponents.NewTestRenderer(comp)
-	vnode := renderer.RenderRoot()
-
-	// Test exact pattern for multi-line h1
-	h1Multi := vnode.Children[1]
-	expectedH1 := "\n        Multi-line: TestTitle\n    "
-	if h1Multi.Content != expectedH1 {
-		t.Errorf("Multi-line h1 has incorrect whitespace pattern.\nExpected: %q\nGot:      %q", expectedH1, h1Multi.Content)
-	}
-
-	// Test exact pattern for multi-line p
-	pMulti := vnode.Children[3]
-	expectedP := "\n        Multi-line paragraph: TestMessage\n    "
-	if pMulti.Content != expectedP {
-		t.Errorf("Multi-line p has incorrect whitespace pattern.\nExpected: %q\nGot:      %q", expectedP, pMulti.Content)
-	}
-
-	// Test exact pattern for multi-line h3
-	h3Multi := vnode.Children[5]
-	expectedH3 := "\n        123\n    "
-	if h3Multi.Content != expectedH3 {
-		t.Errorf("Multi-line h3 has incorrect whitespace pattern.\nExpected: %q\nGot:      %q", expectedH3, h3Multi.Content)
-	}
+		Title:   "Initial",
+		Message: "Initial",
+		Count:   0,
+	}
+	renderer := rendertest.NewTestRenderer(comp)
+	renderer.RenderRoot()
+
+	// Act
+	comp.Title = "TestTitle"
+	comp.Message = "TestMessage"
+	comp.Count = 123
+	comp.StateHasChanged()
+
+	// Assert: multi-line h1, p and h3 are the 2nd, 4th and 6th children
+	want := strings.Join([]string{
+		"\n        Multi-line: TestTitle\n    ",
+		"\n        Multi-line paragraph: TestMessage\n    ",
+		"\n        123\n    ",
+	}, "|")
+	renderer.AssertRendered(t, rendertest.DefaultWaitTimeout, want, func(root *vdom.VNode) string {
+		return strings.Join([]string{root.Children[1].Content, root.Children[3].Content, root.Children[5].Content}, "|")
+	})
 }
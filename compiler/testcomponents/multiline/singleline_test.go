@@ -4,7 +4,7 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/ForgeLogic/nojs-compiler/testcomponents"
+	"github.com/ForgeLogic/nojs/rendertest"
 	"github.com/ForgeLogic/nojs/vdom"
 )
 
@@ -18,7 +18,7 @@ func TestSingleLineBasicRendering(t *testing.T) {
 	}
 
 	// Create a test renderer and perform initial render
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	vnode := renderer.RenderRoot()
 
 	// Verify the root element is a div
@@ -146,7 +146,7 @@ func TestSingleLineDataBinding(t *testing.T) {
 				Count:   tc.count,
 			}
 
-			renderer := testcomponents.NewTestRenderer(comp)
+			renderer := rendertest.NewTestRenderer(comp)
 			vnode := renderer.RenderRoot()
 			tc.checkFn(t, vnode)
 		})
@@ -161,7 +161,7 @@ func TestSingleLineNoExtraWhitespace(t *testing.T) {
 		Count:   1,
 	}
 
-	renderer := testcomponents.NewTestRenderer(comp)
+	renderer := rendertest.NewTestRenderer(comp)
 	vnode := renderer.RenderRoot()
 
 	// Single-line h1 should NOT have leading/trailing whitespace
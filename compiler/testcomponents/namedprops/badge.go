@@ -0,0 +1,24 @@
+package namedprops
+
+import (
+	"github.com/ForgeLogic/nojs-compiler/testcomponents/namedprops/kinds"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Label is a display text.
+type Label string
+
+// Flag is a named bool.
+type Flag bool
+
+// Caption is a named type declared on another named type.
+type Caption Label
+
+// Badge has props of named types defined on built-in types.
+type Badge struct {
+	runtime.ComponentBase
+	Text        Label
+	Count       kinds.Quantity
+	Highlighted Flag
+	Title       Caption
+}
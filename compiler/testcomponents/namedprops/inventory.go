@@ -0,0 +1,10 @@
+package namedprops
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Inventory passes literals, bindings and mixed text to the named-type props of Badge.
+type Inventory struct {
+	runtime.ComponentBase
+	Name    string
+	InStock bool
+}
@@ -0,0 +1,5 @@
+// Package kinds declares a named prop type outside the package of the components using it.
+package kinds
+
+// Quantity is a stock count.
+type Quantity int
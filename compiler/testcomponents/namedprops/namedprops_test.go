@@ -0,0 +1,56 @@
+//go:build !wasm
+// +build !wasm
+
+package namedprops
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// renderBadge renders an Inventory and returns the Badge it passes props to.
+func renderBadge(inventory *Inventory) *Badge {
+	renderer := rendertest.NewTestRenderer(inventory)
+	renderer.RenderRoot()
+	return renderer.GetChild("Badge_0").(*Badge)
+}
+
+// TestInventory_NamedStringPropWithMixedBinding verifies mixed text and bindings convert to a
+// prop of a named string type.
+func TestInventory_NamedStringPropWithMixedBinding(t *testing.T) {
+	// Act
+	badge := renderBadge(&Inventory{Name: "Ada"})
+
+	// Assert
+	if badge.Text != "Hello Ada, welcome" {
+		t.Errorf("expected Text 'Hello Ada, welcome', got %q", badge.Text)
+	}
+	if badge.Title != "Stock" {
+		t.Errorf("expected Title 'Stock' through the chain of named types, got %q", badge.Title)
+	}
+}
+
+// TestInventory_NamedIntPropWithLiteral verifies a literal converts to a named int type
+// declared in another package.
+func TestInventory_NamedIntPropWithLiteral(t *testing.T) {
+	// Act
+	badge := renderBadge(&Inventory{})
+
+	// Assert
+	if badge.Count != 3 {
+		t.Errorf("expected Count 3, got %d", badge.Count)
+	}
+}
+
+// TestInventory_NamedBoolPropWithBinding verifies a {Field} binding converts to a named bool.
+func TestInventory_NamedBoolPropWithBinding(t *testing.T) {
+	// Act
+	inStock := renderBadge(&Inventory{InStock: true})
+	soldOut := renderBadge(&Inventory{InStock: false})
+
+	// Assert
+	if !inStock.Highlighted || soldOut.Highlighted {
+		t.Errorf("expected Highlighted to follow InStock, got %v and %v", inStock.Highlighted, soldOut.Highlighted)
+	}
+}
@@ -0,0 +1,9 @@
+package nestedloops
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// PriceTag renders a price; the catalog renders one per product.
+type PriceTag struct {
+	runtime.ComponentBase
+	Amount float64
+}
@@ -0,0 +1,23 @@
+package nestedloops
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Product is an item of a category, tracked by ID. IDs are only unique within a category.
+type Product struct {
+	ID    int
+	Name  string
+	Price float64
+}
+
+// Category groups the products listed under one heading.
+type Category struct {
+	Name     string
+	Products []Product
+}
+
+// ShopCatalog renders a {@for} over the products of each category inside a {@for} over the
+// categories, binding fields of both loop variables in the inner body.
+type ShopCatalog struct {
+	runtime.ComponentBase
+	Categories []Category
+}
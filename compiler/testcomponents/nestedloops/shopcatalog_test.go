@@ -0,0 +1,128 @@
+//go:build !wasm
+// +build !wasm
+
+package nestedloops
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// newTestCatalog returns a catalog whose two categories both hold a product with ID 1.
+func newTestCatalog() *ShopCatalog {
+	return &ShopCatalog{Categories: []Category{
+		{Name: "Books", Products: []Product{{ID: 1, Name: "Go", Price: 30}, {ID: 2, Name: "Rust", Price: 35.5}}},
+		{Name: "Games", Products: []Product{{ID: 1, Name: "Chess", Price: 12}}},
+	}}
+}
+
+// collectTags returns the elements named tag under root, in document order.
+func collectTags(root *vdom.VNode, tag string) []*vdom.VNode {
+	var found []*vdom.VNode
+	if root.Tag == tag {
+		found = append(found, root)
+	}
+	for _, child := range root.Children {
+		found = append(found, collectTags(child, tag)...)
+	}
+	return found
+}
+
+// TestShopCatalog_NestedLoops_BindOuterAndInnerVariables verifies that the body of the inner
+// loop can bind fields of both the outer and the inner loop variable, and its own index.
+func TestShopCatalog_NestedLoops_BindOuterAndInnerVariables(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(newTestCatalog())
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert: one heading per category
+	headings := collectTags(vnode, "h2")
+	if len(headings) != 2 || headings[0].Content != "Books" || headings[1].Content != "Games" {
+		t.Fatalf("Expected headings Books and Games, got:\n%s", rendertest.FormatVNode(vnode))
+	}
+
+	// Assert: one line per product, naming its category and its index within it
+	want := []string{"Books #0: Go", "Books #1: Rust", "Games #0: Chess"}
+	var lines []*vdom.VNode
+	for _, span := range collectTags(vnode, "span") {
+		if span.Attributes["class"] == nil {
+			lines = append(lines, span)
+		}
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d product lines, got:\n%s", len(want), rendertest.FormatVNode(vnode))
+	}
+	for i, line := range lines {
+		if got := line.Children[0].Content; got != want[i] {
+			t.Errorf("Line %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+}
+
+// TestShopCatalog_NestedLoops_KeysIncludeOuterTrackBy verifies that components in the inner
+// loop are keyed by the trackBy values of both loops, so products sharing an ID in
+// different categories get distinct instances.
+func TestShopCatalog_NestedLoops_KeysIncludeOuterTrackBy(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(newTestCatalog())
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert: every product shows its own price
+	want := []string{"30.00", "35.50", "12.00"}
+	var prices []*vdom.VNode
+	for _, span := range collectTags(vnode, "span") {
+		if span.Attributes["class"] == "price" {
+			prices = append(prices, span)
+		}
+	}
+	if len(prices) != len(want) {
+		t.Fatalf("Expected %d prices, got:\n%s", len(want), rendertest.FormatVNode(vnode))
+	}
+	for i, price := range prices {
+		if got := price.Children[0].Content; got != want[i] {
+			t.Errorf("Price %d: expected %q, got %q", i, want[i], got)
+		}
+	}
+
+	// Assert: the two products with ID 1 are distinct instances
+	book, _ := renderer.GetChild("PriceTag_Books_1_0").(*PriceTag)
+	game, _ := renderer.GetChild("PriceTag_Games_1_0").(*PriceTag)
+	if book == nil || game == nil {
+		t.Fatal("Expected cached instances under keys PriceTag_Books_1_0 and PriceTag_Games_1_0")
+	}
+	if book == game || book.Amount != 30 || game.Amount != 12 {
+		t.Errorf("Expected separate instances with amounts 30 and 12, got %v and %v", book.Amount, game.Amount)
+	}
+}
+
+// TestShopCatalog_NestedLoops_ReRenderFollowsInnerSlice verifies that changes to the slice of
+// one outer item are rendered on the next render.
+func TestShopCatalog_NestedLoops_ReRenderFollowsInnerSlice(t *testing.T) {
+	// Arrange
+	catalog := newTestCatalog()
+	renderer := rendertest.NewTestRenderer(catalog)
+	renderer.RenderRoot()
+
+	// Act
+	catalog.Categories[1].Products = append(catalog.Categories[1].Products, Product{ID: 2, Name: "Go board", Price: 40})
+	catalog.Categories[0].Products = catalog.Categories[0].Products[1:]
+	catalog.StateHasChanged()
+
+	// Assert
+	items := collectTags(renderer.GetCurrentVDOM(), "li")
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 products after the update, got:\n%s", rendertest.FormatVNode(renderer.GetCurrentVDOM()))
+	}
+	if got := items[0].Children[0].Children[0].Content; got != "Books #0: Rust" {
+		t.Errorf("Expected the first line to be Books #0: Rust, got %q", got)
+	}
+	if got := items[2].Children[0].Children[0].Content; got != "Games #1: Go board" {
+		t.Errorf("Expected the last line to be Games #1: Go board, got %q", got)
+	}
+}
@@ -0,0 +1,116 @@
+//go:build !wasm
+// +build !wasm
+
+package nilpointers
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestProfileCard_NilUser_RendersZeroValues verifies that bindings through a nil pointer
+// render empty (or zero) values instead of panicking.
+func TestProfileCard_NilUser_RendersZeroValues(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&ProfileCard{})
+
+	// Act
+	card := renderer.RenderRoot()
+
+	// Assert
+	heading := card.Children[0]
+	if got := rendertest.TextOf(heading); got != "" {
+		t.Errorf("Expected empty heading, got %q", got)
+	}
+	if got := heading.Attributes["title"]; got != "" {
+		t.Errorf("Expected empty title attribute, got %v", got)
+	}
+	if got := heading.Attributes["data-age"]; got != 0 {
+		t.Errorf("Expected zero data-age attribute, got %v", got)
+	}
+	if got := rendertest.TextOf(card.Children[1]); got != "City: " {
+		t.Errorf("Expected 'City: ', got %q", got)
+	}
+	if got := card.Children[2].Attributes["href"]; got != "mailto:?subject=" {
+		t.Errorf("Expected empty mailto href, got %v", got)
+	}
+	if got := rendertest.TextOf(card.Children[3]); got != "Guest" {
+		t.Errorf("Expected {@if User} to fall through to 'Guest', got %q", got)
+	}
+	if got := len(card.Children[4].Children); got != 0 {
+		t.Errorf("Expected no orders when User is nil, got %d", got)
+	}
+}
+
+// TestProfileCard_NonNilUser_RendersFields verifies the guarded bindings render the actual
+// values once the pointers are set, including a nil pointer further down the path.
+func TestProfileCard_NonNilUser_RendersFields(t *testing.T) {
+	// Arrange
+	card := &ProfileCard{User: &User{
+		Name:   "Ada",
+		Email:  "ada@example.com",
+		Age:    36,
+		Orders: []Order{{ID: 1, Title: "Notebook"}, {ID: 2, Title: "Pen"}},
+	}}
+	renderer := rendertest.NewTestRenderer(card)
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	heading := root.Children[0]
+	if got := rendertest.TextOf(heading); got != "Ada" {
+		t.Errorf("Expected heading 'Ada', got %q", got)
+	}
+	if got := heading.Attributes["title"]; got != "ada@example.com" {
+		t.Errorf("Expected title 'ada@example.com', got %v", got)
+	}
+	if got := heading.Attributes["data-age"]; got != 36 {
+		t.Errorf("Expected data-age 36, got %v", got)
+	}
+	// Address is still nil
+	if got := rendertest.TextOf(root.Children[1]); got != "City: " {
+		t.Errorf("Expected 'City: ' while Address is nil, got %q", got)
+	}
+	if got := root.Children[2].Attributes["href"]; got != "mailto:ada@example.com?subject=Ada" {
+		t.Errorf("Unexpected href %v", got)
+	}
+	if got := rendertest.TextOf(root.Children[3]); got != "Signed in" {
+		t.Errorf("Expected 'Signed in', got %q", got)
+	}
+	orders := root.Children[4].Children
+	if len(orders) != 2 || rendertest.TextOf(orders[1]) != "Pen" {
+		t.Errorf("Expected two orders ending with 'Pen', got %d", len(orders))
+	}
+
+	// Act: fill in the nested pointer and re-render
+	card.User.Address = &Address{City: "London"}
+	renderer.ReRender()
+
+	// Assert
+	if got := rendertest.TextOf(renderer.GetCurrentVDOM().Children[1]); got != "City: London" {
+		t.Errorf("Expected 'City: London', got %q", got)
+	}
+}
+
+// TestProfileCard_NilPointerSliceElement_IsSkipped verifies that nil elements of a pointer
+// slice are skipped instead of panicking.
+func TestProfileCard_NilPointerSliceElement_IsSkipped(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&ProfileCard{
+		Pinned: []*Order{{ID: 7, Title: "Pinned"}, nil},
+	})
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	pins := root.Children[5].Children
+	if len(pins) != 1 {
+		t.Fatalf("Expected 1 pinned item, got %d", len(pins))
+	}
+	if got := rendertest.TextOf(pins[0]); got != "Pinned" {
+		t.Errorf("Expected 'Pinned', got %q", got)
+	}
+}
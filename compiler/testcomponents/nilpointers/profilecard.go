@@ -0,0 +1,31 @@
+package nilpointers
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Address is reached from User through a second pointer.
+type Address struct {
+	City string
+}
+
+// Order is an entry of User.Orders, tracked by ID.
+type Order struct {
+	ID    int
+	Title string
+}
+
+// User is the pointer prop rendered by ProfileCard.
+type User struct {
+	Name    string
+	Email   string
+	Age     int
+	Address *Address
+	Orders  []Order
+}
+
+// ProfileCard binds fields through pointer props that may be nil: in text, in attribute
+// values, as a {@for} range, as an {@if} guard, and through pointer slice elements.
+type ProfileCard struct {
+	runtime.ComponentBase
+	User   *User
+	Pinned []*Order
+}
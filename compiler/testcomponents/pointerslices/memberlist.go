@@ -0,0 +1,17 @@
+package pointerslices
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Member is a list entry held by pointer and tracked by ID.
+type Member struct {
+	ID   int
+	Name string
+	Role string
+}
+
+// MemberList ranges over a slice of pointers that may contain nil entries, binding element
+// fields in text and attributes and passing each element to a child component.
+type MemberList struct {
+	runtime.ComponentBase
+	Members []*Member
+}
@@ -0,0 +1,88 @@
+//go:build !wasm
+// +build !wasm
+
+package pointerslices
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestMemberList_NilElementMidSlice_IsSkipped verifies a nil element is skipped without
+// shifting the indexes of the elements after it.
+func TestMemberList_NilElementMidSlice_IsSkipped(t *testing.T) {
+	// Arrange
+	list := &MemberList{Members: []*Member{
+		{ID: 1, Name: "Ann", Role: "Admin"},
+		nil,
+		{ID: 3, Name: "Cy", Role: "Dev"},
+	}}
+	renderer := rendertest.NewTestRenderer(list)
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	items := root.Children[0].Children
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	for i, want := range []string{"0: Ann (Admin)", "2: Cy (Dev)"} {
+		if got := rendertest.TextOf(items[i]); got != want {
+			t.Errorf("item %d: expected %q, got %q", i, want, got)
+		}
+	}
+	if rows := root.Children[1].Children; len(rows) != 2 {
+		t.Errorf("expected 2 child rows, got %d", len(rows))
+	}
+}
+
+// TestMemberList_PassesPointerElementToChild verifies the child component receives the
+// element pointer itself, keyed by the trackBy field of the pointed-to struct.
+func TestMemberList_PassesPointerElementToChild(t *testing.T) {
+	// Arrange
+	ann := &Member{ID: 1, Name: "Ann", Role: "Admin"}
+	renderer := rendertest.NewTestRenderer(&MemberList{Members: []*Member{ann}})
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	row, ok := renderer.GetChild("MemberRow_1_0").(*MemberRow)
+	if !ok {
+		t.Fatal("expected a MemberRow keyed by the member's ID")
+	}
+	if row.Member != ann {
+		t.Error("expected the child to receive the element pointer")
+	}
+	if got := rendertest.TextOf(root.Children[1].Children[0]); got != "Ann (Admin)" {
+		t.Errorf("expected the child to render the member, got %q", got)
+	}
+}
+
+// TestMemberList_TrackByPointerField_ReusesInstances verifies reordering pointer elements
+// keeps each child instance attached to its member.
+func TestMemberList_TrackByPointerField_ReusesInstances(t *testing.T) {
+	// Arrange
+	ann := &Member{ID: 1, Name: "Ann"}
+	cy := &Member{ID: 3, Name: "Cy"}
+	list := &MemberList{Members: []*Member{ann, cy}}
+	renderer := rendertest.NewTestRenderer(list)
+	renderer.RenderRoot()
+	annRow := renderer.GetChild("MemberRow_1_0")
+
+	// Act
+	list.Members = []*Member{cy, nil, ann}
+	list.StateHasChanged()
+	root := renderer.WaitForRender(t)
+
+	// Assert
+	if renderer.GetChild("MemberRow_1_0") != annRow {
+		t.Error("expected Ann's row instance to be reused after reordering")
+	}
+	rows := root.Children[1].Children
+	if len(rows) != 2 || rendertest.TextOf(rows[0]) != "Cy ()" || rendertest.TextOf(rows[1]) != "Ann ()" {
+		t.Errorf("expected rows Cy, Ann; got %d rows", len(rows))
+	}
+}
@@ -0,0 +1,9 @@
+package pointerslices
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// MemberRow receives a loop element of MemberList by pointer.
+type MemberRow struct {
+	runtime.ComponentBase
+	Member *Member
+}
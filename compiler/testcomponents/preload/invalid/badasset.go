@@ -0,0 +1,10 @@
+package invalid
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// BadAsset declares an asset type browsers cannot preload.
+//
+//nojs:preload video /media/intro.mp4
+type BadAsset struct {
+	runtime.ComponentBase
+}
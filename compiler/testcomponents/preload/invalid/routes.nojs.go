@@ -0,0 +1,8 @@
+//nojs:routes
+
+package invalid
+
+// Intro renders BadAsset.
+//
+//nojs:chain BadAsset
+const Intro = "/intro"
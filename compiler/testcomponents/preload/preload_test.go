@@ -0,0 +1,133 @@
+//go:build !wasm
+// +build !wasm
+
+package preload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+)
+
+// hrefs returns the hrefs of assets in order.
+func hrefs(assets []compiler.PreloadAsset) []string {
+	out := make([]string, len(assets))
+	for i, asset := range assets {
+		out[i] = asset.Href
+	}
+	return out
+}
+
+// routeByName returns the route named name, failing the test if it is missing.
+func routeByName(t *testing.T, routes []compiler.RoutePreloads, name string) compiler.RoutePreloads {
+	t.Helper()
+	for _, route := range routes {
+		if route.Name == name {
+			return route
+		}
+	}
+	t.Fatalf("route %s not found in %v", name, routes)
+	return compiler.RoutePreloads{}
+}
+
+// TestCollectPreloads_AggregatesThroughSharedLayout verifies that each route gets its own
+// assets plus the shared layout's (including components the layout uses), deduplicated and
+// with fingerprinted copies resolved.
+func TestCollectPreloads_AggregatesThroughSharedLayout(t *testing.T) {
+	// Arrange
+	srcDir := "site"
+	assetsDir := filepath.Join("site", "assets")
+
+	// Act
+	routes, err := compiler.CollectPreloads(srcDir, assetsDir)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("CollectPreloads failed: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes with a chain (Contact has none), got %d", len(routes))
+	}
+
+	home := routeByName(t, routes, "Home")
+	want := "/fonts/inter.woff2 /img/logo.svg /img/hero.3f9a1c2b.jpg"
+	if got := strings.Join(hrefs(home.Assets), " "); got != want {
+		t.Errorf("Home: expected %s, got %s", want, got)
+	}
+
+	about := routeByName(t, routes, "About")
+	want = "/fonts/inter.woff2 /img/logo.svg /img/team.jpg https://cdn.example.com/team-2x.jpg"
+	if got := strings.Join(hrefs(about.Assets), " "); got != want {
+		t.Errorf("About: expected %s (layout font once), got %s", want, got)
+	}
+	if about.Pattern != "/about" || about.Assets[0].As != "font" {
+		t.Errorf("expected /about with a font first, got %s with %v", about.Pattern, about.Assets[0])
+	}
+}
+
+// TestCollectPreloads_MissingAssetIsAnError verifies that assets absent from the assets
+// directory fail the build.
+func TestCollectPreloads_MissingAssetIsAnError(t *testing.T) {
+	// Arrange
+	emptyAssets := t.TempDir()
+
+	// Act
+	_, err := compiler.CollectPreloads("site", emptyAssets)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "not found in") {
+		t.Fatalf("expected a missing asset error, got %v", err)
+	}
+}
+
+// TestCollectPreloads_UnknownTypeIsAnError verifies that an asset type browsers cannot
+// preload fails the build with the declaration's position.
+func TestCollectPreloads_UnknownTypeIsAnError(t *testing.T) {
+	// Act
+	_, err := compiler.CollectPreloads("invalid", "")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "unknown preload asset type 'video'") || !strings.Contains(err.Error(), "badasset.go") {
+		t.Fatalf("expected an unknown asset type error, got %v", err)
+	}
+}
+
+// TestWritePreloads_EmitsRouteShells verifies the per-route shells and the manifest.
+func TestWritePreloads_EmitsRouteShells(t *testing.T) {
+	// Arrange
+	out := t.TempDir()
+	cfg := compiler.PreloadConfig{
+		AssetsDir:    filepath.Join("site", "assets"),
+		ManifestPath: filepath.Join(out, "preload.json"),
+		ShellPath:    filepath.Join("site", "shell.html"),
+		ShellOutDir:  filepath.Join(out, "shells"),
+	}
+
+	// Act
+	err := compiler.WritePreloads("site", cfg)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("WritePreloads failed: %v", err)
+	}
+	shell, err := os.ReadFile(filepath.Join(out, "shells", "Home.html"))
+	if err != nil {
+		t.Fatalf("expected Home.html shell: %v", err)
+	}
+	html := string(shell)
+	if !strings.Contains(html, `<link rel="preload" href="/fonts/inter.woff2" as="font" crossorigin>`) {
+		t.Errorf("expected a crossorigin font preload, got:\n%s", html)
+	}
+	if strings.Count(html, `href="/img/logo.svg"`) != 1 {
+		t.Errorf("expected the shell's own logo preload not to be duplicated, got:\n%s", html)
+	}
+	if strings.Index(html, "/img/hero.3f9a1c2b.jpg") > strings.Index(html, "</head>") {
+		t.Errorf("expected preload links inside <head>, got:\n%s", html)
+	}
+	if _, err := os.Stat(cfg.ManifestPath); err != nil {
+		t.Errorf("expected a manifest at %s: %v", cfg.ManifestPath, err)
+	}
+}
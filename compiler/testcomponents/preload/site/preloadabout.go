@@ -0,0 +1,10 @@
+package site
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// PreloadAbout repeats the layout's font, which must be preloaded only once.
+//
+//nojs:preload font /fonts/inter.woff2
+type PreloadAbout struct {
+	runtime.ComponentBase
+}
@@ -0,0 +1,10 @@
+package site
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// PreloadHome declares a hero image the build fingerprints (hero.3f9a1c2b.jpg).
+//
+//nojs:preload image /img/hero.jpg
+type PreloadHome struct {
+	runtime.ComponentBase
+}
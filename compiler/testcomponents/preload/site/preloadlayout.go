@@ -0,0 +1,14 @@
+package site
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// PreloadLayout is the layout shared by every route; its font is preloaded for all of them.
+//
+//nojs:preload font /fonts/inter.woff2
+type PreloadLayout struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+}
@@ -0,0 +1,9 @@
+package site
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// PreloadNav is used by PreloadLayout and declares its asset with a marker field.
+type PreloadNav struct {
+	runtime.ComponentBase
+	_ struct{} `nojs:"preload" as:"image" href:"/img/logo.svg"`
+}
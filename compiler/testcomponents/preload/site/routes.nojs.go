@@ -0,0 +1,17 @@
+//nojs:routes
+
+package site
+
+// Route patterns whose component chains declare preloaded assets.
+const (
+	// Home is the landing page.
+	//nojs:chain PreloadLayout PreloadHome
+	Home = "/"
+
+	// About introduces the team.
+	//nojs:chain PreloadLayout PreloadAbout
+	About = "/about"
+
+	// Contact names no chain, so it has no preloads.
+	Contact = "/contact"
+)
@@ -0,0 +1,10 @@
+package site
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// TeamGrid is only reachable through PreloadAbout's template.
+//
+//nojs:preload image /img/team.jpg https://cdn.example.com/team-2x.jpg
+type TeamGrid struct {
+	runtime.ComponentBase
+}
@@ -0,0 +1,58 @@
+//go:build !wasm
+// +build !wasm
+
+package propnames
+
+import (
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs-compiler/compilertest"
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestToolbar_EachUsageGetsItsOwnProps verifies two usages of the same component receive the
+// values written on their own tags.
+func TestToolbar_EachUsageGetsItsOwnProps(t *testing.T) {
+	// Act
+	root := rendertest.NewTestRenderer(&Toolbar{}).RenderRoot()
+
+	// Assert
+	if len(root.Children) != 2 {
+		t.Fatalf("expected two tools, got:\n%s", rendertest.FormatVNode(root))
+	}
+	for i, want := range [][2]string{{"Save", "Ctrl+S"}, {"Open", "Ctrl+O"}} {
+		tool := root.Children[i]
+		if len(tool.Children) != 1 || tool.Children[0].Content != want[0] || tool.Attributes["title"] != want[1] {
+			t.Errorf("expected tool %d to be %s (%s), got:\n%s", i, want[0], want[1], rendertest.FormatVNode(tool))
+		}
+	}
+}
+
+// TestPropNames_SVGRecasedAttribute verifies a prop the parser re-cases inside <svg> is
+// rejected instead of silently keeping its zero value.
+func TestPropNames_SVGRecasedAttribute(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("svgprop", compiler.Options{})
+
+	// Assert
+	compilertest.AssertErrorContains(t, err,
+		"Chart.gt.html:2: attribute 'ViewBox' on <Axis> never reaches the component: the HTML parser read it as 'viewBox'",
+		"Rename the prop (e.g. 'ViewBoxValue')")
+}
+
+// TestPropNames_ReservedAttribute verifies a prop named after a global HTML attribute is only
+// a warning, unless the compilation is strict.
+func TestPropNames_ReservedAttribute(t *testing.T) {
+	// Act
+	lenient := compilertest.CompileFixture("slotprop", compiler.Options{})
+	strict := compilertest.CompileFixture("slotprop", compiler.Options{Strict: true})
+
+	// Assert
+	if lenient != nil {
+		t.Errorf("expected a warning only, got: %v", lenient)
+	}
+	compilertest.AssertErrorContains(t, strict,
+		"prop 'Slot' of component 'Tab' is named after the HTML attribute 'slot'",
+		"e.g. to 'SlotName'")
+}
@@ -0,0 +1,10 @@
+package slotprop
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Tab names a prop after the global slot attribute.
+type Tab struct {
+	runtime.ComponentBase
+	Title string
+	Slot  string
+}
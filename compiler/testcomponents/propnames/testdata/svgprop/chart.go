@@ -0,0 +1,15 @@
+package svgprop
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Axis has a prop the HTML parser re-cases when the component is used inside <svg>.
+type Axis struct {
+	runtime.ComponentBase
+	ViewBox string
+	Label   string
+}
+
+// Chart uses Axis inside <svg>.
+type Chart struct {
+	runtime.ComponentBase
+}
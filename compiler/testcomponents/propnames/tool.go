@@ -0,0 +1,15 @@
+package propnames
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Tool is a toolbar button.
+type Tool struct {
+	runtime.ComponentBase
+	Label    string
+	Shortcut string
+}
+
+// Toolbar uses Tool twice, with different values for the same props.
+type Toolbar struct {
+	runtime.ComponentBase
+}
@@ -0,0 +1,55 @@
+//go:build !wasm
+// +build !wasm
+
+package rawtext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestStyledCard_StyleBlock_PassedThroughVerbatim verifies that CSS braces inside an
+// inline <style> block are not treated as bindings and reach the style VNode unchanged.
+func TestStyledCard_StyleBlock_PassedThroughVerbatim(t *testing.T) {
+	// Arrange
+	card := &StyledCard{Title: "Hello", Body: "World"}
+	renderer := rendertest.NewTestRenderer(card)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert
+	style := vnode.Children[0]
+	if style.Tag != "style" {
+		t.Fatalf("Expected first child to be <style>, got <%s>", style.Tag)
+	}
+	if !strings.Contains(style.Content, ".grid { grid-template: auto / 1fr 1fr; }") ||
+		!strings.Contains(style.Content, ".card h1 {color: red}") {
+		t.Errorf("Expected raw CSS as style content, got %q", style.Content)
+	}
+}
+
+// TestStyledCard_Comment_IgnoredAndBindingsAfterWork verifies that directives and bindings
+// inside an HTML comment are ignored and that real bindings after the style block and
+// the comment are still rendered.
+func TestStyledCard_Comment_IgnoredAndBindingsAfterWork(t *testing.T) {
+	// Arrange
+	card := &StyledCard{Title: "Hello", Body: "World"}
+	renderer := rendertest.NewTestRenderer(card)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert: style, h1 and p only; the comment renders nothing
+	if len(vnode.Children) != 3 {
+		t.Fatalf("Expected 3 children (style, h1, p), got %d", len(vnode.Children))
+	}
+	if h1 := vnode.Children[1]; h1.Tag != "h1" || h1.Content != "Hello" {
+		t.Errorf("Expected <h1>Hello</h1>, got <%s>%s", h1.Tag, h1.Content)
+	}
+	if p := vnode.Children[2]; p.Tag != "p" || p.Content != "World" {
+		t.Errorf("Expected <p>World</p>, got <%s>%s", p.Tag, p.Content)
+	}
+}
@@ -0,0 +1,11 @@
+package rawtext
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// StyledCard has an inline <style> block and an HTML comment containing
+// binding-like and directive-like text, each followed by a real binding.
+type StyledCard struct {
+	runtime.ComponentBase
+	Title string
+	Body  string
+}
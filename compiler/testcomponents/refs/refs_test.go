@@ -0,0 +1,88 @@
+//go:build !wasm
+// +build !wasm
+
+package refs
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestSearchBox_ElementRef_BoundToComponentField verifies that ref="Input" wraps the
+// element's VNode with a pointer to the component's vdom.ElementRef field.
+func TestSearchBox_ElementRef_BoundToComponentField(t *testing.T) {
+	// Arrange
+	searchBox := &SearchBox{Query: "go"}
+	renderer := rendertest.NewTestRenderer(searchBox)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert
+	input := vnode.Children[0]
+	if input.Tag != "input" {
+		t.Fatalf("Expected first child to be <input>, got <%s>", input.Tag)
+	}
+	if input.Ref != &searchBox.Input {
+		t.Fatal("Expected <input> VNode to reference the component's Input field")
+	}
+	if _, exists := input.Attributes["ref"]; exists {
+		t.Error("Expected ref attribute to be stripped from the rendered attributes")
+	}
+	// Without a DOM nothing attaches the ref
+	if searchBox.Input.IsSet() {
+		t.Error("Expected ref to be unset outside the browser")
+	}
+}
+
+// TestSearchBox_ElementRefs_KeyedByTrackBy verifies that refs inside a loop are keyed by
+// the trackBy value, so each item keeps its ref when the list is reordered.
+func TestSearchBox_ElementRefs_KeyedByTrackBy(t *testing.T) {
+	// Arrange
+	searchBox := &SearchBox{Results: []Result{{ID: 1, Title: "One"}, {ID: 2, Title: "Two"}}}
+	renderer := rendertest.NewTestRenderer(searchBox)
+	vnode := renderer.RenderRoot()
+	first := vnode.Children[1].Children[0].Ref
+	second := vnode.Children[1].Children[1].Ref
+
+	// Act: reorder the items
+	searchBox.Results = []Result{searchBox.Results[1], searchBox.Results[0]}
+	searchBox.StateHasChanged()
+	vnode = renderer.GetCurrentVDOM()
+
+	// Assert
+	if first == nil || second == nil || first == second {
+		t.Fatal("Expected a distinct ref per loop item")
+	}
+	if vnode.Children[1].Children[0].Ref != second || vnode.Children[1].Children[1].Ref != first {
+		t.Error("Expected refs to follow their items after reordering")
+	}
+	if searchBox.ResultRefs.For(2) != second {
+		t.Error("Expected ResultRefs.For(2) to return the ref of item 2")
+	}
+	if _, ok := searchBox.ResultRefs.Get(1); ok {
+		t.Error("Expected Get to report unset refs as missing outside the browser")
+	}
+}
+
+// TestSearchBox_ApplyProps_DoesNotCopyRefs verifies that refs are never treated as props,
+// so a parent re-render cannot overwrite the element a child holds.
+func TestSearchBox_ApplyProps_DoesNotCopyRefs(t *testing.T) {
+	// Arrange
+	target := &SearchBox{}
+	targetRef := target.ResultRefs.For(1)
+	source := &SearchBox{Query: "new"}
+	source.ResultRefs.For(1)
+
+	// Act
+	target.ApplyProps(source)
+
+	// Assert
+	if target.Query != "new" {
+		t.Errorf("Expected Query prop to be copied, got %q", target.Query)
+	}
+	if target.ResultRefs.For(1) != targetRef {
+		t.Error("Expected ApplyProps to leave refs untouched")
+	}
+}
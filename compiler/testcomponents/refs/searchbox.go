@@ -0,0 +1,21 @@
+package refs
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// Result is a search result tracked by ID.
+type Result struct {
+	ID    int
+	Title string
+}
+
+// SearchBox binds a single element ref and a keyed ref per loop item.
+type SearchBox struct {
+	runtime.ComponentBase
+	Query      string
+	Results    []Result
+	Input      vdom.ElementRef
+	ResultRefs vdom.ElementRefs
+}
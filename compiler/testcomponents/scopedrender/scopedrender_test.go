@@ -0,0 +1,140 @@
+//go:build !wasm
+// +build !wasm
+
+package scopedrender
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// newScoreboard renders a scoreboard of the given players and returns it with its renderer.
+func newScoreboard(players ...string) (*Scoreboard, *rendertest.TestRenderer) {
+	board := &Scoreboard{Title: "Scores", Players: players}
+	renderer := rendertest.NewTestRenderer(board)
+	renderer.RenderRoot()
+	return board, renderer
+}
+
+// The tests read the tree with GetCurrentVDOM, which flushes batched renders, rather than
+// WaitForRender, which also re-renders the whole tree.
+
+// tile returns the tile instance of player.
+func tile(renderer *rendertest.TestRenderer, player string) *ScoreTile {
+	return renderer.GetChild("tile_" + player).(*ScoreTile)
+}
+
+// TestScoreTile_LeafUpdateRendersOnlyTheTile verifies that StateHasChanged on a tile
+// renders that tile alone: the scoreboard and the other tiles do not render.
+func TestScoreTile_LeafUpdateRendersOnlyTheTile(t *testing.T) {
+	// Arrange
+	board, renderer := newScoreboard("ann", "bob", "cat")
+
+	// Act
+	tile(renderer, "bob").AddPoint()
+	root := renderer.GetCurrentVDOM()
+
+	// Assert
+	if board.Renders != 1 {
+		t.Errorf("expected the scoreboard not to render again, got %d renders", board.Renders)
+	}
+	for player, want := range map[string]int{"ann": 1, "bob": 2, "cat": 1} {
+		if got := tile(renderer, player).Renders; got != want {
+			t.Errorf("expected tile %s to render %d times, got %d", player, want, got)
+		}
+	}
+	if got := root.Children[2].Content; got != "bob: 1" {
+		t.Errorf("expected the tile to show its new score with the player its parent gave it, got %q", got)
+	}
+	if got := len(renderer.Renders()); got != 2 {
+		t.Errorf("expected 2 renders, got %d", got)
+	}
+	if got := renderer.Renders()[0].Children[2].Content; got != "bob: 0" {
+		t.Errorf("expected the first render to keep the tile as it was, got %q", got)
+	}
+}
+
+// TestScoreTile_ParentRenderKeepsTileState verifies that a tile rendered on its own keeps
+// its state and takes new props when its parent renders later.
+func TestScoreTile_ParentRenderKeepsTileState(t *testing.T) {
+	// Arrange
+	board, renderer := newScoreboard("ann", "bob")
+	tile(renderer, "ann").AddPoint()
+	renderer.GetCurrentVDOM()
+
+	// Act
+	board.Title = "Final scores"
+	board.StateHasChanged()
+	root := renderer.GetCurrentVDOM()
+
+	// Assert
+	if board.Renders != 2 {
+		t.Errorf("expected the scoreboard to render again, got %d renders", board.Renders)
+	}
+	if root.Children[0].Content != "Final scores" || root.Children[1].Content != "ann: 1" {
+		t.Errorf("expected the new title and the kept score, got:\n%s", rendertest.FormatVNode(root))
+	}
+}
+
+// TestScoreTile_RootTagChangeRendersTree verifies that a tile whose root element changes
+// tag falls back to a render of the whole tree.
+func TestScoreTile_RootTagChangeRendersTree(t *testing.T) {
+	// Arrange
+	board, renderer := newScoreboard("ann", "bob")
+
+	// Act
+	tile(renderer, "bob").Highlight()
+	root := renderer.GetCurrentVDOM()
+
+	// Assert
+	if board.Renders != 2 {
+		t.Errorf("expected the scoreboard to render again, got %d renders", board.Renders)
+	}
+	if got := root.Children[2].Tag; got != "strong" {
+		t.Errorf("expected the tile to be highlighted, got <%s>", got)
+	}
+}
+
+// renderCount returns how many times the scoreboard and its tiles rendered.
+func renderCount(board *Scoreboard, renderer *rendertest.TestRenderer) int {
+	count := board.Renders
+	for _, player := range board.Players {
+		count += tile(renderer, player).Renders
+	}
+	return count
+}
+
+// BenchmarkScoreTile_LeafUpdate compares a tile rendering on its own with the whole
+// scoreboard rendering, for a board of 1000 tiles. The renders/op metric counts the
+// Render calls per update; the time includes the test renderer's bookkeeping.
+func BenchmarkScoreTile_LeafUpdate(b *testing.B) {
+	players := make([]string, 1000)
+	for i := range players {
+		players[i] = fmt.Sprintf("player%d", i)
+	}
+
+	b.Run("scoped", func(b *testing.B) {
+		board, renderer := newScoreboard(players...)
+		leaf := tile(renderer, "player500")
+		before := renderCount(board, renderer)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			leaf.AddPoint()
+			renderer.Flush()
+		}
+		b.ReportMetric(float64(renderCount(board, renderer)-before)/float64(b.N), "renders/op")
+	})
+	b.Run("full", func(b *testing.B) {
+		board, renderer := newScoreboard(players...)
+		leaf := tile(renderer, "player500")
+		before := renderCount(board, renderer)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			leaf.Score++
+			renderer.ReRender()
+		}
+		b.ReportMetric(float64(renderCount(board, renderer)-before)/float64(b.N), "renders/op")
+	})
+}
@@ -0,0 +1,65 @@
+package scopedrender
+
+import (
+	"fmt"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// Scoreboard renders a ScoreTile child per player. Its Render is written by hand, like
+// the tiles', so the tests can count how often each component renders.
+type Scoreboard struct {
+	runtime.ComponentBase
+	Title   string
+	Players []string
+	Renders int
+}
+
+// Render renders the title and one tile per player.
+func (c *Scoreboard) Render(r runtime.Renderer) *vdom.VNode {
+	c.Renders++
+	children := []*vdom.VNode{vdom.NewVNode("h1", nil, nil, c.Title)}
+	for _, player := range c.Players {
+		children = append(children, r.RenderChild("tile_"+player, &ScoreTile{Player: player}))
+	}
+	return vdom.NewVNode("div", map[string]any{"class": "scoreboard"}, children, "")
+}
+
+// ScoreTile shows a player's score, which it keeps as its own state.
+type ScoreTile struct {
+	runtime.ComponentBase
+	Player      string
+	Score       int
+	Highlighted bool
+	Renders     int
+}
+
+// Render renders the player and score, in a <strong> instead of a <div> when highlighted.
+func (c *ScoreTile) Render(r runtime.Renderer) *vdom.VNode {
+	c.Renders++
+	tag := "div"
+	if c.Highlighted {
+		tag = "strong"
+	}
+	return vdom.NewVNode(tag, map[string]any{"class": "tile"}, nil, fmt.Sprintf("%s: %d", c.Player, c.Score))
+}
+
+// ApplyProps copies the props of a freshly built instance onto the cached one.
+func (c *ScoreTile) ApplyProps(source runtime.Component) {
+	if s, ok := source.(*ScoreTile); ok {
+		c.Player = s.Player
+	}
+}
+
+// AddPoint adds a point, which re-renders the tile.
+func (c *ScoreTile) AddPoint() {
+	c.Score++
+	c.StateHasChanged()
+}
+
+// Highlight switches the tile to its highlighted root element.
+func (c *ScoreTile) Highlight() {
+	c.Highlighted = true
+	c.StateHasChanged()
+}
@@ -0,0 +1,19 @@
+package selectbinding
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// LanguagePicker is a select whose options come from a filtered list.
+type LanguagePicker struct {
+	runtime.ComponentBase
+	Language string
+	Visible  []string
+}
+
+// Pick stores the chosen language.
+func (c *LanguagePicker) Pick(e events.ChangeEventArgs) {
+	c.Language = e.Value
+	c.StateHasChanged()
+}
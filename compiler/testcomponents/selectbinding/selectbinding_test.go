@@ -0,0 +1,114 @@
+//go:build !wasm
+// +build !wasm
+
+package selectbinding
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// selection returns the options of the picker's select and the index its value selects.
+func selection(t *testing.T, root *vdom.VNode) ([]string, int) {
+	t.Helper()
+	sel := root.Children[0]
+	index, bound := vdom.SelectedOptionIndex(sel)
+	if !bound {
+		t.Fatalf("expected the select to be bound to Language:\n%s", rendertest.FormatVNode(sel))
+	}
+	var options []string
+	for _, option := range sel.Children {
+		options = append(options, option.Attributes["value"].(string))
+	}
+	return options, index
+}
+
+// TestLanguagePicker_OptionsFromLoop verifies options rendered by a {@for} inside a <select>
+// stay inside it, each with its loop value.
+func TestLanguagePicker_OptionsFromLoop(t *testing.T) {
+	// Arrange
+	picker := &LanguagePicker{Language: "Rust", Visible: []string{"Go", "Rust", "Zig"}}
+
+	// Act
+	options, index := selection(t, rendertest.NewTestRenderer(picker).RenderRoot())
+
+	// Assert
+	if strings.Join(options, ",") != "Go,Rust,Zig" {
+		t.Errorf("expected options Go,Rust,Zig, got %v", options)
+	}
+	if index != 1 {
+		t.Errorf("expected Rust at index 1 to be selected, got %d", index)
+	}
+}
+
+// TestLanguagePicker_FilteringKeepsOrFallsBack verifies filtering the options keeps the bound
+// language selected while it is listed, and selects the fallback once it is filtered out.
+func TestLanguagePicker_FilteringKeepsOrFallsBack(t *testing.T) {
+	// Arrange
+	picker := &LanguagePicker{Language: "Rust", Visible: []string{"Go", "Rust", "Zig"}}
+	renderer := rendertest.NewTestRenderer(picker)
+	renderer.RenderRoot()
+
+	// Act
+	picker.Visible = []string{"Rust", "Zig"}
+	picker.StateHasChanged()
+	_, aboveRemoved := selection(t, renderer.WaitForRender(t))
+	picker.Visible = []string{"Go", "Zig"}
+	picker.StateHasChanged()
+	_, selectedRemoved := selection(t, renderer.WaitForRender(t))
+
+	// Assert
+	if aboveRemoved != 0 {
+		t.Errorf("expected Rust to stay selected at index 0, got %d", aboveRemoved)
+	}
+	if selectedRemoved != 0 {
+		t.Errorf("expected the fallback Go at index 0, got %d", selectedRemoved)
+	}
+}
+
+// TestLanguagePicker_ChangeUpdatesBoundValue verifies a change event drives the selection
+// through the bound value.
+func TestLanguagePicker_ChangeUpdatesBoundValue(t *testing.T) {
+	// Arrange
+	picker := &LanguagePicker{Language: "Rust", Visible: []string{"Go", "Rust", "Zig"}}
+	renderer := rendertest.NewTestRenderer(picker)
+	root := renderer.RenderRoot()
+
+	// Act
+	rendertest.FireEvent(t, root.Children[0], "change", events.ChangeEventArgs{Value: "Zig"})
+	_, index := selection(t, renderer.WaitForRender(t))
+
+	// Assert
+	if index != 2 {
+		t.Errorf("expected Zig at index 2 to be selected, got %d", index)
+	}
+}
+
+// TestCompile_SelectedOnOptionIsDropped verifies an authored selected attribute is not
+// compiled into the option, so it cannot fight the bound value.
+func TestCompile_SelectedOnOptionIsDropped(t *testing.T) {
+	// Arrange
+	dir := filepath.Join("testdata", "authored")
+
+	// Act
+	err := compiler.CompileWithOptions(dir, compiler.Options{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected the template to compile, got %v", err)
+	}
+	generated, err := os.ReadFile(filepath.Join(dir, "SizePicker.generated.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(generated), `"selected"`) {
+		t.Errorf("expected no selected attribute in the generated code:\n%s", generated)
+	}
+}
@@ -0,0 +1,9 @@
+package authored
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// SizePicker marks an option selected in its template, which the compiler drops.
+type SizePicker struct {
+	runtime.ComponentBase
+	Size string
+}
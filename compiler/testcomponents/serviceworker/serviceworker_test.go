@@ -0,0 +1,126 @@
+//go:build !wasm
+// +build !wasm
+
+package serviceworker
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden service worker files")
+
+// TestGenerateServiceWorker_Golden compares the generated service worker against golden
+// files for the supported configuration variants. Run with -update to refresh them.
+func TestGenerateServiceWorker_Golden(t *testing.T) {
+	defaults := compiler.DefaultServiceWorkerConfig()
+	defaults.BuildHash = "3f9a1c2b7d4e"
+
+	assets, err := compiler.ManifestAssets(filepath.Join("testdata", "preload.json"))
+	if err != nil {
+		t.Fatalf("ManifestAssets failed: %v", err)
+	}
+	withAssets := defaults
+	withAssets.WasmURL = "/static/app.wasm"
+	withAssets.WasmExecURL = "/static/wasm_exec.js"
+	withAssets.Shells = []string{"/", "/routes/Jobs.html"}
+	withAssets.Assets = append(assets, "/static/app.wasm") // Listed once
+	withAssets.CachePrefix = "field-app"
+
+	cases := []struct {
+		name string
+		cfg  compiler.ServiceWorkerConfig
+	}{
+		{"default", defaults},
+		{"assets", withAssets},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := compiler.GenerateServiceWorker(tc.cfg)
+			if err != nil {
+				t.Fatalf("GenerateServiceWorker failed: %v", err)
+			}
+
+			golden := filepath.Join("testdata", tc.name+".golden.js")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Generated service worker does not match %s (run with -update to refresh)", golden)
+			}
+		})
+	}
+}
+
+// TestManifestAssets_SameOriginOnce verifies the preload manifest yields each same-origin
+// asset once, sorted.
+func TestManifestAssets_SameOriginOnce(t *testing.T) {
+	got, err := compiler.ManifestAssets(filepath.Join("testdata", "preload.json"))
+	if err != nil {
+		t.Fatalf("ManifestAssets failed: %v", err)
+	}
+	want := []string{"/fonts/inter.woff2", "/img/hero.3f9a1c2b.jpg"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestBuildHash_FollowsContents verifies the build hash changes with the hashed files and
+// only with them, so each build gets caches of its own.
+func TestBuildHash_FollowsContents(t *testing.T) {
+	wasm := filepath.Join(t.TempDir(), "main.wasm")
+	hashOf := func(content string) string {
+		t.Helper()
+		if err := os.WriteFile(wasm, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := compiler.BuildHash(wasm)
+		if err != nil {
+			t.Fatalf("BuildHash failed: %v", err)
+		}
+		return hash
+	}
+
+	first, again, next := hashOf("build 1"), hashOf("build 1"), hashOf("build 2")
+	if first != again {
+		t.Errorf("Expected the same hash for the same build, got %s and %s", first, again)
+	}
+	if first == next {
+		t.Errorf("Expected a new hash for a new build, got %s twice", first)
+	}
+	if len(first) != 12 || strings.Trim(first, "0123456789abcdef") != "" {
+		t.Errorf("Expected 12 hex digits, got %q", first)
+	}
+}
+
+// TestGenerateServiceWorker_RejectsIncompleteConfig verifies required settings are validated.
+func TestGenerateServiceWorker_RejectsIncompleteConfig(t *testing.T) {
+	for _, mutate := range []func(*compiler.ServiceWorkerConfig){
+		func(c *compiler.ServiceWorkerConfig) { c.WasmURL = "" },
+		func(c *compiler.ServiceWorkerConfig) { c.WasmExecURL = " " },
+		func(c *compiler.ServiceWorkerConfig) { c.Shells = nil },
+		func(c *compiler.ServiceWorkerConfig) { c.BuildHash = "" },
+		func(c *compiler.ServiceWorkerConfig) { c.CachePrefix = "" },
+	} {
+		cfg := compiler.DefaultServiceWorkerConfig()
+		cfg.BuildHash = "3f9a1c2b7d4e"
+		mutate(&cfg)
+		if _, err := compiler.GenerateServiceWorker(cfg); err == nil {
+			t.Errorf("Expected an error for incomplete config %+v", cfg)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Code generated by the nojs AOT compiler. DO NOT EDIT.
+// nojs service worker: precaches the app and serves it cache-first, so it starts offline.
+// A new build waits until the page sends SKIP_WAITING (sw.SkipWaiting in Go).
+"use strict";
+
+var CACHE_PREFIX = "nojs" + "-";
+var CACHE_NAME = "nojs-3f9a1c2b7d4e";
+var DATA_CACHE_NAME = CACHE_PREFIX + "data"; // Kept across builds
+var SHELL_URL = "index.html";
+var PRECACHE_URLS = [
+    "main.wasm",
+    "wasm_exec.js",
+    "index.html"
+];
+
+var precached = {};
+PRECACHE_URLS.forEach(function (url) {
+    precached[new URL(url, self.location).href] = true;
+});
+
+self.addEventListener("install", function (event) {
+    event.waitUntil(caches.open(CACHE_NAME).then(function (cache) {
+        // Revalidate with the server: a build must not precache another build's files
+        return cache.addAll(PRECACHE_URLS.map(function (url) {
+            return new Request(url, { cache: "reload" });
+        }));
+    }));
+});
+
+self.addEventListener("activate", function (event) {
+    event.waitUntil(caches.keys().then(function (names) {
+        return Promise.all(names.filter(function (name) {
+            return name.indexOf(CACHE_PREFIX) === 0 && name !== CACHE_NAME && name !== DATA_CACHE_NAME;
+        }).map(function (name) {
+            return caches.delete(name);
+        }));
+    }).then(function () {
+        return self.clients.claim();
+    }));
+});
+
+self.addEventListener("message", function (event) {
+    if (event.data && event.data.type === "SKIP_WAITING") {
+        self.skipWaiting();
+    }
+});
+
+// Precached files: cache first, then the network.
+function fromPrecache(request) {
+    return caches.match(request, { cacheName: CACHE_NAME }).then(function (cached) {
+        return cached || fetch(request);
+    });
+}
+
+// Navigations: the network, then the cached page, then the shell.
+function navigate(request) {
+    return fetch(request).catch(function () {
+        return caches.match(request, { cacheName: CACHE_NAME }).then(function (cached) {
+            return cached || caches.match(SHELL_URL, { cacheName: CACHE_NAME });
+        });
+    });
+}
+
+// Other requests: the network, keeping a copy of each success for offline use. Without a
+// network or a copy the request fails, and the app serves its offline fallback.
+function fromNetwork(request) {
+    return fetch(request).then(function (response) {
+        if (response.ok) {
+            var copy = response.clone();
+            caches.open(DATA_CACHE_NAME).then(function (cache) {
+                cache.put(request, copy);
+            });
+        }
+        return response;
+    }, function (err) {
+        return caches.match(request, { cacheName: DATA_CACHE_NAME }).then(function (cached) {
+            if (cached) {
+                return cached;
+            }
+            throw err;
+        });
+    });
+}
+
+self.addEventListener("fetch", function (event) {
+    var request = event.request;
+    var url = new URL(request.url);
+    if (request.method !== "GET" || url.origin !== self.location.origin) {
+        return;
+    }
+    if (precached[url.href]) {
+        event.respondWith(fromPrecache(request));
+    } else if (request.mode === "navigate") {
+        event.respondWith(navigate(request));
+    } else {
+        event.respondWith(fromNetwork(request));
+    }
+});
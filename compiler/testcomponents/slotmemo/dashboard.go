@@ -0,0 +1,15 @@
+package slotmemo
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Dashboard projects its page body into a SidebarLayout.
+type Dashboard struct {
+	runtime.ComponentBase
+	Title string
+}
+
+// Rename changes the title, which re-renders the page body.
+func (c *Dashboard) Rename(title string) {
+	c.Title = title
+	c.StateHasChanged()
+}
@@ -0,0 +1,19 @@
+package slotmemo
+
+import (
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// SidebarLayout is a layout whose sidebar collapses on its own state, around a content slot.
+type SidebarLayout struct {
+	runtime.ComponentBase
+	Collapsed   bool
+	BodyContent []*vdom.VNode
+}
+
+// Toggle collapses or expands the sidebar.
+func (c *SidebarLayout) Toggle() {
+	c.Collapsed = !c.Collapsed
+	c.StateHasChanged()
+}
@@ -0,0 +1,71 @@
+//go:build !wasm
+// +build !wasm
+
+package slotmemo
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// projected returns the slot content rendered inside the layout's <main>.
+func projected(t *testing.T, layoutRoot *vdom.VNode) []*vdom.VNode {
+	t.Helper()
+	for _, child := range layoutRoot.Children {
+		if child != nil && child.Tag == "main" {
+			return child.Children
+		}
+	}
+	t.Fatalf("no <main> in layout:\n%s", rendertest.FormatVNode(layoutRoot))
+	return nil
+}
+
+// TestSidebarLayout_HostRerender_ReusesSlotNodes verifies a layout re-rendering on its own
+// state projects the very nodes it was given, so the patcher skips the slot subtree.
+func TestSidebarLayout_HostRerender_ReusesSlotNodes(t *testing.T) {
+	// Arrange
+	body := []*vdom.VNode{vdom.NewVNode("section", map[string]any{"class": "body"}, nil, "Page")}
+	layout := &SidebarLayout{BodyContent: body}
+	renderer := rendertest.NewTestRenderer(layout)
+	before := projected(t, renderer.RenderRoot())
+
+	// Act
+	layout.Toggle()
+	root := renderer.WaitForRender(t)
+
+	// Assert
+	after := projected(t, root)
+	if len(after) != 1 || after[0] != body[0] || before[0] != body[0] {
+		t.Errorf("expected the slot node to be projected unchanged, got %p then %p for %p", before[0], after[0], body[0])
+	}
+	if got := root.Children[0].Attributes["class"]; got != "sidebar collapsed" {
+		t.Errorf("expected the host itself to re-render, got sidebar class %q", got)
+	}
+}
+
+// TestSidebarLayout_ParentRerender_ProjectsNewNodes verifies slot content the parent rendered
+// again replaces the old nodes, so the patcher diffs it in full.
+func TestSidebarLayout_ParentRerender_ProjectsNewNodes(t *testing.T) {
+	// Arrange
+	dashboard := &Dashboard{Title: "Inbox"}
+	renderer := rendertest.NewTestRenderer(dashboard)
+	before := projected(t, renderer.RenderRoot())
+
+	// Act
+	dashboard.Rename("Archive")
+	root := renderer.WaitForRender(t)
+
+	// Assert
+	after := projected(t, root)
+	if len(after) != 1 || after[0] == before[0] {
+		t.Fatalf("expected new slot nodes after the parent re-rendered, got %v", after)
+	}
+	if got := after[0].Children[0].Content; got != "Archive" {
+		t.Errorf("expected the new title in the slot, got %q", got)
+	}
+	if renderer.GetChild("SidebarLayout_0").(*SidebarLayout).BodyContent[0] != after[0] {
+		t.Error("expected the cached layout instance to receive the new slot content")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build !wasm
+// +build !wasm
+
+package structcheck
+
+import (
+	"strings"
+	"testing"
+
+	compiler "github.com/ForgeLogic/nojs-compiler"
+	"github.com/ForgeLogic/nojs-compiler/compilertest"
+)
+
+// assertReported fails the test unless err contains every want, in one struct error rather
+// than cascading binding errors.
+func assertReported(t *testing.T, err error, want ...string) {
+	t.Helper()
+	compilertest.AssertErrorContains(t, err, want...)
+	if strings.Contains(err.Error(), "available fields") {
+		t.Errorf("expected one struct error instead of cascading binding errors, got:\n%s", err)
+	}
+}
+
+// TestDiscovery_MissingStruct verifies a template without a struct of its name fails with the
+// files searched and the closest struct name.
+func TestDiscovery_MissingStruct(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("missing", compiler.Options{})
+
+	// Assert
+	assertReported(t, err,
+		"no exported struct 'ProfileCard' found for template",
+		"Searched the js/wasm files of package missing: profilecard.go",
+		"Did you mean: 'ProfileCrad'?")
+}
+
+// TestDiscovery_UnexportedStruct verifies a struct matching the template name only in case is
+// reported as unexported.
+func TestDiscovery_UnexportedStruct(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("unexported", compiler.Options{})
+
+	// Assert
+	assertReported(t, err,
+		"no exported struct 'UserCard' found",
+		"'userCard' in usercard.go is unexported. Rename it to 'UserCard'")
+}
+
+// TestDiscovery_StructExcludedFromWasm verifies a struct declared only in a file that build
+// constraints exclude from js/wasm is reported as such.
+func TestDiscovery_StructExcludedFromWasm(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("excluded", compiler.Options{})
+
+	// Assert
+	assertReported(t, err,
+		"no exported struct 'StatusBadge' found",
+		"Searched the js/wasm files of package excluded: doc.go",
+		"'StatusBadge' is declared in statusbadge.go, which build constraints exclude from the js/wasm build")
+}
+
+// TestDiscovery_StructInAnotherFile verifies the struct is found in any file of the package,
+// not only in the one named after the template.
+func TestDiscovery_StructInAnotherFile(t *testing.T) {
+	// Act
+	err := compilertest.CompileFixture("otherfile", compiler.Options{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("expected InfoPanel to be found in panels.go, got %v", err)
+	}
+}
@@ -0,0 +1,2 @@
+// Package excluded declares its component struct in a file excluded from js/wasm.
+package excluded
@@ -0,0 +1,12 @@
+//go:build !wasm
+// +build !wasm
+
+package excluded
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// StatusBadge is only built natively, so the js/wasm build has no component struct.
+type StatusBadge struct {
+	runtime.ComponentBase
+	Label string
+}
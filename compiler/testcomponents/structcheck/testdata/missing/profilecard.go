@@ -0,0 +1,9 @@
+package missing
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// ProfileCrad misspells the name of its template, ProfileCard.gt.html.
+type ProfileCrad struct {
+	runtime.ComponentBase
+	Name string
+}
@@ -0,0 +1,9 @@
+package otherfile
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// InfoPanel is declared in panels.go rather than infopanel.go.
+type InfoPanel struct {
+	runtime.ComponentBase
+	Title string
+}
@@ -0,0 +1,9 @@
+package unexported
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// userCard is unexported, so UserCard.gt.html has no component struct.
+type userCard struct {
+	runtime.ComponentBase
+	Name string
+}
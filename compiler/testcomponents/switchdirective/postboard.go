@@ -0,0 +1,18 @@
+package switchdirective
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Post is a list item whose Status drives a per-item {@switch}.
+type Post struct {
+	ID     int
+	Title  string
+	Status string
+}
+
+// PostBoard demonstrates a {@case} body containing a {@for} and multiple nodes,
+// and a {@switch} on a loop variable field.
+type PostBoard struct {
+	runtime.ComponentBase
+	View  string
+	Posts []Post
+}
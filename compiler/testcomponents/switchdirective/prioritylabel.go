@@ -0,0 +1,16 @@
+package switchdirective
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// PriorityLabel demonstrates a {@switch} on an int state field without {@default},
+// which renders nothing when no case matches.
+type PriorityLabel struct {
+	runtime.ComponentBase
+	Priority int `nojs:"state"`
+}
+
+// Escalate raises the priority and re-renders.
+func (p *PriorityLabel) Escalate() {
+	p.Priority++
+	p.StateHasChanged()
+}
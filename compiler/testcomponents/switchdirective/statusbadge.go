@@ -0,0 +1,9 @@
+package switchdirective
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// StatusBadge demonstrates a {@switch} on a string prop with a {@default} branch.
+type StatusBadge struct {
+	runtime.ComponentBase
+	Status string
+}
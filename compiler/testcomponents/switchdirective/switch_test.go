@@ -0,0 +1,109 @@
+//go:build !wasm
+// +build !wasm
+
+package switchdirective
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+// TestStatusBadge_StringSwitch verifies that each string case and the default branch
+// render the matching node.
+func TestStatusBadge_StringSwitch(t *testing.T) {
+	cases := []struct {
+		status    string
+		wantClass string
+	}{
+		{"draft", "draft"},
+		{"published", "published"},
+		{"archived", "unknown"}, // Falls through to {@default}
+	}
+
+	for _, tc := range cases {
+		badge := &StatusBadge{Status: tc.status}
+		vnode := rendertest.NewTestRenderer(badge).RenderRoot()
+
+		if len(vnode.Children) != 1 || vnode.Children[0] == nil {
+			t.Fatalf("Status %q: expected a single rendered branch, got %+v", tc.status, vnode.Children)
+		}
+		if got := vnode.Children[0].Attributes["class"]; got != tc.wantClass {
+			t.Errorf("Status %q: expected class %q, got %q", tc.status, tc.wantClass, got)
+		}
+	}
+}
+
+// TestPriorityLabel_IntSwitchWithoutDefault verifies int cases, and that a switch without
+// {@default} renders nil when nothing matches.
+func TestPriorityLabel_IntSwitchWithoutDefault(t *testing.T) {
+	// Arrange: no case matches priority 0
+	label := &PriorityLabel{}
+	renderer := rendertest.NewTestRenderer(label)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert: the switch rendered nothing
+	if len(vnode.Children) != 1 || vnode.Children[0] != nil {
+		t.Fatalf("Expected a single nil child for an unmatched switch, got %+v", vnode.Children)
+	}
+
+	// Act: escalate to 1, then 2
+	for _, want := range []string{"Low", "High"} {
+		label.Escalate()
+		vnode = renderer.GetCurrentVDOM()
+
+		if vnode.Children[0] == nil || rendertest.TextOf(vnode.Children[0]) != want {
+			t.Errorf("Priority %d: expected %q, got %+v", label.Priority, want, vnode.Children[0])
+		}
+	}
+
+	// Act: escalate past the last case
+	label.Escalate()
+	if renderer.GetCurrentVDOM().Children[0] != nil {
+		t.Error("Expected nil after the priority left all cases")
+	}
+}
+
+// TestPostBoard_CaseWithLoop verifies that a case body with several nodes and a {@for}
+// is spread into the parent's children, and that a loop variable field can be switched on.
+func TestPostBoard_CaseWithLoop(t *testing.T) {
+	// Arrange
+	board := &PostBoard{
+		View: "list",
+		Posts: []Post{
+			{ID: 1, Title: "Hello", Status: "published"},
+			{ID: 2, Title: "WIP", Status: "draft"},
+		},
+	}
+	renderer := rendertest.NewTestRenderer(board)
+
+	// Act
+	vnode := renderer.RenderRoot()
+
+	// Assert: h2, p, post 1 (+ nil status), post 2 (+ draft span), footer
+	tags := make([]string, 0, len(vnode.Children))
+	for _, child := range vnode.Children {
+		tags = append(tags, child.Tag)
+	}
+	want := []string{"h2", "p", "div", "div", "span", "footer"}
+	if len(tags) != len(want) {
+		t.Fatalf("Expected children %v, got %v", want, tags)
+	}
+	for i := range want {
+		if tags[i] != want[i] {
+			t.Errorf("Child %d: expected <%s>, got <%s>", i, want[i], tags[i])
+		}
+	}
+
+	// Act: switch to the single-node case
+	board.View = "empty"
+	board.StateHasChanged()
+	vnode = renderer.GetCurrentVDOM()
+
+	// Assert
+	if len(vnode.Children) != 3 || vnode.Children[1].Content != "Nothing to show" {
+		t.Errorf("Expected h2, p(Nothing to show), footer; got %+v", vnode.Children)
+	}
+}
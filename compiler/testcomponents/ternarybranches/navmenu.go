@@ -0,0 +1,22 @@
+package ternarybranches
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// MenuItem is a menu entry tracked by ID.
+type MenuItem struct {
+	ID    int
+	Label string
+	Class string
+}
+
+// NavMenu uses ternaries whose branches are fields rather than string literals,
+// in text and attribute values, inside and outside loops.
+type NavMenu struct {
+	runtime.ComponentBase
+	IsActive      bool
+	ActiveClass   string
+	InactiveClass string
+	ActiveLabel   string
+	Items         []MenuItem
+	Tags          []string
+}
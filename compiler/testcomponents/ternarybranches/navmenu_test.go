@@ -0,0 +1,112 @@
+//go:build !wasm
+// +build !wasm
+
+package ternarybranches
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+)
+
+func newNavMenu(active bool) *NavMenu {
+	return &NavMenu{
+		IsActive:      active,
+		ActiveClass:   "nav-active",
+		InactiveClass: "nav-inactive",
+		ActiveLabel:   "Active",
+		Items:         []MenuItem{{ID: 1, Label: "Home", Class: "home"}},
+		Tags:          []string{"new"},
+	}
+}
+
+// TestNavMenu_FieldBranches_OutsideLoop verifies field and mixed literal/field branches
+// in attribute values and text outside loops.
+func TestNavMenu_FieldBranches_OutsideLoop(t *testing.T) {
+	tests := []struct {
+		name      string
+		active    bool
+		navClass  string
+		spanTitle string
+		spanText  string
+	}{
+		{"active", true, "nav-active", "menu Active", "Active"},
+		{"inactive", false, "nav-inactive", "menu off", "Inactive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			renderer := rendertest.NewTestRenderer(newNavMenu(tt.active))
+
+			// Act
+			nav := renderer.RenderRoot()
+
+			// Assert
+			if got := nav.Attributes["class"]; got != tt.navClass {
+				t.Errorf("Expected nav class %q, got %v", tt.navClass, got)
+			}
+			span := nav.Children[0]
+			if got := span.Attributes["title"]; got != tt.spanTitle {
+				t.Errorf("Expected span title %q, got %v", tt.spanTitle, got)
+			}
+			if got := span.Children[0].Content; got != tt.spanText {
+				t.Errorf("Expected span text %q, got %q", tt.spanText, got)
+			}
+		})
+	}
+}
+
+// TestNavMenu_FieldBranches_InsideLoop verifies branches referencing loop variable fields
+// and bare loop variables, in attribute values and text inside loops.
+func TestNavMenu_FieldBranches_InsideLoop(t *testing.T) {
+	tests := []struct {
+		name    string
+		active  bool
+		liClass string
+		liText  string
+		tagText string
+	}{
+		{"active", true, "item home", "Active", "new"},
+		{"inactive", false, "item nav-inactive", "Home", "hidden"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			renderer := rendertest.NewTestRenderer(newNavMenu(tt.active))
+
+			// Act
+			nav := renderer.RenderRoot()
+
+			// Assert
+			li := nav.Children[1].Children[0]
+			if got := li.Attributes["class"]; got != tt.liClass {
+				t.Errorf("Expected li class %q, got %v", tt.liClass, got)
+			}
+			if li.Content != tt.liText {
+				t.Errorf("Expected li text %q, got %q", tt.liText, li.Content)
+			}
+			tag := nav.Children[2].Children[0]
+			if got := rendertest.TextOf(tag); got != tt.tagText {
+				t.Errorf("Expected tag text %q, got %q", tt.tagText, got)
+			}
+		})
+	}
+}
+
+// TestNavMenu_FieldBranches_ReflectStateChanges verifies that field branches are read at
+// render time rather than captured at compile time.
+func TestNavMenu_FieldBranches_ReflectStateChanges(t *testing.T) {
+	// Arrange
+	menu := newNavMenu(true)
+	renderer := rendertest.NewTestRenderer(menu)
+	renderer.RenderRoot()
+
+	// Act
+	menu.ActiveClass = "nav-highlighted"
+	menu.StateHasChanged()
+
+	// Assert
+	if got := renderer.GetCurrentVDOM().Attributes["class"]; got != "nav-highlighted" {
+		t.Errorf("Expected updated class 'nav-highlighted', got %v", got)
+	}
+}
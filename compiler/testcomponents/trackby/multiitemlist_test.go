@@ -6,7 +6,7 @@ package trackby
 import (
 	"testing"
 
-	"github.com/ForgeLogic/nojs-compiler/testcomponents"
+	"github.com/ForgeLogic/nojs/rendertest"
 	"github.com/ForgeLogic/nojs/vdom"
 )
 
@@ -23,7 +23,7 @@ func TestMultiItemList_MultipleChildrenPerIteration_InitialRender(t *testing.T)
 			{ID: 103, Name: "Gamma"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(multiList)
+	renderer := rendertest.NewTestRenderer(multiList)
 
 	// Act: Perform initial render
 	vnode := renderer.RenderRoot()
@@ -96,7 +96,7 @@ func TestMultiItemList_MultipleChildrenPerIteration_AddItem(t *testing.T) {
 			{ID: 103, Name: "Gamma"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(multiList)
+	renderer := rendertest.NewTestRenderer(multiList)
 	vnode1 := renderer.RenderRoot()
 
 	// Find the <ul> and verify initial state
@@ -115,7 +115,7 @@ func TestMultiItemList_MultipleChildrenPerIteration_AddItem(t *testing.T) {
 	multiList.AddItem("Delta")
 
 	// Assert: Verify the VDOM was updated
-	vnode2 := renderer.GetCurrentVDOM()
+	vnode2 := renderer.WaitForRender(t)
 	var ulNode2 *vdom.VNode
 	for _, child := range vnode2.Children {
 		if child.Tag == "ul" {
@@ -153,7 +153,7 @@ func TestMultiItemList_MultipleChildrenPerIteration_ClearItems(t *testing.T) {
 			{ID: 103, Name: "Gamma"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(multiList)
+	renderer := rendertest.NewTestRenderer(multiList)
 	vnode1 := renderer.RenderRoot()
 
 	// Verify initial state
@@ -172,7 +172,7 @@ func TestMultiItemList_MultipleChildrenPerIteration_ClearItems(t *testing.T) {
 	multiList.ClearItems()
 
 	// Assert: Verify the VDOM was updated with empty list
-	vnode2 := renderer.GetCurrentVDOM()
+	vnode2 := renderer.WaitForRender(t)
 	var ulNode2 *vdom.VNode
 	for _, child := range vnode2.Children {
 		if child.Tag == "ul" {
@@ -204,8 +204,8 @@ func TestMultiItemList_MultipleChildrenPerIteration_RenderIsolation(t *testing.T
 		},
 	}
 
-	renderer1 := testcomponents.NewTestRenderer(multiList1)
-	renderer2 := testcomponents.NewTestRenderer(multiList2)
+	renderer1 := rendertest.NewTestRenderer(multiList1)
+	renderer2 := rendertest.NewTestRenderer(multiList2)
 
 	// Act: Render both
 	vnode1 := renderer1.RenderRoot()
@@ -239,8 +239,8 @@ func TestMultiItemList_MultipleChildrenPerIteration_RenderIsolation(t *testing.T
 	multiList1.AddItem("Delta")
 
 	// Assert: Only the first instance was modified
-	vnode1 = renderer1.GetCurrentVDOM()
-	vnode2 = renderer2.GetCurrentVDOM()
+	vnode1 = renderer1.WaitForRender(t)
+	vnode2 = renderer2.WaitForRender(t)
 
 	ulNode1 = nil
 	ulNode2 = nil
@@ -279,13 +279,13 @@ func TestMultiItemList_MultipleChildrenPerIteration_VariableNamesUnique(t *testi
 			{ID: 102, Name: "Beta"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(multiList)
+	renderer := rendertest.NewTestRenderer(multiList)
 
 	// Act: Render the component multiple times to ensure no state issues
 	vnode := renderer.RenderRoot()
 	for range 5 {
 		multiList.AddItem("Item")
-		vnode = renderer.GetCurrentVDOM()
+		vnode = renderer.WaitForRender(t)
 	}
 
 	// Assert: Verify final render is valid
@@ -6,7 +6,7 @@ package trackby
 import (
 	"testing"
 
-	"github.com/ForgeLogic/nojs-compiler/testcomponents"
+	"github.com/ForgeLogic/nojs/rendertest"
 	"github.com/ForgeLogic/nojs/vdom"
 )
 
@@ -21,7 +21,7 @@ func TestProductList_DotNotationTrackBy_InitialRender(t *testing.T) {
 			{ID: 3, Name: "Keyboard"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(productList)
+	renderer := rendertest.NewTestRenderer(productList)
 
 	// Act: Perform initial render
 	vnode := renderer.RenderRoot()
@@ -82,7 +82,7 @@ func TestProductList_DotNotationTrackBy_AddProduct(t *testing.T) {
 			{ID: 3, Name: "Keyboard"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(productList)
+	renderer := rendertest.NewTestRenderer(productList)
 	vnode1 := renderer.RenderRoot()
 
 	// Find the initial <ul> element
@@ -101,7 +101,7 @@ func TestProductList_DotNotationTrackBy_AddProduct(t *testing.T) {
 	productList.AddProduct("Monitor")
 
 	// Assert: Verify the VDOM was updated
-	vnode2 := renderer.GetCurrentVDOM()
+	vnode2 := renderer.WaitForRender(t)
 	var ulNode2 *vdom.VNode
 	for _, child := range vnode2.Children {
 		if child.Tag == "ul" {
@@ -134,14 +134,14 @@ func TestProductList_DotNotationTrackBy_MultipleAdditions(t *testing.T) {
 			{ID: 3, Name: "Keyboard"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(productList)
+	renderer := rendertest.NewTestRenderer(productList)
 	renderer.RenderRoot()
 
 	// Act & Assert: Add multiple products
 	productsToAdd := []string{"Monitor", "Headphones", "Webcam"}
 	for i, newProduct := range productsToAdd {
 		productList.AddProduct(newProduct)
-		vnode := renderer.GetCurrentVDOM()
+		vnode := renderer.WaitForRender(t)
 
 		var ulNode *vdom.VNode
 		for _, child := range vnode.Children {
@@ -177,7 +177,7 @@ func TestProductList_DotNotationTrackBy_ClearProducts(t *testing.T) {
 			{ID: 3, Name: "Keyboard"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(productList)
+	renderer := rendertest.NewTestRenderer(productList)
 	vnode1 := renderer.RenderRoot()
 
 	// Verify initial state
@@ -196,7 +196,7 @@ func TestProductList_DotNotationTrackBy_ClearProducts(t *testing.T) {
 	productList.ClearProducts()
 
 	// Assert: Verify the VDOM was updated with an empty list
-	vnode2 := renderer.GetCurrentVDOM()
+	vnode2 := renderer.WaitForRender(t)
 	var ulNode2 *vdom.VNode
 	for _, child := range vnode2.Children {
 		if child.Tag == "ul" {
@@ -221,7 +221,7 @@ func TestProductList_DotNotationTrackBy_AddAfterClear(t *testing.T) {
 			{ID: 3, Name: "Keyboard"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(productList)
+	renderer := rendertest.NewTestRenderer(productList)
 	renderer.RenderRoot()
 
 	// Act: Clear and then add new products
@@ -229,7 +229,7 @@ func TestProductList_DotNotationTrackBy_AddAfterClear(t *testing.T) {
 	productList.AddProduct("NewItem")
 
 	// Assert: Verify the list contains only the newly added product
-	vnode := renderer.GetCurrentVDOM()
+	vnode := renderer.WaitForRender(t)
 	var ulNode *vdom.VNode
 	for _, child := range vnode.Children {
 		if child.Tag == "ul" {
@@ -269,8 +269,8 @@ func TestProductList_DotNotationTrackBy_RenderIsolation(t *testing.T) {
 		},
 	}
 
-	renderer1 := testcomponents.NewTestRenderer(productList1)
-	renderer2 := testcomponents.NewTestRenderer(productList2)
+	renderer1 := rendertest.NewTestRenderer(productList1)
+	renderer2 := rendertest.NewTestRenderer(productList2)
 
 	// Act: Render both
 	vnode1 := renderer1.RenderRoot()
@@ -300,8 +300,8 @@ func TestProductList_DotNotationTrackBy_RenderIsolation(t *testing.T) {
 	productList1.AddProduct("UniqueProduct")
 
 	// Assert: Only the first instance was modified
-	vnode1 = renderer1.GetCurrentVDOM()
-	vnode2 = renderer2.GetCurrentVDOM()
+	vnode1 = renderer1.WaitForRender(t)
+	vnode2 = renderer2.WaitForRender(t)
 
 	ulNode1 = nil
 	ulNode2 = nil
@@ -338,7 +338,7 @@ func TestProductList_DotNotationTrackBy_TrackByUsingProductID(t *testing.T) {
 			{ID: 3, Name: "Keyboard"},
 		},
 	}
-	renderer := testcomponents.NewTestRenderer(productList)
+	renderer := rendertest.NewTestRenderer(productList)
 	vnode1 := renderer.RenderRoot()
 
 	// Get initial products
@@ -363,7 +363,7 @@ func TestProductList_DotNotationTrackBy_TrackByUsingProductID(t *testing.T) {
 	productList.AddProduct("Tablet")
 
 	// Assert
-	vnode2 := renderer.GetCurrentVDOM()
+	vnode2 := renderer.WaitForRender(t)
 	var ulNode2 *vdom.VNode
 	for _, child := range vnode2.Children {
 		if child.Tag == "ul" {
@@ -6,7 +6,7 @@ package trackby
 import (
 	"testing"
 
-	"github.com/ForgeLogic/nojs-compiler/testcomponents"
+	"github.com/ForgeLogic/nojs/rendertest"
 	"github.com/ForgeLogic/nojs/vdom"
 )
 
@@ -17,7 +17,7 @@ func TestTagList_BareVariableTrackBy_InitialRender(t *testing.T) {
 	tagList := &TagList{
 		Tags: []string{"golang", "wasm", "component", "framework"},
 	}
-	renderer := testcomponents.NewTestRenderer(tagList)
+	renderer := rendertest.NewTestRenderer(tagList)
 
 	// Act: Perform initial render
 	vnode := renderer.RenderRoot()
@@ -67,7 +67,7 @@ func TestTagList_BareVariableTrackBy_AddTag(t *testing.T) {
 	tagList := &TagList{
 		Tags: []string{"golang", "wasm", "component", "framework"},
 	}
-	renderer := testcomponents.NewTestRenderer(tagList)
+	renderer := rendertest.NewTestRenderer(tagList)
 	vnode1 := renderer.RenderRoot()
 
 	// Find the initial <ul> element
@@ -86,7 +86,7 @@ func TestTagList_BareVariableTrackBy_AddTag(t *testing.T) {
 	tagList.AddTag("testing")
 
 	// Assert: Verify the VDOM was updated
-	vnode2 := renderer.GetCurrentVDOM()
+	vnode2 := renderer.WaitForRender(t)
 	var ulNode2 *vdom.VNode
 	for _, child := range vnode2.Children {
 		if child.Tag == "ul" {
@@ -115,14 +115,14 @@ func TestTagList_BareVariableTrackBy_MultipleAdditions(t *testing.T) {
 	tagList := &TagList{
 		Tags: []string{"golang", "wasm", "component", "framework"},
 	}
-	renderer := testcomponents.NewTestRenderer(tagList)
+	renderer := rendertest.NewTestRenderer(tagList)
 	renderer.RenderRoot()
 
 	// Act & Assert: Add multiple tags
 	tagsToAdd := []string{"rust", "typescript", "python"}
 	for i, newTag := range tagsToAdd {
 		tagList.AddTag(newTag)
-		vnode := renderer.GetCurrentVDOM()
+		vnode := renderer.WaitForRender(t)
 
 		var ulNode *vdom.VNode
 		for _, child := range vnode.Children {
@@ -153,7 +153,7 @@ func TestTagList_BareVariableTrackBy_ClearTags(t *testing.T) {
 	tagList := &TagList{
 		Tags: []string{"golang", "wasm", "component", "framework"},
 	}
-	renderer := testcomponents.NewTestRenderer(tagList)
+	renderer := rendertest.NewTestRenderer(tagList)
 	vnode1 := renderer.RenderRoot()
 
 	// Verify initial state
@@ -172,7 +172,7 @@ func TestTagList_BareVariableTrackBy_ClearTags(t *testing.T) {
 	tagList.ClearTags()
 
 	// Assert: Verify the VDOM was updated with an empty list
-	vnode2 := renderer.GetCurrentVDOM()
+	vnode2 := renderer.WaitForRender(t)
 	var ulNode2 *vdom.VNode
 	for _, child := range vnode2.Children {
 		if child.Tag == "ul" {
@@ -193,7 +193,7 @@ func TestTagList_BareVariableTrackBy_AddAfterClear(t *testing.T) {
 	tagList := &TagList{
 		Tags: []string{"golang", "wasm", "component", "framework"},
 	}
-	renderer := testcomponents.NewTestRenderer(tagList)
+	renderer := rendertest.NewTestRenderer(tagList)
 	renderer.RenderRoot()
 
 	// Act: Clear and then add new tags
@@ -201,7 +201,7 @@ func TestTagList_BareVariableTrackBy_AddAfterClear(t *testing.T) {
 	tagList.AddTag("newonly")
 
 	// Assert: Verify the list contains only the newly added tag
-	vnode := renderer.GetCurrentVDOM()
+	vnode := renderer.WaitForRender(t)
 	var ulNode *vdom.VNode
 	for _, child := range vnode.Children {
 		if child.Tag == "ul" {
@@ -232,8 +232,8 @@ func TestTagList_BareVariableTrackBy_RenderIsolation(t *testing.T) {
 		Tags: []string{"golang", "wasm", "component", "framework"},
 	}
 
-	renderer1 := testcomponents.NewTestRenderer(tagList1)
-	renderer2 := testcomponents.NewTestRenderer(tagList2)
+	renderer1 := rendertest.NewTestRenderer(tagList1)
+	renderer2 := rendertest.NewTestRenderer(tagList2)
 
 	// Act: Render both
 	vnode1 := renderer1.RenderRoot()
@@ -263,8 +263,8 @@ func TestTagList_BareVariableTrackBy_RenderIsolation(t *testing.T) {
 	tagList1.AddTag("unique1")
 
 	// Assert: Only the first instance was modified
-	vnode1 = renderer1.GetCurrentVDOM()
-	vnode2 = renderer2.GetCurrentVDOM()
+	vnode1 = renderer1.WaitForRender(t)
+	vnode2 = renderer2.WaitForRender(t)
 
 	ulNode1 = nil
 	ulNode2 = nil
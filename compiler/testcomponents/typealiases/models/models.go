@@ -0,0 +1,20 @@
+// Package models declares the data types of the typealiases fixture.
+package models
+
+// User is a listed user.
+type User struct {
+	ID   int
+	Name string
+}
+
+// List is a titled list of items of any type.
+type List[T any] struct {
+	Title string
+	Items []T
+}
+
+// Pair holds two values of different types.
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
@@ -0,0 +1,23 @@
+package typealiases
+
+import (
+	"github.com/ForgeLogic/nojs-compiler/testcomponents/typealiases/models"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Users is a slice of users, declared as an alias.
+type Users = []models.User
+
+// Roles is an alias of an alias, two levels from the slice it stands for.
+type Roles = Assignments
+
+// Assignments pairs role names with users.
+type Assignments = []models.Pair[string, models.User]
+
+// Roster has props typed with a generic instantiation and with aliases.
+type Roster struct {
+	runtime.ComponentBase
+	Team   models.List[models.User]
+	Owners Users
+	Staff  Roles
+}
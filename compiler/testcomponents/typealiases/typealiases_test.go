@@ -0,0 +1,48 @@
+//go:build !wasm
+// +build !wasm
+
+package typealiases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs-compiler/testcomponents/typealiases/models"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// texts returns the text of each item of list.
+func texts(list *vdom.VNode) []string {
+	var items []string
+	for _, item := range list.Children {
+		items = append(items, item.Content)
+	}
+	return items
+}
+
+// TestRoster_AliasedAndGenericProps verifies loops over an aliased slice, over an alias of
+// an alias, and over the field of a generic instantiation, with bindings on its fields.
+func TestRoster_AliasedAndGenericProps(t *testing.T) {
+	// Arrange
+	ada, linus := models.User{ID: 1, Name: "Ada"}, models.User{ID: 2, Name: "Linus"}
+	roster := &Roster{
+		Team:   models.List[models.User]{Title: "Core", Items: []models.User{ada, linus}},
+		Owners: Users{linus},
+		Staff:  Roles{{Key: "lead", Value: ada}},
+	}
+
+	// Act
+	root := rendertest.NewTestRenderer(roster).RenderRoot()
+
+	// Assert
+	if len(root.Children) != 4 || root.Children[0].Content != "Core" {
+		t.Fatalf("expected the team title and three lists, got:\n%s", rendertest.FormatVNode(root))
+	}
+	want := [][]string{{"Ada", "Linus"}, {"Linus"}, {"lead: Ada"}}
+	for i, list := range root.Children[1:] {
+		if got := texts(list); strings.Join(got, ",") != strings.Join(want[i], ",") {
+			t.Errorf("expected list %d to show %v, got:\n%s", i, want[i], rendertest.FormatVNode(list))
+		}
+	}
+}
@@ -0,0 +1,18 @@
+//nojs:routes
+
+package typedroutes
+
+// Route patterns used to exercise typed params generation.
+const (
+	// Home is the landing page.
+	Home = "/"
+
+	// BlogPost shows a single post of a given year.
+	BlogPost = "/blog/{year:int}/{slug}"
+
+	// Order shows an order by its numeric id.
+	Order = "/orders/{id:int64}"
+
+	// UserProfile shows a user by UUID.
+	UserProfile = "/users/{user_id:uuid}/profile"
+)
@@ -0,0 +1,104 @@
+//go:build !wasm
+// +build !wasm
+
+package typedroutes
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// TestBlogPost_BuildParse_RoundTrip verifies that building a path and parsing it back
+// yields the original params, including values that need escaping.
+func TestBlogPost_BuildParse_RoundTrip(t *testing.T) {
+	want := BlogPostParams{Year: 2024, Slug: "hello world/again"}
+
+	path := BuildBlogPostPath(want)
+	if path != "/blog/2024/hello%20world%2Fagain" {
+		t.Fatalf("unexpected path: %q", path)
+	}
+
+	got, err := ParseBlogPostPath(path)
+	if err != nil {
+		t.Fatalf("ParseBlogPostPath(%q) failed: %v", path, err)
+	}
+	if got != want {
+		t.Errorf("round-trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+// TestAllRoutes_BuildParse_RoundTrip verifies round-tripping for every declared route.
+func TestAllRoutes_BuildParse_RoundTrip(t *testing.T) {
+	if _, err := ParseHomePath(BuildHomePath(HomeParams{})); err != nil {
+		t.Errorf("Home round-trip failed: %v", err)
+	}
+
+	order := OrderParams{ID: 9007199254740993}
+	if got, err := ParseOrderPath(BuildOrderPath(order)); err != nil || got != order {
+		t.Errorf("Order round-trip: got %+v, err %v", got, err)
+	}
+
+	user := UserProfileParams{UserID: "123e4567-e89b-12d3-a456-426614174000"}
+	if got, err := ParseUserProfilePath(BuildUserProfilePath(user)); err != nil || got != user {
+		t.Errorf("UserProfile round-trip: got %+v, err %v", got, err)
+	}
+}
+
+// TestParse_InvalidParams_ReturnsError verifies that coercion failures surface as errors
+// instead of silently producing zero values.
+func TestParse_InvalidParams_ReturnsError(t *testing.T) {
+	cases := []struct {
+		name  string
+		parse func() error
+	}{
+		{"non-int year", func() error { _, err := ParseBlogPostPath("/blog/twenty/slug"); return err }},
+		{"overflowing int64", func() error { _, err := ParseOrderPath("/orders/99999999999999999999"); return err }},
+		{"malformed uuid", func() error { _, err := ParseUserProfilePath("/users/not-a-uuid/profile"); return err }},
+		{"static segment mismatch", func() error { _, err := ParseBlogPostPath("/news/2024/slug"); return err }},
+		{"segment count mismatch", func() error { _, err := ParseBlogPostPath("/blog/2024"); return err }},
+	}
+
+	for _, tc := range cases {
+		if err := tc.parse(); err == nil {
+			t.Errorf("%s: expected an error, got nil", tc.name)
+		}
+	}
+}
+
+// TestRouterGlue_ValidateAndFactory verifies the helpers meant for router.Route.
+func TestRouterGlue_ValidateAndFactory(t *testing.T) {
+	if err := ValidateBlogPostParams(map[string]string{"year": "2024", "slug": "x"}); err != nil {
+		t.Errorf("expected valid params, got %v", err)
+	}
+	if err := ValidateBlogPostParams(map[string]string{"year": "abc", "slug": "x"}); err == nil {
+		t.Error("expected an error for a non-int year")
+	}
+	if err := ValidateBlogPostParams(map[string]string{"slug": "x"}); err == nil {
+		t.Error("expected an error for a missing param")
+	}
+
+	var received BlogPostParams
+	factory := BlogPostFactory(func(p BlogPostParams) runtime.Component {
+		received = p
+		return &stubPage{}
+	})
+
+	if comp := factory(map[string]string{"year": "2024", "slug": "x"}); comp == nil {
+		t.Fatal("expected a component for valid params")
+	}
+	if received != (BlogPostParams{Year: 2024, Slug: "x"}) {
+		t.Errorf("factory received %+v", received)
+	}
+	if comp := factory(map[string]string{"year": "abc", "slug": "x"}); comp != nil {
+		t.Error("expected nil component for invalid params")
+	}
+}
+
+// stubPage is a minimal component returned by the test factory.
+type stubPage struct {
+	runtime.ComponentBase
+}
+
+func (s *stubPage) Render(r runtime.Renderer) *vdom.VNode { return vdom.Div(nil) }
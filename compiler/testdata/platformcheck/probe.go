@@ -0,0 +1,9 @@
+package platformcheck
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// Probe is the component the platform contract tests generate code for.
+type Probe struct {
+	runtime.ComponentBase
+	Label string
+}
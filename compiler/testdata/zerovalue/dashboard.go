@@ -0,0 +1,41 @@
+package zerovalue
+
+import (
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Points is a named number.
+type Points int
+
+// Stats is reached through a field.
+type Stats struct {
+	Total float64
+}
+
+// Line is an entry of Quiet.Lines.
+type Line struct {
+	Name string
+	Qty  int
+}
+
+// Dashboard shows unset numbers and times after labels.
+type Dashboard struct {
+	runtime.ComponentBase
+	Year      int
+	UpdatedAt time.Time
+	Stats     Stats
+	Score     Points
+}
+
+// Quiet binds the same kinds of fields in ways that are not reported.
+type Quiet struct {
+	runtime.ComponentBase
+	Name   string
+	Active bool
+	Loaded bool
+	Year   int
+	Count  int
+	Lines  []Line
+}
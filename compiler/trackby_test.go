@@ -0,0 +1,45 @@
+//go:build !wasm
+// +build !wasm
+
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestValidateTrackByField_UntypedAcrossFiles verifies that without type information the
+// element type is found in any file of the component's package, and that a nested trackBy
+// is checked segment by segment with the fields of the type each segment is looked up on.
+func TestValidateTrackByField_UntypedAcrossFiles(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	files := map[string]string{
+		"roster.go": "package roster\n\ntype Roster struct {\n\tUsers []User\n}\n",
+		"user.go":   "package roster\n\ntype User struct {\n\tName    string\n\tProfile *Profile\n}\n\ntype Profile struct {\n\tID    string\n\tEmail string\n}\n",
+	}
+	for name, source := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	comp := componentInfo{Path: filepath.Join(dir, "Roster.gt.html"), PascalName: "Roster"}
+
+	// Act
+	valid := validateTrackByField("Profile.ID", "User", nil, comp)
+	typo := validateTrackByField("Profile.IDD", "User", nil, comp)
+	builtin := validateTrackByField("Name.Length", "User", nil, comp)
+
+	// Assert
+	if valid != nil {
+		t.Errorf("expected user.Profile.ID to validate, got %v", valid)
+	}
+	if typo == nil || !strings.Contains(typo.Error(), "not found on type 'Profile'") || !strings.Contains(typo.Error(), "Available fields: [ID, Email]") {
+		t.Errorf("expected the fields of Profile listed, got %v", typo)
+	}
+	if builtin == nil || !strings.Contains(builtin.Error(), "cannot access field 'Length' on type 'string'") {
+		t.Errorf("expected a field of a string rejected, got %v", builtin)
+	}
+}
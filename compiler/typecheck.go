@@ -0,0 +1,93 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typeLoader type-checks the packages of the compiled tree from source, on demand, so
+// the compiler can see through aliases and generic instantiations in component fields.
+//
+// Standard library packages are not type-checked: they are imported as empty packages,
+// which leaves the types that use them invalid (isValidType). Those fields keep the type
+// written in the source, which the string-based checks handle. Compiling the standard
+// library from source would make every compilation seconds slower, and its export data
+// cannot be read by every version of go/types the compiler may be built with.
+type typeLoader struct {
+	fset     *token.FileSet
+	packages map[string]*packages.Package // Every loaded package and dependency, by import path
+	checked  map[string]*types.Package    // Type-checked packages, by import path
+}
+
+// newTypeLoader indexes roots and their dependencies, which must have been loaded with
+// packages.NeedImports, NeedDeps and NeedModule.
+func newTypeLoader(roots []*packages.Package) *typeLoader {
+	l := &typeLoader{
+		fset:     token.NewFileSet(),
+		packages: make(map[string]*packages.Package),
+		checked:  make(map[string]*types.Package),
+	}
+	packages.Visit(roots, nil, func(pkg *packages.Package) {
+		l.packages[pkg.PkgPath] = pkg
+	})
+	return l
+}
+
+// load returns the type-checked package path, or nil when it was not loaded. Type errors
+// are ignored: the declarations that check are still usable.
+func (l *typeLoader) load(path string) *types.Package {
+	if checked, ok := l.checked[path]; ok {
+		return checked
+	}
+	pkg, ok := l.packages[path]
+	if !ok {
+		return nil
+	}
+	if pkg.Module == nil {
+		// Standard library: see the type documentation
+		checked := types.NewPackage(path, pkg.Name)
+		checked.MarkComplete()
+		l.checked[path] = checked
+		return checked
+	}
+
+	var files []*ast.File
+	for _, name := range pkg.GoFiles {
+		if strings.HasSuffix(name, ".generated.go") {
+			continue // Generated from the templates being compiled; possibly stale
+		}
+		if file, err := parser.ParseFile(l.fset, name, nil, parser.SkipObjectResolution); err == nil {
+			files = append(files, file)
+		}
+	}
+	// An import cycle is an error in Go; the placeholder only keeps a broken tree finite
+	l.checked[path] = types.NewPackage(path, pkg.Name)
+
+	conf := types.Config{
+		Importer: importerFunc(func(importPath string) (*types.Package, error) {
+			if imported, ok := pkg.Imports[importPath]; ok {
+				importPath = imported.PkgPath // Resolves vendored paths
+			}
+			if checked := l.load(importPath); checked != nil {
+				return checked, nil
+			}
+			checked := types.NewPackage(importPath, importPath[strings.LastIndex(importPath, "/")+1:])
+			checked.MarkComplete()
+			return checked, nil
+		}),
+		Error: func(error) {}, // Keep checking past errors
+	}
+	checked, _ := conf.Check(path, l.fset, files, nil)
+	l.checked[path] = checked
+	return checked
+}
+
+// importerFunc adapts a function to types.Importer.
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
 	"path/filepath"
 	"strings"
 
@@ -15,9 +16,24 @@ import (
 // Returns the Go type string, or empty string if field not found.
 // componentDir is the directory containing the component's Go files.
 func resolveNestedFieldType(fieldPath string, comp componentInfo, componentDir string) (string, error) {
+	fieldType, _, err := resolveNestedFieldPath(fieldPath, comp, componentDir)
+	return fieldType, err
+}
+
+// resolveNestedFieldPath resolves a nested field like resolveNestedFieldType and also returns
+// the prefixes of fieldPath that are pointers and are dereferenced on the way to the field
+// (e.g., "User.Address.City" with User *User and Address *Address -> ["User", "User.Address"]).
+func resolveNestedFieldPath(fieldPath string, comp componentInfo, componentDir string) (string, []string, error) {
 	parts := strings.Split(fieldPath, ".")
 	if len(parts) < 2 {
-		return "", fmt.Errorf("not a nested field path: %s", fieldPath)
+		return "", nil, fmt.Errorf("not a nested field path: %s", fieldPath)
+	}
+	if rootType := fieldRootType(parts[0], comp); rootType != nil {
+		fieldType, pointerPaths, err := resolveTypedFieldPath(parts, rootType, comp.Qualifier)
+		if err != nil {
+			return "", nil, err
+		}
+		return typeString(fieldType, comp.Qualifier), pointerPaths, nil
 	}
 
 	// Start with the root field
@@ -30,22 +46,26 @@ func resolveNestedFieldType(fieldPath string, comp componentInfo, componentDir s
 	} else if desc, exists := comp.Schema.State[rootField]; exists {
 		currentType = desc.GoType
 	} else {
-		return "", fmt.Errorf("root field '%s' not found on component '%s'", parts[0], comp.PascalName)
+		return "", nil, fmt.Errorf("root field '%s' not found on component '%s'", parts[0], comp.PascalName)
 	}
 
 	// Traverse the nested fields
+	var pointerPaths []string
 	for i := 1; i < len(parts); i++ {
 		fieldName := parts[i]
 
-		// Remove pointer dereference marker if present
-		currentType = strings.TrimPrefix(currentType, "*")
+		// Remove pointer dereference marker if present, remembering the pointer for nil checks
+		if strings.HasPrefix(currentType, "*") {
+			pointerPaths = append(pointerPaths, strings.Join(parts[:i], "."))
+			currentType = strings.TrimPrefix(currentType, "*")
+		}
 
 		// Remove slice marker if present
 		currentType = strings.TrimPrefix(currentType, "[]")
 
 		// If it's a simple type (like string, int), we can't access fields
 		if isBuiltinType(currentType) {
-			return "", fmt.Errorf("cannot access field '%s' on built-in type '%s'", fieldName, currentType)
+			return "", nil, fmt.Errorf("cannot access field '%s' on built-in type '%s'", fieldName, currentType)
 		}
 
 		// Extract the struct name and potential package prefix
@@ -64,13 +84,13 @@ func resolveNestedFieldType(fieldPath string, comp componentInfo, componentDir s
 		// Find the struct definition to get the field type
 		fieldType, err := findStructFieldType(componentDir, structName, fieldName, packagePath)
 		if err != nil {
-			return "", fmt.Errorf("cannot resolve field '%s' on type '%s': %v", fieldName, currentType, err)
+			return "", nil, fmt.Errorf("cannot resolve field '%s' on type '%s': %v", fieldName, currentType, err)
 		}
 
 		currentType = fieldType
 	}
 
-	return currentType, nil
+	return currentType, pointerPaths, nil
 }
 
 // resolvePackageFromAlias looks for import statements in Go files to resolve package aliases.
@@ -210,6 +230,23 @@ func getAvailableNestedFields(fieldPath string, comp componentInfo, componentDir
 		return nil
 	}
 
+	if rootType := fieldRootType(parts[0], comp); rootType != nil {
+		// The fields of the type reached by every part but the last
+		if len(parts) > 1 {
+			if t, _, err := resolveTypedFieldPath(parts[:len(parts)-1], rootType, comp.Qualifier); err == nil {
+				rootType = t
+			}
+		}
+		rootType = types.Unalias(rootType)
+		if pointer, ok := rootType.(*types.Pointer); ok {
+			rootType = pointer.Elem()
+		}
+		if slice, ok := types.Unalias(rootType).(*types.Slice); ok {
+			rootType = slice.Elem()
+		}
+		return exportedFields(rootType)
+	}
+
 	rootField := strings.ToLower(parts[0])
 	var fieldType string
 
@@ -295,3 +332,241 @@ func getStructFields(dir, structName string) ([]string, error) {
 
 	return nil, fmt.Errorf("struct '%s' not found in %s", structName, dir)
 }
+
+// resolveNamedBasicType follows the declaration of the named type goType ("Label", or
+// "kinds.Quantity" through the imports of componentDir) down to the built-in type it is
+// defined on: type Label string -> "string". found reports whether every declaration on the
+// way was found; basic is empty when the type is defined on something other than a built-in
+// type (a struct, a slice, a func...).
+func resolveNamedBasicType(goType, componentDir string) (basic string, found bool) {
+	dir, name := componentDir, goType
+	for depth := 0; depth < 10; depth++ {
+		if alias, typeName, qualified := strings.Cut(name, "."); qualified {
+			importPath, err := resolvePackageFromAlias(alias, dir)
+			if err != nil {
+				return "", false
+			}
+			if dir = findPackageDir(importPath); dir == "" {
+				return "", false
+			}
+			name = typeName
+		}
+
+		underlying := findTypeDeclInDir(dir, name)
+		switch t := underlying.(type) {
+		case nil:
+			return "", false
+		case *ast.Ident:
+			if isBuiltinType(t.Name) {
+				return t.Name, true
+			}
+			name = t.Name
+		case *ast.SelectorExpr:
+			name = extractTypeName(t)
+		default:
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// findTypeDeclInDir returns the type expression of the type declaration name in dir, or nil.
+func findTypeDeclInDir(dir, name string) ast.Expr {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil
+	}
+	for _, filePath := range matches {
+		if strings.Contains(filePath, ".generated.") || strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+		node, err := parser.ParseFile(token.NewFileSet(), filePath, nil, 0)
+		if err != nil {
+			continue
+		}
+		for _, decl := range node.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if typeSpec := spec.(*ast.TypeSpec); typeSpec.Name.Name == name {
+					return typeSpec.Type
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// attachFieldTypes sets the type-checked type of the fields of structName in schema and
+// rewrites the GoType of the fields whose written type hides its structure: an alias
+// (type Users = []User) is replaced by the type it stands for, through any chain of
+// aliases, and a generic instantiation is written with its type arguments (List[User]).
+// It returns the qualifier naming types as goFile does, or nil when pkg is nil or lacks
+// the struct, in which case the written types are kept.
+func attachFieldTypes(schema *componentSchema, pkg *types.Package, structName, goFile string) types.Qualifier {
+	if pkg == nil {
+		return nil
+	}
+	obj, ok := pkg.Scope().Lookup(structName).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	qualifier := fileQualifier(pkg, goFile)
+	for _, fields := range []map[string]propertyDescriptor{schema.Props, schema.State, schema.Refs} {
+		for key, desc := range fields {
+			field := lookupField(obj.Type(), desc.Name)
+			if field == nil || !isValidType(field.Type()) {
+				continue
+			}
+			desc.Type = field.Type()
+			if hidesStructure(desc.Type) {
+				desc.GoType = typeString(desc.Type, qualifier)
+			}
+			fields[key] = desc
+		}
+	}
+	return qualifier
+}
+
+// fileQualifier returns a qualifier that writes the types of pkg unqualified and those of
+// other packages with the name goFile imports them under.
+func fileQualifier(pkg *types.Package, goFile string) types.Qualifier {
+	names := make(map[string]string) // Import path -> explicit import name
+	if file, err := parser.ParseFile(token.NewFileSet(), goFile, nil, parser.ImportsOnly); err == nil {
+		for _, imp := range file.Imports {
+			if imp.Name != nil {
+				names[strings.Trim(imp.Path.Value, `"`)] = imp.Name.Name
+			}
+		}
+	}
+	return func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		if name, ok := names[p.Path()]; ok {
+			return name
+		}
+		return p.Name()
+	}
+}
+
+// hidesStructure reports whether t, as written, is or contains an alias or a generic
+// instantiation, whose structure the string-based type checks cannot see.
+func hidesStructure(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Alias:
+		return true
+	case *types.Named:
+		return t.TypeArgs().Len() > 0
+	case *types.Pointer:
+		return hidesStructure(t.Elem())
+	case *types.Slice:
+		return hidesStructure(t.Elem())
+	case *types.Array:
+		return hidesStructure(t.Elem())
+	case *types.Map:
+		return hidesStructure(t.Key()) || hidesStructure(t.Elem())
+	}
+	return false
+}
+
+// typeString writes t with qualifier, aliases replaced by the types they stand for.
+func typeString(t types.Type, qualifier types.Qualifier) string {
+	return types.TypeString(unaliasDeep(t), qualifier)
+}
+
+// unaliasDeep replaces the aliases in t and in the pointer, slice, array and map types it
+// is composed of. Named types, including generic instantiations, are kept.
+func unaliasDeep(t types.Type) types.Type {
+	switch t := types.Unalias(t).(type) {
+	case *types.Pointer:
+		return types.NewPointer(unaliasDeep(t.Elem()))
+	case *types.Slice:
+		return types.NewSlice(unaliasDeep(t.Elem()))
+	case *types.Array:
+		return types.NewArray(unaliasDeep(t.Elem()), t.Len())
+	case *types.Map:
+		return types.NewMap(unaliasDeep(t.Key()), unaliasDeep(t.Elem()))
+	default:
+		return t
+	}
+}
+
+// isValidType reports whether t was type-checked, which fails for types of packages that
+// could not be imported.
+func isValidType(t types.Type) bool {
+	return !strings.Contains(types.TypeString(t, nil), "invalid type")
+}
+
+// lookupField returns the exported field name of the struct t (or *t), including fields
+// promoted from embedded structs, or nil. The fields of a generic instantiation have its
+// type arguments substituted: Items of List[User] is a []User.
+func lookupField(t types.Type, name string) *types.Var {
+	if !token.IsExported(name) {
+		return nil
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.Unalias(t), true, nil, name)
+	if field, ok := obj.(*types.Var); ok && field.IsField() {
+		return field
+	}
+	return nil
+}
+
+// exportedFields returns the names of the exported fields of the struct t, or nil.
+func exportedFields(t types.Type) []string {
+	st, ok := types.Unalias(t).Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i); field.Exported() {
+			names = append(names, field.Name())
+		}
+	}
+	return names
+}
+
+// resolveTypedFieldPath resolves parts[1:] on the type-checked type t of the field
+// parts[0], as resolveNestedFieldPath does on written types. It returns the field's type
+// and the pointer prefixes dereferenced on the way.
+func resolveTypedFieldPath(parts []string, t types.Type, qualifier types.Qualifier) (types.Type, []string, error) {
+	var pointerPaths []string
+	for i := 1; i < len(parts); i++ {
+		fieldName := parts[i]
+		t = types.Unalias(t)
+		if pointer, ok := t.(*types.Pointer); ok {
+			pointerPaths = append(pointerPaths, strings.Join(parts[:i], "."))
+			t = types.Unalias(pointer.Elem())
+		}
+		if slice, ok := t.(*types.Slice); ok {
+			t = types.Unalias(slice.Elem())
+		}
+
+		if basic, ok := t.Underlying().(*types.Basic); ok {
+			return nil, nil, fmt.Errorf("cannot access field '%s' on built-in type '%s'", fieldName, basic.Name())
+		}
+		field := lookupField(t, fieldName)
+		if field == nil {
+			return nil, nil, fmt.Errorf("cannot resolve field '%s' on type '%s': field '%s' not found", fieldName, typeString(t, qualifier), fieldName)
+		}
+		t = field.Type()
+	}
+	return t, pointerPaths, nil
+}
+
+// fieldRootType returns the type-checked type of the component field named root, or nil.
+func fieldRootType(root string, comp componentInfo) types.Type {
+	if comp.Qualifier == nil {
+		return nil
+	}
+	if desc, exists := comp.Schema.Props[strings.ToLower(root)]; exists {
+		return desc.Type
+	}
+	if desc, exists := comp.Schema.State[strings.ToLower(root)]; exists {
+		return desc.Type
+	}
+	return nil
+}
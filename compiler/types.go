@@ -1,19 +1,28 @@
 package compiler
 
-import "regexp"
+import (
+	"go/types"
+	"regexp"
+)
 
 // componentSchema holds the type information for a component's props.
 type componentSchema struct {
 	Props   map[string]propertyDescriptor // Map of Prop name to its Go type (e.g., "Title": "string")
 	State   map[string]propertyDescriptor // Map of State name to its Go type (internal component state)
 	Methods map[string]methodDescriptor   // Map of method names to their signatures
-	Slot    *propertyDescriptor           // Optional: single content slot field ([]*vdom.VNode)
+	Slot    *propertyDescriptor           // Optional: default content slot field ([]*vdom.VNode)
+	Slots   map[string]propertyDescriptor // Named content slot fields ([]*vdom.VNode tagged nojs:"slot:name"), by slot name
+	Refs    map[string]propertyDescriptor // DOM ref fields (vdom.ElementRef or vdom.ElementRefs), never copied as props
+	Memo    bool                          // Struct doc comment holds //nojs:memo: PropsEqual is generated
+	Form    bool                          // Embeds forms.Validator: Errors.Field and Invalid.Field are bindable
 }
 
 type propertyDescriptor struct {
 	Name          string
 	LowercaseName string
 	GoType        string
+	Type          types.Type // Type-checked type, aliases included; nil when the package could not be type-checked
+	HistoryKey    string     // Key of a state field kept in router history state (`nojs:"state,history=key"`), "" otherwise
 }
 
 // methodDescriptor holds the signature information for a component method.
@@ -37,18 +46,75 @@ type componentInfo struct {
 	PackageName   string
 	ImportPath    string // Full import path (e.g., "github.com/ForgeLogic/nojs/appcomponents")
 	Schema        componentSchema
+	Qualifier     types.Qualifier // Names types as the component's Go file does; nil without type information
 }
 
 // compileOptions holds compiler-wide options passed from CLI flags.
 type compileOptions struct {
-	DevMode          bool           // Enable development mode (warnings, verbose errors, panic on lifecycle failures)
-	ComponentCounter map[string]int // Template-wide counter per component type for unique RenderChild keys
+	DevMode          bool              // Enable development mode (warnings, verbose errors, panic on lifecycle failures)
+	Strict           bool              // Fail compilation on template markup the HTML parser relocates or drops
+	ComponentCounter map[string]int    // Template-wide counter per component type for unique RenderChild keys
+	Imports          map[string]string // Packages the generated code refers to beyond the components it renders (name -> import path)
+	PackageAliases   map[string]string // Packages of the components it renders from other packages (import path -> name in the generated code)
+	Errors           *errorCollector   // Errors of the template being compiled, reported together once it is generated
+	Lines            *templateLines    // Template lines of the nodes, for the line directives of the generated code
 }
 
-// loopContext holds information about variables available in a loop scope.
+// loopContext holds information about variables available in a loop scope. Nested loops
+// link to the scope of the enclosing loop, whose variables stay visible.
 type loopContext struct {
-	IndexVar string // e.g., "i" or "_"
-	ValueVar string // e.g., "user"
+	IndexVar    string         // e.g., "i" or "_"
+	IndexType   string         // Type of the index variable: "int", or the key type of a ranged map
+	ValueVar    string         // e.g., "user"
+	ElementType string         // Element type of the ranged slice (e.g., "User")
+	Element     types.Type     // Type-checked element type; nil when unknown
+	TrackBy     string         // trackBy expression of the loop (e.g., "user.ID")
+	Occurrences map[string]int // Per-iteration usage count per component type, for unique trackBy keys
+	Parent      *loopContext   // Scope of the enclosing loop; nil for an outermost loop
+}
+
+// scopeOf returns the innermost loop scope declaring name as its index or value variable,
+// or nil when no enclosing loop does.
+func (l *loopContext) scopeOf(name string) *loopContext {
+	if name == "_" {
+		return nil
+	}
+	for scope := l; scope != nil; scope = scope.Parent {
+		if name == scope.ValueVar || name == scope.IndexVar {
+			return scope
+		}
+	}
+	return nil
+}
+
+// valueScope returns the innermost loop scope whose value variable is name, or nil.
+func (l *loopContext) valueScope(name string) *loopContext {
+	if scope := l.scopeOf(name); scope != nil && scope.ValueVar == name {
+		return scope
+	}
+	return nil
+}
+
+// variables returns the loop variables in scope, innermost loop first, without blanks.
+func (l *loopContext) variables() []string {
+	var names []string
+	for scope := l; scope != nil; scope = scope.Parent {
+		for _, name := range []string{scope.IndexVar, scope.ValueVar} {
+			if name != "" && name != "_" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// depth returns the number of loops enclosing the scope's body, 1 for an outermost loop.
+func (l *loopContext) depth() int {
+	depth := 0
+	for scope := l; scope != nil; scope = scope.Parent {
+		depth++
+	}
+	return depth
 }
 
 // textNodePosition tracks the location of an unwrapped text node in slot content.
@@ -58,14 +124,56 @@ type loopContext struct {
 // 	textContent string
 // }
 
-// Regex to find data binding expressions like {FieldName} or {user.Name}
-var dataBindingRegex = regexp.MustCompile(`\{([a-zA-Z0-9_.]+)\}`)
+// Regex to find data binding expressions like {FieldName}, {user.Name} or {FullName()}
+var dataBindingRegex = regexp.MustCompile(`\{([a-zA-Z0-9_.]+|` + methodCallPattern + `)\}`)
+
+// Regex to find text bindings with an optional pipeline of filters, like {Price},
+// {Price | number 2}, {CreatedAt | date "2006-01-02"} or {Title | upper}. A filter's
+// argument may also follow a colon: {Price|printf:'%.2f'}. A binding may also be an arithmetic
+// expression, recognized by its operator: {i + 1} or {(line.Qty * line.Price)|printf:'%.2f'},
+// or a call of a component method: {FullName()}. Group 2 holds the whole pipeline, which
+// parseFilterPipeline splits.
+var textBindingRegex = regexp.MustCompile(`\{([a-zA-Z0-9_.]+|` + methodCallPattern + `|[a-zA-Z0-9_.()\s]*[-+*/][-+*/a-zA-Z0-9_.()\s]*)((?:\s*\|\s*[a-zA-Z]+(?:\s*:\s*'[^']*'|\s+(?:"[^"]*"|'[^']*'|[^\s|'"{}]+))*)*)\s*\}`)
+
+// Regex matching a type name as written in a struct field: Label or kinds.Quantity
+var namedTypeRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
+// methodCallPattern matches a call of a component method in a binding, like FullName(). The
+// arguments are matched only so that a call passing some is reported rather than left as text.
+const methodCallPattern = `[a-zA-Z_][a-zA-Z0-9_]*\([^(){}]*\)`
+
+// Regex splitting a method call binding into the method name and its arguments
+var methodCallRegex = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)\(([^(){}]*)\)$`)
+
+// Regex matching a plain field path binding, as opposed to an arithmetic expression
+var fieldPathRegex = regexp.MustCompile(`^[a-zA-Z0-9_.]+$`)
+
+// boolConditionPattern matches a condition of bool fields joined by && and ||, with !
+// negation and parentheses: IsReady, !IsSaving, (IsAdmin || IsOwner) && !IsLocked.
+const boolConditionPattern = `[!(\s]*[a-zA-Z0-9_]+[)\s]*(?:(?:&&|\|\|)[!(\s]*[a-zA-Z0-9_]+[)\s]*)*`
+
+// Regex to find ternary expressions like { condition ? 'value1' : 'value2' }, where the
+// condition follows boolConditionPattern. Each branch is either a quoted literal or a field
+// reference ({IsActive ? ActiveClass : item.Class}).
+var ternaryExprRegex = regexp.MustCompile(`\{\s*(` + boolConditionPattern + `)\?\s*('[^']*'|[a-zA-Z_][a-zA-Z0-9_.]*)\s*:\s*('[^']*'|[a-zA-Z_][a-zA-Z0-9_.]*)\s*\}`)
+
+// Regex to find anything shaped like a ternary ({... ? ... : ...}), used to report
+// unsupported forms such as nested ternaries instead of treating them as bindings
+var ternaryLikeRegex = regexp.MustCompile(`\{[^{}]*\?[^{}]*:[^{}]*\}`)
+
+// Regex to find a conditional class list like {classes Active:'is-active' !Enabled:'off' 'card'}
+var classesExprRegex = regexp.MustCompile(`^\{\s*classes\s+([^{}]*)\}$`)
+
+// Regex to find a class object like {Active: 'is-active', HasError: 'error'}, with optional
+// static classes around it; the groups are the classes before, the pairs, and the classes after
+var classObjectRegex = regexp.MustCompile(`^([^{}'?]*)\{(\s*!?[a-zA-Z_][a-zA-Z0-9_.]*\s*:\s*'[^{}?]*)\}([^{}'?]*)$`)
 
-// Regex to find ternary expressions like { condition ? 'value1' : 'value2' }
-var ternaryExprRegex = regexp.MustCompile(`\{\s*(!?)([a-zA-Z0-9_]+)\s*\?\s*'([^']*)'\s*:\s*'([^']*)'\s*\}`)
+// Regex to match one entry of a {classes} list at the start of the remaining text:
+// a quoted literal, or a possibly negated Condition:'class' pair
+var classEntryRegex = regexp.MustCompile(`^(?:(!?)([a-zA-Z_][a-zA-Z0-9_.]*)\s*:\s*)?'([^']*)'`)
 
-// Regex to find boolean shorthand like {condition} or {!condition}
-var booleanShorthandRegex = regexp.MustCompile(`^\{\s*(!?)([a-zA-Z0-9_]+)\s*\}$`)
+// Regex to find boolean shorthand like {condition}, {!condition} or {IsA && !IsB}
+var booleanShorthandRegex = regexp.MustCompile(`^\{\s*(` + boolConditionPattern + `)\}$`)
 
 // Standard HTML boolean attributes
 var standardBooleanAttrs = map[string]bool{
@@ -2,7 +2,7 @@ package compiler
 
 import (
 	"fmt"
-	"os"
+	"sort"
 	"strings"
 
 	"github.com/ForgeLogic/nojs/events"
@@ -34,14 +34,54 @@ func validateComponentName(componentName, templatePath string) error {
 	return nil
 }
 
+// reservedPropAttribute describes an HTML attribute name components must not use for a prop.
+type reservedPropAttribute struct {
+	Use     string // What browsers do with the attribute
+	Suggest string // Prop name suggested instead
+}
+
+// reservedPropAttributes lists, by lowercase name, the global attributes browsers act on for
+// every element, whatever its tag. A prop named after one of them (<UserCard Slot="...">)
+// reads as the HTML attribute in a template, and stops reaching the component once it is
+// rendered as a custom element or inside shadow DOM.
+var reservedPropAttributes = map[string]reservedPropAttribute{
+	"is":    {Use: "customized built-in elements", Suggest: "Variant"},
+	"slot":  {Use: "assigning elements to shadow DOM slots", Suggest: "SlotName"},
+	"part":  {Use: "styling shadow DOM parts", Suggest: "PartName"},
+	"xmlns": {Use: "namespace declarations in SVG and MathML", Suggest: "Namespace"},
+}
+
+// checkReservedPropNames reports every prop named after an attribute of reservedPropAttributes,
+// as a warning or, in strict mode, as an error.
+func checkReservedPropNames(components []componentInfo, strict bool) error {
+	for _, comp := range components {
+		names := getAvailableFieldNames(comp.Schema.Props)
+		sort.Strings(names)
+		for _, name := range names {
+			reserved, ok := reservedPropAttributes[strings.ToLower(name)]
+			if !ok {
+				continue
+			}
+			msg := fmt.Sprintf("%s: prop '%s' of component '%s' is named after the HTML attribute '%s', which browsers use for %s. "+
+				"Rename the field, e.g. to '%s'",
+				comp.Path, name, comp.PascalName, strings.ToLower(name), reserved.Use, reserved.Suggest)
+			if strict {
+				return fmt.Errorf("component validation error in %s", msg)
+			}
+			fmt.Fprintf(warningOutput, "Warning in %s\n", msg)
+		}
+	}
+	return nil
+}
+
 // isBooleanAttribute checks if an attribute name is a standard HTML boolean attribute.
 func isBooleanAttribute(attrName string) bool {
 	return standardBooleanAttrs[attrName]
 }
 
 // validateBooleanCondition validates that a condition references a boolean field on the component.
-// Returns the propertyDescriptor if valid, or exits with a compile error.
-func validateBooleanCondition(condition string, comp componentInfo, templatePath string, lineNumber int, htmlSource string) propertyDescriptor {
+// Returns the propertyDescriptor if valid, or a compile error.
+func validateBooleanCondition(condition string, comp componentInfo, templatePath string, lineNumber int, htmlSource string) (propertyDescriptor, error) {
 	propDesc, exists := comp.Schema.Props[strings.ToLower(condition)]
 	if !exists {
 		// Also check state fields
@@ -51,37 +91,43 @@ func validateBooleanCondition(condition string, comp componentInfo, templatePath
 		allFields := append(getAvailableFieldNames(comp.Schema.Props), getAvailableFieldNames(comp.Schema.State)...)
 		availableFields := strings.Join(allFields, ", ")
 		contextLines := getContextLines(htmlSource, lineNumber, 2)
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Condition '%s' not found on component '%s'. Available fields: [%s]\n%s",
+		return propertyDescriptor{}, fmt.Errorf("Compilation Error in %s:%d: Condition '%s' not found on component '%s'. Available fields: [%s]\n%s",
 			templatePath, lineNumber, condition, comp.PascalName, availableFields, contextLines)
-		os.Exit(1)
 	}
 	if propDesc.GoType != "bool" {
 		contextLines := getContextLines(htmlSource, lineNumber, 2)
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Condition '%s' must be a bool field, found type '%s'.\n%s",
+		return propertyDescriptor{}, fmt.Errorf("Compilation Error in %s:%d: Condition '%s' must be a bool field, found type '%s'.\n%s",
 			templatePath, lineNumber, condition, propDesc.GoType, contextLines)
-		os.Exit(1)
 	}
-	return propDesc
+	return propDesc, nil
 }
 
-// validateEventHandler validates that an event handler exists and has the correct signature.
-// Returns the methodDescriptor if valid, or exits with a compile error and helpful suggestions.
-func validateEventHandler(eventName, handlerName, tagName string, comp componentInfo, templatePath string, lineNumber int, htmlSource string) methodDescriptor {
+// validateEventOnTag validates that eventName is a known event supported on tagName and
+// returns its signature, or a compile error.
+func validateEventOnTag(eventName, tagName, templatePath string, lineNumber int, htmlSource string) (*events.EventSignature, error) {
 	// Get the event signature from the registry
 	eventSig := events.GetEventSignature(eventName)
 	if eventSig == nil {
 		contextLines := getContextLines(htmlSource, lineNumber, 2)
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Unknown event '@%s'.\n%s\nSupported events: @onclick, @oninput, @onchange, @onkeydown, @onkeyup, @onkeypress, @onfocus, @onblur, @onsubmit, @onmousedown, @onmouseup, @onmousemove\n",
-			templatePath, lineNumber, eventName, contextLines)
-		os.Exit(1)
+		return nil, fmt.Errorf("Compilation Error in %s:%d: Unknown event '@%s'.\n%s\nSupported events: @%s\n",
+			templatePath, lineNumber, eventName, contextLines, strings.Join(events.EventNames(), ", @"))
 	}
 
 	// Check if the event is supported on this HTML tag
 	if !events.IsEventSupported(eventName, tagName) {
 		contextLines := getContextLines(htmlSource, lineNumber, 2)
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Event '@%s' is not supported on <%s>.\n%s\nSupported elements for @%s: %v\n",
+		return nil, fmt.Errorf("Compilation Error in %s:%d: Event '@%s' is not supported on <%s>.\n%s\nSupported elements for @%s: %v\n",
 			templatePath, lineNumber, eventName, tagName, contextLines, eventName, eventSig.SupportedTags)
-		os.Exit(1)
+	}
+	return eventSig, nil
+}
+
+// validateEventHandler validates that an event handler exists and has the correct signature.
+// Returns the methodDescriptor if valid, or a compile error with helpful suggestions.
+func validateEventHandler(eventName, handlerName, tagName string, comp componentInfo, templatePath string, lineNumber int, htmlSource string) (methodDescriptor, error) {
+	eventSig, err := validateEventOnTag(eventName, tagName, templatePath, lineNumber, htmlSource)
+	if err != nil {
+		return methodDescriptor{}, err
 	}
 
 	// Check if the handler method exists
@@ -89,87 +135,73 @@ func validateEventHandler(eventName, handlerName, tagName string, comp component
 	if !exists {
 		contextLines := getContextLines(htmlSource, lineNumber, 2)
 		availableMethods := getAvailableMethodNames(comp.Schema.Methods)
-		fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Handler method '%s' not found on component '%s'.\n%s\nAvailable methods: %s\n",
+		return methodDescriptor{}, fmt.Errorf("Compilation Error in %s:%d: Handler method '%s' not found on component '%s'.\n%s\nAvailable methods: %s\n",
 			templatePath, lineNumber, handlerName, comp.PascalName, contextLines, availableMethods)
-		os.Exit(1)
 	}
 
-	// Validate the method signature
+	// Validate the method signature. Every handler may take a leading runtime.Ctx
+	// (the extended signature); the parameters after it follow the rules below.
+	params := method.Params
+	if handlerTakesCtx(method) {
+		params = params[1:]
+	}
+
 	// Special case: onclick can accept either func() or func(ClickEventArgs)
 	if eventName == "onclick" {
-		if len(method.Params) == 0 {
+		if len(params) == 0 {
 			// func() - valid, will use AdaptNoArgEvent
-			return method
-		} else if len(method.Params) == 1 && method.Params[0].Type == "events.ClickEventArgs" {
+			return method, nil
+		} else if len(params) == 1 && params[0].Type == "events.ClickEventArgs" {
 			// func(ClickEventArgs) - valid, will use AdaptClickEvent
-			return method
+			return method, nil
 		} else {
 			// Invalid signature
 			contextLines := getContextLines(htmlSource, lineNumber, 2)
-			fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Handler '%s' for '@onclick' has incorrect signature.\n%s\nExpected: func(c *%s) %s() OR func(c *%s) %s(e events.ClickEventArgs)\nFound:    func(c *%s) %s(",
+			return methodDescriptor{}, fmt.Errorf("Compilation Error in %s:%d: Handler '%s' for '@onclick' has incorrect signature.\n%s\nExpected: func(c *%s) %s() OR func(c *%s) %s(e events.ClickEventArgs), optionally with a leading ctx runtime.Ctx parameter\nFound:    func(c *%s) %s(%s)\n",
 				templatePath, lineNumber, handlerName, contextLines,
 				comp.PascalName, handlerName,
 				comp.PascalName, handlerName,
-				comp.PascalName, handlerName)
-			for i, p := range method.Params {
-				if i > 0 {
-					fmt.Fprintf(os.Stderr, ", ")
-				}
-				fmt.Fprintf(os.Stderr, "%s %s", p.Name, p.Type)
-			}
-			fmt.Fprintf(os.Stderr, ")\n")
-			os.Exit(1)
+				comp.PascalName, handlerName, formatParams(method.Params))
 		}
 	}
 
 	// Standard validation for other events
 	if eventSig.RequiresArgs {
 		// Event requires arguments - handler must have exactly one parameter of the correct type
-		if len(method.Params) != 1 {
+		if len(params) != 1 {
 			contextLines := getContextLines(htmlSource, lineNumber, 2)
-			fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Handler '%s' for '@%s' has incorrect signature.\n%s\nExpected: func(c *%s) %s(e %s)\nFound:    func(c *%s) %s(",
+			return methodDescriptor{}, fmt.Errorf("Compilation Error in %s:%d: Handler '%s' for '@%s' has incorrect signature.\n%s\nExpected: func(c *%s) %s(e %s), optionally with a leading ctx runtime.Ctx parameter\nFound:    func(c *%s) %s(%s)\n",
 				templatePath, lineNumber, handlerName, eventName, contextLines,
 				comp.PascalName, handlerName, eventSig.ArgsType,
-				comp.PascalName, handlerName)
-			for i, p := range method.Params {
-				if i > 0 {
-					fmt.Fprintf(os.Stderr, ", ")
-				}
-				fmt.Fprintf(os.Stderr, "%s %s", p.Name, p.Type)
-			}
-			fmt.Fprintf(os.Stderr, ")\n")
-			os.Exit(1)
+				comp.PascalName, handlerName, formatParams(method.Params))
 		}
 
 		// Check if the parameter type matches
-		if method.Params[0].Type != eventSig.ArgsType {
+		if params[0].Type != eventSig.ArgsType {
 			contextLines := getContextLines(htmlSource, lineNumber, 2)
-			fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Handler '%s' for '@%s' has wrong parameter type.\n%s\nExpected: func(c *%s) %s(e %s)\nFound:    func(c *%s) %s(e %s)\n",
+			return methodDescriptor{}, fmt.Errorf("Compilation Error in %s:%d: Handler '%s' for '@%s' has wrong parameter type.\n%s\nExpected: func(c *%s) %s(e %s)\nFound:    func(c *%s) %s(e %s)\n",
 				templatePath, lineNumber, handlerName, eventName, contextLines,
 				comp.PascalName, handlerName, eventSig.ArgsType,
-				comp.PascalName, handlerName, method.Params[0].Type)
-			os.Exit(1)
+				comp.PascalName, handlerName, params[0].Type)
 		}
 	} else {
 		// Event requires no arguments - handler must have zero parameters
-		if len(method.Params) != 0 {
+		if len(params) != 0 {
 			contextLines := getContextLines(htmlSource, lineNumber, 2)
-			fmt.Fprintf(os.Stderr, "Compilation Error in %s:%d: Handler '%s' for '@%s' has incorrect signature.\n%s\nExpected: func(c *%s) %s()\nFound:    func(c *%s) %s(",
+			return methodDescriptor{}, fmt.Errorf("Compilation Error in %s:%d: Handler '%s' for '@%s' has incorrect signature.\n%s\nExpected: func(c *%s) %s()\nFound:    func(c *%s) %s(%s)\n\nSuggestion: For '@%s' events on <%s>, the handler should not take any parameters.\n",
 				templatePath, lineNumber, handlerName, eventName, contextLines,
 				comp.PascalName, handlerName,
-				comp.PascalName, handlerName)
-			for i, p := range method.Params {
-				if i > 0 {
-					fmt.Fprintf(os.Stderr, ", ")
-				}
-				fmt.Fprintf(os.Stderr, "%s %s", p.Name, p.Type)
-			}
-			fmt.Fprintf(os.Stderr, ")\n\nSuggestion: For '@%s' events on <%s>, the handler should not take any parameters.\n", eventName, tagName)
-			os.Exit(1)
+				comp.PascalName, handlerName, formatParams(method.Params), eventName, tagName)
 		}
 	}
 
-	return method
+	return method, nil
+}
+
+// handlerTakesCtx reports whether an event handler uses the extended signature, whose first
+// parameter is a runtime.Ctx.
+func handlerTakesCtx(method methodDescriptor) bool {
+	return len(method.Params) > 0 && method.Params[0].Type == "runtime.Ctx"
 }
 
 // levenshteinDistance calculates the edit distance between two strings.
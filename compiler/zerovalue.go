@@ -0,0 +1,212 @@
+package compiler
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// zeroValueWarning is the rule name of the zero-value placeholder warning, for
+// <!-- nojs:ignore zero-value --> comments.
+const zeroValueWarning = "zero-value"
+
+// ignoreCommentRegex matches a warning suppression comment: <!-- nojs:ignore rule[, rule...] -->.
+var ignoreCommentRegex = regexp.MustCompile(`<!--\s*nojs:ignore\s+([-a-z,\s]+?)\s*-->`)
+
+// ignoredWarnings returns the warnings suppressed by nojs:ignore comments in src, by line: a
+// comment applies to its own line and to the line after it.
+func ignoredWarnings(src string) map[int]map[string]bool {
+	ignored := make(map[int]map[string]bool)
+	for _, m := range ignoreCommentRegex.FindAllStringSubmatchIndex(src, -1) {
+		line := strings.Count(src[:m[0]], "\n") + 1
+		for _, rule := range strings.FieldsFunc(src[m[2]:m[3]], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\n' }) {
+			for _, l := range []int{line, line + 1} {
+				if ignored[l] == nil {
+					ignored[l] = make(map[string]bool)
+				}
+				ignored[l][rule] = true
+			}
+		}
+	}
+	return ignored
+}
+
+// zeroKind classifies a Go type by how its zero value renders: "number" (0), "string" (""),
+// "bool" (false), "time" (time.Time, which has no sensible zero rendering) or "pointer";
+// "" when the type is unknown or has no zero literal (structs, slices...). Named types are
+// followed to the built-in type they are defined on.
+func zeroKind(goType string, comp componentInfo) string {
+	switch {
+	case goType == "time.Time":
+		return "time"
+	case strings.HasPrefix(goType, "*"):
+		return "pointer"
+	case goType == "":
+		return ""
+	}
+	basic := goType
+	if !isBuiltinType(goType) {
+		basic, _ = resolveNamedBasicType(goType, filepath.Dir(comp.Path))
+	}
+	switch {
+	case basic == "string":
+		return "string"
+	case basic == "bool":
+		return "bool"
+	case isBuiltinType(basic):
+		return "number"
+	}
+	return ""
+}
+
+// zeroKindLiteral returns the untyped literal a value of kind other than bool and time
+// compares equal to when zero.
+func zeroKindLiteral(kind string) string {
+	switch kind {
+	case "string":
+		return `""`
+	case "pointer":
+		return "nil"
+	}
+	return "0"
+}
+
+// zeroValueSample is what a binding of kind renders when it is zero, for warnings.
+func zeroValueSample(kind string) string {
+	if kind == "time" {
+		return "0001-01-01"
+	}
+	return "0"
+}
+
+// labelBindingRegex matches the end of static label text: a word followed by a colon,
+// optionally followed by spaces ("Year: ", "Total:").
+var labelBindingRegex = regexp.MustCompile(`([\p{L}\p{N}][\p{L}\p{N} _-]*):\s*$`)
+
+// checkZeroValuePlaceholders reports number and time bindings written after a static label
+// ("Year: {Year}") that render their zero value ("Year: 0") while the field is unset, such
+// as before a fetch completes. Bindings with a default filter, inside an {@if} block or
+// reading loop variables are not reported, nor lines with a nojs:ignore zero-value comment.
+// It is a heuristic for dev builds; the warnings are returned, not printed.
+//
+// templateSource is the template as written, which the warnings quote and locate bindings in.
+func checkZeroValuePlaceholders(root *html.Node, comp componentInfo, templateSource string) []string {
+	check := zeroValueCheck{comp: comp, source: templateSource, ignored: ignoredWarnings(templateSource), seen: make(map[string]int)}
+	var walk func(n *html.Node, guarded bool, loopVars map[string]bool)
+	walk = func(n *html.Node, guarded bool, loopVars map[string]bool) {
+		switch {
+		case n.Type == html.TextNode && !isRawTextElement(n.Parent):
+			check.text(n.Data, guarded, loopVars)
+		case n.Type == html.ElementNode && (n.Data == "go-if" || n.Data == "go-elseif" || n.Data == "go-else"):
+			guarded = true
+		case n.Type == html.ElementNode && n.Data == "go-for":
+			vars := make(map[string]bool, len(loopVars)+2)
+			for name := range loopVars {
+				vars[name] = true
+			}
+			for _, attr := range n.Attr {
+				if attr.Key == "data-index" || attr.Key == "data-value" {
+					vars[attr.Val] = true
+				}
+			}
+			loopVars = vars
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, guarded, loopVars)
+		}
+	}
+	walk(root, false, nil)
+	return check.warnings
+}
+
+// zeroValueCheck is the state of one checkZeroValuePlaceholders run.
+type zeroValueCheck struct {
+	comp     componentInfo
+	source   string
+	ignored  map[int]map[string]bool
+	seen     map[string]int // Occurrences of each "Label: {Binding}" so far, to find the line of the next one
+	warnings []string
+}
+
+// text checks the "Label: {Binding}" pairs of one text node. Guarded text is not reported,
+// but its pairs are counted so that later identical ones are located on their own line.
+func (z *zeroValueCheck) text(text string, guarded bool, loopVars map[string]bool) {
+	last := 0
+	for _, m := range textBindingRegex.FindAllStringSubmatchIndex(text, -1) {
+		before := text[last:m[0]]
+		last = m[1]
+		label := labelBindingRegex.FindStringSubmatch(before)
+		if label == nil {
+			continue
+		}
+		binding := text[m[0]:m[1]]
+		pair := label[0] + binding
+		occurrence := z.seen[pair]
+		z.seen[pair]++
+
+		fieldName := strings.TrimSpace(text[m[2]:m[3]])
+		if guarded || !fieldPathRegex.MatchString(fieldName) || loopVars[strings.SplitN(fieldName, ".", 2)[0]] || hasDefaultFilter(text, m) {
+			continue
+		}
+		kind := zeroKind(bindingGoType(fieldName, z.comp), z.comp)
+		if kind != "number" && kind != "time" {
+			continue
+		}
+		line := nthLineContaining(z.source, pair, occurrence)
+		if z.ignored[line][zeroValueWarning] {
+			continue
+		}
+		fix := strings.TrimSuffix(binding, "}") + "|default:'—'}"
+		z.warnings = append(z.warnings, fmt.Sprintf("%s:%d: '%s' follows the label '%s:' and renders '%s: %s' until %s is set.\n"+
+			"  Render a placeholder instead: %s, or guard the text with {@if}. To keep the zero value, add <!-- nojs:ignore %s --> on the line.%s",
+			z.comp.Path, line, binding, label[1], label[1], zeroValueSample(kind), fieldName, fix, zeroValueWarning, getContextLines(z.source, line, 1)))
+	}
+}
+
+// nthLineContaining returns the line of the n-th (0-based) occurrence of s in src, that of
+// its last occurrence when there are fewer, or 1 when there is none.
+func nthLineContaining(src, s string, n int) int {
+	offset, found := 0, -1
+	for i := 0; i <= n; i++ {
+		idx := strings.Index(src[offset:], s)
+		if idx < 0 {
+			break
+		}
+		found = offset + idx
+		offset = found + len(s)
+	}
+	if found < 0 {
+		return 1
+	}
+	return strings.Count(src[:found], "\n") + 1
+}
+
+// hasDefaultFilter reports whether the text binding match m of textBindingRegex has a
+// default filter, as its only filter or after formatting filters.
+func hasDefaultFilter(text string, m []int) bool {
+	return slices.ContainsFunc(parseFilterPipeline(text[m[4]:m[5]]), func(f bindingFilter) bool { return f.Name == "default" })
+}
+
+// bindingGoType returns the Go type of a component field or nested field path, or "" when it
+// cannot be resolved. Unlike resolveTextBinding it never fails the compilation.
+func bindingGoType(fieldName string, comp componentInfo) string {
+	root, _, nested := strings.Cut(fieldName, ".")
+	if nested {
+		goType, _, err := resolveNestedFieldPath(fieldName, comp, filepath.Dir(comp.Path))
+		if err != nil {
+			return ""
+		}
+		return goType
+	}
+	if desc, ok := comp.Schema.Props[strings.ToLower(root)]; ok {
+		return desc.GoType
+	}
+	if desc, ok := comp.Schema.State[strings.ToLower(root)]; ok {
+		return desc.GoType
+	}
+	return ""
+}
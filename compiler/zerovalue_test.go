@@ -0,0 +1,75 @@
+//go:build !wasm
+// +build !wasm
+
+package compiler
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// zeroValueDir is the fixture package of the zero-value placeholder warning tests.
+const zeroValueDir = "testdata/zerovalue"
+
+// compileZeroValueFixture compiles the fixture and returns the warnings it printed.
+func compileZeroValueFixture(t *testing.T, devMode bool) string {
+	t.Helper()
+	var out bytes.Buffer
+	warningOutput = &out
+	t.Cleanup(func() {
+		warningOutput = os.Stderr
+		os.Remove(filepath.Join(zeroValueDir, "Dashboard.generated.go"))
+		os.Remove(filepath.Join(zeroValueDir, "Quiet.generated.go"))
+	})
+	if err := CompileWithOptions(zeroValueDir, Options{DevMode: devMode}); err != nil {
+		t.Fatalf("expected the fixture to compile, got %v", err)
+	}
+	return out.String()
+}
+
+// TestZeroValueWarning_LabelledNumbersAndTimes verifies number and time bindings after a
+// label are reported with their line and the default filter fix.
+func TestZeroValueWarning_LabelledNumbersAndTimes(t *testing.T) {
+	// Act
+	out := compileZeroValueFixture(t, true)
+
+	// Assert
+	for _, want := range []string{
+		"Dashboard.gt.html:2: '{Year}' follows the label 'Year:' and renders 'Year: 0' until Year is set.",
+		"Render a placeholder instead: {Year|default:'—'}",
+		"Dashboard.gt.html:3: '{UpdatedAt|date:'2006-01-02'}' follows the label 'Updated:' and renders 'Updated: 0001-01-01'",
+		"{UpdatedAt|date:'2006-01-02'|default:'—'}",
+		"Dashboard.gt.html:4: '{Stats.Total|printf:'%.2f'}'",
+		"Dashboard.gt.html:5: '{Score}'",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the warnings to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestZeroValueWarning_FalsePositives verifies strings, booleans, defaults, guarded text,
+// bindings without a label, loop variables and ignored lines are not reported.
+func TestZeroValueWarning_FalsePositives(t *testing.T) {
+	// Act
+	out := compileZeroValueFixture(t, true)
+
+	// Assert
+	if strings.Contains(out, "Quiet.gt.html") {
+		t.Errorf("expected no warning for Quiet, got:\n%s", out)
+	}
+}
+
+// TestZeroValueWarning_OffInProduction verifies production compiles do not run the check.
+func TestZeroValueWarning_OffInProduction(t *testing.T) {
+	// Act
+	out := compileZeroValueFixture(t, false)
+
+	// Assert
+	if out != "" {
+		t.Errorf("expected no warnings, got:\n%s", out)
+	}
+}
@@ -0,0 +1,168 @@
+// Package browser wraps browser APIs that answer asynchronously or need the user's
+// permission, with typed results and errors. In non-WASM builds the APIs are backed by
+// scriptable fakes, so components that use them can be tested with the TestRenderer.
+package browser
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Position is a location reported by the browser.
+type Position struct {
+	Latitude  float64   // Degrees
+	Longitude float64   // Degrees
+	Accuracy  float64   // Radius of the 95% confidence circle, in meters
+	Timestamp time.Time // When the position was acquired
+}
+
+// PositionOptions tunes how the browser acquires a position.
+type PositionOptions struct {
+	HighAccuracy bool          // Prefer GPS-grade fixes, at the cost of time and battery
+	Timeout      time.Duration // Time allowed per fix; zero waits indefinitely
+	MaximumAge   time.Duration // Age of a cached fix that may be returned; zero always asks for a fresh one
+}
+
+// PermissionState is the answer of Permissions.Query.
+type PermissionState string
+
+const (
+	PermissionGranted PermissionState = "granted" // The API can be used without asking
+	PermissionDenied  PermissionState = "denied"  // The user blocked the API
+	PermissionPrompt  PermissionState = "prompt"  // The browser asks the user on first use
+)
+
+// Errors reported by Geolocation. They are returned wrapped in a *GeolocationError carrying
+// the browser's message; compare them with errors.Is.
+var (
+	ErrPermissionDenied    = errors.New("browser: geolocation permission denied")
+	ErrPositionUnavailable = errors.New("browser: position unavailable")
+	ErrTimeout             = errors.New("browser: geolocation timed out")
+	ErrUnsupported         = errors.New("browser: API not supported by this browser")
+)
+
+// GeolocationError is a failure reported by the browser's geolocation API.
+type GeolocationError struct {
+	Kind    error  // ErrPermissionDenied, ErrPositionUnavailable or ErrTimeout
+	Message string // The browser's description, possibly empty
+}
+
+func (e *GeolocationError) Error() string {
+	if e.Message == "" {
+		return e.Kind.Error()
+	}
+	return e.Kind.Error() + ": " + e.Message
+}
+
+// Unwrap returns Kind, so errors.Is(err, browser.ErrTimeout) matches.
+func (e *GeolocationError) Unwrap() error {
+	return e.Kind
+}
+
+// GeolocationAPI is the type of Geolocation.
+type GeolocationAPI struct{}
+
+// Geolocation wraps navigator.geolocation.
+var Geolocation GeolocationAPI
+
+// Current returns the current position of the device. The browser may ask the user for
+// permission first, so Current blocks until the user and the device answer: call it from a
+// goroutine, never directly from an event handler or lifecycle method, which run on the
+// event loop the answer is delivered through.
+//
+// Example:
+//
+//	func (c *StoreFinder) Locate(ctx runtime.Ctx) {
+//	    go func() {
+//	        pos, err := browser.Geolocation.Current(browser.PositionOptions{Timeout: 10 * time.Second})
+//	        ctx.SafeUpdate(func() { c.Position, c.Err = pos, err })
+//	    }()
+//	}
+func (GeolocationAPI) Current(opts PositionOptions) (Position, error) {
+	return currentPosition(opts)
+}
+
+// Watch calls onPosition each time the position of the device changes and onError when the
+// browser fails to produce one; both are dispatched on the main event loop, so they may
+// change state and call StateHasChanged. A denied permission is reported through onError
+// with ErrPermissionDenied, after which no further position arrives. onError may be nil.
+//
+// The watch stops when stop is called or when owner is destroyed, whichever comes first;
+// owner may be nil for a watch that is only stopped explicitly. err is ErrUnsupported when
+// the browser has no geolocation API.
+//
+// Example:
+//
+//	func (c *RunTracker) OnMount() {
+//	    browser.Geolocation.Watch(c, browser.PositionOptions{HighAccuracy: true}, c.moved, c.failed)
+//	}
+func (GeolocationAPI) Watch(owner runtime.Component, opts PositionOptions, onPosition func(Position), onError func(error)) (stop func(), err error) {
+	w := &geolocationWatch{onPosition: onPosition, onError: onError}
+	clear, err := watchPosition(opts, w.position, w.fail)
+	if err != nil {
+		return func() {}, err
+	}
+
+	unhook := func() bool { return false }
+	if owner != nil {
+		unhook = runtime.AfterDestroy(owner, func() { w.stop(clear) })
+	}
+	return func() {
+		unhook()
+		w.stop(clear)
+	}, nil
+}
+
+// geolocationWatch filters out the callbacks of a watch that have been stopped.
+type geolocationWatch struct {
+	mu         sync.Mutex
+	stopped    bool
+	onPosition func(Position)
+	onError    func(error)
+}
+
+func (w *geolocationWatch) position(pos Position) {
+	if w.active() && w.onPosition != nil {
+		w.onPosition(pos)
+	}
+}
+
+func (w *geolocationWatch) fail(err error) {
+	if w.active() && w.onError != nil {
+		w.onError(err)
+	}
+}
+
+func (w *geolocationWatch) active() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return !w.stopped
+}
+
+// stop marks the watch stopped and calls clear the first time it is called.
+func (w *geolocationWatch) stop(clear func()) {
+	w.mu.Lock()
+	stopped := w.stopped
+	w.stopped = true
+	w.mu.Unlock()
+
+	if !stopped {
+		clear()
+	}
+}
+
+// PermissionsAPI is the type of Permissions.
+type PermissionsAPI struct{}
+
+// Permissions wraps navigator.permissions.
+var Permissions PermissionsAPI
+
+// Query returns the state of the permission name ("geolocation", "notifications", ...)
+// without prompting the user. Like Geolocation.Current it blocks until the browser answers,
+// so call it from a goroutine. err is ErrUnsupported when the browser cannot be queried.
+func (PermissionsAPI) Query(name string) (PermissionState, error) {
+	return queryPermission(name)
+}
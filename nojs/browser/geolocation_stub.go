@@ -0,0 +1,195 @@
+//go:build !wasm
+// +build !wasm
+
+package browser
+
+import "sync"
+
+// GeolocationResult is one scripted answer of a FakeGeolocation: a position, or an error
+// when Err is set.
+type GeolocationResult struct {
+	Position Position
+	Err      error
+}
+
+// FakeGeolocation scripts Geolocation and Permissions in non-WASM builds. Install one with
+// UseFakeGeolocation; without one, both report ErrUnsupported like a browser lacking the API.
+//
+// Current takes the next scripted result. A new watch receives the scripted results still
+// queued, in order and before Watch returns, then every result passed to Push. With the
+// permission set to PermissionDenied, Current and new watches fail with ErrPermissionDenied
+// without taking results, as a browser does once the user has blocked the site.
+//
+// Example:
+//
+//	fake := browser.UseFakeGeolocation(browser.PermissionGranted,
+//	    browser.GeolocationResult{Position: browser.Position{Latitude: 52.37, Longitude: 4.89}})
+//	t.Cleanup(fake.Uninstall)
+type FakeGeolocation struct {
+	mu         sync.Mutex
+	permission PermissionState
+	queue      []GeolocationResult
+	watches    map[*fakeWatch]struct{}
+}
+
+// fakeWatch is an active watch of a FakeGeolocation.
+type fakeWatch struct {
+	onPosition func(Position)
+	onError    func(error)
+}
+
+var (
+	fakeMu          sync.Mutex
+	fakeGeolocation *FakeGeolocation // Installed by UseFakeGeolocation
+)
+
+// UseFakeGeolocation installs a FakeGeolocation reporting permission and answering with
+// results, replacing any installed before. Tests using it must not run in parallel.
+func UseFakeGeolocation(permission PermissionState, results ...GeolocationResult) *FakeGeolocation {
+	f := &FakeGeolocation{
+		permission: permission,
+		queue:      append([]GeolocationResult(nil), results...),
+		watches:    make(map[*fakeWatch]struct{}),
+	}
+	fakeMu.Lock()
+	fakeGeolocation = f
+	fakeMu.Unlock()
+	return f
+}
+
+// Uninstall removes f if it is the installed fake.
+func (f *FakeGeolocation) Uninstall() {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	if fakeGeolocation == f {
+		fakeGeolocation = nil
+	}
+}
+
+// SetPermission changes the state reported by Permissions.Query and checked by later calls.
+func (f *FakeGeolocation) SetPermission(permission PermissionState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.permission = permission
+}
+
+// Push delivers results to every active watch, in order. Without an active watch they are
+// queued for the next Current or Watch.
+func (f *FakeGeolocation) Push(results ...GeolocationResult) {
+	f.mu.Lock()
+	watches := make([]*fakeWatch, 0, len(f.watches))
+	for w := range f.watches {
+		watches = append(watches, w)
+	}
+	if len(watches) == 0 {
+		f.queue = append(f.queue, results...)
+	}
+	f.mu.Unlock()
+
+	for _, w := range watches {
+		for _, r := range results {
+			f.deliver(w, r)
+		}
+	}
+}
+
+// Watching returns the number of watches not yet stopped.
+func (f *FakeGeolocation) Watching() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.watches)
+}
+
+// deliver hands r to w unless w has been stopped meanwhile.
+func (f *FakeGeolocation) deliver(w *fakeWatch, r GeolocationResult) {
+	f.mu.Lock()
+	_, active := f.watches[w]
+	f.mu.Unlock()
+	if !active {
+		return
+	}
+	if r.Err != nil {
+		w.onError(r.Err)
+		return
+	}
+	w.onPosition(r.Position)
+}
+
+// installedFake returns the installed fake, or nil.
+func installedFake() *FakeGeolocation {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	return fakeGeolocation
+}
+
+// deniedError is the error of a fake whose permission is denied.
+func deniedError() error {
+	return &GeolocationError{Kind: ErrPermissionDenied, Message: "User denied Geolocation"}
+}
+
+// currentPosition answers with the next scripted result.
+func currentPosition(opts PositionOptions) (Position, error) {
+	f := installedFake()
+	if f == nil {
+		return Position{}, ErrUnsupported
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.permission == PermissionDenied:
+		return Position{}, deniedError()
+	case len(f.queue) == 0:
+		return Position{}, &GeolocationError{Kind: ErrPositionUnavailable, Message: "no scripted position left"}
+	}
+	r := f.queue[0]
+	f.queue = f.queue[1:]
+	return r.Position, r.Err
+}
+
+// watchPosition registers a watch and delivers the queued results to it.
+func watchPosition(opts PositionOptions, onPosition func(Position), onError func(error)) (func(), error) {
+	f := installedFake()
+	if f == nil {
+		return nil, ErrUnsupported
+	}
+	w := &fakeWatch{onPosition: onPosition, onError: onError}
+	clear := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.watches, w)
+	}
+
+	f.mu.Lock()
+	if f.permission == PermissionDenied {
+		f.mu.Unlock()
+		onError(deniedError())
+		return clear, nil
+	}
+	f.watches[w] = struct{}{}
+	queued := f.queue
+	f.queue = nil
+	f.mu.Unlock()
+
+	for _, r := range queued {
+		f.deliver(w, r)
+	}
+	return clear, nil
+}
+
+// queryPermission reports the permission of the installed fake; names other than
+// "geolocation" report PermissionPrompt.
+func queryPermission(name string) (PermissionState, error) {
+	f := installedFake()
+	if f == nil {
+		return "", ErrUnsupported
+	}
+	if name != "geolocation" {
+		return PermissionPrompt, nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.permission == "" {
+		return PermissionPrompt, nil
+	}
+	return f.permission, nil
+}
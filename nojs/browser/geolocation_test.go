@@ -0,0 +1,139 @@
+//go:build !wasm
+// +build !wasm
+
+package browser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// owner is a component that can be destroyed.
+type owner struct {
+	runtime.ComponentBase
+}
+
+func (o *owner) Render(r runtime.Renderer) *vdom.VNode { return nil }
+
+// TestGeolocation_CurrentTakesScriptedResultsInOrder verifies Current answers with the scripted
+// positions and errors in order, then reports the position as unavailable.
+func TestGeolocation_CurrentTakesScriptedResultsInOrder(t *testing.T) {
+	// Arrange
+	fake := UseFakeGeolocation(PermissionGranted,
+		GeolocationResult{Position: Position{Latitude: 52.37, Longitude: 4.89}},
+		GeolocationResult{Err: &GeolocationError{Kind: ErrTimeout}})
+	t.Cleanup(fake.Uninstall)
+
+	// Act
+	first, firstErr := Geolocation.Current(PositionOptions{})
+	_, secondErr := Geolocation.Current(PositionOptions{})
+	_, thirdErr := Geolocation.Current(PositionOptions{})
+
+	// Assert
+	if firstErr != nil || first.Latitude != 52.37 || first.Longitude != 4.89 {
+		t.Errorf("expected the first scripted position, got %+v, %v", first, firstErr)
+	}
+	if !errors.Is(secondErr, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", secondErr)
+	}
+	if !errors.Is(thirdErr, ErrPositionUnavailable) {
+		t.Errorf("expected ErrPositionUnavailable once the script is exhausted, got %v", thirdErr)
+	}
+}
+
+// TestGeolocation_DeniedPermission verifies a denied permission is reported by Query, Current
+// and Watch.
+func TestGeolocation_DeniedPermission(t *testing.T) {
+	// Arrange
+	fake := UseFakeGeolocation(PermissionDenied, GeolocationResult{Position: Position{Latitude: 1}})
+	t.Cleanup(fake.Uninstall)
+	var watchErr error
+
+	// Act
+	state, queryErr := Permissions.Query("geolocation")
+	_, currentErr := Geolocation.Current(PositionOptions{})
+	_, err := Geolocation.Watch(nil, PositionOptions{}, func(Position) {}, func(err error) { watchErr = err })
+
+	// Assert
+	if state != PermissionDenied || queryErr != nil {
+		t.Errorf("expected Query to report denied, got %q, %v", state, queryErr)
+	}
+	if !errors.Is(currentErr, ErrPermissionDenied) {
+		t.Errorf("expected Current to fail with ErrPermissionDenied, got %v", currentErr)
+	}
+	if err != nil || !errors.Is(watchErr, ErrPermissionDenied) {
+		t.Errorf("expected the watch to report ErrPermissionDenied, got %v, %v", err, watchErr)
+	}
+}
+
+// TestGeolocation_WatchStopsWhenOwnerIsDestroyed verifies a watch receives pushed positions
+// until its owning component is destroyed.
+func TestGeolocation_WatchStopsWhenOwnerIsDestroyed(t *testing.T) {
+	// Arrange
+	fake := UseFakeGeolocation(PermissionGranted, GeolocationResult{Position: Position{Latitude: 1}})
+	t.Cleanup(fake.Uninstall)
+	comp := &owner{}
+	var latitudes []float64
+	_, err := Geolocation.Watch(comp, PositionOptions{}, func(p Position) {
+		latitudes = append(latitudes, p.Latitude)
+	}, nil)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	fake.Push(GeolocationResult{Position: Position{Latitude: 2}})
+
+	// Act
+	runtime.Destroy(comp)
+	fake.Push(GeolocationResult{Position: Position{Latitude: 3}})
+
+	// Assert
+	if len(latitudes) != 2 || latitudes[0] != 1 || latitudes[1] != 2 {
+		t.Errorf("expected positions 1 and 2, got %v", latitudes)
+	}
+	if fake.Watching() != 0 {
+		t.Errorf("expected the watch to be cleared, %d still active", fake.Watching())
+	}
+}
+
+// TestGeolocation_StopClearsWatch verifies stop ends the watch and later destruction of the
+// owner does nothing more.
+func TestGeolocation_StopClearsWatch(t *testing.T) {
+	// Arrange
+	fake := UseFakeGeolocation(PermissionGranted)
+	t.Cleanup(fake.Uninstall)
+	comp := &owner{}
+	received := 0
+	stop, _ := Geolocation.Watch(comp, PositionOptions{}, func(Position) { received++ }, nil)
+
+	// Act
+	stop()
+	fake.Push(GeolocationResult{Position: Position{Latitude: 1}})
+	runtime.Destroy(comp)
+
+	// Assert
+	if received != 0 {
+		t.Errorf("expected no position after stop, got %d", received)
+	}
+	if fake.Watching() != 0 {
+		t.Errorf("expected the watch to be cleared, %d still active", fake.Watching())
+	}
+}
+
+// TestGeolocation_UnsupportedWithoutFake verifies the APIs report ErrUnsupported when no fake
+// is installed.
+func TestGeolocation_UnsupportedWithoutFake(t *testing.T) {
+	// Act
+	_, currentErr := Geolocation.Current(PositionOptions{})
+	_, watchErr := Geolocation.Watch(nil, PositionOptions{}, func(Position) {}, nil)
+	_, queryErr := Permissions.Query("geolocation")
+
+	// Assert
+	for _, err := range []error{currentErr, watchErr, queryErr} {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("expected ErrUnsupported, got %v", err)
+		}
+	}
+}
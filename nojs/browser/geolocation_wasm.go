@@ -0,0 +1,137 @@
+//go:build js || wasm
+// +build js wasm
+
+package browser
+
+import (
+	"syscall/js"
+	"time"
+)
+
+// geolocationObject returns navigator.geolocation, or ErrUnsupported when it is missing
+// (insecure origins and some embedded browsers).
+func geolocationObject() (js.Value, error) {
+	geo := js.Global().Get("navigator").Get("geolocation")
+	if geo.IsUndefined() || geo.IsNull() {
+		return js.Value{}, ErrUnsupported
+	}
+	return geo, nil
+}
+
+// currentPosition wraps getCurrentPosition and waits for its answer.
+func currentPosition(opts PositionOptions) (Position, error) {
+	geo, err := geolocationObject()
+	if err != nil {
+		return Position{}, err
+	}
+
+	type result struct {
+		pos Position
+		err error
+	}
+	done := make(chan result, 1)
+	success := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- result{pos: positionFromJS(args[0])}
+		return nil
+	})
+	failure := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- result{err: errorFromJS(args[0])}
+		return nil
+	})
+	defer success.Release()
+	defer failure.Release()
+
+	geo.Call("getCurrentPosition", success, failure, optionsToJS(opts))
+	r := <-done
+	return r.pos, r.err
+}
+
+// watchPosition wraps watchPosition. The returned function clears the watch and releases
+// its callbacks.
+func watchPosition(opts PositionOptions, onPosition func(Position), onError func(error)) (func(), error) {
+	geo, err := geolocationObject()
+	if err != nil {
+		return nil, err
+	}
+
+	success := js.FuncOf(func(this js.Value, args []js.Value) any {
+		onPosition(positionFromJS(args[0]))
+		return nil
+	})
+	failure := js.FuncOf(func(this js.Value, args []js.Value) any {
+		onError(errorFromJS(args[0]))
+		return nil
+	})
+
+	id := geo.Call("watchPosition", success, failure, optionsToJS(opts))
+	return func() {
+		geo.Call("clearWatch", id)
+		success.Release()
+		failure.Release()
+	}, nil
+}
+
+// queryPermission wraps navigator.permissions.query and waits for its promise.
+func queryPermission(name string) (PermissionState, error) {
+	permissions := js.Global().Get("navigator").Get("permissions")
+	if permissions.IsUndefined() || permissions.IsNull() {
+		return "", ErrUnsupported
+	}
+
+	type result struct {
+		state PermissionState
+		err   error
+	}
+	done := make(chan result, 1)
+	resolved := js.FuncOf(func(this js.Value, args []js.Value) any {
+		done <- result{state: PermissionState(args[0].Get("state").String())}
+		return nil
+	})
+	rejected := js.FuncOf(func(this js.Value, args []js.Value) any {
+		// The browser does not know the permission name
+		done <- result{err: ErrUnsupported}
+		return nil
+	})
+	defer resolved.Release()
+	defer rejected.Release()
+
+	permissions.Call("query", map[string]any{"name": name}).Call("then", resolved, rejected)
+	r := <-done
+	return r.state, r.err
+}
+
+// optionsToJS converts opts to a PositionOptions dictionary; a zero Timeout is left out so
+// the browser waits indefinitely.
+func optionsToJS(opts PositionOptions) map[string]any {
+	dict := map[string]any{
+		"enableHighAccuracy": opts.HighAccuracy,
+		"maximumAge":         opts.MaximumAge.Milliseconds(),
+	}
+	if opts.Timeout > 0 {
+		dict["timeout"] = opts.Timeout.Milliseconds()
+	}
+	return dict
+}
+
+// positionFromJS converts a GeolocationPosition.
+func positionFromJS(v js.Value) Position {
+	coords := v.Get("coords")
+	return Position{
+		Latitude:  coords.Get("latitude").Float(),
+		Longitude: coords.Get("longitude").Float(),
+		Accuracy:  coords.Get("accuracy").Float(),
+		Timestamp: time.UnixMilli(int64(v.Get("timestamp").Float())),
+	}
+}
+
+// errorFromJS converts a GeolocationPositionError to a *GeolocationError.
+func errorFromJS(v js.Value) error {
+	kind := ErrPositionUnavailable
+	switch v.Get("code").Int() {
+	case 1: // PERMISSION_DENIED
+		kind = ErrPermissionDenied
+	case 3: // TIMEOUT
+		kind = ErrTimeout
+	}
+	return &GeolocationError{Kind: kind, Message: v.Get("message").String()}
+}
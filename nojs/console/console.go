@@ -6,17 +6,8 @@ import (
 	"syscall/js"
 )
 
-func Log(args ...any) {
+// write calls the browser console method (log, debug, warn, ...) with args.
+func write(method string, args []any) {
 	console := js.Global().Get("console")
-	console.Call("log", args...)
-}
-
-func Warn(args ...any) {
-	console := js.Global().Get("console")
-	console.Call("warn", args...)
-}
-
-func Error(args ...any) {
-	console := js.Global().Get("console")
-	console.Call("error", args...)
+	console.Call(method, args...)
 }
@@ -0,0 +1,151 @@
+package console
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Level is the severity of a console message. Messages below the level set with SetLevel
+// are dropped before anything is formatted.
+type Level int32
+
+const (
+	LevelDebug Level = iota // Framework internals, such as each navigation step of the router
+	LevelInfo               // Regular application output; Log writes at this level
+	LevelWarn               // Something is likely wrong but the app carries on
+	LevelError              // An operation failed
+	LevelOff                // Drops every message
+)
+
+// String returns the name of the level ("debug", "info", ...).
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelOff:
+		return "off"
+	}
+	return fmt.Sprintf("Level(%d)", int32(l))
+}
+
+// sink writes the messages that pass the level: write, or a recorder in tests.
+var sink = write
+
+// currentLevel is the lowest level written. It starts at defaultLevel: LevelDebug in dev
+// builds (-tags dev), LevelWarn in production builds.
+var currentLevel atomic.Int32
+
+func init() {
+	currentLevel.Store(int32(defaultLevel))
+}
+
+// SetLevel sets the lowest level written; messages below it are dropped. It applies to
+// every Logger and to the package functions. Call it first thing in main, before nojs.Run,
+// to see debug output in a production build or to silence a dev build.
+func SetLevel(l Level) {
+	currentLevel.Store(int32(l))
+}
+
+// CurrentLevel returns the lowest level written.
+func CurrentLevel() Level {
+	return Level(currentLevel.Load())
+}
+
+// Enabled reports whether messages at l are written. Guard work done only to build a
+// message with it, or pass the work as a Lazy argument.
+func Enabled(l Level) bool {
+	return l < LevelOff && l >= CurrentLevel()
+}
+
+// Lazy is a message argument computed only when the message is written:
+//
+//	console.Debug("[vdom] patched", console.Lazy(func() string { return describe(tree) }))
+type Lazy func() string
+
+// String computes the argument.
+func (f Lazy) String() string {
+	return f()
+}
+
+// Debug writes a message at debug level.
+func Debug(args ...any) {
+	output(LevelDebug, args)
+}
+
+// Info writes a message at info level.
+func Info(args ...any) {
+	output(LevelInfo, args)
+}
+
+// Log writes a message at info level, through console.log.
+func Log(args ...any) {
+	if Enabled(LevelInfo) {
+		sink("log", resolve(args))
+	}
+}
+
+// Warn writes a message at warning level.
+func Warn(args ...any) {
+	output(LevelWarn, args)
+}
+
+// Error writes a message at error level.
+func Error(args ...any) {
+	output(LevelError, args)
+}
+
+// Debugf writes a message formatted with fmt.Sprintf at debug level. The message is not
+// formatted when debug output is off.
+func Debugf(format string, args ...any) {
+	outputf(LevelDebug, format, args)
+}
+
+// Infof writes a message formatted with fmt.Sprintf at info level.
+func Infof(format string, args ...any) {
+	outputf(LevelInfo, format, args)
+}
+
+// Warnf writes a message formatted with fmt.Sprintf at warning level.
+func Warnf(format string, args ...any) {
+	outputf(LevelWarn, format, args)
+}
+
+// Errorf writes a message formatted with fmt.Sprintf at error level.
+func Errorf(format string, args ...any) {
+	outputf(LevelError, format, args)
+}
+
+// output writes args through the console method of level l, unless l is filtered out.
+func output(l Level, args []any) {
+	if Enabled(l) {
+		sink(l.String(), resolve(args))
+	}
+}
+
+// outputf formats and writes a message at level l, unless l is filtered out.
+func outputf(l Level, format string, args []any) {
+	if Enabled(l) {
+		sink(l.String(), []any{fmt.Sprintf(format, args...)})
+	}
+}
+
+// resolve computes the Lazy arguments of args, in a copy when there are any.
+func resolve(args []any) []any {
+	copied := false
+	for i, arg := range args {
+		if lazy, ok := arg.(Lazy); ok {
+			if !copied {
+				args = append([]any(nil), args...)
+				copied = true
+			}
+			args[i] = lazy()
+		}
+	}
+	return args
+}
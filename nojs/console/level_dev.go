@@ -0,0 +1,7 @@
+//go:build dev
+// +build dev
+
+package console
+
+// defaultLevel shows every message in dev builds, framework debug output included.
+const defaultLevel = LevelDebug
@@ -0,0 +1,7 @@
+//go:build !dev
+// +build !dev
+
+package console
+
+// defaultLevel keeps production consoles to warnings and errors.
+const defaultLevel = LevelWarn
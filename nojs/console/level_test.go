@@ -0,0 +1,87 @@
+//go:build !wasm
+// +build !wasm
+
+package console
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// recordConsole replaces the sink with a recorder and sets level for the test.
+func recordConsole(t *testing.T, level Level) *[]string {
+	t.Helper()
+	var lines []string
+	previousSink, previousLevel := sink, CurrentLevel()
+	sink = func(method string, args []any) {
+		lines = append(lines, method+": "+strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	}
+	SetLevel(level)
+	t.Cleanup(func() {
+		sink = previousSink
+		SetLevel(previousLevel)
+	})
+	return &lines
+}
+
+// TestSetLevel_FiltersBelowLevel verifies messages below the level are dropped and the
+// others go through the console method of their level.
+func TestSetLevel_FiltersBelowLevel(t *testing.T) {
+	// Arrange
+	lines := recordConsole(t, LevelWarn)
+
+	// Act
+	Debug("step")
+	Info("ready")
+	Log("value")
+	Warn("slow")
+	Errorf("failed after %d tries", 3)
+
+	// Assert
+	want := fmt.Sprint([]string{"warn: slow", "error: failed after 3 tries"})
+	if got := fmt.Sprint(*lines); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+// TestLazy_SkippedWhenFiltered verifies lazy arguments and formatting run only for messages
+// that are written.
+func TestLazy_SkippedWhenFiltered(t *testing.T) {
+	// Arrange
+	lines := recordConsole(t, LevelInfo)
+	calls := 0
+	expensive := Lazy(func() string { calls++; return "tree" })
+
+	// Act
+	Debug("dump", expensive)
+	Debugf("dump %s", expensive)
+	Info("dump", expensive)
+
+	// Assert
+	if calls != 1 {
+		t.Errorf("expected the lazy argument computed once, for Info, got %d", calls)
+	}
+	if len(*lines) != 1 || (*lines)[0] != "info: dump tree" {
+		t.Errorf("expected the info message with the computed argument, got %v", *lines)
+	}
+}
+
+// TestLogger_PrefixesAndFilters verifies a Logger prefixes its messages and follows the level.
+func TestLogger_PrefixesAndFilters(t *testing.T) {
+	// Arrange
+	lines := recordConsole(t, LevelDebug)
+	log := NewLogger("Engine")
+
+	// Act
+	log.Debugf("navigating to %s (100%%)", "/a")
+	log.Warn("slow")
+	SetLevel(LevelOff)
+	log.Error("hidden")
+
+	// Assert
+	want := fmt.Sprint([]string{"debug: [Engine] navigating to /a (100%)", "warn: [Engine] slow"})
+	if got := fmt.Sprint(*lines); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,89 @@
+package console
+
+import "strings"
+
+// Logger writes to the browser console with a fixed prefix, so that output from several
+// nojs apps on one page, or from the modules of the framework, can be told apart. Its
+// messages are filtered by the level set with SetLevel. The zero value logs without a prefix.
+type Logger struct {
+	prefix string
+}
+
+// NewLogger returns a Logger that prefixes every message with "[name]".
+func NewLogger(name string) Logger {
+	if name == "" {
+		return Logger{}
+	}
+	return Logger{prefix: "[" + name + "]"}
+}
+
+// Debug writes a prefixed message at debug level.
+func (l Logger) Debug(args ...any) {
+	if Enabled(LevelDebug) {
+		Debug(l.withPrefix(args)...)
+	}
+}
+
+// Info writes a prefixed message at info level.
+func (l Logger) Info(args ...any) {
+	if Enabled(LevelInfo) {
+		Info(l.withPrefix(args)...)
+	}
+}
+
+// Log writes a prefixed message at info level, through console.log.
+func (l Logger) Log(args ...any) {
+	if Enabled(LevelInfo) {
+		Log(l.withPrefix(args)...)
+	}
+}
+
+// Warn writes a prefixed message at warning level.
+func (l Logger) Warn(args ...any) {
+	if Enabled(LevelWarn) {
+		Warn(l.withPrefix(args)...)
+	}
+}
+
+// Error writes a prefixed message at error level.
+func (l Logger) Error(args ...any) {
+	if Enabled(LevelError) {
+		Error(l.withPrefix(args)...)
+	}
+}
+
+// Debugf writes a prefixed message formatted with fmt.Sprintf at debug level. The message
+// is not formatted when debug output is off.
+func (l Logger) Debugf(format string, args ...any) {
+	outputf(LevelDebug, l.formatPrefix()+format, args)
+}
+
+// Infof writes a prefixed message formatted with fmt.Sprintf at info level.
+func (l Logger) Infof(format string, args ...any) {
+	outputf(LevelInfo, l.formatPrefix()+format, args)
+}
+
+// Warnf writes a prefixed message formatted with fmt.Sprintf at warning level.
+func (l Logger) Warnf(format string, args ...any) {
+	outputf(LevelWarn, l.formatPrefix()+format, args)
+}
+
+// Errorf writes a prefixed message formatted with fmt.Sprintf at error level.
+func (l Logger) Errorf(format string, args ...any) {
+	outputf(LevelError, l.formatPrefix()+format, args)
+}
+
+// formatPrefix returns the prefix followed by a space, escaped for use in a format string.
+func (l Logger) formatPrefix() string {
+	if l.prefix == "" {
+		return ""
+	}
+	return strings.ReplaceAll(l.prefix, "%", "%%") + " "
+}
+
+func (l Logger) withPrefix(args []any) []any {
+	if l.prefix == "" {
+		return args
+	}
+	return append([]any{l.prefix}, args...)
+}
@@ -0,0 +1,14 @@
+package console
+
+import "sync"
+
+// warnedKeys records the keys WarnOnce has already reported.
+var warnedKeys sync.Map
+
+// WarnOnce writes a warning the first time it is called with key and ignores later calls
+// with the same key. Generated dev-mode code uses it for warnings raised on every render.
+func WarnOnce(key string, args ...any) {
+	if _, seen := warnedKeys.LoadOrStore(key, struct{}{}); !seen {
+		Warn(args...)
+	}
+}
@@ -2,99 +2,432 @@
 
 package events
 
-import "syscall/js"
+import (
+	"reflect"
+	goruntime "runtime"
+	"strings"
+	"syscall/js"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
 
 // AdaptClickEvent creates a JavaScript-compatible event handler from a Go handler
 // that expects ClickEventArgs. This is used for @onclick events with event arguments.
 func AdaptClickEvent(handler func(ClickEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
 	return func(e js.Value) {
-		args := ClickEventArgs{
-			EventBase: NewEventBase(e),
-			ClientX:   e.Get("clientX").Int(),
-			ClientY:   e.Get("clientY").Int(),
-			Button:    e.Get("button").Int(),
-			AltKey:    e.Get("altKey").Bool(),
-			CtrlKey:   e.Get("ctrlKey").Bool(),
-			ShiftKey:  e.Get("shiftKey").Bool(),
-			MetaKey:   e.Get("metaKey").Bool(),
-		}
-		handler(args)
+		dispatch(owner, handler, func() { handler(newClickEventArgs(e)) })
 	}
 }
 
 // AdaptChangeEvent creates a JavaScript-compatible event handler from a Go handler
 // that expects ChangeEventArgs. This is used for @oninput and @onchange events.
 func AdaptChangeEvent(handler func(ChangeEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
 	return func(e js.Value) {
-		args := ChangeEventArgs{
-			EventBase: NewEventBase(e),
-			Value:     e.Get("target").Get("value").String(),
-		}
-		handler(args)
+		dispatch(owner, handler, func() { handler(newChangeEventArgs(e)) })
 	}
 }
 
 // AdaptKeyboardEvent creates a JavaScript-compatible event handler from a Go handler
 // that expects KeyboardEventArgs. This is used for @onkeydown, @onkeyup, @onkeypress events.
 func AdaptKeyboardEvent(handler func(KeyboardEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
 	return func(e js.Value) {
-		args := KeyboardEventArgs{
-			EventBase: NewEventBase(e),
-			Key:       e.Get("key").String(),
-			Code:      e.Get("code").String(),
-			AltKey:    e.Get("altKey").Bool(),
-			CtrlKey:   e.Get("ctrlKey").Bool(),
-			ShiftKey:  e.Get("shiftKey").Bool(),
-			MetaKey:   e.Get("metaKey").Bool(),
-		}
-		handler(args)
+		dispatch(owner, handler, func() { handler(newKeyboardEventArgs(e)) })
 	}
 }
 
 // AdaptMouseEvent creates a JavaScript-compatible event handler from a Go handler
-// that expects MouseEventArgs. This is used for @onmousedown, @onmouseup, @onmousemove events.
+// that expects MouseEventArgs. This is used for @onmousedown, @onmouseup, @onmousemove, @onmouseenter, @onmouseleave events.
 func AdaptMouseEvent(handler func(MouseEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
 	return func(e js.Value) {
-		args := MouseEventArgs{
-			EventBase: NewEventBase(e),
-			ClientX:   e.Get("clientX").Int(),
-			ClientY:   e.Get("clientY").Int(),
-			Button:    e.Get("button").Int(),
-			AltKey:    e.Get("altKey").Bool(),
-			CtrlKey:   e.Get("ctrlKey").Bool(),
-			ShiftKey:  e.Get("shiftKey").Bool(),
-			MetaKey:   e.Get("metaKey").Bool(),
-		}
-		handler(args)
+		dispatch(owner, handler, func() { handler(newMouseEventArgs(e)) })
 	}
 }
 
 // AdaptFocusEvent creates a JavaScript-compatible event handler from a Go handler
 // that expects FocusEventArgs. This is used for @onfocus and @onblur events.
 func AdaptFocusEvent(handler func(FocusEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
 	return func(e js.Value) {
-		args := FocusEventArgs{
-			EventBase: NewEventBase(e),
-		}
-		handler(args)
+		dispatch(owner, handler, func() { handler(newFocusEventArgs(e)) })
 	}
 }
 
 // AdaptFormEvent creates a JavaScript-compatible event handler from a Go handler
 // that expects FormEventArgs. This is used for @onsubmit events.
 func AdaptFormEvent(handler func(FormEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
 	return func(e js.Value) {
-		args := FormEventArgs{
-			EventBase: NewEventBase(e),
-		}
-		handler(args)
+		dispatch(owner, handler, func() { handler(newFormEventArgs(e)) })
 	}
 }
 
 // AdaptNoArgEvent creates a JavaScript-compatible event handler from a Go handler
 // that expects no arguments. This is used for @onclick events.
 func AdaptNoArgEvent(handler func()) func(js.Value) {
+	owner := runtime.RenderingComponent()
+	return func(e js.Value) {
+		dispatch(owner, handler, handler)
+	}
+}
+
+// AdaptDragEvent creates a JavaScript-compatible event handler from a Go handler
+// that expects DragEventArgs. This is used for the drag and drop events (@ondragstart, @ondrop, ...).
+func AdaptDragEvent(handler func(DragEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
+	return func(e js.Value) {
+		dispatch(owner, handler, func() { handler(newDragEventArgs(e)) })
+	}
+}
+
+// AdaptWheelEvent creates a JavaScript-compatible event handler from a Go handler
+// that expects WheelEventArgs. This is used for @onwheel events.
+func AdaptWheelEvent(handler func(WheelEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
+	return func(e js.Value) {
+		dispatch(owner, handler, func() { handler(newWheelEventArgs(e)) })
+	}
+}
+
+// AdaptTouchEvent creates a JavaScript-compatible event handler from a Go handler
+// that expects TouchEventArgs. This is used for @ontouchstart, @ontouchmove, @ontouchend and @ontouchcancel events.
+func AdaptTouchEvent(handler func(TouchEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
+	return func(e js.Value) {
+		dispatch(owner, handler, func() { handler(newTouchEventArgs(e)) })
+	}
+}
+
+// AdaptScrollEvent creates a JavaScript-compatible event handler from a Go handler
+// that expects ScrollEventArgs. This is used for @onscroll events.
+func AdaptScrollEvent(handler func(ScrollEventArgs)) func(js.Value) {
+	owner := runtime.RenderingComponent()
+	return func(e js.Value) {
+		dispatch(owner, handler, func() { handler(newScrollEventArgs(e)) })
+	}
+}
+
+// The Ctx variants adapt handlers declared with a leading runtime.Ctx parameter. The Ctx of
+// component c is built each time the event fires (see runtime.NewCtx).
+
+// AdaptClickEventCtx adapts func(runtime.Ctx, ClickEventArgs) handlers of component c.
+func AdaptClickEventCtx(c runtime.Component, handler func(runtime.Ctx, ClickEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newClickEventArgs(e)) })
+	}
+}
+
+// AdaptChangeEventCtx adapts func(runtime.Ctx, ChangeEventArgs) handlers of component c.
+func AdaptChangeEventCtx(c runtime.Component, handler func(runtime.Ctx, ChangeEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newChangeEventArgs(e)) })
+	}
+}
+
+// AdaptKeyboardEventCtx adapts func(runtime.Ctx, KeyboardEventArgs) handlers of component c.
+func AdaptKeyboardEventCtx(c runtime.Component, handler func(runtime.Ctx, KeyboardEventArgs)) func(js.Value) {
 	return func(e js.Value) {
-		handler()
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newKeyboardEventArgs(e)) })
+	}
+}
+
+// AdaptMouseEventCtx adapts func(runtime.Ctx, MouseEventArgs) handlers of component c.
+func AdaptMouseEventCtx(c runtime.Component, handler func(runtime.Ctx, MouseEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newMouseEventArgs(e)) })
+	}
+}
+
+// AdaptFocusEventCtx adapts func(runtime.Ctx, FocusEventArgs) handlers of component c.
+func AdaptFocusEventCtx(c runtime.Component, handler func(runtime.Ctx, FocusEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newFocusEventArgs(e)) })
+	}
+}
+
+// AdaptFormEventCtx adapts func(runtime.Ctx, FormEventArgs) handlers of component c.
+func AdaptFormEventCtx(c runtime.Component, handler func(runtime.Ctx, FormEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newFormEventArgs(e)) })
+	}
+}
+
+// AdaptNoArgEventCtx adapts func(runtime.Ctx) handlers of component c.
+func AdaptNoArgEventCtx(c runtime.Component, handler func(runtime.Ctx)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c)) })
+	}
+}
+
+// AdaptDragEventCtx adapts func(runtime.Ctx, DragEventArgs) handlers of component c.
+func AdaptDragEventCtx(c runtime.Component, handler func(runtime.Ctx, DragEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newDragEventArgs(e)) })
+	}
+}
+
+// AdaptWheelEventCtx adapts func(runtime.Ctx, WheelEventArgs) handlers of component c.
+func AdaptWheelEventCtx(c runtime.Component, handler func(runtime.Ctx, WheelEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newWheelEventArgs(e)) })
+	}
+}
+
+// AdaptTouchEventCtx adapts func(runtime.Ctx, TouchEventArgs) handlers of component c.
+func AdaptTouchEventCtx(c runtime.Component, handler func(runtime.Ctx, TouchEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newTouchEventArgs(e)) })
+	}
+}
+
+// AdaptScrollEventCtx adapts func(runtime.Ctx, ScrollEventArgs) handlers of component c.
+func AdaptScrollEventCtx(c runtime.Component, handler func(runtime.Ctx, ScrollEventArgs)) func(js.Value) {
+	return func(e js.Value) {
+		dispatch(c, handler, func() { handler(runtime.NewCtx(c), newScrollEventArgs(e)) })
+	}
+}
+
+// newEventTarget reads the element the handler is bound to (event.currentTarget, or
+// event.target when there is none). Targets that are not elements, such as the document of
+// a scroll event, leave the fields empty.
+func newEventTarget(e js.Value) EventTarget {
+	el := e.Get("currentTarget")
+	if !el.Truthy() {
+		el = e.Get("target")
+	}
+	if !el.Truthy() {
+		return EventTarget{}
+	}
+	target := EventTarget{
+		TargetID:      stringProperty(el, "id"),
+		TargetTagName: strings.ToLower(stringProperty(el, "tagName")),
+		TargetValue:   stringProperty(el, "value"),
+	}
+	if dataset := el.Get("dataset"); dataset.Type() == js.TypeObject {
+		keys := js.Global().Get("Object").Call("keys", dataset)
+		if n := keys.Length(); n > 0 {
+			target.Dataset = make(map[string]string, n)
+			for i := 0; i < n; i++ {
+				key := keys.Index(i).String()
+				target.Dataset[key] = dataset.Get(key).String()
+			}
+		}
+	}
+	return target
+}
+
+// stringProperty returns the property name of v when it is a string, and "" otherwise.
+func stringProperty(v js.Value, name string) string {
+	if prop := v.Get(name); prop.Type() == js.TypeString {
+		return prop.String()
+	}
+	return ""
+}
+
+func newFocusEventArgs(e js.Value) FocusEventArgs {
+	return FocusEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+	}
+}
+
+func newFormEventArgs(e js.Value) FormEventArgs {
+	return FormEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+	}
+}
+
+func newClickEventArgs(e js.Value) ClickEventArgs {
+	return ClickEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+		ClientX:     e.Get("clientX").Int(),
+		ClientY:     e.Get("clientY").Int(),
+		Button:      e.Get("button").Int(),
+		AltKey:      e.Get("altKey").Bool(),
+		CtrlKey:     e.Get("ctrlKey").Bool(),
+		ShiftKey:    e.Get("shiftKey").Bool(),
+		MetaKey:     e.Get("metaKey").Bool(),
+	}
+}
+
+func newChangeEventArgs(e js.Value) ChangeEventArgs {
+	return ChangeEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+		Value:       e.Get("target").Get("value").String(),
+		Checked:     e.Get("target").Get("checked").Truthy(),
+	}
+}
+
+func newKeyboardEventArgs(e js.Value) KeyboardEventArgs {
+	return KeyboardEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+		Key:         e.Get("key").String(),
+		Code:        e.Get("code").String(),
+		AltKey:      e.Get("altKey").Bool(),
+		CtrlKey:     e.Get("ctrlKey").Bool(),
+		ShiftKey:    e.Get("shiftKey").Bool(),
+		MetaKey:     e.Get("metaKey").Bool(),
+	}
+}
+
+func newMouseEventArgs(e js.Value) MouseEventArgs {
+	return MouseEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+		ClientX:     e.Get("clientX").Int(),
+		ClientY:     e.Get("clientY").Int(),
+		Button:      e.Get("button").Int(),
+		AltKey:      e.Get("altKey").Bool(),
+		CtrlKey:     e.Get("ctrlKey").Bool(),
+		ShiftKey:    e.Get("shiftKey").Bool(),
+		MetaKey:     e.Get("metaKey").Bool(),
+	}
+}
+
+func newDragEventArgs(e js.Value) DragEventArgs {
+	args := DragEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+		ClientX:     e.Get("clientX").Int(),
+		ClientY:     e.Get("clientY").Int(),
+		AltKey:      e.Get("altKey").Bool(),
+		CtrlKey:     e.Get("ctrlKey").Bool(),
+		ShiftKey:    e.Get("shiftKey").Bool(),
+		MetaKey:     e.Get("metaKey").Bool(),
+	}
+	if dt := e.Get("dataTransfer"); dt.Truthy() {
+		args.Text = dt.Call("getData", "text/plain").String()
+	}
+	return args
+}
+
+func newWheelEventArgs(e js.Value) WheelEventArgs {
+	return WheelEventArgs{
+		EventBase:   NewEventBase(e),
+		EventTarget: newEventTarget(e),
+		DeltaX:      e.Get("deltaX").Float(),
+		DeltaY:      e.Get("deltaY").Float(),
+		DeltaZ:      e.Get("deltaZ").Float(),
+		DeltaMode:   e.Get("deltaMode").Int(),
+		ClientX:     e.Get("clientX").Int(),
+		ClientY:     e.Get("clientY").Int(),
+		AltKey:      e.Get("altKey").Bool(),
+		CtrlKey:     e.Get("ctrlKey").Bool(),
+		ShiftKey:    e.Get("shiftKey").Bool(),
+		MetaKey:     e.Get("metaKey").Bool(),
+	}
+}
+
+func newTouchEventArgs(e js.Value) TouchEventArgs {
+	return TouchEventArgs{
+		EventBase:      NewEventBase(e),
+		EventTarget:    newEventTarget(e),
+		Touches:        touchPoints(e.Get("touches")),
+		ChangedTouches: touchPoints(e.Get("changedTouches")),
+		AltKey:         e.Get("altKey").Bool(),
+		CtrlKey:        e.Get("ctrlKey").Bool(),
+		ShiftKey:       e.Get("shiftKey").Bool(),
+		MetaKey:        e.Get("metaKey").Bool(),
+	}
+}
+
+// touchPoints converts a TouchList.
+func touchPoints(list js.Value) []TouchPoint {
+	if !list.Truthy() {
+		return nil
+	}
+	points := make([]TouchPoint, list.Length())
+	for i := range points {
+		touch := list.Index(i)
+		points[i] = TouchPoint{
+			Identifier: touch.Get("identifier").Int(),
+			ClientX:    touch.Get("clientX").Int(),
+			ClientY:    touch.Get("clientY").Int(),
+		}
+	}
+	return points
+}
+
+func newScrollEventArgs(e js.Value) ScrollEventArgs {
+	target := e.Get("currentTarget")
+	return ScrollEventArgs{
+		EventBase:    NewEventBase(e),
+		EventTarget:  newEventTarget(e),
+		ScrollTop:    target.Get("scrollTop").Int(),
+		ScrollLeft:   target.Get("scrollLeft").Int(),
+		ScrollHeight: target.Get("scrollHeight").Int(),
+		ScrollWidth:  target.Get("scrollWidth").Int(),
+		ClientHeight: target.Get("clientHeight").Int(),
+		ClientWidth:  target.Get("clientWidth").Int(),
+	}
+}
+
+// dispatch runs call, the invocation of handler, with the handler recorded as the source of
+// the renders it requests (see runtime.Trigger). A panic in the handler is recovered for
+// owner, the component that bound it (see runtime.RecoverHandlerPanic).
+func dispatch(owner runtime.Component, handler any, call func()) {
+	defer runtime.RecoverHandlerPanic(owner)
+	if !runtime.TrackingRenderRate() {
+		call()
+		return
+	}
+	runtime.Trigger("handler "+handlerName(handler), call)
+}
+
+// handlerName returns the name of a handler function, such as "(*Canvas).HandleMove".
+func handlerName(handler any) string {
+	fn := goruntime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return "<unknown>"
+	}
+	name := fn.Name()
+	if slash := strings.LastIndex(name, "/"); slash >= 0 {
+		name = name[slash+1:]
+	}
+	if dot := strings.Index(name, "."); dot >= 0 {
+		name = name[dot+1:] // Drop the package name
+	}
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// onceFiredProperty is the property Modify sets on an element once a .once handler for an
+// event type ran there, suffixed with the type. It lives on the DOM element because the
+// handler is recreated on every render: the element is what lasts as long as the listener.
+const onceFiredProperty = "__nojsOnceFired_"
+
+// Modify wraps handler, an adapted event handler, so that modifiers apply before it runs.
+// Key modifiers drop events for other keys first; Once then ignores every event on the
+// element after the first (the listener, re-attached on each patch, stays as a no-op until
+// the element is removed); Prevent and Stop call preventDefault and stopPropagation
+// synchronously, while the browser is still dispatching the event.
+func Modify(handler func(js.Value), modifiers ...Modifier) func(js.Value) {
+	set := newModifierSet(modifiers)
+	return func(e js.Value) {
+		if len(set.keys) > 0 && !set.passesKey(e.Get("key").String()) {
+			return
+		}
+		if set.once {
+			target, fired := e.Get("currentTarget"), onceFiredProperty+e.Get("type").String()
+			if target.Truthy() {
+				if target.Get(fired).Truthy() {
+					return
+				}
+				target.Set(fired, true)
+			}
+		}
+		if set.prevent {
+			e.Call("preventDefault")
+		}
+		if set.stop {
+			e.Call("stopPropagation")
+		}
+		handler(e)
 	}
 }
@@ -0,0 +1,157 @@
+package events
+
+// The event argument types have no build tags, so components with typed handlers compile
+// and can be tested natively. EventBase is implemented per platform (events.go, events_stub.go).
+
+// EventTarget describes the element an event handler is bound to, so one handler serving
+// many elements (the buttons of a {@for}) can tell which one fired: give each a data-
+// attribute (data-id="{item.ID}") and read it from Dataset. It is the element carrying the
+// handler (event.currentTarget), not a child the event started on. Every event args struct
+// embeds it; for targets that are not elements the fields are empty.
+type EventTarget struct {
+	TargetID      string            // The id attribute
+	TargetTagName string            // The lowercase tag name, such as "button"
+	TargetValue   string            // The value of a form field; empty for other elements
+	Dataset       map[string]string // The data-* attributes, keyed as in element.dataset (data-item-id -> "itemId"); nil when there are none
+}
+
+// ClickEventArgs represents the data passed from click events.
+// Used for @onclick handlers that need event details.
+type ClickEventArgs struct {
+	EventBase
+	EventTarget
+	ClientX  int  // X coordinate relative to the viewport
+	ClientY  int  // Y coordinate relative to the viewport
+	Button   int  // Which mouse button was pressed (0=left, 1=middle, 2=right)
+	AltKey   bool // Whether the Alt key was pressed
+	CtrlKey  bool // Whether the Ctrl key was pressed
+	ShiftKey bool // Whether the Shift key was pressed
+	MetaKey  bool // Whether the Meta key was pressed
+}
+
+// ChangeEventArgs represents the data passed from input/select/textarea change events.
+// This struct provides type-safe access to the current value of form elements.
+type ChangeEventArgs struct {
+	EventBase
+	EventTarget
+	// Value is the current value of the input element.
+	// For text inputs, this is the text content.
+	// For select elements, this is the selected option's value.
+	// For checkboxes, this is their value attribute ("on" by default); see Checked.
+	Value string
+	// Checked is whether a checkbox or radio button is checked; false for other elements.
+	Checked bool
+}
+
+// KeyboardEventArgs represents the data passed from keyboard events.
+// Used for @onkeydown, @onkeyup, @onkeypress handlers.
+type KeyboardEventArgs struct {
+	EventBase
+	EventTarget
+	Key      string // The key value of the key pressed (e.g., "a", "Enter", "Escape")
+	Code     string // The physical key code (e.g., "KeyA", "Enter")
+	AltKey   bool   // Whether the Alt key was pressed
+	CtrlKey  bool   // Whether the Ctrl key was pressed
+	ShiftKey bool   // Whether the Shift key was pressed
+	MetaKey  bool   // Whether the Meta (Command/Windows) key was pressed
+}
+
+// MouseEventArgs represents the data passed from mouse events.
+// Used for @onmousedown, @onmouseup, @onmousemove, @onmouseenter, @onmouseleave handlers.
+type MouseEventArgs struct {
+	EventBase
+	EventTarget
+	ClientX  int  // X coordinate relative to the viewport
+	ClientY  int  // Y coordinate relative to the viewport
+	Button   int  // Which mouse button was pressed (0=left, 1=middle, 2=right)
+	AltKey   bool // Whether the Alt key was pressed
+	CtrlKey  bool // Whether the Ctrl key was pressed
+	ShiftKey bool // Whether the Shift key was pressed
+	MetaKey  bool // Whether the Meta key was pressed
+}
+
+// FocusEventArgs represents the data passed from focus/blur events.
+type FocusEventArgs struct {
+	EventBase
+	EventTarget
+}
+
+// FormEventArgs represents the data passed from form submission events.
+// Used for @onsubmit handlers.
+type FormEventArgs struct {
+	EventBase
+	EventTarget
+}
+
+// DragEventArgs represents the data passed from drag and drop events.
+// Used for @ondragstart, @ondrag, @ondragend, @ondragenter, @ondragover, @ondragleave and
+// @ondrop handlers. A drop target must prevent the default of @ondragover
+// (@ondragover.prevent) for the browser to allow a drop on it.
+type DragEventArgs struct {
+	EventBase
+	EventTarget
+	ClientX  int    // X coordinate relative to the viewport
+	ClientY  int    // Y coordinate relative to the viewport
+	AltKey   bool   // Whether the Alt key was pressed
+	CtrlKey  bool   // Whether the Ctrl key was pressed
+	ShiftKey bool   // Whether the Shift key was pressed
+	MetaKey  bool   // Whether the Meta key was pressed
+	Text     string // The text/plain data of the drag; browsers expose it on @ondrop only
+}
+
+// WheelEventArgs represents the data passed from wheel events.
+// Used for @onwheel handlers.
+type WheelEventArgs struct {
+	EventBase
+	EventTarget
+	DeltaX    float64 // Horizontal scroll amount
+	DeltaY    float64 // Vertical scroll amount
+	DeltaZ    float64 // Scroll amount along the z-axis
+	DeltaMode int     // Unit of the deltas (0=pixels, 1=lines, 2=pages)
+	ClientX   int     // X coordinate relative to the viewport
+	ClientY   int     // Y coordinate relative to the viewport
+	AltKey    bool    // Whether the Alt key was pressed
+	CtrlKey   bool    // Whether the Ctrl key was pressed (also set for pinch-zoom on trackpads)
+	ShiftKey  bool    // Whether the Shift key was pressed
+	MetaKey   bool    // Whether the Meta key was pressed
+}
+
+// TouchPoint is one point of contact of a touch event.
+type TouchPoint struct {
+	Identifier int // Stable identifier of the contact for the duration of the touch
+	ClientX    int // X coordinate relative to the viewport
+	ClientY    int // Y coordinate relative to the viewport
+}
+
+// TouchEventArgs represents the data passed from touch events.
+// Used for @ontouchstart, @ontouchmove, @ontouchend and @ontouchcancel handlers.
+type TouchEventArgs struct {
+	EventBase
+	EventTarget
+	Touches        []TouchPoint // Every contact currently on the surface
+	ChangedTouches []TouchPoint // The contacts that changed in this event (lifted ones on touchend)
+	AltKey         bool         // Whether the Alt key was pressed
+	CtrlKey        bool         // Whether the Ctrl key was pressed
+	ShiftKey       bool         // Whether the Shift key was pressed
+	MetaKey        bool         // Whether the Meta key was pressed
+}
+
+// ScrollEventArgs represents the data passed from scroll events: the scroll position and
+// sizes of the scrolled element, enough to load more items near the end of a list.
+// Used for @onscroll handlers.
+type ScrollEventArgs struct {
+	EventBase
+	EventTarget
+	ScrollTop    int // Pixels scrolled from the top
+	ScrollLeft   int // Pixels scrolled from the left
+	ScrollHeight int // Height of the whole content
+	ScrollWidth  int // Width of the whole content
+	ClientHeight int // Visible height
+	ClientWidth  int // Visible width
+}
+
+// NearBottom reports whether the element is scrolled to within threshold pixels of the end
+// of its content.
+func (e ScrollEventArgs) NearBottom(threshold int) bool {
+	return e.ScrollHeight-e.ScrollTop-e.ClientHeight <= threshold
+}
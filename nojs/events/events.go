@@ -47,62 +47,11 @@ func (e *EventBase) IsPropagationStopped() bool {
 	return e.stopPropagationCalled
 }
 
-// ClickEventArgs represents the data passed from click events.
-// Used for @onclick handlers that need event details.
-type ClickEventArgs struct {
-	EventBase
-	ClientX  int  // X coordinate relative to the viewport
-	ClientY  int  // Y coordinate relative to the viewport
-	Button   int  // Which mouse button was pressed (0=left, 1=middle, 2=right)
-	AltKey   bool // Whether the Alt key was pressed
-	CtrlKey  bool // Whether the Ctrl key was pressed
-	ShiftKey bool // Whether the Shift key was pressed
-	MetaKey  bool // Whether the Meta key was pressed
-}
-
-// ChangeEventArgs represents the data passed from input/select/textarea change events.
-// This struct provides type-safe access to the current value of form elements.
-type ChangeEventArgs struct {
-	EventBase
-	// Value is the current value of the input element.
-	// For text inputs, this is the text content.
-	// For select elements, this is the selected option's value.
-	// For checkboxes, this will be "true" or "false".
-	Value string
-}
-
-// KeyboardEventArgs represents the data passed from keyboard events.
-// Used for @onkeydown, @onkeyup, @onkeypress handlers.
-type KeyboardEventArgs struct {
-	EventBase
-	Key      string // The key value of the key pressed (e.g., "a", "Enter", "Escape")
-	Code     string // The physical key code (e.g., "KeyA", "Enter")
-	AltKey   bool   // Whether the Alt key was pressed
-	CtrlKey  bool   // Whether the Ctrl key was pressed
-	ShiftKey bool   // Whether the Shift key was pressed
-	MetaKey  bool   // Whether the Meta (Command/Windows) key was pressed
-}
-
-// MouseEventArgs represents the data passed from mouse events.
-// Used for @onmousedown, @onmouseup, @onmousemove handlers.
-type MouseEventArgs struct {
-	EventBase
-	ClientX  int  // X coordinate relative to the viewport
-	ClientY  int  // Y coordinate relative to the viewport
-	Button   int  // Which mouse button was pressed (0=left, 1=middle, 2=right)
-	AltKey   bool // Whether the Alt key was pressed
-	CtrlKey  bool // Whether the Ctrl key was pressed
-	ShiftKey bool // Whether the Shift key was pressed
-	MetaKey  bool // Whether the Meta key was pressed
-}
-
-// FocusEventArgs represents the data passed from focus/blur events.
-type FocusEventArgs struct {
-	EventBase
-}
-
-// FormEventArgs represents the data passed from form submission events.
-// Used for @onsubmit handlers.
-type FormEventArgs struct {
-	EventBase
+// SetText sets the text/plain data of the drag, for the drop target to read from Text.
+// Call it from an @ondragstart handler.
+func (e *DragEventArgs) SetText(text string) {
+	if dt := e.jsEvent.Get("dataTransfer"); dt.Truthy() {
+		dt.Call("setData", "text/plain", text)
+	}
+	e.Text = text
 }
@@ -3,10 +3,210 @@
 
 package events
 
-// Stub file for non-WASM builds to allow generated code to compile.
-// The actual implementation is in events.go with js/wasm build tags.
+import (
+	"reflect"
 
-// AdaptNoArgEvent is a stub for non-WASM builds.
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Native implementation for non-WASM builds, so generated code and components with typed
+// event handlers compile and can be tested without a browser. The adapters return the
+// handler unchanged; rendertest.FireEvent calls it with the event arguments of a test.
+
+// EventBase provides common functionality for all DOM events.
+// Natively there is no browser event, so it only records the calls.
+type EventBase struct {
+	preventDefaultCalled  bool
+	stopPropagationCalled bool
+}
+
+// PreventDefault records that the default action was prevented.
+func (e *EventBase) PreventDefault() {
+	e.preventDefaultCalled = true
+}
+
+// StopPropagation records that propagation was stopped.
+func (e *EventBase) StopPropagation() {
+	e.stopPropagationCalled = true
+}
+
+// IsDefaultPrevented returns whether PreventDefault was called.
+func (e *EventBase) IsDefaultPrevented() bool {
+	return e.preventDefaultCalled
+}
+
+// IsPropagationStopped returns whether StopPropagation was called.
+func (e *EventBase) IsPropagationStopped() bool {
+	return e.stopPropagationCalled
+}
+
+// SetText sets the text/plain data of the drag. Natively there is no drag, so it only sets
+// Text, which a test can then pass on to a drop handler.
+func (e *DragEventArgs) SetText(text string) {
+	e.Text = text
+}
+
+// AdaptClickEvent returns handler unchanged in non-WASM builds.
+func AdaptClickEvent(handler func(ClickEventArgs)) func(ClickEventArgs) {
+	return handler
+}
+
+// AdaptChangeEvent returns handler unchanged in non-WASM builds.
+func AdaptChangeEvent(handler func(ChangeEventArgs)) func(ChangeEventArgs) {
+	return handler
+}
+
+// AdaptKeyboardEvent returns handler unchanged in non-WASM builds.
+func AdaptKeyboardEvent(handler func(KeyboardEventArgs)) func(KeyboardEventArgs) {
+	return handler
+}
+
+// AdaptMouseEvent returns handler unchanged in non-WASM builds.
+func AdaptMouseEvent(handler func(MouseEventArgs)) func(MouseEventArgs) {
+	return handler
+}
+
+// AdaptFocusEvent returns handler unchanged in non-WASM builds.
+func AdaptFocusEvent(handler func(FocusEventArgs)) func(FocusEventArgs) {
+	return handler
+}
+
+// AdaptFormEvent returns handler unchanged in non-WASM builds.
+func AdaptFormEvent(handler func(FormEventArgs)) func(FormEventArgs) {
+	return handler
+}
+
+// AdaptDragEvent returns handler unchanged in non-WASM builds.
+func AdaptDragEvent(handler func(DragEventArgs)) func(DragEventArgs) {
+	return handler
+}
+
+// AdaptWheelEvent returns handler unchanged in non-WASM builds.
+func AdaptWheelEvent(handler func(WheelEventArgs)) func(WheelEventArgs) {
+	return handler
+}
+
+// AdaptTouchEvent returns handler unchanged in non-WASM builds.
+func AdaptTouchEvent(handler func(TouchEventArgs)) func(TouchEventArgs) {
+	return handler
+}
+
+// AdaptScrollEvent returns handler unchanged in non-WASM builds.
+func AdaptScrollEvent(handler func(ScrollEventArgs)) func(ScrollEventArgs) {
+	return handler
+}
+
+// AdaptNoArgEvent returns handler unchanged in non-WASM builds.
 func AdaptNoArgEvent(handler func()) func() {
 	return handler
 }
+
+// AdaptClickEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptClickEventCtx(c runtime.Component, handler func(runtime.Ctx, ClickEventArgs)) func(ClickEventArgs) {
+	return func(e ClickEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptChangeEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptChangeEventCtx(c runtime.Component, handler func(runtime.Ctx, ChangeEventArgs)) func(ChangeEventArgs) {
+	return func(e ChangeEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptKeyboardEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptKeyboardEventCtx(c runtime.Component, handler func(runtime.Ctx, KeyboardEventArgs)) func(KeyboardEventArgs) {
+	return func(e KeyboardEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptMouseEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptMouseEventCtx(c runtime.Component, handler func(runtime.Ctx, MouseEventArgs)) func(MouseEventArgs) {
+	return func(e MouseEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptFocusEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptFocusEventCtx(c runtime.Component, handler func(runtime.Ctx, FocusEventArgs)) func(FocusEventArgs) {
+	return func(e FocusEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptFormEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptFormEventCtx(c runtime.Component, handler func(runtime.Ctx, FormEventArgs)) func(FormEventArgs) {
+	return func(e FormEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptDragEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptDragEventCtx(c runtime.Component, handler func(runtime.Ctx, DragEventArgs)) func(DragEventArgs) {
+	return func(e DragEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptWheelEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptWheelEventCtx(c runtime.Component, handler func(runtime.Ctx, WheelEventArgs)) func(WheelEventArgs) {
+	return func(e WheelEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptTouchEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptTouchEventCtx(c runtime.Component, handler func(runtime.Ctx, TouchEventArgs)) func(TouchEventArgs) {
+	return func(e TouchEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptScrollEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptScrollEventCtx(c runtime.Component, handler func(runtime.Ctx, ScrollEventArgs)) func(ScrollEventArgs) {
+	return func(e ScrollEventArgs) { handler(runtime.NewCtx(c), e) }
+}
+
+// AdaptNoArgEventCtx binds the Ctx of component c to handler in non-WASM builds.
+func AdaptNoArgEventCtx(c runtime.Component, handler func(runtime.Ctx)) func() {
+	return func() { handler(runtime.NewCtx(c)) }
+}
+
+// Modify wraps handler, an adapted event handler, so that modifiers apply when a test fires
+// it: key modifiers drop KeyboardEventArgs for other keys, Once ignores every call after the
+// first, and Prevent and Stop are recorded on the event arguments the handler receives.
+func Modify[H any](handler H, modifiers ...Modifier) H {
+	set := newModifierSet(modifiers)
+	fn := reflect.ValueOf(handler)
+	fired := false
+	wrapped := reflect.MakeFunc(fn.Type(), func(in []reflect.Value) []reflect.Value {
+		var args reflect.Value // Addressable copy of the event arguments, if any
+		if len(in) == 1 {
+			args = reflect.New(in[0].Type()).Elem()
+			args.Set(in[0])
+			in = []reflect.Value{args}
+		}
+		if len(set.keys) > 0 {
+			var keyboard KeyboardEventArgs
+			ok := false
+			if args.IsValid() {
+				keyboard, ok = args.Interface().(KeyboardEventArgs)
+			}
+			if !ok || !set.passesKey(keyboard.Key) {
+				return nil
+			}
+		}
+		if set.once {
+			if fired {
+				return nil
+			}
+			fired = true
+		}
+		if base, ok := eventBaseOf(args); ok {
+			if set.prevent {
+				base.PreventDefault()
+			}
+			if set.stop {
+				base.StopPropagation()
+			}
+		}
+		return fn.Call(in)
+	})
+	return wrapped.Interface().(H)
+}
+
+// eventBaseOf returns the EventBase embedded in the event arguments args, if any.
+func eventBaseOf(args reflect.Value) (*EventBase, bool) {
+	if !args.IsValid() || args.Kind() != reflect.Struct {
+		return nil, false
+	}
+	field := args.FieldByName("EventBase")
+	if !field.IsValid() || field.Type() != reflect.TypeOf(EventBase{}) {
+		return nil, false
+	}
+	return field.Addr().Interface().(*EventBase), true
+}
@@ -0,0 +1,109 @@
+package events
+
+import "sort"
+
+// Modifier changes how an event reaches its handler. In templates modifiers follow the event
+// name: @onsubmit.prevent="Save", @onkeydown.enter="Submit". The adapted handler is wrapped
+// with Modify, which applies them synchronously in the browser's event dispatch, before the
+// Go handler runs.
+type Modifier int
+
+const (
+	// Prevent calls preventDefault on the event (.prevent).
+	Prevent Modifier = iota
+	// Stop calls stopPropagation on the event (.stop).
+	Stop
+	// Once runs the handler for the first event on the element only (.once).
+	Once
+	// Enter passes on only keyboard events for the Enter key (.enter).
+	Enter
+	// Escape passes on only keyboard events for the Escape key (.escape).
+	Escape
+)
+
+// modifierNames maps the template name of each modifier to it.
+var modifierNames = map[string]Modifier{
+	"prevent": Prevent,
+	"stop":    Stop,
+	"once":    Once,
+	"enter":   Enter,
+	"escape":  Escape,
+}
+
+// ModifierNamed returns the modifier written as name in templates (@onclick.name).
+func ModifierNamed(name string) (Modifier, bool) {
+	m, ok := modifierNames[name]
+	return m, ok
+}
+
+// ModifierNames returns the template names of all modifiers, sorted.
+func ModifierNames() []string {
+	names := make([]string, 0, len(modifierNames))
+	for name := range modifierNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String returns the template name of m.
+func (m Modifier) String() string {
+	for name, modifier := range modifierNames {
+		if modifier == m {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// Key returns the KeyboardEvent key m filters for, or "" when m is not a key modifier.
+func (m Modifier) Key() string {
+	switch m {
+	case Enter:
+		return "Enter"
+	case Escape:
+		return "Escape"
+	default:
+		return ""
+	}
+}
+
+// modifierSet is the combined effect of a list of modifiers.
+type modifierSet struct {
+	prevent, stop, once bool
+	keys                []string // Keys passed on; empty passes every event
+}
+
+// newModifierSet combines modifiers.
+func newModifierSet(modifiers []Modifier) modifierSet {
+	var set modifierSet
+	for _, m := range modifiers {
+		switch m {
+		case Prevent:
+			set.prevent = true
+		case Stop:
+			set.stop = true
+		case Once:
+			set.once = true
+		default:
+			if key := m.Key(); key != "" {
+				set.keys = append(set.keys, key)
+			}
+		}
+	}
+	return set
+}
+
+// passesKey reports whether an event for key reaches the handler. An event filtered out is
+// ignored entirely: it is neither prevented nor stopped, and does not use up .once.
+func (s modifierSet) passesKey(key string) bool {
+	if len(s.keys) == 0 {
+		return true
+	}
+	for _, k := range s.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
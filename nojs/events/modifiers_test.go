@@ -0,0 +1,73 @@
+//go:build !wasm
+// +build !wasm
+
+package events
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestModify_KeyFilterAndOnce verifies key modifiers drop other keys without using up Once,
+// and that Once lets only the first matching event through.
+func TestModify_KeyFilterAndOnce(t *testing.T) {
+	// Arrange
+	var keys []string
+	handler := Modify(AdaptKeyboardEvent(func(e KeyboardEventArgs) { keys = append(keys, e.Key) }), Enter, Once)
+
+	// Act
+	for _, key := range []string{"a", "Enter", "Enter", "Escape"} {
+		handler(KeyboardEventArgs{Key: key})
+	}
+
+	// Assert
+	if !slices.Equal(keys, []string{"Enter"}) {
+		t.Errorf("expected only the first Enter, got %v", keys)
+	}
+}
+
+// TestModify_PreventAndStopBeforeHandler verifies the handler receives event arguments with
+// the default prevented and propagation stopped.
+func TestModify_PreventAndStopBeforeHandler(t *testing.T) {
+	// Arrange
+	var got FormEventArgs
+	handler := Modify(AdaptFormEvent(func(e FormEventArgs) { got = e }), Prevent, Stop)
+
+	// Act
+	handler(FormEventArgs{})
+
+	// Assert
+	if !got.IsDefaultPrevented() || !got.IsPropagationStopped() {
+		t.Errorf("expected the event prevented and stopped, got prevented=%v stopped=%v", got.IsDefaultPrevented(), got.IsPropagationStopped())
+	}
+}
+
+// TestModify_NoArgHandler verifies modifiers wrap handlers without event arguments.
+func TestModify_NoArgHandler(t *testing.T) {
+	// Arrange
+	calls := 0
+	handler := Modify(AdaptNoArgEvent(func() { calls++ }), Prevent, Once)
+
+	// Act
+	handler()
+	handler()
+
+	// Assert
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestModifierNamed_TemplateNames verifies every listed name resolves to a modifier with that
+// name.
+func TestModifierNamed_TemplateNames(t *testing.T) {
+	for _, name := range ModifierNames() {
+		m, ok := ModifierNamed(name)
+		if !ok || m.String() != name {
+			t.Errorf("%s: expected a modifier named %s, got %v (%v)", name, name, m, ok)
+		}
+	}
+	if _, ok := ModifierNamed("capture"); ok {
+		t.Error("expected no modifier named capture")
+	}
+}
@@ -1,5 +1,7 @@
 package events
 
+import "sort"
+
 // EventSignature defines the expected handler signature for a specific event.
 // The compiler uses this to validate that component methods match the required signature.
 type EventSignature struct {
@@ -116,6 +118,109 @@ var EventRegistry = map[string]EventSignature{
 		RequiresArgs:  true,
 		ArgsType:      "events.MouseEventArgs",
 	},
+	"onmouseenter": {
+		EventName:     "onmouseenter",
+		SupportedTags: []string{"button", "div", "span", "img", "a", "li"},
+		ExpectedSig:   "func(events.MouseEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.MouseEventArgs",
+	},
+	"onmouseleave": {
+		EventName:     "onmouseleave",
+		SupportedTags: []string{"button", "div", "span", "img", "a", "li"},
+		ExpectedSig:   "func(events.MouseEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.MouseEventArgs",
+	},
+
+	// Phase 4: More mouse events
+	"ondblclick": {
+		EventName:     "ondblclick",
+		SupportedTags: []string{"button", "div", "span", "p", "img", "a", "li", "td", "th", "tr"},
+		ExpectedSig:   "func(events.MouseEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.MouseEventArgs",
+	},
+	"oncontextmenu": {
+		EventName:     "oncontextmenu",
+		SupportedTags: []string{"button", "div", "span", "p", "img", "a", "li", "td", "th", "tr", "canvas"},
+		ExpectedSig:   "func(events.MouseEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.MouseEventArgs",
+	},
+
+	// Phase 4: Scroll and wheel events
+	"onscroll": {
+		EventName:     "onscroll",
+		SupportedTags: scrollableTags,
+		ExpectedSig:   "func(events.ScrollEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.ScrollEventArgs",
+	},
+	"onwheel": {
+		EventName:     "onwheel",
+		SupportedTags: append([]string{"canvas", "img"}, scrollableTags...),
+		ExpectedSig:   "func(events.WheelEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.WheelEventArgs",
+	},
+
+	// Phase 4: Drag and drop events
+	"ondragstart": dragEvent("ondragstart"),
+	"ondrag":      dragEvent("ondrag"),
+	"ondragend":   dragEvent("ondragend"),
+	"ondragenter": dragEvent("ondragenter"),
+	"ondragover":  dragEvent("ondragover"),
+	"ondragleave": dragEvent("ondragleave"),
+	"ondrop":      dragEvent("ondrop"),
+
+	// Phase 4: Touch events
+	"ontouchstart":  touchEvent("ontouchstart"),
+	"ontouchmove":   touchEvent("ontouchmove"),
+	"ontouchend":    touchEvent("ontouchend"),
+	"ontouchcancel": touchEvent("ontouchcancel"),
+}
+
+// scrollableTags are the elements commonly given a scrolling overflow.
+var scrollableTags = []string{"div", "ul", "ol", "section", "article", "main", "aside", "nav", "textarea", "table", "tbody"}
+
+// dragTags are the elements commonly dragged or dropped on: cards, list items and the
+// columns holding them.
+var dragTags = []string{"div", "li", "ul", "ol", "span", "img", "a", "section", "article", "tr", "td"}
+
+// touchTags are the elements commonly handling touch gestures.
+var touchTags = []string{"div", "canvas", "button", "img", "li", "span", "a", "section", "article"}
+
+// dragEvent returns the signature of the drag and drop event name.
+func dragEvent(name string) EventSignature {
+	return EventSignature{
+		EventName:     name,
+		SupportedTags: dragTags,
+		ExpectedSig:   "func(events.DragEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.DragEventArgs",
+	}
+}
+
+// touchEvent returns the signature of the touch event name.
+func touchEvent(name string) EventSignature {
+	return EventSignature{
+		EventName:     name,
+		SupportedTags: touchTags,
+		ExpectedSig:   "func(events.TouchEventArgs)",
+		RequiresArgs:  true,
+		ArgsType:      "events.TouchEventArgs",
+	}
+}
+
+// EventNames returns the names of all registered events, sorted.
+func EventNames() []string {
+	names := make([]string, 0, len(EventRegistry))
+	for name := range EventRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // GetEventSignature returns the signature for an event name.
@@ -0,0 +1,92 @@
+// Package fetch loads data over HTTP for components. In WASM builds net/http uses the
+// browser's fetch, so requests go through the service worker (see the nojs/sw package),
+// which answers from its cache when the network fails.
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrOffline is returned, wrapped with the cause, when a Resource without OfflineFallback
+// cannot reach the server and the service worker has no cached response.
+var ErrOffline = errors.New("fetch: offline")
+
+// Resource is JSON data of type T served at URL.
+type Resource[T any] struct {
+	URL    string
+	Client *http.Client // http.DefaultClient when nil
+
+	// OfflineFallback, if set, is served when the request fails without an HTTP response:
+	// no network, and no response cached by the service worker. Responses with an error
+	// status are reported as errors all the same.
+	OfflineFallback *T
+}
+
+// Result is the value a Resource loaded.
+type Result[T any] struct {
+	Value   T
+	Offline bool // Value is the OfflineFallback; the app may show it as read-only or stale
+}
+
+// StatusError is the error of a response with a status other than 2xx.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Status     string // e.g., "404 Not Found"
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("fetch: GET %s: %s", e.URL, e.Status)
+}
+
+// Load fetches and decodes the resource. It blocks until the server answers: call it from a
+// goroutine, never directly from an event handler or lifecycle method, which run on the
+// event loop the answer is delivered through. A cancelled ctx is returned as an error,
+// never as the offline fallback.
+//
+// Example:
+//
+//	var jobs = fetch.Resource[[]Job]{URL: "/api/jobs", OfflineFallback: &[]Job{}}
+//
+//	func (c *JobList) OnMount() {
+//	    go func() {
+//	        result, err := jobs.Load(context.Background())
+//	        c.ctx.SafeUpdate(func() { c.Jobs, c.Offline, c.Err = result.Value, result.Offline, err })
+//	    }()
+//	}
+func (r Resource[T]) Load(ctx context.Context) (Result[T], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		return Result[T]{}, fmt.Errorf("fetch: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return Result[T]{}, ctx.Err()
+		}
+		if r.OfflineFallback != nil {
+			return Result[T]{Value: *r.OfflineFallback, Offline: true}, nil
+		}
+		return Result[T]{}, fmt.Errorf("%w: GET %s: %v", ErrOffline, r.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Result[T]{}, &StatusError{URL: r.URL, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	var result Result[T]
+	if err := json.NewDecoder(resp.Body).Decode(&result.Value); err != nil {
+		return Result[T]{}, fmt.Errorf("fetch: decoding %s: %w", r.URL, err)
+	}
+	return result, nil
+}
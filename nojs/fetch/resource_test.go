@@ -0,0 +1,93 @@
+//go:build !wasm
+// +build !wasm
+
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// job is the data of the test resources.
+type job struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+// offlineURL returns the URL of a server that is no longer reachable.
+func offlineURL(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL + "/api/jobs"
+}
+
+// TestResource_LoadsFromServer verifies a reachable server's JSON is decoded and not
+// reported as offline.
+func TestResource_LoadsFromServer(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":1,"title":"Replace meter"}]`))
+	}))
+	t.Cleanup(server.Close)
+	jobs := Resource[[]job]{URL: server.URL, OfflineFallback: &[]job{}}
+
+	// Act
+	result, err := jobs.Load(context.Background())
+
+	// Assert
+	if err != nil || result.Offline || len(result.Value) != 1 || result.Value[0].Title != "Replace meter" {
+		t.Errorf("expected the server's job, got %+v, %v", result, err)
+	}
+}
+
+// TestResource_OfflineFallback verifies the fallback is served when the request fails
+// without a response, and ErrOffline is returned without one.
+func TestResource_OfflineFallback(t *testing.T) {
+	// Arrange
+	url := offlineURL(t)
+	withFallback := Resource[[]job]{URL: url, OfflineFallback: &[]job{{ID: 7, Title: "Cached"}}}
+	withoutFallback := Resource[[]job]{URL: url}
+
+	// Act
+	result, err := withFallback.Load(context.Background())
+	_, offlineErr := withoutFallback.Load(context.Background())
+
+	// Assert
+	if err != nil || !result.Offline || len(result.Value) != 1 || result.Value[0].ID != 7 {
+		t.Errorf("expected the offline fallback, got %+v, %v", result, err)
+	}
+	if !errors.Is(offlineErr, ErrOffline) {
+		t.Errorf("expected ErrOffline without a fallback, got %v", offlineErr)
+	}
+}
+
+// TestResource_ErrorsAreNotOffline verifies an error status and a cancelled context are
+// reported as errors, not answered with the fallback.
+func TestResource_ErrorsAreNotOffline(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	failing := Resource[[]job]{URL: server.URL, OfflineFallback: &[]job{}}
+	offline := Resource[[]job]{URL: offlineURL(t), OfflineFallback: &[]job{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	_, statusErr := failing.Load(context.Background())
+	_, cancelErr := offline.Load(ctx)
+
+	// Assert
+	var status *StatusError
+	if !errors.As(statusErr, &status) || status.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected a 503 StatusError, got %v", statusErr)
+	}
+	if !errors.Is(cancelErr, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", cancelErr)
+	}
+}
@@ -0,0 +1,41 @@
+// Package formatters holds the functions the compiler calls for the filters of text
+// bindings, so that generated code reads like the template:
+//
+//	<p>{Title | upper}: {Price | number 2}</p>
+//
+// compiles to
+//
+//	fmt.Sprintf("%s: %s", formatters.Upper(c.Title), formatters.Number(c.Price, 2))
+//
+// The date filter needs no helper: it compiles to a call of time.Time.Format.
+//
+// The compiler checks the type of each bound value, so the functions do no validation.
+package formatters
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Numeric is the set of types the number filter accepts, named types included.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Number formats v with the given number of decimals, rounded like fmt's %.2f:
+// Number(3.14159, 2) is "3.14" and Number(7, 1) is "7.0".
+func Number[T Numeric](v T, decimals int) string {
+	return strconv.FormatFloat(float64(v), 'f', decimals, 64)
+}
+
+// Upper returns s with all letters mapped to upper case.
+func Upper[T ~string](s T) string {
+	return strings.ToUpper(string(s))
+}
+
+// Lower returns s with all letters mapped to lower case.
+func Lower[T ~string](s T) string {
+	return strings.ToLower(string(s))
+}
@@ -0,0 +1,40 @@
+//go:build !wasm
+// +build !wasm
+
+package formatters
+
+import "testing"
+
+type quantity int
+
+type status string
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"float rounded", Number(3.14159, 2), "3.14"},
+		{"int padded", Number(7, 1), "7.0"},
+		{"no decimals", Number(2.5e6, 0), "2500000"},
+		{"named type", Number(quantity(12), 2), "12.00"},
+		{"negative", Number(float32(-0.5), 1), "-0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, tt.got)
+			}
+		})
+	}
+}
+
+func TestCasing(t *testing.T) {
+	if got := Upper(status("draft")); got != "DRAFT" {
+		t.Errorf("expected Upper to give %q, got %q", "DRAFT", got)
+	}
+	if got := Lower("Hello, World"); got != "hello, world" {
+		t.Errorf("expected Lower to give %q, got %q", "hello, world", got)
+	}
+}
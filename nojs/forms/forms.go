@@ -0,0 +1,141 @@
+// Package forms validates form input against rules declared in validate struct tags. A
+// component embeds a Validator and tags its own fields, the ones its template binds with
+// @bind, so the component is the form model:
+//
+//	type Signup struct {
+//	    runtime.ComponentBase
+//	    forms.Validator
+//	    Email string `nojs:"state" validate:"required,email"`
+//	    Name  string `nojs:"state" validate:"required,min=3" message:"Tell us your name"`
+//	    Age   int    `nojs:"state" validate:"min=18"`
+//	}
+//
+//	func (c *Signup) Submit() {
+//	    if c.Validate() {
+//	        save(c.Email, c.Name, c.Age)
+//	    }
+//	}
+//
+// Templates bind {Errors.Email} for the message of a field and toggle a class with
+// {classes Invalid.Email:'invalid'}; the compiler turns them into ErrorFor("Email") and
+// !IsValid("Email"), and binds the Validator to the component in Render. Once a field has
+// been validated, by Validate on submit or ValidateField on change, each @bind write checks
+// it again, so its message goes away as soon as the input is fixed.
+//
+// The rules are checked in the order written, and the message of the first that fails is
+// the field's error. A field that is empty (its zero value) and not required is valid
+// whatever its other rules: they apply to what was entered.
+package forms
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validator checks the fields of a bound struct against their validate tags and keeps the
+// message of each invalid field. Its zero value is ready to Bind.
+type Validator struct {
+	model   reflect.Value // The bound struct, addressable
+	rules   []fieldRules  // Rules of the fields of the model's type
+	errors  map[string]string
+	checked map[string]bool // Fields validated since the last Bind or Reset
+}
+
+// Bind makes model, a pointer to a struct, the struct Validate checks. Binding the model
+// already bound does nothing, so Render can bind on every render; binding another one
+// clears the errors. It panics when model is not a pointer to a struct or a validate tag is
+// malformed, like regexp.MustCompile: both are programming errors.
+func (v *Validator) Bind(model any) {
+	value := reflect.ValueOf(model)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("forms: Bind needs a pointer to a struct, got %T", model))
+	}
+	if v.model.IsValid() && v.model.Addr().Pointer() == value.Pointer() {
+		return
+	}
+	v.model = value.Elem()
+	v.rules = rulesOf(v.model.Type())
+	v.Reset()
+}
+
+// Validate checks every field with rules and reports whether all of them are valid. It
+// replaces the errors set before, SetError's included.
+func (v *Validator) Validate() bool {
+	v.mustBeBound("Validate")
+	v.errors = nil
+	for _, field := range v.rules {
+		v.check(field)
+	}
+	return len(v.errors) == 0
+}
+
+// ValidateField checks the field named field alone, for feedback as it is edited, and
+// reports whether it is valid. A field without rules is valid.
+func (v *Validator) ValidateField(field string) bool {
+	v.mustBeBound("ValidateField")
+	for _, f := range v.rules {
+		if f.name == field {
+			return v.check(f)
+		}
+	}
+	return v.ErrorFor(field) == ""
+}
+
+// Revalidate checks the field named field again if it has been validated since the last
+// Bind or Reset, and otherwise does nothing: a field is not flagged before the user is done
+// with it. Generated @bind handlers call it after each write.
+func (v *Validator) Revalidate(field string) {
+	if v.checked[field] {
+		v.ValidateField(field)
+	}
+}
+
+// ErrorFor returns the message of the field named field, or "" when it is valid or has not
+// been validated.
+func (v *Validator) ErrorFor(field string) string {
+	return v.errors[field]
+}
+
+// IsValid reports whether the field named field has no error. A field that has not been
+// validated is valid.
+func (v *Validator) IsValid(field string) bool {
+	return v.errors[field] == ""
+}
+
+// SetError sets the message of the field named field, such as one a server returned for
+// it; an empty message clears it. The next Validate replaces it.
+func (v *Validator) SetError(field, message string) {
+	if message == "" {
+		delete(v.errors, field)
+		return
+	}
+	if v.errors == nil {
+		v.errors = make(map[string]string)
+	}
+	v.errors[field] = message
+}
+
+// Reset clears the errors and forgets which fields have been validated, as for a form
+// emptied after a submit.
+func (v *Validator) Reset() {
+	v.errors = nil
+	v.checked = nil
+}
+
+// check validates field, records its message and reports whether it is valid.
+func (v *Validator) check(field fieldRules) bool {
+	if v.checked == nil {
+		v.checked = make(map[string]bool)
+	}
+	v.checked[field.name] = true
+	message := field.validate(v.model.FieldByIndex(field.index))
+	v.SetError(field.name, message)
+	return message == ""
+}
+
+// mustBeBound panics when no model is bound, naming the method called.
+func (v *Validator) mustBeBound(method string) {
+	if !v.model.IsValid() {
+		panic("forms: " + method + " called before Bind")
+	}
+}
@@ -0,0 +1,96 @@
+//go:build !wasm
+// +build !wasm
+
+package forms
+
+import "testing"
+
+// signup is a form model, as a component embedding its Validator would be.
+type signup struct {
+	Validator
+	Email string `validate:"required,email"`
+	Name  string `validate:"min=2"`
+	Notes string
+}
+
+// TestValidator_ValidateCollectsEveryError verifies Validate checks every field with rules,
+// and that fields start valid until they are validated.
+func TestValidator_ValidateCollectsEveryError(t *testing.T) {
+	// Arrange
+	form := &signup{Name: "A"}
+	form.Bind(form)
+	if !form.IsValid("Email") || form.ErrorFor("Email") != "" {
+		t.Fatal("expected fields valid before validation")
+	}
+
+	// Act
+	ok := form.Validate()
+
+	// Assert
+	if ok {
+		t.Error("expected the form invalid")
+	}
+	if form.IsValid("Email") || form.IsValid("Name") || !form.IsValid("Notes") {
+		t.Errorf("expected Email and Name invalid, got %q and %q", form.ErrorFor("Email"), form.ErrorFor("Name"))
+	}
+}
+
+// TestValidator_RevalidateOnlyValidatedFields verifies a field is checked again on change
+// once it has been validated, and not before.
+func TestValidator_RevalidateOnlyValidatedFields(t *testing.T) {
+	// Arrange
+	form := &signup{Email: "ada@", Name: "A"}
+	form.Bind(form)
+	form.ValidateField("Email")
+
+	// Act
+	form.Revalidate("Name")
+	form.Email = "ada@example.com"
+	form.Revalidate("Email")
+
+	// Assert
+	if !form.IsValid("Name") {
+		t.Error("expected Name not flagged before it is validated")
+	}
+	if !form.IsValid("Email") {
+		t.Errorf("expected the fixed Email valid, got %q", form.ErrorFor("Email"))
+	}
+}
+
+// TestValidator_SetErrorAndBind verifies an error set by hand shows until the next Validate,
+// that binding the same model keeps it, and that binding another model clears it.
+func TestValidator_SetErrorAndBind(t *testing.T) {
+	// Arrange
+	form := &signup{Email: "ada@example.com"}
+	form.Bind(form)
+	form.SetError("Email", "This address is taken.")
+
+	// Act
+	form.Bind(form)
+	kept := form.ErrorFor("Email")
+	valid := form.Validate()
+
+	// Assert
+	if kept != "This address is taken." {
+		t.Errorf("expected the error kept when binding the same model, got %q", kept)
+	}
+	if !valid || !form.IsValid("Email") {
+		t.Error("expected Validate to replace the error set by hand")
+	}
+	form.SetError("Email", "taken")
+	form.Bind(&signup{})
+	if !form.IsValid("Email") {
+		t.Error("expected binding another model to clear the errors")
+	}
+}
+
+// TestValidator_BindPanics verifies binding anything but a pointer to a struct panics.
+func TestValidator_BindPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Bind of a struct value to panic")
+		}
+	}()
+	var v Validator
+	v.Bind(signup{})
+}
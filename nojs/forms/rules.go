@@ -0,0 +1,240 @@
+package forms
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// fieldRules are the rules of one struct field, from its validate tag.
+type fieldRules struct {
+	name     string
+	index    []int
+	required bool
+	checks   []func(value reflect.Value) string // Each returns the message of a broken rule, or ""
+	message  string                             // Replaces the messages of the checks; from the message tag
+}
+
+// validate returns the message of the first rule value breaks, or "" when it is valid.
+func (f fieldRules) validate(value reflect.Value) string {
+	if isEmpty(value) {
+		if f.required {
+			return f.messageOr("This field is required.")
+		}
+		return "" // Optional and empty: nothing entered to check
+	}
+	for _, check := range f.checks {
+		if message := check(value); message != "" {
+			return f.messageOr(message)
+		}
+	}
+	return ""
+}
+
+// isEmpty reports whether value is empty: its zero value, a blank string, or a slice or map
+// without items.
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// messageOr returns the message tag of the field, or else message.
+func (f fieldRules) messageOr(message string) string {
+	if f.message != "" {
+		return f.message
+	}
+	return message
+}
+
+// rulesCache holds the rules of each struct type bound, by reflect.Type.
+var rulesCache sync.Map
+
+// rulesOf returns the rules of the fields of the struct type t that have a validate tag.
+func rulesOf(t reflect.Type) []fieldRules {
+	if cached, ok := rulesCache.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+	var fields []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+		if !field.IsExported() {
+			panic(fmt.Sprintf("forms: field %s.%s has a validate tag but is not exported", t.Name(), field.Name))
+		}
+		rules, err := parseRules(tag, field.Type)
+		if err != nil {
+			panic(fmt.Sprintf("forms: field %s.%s: %v", t.Name(), field.Name, err))
+		}
+		rules.name, rules.index, rules.message = field.Name, field.Index, field.Tag.Get("message")
+		fields = append(fields, rules)
+	}
+	rulesCache.Store(t, fields)
+	return fields
+}
+
+// parseRules parses a validate tag, such as "required,email,min=3", for a field of type t.
+func parseRules(tag string, t reflect.Type) (fieldRules, error) {
+	var rules fieldRules
+	for _, item := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(item), "=")
+		var check func(reflect.Value) string
+		var err error
+		switch name {
+		case "":
+			continue
+		case "required":
+			rules.required = true
+			continue
+		case "email":
+			check, err = emailRule(t)
+		case "url":
+			check, err = urlRule(t)
+		case "min":
+			check, err = boundRule(t, param, true)
+		case "max":
+			check, err = boundRule(t, param, false)
+		case "oneof":
+			check, err = oneOfRule(t, param)
+		default:
+			err = fmt.Errorf("unknown rule %q", name)
+		}
+		if err != nil {
+			return rules, err
+		}
+		rules.checks = append(rules.checks, check)
+	}
+	return rules, nil
+}
+
+// emailRule checks that a string is a bare email address, such as ada@example.com.
+func emailRule(t reflect.Type) (func(reflect.Value) string, error) {
+	if t.Kind() != reflect.String {
+		return nil, fmt.Errorf("rule email needs a string field, not %s", t)
+	}
+	return func(v reflect.Value) string {
+		s := v.String()
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s || !strings.Contains(s[strings.LastIndex(s, "@"):], ".") {
+			return "Enter a valid email address."
+		}
+		return ""
+	}, nil
+}
+
+// urlRule checks that a string is an absolute http or https URL.
+func urlRule(t reflect.Type) (func(reflect.Value) string, error) {
+	if t.Kind() != reflect.String {
+		return nil, fmt.Errorf("rule url needs a string field, not %s", t)
+	}
+	return func(v reflect.Value) string {
+		u, err := url.Parse(v.String())
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "Enter a valid URL, starting with http:// or https://."
+		}
+		return ""
+	}, nil
+}
+
+// boundRule checks the lower (min) or upper (max) bound param of a number, or of the length
+// of a string (in characters) or a slice.
+func boundRule(t reflect.Type, param string, lower bool) (func(reflect.Value) string, error) {
+	name := "max"
+	if lower {
+		name = "min"
+	}
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s needs a number, as in %s=3, got %q", name, name, param)
+	}
+	breaks := func(n float64) bool { return (lower && n < bound) || (!lower && n > bound) }
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		message := fmt.Sprintf("Enter a number no greater than %s.", param)
+		if lower {
+			message = fmt.Sprintf("Enter a number no less than %s.", param)
+		}
+		return func(v reflect.Value) string {
+			if breaks(numberOf(v)) {
+				return message
+			}
+			return ""
+		}, nil
+	case reflect.String, reflect.Slice, reflect.Map:
+		if bound != float64(int(bound)) {
+			return nil, fmt.Errorf("rule %s on a length needs a whole number, got %q", name, param)
+		}
+		unit := "characters"
+		switch {
+		case t.Kind() != reflect.String && bound == 1:
+			unit = "item"
+		case t.Kind() != reflect.String:
+			unit = "items"
+		case bound == 1:
+			unit = "character"
+		}
+		message := fmt.Sprintf("Enter at most %s %s.", param, unit)
+		if lower {
+			message = fmt.Sprintf("Enter at least %s %s.", param, unit)
+		}
+		return func(v reflect.Value) string {
+			n := v.Len()
+			if v.Kind() == reflect.String {
+				n = utf8.RuneCountInString(v.String())
+			}
+			if breaks(float64(n)) {
+				return message
+			}
+			return ""
+		}, nil
+	}
+	return nil, fmt.Errorf("rule %s needs a number, string, slice or map field, not %s", name, t)
+}
+
+// oneOfRule checks that a string or number is one of the space-separated values of param,
+// as in oneof=small medium large.
+func oneOfRule(t reflect.Type, param string) (func(reflect.Value) string, error) {
+	values := strings.Fields(param)
+	if len(values) == 0 {
+		return nil, fmt.Errorf("rule oneof needs values, as in oneof=small medium large")
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Struct, reflect.Pointer, reflect.Interface, reflect.Func, reflect.Chan, reflect.Array:
+		return nil, fmt.Errorf("rule oneof needs a string or number field, not %s", t)
+	}
+	message := "Choose one of: " + strings.Join(values, ", ") + "."
+	return func(v reflect.Value) string {
+		s := fmt.Sprint(v.Interface())
+		for _, value := range values {
+			if s == value {
+				return ""
+			}
+		}
+		return message
+	}, nil
+}
+
+// numberOf returns the value of an integer or float as a float64.
+func numberOf(v reflect.Value) float64 {
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
@@ -0,0 +1,114 @@
+//go:build !wasm
+// +build !wasm
+
+package forms
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type size string
+
+// ruleSubject has one field per rule under test, each optional unless required.
+type ruleSubject struct {
+	Name    string   `validate:"required"`
+	Agreed  bool     `validate:"required"`
+	Email   string   `validate:"email"`
+	Site    string   `validate:"url"`
+	Nick    string   `validate:"min=3,max=5"`
+	Age     int      `validate:"min=18,max=120"`
+	Price   float64  `validate:"min=0.5"`
+	Tags    []string `validate:"min=1,max=2"`
+	Size    size     `validate:"oneof=small medium large"`
+	Rating  uint8    `validate:"oneof=1 3 5"`
+	Company string   `validate:"required,min=2" message:"Name your company"`
+}
+
+// validateField returns the message of field of s.
+func validateField(t *testing.T, s ruleSubject, field string) string {
+	t.Helper()
+	var v Validator
+	v.Bind(&s)
+	v.ValidateField(field)
+	return v.ErrorFor(field)
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		subject ruleSubject
+		want    string // Substring of the message; "" for valid
+	}{
+		{"required empty", "Name", ruleSubject{}, "required"},
+		{"required blank", "Name", ruleSubject{Name: "  "}, "required"},
+		{"required set", "Name", ruleSubject{Name: "Ada"}, ""},
+		{"required bool unchecked", "Agreed", ruleSubject{}, "required"},
+		{"required bool checked", "Agreed", ruleSubject{Agreed: true}, ""},
+
+		{"email empty optional", "Email", ruleSubject{}, ""},
+		{"email valid", "Email", ruleSubject{Email: "ada@example.com"}, ""},
+		{"email without domain dot", "Email", ruleSubject{Email: "ada@localhost"}, "email"},
+		{"email with display name", "Email", ruleSubject{Email: "Ada <ada@example.com>"}, "email"},
+		{"email without at", "Email", ruleSubject{Email: "ada.example.com"}, "email"},
+
+		{"url empty optional", "Site", ruleSubject{}, ""},
+		{"url valid", "Site", ruleSubject{Site: "https://example.com/a?b=c"}, ""},
+		{"url relative", "Site", ruleSubject{Site: "/about"}, "URL"},
+		{"url other scheme", "Site", ruleSubject{Site: "javascript:alert(1)"}, "URL"},
+
+		{"min length empty optional", "Nick", ruleSubject{}, ""},
+		{"min length short", "Nick", ruleSubject{Nick: "ab"}, "at least 3 characters"},
+		{"min length counts characters", "Nick", ruleSubject{Nick: "éèê"}, ""},
+		{"max length long", "Nick", ruleSubject{Nick: "abcdef"}, "at most 5 characters"},
+		{"min number zero optional", "Age", ruleSubject{}, ""},
+		{"min number low", "Age", ruleSubject{Age: 17}, "no less than 18"},
+		{"min number bound", "Age", ruleSubject{Age: 18}, ""},
+		{"max number high", "Age", ruleSubject{Age: 121}, "no greater than 120"},
+		{"min float low", "Price", ruleSubject{Price: 0.25}, "no less than 0.5"},
+		{"min items empty optional", "Tags", ruleSubject{Tags: []string{}}, ""},
+		{"max items many", "Tags", ruleSubject{Tags: []string{"a", "b", "c"}}, "at most 2 items"},
+
+		{"oneof empty optional", "Size", ruleSubject{}, ""},
+		{"oneof listed", "Size", ruleSubject{Size: "medium"}, ""},
+		{"oneof unlisted", "Size", ruleSubject{Size: "huge"}, "small, medium, large"},
+		{"oneof number", "Rating", ruleSubject{Rating: 3}, ""},
+		{"oneof number unlisted", "Rating", ruleSubject{Rating: 4}, "1, 3, 5"},
+
+		{"message replaces required", "Company", ruleSubject{}, "Name your company"},
+		{"message replaces min", "Company", ruleSubject{Company: "X"}, "Name your company"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateField(t, tt.subject, tt.field)
+			if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("expected a message containing %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRules_Errors(t *testing.T) {
+	tests := []struct {
+		tag  string
+		typ  reflect.Type
+		want string
+	}{
+		{"required,phone", reflect.TypeOf(""), `unknown rule "phone"`},
+		{"email", reflect.TypeOf(0), "rule email needs a string field"},
+		{"min=three", reflect.TypeOf(""), "rule min needs a number"},
+		{"max=2.5", reflect.TypeOf(""), "needs a whole number"},
+		{"min=1", reflect.TypeOf(false), "rule min needs a number, string, slice or map field"},
+		{"oneof=", reflect.TypeOf(""), "rule oneof needs values"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			_, err := parseRules(tt.tag, tt.typ)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package head manages the document's <title> and <meta> tags. Route components describe
+// them by implementing HeadProvider, and the router applies the chain shown after each
+// navigation: the leaf page overrides the defaults of its layouts, and the title of
+// index.html comes back on pages that set none.
+//
+//	func (p *AboutPage) Title() string { return "About – My App" }
+//
+//	func (p *AboutPage) Meta() []head.Meta {
+//	    return []head.Meta{
+//	        {Name: "description", Content: "Who we are"},
+//	        {Property: "og:title", Content: "About us"},
+//	    }
+//	}
+//
+// A page whose title depends on data it loads later sets it with SetTitle once the data is
+// there; the next navigation applies the route's title again.
+package head
+
+import (
+	"sync"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Meta is a <meta> tag: named (<meta name="description">) or, with Property set, an Open
+// Graph property (<meta property="og:title">).
+type Meta struct {
+	Name     string
+	Property string
+	Content  string
+}
+
+// key identifies the tag m replaces: its property, or else its name.
+func (m Meta) key() string {
+	if m.Property != "" {
+		return m.Property
+	}
+	return m.Name
+}
+
+// HeadProvider is implemented by components that set the document's title and meta tags
+// while they are shown by the router. An empty title leaves the title to the components
+// above; a meta tag replaces the one of the same name or property a layout provides.
+type HeadProvider interface {
+	Title() string
+	Meta() []Meta
+}
+
+// document is the head state applied so far.
+type document struct {
+	mu           sync.Mutex
+	captured     bool            // initialTitle was read, before the first change
+	initialTitle string          // The title of index.html
+	titleSet     bool            // The title was changed by this package
+	applied      map[string]Meta // Tags set by Apply, by key
+}
+
+var doc = &document{}
+
+// SetTitle sets document.title at once. The next navigation replaces it with the title of
+// the route's components, or the title of index.html when they provide none.
+func SetTitle(title string) {
+	doc.mu.Lock()
+	doc.capture()
+	doc.titleSet = true
+	doc.mu.Unlock()
+
+	dom.setTitle(title)
+}
+
+// Apply sets the title and meta tags provided by chain, the components shown from the
+// outermost layout to the page; nil entries and components that are not HeadProviders are
+// skipped. Tags set by an earlier Apply that chain no longer provides are removed, or get
+// back the content they had in index.html. The router calls it after each navigation.
+func Apply(chain []runtime.Component) {
+	title := ""
+	tags := make(map[string]Meta)
+	var order []string
+	for _, c := range chain {
+		provider, ok := c.(HeadProvider)
+		if !ok {
+			continue
+		}
+		if t := provider.Title(); t != "" {
+			title = t
+		}
+		for _, m := range provider.Meta() {
+			k := m.key()
+			if k == "" {
+				continue
+			}
+			if _, seen := tags[k]; !seen {
+				order = append(order, k)
+			}
+			tags[k] = m
+		}
+	}
+
+	doc.mu.Lock()
+	doc.capture()
+	writeTitle := title != "" || doc.titleSet
+	if title == "" {
+		title = doc.initialTitle
+	}
+	doc.titleSet = title != doc.initialTitle
+	previous := doc.applied
+	doc.applied = tags
+	doc.mu.Unlock()
+
+	if writeTitle {
+		dom.setTitle(title)
+	}
+	for k, m := range previous {
+		if _, kept := tags[k]; !kept {
+			dom.removeMeta(m)
+		}
+	}
+	for _, k := range order {
+		dom.setMeta(tags[k])
+	}
+}
+
+// DocumentTitle returns document.title.
+func DocumentTitle() string {
+	return dom.title()
+}
+
+// DocumentMeta returns the content of the <meta> tag whose name or property is key, and
+// whether there is one.
+func DocumentMeta(key string) (string, bool) {
+	return dom.meta(key)
+}
+
+// capture reads the title of index.html before the first change. The caller holds d.mu.
+func (d *document) capture() {
+	if !d.captured {
+		d.initialTitle = dom.title()
+		d.captured = true
+	}
+}
+
+// headDOM is the document the head state is written to: the browser's in WASM builds, an
+// in-memory one otherwise.
+type headDOM interface {
+	title() string
+	setTitle(title string)
+	meta(key string) (string, bool)
+	setMeta(m Meta)    // Creates or updates the tag of m's key
+	removeMeta(m Meta) // Removes the tag of m's key, or restores its index.html content
+}
@@ -0,0 +1,71 @@
+//go:build !wasm
+// +build !wasm
+
+package head
+
+import "sync"
+
+// memoryDOM is an in-memory document for non-WASM builds.
+type memoryDOM struct {
+	mu       sync.Mutex
+	docTitle string
+	tags     map[string]string // Content by key
+	original map[string]string // Tags of the loaded page, by key
+}
+
+var dom headDOM = &memoryDOM{tags: make(map[string]string), original: make(map[string]string)}
+
+func (d *memoryDOM) title() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.docTitle
+}
+
+func (d *memoryDOM) setTitle(title string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.docTitle = title
+}
+
+func (d *memoryDOM) meta(key string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	content, ok := d.tags[key]
+	return content, ok
+}
+
+func (d *memoryDOM) setMeta(m Meta) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tags[m.key()] = m.Content
+}
+
+func (d *memoryDOM) removeMeta(m Meta) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if content, ok := d.original[m.key()]; ok {
+		d.tags[m.key()] = content
+		return
+	}
+	delete(d.tags, m.key())
+}
+
+// ResetDocument replaces the in-memory document with a freshly loaded page having title and
+// the tags meta, as index.html, and forgets what Apply and SetTitle did. It exists in
+// non-WASM builds only, for tests.
+func ResetDocument(title string, meta ...Meta) {
+	d := dom.(*memoryDOM)
+	d.mu.Lock()
+	d.docTitle = title
+	d.tags = make(map[string]string)
+	d.original = make(map[string]string)
+	for _, m := range meta {
+		d.tags[m.key()] = m.Content
+		d.original[m.key()] = m.Content
+	}
+	d.mu.Unlock()
+
+	doc.mu.Lock()
+	doc.captured, doc.initialTitle, doc.titleSet, doc.applied = false, "", false, nil
+	doc.mu.Unlock()
+}
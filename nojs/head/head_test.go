@@ -0,0 +1,110 @@
+//go:build !wasm
+// +build !wasm
+
+package head
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// headTestComponent provides a fixed title and meta tags.
+type headTestComponent struct {
+	runtime.ComponentBase
+	title string
+	meta  []Meta
+}
+
+func (c *headTestComponent) Render(r runtime.Renderer) *vdom.VNode { return vdom.Div(nil) }
+func (c *headTestComponent) Title() string                         { return c.title }
+func (c *headTestComponent) Meta() []Meta                          { return c.meta }
+
+// plainTestComponent provides nothing.
+type plainTestComponent struct {
+	runtime.ComponentBase
+}
+
+func (c *plainTestComponent) Render(r runtime.Renderer) *vdom.VNode { return vdom.Div(nil) }
+
+// loadPage resets the document to an index.html with a title and a description.
+func loadPage(t *testing.T) {
+	t.Helper()
+	ResetDocument("My App", Meta{Name: "description", Content: "Default"})
+	t.Cleanup(func() { ResetDocument("") })
+}
+
+// TestApply_LeafOverridesLayout verifies the page's title and tags win over its layout's,
+// and tags with the same name replace each other.
+func TestApply_LeafOverridesLayout(t *testing.T) {
+	// Arrange
+	loadPage(t)
+	layout := &headTestComponent{title: "Docs – My App", meta: []Meta{
+		{Name: "description", Content: "Documentation"},
+		{Property: "og:site_name", Content: "My App"},
+	}}
+	page := &headTestComponent{title: "Install – My App", meta: []Meta{
+		{Name: "description", Content: "First draft"},
+		{Name: "description", Content: "How to install"},
+	}}
+
+	// Act
+	Apply([]runtime.Component{layout, page})
+
+	// Assert
+	if got := DocumentTitle(); got != "Install – My App" {
+		t.Errorf("expected the page title, got %q", got)
+	}
+	if got, _ := DocumentMeta("description"); got != "How to install" {
+		t.Errorf("expected the page's last description, got %q", got)
+	}
+	if got, _ := DocumentMeta("og:site_name"); got != "My App" {
+		t.Errorf("expected the layout's tag to stay, got %q", got)
+	}
+}
+
+// TestApply_RevertsOnPagesWithoutHead verifies navigating to a page without a title or tags
+// brings back index.html's, and removes tags index.html did not have.
+func TestApply_RevertsOnPagesWithoutHead(t *testing.T) {
+	// Arrange
+	loadPage(t)
+	Apply([]runtime.Component{&headTestComponent{title: "About", meta: []Meta{
+		{Name: "description", Content: "About us"},
+		{Property: "og:title", Content: "About"},
+	}}})
+
+	// Act
+	Apply([]runtime.Component{&plainTestComponent{}, nil})
+
+	// Assert
+	if got := DocumentTitle(); got != "My App" {
+		t.Errorf("expected the title of index.html, got %q", got)
+	}
+	if got, _ := DocumentMeta("description"); got != "Default" {
+		t.Errorf("expected the description of index.html, got %q", got)
+	}
+	if _, ok := DocumentMeta("og:title"); ok {
+		t.Error("expected the page's own tag to be removed")
+	}
+}
+
+// TestSetTitle_LastsUntilNextApply verifies SetTitle changes the title at once and the next
+// navigation replaces it, even with a page providing none.
+func TestSetTitle_LastsUntilNextApply(t *testing.T) {
+	// Arrange
+	loadPage(t)
+
+	// Act
+	SetTitle("Loading…")
+	set := DocumentTitle()
+	Apply([]runtime.Component{&plainTestComponent{}})
+
+	// Assert
+	if set != "Loading…" {
+		t.Errorf("expected SetTitle to apply at once, got %q", set)
+	}
+	if got := DocumentTitle(); got != "My App" {
+		t.Errorf("expected the next navigation to restore the title, got %q", got)
+	}
+}
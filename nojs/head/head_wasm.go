@@ -0,0 +1,84 @@
+//go:build js || wasm
+// +build js wasm
+
+package head
+
+import "syscall/js"
+
+// Attributes marking the <meta> tags this package touched: tags it created, which it
+// removes, and the index.html content of tags it overrode, which it restores.
+const (
+	createdAttr  = "data-nojs-head"
+	originalAttr = "data-nojs-original"
+)
+
+// browserDOM is the browser's document.
+type browserDOM struct{}
+
+var dom headDOM = browserDOM{}
+
+func (browserDOM) title() string {
+	return js.Global().Get("document").Get("title").String()
+}
+
+func (browserDOM) setTitle(title string) {
+	js.Global().Get("document").Set("title", title)
+}
+
+func (browserDOM) meta(key string) (string, bool) {
+	el := findMeta(key)
+	if el.IsNull() {
+		return "", false
+	}
+	return attribute(el, "content"), true
+}
+
+func (browserDOM) setMeta(m Meta) {
+	el := findMeta(m.key())
+	if el.IsNull() {
+		el = js.Global().Get("document").Call("createElement", "meta")
+		if m.Property != "" {
+			el.Call("setAttribute", "property", m.Property)
+		} else {
+			el.Call("setAttribute", "name", m.Name)
+		}
+		el.Call("setAttribute", createdAttr, "")
+		js.Global().Get("document").Get("head").Call("appendChild", el)
+	} else if !el.Call("hasAttribute", createdAttr).Bool() && !el.Call("hasAttribute", originalAttr).Bool() {
+		el.Call("setAttribute", originalAttr, attribute(el, "content"))
+	}
+	el.Call("setAttribute", "content", m.Content)
+}
+
+func (browserDOM) removeMeta(m Meta) {
+	el := findMeta(m.key())
+	switch {
+	case el.IsNull():
+	case el.Call("hasAttribute", originalAttr).Bool():
+		el.Call("setAttribute", "content", attribute(el, originalAttr))
+		el.Call("removeAttribute", originalAttr)
+	case el.Call("hasAttribute", createdAttr).Bool():
+		el.Call("remove")
+	}
+}
+
+// findMeta returns the <meta> tag of <head> whose property or name is key, or null.
+func findMeta(key string) js.Value {
+	tags := js.Global().Get("document").Get("head").Call("getElementsByTagName", "meta")
+	for i := 0; i < tags.Length(); i++ {
+		el := tags.Index(i)
+		if attribute(el, "property") == key || attribute(el, "name") == key {
+			return el
+		}
+	}
+	return js.Null()
+}
+
+// attribute returns the value of el's attribute, "" when it has none.
+func attribute(el js.Value, name string) string {
+	v := el.Call("getAttribute", name)
+	if v.IsNull() {
+		return ""
+	}
+	return v.String()
+}
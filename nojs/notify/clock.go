@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of a Notifier. Auto-dismiss timers are scheduled through it, so
+// tests can replace the system clock with a ManualClock and expire toasts deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls f in its own goroutine once d has elapsed. The returned function
+	// cancels the call and reports whether it did so before f ran.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// SystemClock is the Clock backed by the time package, used when Config.Clock is nil.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// ManualClock is a Clock that only moves when Advance is called. Timers run synchronously
+// inside Advance, on the caller's goroutine, in the order of their deadlines.
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	seq    int // Orders timers with the same deadline by creation
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	when time.Time
+	seq  int
+	f    func()
+}
+
+// NewManualClock returns a ManualClock reading start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the time the clock has been advanced to.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// AfterFunc schedules f to run when the clock is advanced by d or more.
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	timer := &manualTimer{when: c.now.Add(d), seq: c.seq, f: f}
+	c.timers = append(c.timers, timer)
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for i, pending := range c.timers {
+			if pending == timer {
+				c.timers = append(c.timers[:i], c.timers[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance moves the clock forward by d, running every timer that falls due on the way.
+// A timer scheduled by another timer runs too if its deadline is within d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	for {
+		sort.Slice(c.timers, func(i, j int) bool {
+			a, b := c.timers[i], c.timers[j]
+			return a.when.Before(b.when) || (a.when.Equal(b.when) && a.seq < b.seq)
+		})
+		if len(c.timers) == 0 || c.timers[0].when.After(target) {
+			break
+		}
+		timer := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = timer.when
+		c.mu.Unlock()
+		timer.f()
+		c.mu.Lock()
+	}
+	c.now = target
+	c.mu.Unlock()
+}
+
+// Pending returns the number of timers that have not run or been stopped.
+func (c *ManualClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
@@ -0,0 +1,313 @@
+// Package notify shows transient notifications (toasts). A Notifier is shared by the whole
+// application, usually through injection, and any handler or goroutine raises toasts with
+// Success, Error or Info. A host component mounted once renders the queue: it watches the
+// Notifier, so raising a toast re-renders the host and nothing else.
+//
+//	type SaveButton struct {
+//	    runtime.ComponentBase
+//	    Toasts *notify.Notifier `nojs:"inject"`
+//	}
+//
+//	func (c *SaveButton) Save() {
+//	    go func() {
+//	        if err := save(); err != nil {
+//	            c.Toasts.Error("Saving failed: "+err.Error(), notify.Options{})
+//	            return
+//	        }
+//	        c.Toasts.Success("Saved", notify.Options{})
+//	    }()
+//	}
+package notify
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ForgeLogic/nojs/signals"
+)
+
+// Kind is the severity of a toast.
+type Kind int
+
+const (
+	KindInfo Kind = iota
+	KindSuccess
+	KindError
+)
+
+// String returns the name of the kind, as used in the toast's CSS class.
+func (k Kind) String() string {
+	switch k {
+	case KindSuccess:
+		return "success"
+	case KindError:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+// Options tunes a single toast.
+type Options struct {
+	// Duration is how long the toast stays visible before it dismisses itself. Zero uses
+	// Config.Duration; a negative duration keeps the toast until it is dismissed.
+	Duration time.Duration
+}
+
+// Toast is a notification as the host renders it.
+type Toast struct {
+	ID      int
+	Kind    Kind
+	Message string
+	Class   string // "toast toast-<kind>", plus "toast-paused" while hovered
+	Paused  bool   // The auto-dismiss timer is stopped (the pointer is over the toast)
+}
+
+// State is a snapshot of the queue.
+type State struct {
+	Visible []Toast // Oldest first, at most Config.MaxVisible
+	Queued  int     // Toasts waiting for a visible slot
+
+	// Polite and Urgent hold the message of the latest toast that became visible, for an
+	// aria-live="polite" and an aria-live="assertive" region respectively: errors are
+	// announced as urgent, everything else as polite.
+	Polite string
+	Urgent string
+}
+
+// Config configures a Notifier. Zero fields take their defaults.
+type Config struct {
+	MaxVisible int           // Toasts shown at once; the rest wait in order. Default 3
+	Duration   time.Duration // Default auto-dismiss delay. Default 5s
+	Clock      Clock         // Time source for auto-dismiss. Default SystemClock
+}
+
+// Default values of Config.
+const (
+	DefaultMaxVisible = 3
+	DefaultDuration   = 5 * time.Second
+)
+
+// entry is one toast in the queue with its auto-dismiss timer.
+type entry struct {
+	toast     Toast
+	duration  time.Duration // Negative: no auto-dismiss
+	remaining time.Duration // Time left on the timer; counts down only while visible and not paused
+	started   time.Time     // When the running timer was started
+	timer     *timer        // The running timer; nil when none runs
+	visible   bool
+}
+
+// timer is a scheduled auto-dismiss. Its identity tells a firing timer whether it is still
+// the entry's current one, since a timer stopped too late still fires.
+type timer struct {
+	stop func() bool
+}
+
+// stopTimerLocked cancels the running timer of e, if any.
+func (e *entry) stopTimerLocked() {
+	if e.timer != nil {
+		e.timer.stop()
+		e.timer = nil
+	}
+}
+
+// Notifier is the queue of toasts. Its methods may be called from any goroutine: changes are
+// made under the Notifier's lock and watchers are notified after it is released.
+type Notifier struct {
+	mu      sync.Mutex
+	cfg     Config
+	nextID  int
+	entries []*entry // Visible entries first, then queued ones, each in arrival order
+	polite  string
+	urgent  string
+	version int
+
+	changes *signals.Signal[int] // Version of the queue, set after each change
+}
+
+// New creates a Notifier.
+func New(cfg Config) *Notifier {
+	if cfg.MaxVisible <= 0 {
+		cfg.MaxVisible = DefaultMaxVisible
+	}
+	if cfg.Duration <= 0 {
+		cfg.Duration = DefaultDuration
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+	return &Notifier{cfg: cfg, changes: signals.NewSignal(0)}
+}
+
+// Success shows a success toast and returns its ID.
+func (n *Notifier) Success(message string, opts Options) int {
+	return n.show(KindSuccess, message, opts)
+}
+
+// Error shows an error toast, announced as urgent, and returns its ID.
+func (n *Notifier) Error(message string, opts Options) int {
+	return n.show(KindError, message, opts)
+}
+
+// Info shows an informational toast and returns its ID.
+func (n *Notifier) Info(message string, opts Options) int {
+	return n.show(KindInfo, message, opts)
+}
+
+func (n *Notifier) show(kind Kind, message string, opts Options) int {
+	n.mu.Lock()
+	n.nextID++
+	e := &entry{
+		toast:    Toast{ID: n.nextID, Kind: kind, Message: message},
+		duration: opts.Duration,
+	}
+	if e.duration == 0 {
+		e.duration = n.cfg.Duration
+	}
+	e.remaining = e.duration
+	n.entries = append(n.entries, e)
+	n.promoteLocked()
+	version := n.changedLocked()
+	n.mu.Unlock()
+
+	n.changes.Set(version)
+	return e.toast.ID
+}
+
+// Dismiss removes the toast with the given ID, visible or queued, and shows the next queued
+// toast in its place. Unknown IDs (a toast that already expired) are ignored.
+func (n *Notifier) Dismiss(id int) {
+	n.update(id, func(i int, e *entry) bool {
+		e.stopTimerLocked()
+		n.removeLocked(i)
+		return true
+	})
+}
+
+// Pause stops the auto-dismiss timer of a visible toast, keeping the time it has left. Hosts
+// call it when the pointer enters the toast.
+func (n *Notifier) Pause(id int) {
+	n.update(id, func(_ int, e *entry) bool {
+		if !e.visible || e.toast.Paused {
+			return false
+		}
+		if e.timer != nil {
+			e.stopTimerLocked()
+			e.remaining -= n.cfg.Clock.Now().Sub(e.started)
+		}
+		e.toast.Paused = true
+		return true
+	})
+}
+
+// Resume restarts the auto-dismiss timer of a paused toast with the time it had left. Hosts
+// call it when the pointer leaves the toast.
+func (n *Notifier) Resume(id int) {
+	n.update(id, func(_ int, e *entry) bool {
+		if !e.toast.Paused {
+			return false
+		}
+		e.toast.Paused = false
+		n.startTimerLocked(e)
+		return true
+	})
+}
+
+// update runs change on the entry with the given ID, at index i of the queue, under the lock
+// and notifies watchers when change reports that it changed something.
+func (n *Notifier) update(id int, change func(i int, e *entry) bool) {
+	n.mu.Lock()
+	for i, e := range n.entries {
+		if e.toast.ID == id {
+			if !change(i, e) {
+				break
+			}
+			version := n.changedLocked()
+			n.mu.Unlock()
+			n.changes.Set(version)
+			return
+		}
+	}
+	n.mu.Unlock()
+}
+
+// removeLocked removes the entry at index i and shows the next queued one in its place.
+func (n *Notifier) removeLocked(i int) {
+	n.entries = append(n.entries[:i], n.entries[i+1:]...)
+	n.promoteLocked()
+}
+
+// promoteLocked makes queued entries visible while there is room and starts their timers.
+func (n *Notifier) promoteLocked() {
+	for i, e := range n.entries {
+		if i >= n.cfg.MaxVisible {
+			return
+		}
+		if e.visible {
+			continue
+		}
+		e.visible = true
+		n.startTimerLocked(e)
+		if e.toast.Kind == KindError {
+			n.urgent = e.toast.Message
+		} else {
+			n.polite = e.toast.Message
+		}
+	}
+}
+
+// startTimerLocked schedules the auto-dismiss of e for the time it has left.
+func (n *Notifier) startTimerLocked(e *entry) {
+	if e.duration < 0 {
+		return
+	}
+	e.started = n.cfg.Clock.Now()
+	t := &timer{}
+	e.timer = t
+	t.stop = n.cfg.Clock.AfterFunc(e.remaining, func() {
+		n.update(e.toast.ID, func(i int, e *entry) bool {
+			if e.timer != t {
+				return false // Paused or restarted since
+			}
+			e.timer = nil
+			n.removeLocked(i)
+			return true
+		})
+	})
+}
+
+// changedLocked records a change and returns the new version.
+func (n *Notifier) changedLocked() int {
+	n.version++
+	return n.version
+}
+
+// State returns a snapshot of the queue.
+func (n *Notifier) State() State {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	state := State{Polite: n.polite, Urgent: n.urgent}
+	for _, e := range n.entries {
+		if !e.visible {
+			state.Queued++
+			continue
+		}
+		toast := e.toast
+		toast.Class = "toast toast-" + toast.Kind.String()
+		if toast.Paused {
+			toast.Class += " toast-paused"
+		}
+		state.Visible = append(state.Visible, toast)
+	}
+	return state
+}
+
+// Watch registers fn to run after every change of the queue and returns a function that
+// removes it. Hosts watch the Notifier from OnMount and read State in fn. The method is
+// deliberately not named Subscribe: injected values with a Subscribe method re-render every
+// component they are injected into, and components that only raise toasts must not
+// re-render when the queue changes.
+func (n *Notifier) Watch(fn func()) (stop func()) {
+	return n.changes.Subscribe(fn)
+}
@@ -0,0 +1,184 @@
+//go:build !wasm
+// +build !wasm
+
+package notify
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestNotifier returns a Notifier on a ManualClock with a 4s default duration.
+func newTestNotifier(maxVisible int) (*Notifier, *ManualClock) {
+	clock := NewManualClock(time.Unix(0, 0))
+	return New(Config{MaxVisible: maxVisible, Duration: 4 * time.Second, Clock: clock}), clock
+}
+
+// messages returns the messages of the visible toasts, in order.
+func messages(state State) []string {
+	var out []string
+	for _, toast := range state.Visible {
+		out = append(out, toast.Message)
+	}
+	return out
+}
+
+func equalMessages(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestNotifier_QueuesBeyondMaxVisible verifies toasts beyond MaxVisible wait, without a
+// running timer, until a visible one leaves.
+func TestNotifier_QueuesBeyondMaxVisible(t *testing.T) {
+	// Arrange
+	n, clock := newTestNotifier(2)
+	first := n.Info("one", Options{})
+	n.Success("two", Options{})
+	n.Error("three", Options{})
+
+	// Act
+	before := n.State()
+	n.Dismiss(first)
+	after := n.State()
+
+	// Assert
+	if got := messages(before); !equalMessages(got, []string{"one", "two"}) || before.Queued != 1 {
+		t.Errorf("expected [one two] with 1 queued, got %v with %d queued", got, before.Queued)
+	}
+	if got := messages(after); !equalMessages(got, []string{"two", "three"}) || after.Queued != 0 {
+		t.Errorf("expected [two three] after dismissing the first, got %v with %d queued", got, after.Queued)
+	}
+	if clock.Pending() != 2 {
+		t.Errorf("expected a timer per visible toast, got %d", clock.Pending())
+	}
+	if after.Urgent != "three" || after.Polite != "two" {
+		t.Errorf("expected the error announced as urgent and the success as polite, got %q and %q", after.Urgent, after.Polite)
+	}
+}
+
+// TestNotifier_AutoDismissCountsOnlyVisibleTime verifies a queued toast's timer starts when it
+// becomes visible, not when it is raised.
+func TestNotifier_AutoDismissCountsOnlyVisibleTime(t *testing.T) {
+	// Arrange
+	n, clock := newTestNotifier(1)
+	n.Info("one", Options{})
+	n.Info("two", Options{Duration: 2 * time.Second})
+
+	// Act
+	clock.Advance(4 * time.Second) // "one" expires, "two" becomes visible
+	middle := n.State()
+	clock.Advance(time.Second)
+	stillShown := n.State()
+	clock.Advance(time.Second)
+	final := n.State()
+
+	// Assert
+	if got := messages(middle); !equalMessages(got, []string{"two"}) {
+		t.Errorf("expected [two] after the first expired, got %v", got)
+	}
+	if got := messages(stillShown); !equalMessages(got, []string{"two"}) {
+		t.Errorf("expected [two] one second into its two, got %v", got)
+	}
+	if len(final.Visible) != 0 {
+		t.Errorf("expected no toasts, got %v", messages(final))
+	}
+}
+
+// TestNotifier_PauseKeepsRemainingTime verifies a paused toast does not expire and resumes with
+// the time it had left.
+func TestNotifier_PauseKeepsRemainingTime(t *testing.T) {
+	// Arrange
+	n, clock := newTestNotifier(3)
+	id := n.Info("hover me", Options{})
+	clock.Advance(3 * time.Second)
+
+	// Act
+	n.Pause(id)
+	clock.Advance(time.Minute)
+	paused := n.State()
+	n.Resume(id)
+	clock.Advance(999 * time.Millisecond)
+	resumed := n.State()
+	clock.Advance(time.Millisecond)
+	expired := n.State()
+
+	// Assert
+	if len(paused.Visible) != 1 || !paused.Visible[0].Paused || paused.Visible[0].Class != "toast toast-info toast-paused" {
+		t.Fatalf("expected the paused toast to stay, got %+v", paused.Visible)
+	}
+	if len(resumed.Visible) != 1 || resumed.Visible[0].Paused {
+		t.Errorf("expected the resumed toast shown with under a second left, got %+v", resumed.Visible)
+	}
+	if len(expired.Visible) != 0 {
+		t.Errorf("expected the toast to expire after its remaining second, got %+v", expired.Visible)
+	}
+}
+
+// TestNotifier_StickyToastAndUnknownIDs verifies a negative duration disables auto-dismiss and
+// calls with unknown IDs change nothing.
+func TestNotifier_StickyToastAndUnknownIDs(t *testing.T) {
+	// Arrange
+	n, clock := newTestNotifier(3)
+	n.Error("sticky", Options{Duration: -1})
+	changes := 0
+	stop := n.Watch(func() { changes++ })
+	defer stop()
+
+	// Act
+	clock.Advance(time.Hour)
+	n.Dismiss(42)
+	n.Pause(42)
+	n.Resume(42)
+
+	// Assert
+	if got := messages(n.State()); !equalMessages(got, []string{"sticky"}) {
+		t.Errorf("expected the sticky toast to stay, got %v", got)
+	}
+	if changes != 0 {
+		t.Errorf("expected no change notifications, got %d", changes)
+	}
+}
+
+// TestNotifier_ConcurrentCallers verifies toasts raised from many goroutines are all queued and
+// watchers are notified of each.
+func TestNotifier_ConcurrentCallers(t *testing.T) {
+	// Arrange
+	n, _ := newTestNotifier(3)
+	var mu sync.Mutex
+	changes := 0
+	stop := n.Watch(func() {
+		mu.Lock()
+		changes++
+		mu.Unlock()
+	})
+	defer stop()
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n.Success("saved", Options{})
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	state := n.State()
+	if len(state.Visible) != 3 || state.Queued != 17 {
+		t.Errorf("expected 3 visible and 17 queued, got %d and %d", len(state.Visible), state.Queued)
+	}
+	if changes != 20 {
+		t.Errorf("expected 20 change notifications, got %d", changes)
+	}
+}
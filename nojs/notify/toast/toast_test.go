@@ -0,0 +1,168 @@
+//go:build !wasm
+// +build !wasm
+
+package toast
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/notify"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// mountHost renders a ToastHost for a Notifier showing three toasts for 5s on a manual clock,
+// and mounts it so it watches the queue.
+func mountHost() (*notify.Notifier, *notify.ManualClock, *rendertest.TestRenderer) {
+	clock := notify.NewManualClock(time.Unix(0, 0))
+	notifier := notify.New(notify.Config{MaxVisible: 3, Duration: 5 * time.Second, Clock: clock})
+	host := &ToastHost{Notifier: notifier}
+	renderer := rendertest.NewTestRenderer(host)
+	renderer.RenderRoot()
+	host.OnMount()
+	return notifier, clock, renderer
+}
+
+// byClass returns the nodes under n whose class list contains class, in document order.
+func byClass(n *vdom.VNode, class string) []*vdom.VNode {
+	if n == nil {
+		return nil
+	}
+	var found []*vdom.VNode
+	if classes, _ := n.Attributes["class"].(string); strings.Contains(" "+classes+" ", " "+class+" ") {
+		found = append(found, n)
+	}
+	for _, child := range n.Children {
+		found = append(found, byClass(child, class)...)
+	}
+	return found
+}
+
+// cards returns the messages of the rendered toast cards.
+func cards(root *vdom.VNode) []string {
+	var out []string
+	for _, message := range byClass(root, "toast-message") {
+		out = append(out, message.Children[0].Content)
+	}
+	return out
+}
+
+// more returns the text of the queued-toasts line, or "" when it is not rendered.
+func more(root *vdom.VNode) string {
+	if lines := byClass(root, "toast-more"); len(lines) == 1 {
+		return lines[0].Content
+	}
+	return ""
+}
+
+// TestToastHost_CapsVisibleToasts verifies five toasts render three cards and a line
+// counting the two waiting, and that the latest visible ones are announced.
+func TestToastHost_CapsVisibleToasts(t *testing.T) {
+	// Arrange
+	notifier, _, renderer := mountHost()
+
+	// Act
+	notifier.Info("one", notify.Options{})
+	notifier.Success("two", notify.Options{})
+	notifier.Error("three", notify.Options{})
+	notifier.Info("four", notify.Options{})
+	notifier.Info("five", notify.Options{})
+	root := renderer.WaitForRender(t)
+
+	// Assert
+	if got := strings.Join(cards(root), ","); got != "one,two,three" {
+		t.Errorf("expected cards one,two,three, got %q", got)
+	}
+	if got := more(root); got != "2 more" {
+		t.Errorf("expected '2 more', got %q", got)
+	}
+	if classes := byClass(root, "toast-error"); len(classes) != 1 {
+		t.Errorf("expected one error card, got %d", len(classes))
+	}
+	polite := byClass(root, "toast-announcer")[0]
+	urgent := byClass(root, "toast-announcer")[1]
+	if polite.Attributes["aria-live"] != "polite" || polite.Children[0].Content != "two" {
+		t.Errorf("expected 'two' announced politely, got %q", polite.Children[0].Content)
+	}
+	if urgent.Attributes["aria-live"] != "assertive" || urgent.Children[0].Content != "three" {
+		t.Errorf("expected 'three' announced assertively, got %q", urgent.Children[0].Content)
+	}
+}
+
+// TestToastHost_AutoDismissShowsQueuedToasts verifies expired toasts leave the host and the
+// queued ones take their place with a full timer of their own.
+func TestToastHost_AutoDismissShowsQueuedToasts(t *testing.T) {
+	// Arrange
+	notifier, clock, renderer := mountHost()
+	for _, message := range []string{"one", "two", "three", "four", "five"} {
+		notifier.Info(message, notify.Options{})
+	}
+
+	// Act
+	clock.Advance(5 * time.Second)
+	promoted := renderer.WaitForRender(t)
+	clock.Advance(4 * time.Second)
+	beforeExpiry := renderer.WaitForRender(t)
+	clock.Advance(time.Second)
+	empty := renderer.WaitForRender(t)
+
+	// Assert
+	if got := strings.Join(cards(promoted), ","); got != "four,five" || more(promoted) != "" {
+		t.Errorf("expected cards four,five and no queue, got %q and %q", got, more(promoted))
+	}
+	if got := strings.Join(cards(beforeExpiry), ","); got != "four,five" {
+		t.Errorf("expected the promoted toasts to count their 5s from promotion, got %q", got)
+	}
+	if got := cards(empty); len(got) != 0 {
+		t.Errorf("expected no cards, got %v", got)
+	}
+}
+
+// TestToastCard_HoverPausesAutoDismiss verifies the pointer over a card stops its timer and
+// leaving the card restarts it with the time it had left.
+func TestToastCard_HoverPausesAutoDismiss(t *testing.T) {
+	// Arrange
+	notifier, clock, renderer := mountHost()
+	notifier.Success("saved", notify.Options{})
+	clock.Advance(4 * time.Second)
+	card := byClass(renderer.WaitForRender(t), "toast")[0]
+
+	// Act
+	rendertest.FireEvent(t, card, "mouseenter", events.MouseEventArgs{})
+	clock.Advance(time.Minute)
+	hovered := renderer.WaitForRender(t)
+	rendertest.FireEvent(t, byClass(hovered, "toast")[0], "mouseleave", events.MouseEventArgs{})
+	clock.Advance(time.Second)
+	left := renderer.WaitForRender(t)
+
+	// Assert
+	if paused := byClass(hovered, "toast-paused"); len(paused) != 1 || cards(hovered)[0] != "saved" {
+		t.Errorf("expected the hovered card to stay, paused, got %v", cards(hovered))
+	}
+	if got := cards(left); len(got) != 0 {
+		t.Errorf("expected the card to expire one second after the pointer left, got %v", got)
+	}
+}
+
+// TestToastCard_DismissButton verifies the dismiss button removes its card at once and the
+// next queued toast takes its place.
+func TestToastCard_DismissButton(t *testing.T) {
+	// Arrange
+	notifier, _, renderer := mountHost()
+	for _, message := range []string{"one", "two", "three", "four"} {
+		notifier.Error(message, notify.Options{Duration: -1})
+	}
+	buttons := byClass(renderer.WaitForRender(t), "toast-dismiss")
+
+	// Act
+	rendertest.FireEvent(t, buttons[1], "click", nil)
+	root := renderer.WaitForRender(t)
+
+	// Assert
+	if got := strings.Join(cards(root), ","); got != "one,three,four" || more(root) != "" {
+		t.Errorf("expected cards one,three,four and no queue, got %q and %q", got, more(root))
+	}
+}
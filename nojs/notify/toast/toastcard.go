@@ -0,0 +1,26 @@
+package toast
+
+import (
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/notify"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// ToastCard is one visible toast. Hovering it pauses its auto-dismiss timer.
+type ToastCard struct {
+	runtime.ComponentBase
+	Toast    notify.Toast
+	Notifier *notify.Notifier
+}
+
+func (c *ToastCard) Pause(e events.MouseEventArgs) {
+	c.Notifier.Pause(c.Toast.ID)
+}
+
+func (c *ToastCard) Resume(e events.MouseEventArgs) {
+	c.Notifier.Resume(c.Toast.ID)
+}
+
+func (c *ToastCard) Dismiss() {
+	c.Notifier.Dismiss(c.Toast.ID)
+}
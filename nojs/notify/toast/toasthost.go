@@ -0,0 +1,45 @@
+// Package toast renders the toasts of a notify.Notifier. Mount a ToastHost once, as its own
+// app, and give it the application's Notifier through injection.
+package toast
+
+import (
+	"github.com/ForgeLogic/nojs/notify"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// ToastHost renders the toast queue of the injected Notifier. It watches the Notifier from
+// OnMount, so a toast raised anywhere re-renders the host alone.
+type ToastHost struct {
+	runtime.ComponentBase
+	Notifier *notify.Notifier `nojs:"inject"`
+
+	Toasts    []notify.Toast
+	Polite    string
+	Urgent    string
+	Queued    int
+	HasQueued bool
+
+	stopWatching func()
+}
+
+func (c *ToastHost) OnMount() {
+	c.refresh()
+	c.stopWatching = c.Notifier.Watch(func() {
+		c.refresh()
+		c.StateHasChanged()
+	})
+}
+
+func (c *ToastHost) OnUnmount() {
+	if c.stopWatching != nil {
+		c.stopWatching()
+	}
+}
+
+// refresh copies the queue into the fields the template binds.
+func (c *ToastHost) refresh() {
+	state := c.Notifier.State()
+	c.Toasts = state.Visible
+	c.Polite, c.Urgent = state.Polite, state.Urgent
+	c.Queued, c.HasQueued = state.Queued, state.Queued > 0
+}
@@ -0,0 +1,58 @@
+// Package examplelib is a component library laid out the way a third-party module would be:
+// it depends only on the public runtime, vdom and events packages, and its tests use
+// rendertest. It builds and tests with plain go test on any platform, and serves as the
+// acceptance test for that setup.
+package examplelib
+
+import (
+	"fmt"
+
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// LikeButton counts likes and links to the liked item. Its Render method is written the way
+// the AOT compiler generates one, so it is tested exactly like a template component.
+//
+// Props:
+//   - Label: The text shown before the count
+//   - Href: The page of the liked item, opened by the details link
+type LikeButton struct {
+	runtime.ComponentBase
+	Label string
+	Href  string
+
+	likes int
+}
+
+// Like handles a click on the button. A click with Shift held adds ten likes.
+func (c *LikeButton) Like(e events.ClickEventArgs) {
+	if e.ShiftKey {
+		c.likes += 10
+	} else {
+		c.likes++
+	}
+	c.StateHasChanged()
+}
+
+// OpenDetails handles a click on the details link.
+func (c *LikeButton) OpenDetails(e events.ClickEventArgs) {
+	e.PreventDefault()
+	if err := c.Navigate(c.Href); err != nil {
+		println("[LikeButton] Navigation error:", err.Error())
+	}
+}
+
+// Render builds the VDOM:
+//
+//	<div class="like">
+//	    <button @onclick="Like">{Label} ({likes})</button>
+//	    <a href="{Href}" @onclick="OpenDetails">Details</a>
+//	</div>
+func (c *LikeButton) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.Div(map[string]any{"class": "like"},
+		vdom.NewVNode("button", map[string]any{"onClick": events.AdaptClickEvent(c.Like)}, nil, fmt.Sprintf("%v (%v)", c.Label, c.likes)),
+		vdom.NewVNode("a", map[string]any{"href": c.Href, "onClick": events.AdaptClickEvent(c.OpenDetails)}, nil, "Details"),
+	)
+}
@@ -0,0 +1,62 @@
+//go:build !wasm
+// +build !wasm
+
+package examplelib
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/events"
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// TestLikeButton_RendersInitialState verifies the first render against a snapshot of the tree.
+func TestLikeButton_RendersInitialState(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&LikeButton{Label: "Like", Href: "/posts/7"})
+
+	// Act
+	root := renderer.RenderRoot()
+
+	// Assert
+	want := `<div class="like">
+  <button onClick> "Like (0)"
+  <a href="/posts/7" onClick> "Details"
+`
+	if got := rendertest.FormatVNode(root); got != want {
+		t.Errorf("unexpected tree:\n%s", got)
+	}
+}
+
+// TestLikeButton_ClickUpdatesCount verifies firing the click handler re-renders the count,
+// with the event arguments reaching the handler.
+func TestLikeButton_ClickUpdatesCount(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&LikeButton{Label: "Like"})
+	root := renderer.RenderRoot()
+
+	// Act
+	rendertest.FireEvent(t, root.Children[0], "click", nil)
+	rendertest.FireEvent(t, root.Children[0], "click", events.ClickEventArgs{ShiftKey: true})
+
+	// Assert
+	renderer.AssertRendered(t, rendertest.DefaultWaitTimeout, "Like (11)", func(root *vdom.VNode) string {
+		return root.Children[0].Content
+	})
+}
+
+// TestLikeButton_DetailsLinkNavigates verifies the link navigates through the renderer.
+func TestLikeButton_DetailsLinkNavigates(t *testing.T) {
+	// Arrange
+	renderer := rendertest.NewTestRenderer(&LikeButton{Label: "Like", Href: "/posts/7"})
+	root := renderer.RenderRoot()
+
+	// Act
+	rendertest.FireEvent(t, root.Children[1], "click", events.ClickEventArgs{})
+
+	// Assert
+	if got := renderer.Navigations(); len(got) != 1 || got[0] != "/posts/7" {
+		t.Errorf("expected one navigation to /posts/7, got %v", got)
+	}
+}
@@ -0,0 +1,409 @@
+// Package rendertest renders nojs components in memory, so components (generated or
+// hand-written) can be tested with go test on any platform, without a browser or the
+// js/wasm toolchain. A component library that depends only on runtime, vdom, events and
+// rendertest builds and tests natively.
+//
+//	renderer := rendertest.NewTestRenderer(&Counter{})
+//	root := renderer.RenderRoot()
+//	rendertest.FireEvent(t, root.Children[0], "click", events.ClickEventArgs{})
+//	root = renderer.WaitForRender(t)
+package rendertest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// DefaultWaitTimeout bounds WaitForRender.
+const DefaultWaitTimeout = time.Second
+
+// TestRenderer is a minimal test harness that implements runtime.Renderer
+// for in-memory testing without browser or WASM dependencies.
+//
+// It captures VDOM output from component renders and allows tests to:
+//   - Attach components to the renderer
+//   - Trigger re-renders via StateHasChanged() and run them with Flush; a child component
+//     re-renders alone, as in the browser (see ReRenderComponent)
+//   - Inspect the resulting VDOM tree
+//   - Fire event handlers and check the paths components navigated to
+type TestRenderer struct {
+	currentVDOM *vdom.VNode
+	component   runtime.Component
+	children    map[string]runtime.Component      // Child instances by RenderChild key
+	childVDOM   map[runtime.Component]*vdom.VNode // Tree last rendered by each child instance
+	navigations []string                          // Paths passed to Navigate, in order
+	renders     []*vdom.VNode                     // Every tree rendered by RenderRoot and ReRender, in order
+	scheduled   []func()                          // Flushes of renders batched by StateHasChanged, run by Flush
+	rendering   []runtime.Component               // Components whose Render is running, innermost last
+}
+
+// Compile-time assertion to ensure TestRenderer implements runtime.Renderer interface.
+var _ runtime.Renderer = (*TestRenderer)(nil)
+
+// TestRenderer decides when batched renders run, so tests stay deterministic.
+var _ runtime.RenderScheduler = (*TestRenderer)(nil)
+
+// TestRenderer re-renders a child component alone, like the WASM renderer.
+var _ runtime.ComponentRenderer = (*TestRenderer)(nil)
+
+// NewTestRenderer creates a test renderer attached to the given component.
+func NewTestRenderer(comp runtime.Component) *TestRenderer {
+	r := &TestRenderer{
+		component: comp,
+		children:  make(map[string]runtime.Component),
+		childVDOM: make(map[runtime.Component]*vdom.VNode),
+	}
+	comp.SetRenderer(r)
+	return r
+}
+
+// RenderRoot performs the initial render of the component.
+// This should be called at the start of a test to get the initial VDOM.
+func (r *TestRenderer) RenderRoot() *vdom.VNode {
+	r.currentVDOM = r.render(r.component)
+	r.renders = append(r.renders, r.currentVDOM)
+	return r.currentVDOM
+}
+
+// ReRender performs a re-render of the component.
+// This is called when Flush runs the renders batched by StateHasChanged(), and by
+// StateHasChangedSync().
+func (r *TestRenderer) ReRender() {
+	r.currentVDOM = r.render(r.component)
+	r.renders = append(r.renders, r.currentVDOM)
+}
+
+// ScheduleRender queues flush, the renders batched by StateHasChanged, until the next
+// Flush. The runtime calls it; tests do not.
+func (r *TestRenderer) ScheduleRender(flush func()) {
+	r.scheduled = append(r.scheduled, flush)
+}
+
+// Flush runs the renders batched by StateHasChanged since the last flush, as the browser
+// does on the next animation frame: components that called it several times render once.
+// Renders requested while flushing wait for the next Flush.
+func (r *TestRenderer) Flush() {
+	scheduled := r.scheduled
+	r.scheduled = nil
+	for _, flush := range scheduled {
+		flush()
+	}
+}
+
+// Renders returns every tree rendered so far, oldest first, after flushing batched renders.
+// Tests use it to check states that never last, such as the trees rendered in the middle of
+// a navigation, or to count renders.
+func (r *TestRenderer) Renders() []*vdom.VNode {
+	r.Flush()
+	return r.renders
+}
+
+// GetCurrentVDOM flushes batched renders and returns the most recently rendered VDOM tree.
+// Tests use this to inspect the component's output after renders.
+// After a state change, prefer WaitForRender, which does not assume renders are synchronous.
+func (r *TestRenderer) GetCurrentVDOM() *vdom.VNode {
+	r.Flush()
+	return r.currentVDOM
+}
+
+// RenderAndWait flushes pending renders. The test renderer renders synchronously, so it
+// flushes batched renders, re-renders the component and returns at once.
+func (r *TestRenderer) RenderAndWait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.Flush()
+	r.ReRender()
+	return nil
+}
+
+// WaitForRender flushes pending renders and returns the resulting VDOM tree, failing t
+// if the flush does not complete within DefaultWaitTimeout. Call it after a state change
+// instead of reading GetCurrentVDOM directly.
+func (r *TestRenderer) WaitForRender(t testing.TB) *vdom.VNode {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultWaitTimeout)
+	defer cancel()
+	if err := r.RenderAndWait(ctx); err != nil {
+		t.Fatalf("WaitForRender: %v", err)
+	}
+	return r.currentVDOM
+}
+
+// AssertRendered flushes renders until describe(root) equals want, failing t with a line
+// diff of the last description against want if they still differ after timeout.
+// describe typically formats the part of the tree under test with FormatVNode.
+func (r *TestRenderer) AssertRendered(t testing.TB, timeout time.Duration, want string, describe func(root *vdom.VNode) string) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		got := describe(r.WaitForRender(t))
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("rendered VDOM did not match within %v (- want, + got):\n%s", timeout, lineDiff(want, got))
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TextOf returns the text under n, concatenated in document order. For a text-only element
+// it is the element's text whichever way it was generated: as its Content or as a text
+// child.
+func TextOf(n *vdom.VNode) string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(n.Content)
+	for _, child := range n.Children {
+		b.WriteString(TextOf(child))
+	}
+	return b.String()
+}
+
+// FormatVNode renders n as indented text, one node per line with its sorted attributes and
+// content; event handlers are shown by name only. Nil children print as "<nil>".
+func FormatVNode(n *vdom.VNode) string {
+	var b strings.Builder
+	formatVNode(&b, n, 0)
+	return b.String()
+}
+
+func formatVNode(b *strings.Builder, n *vdom.VNode, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+	if n == nil {
+		b.WriteString("<nil>\n")
+		return
+	}
+	b.WriteString("<" + n.Tag)
+	keys := make([]string, 0, len(n.Attributes))
+	for key := range n.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.HasPrefix(key, "on") {
+			fmt.Fprintf(b, " %s", key)
+			continue
+		}
+		fmt.Fprintf(b, " %s=%q", key, fmt.Sprint(n.Attributes[key]))
+	}
+	b.WriteString(">")
+	if n.Content != "" {
+		fmt.Fprintf(b, " %q", n.Content)
+	}
+	b.WriteString("\n")
+	for _, child := range n.Children {
+		formatVNode(b, child, depth+1)
+	}
+}
+
+// lineDiff returns want and got line by line, marking lines that differ with - and +.
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			fmt.Fprintf(&b, "  %s\n", w)
+			continue
+		}
+		if i < len(wantLines) {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+		if i < len(gotLines) {
+			fmt.Fprintf(&b, "+ %s\n", g)
+		}
+	}
+	return b.String()
+}
+
+// RenderChild renders a child component, reusing the instance cached under key
+// the same way the WASM renderer does: a cached instance receives the new props
+// via ApplyProps and keeps its state, and fields tagged `nojs:"inject:key"` receive
+// the context values its ancestors provide. A runtime.Memoized instance whose props and
+// state did not change returns the tree it rendered last time without rendering.
+func (r *TestRenderer) RenderChild(key string, child runtime.Component) *vdom.VNode {
+	var parent runtime.Component
+	if len(r.rendering) > 0 {
+		parent = r.rendering[len(r.rendering)-1]
+	}
+	instance, exists := r.children[key]
+	reuse := false
+	if !exists {
+		instance = child
+		r.children[key] = instance
+		runtime.InjectContext(instance, parent)
+	} else {
+		runtime.InjectContext(instance, parent)
+		reuse = runtime.ReuseRender(instance, child)
+		if updater, ok := instance.(interface {
+			ApplyProps(source runtime.Component)
+		}); ok {
+			updater.ApplyProps(child)
+		}
+	}
+	instance.SetRenderer(r)
+	if cached := r.childVDOM[instance]; reuse && cached != nil {
+		return cached
+	}
+	vnode := r.render(instance)
+	r.childVDOM[instance] = vnode
+	return vnode
+}
+
+// render calls comp's Render with comp on the rendering stack, so the children it renders
+// see it as their parent.
+func (r *TestRenderer) render(comp runtime.Component) *vdom.VNode {
+	depth := len(r.rendering)
+	r.rendering = append(r.rendering, comp)
+	defer func() { r.rendering = r.rendering[:depth] }()
+	return runtime.RenderComponent(comp, r)
+}
+
+// ReRenderComponent re-renders only the child instance embedding b, as the WASM renderer
+// does: its parent and siblings do not render, and it keeps the props last applied. Its
+// new tree replaces the old one in place in the current tree, which Renders then lists
+// again; the earlier entry keeps a copy of the tree as it was. It reports false for the
+// root component or when the child's root tag changed, and the whole tree re-renders.
+func (r *TestRenderer) ReRenderComponent(b *runtime.ComponentBase) bool {
+	var instance runtime.Component
+	for _, child := range r.children {
+		if runtime.BaseOf(child) == b {
+			instance = child
+			break
+		}
+	}
+	old := r.childVDOM[instance]
+	if old == nil || r.currentVDOM == nil {
+		return false
+	}
+
+	previous := cloneVNode(r.currentVDOM)
+	runtime.InjectContext(instance, nil)
+	next := r.render(instance)
+	if !vdom.ReplaceNode(r.currentVDOM, old, next) {
+		return false
+	}
+	// old now shows next, so children rendered at the instance's root element point at old
+	for child, vnode := range r.childVDOM {
+		if vnode == next {
+			r.childVDOM[child] = old
+		}
+	}
+	if last := len(r.renders) - 1; last >= 0 && r.renders[last] == r.currentVDOM {
+		r.renders[last] = previous
+	}
+	r.renders = append(r.renders, r.currentVDOM)
+	return true
+}
+
+// cloneVNode deep-copies the nodes and attribute maps of the tree rooted at n.
+func cloneVNode(n *vdom.VNode) *vdom.VNode {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	if n.Attributes != nil {
+		c.Attributes = make(map[string]any, len(n.Attributes))
+		for k, v := range n.Attributes {
+			c.Attributes[k] = v
+		}
+	}
+	if n.Children != nil {
+		c.Children = make([]*vdom.VNode, len(n.Children))
+		for i, child := range n.Children {
+			c.Children[i] = cloneVNode(child)
+		}
+	}
+	return &c
+}
+
+// GetChild returns the child instance cached under key, or nil if none was rendered.
+func (r *TestRenderer) GetChild(key string) runtime.Component {
+	return r.children[key]
+}
+
+// Navigate records path and returns nil; nothing is routed. Tests check the recorded
+// paths with Navigations.
+func (r *TestRenderer) Navigate(path string) error {
+	r.navigations = append(r.navigations, path)
+	return nil
+}
+
+// Navigations returns the paths components navigated to, in call order.
+func (r *TestRenderer) Navigations() []string {
+	return r.navigations
+}
+
+// ReRenderSlot patches only the BodyContent slot of a layout.
+// For tests, this simply re-renders the slot parent component.
+func (r *TestRenderer) ReRenderSlot(slotParent runtime.Component) error {
+	// Re-render the slot parent component
+	runtime.RenderComponent(slotParent, r)
+	return nil
+}
+
+// FireEvent calls the handler bound to event ("click", "input", "keydown", ...) on node, as
+// the browser would when the event fires on the element. args is the handler's argument
+// (events.ClickEventArgs, events.ChangeEventArgs, ...); pass nil for handlers without one,
+// or to send the zero value. It fails t if node has no handler for event or args has the
+// wrong type. A handler that calls StateHasChanged re-renders on the next Flush, which
+// WaitForRender and GetCurrentVDOM run; read the result with WaitForRender.
+func FireEvent(t testing.TB, node *vdom.VNode, event string, args any) {
+	t.Helper()
+	if node == nil {
+		t.Fatalf("FireEvent(%q): node is nil", event)
+		return
+	}
+
+	// Templates bind handlers as "onClick", "onInput", ...; a no-arg click handler is
+	// moved to VNode.OnClick by vdom.NewVNode
+	key := "on" + strings.ToUpper(event[:1]) + event[1:]
+	handler, ok := node.Attributes[key]
+	if !ok && event == "click" && node.OnClick != nil {
+		handler, ok = node.OnClick, true
+	}
+	if !ok {
+		t.Fatalf("FireEvent(%q): <%s> has no %s handler", event, node.Tag, key)
+		return
+	}
+
+	fn := reflect.ValueOf(handler)
+	if fn.Kind() != reflect.Func || fn.Type().NumIn() > 1 {
+		t.Fatalf("FireEvent(%q): %s on <%s> is a %T, not an event handler", event, key, node.Tag, handler)
+		return
+	}
+	var in []reflect.Value
+	if fn.Type().NumIn() == 1 {
+		argType := fn.Type().In(0)
+		arg := reflect.Zero(argType)
+		if args != nil {
+			if !reflect.TypeOf(args).AssignableTo(argType) {
+				t.Fatalf("FireEvent(%q): the handler on <%s> takes %s, got %T", event, node.Tag, argType, args)
+				return
+			}
+			arg = reflect.ValueOf(args)
+		}
+		in = []reflect.Value{arg}
+	}
+	fn.Call(in)
+}
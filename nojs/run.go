@@ -0,0 +1,125 @@
+//go:build js || wasm
+// +build js wasm
+
+// Package nojs starts nojs applications. Run mounts one application instance; call it
+// once per app, including when several independently built apps share a page.
+package nojs
+
+import (
+	"fmt"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// AppInstance is a mounted application returned by Run.
+type AppInstance = runtime.AppInstance
+
+// Options configures an application instance started by Run.
+type Options struct {
+	// Name identifies the instance. It prefixes log output, names the instance in
+	// window.nojsApps and must be unique on the page (e.g., "header", "content").
+	Name string
+
+	// Mount is the CSS selector of the element the app renders into (e.g., "#app").
+	Mount string
+
+	// Root is the root component (typically a router.AppShell).
+	Root runtime.Component
+
+	// Navigation is an optional router. If it has a SetRenderer method, the instance's
+	// renderer is injected; if it has an AttachApp method, it is attached to the instance
+	// so it can log with the instance prefix and coordinate with the other apps.
+	Navigation runtime.NavigationManager
+
+	// OnRouteChange receives the component chain on every navigation (required with Navigation).
+	OnRouteChange func(chain []runtime.Component, key string)
+
+	// ProgressiveMount, when set, splits the first render into chunks mounted over several
+	// animation frames so very large pages do not block the main thread (see
+	// runtime.ProgressiveMountOptions).
+	ProgressiveMount *runtime.ProgressiveMountOptions
+
+	// Provide lists values injected into component fields tagged `nojs:"inject"`, registered
+	// before the first render. Several apps on a page can be given the same value (e.g., one
+	// *notify.Notifier raised from the content app and rendered by a toast app).
+	Provide []any
+
+	// RenderRate, when set, replaces the render-rate tracking options (see
+	// runtime.RenderRateOptions). Tracking is shared by every app on the page; it is off in
+	// production builds and warns above runtime.DefaultSoftRenderLimit in dev builds.
+	RenderRate *runtime.RenderRateOptions
+}
+
+// Run creates the renderer for opts, registers the app instance, renders the root
+// component and starts the router. When the mount element holds markup, a page pre-rendered
+// at build time, the first render adopts it instead of replacing it (see
+// runtime.RendererImpl.Hydrate); with a router, that first render is the one of the
+// initial route. Unmount the returned instance to remove the app and
+// all of its window listeners from the page.
+//
+// Example:
+//
+//	engine := router.NewEngine(nil)
+//	engine.SetMode(router.ModePassive) // another app on the page owns history
+//	shell := router.NewAppShell(layout)
+//	app, err := nojs.Run(nojs.Options{
+//	    Name:          "content",
+//	    Mount:         "#app-content",
+//	    Root:          shell,
+//	    Navigation:    engine,
+//	    OnRouteChange: shell.SetPage,
+//	})
+func Run(opts Options) (*AppInstance, error) {
+	if opts.Root == nil {
+		return nil, fmt.Errorf("nojs: app %q has no root component", opts.Name)
+	}
+	if opts.Navigation != nil && opts.OnRouteChange == nil {
+		return nil, fmt.Errorf("nojs: app %q has a router but no OnRouteChange callback", opts.Name)
+	}
+
+	renderer := runtime.NewRenderer(opts.Navigation, opts.Mount)
+	if opts.ProgressiveMount != nil {
+		renderer.SetProgressiveMount(*opts.ProgressiveMount)
+	}
+	if opts.RenderRate != nil {
+		runtime.SetRenderRate(*opts.RenderRate)
+	}
+	for _, value := range opts.Provide {
+		renderer.Services().Provide(value)
+	}
+	app, err := runtime.NewAppInstance(opts.Name, opts.Mount, renderer)
+	if err != nil {
+		return nil, fmt.Errorf("nojs: %w", err)
+	}
+
+	if nav, ok := opts.Navigation.(interface{ SetRenderer(runtime.Renderer) }); ok {
+		nav.SetRenderer(renderer)
+	}
+	if nav, ok := opts.Navigation.(interface {
+		AttachApp(app *runtime.AppInstance) error
+	}); ok {
+		if err := nav.AttachApp(app); err != nil {
+			app.Unmount()
+			return nil, fmt.Errorf("nojs: %w", err)
+		}
+	}
+
+	renderer.SetCurrentComponent(opts.Root, opts.Name)
+	if renderer.Hydrate() && opts.Navigation != nil {
+		// The mount element holds a pre-rendered page: the router's first navigation renders
+		// the root, adopting it, rather than a render without the page replacing it first
+		opts.Root.SetRenderer(renderer)
+	} else {
+		renderer.ReRender()
+	}
+
+	if opts.Navigation != nil {
+		if err := opts.Navigation.Start(opts.OnRouteChange); err != nil {
+			app.Unmount()
+			return nil, fmt.Errorf("nojs: app %q failed to start router: %w", opts.Name, err)
+		}
+	}
+
+	app.Logger().Log("Mounted on", opts.Mount)
+	return app, nil
+}
@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+// renderedComponent records a component rendered in the current pass.
+type renderedComponent struct {
+	component Component
+	key       string
+	first     bool // The pass mounted the instance
+}
+
+// maxAfterRenderPasses bounds the passes one afterRenderQueue.run call runs: hooks that
+// re-render every time would otherwise never let it return.
+const maxAfterRenderPasses = 10
+
+// afterRenderQueue runs the OnAfterRender hooks of a renderer's passes one after another.
+// A render triggered by a hook does not run its hooks from inside the hook, nesting
+// deeper with every render: its pass is queued and run by the outer call once the current
+// pass is done. The zero value is ready to use.
+type afterRenderQueue struct {
+	mu      sync.Mutex // Separate from the renderer's mutex, which hooks must not hold
+	passes  [][]renderedComponent
+	running bool
+}
+
+// run calls hook for each component of pass, unless a run call is already in progress,
+// which then runs pass after its own. After maxAfterRenderPasses passes the remaining ones
+// are dropped with a warning.
+func (q *afterRenderQueue) run(pass []renderedComponent, hook func(renderedComponent)) {
+	q.mu.Lock()
+	q.passes = append(q.passes, pass)
+	if q.running {
+		q.mu.Unlock()
+		return
+	}
+	q.running = true
+	q.mu.Unlock()
+
+	for passes := 0; ; passes++ {
+		q.mu.Lock()
+		if len(q.passes) == 0 || passes == maxAfterRenderPasses {
+			dropped := len(q.passes)
+			q.passes = nil
+			q.running = false
+			q.mu.Unlock()
+			if dropped > 0 {
+				console.Warn(fmt.Sprintf("[nojs] OnAfterRender hooks re-rendered %d times in a row; skipping the hooks of the last %d renders. Guard StateHasChanged calls in OnAfterRender to avoid a render loop.", maxAfterRenderPasses, dropped))
+			}
+			return
+		}
+		current := q.passes[0]
+		q.passes = q.passes[1:]
+		q.mu.Unlock()
+
+		for _, rc := range current {
+			hook(rc)
+		}
+	}
+}
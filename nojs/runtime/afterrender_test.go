@@ -0,0 +1,61 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestAfterRenderQueue_RunsNestedPassAfterCurrent verifies a pass queued from inside a hook,
+// as a render triggered by OnAfterRender queues one, runs after the current pass instead of
+// inside the hook.
+func TestAfterRenderQueue_RunsNestedPassAfterCurrent(t *testing.T) {
+	// Arrange
+	var q afterRenderQueue
+	var calls []string
+	var hook func(renderedComponent)
+	hook = func(rc renderedComponent) {
+		calls = append(calls, rc.key)
+		if rc.key == "child" && rc.first {
+			q.run([]renderedComponent{{key: "rerendered"}}, hook)
+			calls = append(calls, "child returned")
+		}
+	}
+
+	// Act
+	q.run([]renderedComponent{{key: "child", first: true}, {key: "parent", first: true}}, hook)
+
+	// Assert
+	want := []string{"child", "child returned", "parent", "rerendered"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+}
+
+// TestAfterRenderQueue_StopsRenderLoop verifies hooks that re-render every time are stopped
+// after maxAfterRenderPasses passes, and the queue runs later passes again.
+func TestAfterRenderQueue_StopsRenderLoop(t *testing.T) {
+	// Arrange
+	var q afterRenderQueue
+	passes := 0
+	var hook func(renderedComponent)
+	hook = func(rc renderedComponent) {
+		passes++
+		q.run([]renderedComponent{rc}, hook) // StateHasChanged on every render
+	}
+
+	// Act
+	q.run([]renderedComponent{{key: "looping"}}, hook)
+	later := 0
+	q.run([]renderedComponent{{key: "later"}}, func(renderedComponent) { later++ })
+
+	// Assert
+	if passes != maxAfterRenderPasses {
+		t.Errorf("expected %d passes, got %d", maxAfterRenderPasses, passes)
+	}
+	if later != 1 {
+		t.Errorf("expected the next render's hooks to run, got %d calls", later)
+	}
+}
@@ -0,0 +1,205 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+// Window events nojs apps on the same page use to coordinate navigation.
+// Each is a CustomEvent dispatched on window whose detail is {source, path}: source is
+// the name of the dispatching app and path is a browser path (including any base path).
+const (
+	// PathChangedEvent is dispatched by the app owning browser history after every navigation.
+	PathChangedEvent = "nojs:pathchanged"
+	// NavigationRequestEvent is dispatched by other apps to ask the history owner to navigate.
+	NavigationRequestEvent = "nojs:navigate"
+)
+
+// AppInstance is one independently mounted nojs application. Several instances (for
+// example separately built micro-frontends on #app-header and #app-content) can share a
+// page: each has its own renderer and mount element, logs with its name as prefix, and
+// is registered for inspection under its name (window.nojsApps in the browser).
+// At most one instance owns browser history; the others follow its path broadcasts.
+type AppInstance struct {
+	name     string
+	mount    string
+	renderer Renderer
+	log      console.Logger
+
+	mu        sync.Mutex
+	cleanups  []func() // Run in reverse order by Unmount
+	unmounted bool
+}
+
+var (
+	appsMu       sync.Mutex
+	apps         = map[string]*AppInstance{} // Mounted instances by name
+	historyOwner *AppInstance                // Instance that claimed browser history, if any
+)
+
+// NewAppInstance registers a new app instance rendering into mount with renderer.
+// Names and mount selectors must be unique among mounted instances.
+// Most applications create instances through nojs.Run instead of calling this directly.
+func NewAppInstance(name, mount string, renderer Renderer) (*AppInstance, error) {
+	if name == "" {
+		return nil, fmt.Errorf("app instance name must not be empty")
+	}
+	if mount == "" {
+		return nil, fmt.Errorf("app instance %q: mount selector must not be empty", name)
+	}
+
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	if _, exists := apps[name]; exists {
+		return nil, fmt.Errorf("app instance %q is already mounted", name)
+	}
+	for _, other := range apps {
+		if other.mount == mount {
+			return nil, fmt.Errorf("app instance %q: mount %q is already used by app instance %q", name, mount, other.name)
+		}
+	}
+
+	a := &AppInstance{
+		name:     name,
+		mount:    mount,
+		renderer: renderer,
+		log:      console.NewLogger(name),
+	}
+	apps[name] = a
+	registerAppInspector(a)
+	return a, nil
+}
+
+// LookupApp returns the mounted app instance with the given name.
+func LookupApp(name string) (*AppInstance, bool) {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	a, ok := apps[name]
+	return a, ok
+}
+
+// Apps returns all mounted app instances, sorted by name.
+func Apps() []*AppInstance {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	list := make([]*AppInstance, 0, len(apps))
+	for _, a := range apps {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list
+}
+
+// Name returns the instance name.
+func (a *AppInstance) Name() string {
+	return a.name
+}
+
+// Mount returns the CSS selector of the instance's mount element.
+func (a *AppInstance) Mount() string {
+	return a.mount
+}
+
+// Renderer returns the renderer of the instance.
+func (a *AppInstance) Renderer() Renderer {
+	return a.renderer
+}
+
+// Logger returns a console logger prefixed with the instance name.
+func (a *AppInstance) Logger() console.Logger {
+	return a.log
+}
+
+// OnUnmount registers fn to run when the instance is unmounted. Cleanups run in reverse
+// registration order, before the renderer tears down the component tree. Routers use it
+// to remove their window listeners.
+func (a *AppInstance) OnUnmount(fn func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.unmounted {
+		fn()
+		return
+	}
+	a.cleanups = append(a.cleanups, fn)
+}
+
+// ClaimHistory makes a the only instance allowed to drive browser history (pushState and
+// popstate). It fails if another mounted instance already claimed it. The claim is
+// released when a is unmounted.
+func (a *AppInstance) ClaimHistory() error {
+	appsMu.Lock()
+	defer appsMu.Unlock()
+	if historyOwner != nil && historyOwner != a {
+		return fmt.Errorf("app instance %q: browser history is already owned by app instance %q", a.name, historyOwner.name)
+	}
+	historyOwner = a
+	return nil
+}
+
+// BroadcastPath announces that the browser path changed to path. It is called by the
+// history owner after every navigation.
+func (a *AppInstance) BroadcastPath(path string) {
+	dispatchAppEvent(PathChangedEvent, a.name, path)
+}
+
+// OnPathBroadcast calls fn whenever another instance broadcasts a path change.
+// The listener is removed automatically when a is unmounted.
+func (a *AppInstance) OnPathBroadcast(fn func(source, path string)) {
+	a.listen(PathChangedEvent, fn)
+}
+
+// RequestNavigation asks the history owner to navigate to path.
+func (a *AppInstance) RequestNavigation(path string) {
+	dispatchAppEvent(NavigationRequestEvent, a.name, path)
+}
+
+// OnNavigationRequest calls fn whenever another instance requests a navigation.
+// The listener is removed automatically when a is unmounted.
+func (a *AppInstance) OnNavigationRequest(fn func(source, path string)) {
+	a.listen(NavigationRequestEvent, fn)
+}
+
+// listen subscribes fn to event, ignoring events the instance dispatched itself.
+func (a *AppInstance) listen(event string, fn func(source, path string)) {
+	remove := listenAppEvent(event, func(source, path string) {
+		if source != a.name {
+			fn(source, path)
+		}
+	})
+	a.OnUnmount(remove)
+}
+
+// Unmount runs the registered cleanups, unmounts the component tree (when the renderer
+// supports it), releases the history claim and unregisters the instance.
+// Calling Unmount more than once is a no-op.
+func (a *AppInstance) Unmount() {
+	a.mu.Lock()
+	if a.unmounted {
+		a.mu.Unlock()
+		return
+	}
+	a.unmounted = true
+	cleanups := a.cleanups
+	a.cleanups = nil
+	a.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+	if unmounter, ok := a.renderer.(interface{ Unmount() }); ok {
+		unmounter.Unmount()
+	}
+
+	appsMu.Lock()
+	if historyOwner == a {
+		historyOwner = nil
+	}
+	if apps[a.name] == a {
+		delete(apps, a.name)
+	}
+	appsMu.Unlock()
+	unregisterAppInspector(a)
+}
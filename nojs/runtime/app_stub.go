@@ -0,0 +1,68 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import "sync"
+
+// In non-WASM builds there is no window; app events are delivered in-process, which
+// lets tests run several app instances side by side.
+var (
+	appEventMu        sync.Mutex
+	appEventListeners = map[string][]*appEventListener{}
+)
+
+type appEventListener struct {
+	fn func(source, path string)
+}
+
+// dispatchAppEvent synchronously calls every listener of event, like window.dispatchEvent.
+func dispatchAppEvent(event, source, path string) {
+	appEventMu.Lock()
+	listeners := append([]*appEventListener{}, appEventListeners[event]...)
+	appEventMu.Unlock()
+
+	for _, l := range listeners {
+		l.fn(source, path)
+	}
+}
+
+// listenAppEvent adds fn as a listener of event and returns a function removing it.
+func listenAppEvent(event string, fn func(source, path string)) func() {
+	l := &appEventListener{fn: fn}
+	appEventMu.Lock()
+	appEventListeners[event] = append(appEventListeners[event], l)
+	appEventMu.Unlock()
+
+	return func() {
+		appEventMu.Lock()
+		defer appEventMu.Unlock()
+		listeners := appEventListeners[event]
+		for i, other := range listeners {
+			if other == l {
+				appEventListeners[event] = append(listeners[:i], listeners[i+1:]...)
+				break
+			}
+		}
+		if len(appEventListeners[event]) == 0 {
+			delete(appEventListeners, event)
+		}
+	}
+}
+
+// appEventListenerCount returns the number of registered app event listeners (for tests).
+func appEventListenerCount() int {
+	appEventMu.Lock()
+	defer appEventMu.Unlock()
+	count := 0
+	for _, listeners := range appEventListeners {
+		count += len(listeners)
+	}
+	return count
+}
+
+// registerAppInspector is a no-op in non-WASM builds; there is no window to expose apps on.
+func registerAppInspector(a *AppInstance) {}
+
+// unregisterAppInspector is a no-op in non-WASM builds.
+func unregisterAppInspector(a *AppInstance) {}
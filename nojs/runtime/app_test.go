@@ -0,0 +1,145 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// appTestRenderer records re-renders and unmounts of an app instance.
+type appTestRenderer struct {
+	renders  int
+	unmounts int
+}
+
+func (r *appTestRenderer) RenderChild(key string, child Component) *vdom.VNode { return nil }
+func (r *appTestRenderer) ReRender()                                           { r.renders++ }
+func (r *appTestRenderer) ReRenderSlot(slotParent Component) error             { return nil }
+func (r *appTestRenderer) Navigate(path string) error                          { return nil }
+func (r *appTestRenderer) RenderAndWait(ctx context.Context) error             { r.renders++; return nil }
+func (r *appTestRenderer) Unmount()                                            { r.unmounts++ }
+
+// newTestApp registers an app instance that is unmounted when the test ends.
+func newTestApp(t *testing.T, name, mount string) (*AppInstance, *appTestRenderer) {
+	t.Helper()
+	renderer := &appTestRenderer{}
+	app, err := NewAppInstance(name, mount, renderer)
+	if err != nil {
+		t.Fatalf("NewAppInstance(%q): %v", name, err)
+	}
+	t.Cleanup(app.Unmount)
+	return app, renderer
+}
+
+// TestAppInstance_DuplicateNameOrMount_Rejected verifies that two apps cannot share a name or mount.
+func TestAppInstance_DuplicateNameOrMount_Rejected(t *testing.T) {
+	newTestApp(t, "header", "#app-header")
+
+	if _, err := NewAppInstance("header", "#other", &appTestRenderer{}); err == nil {
+		t.Error("expected an error for a duplicate app name")
+	}
+	if _, err := NewAppInstance("content", "#app-header", &appTestRenderer{}); err == nil {
+		t.Error("expected an error for a duplicate mount selector")
+	}
+	if a, ok := LookupApp("header"); !ok || a.Mount() != "#app-header" {
+		t.Error("expected the first app to stay registered")
+	}
+}
+
+// TestAppInstance_PrimaryNavigates_PassiveFollows verifies the window event protocol between
+// the app owning history and an app following it: path broadcasts reach the passive app and
+// its navigation requests reach the primary, and no app receives its own events.
+func TestAppInstance_PrimaryNavigates_PassiveFollows(t *testing.T) {
+	header, _ := newTestApp(t, "header", "#app-header")
+	content, contentRenderer := newTestApp(t, "content", "#app-content")
+
+	if err := header.ClaimHistory(); err != nil {
+		t.Fatalf("ClaimHistory: %v", err)
+	}
+	if err := content.ClaimHistory(); err == nil {
+		t.Error("expected a second history claim to fail")
+	}
+
+	// Passive app: follow broadcasts and update its own view
+	var contentPath string
+	content.OnPathBroadcast(func(source, path string) {
+		if source != "header" {
+			t.Errorf("expected broadcast from header, got %q", source)
+		}
+		contentPath = path
+		contentRenderer.ReRender()
+	})
+	// Primary app: must not observe its own broadcast, but answers navigation requests
+	header.OnPathBroadcast(func(source, path string) {
+		t.Errorf("primary received its own path broadcast %q", path)
+	})
+	var requested string
+	header.OnNavigationRequest(func(source, path string) {
+		requested = path
+		header.BroadcastPath(path)
+	})
+
+	// Primary navigates
+	header.BroadcastPath("/users/42")
+	if contentPath != "/users/42" || contentRenderer.renders != 1 {
+		t.Fatalf("expected passive app to render /users/42 once, got path %q after %d renders", contentPath, contentRenderer.renders)
+	}
+
+	// Passive app asks the primary to navigate; the primary's broadcast updates the passive view
+	content.RequestNavigation("/about")
+	if requested != "/about" {
+		t.Errorf("expected primary to receive the navigation request, got %q", requested)
+	}
+	if contentPath != "/about" || contentRenderer.renders != 2 {
+		t.Errorf("expected passive app to follow to /about, got path %q after %d renders", contentPath, contentRenderer.renders)
+	}
+}
+
+// TestAppInstance_Unmount_RemovesListeners verifies that unmounting releases every listener,
+// the renderer and the history claim, and that unmounting twice is a no-op.
+func TestAppInstance_Unmount_RemovesListeners(t *testing.T) {
+	header, headerRenderer := newTestApp(t, "header", "#app-header")
+	content, contentRenderer := newTestApp(t, "content", "#app-content")
+	header.ClaimHistory()
+	header.OnNavigationRequest(func(source, path string) {})
+	content.OnPathBroadcast(func(source, path string) {})
+	cleanedUp := false
+	content.OnUnmount(func() { cleanedUp = true })
+
+	if got := appEventListenerCount(); got != 2 {
+		t.Fatalf("expected 2 listeners, got %d", got)
+	}
+
+	content.Unmount()
+	content.Unmount()
+	if got := appEventListenerCount(); got != 1 {
+		t.Errorf("expected only the primary's listener after unmounting the passive app, got %d", got)
+	}
+	if !cleanedUp || contentRenderer.unmounts != 1 {
+		t.Errorf("expected cleanup and one renderer unmount, got cleanup=%v unmounts=%d", cleanedUp, contentRenderer.unmounts)
+	}
+	if _, ok := LookupApp("content"); ok {
+		t.Error("expected unmounted app to be unregistered")
+	}
+
+	header.Unmount()
+	if got := appEventListenerCount(); got != 0 {
+		t.Errorf("expected no listeners after unmounting both apps, got %d", got)
+	}
+	if headerRenderer.unmounts != 1 {
+		t.Errorf("expected primary renderer to be unmounted once, got %d", headerRenderer.unmounts)
+	}
+	if len(Apps()) != 0 {
+		t.Errorf("expected no mounted apps, got %d", len(Apps()))
+	}
+
+	// The history claim is released with its owner
+	other, _ := newTestApp(t, "other", "#app-other")
+	if err := other.ClaimHistory(); err != nil {
+		t.Errorf("expected history to be claimable after the owner unmounted: %v", err)
+	}
+}
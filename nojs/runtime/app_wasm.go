@@ -0,0 +1,107 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+import (
+	"sync"
+	"syscall/js"
+)
+
+// dispatchAppEvent dispatches a CustomEvent named event on window with detail {source, path}.
+func dispatchAppEvent(event, source, path string) {
+	detail := js.Global().Get("Object").New()
+	detail.Set("source", source)
+	detail.Set("path", path)
+	init := js.Global().Get("Object").New()
+	init.Set("detail", detail)
+	js.Global().Call("dispatchEvent", js.Global().Get("CustomEvent").New(event, init))
+}
+
+// listenAppEvent adds a window listener for event and returns a function that removes it
+// and releases the underlying js.Func.
+func listenAppEvent(event string, fn func(source, path string)) func() {
+	listener := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) == 0 {
+			return nil
+		}
+		detail := args[0].Get("detail")
+		if !detail.Truthy() {
+			return nil
+		}
+		fn(detail.Get("source").String(), detail.Get("path").String())
+		return nil
+	})
+	js.Global().Call("addEventListener", event, listener)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			js.Global().Call("removeEventListener", event, listener)
+			listener.Release()
+		})
+	}
+}
+
+var (
+	inspectorMu    sync.Mutex
+	inspectorFuncs = map[*AppInstance][]js.Func{} // Callbacks of window.nojsApps entries
+)
+
+// registerAppInspector exposes a under window.nojsApps[name] as {name, mount, unmount(),
+// renderStats()}, so the host page and browser devtools can tell the apps on a page apart.
+// renderStats returns the render-rate records of the app's components (see RenderStats).
+func registerAppInspector(a *AppInstance) {
+	registry := js.Global().Get("nojsApps")
+	if !registry.Truthy() {
+		registry = js.Global().Get("Object").New()
+		js.Global().Set("nojsApps", registry)
+	}
+
+	unmount := js.FuncOf(func(this js.Value, args []js.Value) any {
+		// Unmount releases this js.Func, so it must not run on the callback's own stack
+		go a.Unmount()
+		return nil
+	})
+	renderStats := js.FuncOf(func(this js.Value, args []js.Value) any {
+		stats := renderRates.stats(a.renderer)
+		list := make([]any, len(stats))
+		for i, s := range stats {
+			list[i] = map[string]any{
+				"component":   s.Component,
+				"key":         s.Key,
+				"rate":        s.Rate,
+				"peak":        s.Peak,
+				"total":       s.Total,
+				"throttled":   s.Throttled,
+				"lastTrigger": s.LastTrigger,
+			}
+		}
+		return list
+	})
+	inspectorMu.Lock()
+	inspectorFuncs[a] = []js.Func{unmount, renderStats}
+	inspectorMu.Unlock()
+
+	entry := js.Global().Get("Object").New()
+	entry.Set("name", a.name)
+	entry.Set("mount", a.mount)
+	entry.Set("unmount", unmount)
+	entry.Set("renderStats", renderStats)
+	registry.Set(a.name, entry)
+}
+
+// unregisterAppInspector removes a from window.nojsApps.
+func unregisterAppInspector(a *AppInstance) {
+	if registry := js.Global().Get("nojsApps"); registry.Truthy() {
+		registry.Delete(a.name)
+	}
+
+	inspectorMu.Lock()
+	funcs := inspectorFuncs[a]
+	delete(inspectorFuncs, a)
+	inspectorMu.Unlock()
+	for _, fn := range funcs {
+		fn.Release()
+	}
+}
@@ -0,0 +1,80 @@
+package runtime
+
+import "sync"
+
+// RenderScheduler is implemented by renderers that decide when the renders batched by
+// StateHasChanged run. Other renderers run them on the next animation frame (synchronously
+// in non-WASM builds). rendertest.TestRenderer implements it to run them on Flush.
+type RenderScheduler interface {
+	// ScheduleRender arranges for flush to be called once, after the caller returns.
+	// flush renders every component of the renderer that called StateHasChanged since.
+	ScheduleRender(flush func())
+}
+
+// renderBatcher coalesces the StateHasChanged calls made before the next frame into one
+// render per renderer, and per layout for components in a layout slot.
+type renderBatcher struct {
+	mu       sync.Mutex
+	schedule func(cb func()) (cancel func()) // requestFrame, or a fake in tests
+	pending  map[Renderer][]*ComponentBase   // Components awaiting the scheduled flush, per renderer
+}
+
+// batcher is the batcher behind StateHasChanged.
+var batcher = newRenderBatcher(nil)
+
+// newRenderBatcher creates a batcher that runs its flushes through schedule (requestFrame
+// when nil) for renderers that are not a RenderScheduler.
+func newRenderBatcher(schedule func(cb func()) func()) *renderBatcher {
+	if schedule == nil {
+		schedule = requestFrame
+	}
+	return &renderBatcher{schedule: schedule, pending: make(map[Renderer][]*ComponentBase)}
+}
+
+// isPending reports whether b already awaits a flush, which covers any further call.
+func (q *renderBatcher) isPending(b *ComponentBase) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, p := range q.pending[b.renderer] {
+		if p == b {
+			return true
+		}
+	}
+	return false
+}
+
+// request adds b to the next flush of its renderer, scheduling one unless it is pending.
+func (q *renderBatcher) request(b *ComponentBase) {
+	r := b.renderer
+	q.mu.Lock()
+	for _, p := range q.pending[r] {
+		if p == b {
+			q.mu.Unlock()
+			return
+		}
+	}
+	first := len(q.pending[r]) == 0
+	q.pending[r] = append(q.pending[r], b)
+	q.mu.Unlock()
+
+	if !first {
+		return
+	}
+	flush := func() { q.flush(r) }
+	if scheduler, ok := r.(RenderScheduler); ok {
+		scheduler.ScheduleRender(flush)
+		return
+	}
+	q.schedule(flush)
+}
+
+// flush renders the components of r requested so far. Requests made during the render
+// schedule a new flush, so they observe a render that started after they asked.
+func (q *renderBatcher) flush(r Renderer) {
+	q.mu.Lock()
+	bases := q.pending[r]
+	delete(q.pending, r)
+	q.mu.Unlock()
+
+	renderDeferred(bases)
+}
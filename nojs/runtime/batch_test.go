@@ -0,0 +1,162 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import "testing"
+
+// schedulingTestRenderer records re-renders and holds batched flushes until run.
+type schedulingTestRenderer struct {
+	appTestRenderer
+	flushes []func()
+}
+
+func (r *schedulingTestRenderer) ScheduleRender(flush func()) { r.flushes = append(r.flushes, flush) }
+
+// run calls the scheduled flushes.
+func (r *schedulingTestRenderer) run() {
+	flushes := r.flushes
+	r.flushes = nil
+	for _, flush := range flushes {
+		flush()
+	}
+}
+
+// TestRenderBatcher_CoalescesRequestsIntoOneRender verifies that requests made before the
+// frame, from one or several components of a renderer, share one render.
+func TestRenderBatcher_CoalescesRequestsIntoOneRender(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	q := newRenderBatcher(frames.schedule)
+	renderer := &appTestRenderer{}
+	first, second := &ComponentBase{}, &ComponentBase{}
+	first.SetRenderer(renderer)
+	second.SetRenderer(renderer)
+
+	// Act
+	q.request(first)
+	q.request(first)
+	q.request(second)
+	pendingBeforeFrame := q.isPending(first)
+	renderedBeforeFrame := renderer.renders
+	frames.next()
+
+	// Assert
+	if !pendingBeforeFrame || renderedBeforeFrame != 0 {
+		t.Errorf("expected the render to wait for the frame, got %d renders", renderedBeforeFrame)
+	}
+	if renderer.renders != 1 {
+		t.Errorf("expected 1 render for 3 requests, got %d", renderer.renders)
+	}
+	if q.isPending(first) {
+		t.Error("expected nothing pending after the frame")
+	}
+	if frames.next() {
+		t.Error("expected no further frame to be scheduled")
+	}
+}
+
+// TestRenderBatcher_RequestDuringFlushSchedulesNextFrame verifies that a request made while
+// a flush renders waits for a frame of its own.
+func TestRenderBatcher_RequestDuringFlushSchedulesNextFrame(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	q := newRenderBatcher(frames.schedule)
+	b := &ComponentBase{}
+	renderer := &rerenderingTestRenderer{onRender: func() { q.request(b) }}
+	b.SetRenderer(renderer)
+
+	// Act
+	q.request(b)
+	frames.next()
+	rendersAfterFirstFrame := renderer.renders
+	frames.next()
+
+	// Assert
+	if rendersAfterFirstFrame != 1 {
+		t.Errorf("expected 1 render in the first frame, got %d", rendersAfterFirstFrame)
+	}
+	if renderer.renders != 2 {
+		t.Errorf("expected the request made while rendering to render in the next frame, got %d renders", renderer.renders)
+	}
+}
+
+// TestStateHasChanged_RenderSchedulerRunsBatch verifies that StateHasChanged leaves the
+// timing of the batched render to a renderer implementing RenderScheduler, and that
+// StateHasChangedSync renders at once.
+func TestStateHasChanged_RenderSchedulerRunsBatch(t *testing.T) {
+	// Arrange
+	renderer := &schedulingTestRenderer{}
+	b := &ComponentBase{}
+	b.SetRenderer(renderer)
+
+	// Act
+	b.StateHasChanged()
+	b.StateHasChanged()
+	rendersBeforeRun := renderer.renders
+	renderer.run()
+	rendersAfterRun := renderer.renders
+	b.StateHasChangedSync()
+
+	// Assert
+	if rendersBeforeRun != 0 {
+		t.Errorf("expected no render before the scheduled flush, got %d", rendersBeforeRun)
+	}
+	if rendersAfterRun != 1 {
+		t.Errorf("expected 1 render for 2 calls, got %d", rendersAfterRun)
+	}
+	if renderer.renders != 2 {
+		t.Errorf("expected StateHasChangedSync to render at once, got %d renders", renderer.renders)
+	}
+}
+
+// rerenderingTestRenderer calls onRender on each re-render.
+type rerenderingTestRenderer struct {
+	appTestRenderer
+	onRender func()
+}
+
+func (r *rerenderingTestRenderer) ReRender() {
+	r.renders++
+	r.onRender()
+}
+
+// TestAfterStateChange_RunsOncePerCommittedBatch verifies the hooks run once for a batch of
+// StateHasChanged calls, before its render, and no more once stopped or destroyed.
+func TestAfterStateChange_RunsOncePerCommittedBatch(t *testing.T) {
+	// Arrange
+	renderer := &schedulingTestRenderer{}
+	c := &rateTestComponent{}
+	c.SetRenderer(renderer)
+	var commits, rendersAtCommit int
+	AfterStateChange(c, func() {
+		commits++
+		rendersAtCommit = renderer.renders
+	})
+	stopped := 0
+	stop := AfterStateChange(c, func() { stopped++ })
+
+	// Act
+	c.StateHasChanged()
+	c.StateHasChanged()
+	committedBeforeFlush := commits
+	renderer.run()
+	stop()
+	c.StateHasChangedSync()
+	Destroy(c)
+	c.StateHasChangedSync()
+
+	// Assert
+	if committedBeforeFlush != 0 {
+		t.Errorf("expected no commit before the batched render, got %d", committedBeforeFlush)
+	}
+	if commits != 2 {
+		t.Errorf("expected a commit for the batch and one for the sync render, got %d", commits)
+	}
+	if rendersAtCommit != 1 {
+		t.Errorf("expected the hook to run before the sync render, after %d renders", rendersAtCommit)
+	}
+	if stopped != 1 {
+		t.Errorf("expected the stopped hook to run once, ran %d times", stopped)
+	}
+}
@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	bootMu    sync.Mutex
+	bootHooks []func(err error)
+)
+
+// OnBootError registers fn to be called when the application fails during startup,
+// i.e. after the WASM module was instantiated but before the app is up and running.
+// Hooks run in registration order; the bootstrap loader is always notified afterwards
+// so it can replace the loading indicator with its error UI.
+func OnBootError(fn func(err error)) {
+	bootMu.Lock()
+	defer bootMu.Unlock()
+	bootHooks = append(bootHooks, fn)
+}
+
+// ReportBootError runs the OnBootError hooks for err and notifies the bootstrap loader.
+func ReportBootError(err error) {
+	if err == nil {
+		return
+	}
+
+	bootMu.Lock()
+	hooks := append([]func(error){}, bootHooks...)
+	bootMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(err)
+	}
+	notifyLoaderBootError(err)
+}
+
+// RecoverBootPanic reports a panic raised during startup as a boot error.
+// Defer it first thing in main so a failing setup shows the loader's error UI
+// instead of leaving a spinner (or a blank mount) behind:
+//
+//	func main() {
+//	    defer runtime.RecoverBootPanic()
+//	    // ... create renderer, register routes, start router ...
+//	    select {}
+//	}
+func RecoverBootPanic() {
+	if rec := recover(); rec != nil {
+		err, ok := rec.(error)
+		if !ok {
+			err = fmt.Errorf("%v", rec)
+		}
+		ReportBootError(fmt.Errorf("panic during startup: %w", err))
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+// notifyLoaderBootError is a no-op in non-WASM builds; there is no loader to notify.
+func notifyLoaderBootError(err error) {}
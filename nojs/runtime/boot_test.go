@@ -0,0 +1,63 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestRecoverBootPanic_ReportsToHooks verifies that a startup panic is recovered
+// and delivered to every registered OnBootError hook.
+func TestRecoverBootPanic_ReportsToHooks(t *testing.T) {
+	var got []error
+	OnBootError(func(err error) { got = append(got, err) })
+	OnBootError(func(err error) { got = append(got, err) })
+	defer func() { bootHooks = nil }()
+
+	func() {
+		defer RecoverBootPanic()
+		panic("router failed to start")
+	}()
+
+	if len(got) != 2 {
+		t.Fatalf("Expected both hooks to run, got %d calls", len(got))
+	}
+	if !strings.Contains(got[0].Error(), "router failed to start") {
+		t.Errorf("Expected panic value in error, got %q", got[0].Error())
+	}
+}
+
+// TestRecoverBootPanic_WrapsErrorValues verifies that error panic values stay unwrappable.
+func TestRecoverBootPanic_WrapsErrorValues(t *testing.T) {
+	sentinel := errors.New("boom")
+	var got error
+	OnBootError(func(err error) { got = err })
+	defer func() { bootHooks = nil }()
+
+	func() {
+		defer RecoverBootPanic()
+		panic(sentinel)
+	}()
+
+	if !errors.Is(got, sentinel) {
+		t.Errorf("Expected reported error to wrap the panic value, got %v", got)
+	}
+}
+
+// TestRecoverBootPanic_NoPanic verifies that hooks are not called on a clean startup.
+func TestRecoverBootPanic_NoPanic(t *testing.T) {
+	called := false
+	OnBootError(func(err error) { called = true })
+	defer func() { bootHooks = nil }()
+
+	func() {
+		defer RecoverBootPanic()
+	}()
+
+	if called {
+		t.Error("Expected no boot error to be reported")
+	}
+}
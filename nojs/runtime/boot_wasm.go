@@ -0,0 +1,14 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+import "syscall/js"
+
+// notifyLoaderBootError calls the bootstrap loader's window.nojsBootError, if present.
+func notifyLoaderBootError(err error) {
+	notify := js.Global().Get("nojsBootError")
+	if notify.Type() == js.TypeFunction {
+		notify.Invoke(err.Error())
+	}
+}
@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/ForgeLogic/nojs/console"
 )
@@ -10,8 +11,20 @@ import (
 // StateHasChanged method, which triggers a UI re-render.
 // This type has no build tags and works in both WASM and test environments.
 type ComponentBase struct {
-	renderer   Renderer  // Use interface type, not concrete implementation
-	slotParent Component // Parent layout if this component is in a []*vdom.VNode slot
+	renderer   Renderer                       // Use interface type, not concrete implementation
+	slotParent Component                      // Parent layout if this component is in a []*vdom.VNode slot
+	lifetime   *componentLifetime             // Handler context state, created by the first NewCtx; reset by Destroy
+	changed    atomic.Bool                    // State changed since the last render, so a Memoized component renders; set from any goroutine
+	failure    atomic.Pointer[ComponentPanic] // Panic of an event handler, shown by the next render (see RecoverHandlerPanic)
+	renderErr  *ComponentPanic                // Failure shown by the last render, nil when it succeeded (see LastRenderError)
+
+	contextParent Component      // Component whose provided context values this one sees
+	contexts      map[string]any // Context values provided to descendants, by key
+}
+
+// base gives the runtime access to the embedded ComponentBase of a component.
+func (b *ComponentBase) base() *ComponentBase {
+	return b
 }
 
 // SetRenderer is called by the framework's runtime to inject a reference
@@ -29,6 +42,8 @@ func (b *ComponentBase) GetRenderer() Renderer {
 
 // StateHasChanged signals to the framework that the component's state has
 // been updated and the UI should be re-rendered to reflect the changes.
+// The render is batched: it runs on the next animation frame, and every call made before
+// then shares it, so a handler may update several fields and call this after each one.
 // If this component is mounted inside a layout's []*vdom.VNode slot,
 // triggers scoped re-render of only that slot. Otherwise, full re-render.
 func (b *ComponentBase) StateHasChanged() {
@@ -36,7 +51,72 @@ func (b *ComponentBase) StateHasChanged() {
 		console.Error("StateHasChanged called, but renderer is nil (component not mounted?)")
 		return
 	}
+	b.changed.Store(true)
+
+	// A render already batched for the next frame covers this call
+	if batcher.isPending(b) {
+		return
+	}
+	// Over the render-rate throttle, the render is coalesced into a later one
+	if !renderRates.admit(b) {
+		return
+	}
+	// Inside the apply phase of a measure cycle, the render waits for the write phase
+	if measurer.deferRender(b) {
+		return
+	}
+	batcher.request(b)
+}
+
+// StateHasChangedSync re-renders the component at once, like StateHasChanged without the
+// batching, so the DOM reflects the new state when it returns. It also bypasses the
+// render-rate throttle. Prefer StateHasChanged; use this only for code that reads the DOM
+// right after the change.
+func (b *ComponentBase) StateHasChangedSync() {
+	if b.renderer == nil {
+		console.Error("StateHasChangedSync called, but renderer is nil (component not mounted?)")
+		return
+	}
+	b.changed.Store(true)
+	b.rerender()
+}
+
+// Invalidate makes the next render of the parent render this component too, even when the
+// component is Memoized and its props did not change. Unlike StateHasChanged it requests no
+// render. Use it when Render reads something besides props and state that changed.
+func (b *ComponentBase) Invalidate() {
+	b.changed.Store(true)
+}
+
+// rerender re-renders the component alone when its renderer supports it (see
+// ComponentRenderer), and otherwise its slot or the whole tree. The renderer must be set.
+func (b *ComponentBase) rerender() {
+	b.commit()
+	if b.renderAlone() {
+		return
+	}
+	b.rerenderTree()
+}
 
+// renderAlone re-renders the component on its own through a ComponentRenderer, reporting
+// false when the renderer is not one or cannot.
+func (b *ComponentBase) renderAlone() bool {
+	scoped, ok := b.renderer.(ComponentRenderer)
+	if !ok {
+		return false
+	}
+	changed := b.changed.Swap(false)
+	if scoped.ReRenderComponent(b) {
+		return true
+	}
+	if changed {
+		b.changed.Store(true)
+	}
+	return false
+}
+
+// rerenderTree re-renders the component's slot or the whole tree. The renderer must be set.
+func (b *ComponentBase) rerenderTree() {
 	// Check if this component is in a layout's slot (in-memory tracking)
 	if b.slotParent != nil {
 		// Scoped re-render: only re-render the parent layout's slot content
@@ -50,6 +130,15 @@ func (b *ComponentBase) StateHasChanged() {
 	b.renderer.ReRender()
 }
 
+// BaseOf returns the ComponentBase embedded in c, or nil when c does not embed one.
+// Renderers implementing ComponentRenderer use it to find the instance a call is for.
+func BaseOf(c Component) *ComponentBase {
+	if owner, ok := c.(interface{ base() *ComponentBase }); ok {
+		return owner.base()
+	}
+	return nil
+}
+
 // SetSlotParent associates this component with a parent layout.
 // Called by the renderer when mounting a child into a layout's []*vdom.VNode slot.
 // No DOM attributes are added—the relationship is tracked entirely in Go memory.
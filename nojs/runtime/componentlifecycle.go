@@ -1,6 +1,3 @@
-//go:build js || wasm
-// +build js wasm
-
 package runtime
 
 // Mountable is implemented by components that need one-time initialization.
@@ -96,6 +93,32 @@ type Unmountable interface {
 	OnUnmount()
 }
 
+// AfterRenderer is implemented by components that need to work with the rendered DOM.
+// OnAfterRender is called after every render of the component once the DOM has been
+// created or patched, so element refs (vdom.ElementRef fields bound with ref="...") are
+// guaranteed to be set. firstRender is true for the render that mounted the instance, to
+// set up a third-party widget or focus an input once. Children are notified before their
+// parents, after every DOM change of the render. It runs outside the render pass, so
+// calling StateHasChanged from it is allowed: the render runs at once, and its hooks once
+// the current ones are done. Hooks that re-render every time are stopped after a few passes.
+//
+// Example:
+//
+//	type LoginForm struct {
+//	    runtime.ComponentBase
+//	    Email    vdom.ElementRef // <input ref="Email" />
+//	    HasError bool
+//	}
+//
+//	func (c *LoginForm) OnAfterRender(firstRender bool) {
+//	    if firstRender || c.HasError {
+//	        c.Email.Focus()
+//	    }
+//	}
+type AfterRenderer interface {
+	OnAfterRender(firstRender bool)
+}
+
 // PropUpdater is implemented by generated component code to support prop updates.
 // This interface is used internally by the framework and should not be implemented manually.
 // The compiler generates the ApplyProps method automatically for each component.
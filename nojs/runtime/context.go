@@ -0,0 +1,138 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// contextTagPrefix starts the tag of a component field the renderer fills from a value
+// provided by an ancestor under the key that follows:
+//
+//	type Breadcrumbs struct {
+//	    runtime.ComponentBase
+//	    Layout *MainLayoutCtx `nojs:"inject:mainLayout"`
+//	}
+//
+// Unlike `nojs:"inject"` services, which are app-wide and keyed by type, context values
+// cascade down the component tree: the nearest ancestor providing the key wins.
+const contextTagPrefix = "inject:"
+
+// ProvideContext makes value available under key to every descendant of provider, which
+// receives it in its fields tagged `nojs:"inject:key"`. Descendants read it each time they
+// render, so providing a new value under the same key reaches them on their next render.
+// Provide values before the descendants first render: when building the provider, as for a
+// layout shared by the routes of an app, or in its OnMount for the components of its own
+// template.
+func ProvideContext(provider Component, key string, value any) {
+	b := BaseOf(provider)
+	if b == nil {
+		return
+	}
+	if b.contexts == nil {
+		b.contexts = make(map[string]any)
+	}
+	b.contexts[key] = value
+}
+
+// SetContextParent makes parent the component child looks up context values from. The
+// renderer links a child to the component rendering it; the router links each component of
+// a route chain to the layout whose slot holds it, as pages render before their layouts.
+func SetContextParent(child, parent Component) {
+	if b := BaseOf(child); b != nil {
+		b.contextParent = parent
+	}
+}
+
+// InjectContext fills the fields of comp tagged `nojs:"inject:key"` with the values its
+// nearest ancestor provided under each key. parent is the component rendering comp; it
+// becomes comp's context parent unless SetContextParent linked comp already. Renderers call
+// it in RenderChild before Render. A key no ancestor provides leaves the field nil; dev
+// builds log an error naming the field.
+func InjectContext(comp, parent Component) {
+	if err := injectContext(comp, parent); err != nil {
+		reportMissingContext(err)
+	}
+}
+
+// injectContext is InjectContext returning the first field that could not be filled.
+// A field whose value changes makes a Memoized comp render.
+func injectContext(comp, parent Component) error {
+	b := BaseOf(comp)
+	if b == nil {
+		return nil
+	}
+	if b.contextParent == nil {
+		b.contextParent = parent
+	}
+
+	v := reflect.ValueOf(comp)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	elem := v.Elem()
+	t := elem.Type()
+
+	var firstErr error
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, ok := strings.CutPrefix(field.Tag.Get("nojs"), contextTagPrefix)
+		if !ok {
+			continue
+		}
+		fv := elem.Field(i)
+		if !field.IsExported() || !fv.CanSet() {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("inject: field %s.%s must be exported", t.Name(), field.Name)
+			}
+			continue
+		}
+
+		value, found := b.lookupContext(key)
+		next := reflect.Zero(field.Type)
+		switch {
+		case !found:
+			if firstErr == nil {
+				firstErr = fmt.Errorf("inject: no ancestor of %s provides context %q for field %s", t.Name(), key, field.Name)
+			}
+		case value != nil && !reflect.TypeOf(value).AssignableTo(field.Type):
+			if firstErr == nil {
+				firstErr = fmt.Errorf("inject: context %q holds a %T, which cannot be assigned to %s.%s of type %s", key, value, t.Name(), field.Name, field.Type)
+			}
+			continue
+		case value != nil:
+			next = reflect.ValueOf(value)
+		}
+
+		if !sameContextValue(fv, next) {
+			fv.Set(next)
+			b.changed.Store(true)
+		}
+	}
+	return firstErr
+}
+
+// lookupContext returns the value the nearest ancestor of b provides under key.
+func (b *ComponentBase) lookupContext(key string) (any, bool) {
+	for ancestor := b.contextParent; ancestor != nil; {
+		ab := BaseOf(ancestor)
+		if ab == nil {
+			break
+		}
+		if value, ok := ab.contexts[key]; ok {
+			return value, true
+		}
+		ancestor = ab.contextParent
+	}
+	return nil, false
+}
+
+// sameContextValue reports whether the field value current equals next. Values that
+// cannot be compared count as changed.
+func sameContextValue(current, next reflect.Value) bool {
+	if !current.Type().Comparable() || !next.Type().Comparable() {
+		return false
+	}
+	defer func() { recover() }() // An interface field holding an uncomparable value
+	return current.Interface() == next.Interface()
+}
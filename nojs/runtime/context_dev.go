@@ -0,0 +1,12 @@
+//go:build dev
+// +build dev
+
+package runtime
+
+import "github.com/ForgeLogic/nojs/console"
+
+// reportMissingContext reports a context field that could not be injected. Dev builds log
+// it as an error.
+func reportMissingContext(err error) {
+	console.Error(err.Error())
+}
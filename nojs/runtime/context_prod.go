@@ -0,0 +1,8 @@
+//go:build !dev
+// +build !dev
+
+package runtime
+
+// reportMissingContext reports a context field that could not be injected. Production
+// builds leave the field nil without a message.
+func reportMissingContext(err error) {}
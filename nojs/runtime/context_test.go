@@ -0,0 +1,75 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// contextTestTheme is a value provided as context.
+type contextTestTheme struct{ Name string }
+
+// contextTestComponent declares a context field.
+type contextTestComponent struct {
+	ComponentBase
+	Theme *contextTestTheme `nojs:"inject:theme"`
+}
+
+func (c *contextTestComponent) Render(Renderer) *vdom.VNode { return vdom.Div(nil) }
+
+// TestInjectContext_NearestAncestorWins verifies a field receives the value of the nearest
+// ancestor providing its key, and a new value on the next injection.
+func TestInjectContext_NearestAncestorWins(t *testing.T) {
+	// Arrange
+	app, layout, panel := &contextTestComponent{}, &contextTestComponent{}, &contextTestComponent{}
+	outer, inner := &contextTestTheme{Name: "outer"}, &contextTestTheme{Name: "inner"}
+	ProvideContext(app, "theme", outer)
+	ProvideContext(layout, "theme", inner)
+	SetContextParent(layout, app)
+	leaf := &contextTestComponent{}
+
+	// Act
+	err := injectContext(leaf, panel)
+	withoutProvider := leaf.Theme
+	SetContextParent(panel, layout)
+	injectContext(leaf, nil)
+	fromLayout := leaf.Theme
+	ProvideContext(layout, "theme", outer)
+	leaf.changed.Store(false)
+	injectContext(leaf, nil)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), `context "theme"`) || withoutProvider != nil {
+		t.Errorf("expected a missing provider to leave the field nil with an error, got %v and %v", withoutProvider, err)
+	}
+	if fromLayout != inner {
+		t.Errorf("expected the nearest provider's value, got %+v", fromLayout)
+	}
+	if leaf.Theme != outer || !leaf.changed.Load() {
+		t.Errorf("expected the new value to be injected and mark the component changed, got %+v", leaf.Theme)
+	}
+}
+
+// TestInjectContext_RejectsValueOfWrongType verifies a value that cannot be assigned to the
+// field is reported and leaves the field alone.
+func TestInjectContext_RejectsValueOfWrongType(t *testing.T) {
+	// Arrange
+	layout := &contextTestComponent{}
+	ProvideContext(layout, "theme", "dark")
+	leaf := &contextTestComponent{}
+
+	// Act
+	err := injectContext(leaf, layout)
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "holds a string") {
+		t.Errorf("expected a type error, got %v", err)
+	}
+	if leaf.Theme != nil {
+		t.Errorf("expected the field to stay nil, got %+v", leaf.Theme)
+	}
+}
@@ -0,0 +1,188 @@
+package runtime
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// ErrorBoundary is implemented by components that render an error view in place of a part
+// of the tree that panicked. When the Render of a component, or one of its event handlers,
+// panics, the renderer recovers and asks the nearest ErrorBoundary, the component itself or
+// its closest ancestor, for the view to render in place of the failed component's tree. The
+// rest of the app keeps working. Without a boundary a default error panel is rendered there,
+// with the stack of the panic in dev builds.
+//
+// The failed component renders again on the next render of its parent or its own next
+// StateHasChanged; a render that succeeds replaces the error view.
+//
+//	func (p *Panel) RenderError(err error, r runtime.Renderer) *vdom.VNode {
+//	    return vdom.Paragraph("This panel could not be displayed.", map[string]any{"class": "error"})
+//	}
+type ErrorBoundary interface {
+	// RenderError returns the view replacing the failed tree. err is a *ComponentPanic.
+	RenderError(err error, r Renderer) *vdom.VNode
+}
+
+// ComponentPanic is the error a renderer recovers from a panic in the Render of a component
+// or in one of its event handlers.
+type ComponentPanic struct {
+	Component Component // The component whose Render or handler panicked; nil when unknown
+	Source    string    // "Render" or "event handler"
+	Value     any       // The value passed to panic
+	Stack     string    // Stack of the goroutine at the panic
+}
+
+// Error describes the panic and the component it happened in.
+func (p *ComponentPanic) Error() string {
+	if p.Component == nil {
+		return fmt.Sprintf("%s panic: %v", p.Source, p.Value)
+	}
+	return fmt.Sprintf("%s panic in component %T: %v", p.Source, p.Component, p.Value)
+}
+
+// Unwrap returns the value passed to panic when it is an error.
+func (p *ComponentPanic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// RenderComponent calls c.Render(r), recovering a panic: the error view of c's nearest
+// ErrorBoundary, or the default error panel, is returned in place of c's tree. A failure
+// left by a panicking event handler of c (see RecoverHandlerPanic) is shown the same way
+// once, instead of calling Render. Renderers call it for every component they render.
+func RenderComponent(c Component, r Renderer) (vnode *vdom.VNode) {
+	b := BaseOf(c)
+	if b != nil {
+		if err := b.failure.Swap(nil); err != nil {
+			return renderFailure(c, r, err)
+		}
+	}
+
+	renderingMu.Lock()
+	rendering = append(rendering, c)
+	depth := len(rendering)
+	renderingMu.Unlock()
+
+	defer func() {
+		renderingMu.Lock()
+		rendering = rendering[:depth-1]
+		renderingMu.Unlock()
+
+		if rec := recover(); rec != nil {
+			err := &ComponentPanic{Component: c, Source: "Render", Value: rec, Stack: string(debug.Stack())}
+			reportComponentPanic(err)
+			vnode = renderFailure(c, r, err)
+		}
+	}()
+	vnode = c.Render(r)
+	if b != nil {
+		b.renderErr = nil
+	}
+	return vnode
+}
+
+// LastRenderError returns the *ComponentPanic shown in place of the tree of c by its last
+// render, or nil when that render succeeded. Components rendering others directly, like the
+// router's AppShell swapping pages, use it to tell a failed render from a regular one.
+func LastRenderError(c Component) error {
+	if b := BaseOf(c); b != nil && b.renderErr != nil {
+		return b.renderErr
+	}
+	return nil
+}
+
+// rendering is the stack of components whose Render runs in RenderComponent, innermost
+// last. Rendering is synchronous, so the top is the component binding the event handlers
+// being built.
+var (
+	renderingMu sync.Mutex
+	rendering   []Component
+)
+
+// RenderingComponent returns the component whose Render is running, or nil outside a render.
+// Event adapters call it as Render builds them, to learn the owner of a handler.
+func RenderingComponent() Component {
+	renderingMu.Lock()
+	defer renderingMu.Unlock()
+	if len(rendering) == 0 {
+		return nil
+	}
+	return rendering[len(rendering)-1]
+}
+
+// RecoverHandlerPanic recovers a panic in an event handler of owner. Defer it where the
+// handler is invoked:
+//
+//	defer runtime.RecoverHandlerPanic(owner)
+//	handler(args)
+//
+// The panic is reported and owner re-renders, showing the error view of its nearest
+// ErrorBoundary, or the default error panel, in place of its tree. With a nil owner the
+// panic is only reported.
+func RecoverHandlerPanic(owner Component) {
+	rec := recover()
+	if rec == nil {
+		return
+	}
+	err := &ComponentPanic{Component: owner, Source: "event handler", Value: rec, Stack: string(debug.Stack())}
+	reportComponentPanic(err)
+	b := BaseOf(owner)
+	if b == nil {
+		return
+	}
+	b.failure.Store(err)
+	b.StateHasChanged()
+}
+
+// renderFailure returns the view replacing the tree of c, which failed with err: the error
+// view of its nearest ErrorBoundary, or the default error panel when it has none or its
+// RenderError panics too. err is recorded as c's last render error, and c is invalidated so
+// the next render of its parent renders it again even when it is Memoized.
+func renderFailure(c Component, r Renderer, err *ComponentPanic) (vnode *vdom.VNode) {
+	if b := BaseOf(c); b != nil {
+		b.renderErr = err
+		b.Invalidate()
+	}
+	boundary := nearestBoundary(c)
+	if boundary == nil {
+		return errorPanel(err, showPanicStack)
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			vnode = errorPanel(err, showPanicStack)
+		}
+	}()
+	if view := boundary.RenderError(err, r); view != nil {
+		return view
+	}
+	return errorPanel(err, showPanicStack)
+}
+
+// nearestBoundary returns c when it is an ErrorBoundary, and otherwise its closest ancestor
+// that is one, following the context parents renderers link.
+func nearestBoundary(c Component) ErrorBoundary {
+	for c != nil {
+		if boundary, ok := c.(ErrorBoundary); ok {
+			return boundary
+		}
+		b := BaseOf(c)
+		if b == nil {
+			return nil
+		}
+		c = b.contextParent
+	}
+	return nil
+}
+
+// errorPanel is the default view rendered in place of a failed tree: the panic, and its stack
+// when withStack is set.
+func errorPanel(err *ComponentPanic, withStack bool) *vdom.VNode {
+	children := []*vdom.VNode{vdom.NewVNode("strong", nil, nil, err.Error())}
+	if withStack {
+		children = append(children, vdom.NewVNode("pre", nil, nil, err.Stack))
+	}
+	return vdom.Div(map[string]any{"class": "nojs-error-panel", "role": "alert"}, children...)
+}
@@ -0,0 +1,15 @@
+//go:build dev
+// +build dev
+
+package runtime
+
+import "github.com/ForgeLogic/nojs/console"
+
+// showPanicStack makes the default error panel show the stack of the panic in dev builds.
+const showPanicStack = true
+
+// reportComponentPanic reports a panic recovered from a Render or an event handler. Dev
+// builds log it as an error with its stack.
+func reportComponentPanic(err *ComponentPanic) {
+	console.Error(err.Error() + "\n" + err.Stack)
+}
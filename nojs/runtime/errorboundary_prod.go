@@ -0,0 +1,14 @@
+//go:build !dev
+// +build !dev
+
+package runtime
+
+import "fmt"
+
+// showPanicStack is off in production builds: the default error panel shows the panic only.
+const showPanicStack = false
+
+// reportComponentPanic reports a panic recovered from a Render or an event handler.
+func reportComponentPanic(err *ComponentPanic) {
+	fmt.Println("ERROR:", err.Error())
+}
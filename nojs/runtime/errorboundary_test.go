@@ -0,0 +1,202 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// brokenTestComponent renders its label, or panics while broken is set.
+type brokenTestComponent struct {
+	ComponentBase
+	label  string
+	broken bool
+}
+
+func (c *brokenTestComponent) Render(Renderer) *vdom.VNode {
+	if c.broken {
+		panic("no rows for " + c.label)
+	}
+	return vdom.Text(c.label)
+}
+
+// Click panics, like a handler hitting a bug.
+func (c *brokenTestComponent) Click() { panic("click failed") }
+
+// panelTestComponent renders its children side by side.
+type panelTestComponent struct {
+	ComponentBase
+	children []Component
+}
+
+func (c *panelTestComponent) Render(r Renderer) *vdom.VNode { return renderPanel(c, c.children, r) }
+
+// renderPanel renders children side by side below parent, as a renderer's RenderChild does.
+func renderPanel(parent Component, children []Component, r Renderer) *vdom.VNode {
+	var nodes []*vdom.VNode
+	for _, child := range children {
+		SetContextParent(child, parent)
+		nodes = append(nodes, RenderComponent(child, r))
+	}
+	return vdom.Div(nil, nodes...)
+}
+
+// boundaryTestComponent is a panelTestComponent implementing ErrorBoundary: it records the
+// errors it receives, and its error view panics when brokenView is set.
+type boundaryTestComponent struct {
+	panelTestComponent
+	brokenView bool
+	errs       []error
+}
+
+func (c *boundaryTestComponent) Render(r Renderer) *vdom.VNode {
+	return renderPanel(c, c.children, r)
+}
+
+func (c *boundaryTestComponent) RenderError(err error, r Renderer) *vdom.VNode {
+	c.errs = append(c.errs, err)
+	if c.brokenView {
+		panic("error view failed")
+	}
+	return vdom.Paragraph("Something went wrong", map[string]any{"class": "error"})
+}
+
+// TestRenderComponent_DefaultPanelReplacesFailedTree verifies a panicking Render without a
+// boundary is replaced by the default error panel while its siblings render.
+func TestRenderComponent_DefaultPanelReplacesFailedTree(t *testing.T) {
+	// Arrange
+	broken := &brokenTestComponent{label: "orders", broken: true}
+	sibling := &brokenTestComponent{label: "news"}
+	panel := &panelTestComponent{children: []Component{broken, sibling}}
+
+	// Act
+	root := RenderComponent(panel, &appTestRenderer{})
+
+	// Assert
+	if len(root.Children) != 2 {
+		t.Fatalf("expected both children to render, got %d", len(root.Children))
+	}
+	failed := root.Children[0]
+	if failed.Attributes["class"] != "nojs-error-panel" || len(failed.Children) == 0 || !strings.Contains(failed.Children[0].Content, "no rows for orders") {
+		t.Errorf("expected the default error panel naming the panic, got %+v", failed)
+	}
+	if root.Children[1].Content != "news" {
+		t.Errorf("expected the sibling to render, got %q", root.Children[1].Content)
+	}
+	var p *ComponentPanic
+	if err := LastRenderError(broken); !errors.As(err, &p) || p.Component != broken || p.Source != "Render" {
+		t.Errorf("expected the panic recorded as the last render error, got %v", err)
+	}
+	if LastRenderError(sibling) != nil || LastRenderError(panel) != nil {
+		t.Error("expected no error for the components that rendered")
+	}
+}
+
+// TestRenderComponent_AncestorBoundaryRendersErrorView verifies the nearest boundary above a
+// failed component renders the view replacing its tree.
+func TestRenderComponent_AncestorBoundaryRendersErrorView(t *testing.T) {
+	// Arrange
+	broken := &brokenTestComponent{label: "orders", broken: true}
+	inner := &panelTestComponent{children: []Component{broken}}
+	boundary := &boundaryTestComponent{panelTestComponent: panelTestComponent{children: []Component{inner}}}
+
+	// Act
+	root := RenderComponent(boundary, &appTestRenderer{})
+
+	// Assert
+	view := root.Children[0].Children[0]
+	if view.Tag != "p" || view.Attributes["class"] != "error" {
+		t.Fatalf("expected the boundary's error view in place of the failed tree, got <%s>", view.Tag)
+	}
+	var p *ComponentPanic
+	if len(boundary.errs) != 1 || !errors.As(boundary.errs[0], &p) || p.Component != broken {
+		t.Errorf("expected RenderError to receive the panic of the failed component, got %v", boundary.errs)
+	}
+}
+
+// TestRenderComponent_BrokenErrorViewFallsBackToPanel verifies a RenderError that panics
+// leaves the default error panel in place.
+func TestRenderComponent_BrokenErrorViewFallsBackToPanel(t *testing.T) {
+	// Arrange
+	broken := &brokenTestComponent{label: "orders", broken: true}
+	boundary := &boundaryTestComponent{panelTestComponent: panelTestComponent{children: []Component{broken}}, brokenView: true}
+
+	// Act
+	root := RenderComponent(boundary, &appTestRenderer{})
+
+	// Assert
+	if got := root.Children[0].Attributes["class"]; got != "nojs-error-panel" {
+		t.Errorf("expected the default error panel, got class %v", got)
+	}
+}
+
+// TestRenderComponent_SuccessfulRenderClearsError verifies the next render that succeeds
+// replaces the error view and clears the error.
+func TestRenderComponent_SuccessfulRenderClearsError(t *testing.T) {
+	// Arrange
+	broken := &brokenTestComponent{label: "orders", broken: true}
+	RenderComponent(broken, &appTestRenderer{})
+
+	// Act
+	broken.broken = false
+	node := RenderComponent(broken, &appTestRenderer{})
+
+	// Assert
+	if node.Content != "orders" {
+		t.Errorf("expected the component's own tree, got %+v", node)
+	}
+	if err := LastRenderError(broken); err != nil {
+		t.Errorf("expected the error to be cleared, got %v", err)
+	}
+}
+
+// TestRecoverHandlerPanic_NextRenderShowsError verifies a panicking handler requests a render
+// of its owner, which shows the error once; the render after it renders the component again.
+func TestRecoverHandlerPanic_NextRenderShowsError(t *testing.T) {
+	// Arrange
+	renderer := &schedulingTestRenderer{}
+	c := &brokenTestComponent{label: "orders"}
+	c.SetRenderer(renderer)
+
+	// Act
+	func() {
+		defer RecoverHandlerPanic(c)
+		c.Click()
+	}()
+	failed := RenderComponent(c, renderer)
+	recovered := RenderComponent(c, renderer)
+
+	// Assert
+	if len(renderer.flushes) != 1 {
+		t.Errorf("expected the panic to request a render, got %d", len(renderer.flushes))
+	}
+	if failed.Attributes["class"] != "nojs-error-panel" || !strings.Contains(failed.Children[0].Content, "event handler panic") {
+		t.Errorf("expected the error panel for the handler panic, got %+v", failed)
+	}
+	if recovered.Content != "orders" {
+		t.Errorf("expected the following render to render the component, got %+v", recovered)
+	}
+}
+
+// TestErrorPanel_Stack verifies the panel shows the stack only when asked to, as dev builds do.
+func TestErrorPanel_Stack(t *testing.T) {
+	// Arrange
+	p := &ComponentPanic{Source: "Render", Value: "boom", Stack: "goroutine 1 [running]:"}
+
+	// Act
+	dev := errorPanel(p, true)
+	prod := errorPanel(p, false)
+
+	// Assert
+	if len(dev.Children) != 2 || dev.Children[1].Tag != "pre" || dev.Children[1].Content != p.Stack {
+		t.Errorf("expected the stack in a <pre>, got %+v", dev.Children)
+	}
+	if len(prod.Children) != 1 {
+		t.Errorf("expected the message only, got %d children", len(prod.Children))
+	}
+}
@@ -0,0 +1,165 @@
+package runtime
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// EventToken identifies a typed application event, created once with DefineEvent and shared
+// by the components that emit and listen to it. Events connect components anywhere in the
+// tree (a search box and a results list in different layout branches) without threading
+// callback props through the layouts in between. Unlike a store they retain nothing: a
+// listener only receives the events emitted after it registered.
+type EventToken[T any] struct {
+	id   uint64
+	name string
+}
+
+// Name returns the name the event was defined with.
+func (t EventToken[T]) Name() string {
+	return t.name
+}
+
+// nextEventID numbers the tokens, so that two events defined with the same name stay distinct.
+var nextEventID atomic.Uint64
+
+// DefineEvent returns the token of a new event carrying payloads of type T. Define events
+// as package-level variables next to the components that use them:
+//
+//	var SearchChanged = runtime.DefineEvent[string]("search-changed")
+func DefineEvent[T any](name string) EventToken[T] {
+	return EventToken[T]{id: nextEventID.Add(1), name: name}
+}
+
+// Emit delivers payload to the current listeners of token, in the order they registered.
+// Delivery runs on the main loop: never during the caller's handler, but right after it, and
+// never concurrently with another delivery, so Emit is safe to call from goroutines. Events
+// are delivered in the order they were emitted; those emitted by a listener follow the ones
+// already queued.
+func Emit[T any](token EventToken[T], payload T) {
+	appEvents.emit(token.id, "event "+token.name, func(fn any) { fn.(func(T))(payload) })
+}
+
+// Listen registers fn to run for each event of token emitted from now on. After fn returns,
+// c re-renders (a scoped re-render when c sits in a layout slot). The listener is removed
+// when c is destroyed, such as when a navigation discards it; stop removes it earlier.
+// Register listeners in OnMount:
+//
+//	func (c *Results) OnMount() {
+//	    runtime.Listen(c, SearchChanged, func(query string) {
+//	        c.Query = query
+//	    })
+//	}
+func Listen[T any](c Component, token EventToken[T], fn func(T)) (stop func()) {
+	l := &eventListener{owner: c, fn: fn}
+	appEvents.add(token.id, l)
+	stopOnDestroy := AfterDestroy(c, func() { appEvents.remove(token.id, l) })
+	return func() {
+		stopOnDestroy()
+		appEvents.remove(token.id, l)
+	}
+}
+
+// eventListener is one Listen registration.
+type eventListener struct {
+	owner   Component
+	fn      any  // func(T) of the token's T
+	removed bool // Guarded by eventBus.mu
+}
+
+// eventBus queues emitted events and delivers them one at a time.
+type eventBus struct {
+	mu        sync.Mutex
+	schedule  func(cb func()) // postTask, or a fake in tests
+	listeners map[uint64][]*eventListener
+	queue     []func() // Pending deliveries, in emission order
+	busy      bool     // A delivery is scheduled or running
+}
+
+// appEvents is the application-wide event bus. All apps on the page share it.
+var appEvents = newEventBus(nil)
+
+// newEventBus creates a bus that delivers through schedule (postTask when nil).
+func newEventBus(schedule func(cb func())) *eventBus {
+	if schedule == nil {
+		schedule = postTask
+	}
+	return &eventBus{schedule: schedule, listeners: make(map[uint64][]*eventListener)}
+}
+
+func (b *eventBus) add(id uint64, l *eventListener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners[id] = append(b.listeners[id], l)
+}
+
+func (b *eventBus) remove(id uint64, l *eventListener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	l.removed = true
+	listeners := b.listeners[id]
+	for i, other := range listeners {
+		if other == l {
+			// Copy, so that the recipients snapshotted by queued deliveries stay intact
+			b.listeners[id] = append(listeners[:i:i], listeners[i+1:]...)
+			break
+		}
+	}
+	if len(b.listeners[id]) == 0 {
+		delete(b.listeners, id)
+	}
+}
+
+// emit queues a delivery to the current listeners of id; call runs a listener's fn. The
+// recipients are fixed now, so listeners registered before the delivery runs wait for the
+// next event.
+func (b *eventBus) emit(id uint64, source string, call func(fn any)) {
+	b.mu.Lock()
+	recipients := b.listeners[id]
+	b.queue = append(b.queue, func() { b.deliver(recipients, source, call) })
+	start := !b.busy
+	b.busy = true
+	b.mu.Unlock()
+
+	if start {
+		b.schedule(b.drain)
+	}
+}
+
+// drain runs the queued deliveries, including those queued meanwhile, until none is left.
+func (b *eventBus) drain() {
+	for {
+		b.mu.Lock()
+		if len(b.queue) == 0 {
+			b.busy = false
+			b.mu.Unlock()
+			return
+		}
+		next := b.queue[0]
+		b.queue = b.queue[1:]
+		b.mu.Unlock()
+
+		next()
+	}
+}
+
+// deliver calls each recipient still registered, then re-renders its component.
+func (b *eventBus) deliver(recipients []*eventListener, source string, call func(fn any)) {
+	for _, l := range recipients {
+		b.mu.Lock()
+		removed := l.removed
+		b.mu.Unlock()
+		if removed {
+			continue // Stopped, or its component destroyed by an earlier listener
+		}
+		Trigger(source, func() {
+			call(l.fn)
+			if owner, ok := l.owner.(interface{ GetRenderer() Renderer }); ok && owner.GetRenderer() == nil {
+				return // Not mounted yet: its first render shows the change
+			}
+			if changer, ok := l.owner.(interface{ StateHasChanged() }); ok {
+				changer.StateHasChanged()
+			}
+		})
+	}
+}
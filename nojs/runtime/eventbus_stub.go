@@ -0,0 +1,10 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+// postTask runs cb synchronously in non-WASM builds so tests are deterministic. An Emit made
+// while a delivery runs, including from another goroutine, is delivered by that one.
+func postTask(cb func()) {
+	cb()
+}
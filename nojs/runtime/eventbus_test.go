@@ -0,0 +1,167 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// mountedListener is a component rendered through a counting renderer.
+func mountedListener() (*rateTestComponent, *appTestRenderer) {
+	renderer := &appTestRenderer{}
+	c := &rateTestComponent{}
+	c.SetRenderer(renderer)
+	return c, renderer
+}
+
+// TestEmit_OrdersListenersAndEvents verifies listeners run in registration order, events in
+// emission order, and an event emitted by a listener after the event being delivered.
+func TestEmit_OrdersListenersAndEvents(t *testing.T) {
+	// Arrange
+	ping := DefineEvent[int]("ping")
+	a, _ := mountedListener()
+	b, _ := mountedListener()
+	t.Cleanup(func() { Destroy(a); Destroy(b) })
+	var got []string
+	Listen(a, ping, func(n int) {
+		got = append(got, "a"+string(rune('0'+n)))
+		if n == 1 {
+			Emit(ping, 2)
+		}
+	})
+	Listen(b, ping, func(n int) { got = append(got, "b"+string(rune('0'+n))) })
+
+	// Act
+	Emit(ping, 1)
+
+	// Assert
+	want := []string{"a1", "b1", "a2", "b2"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestListen_ReRendersAfterCallback verifies the listener's component re-renders once per
+// event, after its callback changed the state.
+func TestListen_ReRendersAfterCallback(t *testing.T) {
+	// Arrange
+	changed := DefineEvent[string]("changed")
+	c, renderer := mountedListener()
+	t.Cleanup(func() { Destroy(c) })
+	var value string
+	rendersInCallback := -1
+	Listen(c, changed, func(v string) {
+		value = v
+		rendersInCallback = renderer.renders
+	})
+	before := renderer.renders
+
+	// Act
+	Emit(changed, "tea")
+
+	// Assert
+	if value != "tea" {
+		t.Errorf("expected the callback to receive tea, got %q", value)
+	}
+	if rendersInCallback != before || renderer.renders != before+1 {
+		t.Errorf("expected one re-render after the callback, got %d during and %d after", rendersInCallback-before, renderer.renders-before)
+	}
+}
+
+// TestListen_DuringEmission_ReceivesLaterEventsOnly verifies a listener registered while an
+// event is delivered does not receive that event.
+func TestListen_DuringEmission_ReceivesLaterEventsOnly(t *testing.T) {
+	// Arrange
+	tick := DefineEvent[int]("tick")
+	a, _ := mountedListener()
+	late, _ := mountedListener()
+	t.Cleanup(func() { Destroy(a); Destroy(late) })
+	var lateGot []int
+	Listen(a, tick, func(n int) {
+		if n == 1 {
+			Listen(late, tick, func(n int) { lateGot = append(lateGot, n) })
+		}
+	})
+
+	// Act
+	Emit(tick, 1)
+	Emit(tick, 2)
+
+	// Assert
+	if len(lateGot) != 1 || lateGot[0] != 2 {
+		t.Errorf("expected the late listener to receive only event 2, got %v", lateGot)
+	}
+}
+
+// TestListen_RemovedOnDestroy verifies a destroyed component no longer receives events, nor
+// does one whose listener was stopped.
+func TestListen_RemovedOnDestroy(t *testing.T) {
+	// Arrange
+	saved := DefineEvent[struct{}]("saved")
+	destroyed, _ := mountedListener()
+	stopped, _ := mountedListener()
+	t.Cleanup(func() { Destroy(stopped) })
+	calls := 0
+	Listen(destroyed, saved, func(struct{}) { calls++ })
+	stop := Listen(stopped, saved, func(struct{}) { calls++ })
+
+	// Act
+	Destroy(destroyed)
+	stop()
+	Emit(saved, struct{}{})
+
+	// Assert
+	if calls != 0 {
+		t.Errorf("expected no deliveries, got %d", calls)
+	}
+	if _, ok := appEvents.listeners[saved.id]; ok {
+		t.Error("expected the event to have no listeners left")
+	}
+}
+
+// TestEmit_FromGoroutines verifies events emitted concurrently are all delivered, one at a
+// time.
+func TestEmit_FromGoroutines(t *testing.T) {
+	// Arrange
+	progress := DefineEvent[int]("progress")
+	c, _ := mountedListener()
+	t.Cleanup(func() { Destroy(c) })
+	var inFlight, overlaps atomic.Int32
+	total := 0
+	Listen(c, progress, func(n int) {
+		if inFlight.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		total += n
+		inFlight.Add(-1)
+	})
+
+	// Act
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Emit(progress, 1)
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if overlaps.Load() != 0 {
+		t.Errorf("expected deliveries never to overlap, got %d overlaps", overlaps.Load())
+	}
+	appEvents.mu.Lock()
+	defer appEvents.mu.Unlock()
+	if total != 50 {
+		t.Errorf("expected 50 deliveries, got %d", total)
+	}
+}
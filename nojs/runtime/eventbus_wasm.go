@@ -0,0 +1,24 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+import "syscall/js"
+
+// postTask runs cb on the browser's event loop once the current task, such as the event
+// handler that emitted, has finished: via queueMicrotask, or setTimeout where it is missing.
+func postTask(cb func()) {
+	var jsCb js.Func
+	jsCb = js.FuncOf(func(this js.Value, args []js.Value) any {
+		jsCb.Release()
+		cb()
+		return nil
+	})
+
+	global := js.Global()
+	if global.Get("queueMicrotask").Type() == js.TypeFunction {
+		global.Call("queueMicrotask", jsCb)
+		return
+	}
+	global.Call("setTimeout", jsCb, 0)
+}
@@ -0,0 +1,68 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+)
+
+// renderFlusher implements RenderAndWait: it coalesces the flush requests made before the
+// next frame into one render and releases every caller once that render has patched.
+type renderFlusher struct {
+	mu        sync.Mutex
+	schedule  func(cb func()) (cancel func()) // requestFrame, or a fake in tests
+	scheduled bool                            // A flush frame is pending
+	waiters   []chan struct{}                 // Released by the pending flush
+}
+
+// newRenderFlusher creates a flusher that runs flushes through schedule
+// (requestFrame when nil).
+func newRenderFlusher(schedule func(cb func()) func()) *renderFlusher {
+	if schedule == nil {
+		schedule = requestFrame
+	}
+	return &renderFlusher{schedule: schedule}
+}
+
+// request asks for a flush on the next frame and returns a channel closed once it completes.
+// render performs the flush and calls done when the DOM is patched; requests made before the
+// frame runs share one call to render.
+func (f *renderFlusher) request(render func(done func())) <-chan struct{} {
+	ch := make(chan struct{})
+
+	f.mu.Lock()
+	f.waiters = append(f.waiters, ch)
+	first := !f.scheduled
+	f.scheduled = true
+	f.mu.Unlock()
+
+	if first {
+		f.schedule(func() { f.flush(render) })
+	}
+	return ch
+}
+
+// flush runs render for the waiters registered so far. Requests made during the render
+// schedule a new frame, so they observe a render that started after they asked.
+func (f *renderFlusher) flush(render func(done func())) {
+	f.mu.Lock()
+	waiters := f.waiters
+	f.waiters = nil
+	f.scheduled = false
+	f.mu.Unlock()
+
+	render(func() {
+		for _, ch := range waiters {
+			close(ch)
+		}
+	})
+}
+
+// wait requests a flush and blocks until it completes or ctx is done.
+func (f *renderFlusher) wait(ctx context.Context, render func(done func())) error {
+	select {
+	case <-f.request(render):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
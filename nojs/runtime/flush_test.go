@@ -0,0 +1,104 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"context"
+	"testing"
+)
+
+// isClosed reports whether ch has been closed.
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// TestRenderFlusher_CoalescesRequestsIntoOneRender verifies that flushes requested before the
+// frame share one render and are all released when it patches.
+func TestRenderFlusher_CoalescesRequestsIntoOneRender(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	f := newRenderFlusher(frames.schedule)
+	renders := 0
+	render := func(done func()) {
+		renders++
+		done()
+	}
+
+	// Act
+	first := f.request(render)
+	second := f.request(render)
+	releasedBeforeFrame := isClosed(first) || isClosed(second)
+	frames.next()
+
+	// Assert
+	if releasedBeforeFrame {
+		t.Error("expected no waiter to be released before the frame")
+	}
+	if renders != 1 {
+		t.Errorf("expected 1 render for 2 requests, got %d", renders)
+	}
+	if !isClosed(first) || !isClosed(second) {
+		t.Error("expected both waiters to be released after the frame")
+	}
+	if frames.next() {
+		t.Error("expected no further frame to be scheduled")
+	}
+}
+
+// TestRenderFlusher_WaitsForRenderQueuedBehindMount verifies a flush requested during a
+// progressive mount is released only after the mount and the render queued behind it.
+func TestRenderFlusher_WaitsForRenderQueuedBehindMount(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	s := newMountScheduler(frames.schedule)
+	f := newRenderFlusher(frames.schedule)
+	h := &progressiveHarness{chunks: 3}
+	s.start(h.step, h.finish, h.replay)
+	frames.next()
+
+	// Act
+	done := f.request(func(done func()) {
+		s.queue()
+		s.afterMount(done)
+	})
+	frames.next() // Second mount step, then the flush frame
+	frames.next()
+	releasedMidMount := isClosed(done)
+	for frames.next() {
+	}
+
+	// Assert
+	if releasedMidMount {
+		t.Error("expected the waiter to be held while the mount is in flight")
+	}
+	if h.replays != 1 {
+		t.Errorf("expected the queued render to be replayed once, got %d", h.replays)
+	}
+	if !isClosed(done) {
+		t.Error("expected the waiter to be released after the mount completed")
+	}
+}
+
+// TestRenderFlusher_WaitHonorsContext verifies wait returns the context error when the flush
+// never runs.
+func TestRenderFlusher_WaitHonorsContext(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	f := newRenderFlusher(frames.schedule)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Act
+	err := f.wait(ctx, func(done func()) { done() })
+
+	// Assert
+	if err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
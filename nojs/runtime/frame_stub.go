@@ -0,0 +1,11 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+// requestFrame runs cb synchronously in non-WASM builds so tests are deterministic.
+// The returned cancel function is a no-op because cb has already run.
+func requestFrame(cb func()) func() {
+	cb()
+	return func() {}
+}
@@ -0,0 +1,45 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+import "syscall/js"
+
+// frameFallbackDelay is the setTimeout delay used where requestAnimationFrame is unavailable.
+const frameFallbackDelay = 16
+
+// requestFrame schedules cb before the next paint via requestAnimationFrame, falling back
+// to setTimeout. The returned function cancels the pending callback and releases its js.Func.
+func requestFrame(cb func()) func() {
+	global := js.Global()
+	useRAF := global.Get("requestAnimationFrame").Type() == js.TypeFunction
+
+	var jsCb js.Func
+	released := false
+	release := func() {
+		if !released {
+			released = true
+			jsCb.Release()
+		}
+	}
+
+	jsCb = js.FuncOf(func(this js.Value, args []js.Value) any {
+		release()
+		cb()
+		return nil
+	})
+
+	if useRAF {
+		id := global.Call("requestAnimationFrame", jsCb)
+		return func() {
+			global.Call("cancelAnimationFrame", id)
+			release()
+		}
+	}
+
+	id := global.Call("setTimeout", jsCb, frameFallbackDelay)
+	return func() {
+		global.Call("clearTimeout", id)
+		release()
+	}
+}
@@ -0,0 +1,215 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Ctx is passed to event handlers declared with the extended signature, which the compiler
+// recognizes in place of the plain one:
+//
+//	func (c *SaveButton) Save(ctx runtime.Ctx, e events.ClickEventArgs) {
+//	    go func() {
+//	        err := c.store.Save(ctx, c.Draft) // Aborted if the user navigates away
+//	        ctx.SafeUpdate(func() { c.Err = err })
+//	    }()
+//	}
+//
+// It is a context.Context cancelled when the component that owns the handler is destroyed,
+// so work started by the handler can stop, and SafeUpdate applies results only while the
+// component is still alive instead of re-rendering a dead one.
+type Ctx struct {
+	context.Context // Cancelled when the component is destroyed
+
+	component Component
+	renderer  Renderer
+	lifetime  *componentLifetime
+}
+
+// componentLifetime is the destruction state of one component, created on first use.
+type componentLifetime struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	destroyed bool // Guarded by lifetimeMu
+
+	hooks   map[*func()]struct{} // Registered by AfterDestroy; guarded by lifetimeMu
+	commits []*func()            // Registered by AfterStateChange, in order; guarded by lifetimeMu
+}
+
+// lifetimeMu guards the lazy creation of lifetimes and their destroyed flag; goroutines
+// started by handlers read it through SafeUpdate.
+var lifetimeMu sync.Mutex
+
+// lifetimeOf returns the current lifetime of c, creating it on first use. Components that
+// do not embed ComponentBase get a lifetime that is never cancelled.
+func lifetimeOf(c Component) *componentLifetime {
+	lifetimeMu.Lock()
+	defer lifetimeMu.Unlock()
+
+	owner, ok := c.(interface{ base() *ComponentBase })
+	if !ok {
+		return &componentLifetime{ctx: context.Background(), cancel: func() {}}
+	}
+	b := owner.base()
+	if b.lifetime == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		b.lifetime = &componentLifetime{ctx: ctx, cancel: cancel}
+	}
+	return b.lifetime
+}
+
+// NewCtx returns the handler context of component c. The extended event adapters call it
+// each time an event fires; tests can call it to invoke a handler directly.
+func NewCtx(c Component) Ctx {
+	lifetime := lifetimeOf(c)
+	var renderer Renderer
+	if owner, ok := c.(interface{ GetRenderer() Renderer }); ok {
+		renderer = owner.GetRenderer()
+	}
+	return Ctx{Context: lifetime.ctx, component: c, renderer: renderer, lifetime: lifetime}
+}
+
+// Destroy marks c as destroyed: every Ctx handed to its handlers so far is cancelled and
+// its SafeUpdate does nothing. The renderer calls it when c unmounts and routers call it
+// for the instances a navigation discards. Should c be mounted again (an app unmounted and
+// run anew), its handlers receive a fresh Ctx.
+func Destroy(c Component) {
+	owner, ok := c.(interface{ base() *ComponentBase })
+	if !ok {
+		return
+	}
+	b := owner.base()
+	renderRates.forget(b)
+	b.contextParent = nil
+
+	lifetimeMu.Lock()
+	lifetime := b.lifetime
+	b.lifetime = nil
+	var hooks []func()
+	if lifetime != nil {
+		lifetime.destroyed = true
+		for hook := range lifetime.hooks {
+			hooks = append(hooks, *hook)
+		}
+		lifetime.hooks = nil
+	}
+	lifetimeMu.Unlock()
+
+	if lifetime != nil {
+		lifetime.cancel()
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+}
+
+// AfterDestroy arranges for fn to run when c is destroyed, synchronously inside Destroy, so
+// packages that hold browser resources on behalf of a component (a geolocation watch, a
+// subscription) release them together with it. The returned function unregisters fn and
+// reports whether it did so before fn ran. For components that do not embed ComponentBase,
+// fn never runs.
+func AfterDestroy(c Component, fn func()) (stop func() bool) {
+	lifetime := lifetimeOf(c)
+	hook := &fn
+
+	lifetimeMu.Lock()
+	defer lifetimeMu.Unlock()
+	if lifetime.destroyed {
+		return func() bool { return false }
+	}
+	if lifetime.hooks == nil {
+		lifetime.hooks = make(map[*func()]struct{})
+	}
+	lifetime.hooks[hook] = struct{}{}
+	return func() bool {
+		lifetimeMu.Lock()
+		defer lifetimeMu.Unlock()
+		if _, pending := lifetime.hooks[hook]; !pending {
+			return false
+		}
+		delete(lifetime.hooks, hook)
+		return true
+	}
+}
+
+// AfterStateChange arranges for fn to run each time a state change of c is committed: when
+// the render requested by StateHasChanged (once for a batch of calls) or StateHasChangedSync
+// starts, on the goroutine that renders. Packages that mirror component state elsewhere,
+// such as storage.Persist, save it then. The hooks end when c is destroyed; the returned
+// function unregisters fn earlier. For components that do not embed ComponentBase, fn
+// never runs.
+func AfterStateChange(c Component, fn func()) (stop func()) {
+	lifetime := lifetimeOf(c)
+	hook := &fn
+
+	lifetimeMu.Lock()
+	defer lifetimeMu.Unlock()
+	if lifetime.destroyed {
+		return func() {}
+	}
+	lifetime.commits = append(lifetime.commits, hook)
+	return func() {
+		lifetimeMu.Lock()
+		defer lifetimeMu.Unlock()
+		for i, other := range lifetime.commits {
+			if other == hook {
+				lifetime.commits = append(lifetime.commits[:i:i], lifetime.commits[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// commit runs the AfterStateChange hooks of b, before the render of its changed state.
+func (b *ComponentBase) commit() {
+	lifetimeMu.Lock()
+	var hooks []*func()
+	if b.lifetime != nil {
+		hooks = b.lifetime.commits
+	}
+	lifetimeMu.Unlock()
+
+	for _, hook := range hooks {
+		(*hook)()
+	}
+}
+
+// Navigate requests client-side navigation, as ComponentBase.Navigate does.
+func (c Ctx) Navigate(path string) error {
+	if c.renderer == nil {
+		return fmt.Errorf("navigate called, but renderer is nil (component not mounted?)")
+	}
+	return c.renderer.Navigate(path)
+}
+
+// Services returns the registry of injectable values of the renderer, or nil when the
+// renderer does not support injection.
+func (c Ctx) Services() *Services {
+	if provider, ok := c.renderer.(ServiceProvider); ok {
+		return provider.Services()
+	}
+	return nil
+}
+
+// Alive reports whether the component has not been destroyed.
+func (c Ctx) Alive() bool {
+	lifetimeMu.Lock()
+	defer lifetimeMu.Unlock()
+	return !c.lifetime.destroyed
+}
+
+// SafeUpdate runs update and re-renders the component, unless the component has been
+// destroyed, in which case it does nothing. Use it from goroutines started by a handler
+// in place of changing fields and calling StateHasChanged.
+func (c Ctx) SafeUpdate(update func()) {
+	if !c.Alive() {
+		return
+	}
+	if update != nil {
+		update()
+	}
+	if changer, ok := c.component.(interface{ StateHasChanged() }); ok {
+		Trigger("Ctx.SafeUpdate", changer.StateHasChanged)
+	}
+}
@@ -0,0 +1,32 @@
+package runtime
+
+// HistoryBag is the state kept with one browser history entry (router.HistoryState). Values
+// are stored as JSON, so they survive a full page reload and come back with the entry on
+// back/forward navigation.
+type HistoryBag interface {
+	// Get decodes the value stored under key into v, a pointer, and reports whether there
+	// was one that decoded.
+	Get(key string, v any) bool
+	// Set stores v under key. It fails when v cannot be encoded as JSON.
+	Set(key string, v any) error
+}
+
+// HistoryStateful is implemented by generated component code for components with fields
+// tagged `nojs:"state,history=key"`. This interface is used internally by the framework and
+// should not be implemented manually.
+//
+// The router calls RestoreHistoryState on each component it creates, before its first
+// render, and SaveHistoryState on each live component before it leaves the history entry.
+//
+// Example:
+//
+//	type SignupStep2 struct {
+//	    runtime.ComponentBase
+//	    Answers map[string]string `nojs:"state,history=answers"`
+//	}
+type HistoryStateful interface {
+	// SaveHistoryState stores the tagged fields in bag under their keys.
+	SaveHistoryState(bag HistoryBag) error
+	// RestoreHistoryState sets the tagged fields found in bag; the others keep their value.
+	RestoreHistoryState(bag HistoryBag)
+}
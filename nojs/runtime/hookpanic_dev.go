@@ -0,0 +1,16 @@
+//go:build dev
+// +build dev
+
+package runtime
+
+import (
+	"runtime/debug"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+// reportHookPanic reports a panic recovered from a lifecycle hook. Dev builds log it as an
+// error with the stack of the panic, to find the failing line.
+func reportHookPanic(c any, key, hook string, rec any) {
+	console.Error(hookPanicMessage(c, key, hook, rec) + "\n" + string(debug.Stack()))
+}
@@ -0,0 +1,12 @@
+//go:build !dev
+// +build !dev
+
+package runtime
+
+import "fmt"
+
+// reportHookPanic reports a panic recovered from a lifecycle hook.
+func reportHookPanic(c any, key, hook string, rec any) {
+	fmt.Println("ERROR:", hookPanicMessage(c, key, hook, rec))
+	// In a real production environment, this could be sent to an error tracking service
+}
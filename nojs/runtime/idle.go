@@ -0,0 +1,234 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+)
+
+// IdleDeadline describes how much idle time is left in the current idle period.
+// Long-running idle work should check TimeRemaining and reschedule itself with
+// ScheduleIdle instead of running past the deadline and delaying the next frame.
+type IdleDeadline struct {
+	timeRemaining func() time.Duration
+	didTimeout    bool
+}
+
+// TimeRemaining returns the estimated time left in the current idle period.
+// It returns 0 once the period is over.
+func (d IdleDeadline) TimeRemaining() time.Duration {
+	if d.timeRemaining == nil {
+		return 0
+	}
+	if remaining := d.timeRemaining(); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// DidTimeout reports whether the callback ran because the browser's idle timeout
+// elapsed rather than because the main thread became idle.
+func (d IdleDeadline) DidTimeout() bool {
+	return d.didTimeout
+}
+
+// IdleHandle identifies a callback scheduled with ScheduleIdle.
+type IdleHandle struct {
+	owner    Component
+	fn       func(deadline IdleDeadline)
+	cancel   func() // Cancels the pending platform callback; set by ScheduleIdle
+	canceled bool
+	done     bool
+}
+
+// Cancel prevents the callback from running if it has not run yet.
+// Calling Cancel on a handle that already ran or was canceled is a no-op.
+func (h *IdleHandle) Cancel() {
+	if h == nil {
+		return
+	}
+	idleMu.Lock()
+	if h.canceled || h.done {
+		idleMu.Unlock()
+		return
+	}
+	h.canceled = true
+	cancel := h.cancel
+	unregisterIdleHandle(h)
+	idleMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// Canceled reports whether the callback was canceled before it ran.
+func (h *IdleHandle) Canceled() bool {
+	idleMu.Lock()
+	defer idleMu.Unlock()
+	return h.canceled
+}
+
+var (
+	idleMu      sync.Mutex                      // Protects idleHandles and handle state
+	idleHandles = map[Component][]*IdleHandle{} // Pending idle callbacks per owning component
+)
+
+// ScheduleIdle runs fn once the browser is idle, after the current render has been painted.
+// It wraps requestIdleCallback (falling back to setTimeout where unsupported), so heavy,
+// non-urgent work such as building a search index does not compete with rendering.
+//
+// The callback is dispatched on the main event loop, outside any render pass, so it may
+// freely mutate state and call StateHasChanged. Pending callbacks are canceled
+// automatically when c is unmounted; use the returned handle to cancel earlier.
+//
+// In non-WASM builds fn runs synchronously before ScheduleIdle returns, which keeps
+// tests deterministic.
+//
+// Example:
+//
+//	func (c *SearchPage) OnMount() {
+//	    runtime.ScheduleIdle(c, c.buildIndex)
+//	}
+//
+//	func (c *SearchPage) buildIndex(deadline runtime.IdleDeadline) {
+//	    for c.next < len(c.Docs) && deadline.TimeRemaining() > time.Millisecond {
+//	        c.index.Add(c.Docs[c.next])
+//	        c.next++
+//	    }
+//	    if c.next < len(c.Docs) {
+//	        runtime.ScheduleIdle(c, c.buildIndex) // Yield and continue in the next idle period
+//	    }
+//	}
+func ScheduleIdle(c Component, fn func(deadline IdleDeadline)) *IdleHandle {
+	h := &IdleHandle{owner: c, fn: fn}
+
+	idleMu.Lock()
+	idleHandles[c] = append(idleHandles[c], h)
+	idleMu.Unlock()
+
+	cancel := requestIdle(func(deadline IdleDeadline) {
+		runIdleHandle(h, deadline)
+	})
+
+	idleMu.Lock()
+	if !h.done && !h.canceled {
+		h.cancel = cancel
+	}
+	idleMu.Unlock()
+
+	return h
+}
+
+// CancelIdle cancels every pending idle callback scheduled for c.
+// The renderer calls it when c is unmounted, so user code rarely needs it.
+func CancelIdle(c Component) {
+	idleMu.Lock()
+	pending := idleHandles[c]
+	delete(idleHandles, c)
+	cancels := make([]func(), 0, len(pending))
+	for _, h := range pending {
+		h.canceled = true
+		if h.cancel != nil {
+			cancels = append(cancels, h.cancel)
+		}
+	}
+	idleMu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// ChunkedFor calls perItem for each element of items, chunkSize items per idle period,
+// and calls StateHasChanged on c once after the last item (a scoped re-render when c
+// sits in a layout slot). A chunk also ends early when the idle deadline runs out.
+// Processing stops when the returned handle is canceled or c is unmounted; in that
+// case no re-render is triggered.
+//
+// Example:
+//
+//	runtime.ChunkedFor(c, c.Products, 50, func(i int, p Product) {
+//	    c.index[p.ID] = p
+//	})
+func ChunkedFor[T any](c Component, items []T, chunkSize int, perItem func(i int, item T)) *IdleHandle {
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	// The outer handle tracks the whole loop; each chunk gets its own idle callback.
+	loop := &IdleHandle{owner: c}
+	idleMu.Lock()
+	idleHandles[c] = append(idleHandles[c], loop)
+	idleMu.Unlock()
+
+	var current *IdleHandle
+	loop.cancel = func() {
+		current.Cancel()
+	}
+
+	next := 0
+	var runChunk func(deadline IdleDeadline)
+	runChunk = func(deadline IdleDeadline) {
+		for processed := 0; next < len(items) && processed < chunkSize; processed++ {
+			if loop.Canceled() {
+				return
+			}
+			perItem(next, items[next])
+			next++
+			if deadline.TimeRemaining() == 0 && !deadline.DidTimeout() {
+				break
+			}
+		}
+		if loop.Canceled() {
+			return
+		}
+
+		if next < len(items) {
+			current = ScheduleIdle(c, runChunk)
+			return
+		}
+
+		idleMu.Lock()
+		loop.done = true
+		unregisterIdleHandle(loop)
+		idleMu.Unlock()
+
+		if notifier, ok := c.(interface{ StateHasChanged() }); ok {
+			notifier.StateHasChanged()
+		}
+	}
+
+	current = ScheduleIdle(c, runChunk)
+	return loop
+}
+
+// runIdleHandle invokes the handle's callback unless it was canceled in the meantime.
+func runIdleHandle(h *IdleHandle, deadline IdleDeadline) {
+	idleMu.Lock()
+	if h.canceled || h.done {
+		idleMu.Unlock()
+		return
+	}
+	h.done = true
+	unregisterIdleHandle(h)
+	idleMu.Unlock()
+
+	h.fn(deadline)
+}
+
+// unregisterIdleHandle removes h from its owner's pending list.
+// The caller must hold idleMu.
+func unregisterIdleHandle(h *IdleHandle) {
+	pending := idleHandles[h.owner]
+	for i, p := range pending {
+		if p == h {
+			pending = append(pending[:i], pending[i+1:]...)
+			break
+		}
+	}
+	if len(pending) == 0 {
+		delete(idleHandles, h.owner)
+	} else {
+		idleHandles[h.owner] = pending
+	}
+}
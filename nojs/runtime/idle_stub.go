@@ -0,0 +1,17 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import "time"
+
+// nativeIdleBudget is the idle time reported to callbacks in non-WASM builds.
+// It matches the longest idle period browsers hand out (50ms).
+const nativeIdleBudget = 50 * time.Millisecond
+
+// requestIdle runs cb synchronously in non-WASM builds so tests are deterministic.
+// The returned cancel function is a no-op because cb has already run.
+func requestIdle(cb func(deadline IdleDeadline)) func() {
+	cb(IdleDeadline{timeRemaining: func() time.Duration { return nativeIdleBudget }})
+	return func() {}
+}
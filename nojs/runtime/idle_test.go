@@ -0,0 +1,97 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// idleTestComponent counts StateHasChanged calls made by ChunkedFor.
+type idleTestComponent struct {
+	ComponentBase
+	renders int
+}
+
+func (c *idleTestComponent) Render(r Renderer) *vdom.VNode { return vdom.Div(nil) }
+
+func (c *idleTestComponent) StateHasChanged() { c.renders++ }
+
+// TestScheduleIdle_RunsSynchronouslyInNativeBuilds verifies the deterministic native fallback.
+func TestScheduleIdle_RunsSynchronouslyInNativeBuilds(t *testing.T) {
+	comp := &idleTestComponent{}
+	ran := false
+
+	h := ScheduleIdle(comp, func(deadline IdleDeadline) {
+		ran = true
+		if deadline.TimeRemaining() <= 0 {
+			t.Errorf("expected a positive idle budget, got %v", deadline.TimeRemaining())
+		}
+	})
+
+	if !ran {
+		t.Fatal("expected callback to run before ScheduleIdle returned")
+	}
+	if h.Canceled() {
+		t.Error("handle of a completed callback must not report canceled")
+	}
+	if len(idleHandles[comp]) != 0 {
+		t.Errorf("expected no pending handles, got %d", len(idleHandles[comp]))
+	}
+}
+
+// TestChunkedFor_ProcessesAllItemsAndRendersOnce verifies every item is visited in order
+// and exactly one re-render is triggered at the end.
+func TestChunkedFor_ProcessesAllItemsAndRendersOnce(t *testing.T) {
+	comp := &idleTestComponent{}
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+	var seen []int
+
+	ChunkedFor(comp, items, 3, func(i int, item int) {
+		if items[i] != item {
+			t.Errorf("index %d: got item %d, want %d", i, item, items[i])
+		}
+		seen = append(seen, item)
+	})
+
+	if len(seen) != len(items) {
+		t.Fatalf("expected %d items processed, got %d", len(items), len(seen))
+	}
+	if comp.renders != 1 {
+		t.Errorf("expected exactly 1 re-render, got %d", comp.renders)
+	}
+	if len(idleHandles[comp]) != 0 {
+		t.Errorf("expected no pending handles, got %d", len(idleHandles[comp]))
+	}
+}
+
+// TestChunkedFor_CanceledOnNavigationMidChunking simulates the component being unmounted
+// (as the renderer does on navigation) while chunks are still being processed.
+func TestChunkedFor_CanceledOnNavigationMidChunking(t *testing.T) {
+	comp := &idleTestComponent{}
+	items := make([]int, 20)
+	processed := 0
+
+	h := ChunkedFor(comp, items, 4, func(i int, item int) {
+		processed++
+		if i == 5 {
+			// Navigation away unmounts the page; the renderer cancels its idle work.
+			CancelIdle(comp)
+		}
+	})
+
+	if processed != 6 {
+		t.Errorf("expected processing to stop after item 5 (6 items), got %d", processed)
+	}
+	if comp.renders != 0 {
+		t.Errorf("expected no re-render after cancellation, got %d", comp.renders)
+	}
+	if !h.Canceled() {
+		t.Error("expected ChunkedFor handle to report canceled")
+	}
+	if len(idleHandles[comp]) != 0 {
+		t.Errorf("expected no pending handles, got %d", len(idleHandles[comp]))
+	}
+}
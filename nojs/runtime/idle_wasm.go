@@ -0,0 +1,69 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+import (
+	"syscall/js"
+	"time"
+)
+
+// idleFallbackDelay is the setTimeout delay used where requestIdleCallback is unavailable
+// (e.g. Safari). It lets the browser paint first, then grants a nominal 50ms budget.
+const idleFallbackDelay = 1
+
+// requestIdle schedules cb via requestIdleCallback, falling back to setTimeout.
+// The returned function cancels the pending callback and releases its js.Func.
+func requestIdle(cb func(deadline IdleDeadline)) func() {
+	global := js.Global()
+	useIdle := global.Get("requestIdleCallback").Type() == js.TypeFunction
+
+	var jsCb js.Func
+	released := false
+	release := func() {
+		if !released {
+			released = true
+			jsCb.Release()
+		}
+	}
+
+	jsCb = js.FuncOf(func(this js.Value, args []js.Value) any {
+		release()
+
+		var deadline IdleDeadline
+		if useIdle && len(args) > 0 {
+			jsDeadline := args[0]
+			deadline = IdleDeadline{
+				timeRemaining: func() time.Duration {
+					ms := jsDeadline.Call("timeRemaining").Float()
+					return time.Duration(ms * float64(time.Millisecond))
+				},
+				didTimeout: jsDeadline.Get("didTimeout").Bool(),
+			}
+		} else {
+			start := time.Now()
+			deadline = IdleDeadline{
+				timeRemaining: func() time.Duration {
+					return 50*time.Millisecond - time.Since(start)
+				},
+			}
+		}
+
+		cb(deadline)
+		return nil
+	})
+
+	if useIdle {
+		id := global.Call("requestIdleCallback", jsCb)
+		return func() {
+			global.Call("cancelIdleCallback", id)
+			release()
+		}
+	}
+
+	id := global.Call("setTimeout", jsCb, idleFallbackDelay)
+	return func() {
+		global.Call("clearTimeout", id)
+		release()
+	}
+}
@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+// injectTag marks a component field the renderer fills from its Services registry:
+//
+//	type Breadcrumbs struct {
+//	    runtime.ComponentBase
+//	    Route *router.RouteContext `nojs:"inject"`
+//	}
+const injectTag = "inject"
+
+// Notifier is implemented by injectable values that change over time. A component that
+// receives a Notifier through injection is subscribed to it and re-renders (through
+// StateHasChanged, so scoped to its slot) whenever the value announces a change.
+type Notifier interface {
+	// Subscribe registers fn to run after every change and returns a function that removes it.
+	Subscribe(fn func()) (unsubscribe func())
+}
+
+// ServiceProvider is implemented by renderers that support field injection.
+type ServiceProvider interface {
+	Services() *Services
+}
+
+// Services is a registry of values injected into component fields tagged `nojs:"inject"`.
+// Values are keyed by their type, so a field of type *router.RouteContext receives the
+// *router.RouteContext provided by the router engine.
+type Services struct {
+	mu            sync.Mutex
+	values        map[reflect.Type]any
+	subscriptions map[Component][]func() // Unsubscribe functions per injected component
+}
+
+// NewServices creates an empty registry.
+func NewServices() *Services {
+	return &Services{
+		values:        make(map[reflect.Type]any),
+		subscriptions: make(map[Component][]func()),
+	}
+}
+
+// Provide registers value for injection, replacing any earlier value of the same type.
+func (s *Services) Provide(value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[reflect.TypeOf(value)] = value
+}
+
+// Inject fills the fields of comp tagged `nojs:"inject"` that are still nil. It is safe to
+// call on every render: fields that are already set are left alone and each component is
+// subscribed to a Notifier only once. It returns an error naming the first tagged field for
+// which no value was provided.
+func (s *Services) Inject(comp Component) error {
+	v := reflect.ValueOf(comp)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	elem := v.Elem()
+	t := elem.Type()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("nojs") != injectTag {
+			continue
+		}
+		fv := elem.Field(i)
+		if !field.IsExported() || !fv.CanSet() {
+			return fmt.Errorf("inject: field %s.%s must be exported", t.Name(), field.Name)
+		}
+		if !fv.IsZero() {
+			continue
+		}
+		value, ok := s.values[field.Type]
+		if !ok {
+			return fmt.Errorf("inject: no value of type %s provided for %s.%s", field.Type, t.Name(), field.Name)
+		}
+		fv.Set(reflect.ValueOf(value))
+
+		if notifier, ok := value.(Notifier); ok {
+			if changer, ok := comp.(interface{ StateHasChanged() }); ok {
+				source := "store " + field.Type.String()
+				changed := func() { Trigger(source, changer.StateHasChanged) }
+				s.subscriptions[comp] = append(s.subscriptions[comp], notifier.Subscribe(changed))
+			}
+		}
+	}
+	return nil
+}
+
+// Release unsubscribes comp from every Notifier it was injected with. The renderer calls it
+// when the component unmounts.
+func (s *Services) Release(comp Component) {
+	s.mu.Lock()
+	unsubscribes := s.subscriptions[comp]
+	delete(s.subscriptions, comp)
+	s.mu.Unlock()
+
+	for _, unsubscribe := range unsubscribes {
+		unsubscribe()
+	}
+}
+
+// InjectServices injects comp through r when r is a ServiceProvider, logging failures.
+// It is a no-op for renderers without injection support.
+func InjectServices(r Renderer, comp Component) {
+	if provider, ok := r.(ServiceProvider); ok && provider.Services() != nil {
+		if err := provider.Services().Inject(comp); err != nil {
+			console.Error(err.Error())
+		}
+	}
+}
+
+// ReleaseServices undoes the subscriptions InjectServices made for comp.
+func ReleaseServices(r Renderer, comp Component) {
+	if provider, ok := r.(ServiceProvider); ok && provider.Services() != nil {
+		provider.Services().Release(comp)
+	}
+}
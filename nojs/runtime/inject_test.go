@@ -0,0 +1,94 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// injectTestClock is an injectable value that announces changes.
+type injectTestClock struct {
+	subscribers []func()
+}
+
+func (c *injectTestClock) Subscribe(fn func()) func() {
+	c.subscribers = append(c.subscribers, fn)
+	index := len(c.subscribers) - 1
+	return func() { c.subscribers[index] = nil }
+}
+
+func (c *injectTestClock) tick() {
+	for _, fn := range c.subscribers {
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// injectTestComponent declares one injected field and counts re-render requests.
+type injectTestComponent struct {
+	ComponentBase
+	Clock   *injectTestClock `nojs:"inject"`
+	Title   string
+	changes int
+}
+
+func (c *injectTestComponent) Render(r Renderer) *vdom.VNode { return vdom.Div(nil) }
+func (c *injectTestComponent) StateHasChanged()              { c.changes++ }
+
+// TestServices_Inject_FillsTaggedFieldsAndSubscribes verifies injection, the one-time
+// subscription to a Notifier, and Release.
+func TestServices_Inject_FillsTaggedFieldsAndSubscribes(t *testing.T) {
+	// Arrange
+	services := NewServices()
+	clock := &injectTestClock{}
+	services.Provide(clock)
+	comp := &injectTestComponent{Title: "kept"}
+
+	// Act
+	if err := services.Inject(comp); err != nil {
+		t.Fatalf("Inject: %v", err)
+	}
+	if err := services.Inject(comp); err != nil {
+		t.Fatalf("second Inject: %v", err)
+	}
+	clock.tick()
+
+	// Assert
+	if comp.Clock != clock {
+		t.Fatal("expected the provided clock to be injected")
+	}
+	if comp.Title != "kept" {
+		t.Errorf("expected untagged fields to be left alone, got %q", comp.Title)
+	}
+	if comp.changes != 1 {
+		t.Errorf("expected one re-render per change after repeated injection, got %d", comp.changes)
+	}
+
+	services.Release(comp)
+	clock.tick()
+	if comp.changes != 1 {
+		t.Errorf("expected no re-render after Release, got %d", comp.changes)
+	}
+}
+
+// TestServices_Inject_MissingValueReportsField verifies the error for an unprovided type.
+func TestServices_Inject_MissingValueReportsField(t *testing.T) {
+	// Arrange
+	services := NewServices()
+	comp := &injectTestComponent{}
+
+	// Act
+	err := services.Inject(comp)
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected an error when no value of the field's type was provided")
+	}
+	if comp.Clock != nil {
+		t.Error("expected the field to stay nil")
+	}
+}
@@ -0,0 +1,48 @@
+package runtime
+
+import "fmt"
+
+// CallOnMount calls OnMount on c if it is Mountable. The renderer runs the lifecycle of the
+// components it renders with RenderChild; code that renders components itself, like the
+// router linking a route's chain into its layouts without an AppShell, calls these helpers
+// so they get the same lifecycle. As in the renderer, a panic in the hook is recovered and
+// reported with the component's type.
+func CallOnMount(c Component) {
+	if mountable, ok := c.(Mountable); ok {
+		runHook(c, "", "OnMount", mountable.OnMount)
+	}
+}
+
+// CallOnParametersSet calls OnParametersSet on c if it is a ParameterReceiver. See CallOnMount.
+func CallOnParametersSet(c Component) {
+	if receiver, ok := c.(ParameterReceiver); ok {
+		runHook(c, "", "OnParametersSet", receiver.OnParametersSet)
+	}
+}
+
+// CallOnUnmount calls OnUnmount on c if it is Unmountable. See CallOnMount.
+func CallOnUnmount(c Component) {
+	if unmountable, ok := c.(Unmountable); ok {
+		runHook(c, "", "OnUnmount", unmountable.OnUnmount)
+	}
+}
+
+// runHook runs the lifecycle hook named hook of component c, rendered at key (empty outside
+// the renderer). A panic is reported instead of propagated, so one failing component does
+// not stop the program.
+func runHook(c any, key, hook string, fn func()) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			reportHookPanic(c, key, hook, rec)
+		}
+	}()
+	fn()
+}
+
+// hookPanicMessage describes a panic recovered from a lifecycle hook.
+func hookPanicMessage(c any, key, hook string, rec any) string {
+	if key == "" {
+		return fmt.Sprintf("%s panic in component %T: %v", hook, c, rec)
+	}
+	return fmt.Sprintf("%s panic in component %T (%s): %v", hook, c, key, rec)
+}
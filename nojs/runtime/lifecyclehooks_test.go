@@ -0,0 +1,59 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// hookTestComponent counts its lifecycle calls and panics in OnMount when told to.
+type hookTestComponent struct {
+	ComponentBase
+	panicOnMount                 bool
+	mounts, parameters, unmounts int
+}
+
+func (c *hookTestComponent) Render(r Renderer) *vdom.VNode { return vdom.Div(nil) }
+func (c *hookTestComponent) OnParametersSet()              { c.parameters++ }
+func (c *hookTestComponent) OnUnmount()                    { c.unmounts++ }
+func (c *hookTestComponent) OnMount() {
+	c.mounts++
+	if c.panicOnMount {
+		panic("fetch failed")
+	}
+}
+
+// TestCallHooks verifies the helpers call the hooks a component implements.
+func TestCallHooks(t *testing.T) {
+	// Arrange
+	c := &hookTestComponent{}
+
+	// Act
+	CallOnMount(c)
+	CallOnParametersSet(c)
+	CallOnUnmount(c)
+	CallOnMount(&idleTestComponent{}) // Implements none of the hooks
+
+	// Assert
+	if c.mounts != 1 || c.parameters != 1 || c.unmounts != 1 {
+		t.Errorf("expected each hook called once, got OnMount %d, OnParametersSet %d, OnUnmount %d", c.mounts, c.parameters, c.unmounts)
+	}
+}
+
+// TestCallHooks_RecoversPanic verifies a panic in a hook is reported, not propagated.
+func TestCallHooks_RecoversPanic(t *testing.T) {
+	// Arrange
+	c := &hookTestComponent{panicOnMount: true}
+
+	// Act
+	CallOnMount(c)
+	CallOnParametersSet(c)
+
+	// Assert
+	if c.mounts != 1 || c.parameters != 1 {
+		t.Errorf("expected the hooks after the panic to run, got OnMount %d, OnParametersSet %d", c.mounts, c.parameters)
+	}
+}
@@ -0,0 +1,273 @@
+package runtime
+
+import (
+	"sync"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// Rect is the layout box of an element in CSS pixels, relative to the viewport, as
+// getBoundingClientRect reports it.
+type Rect struct {
+	X, Y          float64
+	Width, Height float64
+}
+
+// MeasureThen reads the layout with read and hands the result to apply, batched with the
+// measurements of every other component. The reads queued for a frame run together after
+// the current patch, before anything writes to the DOM again, so the browser computes the
+// layout once instead of once per component. The apply callbacks run next and may change
+// state; c and every component that calls StateHasChanged meanwhile are then re-rendered
+// in one pass per renderer, so apply does not need to call StateHasChanged itself.
+//
+// MeasureThen called from apply is queued for the next frame, as is a measurement queued
+// while another cycle runs. Measurements of a component that is unmounted before its
+// cycle are dropped. In non-WASM builds the cycle runs before MeasureThen returns, unless
+// a FakeLayout is installed to run it on Frame.
+//
+// Example:
+//
+//	func (c *Tooltip) OnAfterRender(firstRender bool) {
+//	    runtime.MeasureThen(c, func() any {
+//	        return runtime.ReadRect(&c.Anchor)
+//	    }, func(v any) {
+//	        c.Above = v.(runtime.Rect).Y > 200
+//	    })
+//	}
+func MeasureThen(c Component, read func() any, apply func(any)) {
+	measurer.enqueue(&measurement{owner: c, lifetime: lifetimeOf(c), read: read, apply: apply})
+}
+
+// OnResize calls fn with the content size of the element behind ref, in whole CSS pixels,
+// when the element is first observed and whenever it changes size. It is backed by a
+// ResizeObserver whose sizes are delivered through the MeasureThen cycle: fn runs in the
+// apply phase, sizes reported before the cycle collapse into the latest one, and c is
+// re-rendered afterwards together with the other measuring components.
+//
+// The ref must be set, so call OnResize from OnAfterRender on the first render. Observation stops when
+// stop is called or c is destroyed, whichever comes first. Where ResizeObserver is missing,
+// fn is never called.
+//
+// Example:
+//
+//	func (c *Chart) OnAfterRender(firstRender bool) {
+//	    if firstRender {
+//	        runtime.OnResize(c, &c.Canvas, func(width, height int) {
+//	            c.Columns = width / 80
+//	        })
+//	    }
+//	}
+func OnResize(c Component, ref *vdom.ElementRef, fn func(width, height int)) (stop func()) {
+	o := &resizeObservation{owner: c, fn: fn}
+	disconnect := observeResize(ref, o.resized)
+	unhook := AfterDestroy(c, func() { o.stop(disconnect) })
+	return func() {
+		unhook()
+		o.stop(disconnect)
+	}
+}
+
+// measurement is a read queued by MeasureThen.
+type measurement struct {
+	owner    Component
+	lifetime *componentLifetime // Lifetime of owner when queued; the read is dropped once it ends
+	read     func() any
+	apply    func(any)
+}
+
+// alive reports whether the owner has not been destroyed since the measurement was queued.
+func (m *measurement) alive() bool {
+	lifetimeMu.Lock()
+	defer lifetimeMu.Unlock()
+	return !m.lifetime.destroyed
+}
+
+// measureScheduler runs the measurements queued for a frame in three phases: every read,
+// then every apply, then one re-render per renderer the applies changed.
+type measureScheduler struct {
+	mu        sync.Mutex
+	schedule  func(cb func()) (cancel func()) // requestMeasureFrame, or a fake in tests
+	scheduled bool                            // A cycle frame is pending
+	running   bool                            // A cycle is running; new measurements wait for the next
+	applying  bool                            // StateHasChanged calls are deferred to the write phase
+	queue     []*measurement
+	deferred  []*ComponentBase // Components that called StateHasChanged during the apply phase
+}
+
+// measurer is the scheduler behind MeasureThen and OnResize.
+var measurer = newMeasureScheduler(nil)
+
+// newMeasureScheduler creates a scheduler that runs its cycles through schedule
+// (requestMeasureFrame when nil).
+func newMeasureScheduler(schedule func(cb func()) func()) *measureScheduler {
+	if schedule == nil {
+		schedule = requestMeasureFrame
+	}
+	return &measureScheduler{schedule: schedule}
+}
+
+// enqueue adds m to the next cycle, scheduling one unless it is already pending or running.
+func (s *measureScheduler) enqueue(m *measurement) {
+	s.mu.Lock()
+	s.queue = append(s.queue, m)
+	start := !s.scheduled && !s.running
+	s.scheduled = s.scheduled || start
+	s.mu.Unlock()
+
+	if start {
+		s.schedule(s.run)
+	}
+}
+
+// run is one measure cycle. Measurements queued while it runs go to the next cycle, which
+// it schedules at the end.
+func (s *measureScheduler) run() {
+	s.mu.Lock()
+	batch := s.queue
+	s.queue = nil
+	s.scheduled = false
+	s.running = true
+	s.mu.Unlock()
+
+	// Read phase: nothing writes in between, so the layout is computed at most once
+	values := make([]any, len(batch))
+	for i, m := range batch {
+		if m.alive() {
+			values[i] = m.read()
+		}
+	}
+
+	// Apply phase: re-renders requested by the callbacks are collected for the write phase
+	s.mu.Lock()
+	s.applying = true
+	s.mu.Unlock()
+	for i, m := range batch {
+		if !m.alive() {
+			continue
+		}
+		m, value := m, values[i]
+		Trigger("MeasureThen", func() {
+			m.apply(value)
+			if changer, ok := m.owner.(interface{ StateHasChanged() }); ok {
+				changer.StateHasChanged()
+			}
+		})
+	}
+	s.mu.Lock()
+	s.applying = false
+	deferred := s.deferred
+	s.deferred = nil
+	s.mu.Unlock()
+
+	// Write phase
+	renderDeferred(deferred)
+
+	s.mu.Lock()
+	s.running = false
+	next := len(s.queue) > 0 && !s.scheduled
+	s.scheduled = s.scheduled || next
+	s.mu.Unlock()
+
+	if next {
+		s.schedule(s.run)
+	}
+}
+
+// deferRender records a StateHasChanged call made during the apply phase and reports
+// whether it did; outside the apply phase the caller renders at once.
+func (s *measureScheduler) deferRender(b *ComponentBase) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.applying {
+		return false
+	}
+	s.deferred = append(s.deferred, b)
+	return true
+}
+
+// renderDeferred re-renders the components that changed during an apply phase: each on
+// its own when its renderer supports it (see ComponentRenderer), and the rest once per
+// renderer, and once per layout for components in a layout slot whose renderer does not
+// re-render fully anyway.
+func renderDeferred(bases []*ComponentBase) {
+	for _, b := range bases {
+		b.commit()
+	}
+	rest := bases[:0:0]
+	for _, b := range bases {
+		if !b.renderAlone() {
+			rest = append(rest, b)
+		}
+	}
+	bases = rest
+
+	full := make(map[Renderer]bool)
+	for _, b := range bases {
+		if b.slotParent == nil && !full[b.renderer] {
+			full[b.renderer] = true
+			b.rerenderTree()
+		}
+	}
+	slots := make(map[Component]bool)
+	for _, b := range bases {
+		if b.slotParent != nil && !full[b.renderer] && !slots[b.slotParent] {
+			slots[b.slotParent] = true
+			b.rerenderTree()
+		}
+	}
+}
+
+// resizeObservation delivers the sizes reported by a resize observer through the measure
+// cycle, one per cycle.
+type resizeObservation struct {
+	mu            sync.Mutex
+	owner         Component
+	fn            func(width, height int)
+	width, height int  // Latest reported size
+	queued        bool // A measurement for the latest size is queued
+	stopped       bool
+}
+
+// resized records a size reported by the observer and queues its delivery.
+func (o *resizeObservation) resized(width, height int) {
+	o.mu.Lock()
+	o.width, o.height = width, height
+	queue := !o.queued && !o.stopped
+	o.queued = o.queued || queue
+	o.mu.Unlock()
+
+	if queue {
+		MeasureThen(o.owner, o.read, o.apply)
+	}
+}
+
+// read takes the latest size in the read phase.
+func (o *resizeObservation) read() any {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.queued = false
+	return [2]int{o.width, o.height}
+}
+
+// apply calls fn with the size read, unless the observation has been stopped.
+func (o *resizeObservation) apply(v any) {
+	o.mu.Lock()
+	stopped := o.stopped
+	o.mu.Unlock()
+
+	if size := v.([2]int); !stopped {
+		o.fn(size[0], size[1])
+	}
+}
+
+// stop marks the observation stopped and calls disconnect the first time it is called.
+func (o *resizeObservation) stop(disconnect func()) {
+	o.mu.Lock()
+	stopped := o.stopped
+	o.stopped = true
+	o.mu.Unlock()
+
+	if !stopped {
+		disconnect()
+	}
+}
@@ -0,0 +1,169 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"sync"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// FakeLayout scripts measurements in non-WASM builds. Install one with UseFakeLayout;
+// without one, measure cycles run before MeasureThen returns, ReadRect returns a zero Rect
+// and OnResize never calls back.
+//
+// While a FakeLayout is installed, measure cycles wait for Frame, so a test can queue the
+// measurements of several components and run them as one cycle. ReadRect returns the rect
+// set with SetRect, and Resize reports a new size to the OnResize observers of a ref.
+//
+// Example:
+//
+//	layout := runtime.UseFakeLayout()
+//	t.Cleanup(layout.Uninstall)
+//	layout.SetRect(&card.Body, runtime.Rect{Width: 320, Height: 90})
+//	card.OnAfterRender(true)
+//	layout.Frame()
+type FakeLayout struct {
+	mu        sync.Mutex
+	rects     map[*vdom.ElementRef]Rect
+	observers map[*vdom.ElementRef]map[*fakeResizeObserver]struct{}
+	frames    []func()
+}
+
+// fakeResizeObserver is an observer registered by OnResize on a FakeLayout.
+type fakeResizeObserver struct {
+	resized func(width, height int)
+}
+
+var (
+	layoutMu   sync.Mutex
+	fakeLayout *FakeLayout // Installed by UseFakeLayout
+)
+
+// UseFakeLayout installs an empty FakeLayout, replacing any installed before. Tests using
+// it must not run in parallel.
+func UseFakeLayout() *FakeLayout {
+	f := &FakeLayout{
+		rects:     make(map[*vdom.ElementRef]Rect),
+		observers: make(map[*vdom.ElementRef]map[*fakeResizeObserver]struct{}),
+	}
+	layoutMu.Lock()
+	fakeLayout = f
+	layoutMu.Unlock()
+	return f
+}
+
+// Uninstall removes f if it is the installed fake. Frames still pending run first, so no
+// measure cycle is left waiting.
+func (f *FakeLayout) Uninstall() {
+	f.Frame()
+	layoutMu.Lock()
+	defer layoutMu.Unlock()
+	if fakeLayout == f {
+		fakeLayout = nil
+	}
+}
+
+// SetRect sets the rect ReadRect returns for ref.
+func (f *FakeLayout) SetRect(ref *vdom.ElementRef, rect Rect) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rects[ref] = rect
+}
+
+// Resize sets the size of ref's rect and reports it to the OnResize observers of ref. The
+// observers are called back on the next Frame.
+func (f *FakeLayout) Resize(ref *vdom.ElementRef, width, height int) {
+	f.mu.Lock()
+	rect := f.rects[ref]
+	rect.Width, rect.Height = float64(width), float64(height)
+	f.rects[ref] = rect
+	observers := make([]*fakeResizeObserver, 0, len(f.observers[ref]))
+	for o := range f.observers[ref] {
+		observers = append(observers, o)
+	}
+	f.mu.Unlock()
+
+	for _, o := range observers {
+		o.resized(width, height)
+	}
+}
+
+// Observers returns the number of OnResize observations of ref not yet stopped.
+func (f *FakeLayout) Observers(ref *vdom.ElementRef) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.observers[ref])
+}
+
+// Frame runs the frames pending when it is called and reports whether there were any.
+// Frames requested meanwhile, such as a cycle for MeasureThen called from apply, wait for
+// the next Frame.
+func (f *FakeLayout) Frame() bool {
+	f.mu.Lock()
+	frames := f.frames
+	f.frames = nil
+	f.mu.Unlock()
+
+	for _, frame := range frames {
+		frame()
+	}
+	return len(frames) > 0
+}
+
+// installedLayout returns the installed fake, or nil.
+func installedLayout() *FakeLayout {
+	layoutMu.Lock()
+	defer layoutMu.Unlock()
+	return fakeLayout
+}
+
+// requestMeasureFrame queues cb for the next Frame of the installed FakeLayout, or runs it
+// synchronously when none is installed.
+func requestMeasureFrame(cb func()) func() {
+	f := installedLayout()
+	if f == nil {
+		cb()
+		return func() {}
+	}
+	f.mu.Lock()
+	f.frames = append(f.frames, cb)
+	f.mu.Unlock()
+	return func() {}
+}
+
+// ReadRect returns the rect scripted for ref with FakeLayout.SetRect.
+func ReadRect(ref *vdom.ElementRef) Rect {
+	f := installedLayout()
+	if f == nil {
+		return Rect{}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rects[ref]
+}
+
+// observeResize registers an observer of ref on the installed FakeLayout and reports the
+// rect's current size to it, as a ResizeObserver does when it starts observing.
+func observeResize(ref *vdom.ElementRef, resized func(width, height int)) (disconnect func()) {
+	f := installedLayout()
+	if f == nil {
+		return func() {}
+	}
+	o := &fakeResizeObserver{resized: resized}
+	f.mu.Lock()
+	if f.observers[ref] == nil {
+		f.observers[ref] = make(map[*fakeResizeObserver]struct{})
+	}
+	f.observers[ref][o] = struct{}{}
+	rect := f.rects[ref]
+	f.mu.Unlock()
+
+	resized(int(rect.Width), int(rect.Height))
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.observers[ref], o)
+	}
+}
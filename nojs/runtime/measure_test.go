@@ -0,0 +1,148 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// measureTestComponent measures the width of its box.
+type measureTestComponent struct {
+	ComponentBase
+	Box   vdom.ElementRef
+	Width float64
+}
+
+func (c *measureTestComponent) Render(r Renderer) *vdom.VNode { return vdom.Div(nil) }
+
+// newMeasured returns n components sharing one counting renderer, on an installed FakeLayout.
+func newMeasured(t *testing.T, n int) (*FakeLayout, *appTestRenderer, []*measureTestComponent) {
+	t.Helper()
+	layout := UseFakeLayout()
+	t.Cleanup(layout.Uninstall)
+	renderer := &appTestRenderer{}
+	comps := make([]*measureTestComponent, n)
+	for i := range comps {
+		comps[i] = &measureTestComponent{}
+		comps[i].SetRenderer(renderer)
+	}
+	return layout, renderer, comps
+}
+
+// TestMeasureThen_SameCycleRendersOnce verifies two components measuring in the same cycle
+// read before either applies, and trigger exactly one render pass afterwards.
+func TestMeasureThen_SameCycleRendersOnce(t *testing.T) {
+	// Arrange
+	layout, renderer, comps := newMeasured(t, 2)
+	layout.SetRect(&comps[0].Box, Rect{Width: 320})
+	layout.SetRect(&comps[1].Box, Rect{Width: 180})
+	var phases []string
+	for i, c := range comps {
+		c, name := c, string(rune('a'+i))
+		MeasureThen(c, func() any {
+			phases = append(phases, "read "+name)
+			return ReadRect(&c.Box)
+		}, func(v any) {
+			phases = append(phases, "apply "+name)
+			c.Width = v.(Rect).Width
+			c.StateHasChanged()
+		})
+	}
+
+	// Act
+	rendersBeforeFrame := renderer.renders
+	layout.Frame()
+
+	// Assert
+	if rendersBeforeFrame != 0 || len(phases) != 4 {
+		t.Fatalf("expected the cycle to wait for the frame, got %d renders before it", rendersBeforeFrame)
+	}
+	if got := strings.Join(phases, ", "); got != "read a, read b, apply a, apply b" {
+		t.Errorf("expected every read before any apply, got %s", got)
+	}
+	if comps[0].Width != 320 || comps[1].Width != 180 {
+		t.Errorf("expected widths 320 and 180, got %v and %v", comps[0].Width, comps[1].Width)
+	}
+	if renderer.renders != 1 {
+		t.Errorf("expected exactly one render pass, got %d", renderer.renders)
+	}
+}
+
+// TestMeasureThen_NestedCallRunsNextCycle verifies MeasureThen called from apply waits for the
+// next frame and renders in a pass of its own.
+func TestMeasureThen_NestedCallRunsNextCycle(t *testing.T) {
+	// Arrange
+	layout, renderer, comps := newMeasured(t, 1)
+	c := comps[0]
+	nested := false
+	MeasureThen(c, func() any { return nil }, func(any) {
+		MeasureThen(c, func() any { return nil }, func(any) { nested = true })
+	})
+
+	// Act
+	layout.Frame()
+	nestedInFirstCycle, rendersAfterFirst := nested, renderer.renders
+	layout.Frame()
+
+	// Assert
+	if nestedInFirstCycle || rendersAfterFirst != 1 {
+		t.Fatalf("expected the nested measurement to wait, got ran=%v after %d renders", nestedInFirstCycle, rendersAfterFirst)
+	}
+	if !nested || renderer.renders != 2 {
+		t.Errorf("expected the nested measurement in the second cycle, got ran=%v after %d renders", nested, renderer.renders)
+	}
+}
+
+// TestMeasureThen_DestroyedComponentIsSkipped verifies a measurement of a component destroyed
+// before its cycle neither reads nor renders.
+func TestMeasureThen_DestroyedComponentIsSkipped(t *testing.T) {
+	// Arrange
+	layout, renderer, comps := newMeasured(t, 1)
+	read := false
+	MeasureThen(comps[0], func() any { read = true; return nil }, func(any) {})
+
+	// Act
+	Destroy(comps[0])
+	layout.Frame()
+
+	// Assert
+	if read || renderer.renders != 0 {
+		t.Errorf("expected no read and no render, got read=%v and %d renders", read, renderer.renders)
+	}
+}
+
+// TestOnResize_DeliversLatestSizePerCycle verifies sizes reported before a cycle collapse into
+// the latest one, and that stopping or destroying the owner disconnects the observer.
+func TestOnResize_DeliversLatestSizePerCycle(t *testing.T) {
+	// Arrange
+	layout, renderer, comps := newMeasured(t, 2)
+	layout.SetRect(&comps[0].Box, Rect{Width: 100, Height: 40})
+	var sizes [][2]int
+	stop := OnResize(comps[0], &comps[0].Box, func(width, height int) {
+		sizes = append(sizes, [2]int{width, height})
+	})
+	OnResize(comps[1], &comps[1].Box, func(width, height int) {})
+
+	// Act
+	layout.Frame()
+	layout.Resize(&comps[0].Box, 200, 40)
+	layout.Resize(&comps[0].Box, 240, 60)
+	layout.Frame()
+	stop()
+	Destroy(comps[1])
+
+	// Assert
+	if len(sizes) != 2 || sizes[0] != [2]int{100, 40} || sizes[1] != [2]int{240, 60} {
+		t.Errorf("expected the initial size then the latest one, got %v", sizes)
+	}
+	if renderer.renders != 2 {
+		t.Errorf("expected one render pass per cycle, got %d", renderer.renders)
+	}
+	if layout.Observers(&comps[0].Box) != 0 || layout.Observers(&comps[1].Box) != 0 {
+		t.Error("expected stop and Destroy to disconnect the observers")
+	}
+}
@@ -0,0 +1,57 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+import (
+	"math"
+	"syscall/js"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// requestMeasureFrame schedules a measure cycle before the next paint.
+func requestMeasureFrame(cb func()) func() {
+	return requestFrame(cb)
+}
+
+// ReadRect returns the bounding rect of the element behind ref, or a zero Rect if the ref is
+// not set. Reading it forces a layout when the DOM changed since the last one, so call it
+// from the read function of MeasureThen.
+func ReadRect(ref *vdom.ElementRef) Rect {
+	if !ref.IsSet() {
+		return Rect{}
+	}
+	rect := ref.Call("getBoundingClientRect")
+	return Rect{
+		X:      rect.Get("x").Float(),
+		Y:      rect.Get("y").Float(),
+		Width:  rect.Get("width").Float(),
+		Height: rect.Get("height").Float(),
+	}
+}
+
+// observeResize observes ref with a ResizeObserver and reports each content size to resized.
+// The returned function disconnects the observer and releases its callback. Nothing is
+// observed when the ref is not set or the browser lacks ResizeObserver.
+func observeResize(ref *vdom.ElementRef, resized func(width, height int)) (disconnect func()) {
+	ctor := js.Global().Get("ResizeObserver")
+	if !ref.IsSet() || ctor.Type() != js.TypeFunction {
+		return func() {}
+	}
+
+	cb := js.FuncOf(func(this js.Value, args []js.Value) any {
+		entries := args[0]
+		if n := entries.Length(); n > 0 {
+			rect := entries.Index(n - 1).Get("contentRect")
+			resized(int(math.Round(rect.Get("width").Float())), int(math.Round(rect.Get("height").Float())))
+		}
+		return nil
+	})
+	observer := ctor.New(cb)
+	observer.Call("observe", ref.Value())
+	return func() {
+		observer.Call("disconnect")
+		cb.Release()
+	}
+}
@@ -0,0 +1,49 @@
+package runtime
+
+// Memoized is implemented by components whose Render depends only on their props and their
+// own state. When a parent re-renders, RenderChild reuses the tree such a child rendered last
+// time, without calling its Render, if PropsEqual reports the new props equal and the child
+// has not called StateHasChanged or Invalidate since it last rendered.
+//
+// The compiler generates PropsEqual for components whose struct's doc comment holds a
+// //nojs:memo line:
+//
+//	// PriceTable renders thousands of rows.
+//	//nojs:memo
+//	type PriceTable struct {
+//	    runtime.ComponentBase
+//	    Rows []Row
+//	}
+//
+// Comparable props are compared with ==, slices and slot content with SameSlice, and func
+// props not at all: the new callbacks are applied without a render.
+type Memoized interface {
+	// PropsEqual reports whether other, a freshly built instance of the same component,
+	// carries the props the receiver last rendered with.
+	PropsEqual(other Component) bool
+}
+
+// ReuseRender reports whether a renderer may return the tree instance rendered last time
+// instead of rendering it again with the props of next: instance implements Memoized, its
+// props equal next's and its state did not change since it last rendered. Call it before
+// ApplyProps. When it reports false the caller renders instance, which is then up to date.
+func ReuseRender(instance, next Component) bool {
+	memo, ok := instance.(Memoized)
+	if !ok {
+		return false
+	}
+	b := BaseOf(instance)
+	if b == nil {
+		return false
+	}
+	changed := b.changed.Swap(false)
+	return !changed && memo.PropsEqual(next)
+}
+
+// SameSlice reports whether a and b are the same slice: same length and, when not empty,
+// the same backing array. Generated PropsEqual methods compare slice props and slot content
+// with it, so a parent forces a memoized child to render by passing a new slice rather than
+// changing the elements of the old one.
+func SameSlice[T any](a, b []T) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
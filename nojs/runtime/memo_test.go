@@ -0,0 +1,65 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// memoTestComponent is a Memoized component with one prop.
+type memoTestComponent struct {
+	ComponentBase
+	Label string
+}
+
+func (c *memoTestComponent) Render(Renderer) *vdom.VNode { return vdom.Text(c.Label) }
+
+func (c *memoTestComponent) PropsEqual(other Component) bool {
+	o, ok := other.(*memoTestComponent)
+	return ok && o.Label == c.Label
+}
+
+// TestReuseRender_FollowsPropsAndState verifies a Memoized instance is reused only while
+// its props are equal and it did not change its state, and that a change is consumed by
+// the render it causes.
+func TestReuseRender_FollowsPropsAndState(t *testing.T) {
+	// Arrange
+	instance := &memoTestComponent{Label: "a"}
+	instance.SetRenderer(&appTestRenderer{})
+
+	// Act
+	sameProps := ReuseRender(instance, &memoTestComponent{Label: "a"})
+	newProps := ReuseRender(instance, &memoTestComponent{Label: "b"})
+	instance.Invalidate()
+	invalidated := ReuseRender(instance, &memoTestComponent{Label: "a"})
+	afterRender := ReuseRender(instance, &memoTestComponent{Label: "a"})
+
+	// Assert
+	if !sameProps || !afterRender {
+		t.Errorf("expected equal props to reuse the render, got %v and %v", sameProps, afterRender)
+	}
+	if newProps || invalidated {
+		t.Errorf("expected new props and Invalidate to render, got %v and %v", newProps, invalidated)
+	}
+	if ReuseRender(&idleTestComponent{}, &idleTestComponent{}) {
+		t.Error("expected a component that is not Memoized to render")
+	}
+}
+
+// TestSameSlice_ComparesIdentityNotElements verifies slices are the same only when they share
+// their length and backing array.
+func TestSameSlice_ComparesIdentityNotElements(t *testing.T) {
+	// Arrange
+	rows := []int{1, 2, 3}
+
+	// Act & Assert
+	if !SameSlice(rows, rows) || !SameSlice[int](nil, []int{}) {
+		t.Error("expected a slice and two empty slices to be the same")
+	}
+	if SameSlice(rows, rows[:2]) || SameSlice(rows, []int{1, 2, 3}) {
+		t.Error("expected a shorter slice and a copy to differ")
+	}
+}
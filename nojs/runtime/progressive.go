@@ -0,0 +1,167 @@
+package runtime
+
+import "sync"
+
+// MountMode selects when a progressive mount attaches the page to the document.
+type MountMode int
+
+const (
+	// MountOnComplete builds the tree off-document and attaches it once, after the last chunk.
+	MountOnComplete MountMode = iota
+	// MountStreaming attaches the tree after the first chunk, so the page appears top-down.
+	MountStreaming
+)
+
+// ProgressiveMountOptions enables progressive mounting of the first render. Instead of
+// creating the whole DOM in one task, the renderer creates NodeBudget nodes per animation
+// frame, keeping the main thread responsive while a very large page mounts.
+//
+// While the mount is in flight, re-renders (StateHasChanged, navigation) are queued and run
+// once, together, after the last chunk; OnAfterRender hooks of the first render also wait for
+// it. Later renders are patched as usual.
+type ProgressiveMountOptions struct {
+	// NodeBudget is the number of DOM nodes created per frame (vdom.DefaultMountBudget when <= 0).
+	NodeBudget int
+
+	// Mode selects whether the page appears chunk by chunk or all at once at the end.
+	Mode MountMode
+}
+
+// mountScheduler runs a chunked mount one step per scheduled frame and holds back renders
+// requested while it is in flight, replaying them once after the mount completes.
+type mountScheduler struct {
+	mu         sync.Mutex
+	schedule   func(cb func()) (cancel func()) // requestFrame, or a fake in tests
+	active     bool
+	pending    bool     // A render was requested mid-mount
+	cancel     func()   // Cancels the next scheduled step
+	generation int      // Incremented by start and abort so stale steps stop
+	idle       []func() // Run once the mount in flight and its replayed render complete
+}
+
+// newMountScheduler creates a scheduler that runs steps through schedule
+// (requestFrame when nil).
+func newMountScheduler(schedule func(cb func()) func()) *mountScheduler {
+	if schedule == nil {
+		schedule = requestFrame
+	}
+	return &mountScheduler{schedule: schedule}
+}
+
+// start calls step once per scheduled frame until it reports the mount complete, then
+// calls finish. If a render was queued in the meantime, replay runs once after finish.
+// The first step is scheduled rather than run directly, so in the browser start may be
+// called while holding the renderer's lock.
+func (s *mountScheduler) start(step func() bool, finish func(), replay func()) {
+	s.mu.Lock()
+	s.generation++
+	gen := s.generation
+	s.active = true
+	s.pending = false
+	s.mu.Unlock()
+
+	s.scheduleStep(gen, step, finish, replay)
+}
+
+// scheduleStep schedules the next step of the mount identified by gen.
+func (s *mountScheduler) scheduleStep(gen int, step func() bool, finish func(), replay func()) {
+	cancel := s.schedule(func() {
+		if !s.isCurrent(gen) {
+			return
+		}
+		if !step() {
+			s.scheduleStep(gen, step, finish, replay)
+			return
+		}
+
+		s.mu.Lock()
+		if s.generation != gen {
+			s.mu.Unlock()
+			return
+		}
+		s.active = false
+		s.cancel = nil
+		queued := s.pending
+		s.pending = false
+		s.mu.Unlock()
+
+		finish()
+		if queued {
+			replay()
+		}
+		s.runIdle()
+	})
+
+	s.mu.Lock()
+	if s.generation == gen && s.active {
+		s.cancel = cancel
+	}
+	s.mu.Unlock()
+}
+
+// isCurrent reports whether the mount identified by gen is still in flight.
+func (s *mountScheduler) isCurrent(gen int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active && s.generation == gen
+}
+
+// queue records a render request and reports true when a mount is in flight; the caller
+// must then skip the render, which runs once the mount completes. It reports false (and
+// records nothing) when no mount is in flight.
+func (s *mountScheduler) queue() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.active {
+		s.pending = true
+	}
+	return s.active
+}
+
+// mounting reports whether a mount is in flight.
+func (s *mountScheduler) mounting() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+// abort stops the mount in flight, if any, dropping queued renders.
+func (s *mountScheduler) abort() {
+	s.mu.Lock()
+	s.generation++
+	s.active = false
+	s.pending = false
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.runIdle()
+}
+
+// afterMount runs cb once no mount is in flight: immediately when idle, otherwise after the
+// mount completes and any render queued during it has run (or after the mount is aborted).
+func (s *mountScheduler) afterMount(cb func()) {
+	s.mu.Lock()
+	if s.active {
+		s.idle = append(s.idle, cb)
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	cb()
+}
+
+// runIdle runs and clears the callbacks registered with afterMount.
+func (s *mountScheduler) runIdle() {
+	s.mu.Lock()
+	idle := s.idle
+	s.idle = nil
+	s.mu.Unlock()
+
+	for _, cb := range idle {
+		cb()
+	}
+}
@@ -0,0 +1,163 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import "testing"
+
+// fakeFrames is a manual frame scheduler: callbacks run only when the test advances a frame.
+type fakeFrames struct {
+	pending []*fakeFrame
+}
+
+// fakeFrame is a scheduled callback that can be canceled before it runs.
+type fakeFrame struct {
+	cb       func()
+	canceled bool
+}
+
+func (f *fakeFrames) schedule(cb func()) func() {
+	frame := &fakeFrame{cb: cb}
+	f.pending = append(f.pending, frame)
+	return func() { frame.canceled = true }
+}
+
+// next runs the oldest pending callback and reports whether there was one.
+func (f *fakeFrames) next() bool {
+	for len(f.pending) > 0 {
+		frame := f.pending[0]
+		f.pending = f.pending[1:]
+		if !frame.canceled {
+			frame.cb()
+			return true
+		}
+	}
+	return false
+}
+
+// progressiveHarness counts the calls a mountScheduler makes during a mount of chunks steps.
+type progressiveHarness struct {
+	chunks   int
+	steps    int
+	finished int
+	replays  int
+}
+
+func (h *progressiveHarness) step() bool {
+	h.steps++
+	return h.steps == h.chunks
+}
+
+func (h *progressiveHarness) finish() { h.finished++ }
+
+func (h *progressiveHarness) replay() { h.replays++ }
+
+// TestMountScheduler_OneStepPerFrame verifies the mount never runs synchronously and takes one
+// frame per chunk.
+func TestMountScheduler_OneStepPerFrame(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	s := newMountScheduler(frames.schedule)
+	h := &progressiveHarness{chunks: 3}
+
+	// Act
+	s.start(h.step, h.finish, h.replay)
+	stepsBeforeFrames := h.steps
+	framesRun := 0
+	for frames.next() {
+		framesRun++
+	}
+
+	// Assert
+	if stepsBeforeFrames != 0 {
+		t.Errorf("expected no step before the first frame, got %d", stepsBeforeFrames)
+	}
+	if framesRun != 3 || h.steps != 3 {
+		t.Errorf("expected 3 frames and 3 steps, got %d frames and %d steps", framesRun, h.steps)
+	}
+	if h.finished != 1 || h.replays != 0 {
+		t.Errorf("expected finish once and no replay, got %d finish and %d replays", h.finished, h.replays)
+	}
+	if s.mounting() {
+		t.Error("expected the mount to be complete")
+	}
+}
+
+// TestMountScheduler_StateChangeMidMountQueuesUntilComplete verifies renders requested while the
+// mount is in flight are held back and replayed once, after the mount completes.
+func TestMountScheduler_StateChangeMidMountQueuesUntilComplete(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	s := newMountScheduler(frames.schedule)
+	h := &progressiveHarness{chunks: 4}
+	s.start(h.step, h.finish, h.replay)
+	frames.next()
+
+	// Act
+	queuedFirst := s.queue()
+	queuedSecond := s.queue()
+	rendersMidMount := h.replays
+	for frames.next() {
+	}
+
+	// Assert
+	if !queuedFirst || !queuedSecond {
+		t.Error("expected state changes during the mount to be queued")
+	}
+	if rendersMidMount != 0 {
+		t.Errorf("expected no render before the mount completes, got %d", rendersMidMount)
+	}
+	if h.finished != 1 {
+		t.Errorf("expected finish once, got %d", h.finished)
+	}
+	if h.replays != 1 {
+		t.Errorf("expected queued renders to coalesce into 1 replay, got %d", h.replays)
+	}
+	if s.queue() {
+		t.Error("expected renders after the mount to run immediately")
+	}
+}
+
+// TestMountScheduler_AbortDropsQueuedRender verifies unmounting mid-mount stops further steps
+// and drops renders queued meanwhile.
+func TestMountScheduler_AbortDropsQueuedRender(t *testing.T) {
+	// Arrange
+	frames := &fakeFrames{}
+	s := newMountScheduler(frames.schedule)
+	h := &progressiveHarness{chunks: 5}
+	s.start(h.step, h.finish, h.replay)
+	frames.next()
+	s.queue()
+
+	// Act
+	s.abort()
+	for frames.next() {
+	}
+
+	// Assert
+	if h.steps != 1 {
+		t.Errorf("expected no steps after abort, got %d in total", h.steps)
+	}
+	if h.finished != 0 || h.replays != 0 {
+		t.Errorf("expected no finish or replay after abort, got %d and %d", h.finished, h.replays)
+	}
+	if s.mounting() {
+		t.Error("expected no mount in flight after abort")
+	}
+}
+
+// TestMountScheduler_NativeFramesRunSynchronously verifies the default native scheduler
+// completes the mount before start returns, keeping tests deterministic.
+func TestMountScheduler_NativeFramesRunSynchronously(t *testing.T) {
+	// Arrange
+	s := newMountScheduler(nil)
+	h := &progressiveHarness{chunks: 3}
+
+	// Act
+	s.start(h.step, h.finish, h.replay)
+
+	// Assert
+	if h.steps != 3 || h.finished != 1 {
+		t.Errorf("expected 3 steps and finish before start returned, got %d and %d", h.steps, h.finished)
+	}
+}
@@ -1,10 +1,18 @@
 package runtime
 
-import "github.com/ForgeLogic/nojs/vdom"
+import (
+	"context"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
 
 // Renderer defines the minimal set of runtime operations used by generated Render() code.
 // This interface has NO build tags, making it available to both WASM and native test builds.
 // This allows the AOT compiler to generate identical Render() signatures for both environments.
+//
+// It is also the supported contract for third-party components: a component may rely on
+// these methods only, never on the concrete type behind them. RendererImpl (js/wasm) is the
+// browser implementation, and rendertest.TestRenderer implements it for native tests.
 type Renderer interface {
 	// RenderChild is used by generated code to render child components.
 	// The key parameter uniquely identifies the component instance for state preservation.
@@ -23,4 +31,21 @@ type Renderer interface {
 	// Navigate performs client-side navigation to the given path.
 	// Used by Link components and programmatic navigation.
 	Navigate(path string) error
+
+	// RenderAndWait flushes every pending render and returns once the DOM (or, in tests,
+	// the rendered VDOM) reflects the current component state, or when ctx is done.
+	// In the browser it renders on the next animation frame and waits for the patch, so it
+	// must be called from a goroutine, never directly from an event handler.
+	RenderAndWait(ctx context.Context) error
+}
+
+// ComponentRenderer is implemented by renderers that can re-render one component of the
+// tree on its own. StateHasChanged on a child component then calls only that child's Render
+// and patches only the part of the DOM it rendered, instead of re-rendering from the root.
+// The child keeps the props its parent last applied; siblings and ancestors do not render.
+type ComponentRenderer interface {
+	// ReRenderComponent re-renders the component embedding b and patches what it rendered.
+	// It reports false when it cannot: for the root component, a component it has not
+	// rendered, or one whose root element changed tag. The caller then re-renders the tree.
+	ReRenderComponent(b *ComponentBase) bool
 }
@@ -0,0 +1,25 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+// callOnMount invokes the OnMount lifecycle method of the component at key.
+func (r *RendererImpl) callOnMount(mountable Mountable, key string) {
+	runHook(mountable, key, "OnMount", mountable.OnMount)
+}
+
+// callOnParametersSet invokes the OnParametersSet lifecycle method of the component at key.
+func (r *RendererImpl) callOnParametersSet(receiver ParameterReceiver, key string) {
+	runHook(receiver, key, "OnParametersSet", receiver.OnParametersSet)
+}
+
+// callOnUnmount invokes the OnUnmount lifecycle method of the component at key.
+func (r *RendererImpl) callOnUnmount(unmountable Unmountable, key string) {
+	runHook(unmountable, key, "OnUnmount", unmountable.OnUnmount)
+}
+
+// callOnAfterRender invokes the OnAfterRender lifecycle method of the component at key;
+// firstRender reports whether the render mounted it.
+func (r *RendererImpl) callOnAfterRender(afterRenderer AfterRenderer, key string, firstRender bool) {
+	runHook(afterRenderer, key, "OnAfterRender", func() { afterRenderer.OnAfterRender(firstRender) })
+}
@@ -4,14 +4,18 @@
 package runtime
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"sync"
 
+	"github.com/ForgeLogic/nojs/console"
 	"github.com/ForgeLogic/nojs/vdom"
 )
 
 // Compile-time assertion to ensure the concrete RendererImpl implements the Renderer interface.
 var _ Renderer = (*RendererImpl)(nil)
+var _ ComponentRenderer = (*RendererImpl)(nil)
 
 // RendererImpl is the concrete implementation of the Renderer interface.
 // It manages the component instance tree and handles rendering lifecycle.
@@ -23,11 +27,20 @@ type RendererImpl struct {
 	activeKeys        map[string]bool   // Track which components are active in the current render
 	currentComponent  Component         // The currently active root component (set by router or directly)
 	currentKey        string            // Key for component-level reconciliation (e.g., current route path)
+	replacedRoot      Component         // Mounted root replaced by SetCurrentComponent, unmounted by the next render
 	navManager        NavigationManager // Optional: router for client-side navigation
 	mountID           string
 	prevVDOM          *vdom.VNode               // Previous VDOM tree for patching
 	instanceVDOMCache map[Component]*vdom.VNode // Track VDOM per component instance (for scoped updates)
 	renderingStack    []Component               // Stack of components currently rendering (for scoped cache keys)
+	rendered          []renderedComponent       // Components rendered in the current pass, for OnAfterRender
+	services          *Services                 // Values injected into fields tagged nojs:"inject"
+	progressive       *ProgressiveMountOptions  // Chunked first render; nil mounts in one task
+	hydrate           bool                      // The first render adopts the markup of the mount element (see Hydrate)
+	mountScheduler    *mountScheduler           // Runs progressive mount steps and queues renders meanwhile
+	mountRendered     []renderedComponent       // Components awaiting OnAfterRender until the mount completes
+	flusher           *renderFlusher            // Coalesces RenderAndWait calls into one render per frame
+	afterRender       afterRenderQueue          // Runs OnAfterRender hooks, one pass after another
 }
 
 // NewRenderer creates a new runtime renderer.
@@ -43,9 +56,41 @@ func NewRenderer(navManager NavigationManager, mountID string) *RendererImpl {
 		mountID:           mountID,
 		prevVDOM:          nil,
 		renderingStack:    make([]Component, 0),
+		services:          NewServices(),
+		mountScheduler:    newMountScheduler(nil),
+		flusher:           newRenderFlusher(nil),
 	}
 }
 
+// SetProgressiveMount enables progressive mounting of the first render (see
+// ProgressiveMountOptions). Call it before the first render; later renders are unaffected.
+func (r *RendererImpl) SetProgressiveMount(opts ProgressiveMountOptions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progressive = &opts
+}
+
+// Hydrate makes the first render adopt the markup the mount element holds, a page
+// pre-rendered at build time, instead of replacing it (see vdom.Hydrate): the page does not
+// flash and keeps its scroll position. It reports whether the first render will hydrate,
+// false when the mount element holds no markup or the renderer has rendered already.
+// Progressive mounting does not apply to a hydrated first render.
+func (r *RendererImpl) Hydrate() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.prevVDOM != nil || !vdom.HasMarkup(r.mountID) {
+		return false
+	}
+	r.hydrate = true
+	return true
+}
+
+// Services returns the registry of values injected into component fields tagged
+// `nojs:"inject"`. Register values with Provide before the first render.
+func (r *RendererImpl) Services() *Services {
+	return r.services
+}
+
 // GetCurrentComponent returns the current root component being rendered.
 // This is used by the router engine to access methods on the root component (e.g., AppShell).
 func (r *RendererImpl) GetCurrentComponent() Component {
@@ -59,33 +104,68 @@ func (r *RendererImpl) GetCurrentComponent() Component {
 // When the key changes, the entire component tree is replaced instead of patched.
 // This is typically called by the router's onChange callback when navigation occurs.
 // For non-routed apps, it can be called directly with a static component and empty key.
+// A mounted root that comp replaces is unmounted by the next render, before comp mounts.
 // This method is thread-safe.
 func (r *RendererImpl) SetCurrentComponent(comp Component, key string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if r.currentComponent != comp && r.initialized["__root__"] {
+		r.replacedRoot = r.currentComponent
+		delete(r.initialized, "__root__")
+	}
 	r.currentComponent = comp
 	r.currentKey = key
 }
 
 // RenderRoot starts the rendering process for the entire application.
 // This method is thread-safe and protected by a mutex.
+// OnAfterRender hooks run once the DOM is patched and the mutex is released.
+// While a progressive mount is in flight the render is queued until the mount completes.
 func (r *RendererImpl) RenderRoot() {
+	if r.mountScheduler.queue() {
+		return
+	}
+
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	r.renderRootLocked()
+	rendered := r.takeRendered()
+	if r.mountScheduler.mounting() {
+		// The first render is still being mounted; its hooks run once the DOM is complete
+		r.mountRendered = rendered
+		rendered = nil
+	}
+	r.mu.Unlock()
 
+	r.notifyAfterRender(rendered)
+}
+
+// renderRootLocked renders the root component and patches the DOM.
+// The caller must hold r.mu.
+func (r *RendererImpl) renderRootLocked() {
 	// Reset activeKeys for this render cycle
 	r.activeKeys = make(map[string]bool)
 
+	if r.replacedRoot != nil {
+		r.destroyRoot(r.replacedRoot)
+		r.replacedRoot = nil
+	}
+
 	// On each root render, we build the VDOM tree from the current component.
 	// Ensure the component has a reference to the renderer for StateHasChanged and Navigate.
+	firstRender := false
 	if r.currentComponent != nil {
 		r.currentComponent.SetRenderer(r)
+		renderRates.identify(r.currentComponent, "__root__")
 
 		// Push root component onto rendering stack
 		r.renderingStack = append(r.renderingStack, r.currentComponent)
 
 		// Handle root component lifecycle
-		if _, initialized := r.initialized["__root__"]; !initialized {
+		_, initialized := r.initialized["__root__"]
+		firstRender = !initialized
+		if !initialized {
+			r.injectServices(r.currentComponent)
+
 			// Call OnMount only once, before first render
 			if mountable, ok := r.currentComponent.(Mountable); ok {
 				r.callOnMount(mountable, "__root__")
@@ -99,34 +179,39 @@ func (r *RendererImpl) RenderRoot() {
 		}
 	}
 
-	newVDOM := r.currentComponent.Render(r)
+	newVDOM := RenderComponent(r.currentComponent, r)
 
 	// Pop root component from rendering stack
 	if len(r.renderingStack) > 0 {
 		r.renderingStack = r.renderingStack[:len(r.renderingStack)-1]
 	}
+	r.rendered = append(r.rendered, renderedComponent{component: r.currentComponent, key: "__root__", first: firstRender})
 
 	// Attach the component key to the root VNode for reconciliation
 	newVDOM.ComponentKey = r.currentKey
+	vdom.Normalize(newVDOM)
 
-	if r.prevVDOM == nil {
+	if r.prevVDOM == nil && r.hydrate {
+		// Initial render over a pre-rendered page: adopt its DOM, or render fresh without one
+		r.hydrate = false
+		if !vdom.Hydrate(r.mountID, newVDOM) {
+			vdom.Clear(r.mountID, nil)
+			vdom.RenderToSelector(r.mountID, newVDOM)
+		}
+	} else if r.prevVDOM == nil {
 		// Initial render: clear and render fresh
 		vdom.Clear(r.mountID, nil)
-		vdom.RenderToSelector(r.mountID, newVDOM)
+		if r.progressive != nil {
+			r.startProgressiveMount(newVDOM)
+		} else {
+			vdom.RenderToSelector(r.mountID, newVDOM)
+		}
 	} else {
 		// Check if component key changed (e.g., router navigation)
 		if r.prevVDOM.ComponentKey != newVDOM.ComponentKey {
 			// Component key changed - replace entire tree
 			vdom.Clear(r.mountID, r.prevVDOM)
 			vdom.RenderToSelector(r.mountID, newVDOM)
-
-			// Call OnUnmount on old root component
-			if unmountable, ok := r.currentComponent.(Unmountable); ok {
-				r.callOnUnmount(unmountable, "__root__")
-			}
-
-			// Reset initialization tracking for fresh component lifecycle
-			r.initialized = make(map[string]bool)
 		} else {
 			// Same key - patch normally
 			vdom.Patch(r.mountID, r.prevVDOM, newVDOM)
@@ -143,6 +228,33 @@ func (r *RendererImpl) RenderRoot() {
 	r.cleanupUnmountedComponents()
 }
 
+// startProgressiveMount mounts newVDOM in chunks of the configured node budget, one chunk per
+// animation frame. The VDOM bookkeeping is updated by the caller as for a regular mount, so
+// renders queued meanwhile patch against newVDOM once the DOM is complete.
+// The caller must hold r.mu.
+func (r *RendererImpl) startProgressiveMount(newVDOM *vdom.VNode) {
+	mount := vdom.NewProgressiveMount(r.mountID, newVDOM, r.progressive.Mode == MountStreaming)
+	if mount == nil {
+		return
+	}
+	budget := r.progressive.NodeBudget
+
+	step := func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return mount.Step(budget)
+	}
+	finish := func() {
+		r.mu.Lock()
+		rendered := r.mountRendered
+		r.mountRendered = nil
+		r.mu.Unlock()
+
+		r.notifyAfterRender(rendered)
+	}
+	r.mountScheduler.start(step, finish, r.RenderRoot)
+}
+
 // RenderChild is called by compiler-generated code to render a child component.
 // It handles the core logic of instance creation and reuse.
 // Uses a composite key that includes the parent component context to avoid collisions
@@ -151,8 +263,9 @@ func (r *RendererImpl) RenderChild(key string, childWithProps Component) *vdom.V
 	// Create a globally unique key by including the parent component's pointer
 	// This prevents collisions when multiple parents render children with the same key
 	globalKey := key
+	var parent Component
 	if len(r.renderingStack) > 0 {
-		parent := r.renderingStack[len(r.renderingStack)-1]
+		parent = r.renderingStack[len(r.renderingStack)-1]
 		globalKey = fmt.Sprintf("%p:%s", parent, key)
 	}
 
@@ -169,16 +282,26 @@ func (r *RendererImpl) RenderChild(key string, childWithProps Component) *vdom.V
 		isFirstRender = true
 	} else {
 		// We have seen this component before. Preserve the existing instance to keep state.
+		// A Memoized instance whose props, context and state did not change keeps its last render.
+		InjectContext(instance, parent)
+		reuse := ReuseRender(instance, childWithProps)
+
 		// Apply new props from childWithProps to the existing instance.
 		if updater, ok := instance.(PropUpdater); ok {
-			println("[RenderChild] Found cached component, calling ApplyProps for key:", globalKey)
+			console.Debug("[RenderChild] Found cached component, calling ApplyProps for key:", globalKey)
 			updater.ApplyProps(childWithProps)
 		}
+		if cached := r.instanceVDOMCache[instance]; reuse && cached != nil {
+			// The patcher skips the reused subtree; the children it rendered stay mounted
+			r.keepDescendants(instance)
+			return cached
+		}
 	}
 
 	// Now, render the child (either the new or reused one).
 	// Ensure the instance knows about the renderer so it can call StateHasChanged.
 	instance.SetRenderer(r)
+	renderRates.identify(instance, globalKey)
 
 	// Track slot parent relationship (child is inside parent's []*vdom.VNode slot)
 	// This enables scoped re-renders when child calls StateHasChanged()
@@ -191,6 +314,9 @@ func (r *RendererImpl) RenderChild(key string, childWithProps Component) *vdom.V
 
 	// Call lifecycle methods in the correct order
 	if isFirstRender {
+		r.injectServices(instance)
+		InjectContext(instance, parent)
+
 		// Call OnMount only once, before first render
 		if mountable, ok := instance.(Mountable); ok {
 			r.callOnMount(mountable, globalKey)
@@ -203,29 +329,135 @@ func (r *RendererImpl) RenderChild(key string, childWithProps Component) *vdom.V
 		r.callOnParametersSet(paramReceiver, globalKey)
 	}
 
-	// Push instance onto rendering stack before calling Render
+	// Push instance onto rendering stack before calling Render. RenderComponent recovers a
+	// panic in Render, and the deferred pop keeps the stack right should anything else panic.
+	depth := len(r.renderingStack)
 	r.renderingStack = append(r.renderingStack, instance)
-	vnode := instance.Render(r)
-	// Pop from rendering stack after Render completes
-	r.renderingStack = r.renderingStack[:len(r.renderingStack)-1]
+	defer func() { r.renderingStack = r.renderingStack[:depth] }()
+	vnode := RenderComponent(instance, r)
+	r.rendered = append(r.rendered, renderedComponent{component: instance, key: globalKey, first: isFirstRender})
+
+	// Cache the child's VDOM so it can later re-render on its own (see ReRenderComponent)
+	r.instanceVDOMCache[instance] = vnode
 
 	return vnode
 }
 
+// ReRenderComponent re-renders the child component embedding b on its own and patches
+// only the DOM it rendered. The instance keeps the props its parent last applied through
+// ApplyProps, and OnParametersSet is not called since they did not change. Children it
+// stops rendering are unmounted. It reports false, rendering nothing, for the root
+// component, an instance this renderer does not hold or during a progressive mount. When
+// the new VDOM cannot be patched in place, because its root tag changed or its element
+// is gone, the whole tree is rendered instead.
+func (r *RendererImpl) ReRenderComponent(b *ComponentBase) bool {
+	if r.mountScheduler.mounting() {
+		return false
+	}
+
+	r.mu.Lock()
+	ok := r.reRenderComponentLocked(b)
+	rendered := r.takeRendered()
+	r.mu.Unlock()
+
+	if ok {
+		r.notifyAfterRender(rendered)
+	}
+	return ok
+}
+
+// reRenderComponentLocked performs the scoped re-render. The caller must hold r.mu.
+func (r *RendererImpl) reRenderComponentLocked(b *ComponentBase) bool {
+	key, instance := r.childOf(b)
+	if instance == nil {
+		return false
+	}
+	old := r.instanceVDOMCache[instance]
+	root := r.instanceVDOMCache[r.currentComponent]
+	if old == nil || root == nil {
+		return false
+	}
+
+	r.activeKeys = make(map[string]bool)
+	r.activeKeys[key] = true
+	InjectContext(instance, nil)
+	start := len(r.rendered)
+	next := r.renderInstance(instance)
+	r.rendered = append(r.rendered, renderedComponent{component: instance, key: key})
+	if next != nil {
+		vdom.Normalize(next)
+	}
+
+	if !vdom.PatchNode(r.mountID, root, old, next) {
+		// Root tag changed or the element is gone: the parent must render it afresh, reusing
+		// the new VDOM if the instance is Memoized
+		r.rendered = r.rendered[:start]
+		r.instanceVDOMCache[instance] = next
+		r.renderRootLocked()
+		return true
+	}
+
+	// old now shows next, so caches holding next (a child rendered at the instance's root
+	// element included) point at old, the node that stays in the tree
+	for comp, vnode := range r.instanceVDOMCache {
+		if vnode == next {
+			r.instanceVDOMCache[comp] = old
+		}
+	}
+	r.cleanupUnmountedDescendants(instance)
+	return true
+}
+
+// keepDescendants marks the components below parent active in the current render, as a
+// parent whose last render is reused does not render them again. The caller must hold r.mu.
+func (r *RendererImpl) keepDescendants(parent Component) {
+	parents := []Component{parent}
+	for len(parents) > 0 {
+		prefix := fmt.Sprintf("%p:", parents[0])
+		parents = parents[1:]
+		for key, instance := range r.instances {
+			if strings.HasPrefix(key, prefix) {
+				r.activeKeys[key] = true
+				parents = append(parents, instance)
+			}
+		}
+	}
+}
+
+// childOf returns the key and instance of the child component embedding b, or a nil
+// instance when b is not one of the renderer's children. The caller must hold r.mu.
+func (r *RendererImpl) childOf(b *ComponentBase) (string, Component) {
+	for key, instance := range r.instances {
+		if BaseOf(instance) == b {
+			return key, instance
+		}
+	}
+	return "", nil
+}
+
+// renderInstance calls Render on an instance the renderer already holds, with the
+// instance on the rendering stack so its children keep their keys. The caller must hold r.mu.
+func (r *RendererImpl) renderInstance(instance Component) *vdom.VNode {
+	depth := len(r.renderingStack)
+	r.renderingStack = append(r.renderingStack, instance)
+	defer func() { r.renderingStack = r.renderingStack[:depth] }()
+	return RenderComponent(instance, r)
+}
+
+// injectServices fills the component's injected fields before it mounts.
+func (r *RendererImpl) injectServices(comp Component) {
+	if err := r.services.Inject(comp); err != nil {
+		console.Error(err.Error())
+	}
+}
+
 // cleanupUnmountedComponents removes components that are no longer in the tree
 // and calls their OnUnmount lifecycle method if they implement the Unmountable interface.
 func (r *RendererImpl) cleanupUnmountedComponents() {
 	for key, instance := range r.instances {
 		// If the component wasn't marked as active in this render, it's been unmounted
 		if !r.activeKeys[key] {
-			// Call OnUnmount if the component implements Unmountable
-			if unmountable, ok := instance.(Unmountable); ok {
-				r.callOnUnmount(unmountable, key)
-			}
-
-			// Remove from tracking maps
-			delete(r.instances, key)
-			delete(r.initialized, key)
+			r.unmountChild(key, instance)
 		}
 	}
 
@@ -233,6 +465,48 @@ func (r *RendererImpl) cleanupUnmountedComponents() {
 	r.activeKeys = make(map[string]bool)
 }
 
+// cleanupUnmountedDescendants is cleanupUnmountedComponents for a render of parent alone:
+// only the components below parent are considered, found by the parent pointer their
+// keys start with.
+func (r *RendererImpl) cleanupUnmountedDescendants(parent Component) {
+	parents := []Component{parent}
+	for len(parents) > 0 {
+		prefix := fmt.Sprintf("%p:", parents[0])
+		parents = parents[1:]
+		for key, instance := range r.instances {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			// Its own children are below parent too, and unmounted with it when inactive
+			parents = append(parents, instance)
+			if !r.activeKeys[key] {
+				r.unmountChild(key, instance)
+			}
+		}
+	}
+
+	r.activeKeys = make(map[string]bool)
+}
+
+// unmountChild calls OnUnmount on a child component that is no longer rendered, releases
+// what it holds and stops tracking it.
+func (r *RendererImpl) unmountChild(key string, instance Component) {
+	// Call OnUnmount if the component implements Unmountable
+	if unmountable, ok := instance.(Unmountable); ok {
+		r.callOnUnmount(unmountable, key)
+	}
+
+	// Drop idle work scheduled by the component so it never runs against a dead instance
+	CancelIdle(instance)
+	r.services.Release(instance)
+	Destroy(instance)
+
+	// Remove from tracking maps
+	delete(r.instances, key)
+	delete(r.initialized, key)
+	delete(r.instanceVDOMCache, instance)
+}
+
 // ReRender patches the DOM with minimal changes.
 // This method is thread-safe and can be called from multiple goroutines.
 // If multiple goroutines call this simultaneously, only one will execute at a time.
@@ -241,14 +515,40 @@ func (r *RendererImpl) ReRender() {
 	r.RenderRoot()
 }
 
+// RenderAndWait renders the root component on the next animation frame and returns once
+// the DOM is patched and OnAfterRender hooks have run. Calls made before the frame share
+// one render. During a progressive mount it returns after the mount and the render queued
+// behind it complete. Call it from a goroutine: blocking an event handler stalls the frame.
+func (r *RendererImpl) RenderAndWait(ctx context.Context) error {
+	return r.flusher.wait(ctx, func(done func()) {
+		r.RenderRoot()
+		r.mountScheduler.afterMount(done)
+	})
+}
+
 // ReRenderSlot patches only the BodyContent slot of a layout,
 // preserving the layout instance and its state.
 // Works by diffing the entire parent layout VDOM; only changed content is patched.
 // Called when a page component (inside a layout's slot) calls StateHasChanged().
 func (r *RendererImpl) ReRenderSlot(slotParent Component) error {
+	if r.mountScheduler.queue() {
+		// Mid-mount: the queued full render after the mount also covers the slot
+		return nil
+	}
+
 	r.mu.Lock()
-	defer r.mu.Unlock()
+	err := r.reRenderSlotLocked(slotParent)
+	rendered := r.takeRendered()
+	r.mu.Unlock()
+
+	if err == nil {
+		r.notifyAfterRender(rendered)
+	}
+	return err
+}
 
+// reRenderSlotLocked performs the scoped slot re-render. The caller must hold r.mu.
+func (r *RendererImpl) reRenderSlotLocked(slotParent Component) error {
 	if slotParent == nil {
 		return fmt.Errorf("slotParent is nil")
 	}
@@ -264,13 +564,15 @@ func (r *RendererImpl) ReRenderSlot(slotParent Component) error {
 	// Its BodyContent field has been updated by the caller (router or child)
 	// CRITICAL: Push slotParent onto rendering stack to maintain consistent key generation
 	r.renderingStack = append(r.renderingStack, slotParent)
-	newParentVDOM := slotParent.Render(r)
+	newParentVDOM := RenderComponent(slotParent, r)
 	// Pop from rendering stack after Render completes
 	r.renderingStack = r.renderingStack[:len(r.renderingStack)-1]
+	r.rendered = append(r.rendered, renderedComponent{component: slotParent, key: "__slot__"})
 
 	if newParentVDOM == nil {
 		return fmt.Errorf("slotParent.Render() returned nil")
 	}
+	vdom.Normalize(newParentVDOM)
 
 	// 3. Diff the entire parent layout's VDOM and patch
 	// The layout's template includes the slot content, so changes are captured
@@ -283,12 +585,33 @@ func (r *RendererImpl) ReRenderSlot(slotParent Component) error {
 	return nil
 }
 
+// takeRendered returns the components rendered in the current pass and resets the list.
+// The caller must hold r.mu.
+func (r *RendererImpl) takeRendered() []renderedComponent {
+	rendered := r.rendered
+	r.rendered = nil
+	return rendered
+}
+
+// notifyAfterRender calls OnAfterRender on every rendered component that implements it,
+// children first. It must be called without holding r.mu so hooks can trigger re-renders.
+func (r *RendererImpl) notifyAfterRender(rendered []renderedComponent) {
+	r.afterRender.run(rendered, func(rc renderedComponent) {
+		if afterRenderer, ok := rc.component.(AfterRenderer); ok {
+			r.callOnAfterRender(afterRenderer, rc.key, rc.first)
+			// Renders the hook batched run now, so afterRender still stops a render loop
+			batcher.flush(r)
+		}
+	})
+}
+
 // reRenderFull is a helper to do a complete re-render when needed
 func (r *RendererImpl) reRenderFull(component Component) error {
-	newVDOM := component.Render(r)
+	newVDOM := RenderComponent(component, r)
 	if newVDOM == nil {
 		return fmt.Errorf("component.Render() returned nil")
 	}
+	vdom.Normalize(newVDOM)
 
 	vdom.RenderToSelector(r.mountID, newVDOM)
 	r.instanceVDOMCache[component] = newVDOM
@@ -296,6 +619,46 @@ func (r *RendererImpl) reRenderFull(component Component) error {
 	return nil
 }
 
+// Unmount removes the rendered tree from the mount element and unmounts every component,
+// calling OnUnmount on each. Used by AppInstance.Unmount; a later RenderRoot mounts afresh.
+func (r *RendererImpl) Unmount() {
+	r.mountScheduler.abort()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mountRendered = nil
+	if r.prevVDOM == nil {
+		return
+	}
+	vdom.Clear(r.mountID, r.prevVDOM)
+
+	r.destroyRoot(r.currentComponent)
+	if r.replacedRoot != nil {
+		r.destroyRoot(r.replacedRoot)
+		r.replacedRoot = nil
+	}
+
+	// No key is active, so every cached child is unmounted
+	r.activeKeys = make(map[string]bool)
+	r.cleanupUnmountedComponents()
+
+	r.prevVDOM = nil
+	r.initialized = make(map[string]bool)
+	r.instanceVDOMCache = make(map[Component]*vdom.VNode)
+}
+
+// destroyRoot unmounts a root component: it calls OnUnmount and releases what the component
+// holds. The caller must hold r.mu.
+func (r *RendererImpl) destroyRoot(root Component) {
+	if unmountable, ok := root.(Unmountable); ok {
+		r.callOnUnmount(unmountable, "__root__")
+	}
+	CancelIdle(root)
+	r.services.Release(root)
+	Destroy(root)
+}
+
 // Navigate implements the Navigator interface.
 // It delegates to the NavigationManager (router) to perform client-side navigation.
 // Returns an error if no router is configured.
@@ -0,0 +1,333 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+// DefaultSoftRenderLimit is the SoftLimit dev builds (-tags dev) start with.
+const DefaultSoftRenderLimit = 30
+
+// renderRateWindow is the sliding window render rates are measured over.
+const renderRateWindow = time.Second
+
+// RenderRateOptions configures render-rate tracking (see SetRenderRate). Tracking counts,
+// per component, the renders its StateHasChanged calls request over a sliding one-second
+// window. It is off in production builds and starts with SoftLimit DefaultSoftRenderLimit
+// in dev builds.
+type RenderRateOptions struct {
+	// SoftLimit is the number of renders per second above which a component is reported
+	// with console.Warn, at most once per second, naming its type and key, the source of
+	// the last render and a suggestion to throttle. Zero disables the warning.
+	SoftLimit int
+
+	// Throttle, when positive, caps the renders of each component at Throttle per second:
+	// StateHasChanged calls beyond the cap are coalesced into one render, run as soon as
+	// the window allows it. Zero disables the throttle.
+	Throttle int
+
+	// Clock is the time source, the system clock when nil. Tests pass a fake clock (e.g.,
+	// notify.ManualClock) to move the window and fire coalesced renders deterministically.
+	Clock Clock
+}
+
+// Clock is the time source of render-rate tracking. notify.ManualClock implements it.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// AfterFunc calls f once d has elapsed. The returned function cancels the call and
+	// reports whether it did so before f ran.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// ComponentRenderStats is the render-rate record of one component, as RenderStats
+// returns it.
+type ComponentRenderStats struct {
+	Component   string // Go type of the component (e.g., "*components.Cursor")
+	Key         string // Key the renderer knows the instance by; empty before it rendered as a child
+	Rate        int    // Renders in the last second
+	Peak        int    // Highest Rate seen
+	Total       int    // Renders since tracking started
+	Throttled   int    // StateHasChanged calls coalesced by the throttle
+	LastTrigger string // Source of the last render (see Trigger), empty when unknown
+}
+
+// SetRenderRate replaces the render-rate options. Counters are kept; with both limits at
+// zero tracking stops and the counters are dropped.
+func SetRenderRate(opts RenderRateOptions) {
+	renderRates.configure(opts)
+}
+
+// RenderStats returns the render-rate records of the tracked components, highest rate
+// first. It is empty when tracking is off.
+func RenderStats() []ComponentRenderStats {
+	return renderRates.stats(nil)
+}
+
+// TrackingRenderRate reports whether render-rate tracking is on. Instrumentation points can
+// check it to skip computing a Trigger source nobody reads.
+func TrackingRenderRate() bool {
+	return renderRates.enabled()
+}
+
+// Trigger runs fn with source recorded as the reason for the renders it requests, so that
+// render-rate warnings can name it (e.g., "handler HandleMove", "store *app.Cart"). Event
+// adapters, injected Notifiers, Ctx.SafeUpdate and MeasureThen record themselves; wrap
+// other sources, such as a ticker, to have them named too. Calls nest.
+func Trigger(source string, fn func()) {
+	if !renderRates.enabled() {
+		fn()
+		return
+	}
+	previous := renderRates.setTrigger(source)
+	defer renderRates.setTrigger(previous)
+	fn()
+}
+
+// renderRate is the record of one tracked component.
+type renderRate struct {
+	component Component // Set once the renderer has rendered it
+	key       string
+	renderer  Renderer
+	times     []time.Time // Renders within the window, oldest first
+	peak      int
+	total     int
+	throttled int
+	trigger   string
+	warnedAt  time.Time
+	pending   func() bool // Stops the coalesced render scheduled by the throttle
+}
+
+// renderRateTracker implements render-rate tracking for every renderer.
+type renderRateTracker struct {
+	mu      sync.Mutex
+	opts    RenderRateOptions
+	clock   Clock
+	trigger string // Source set by the innermost Trigger call
+	rates   map[*ComponentBase]*renderRate
+	warn    func(args ...any) // console.Warn; replaced in tests
+}
+
+// renderRates is the tracker behind StateHasChanged.
+var renderRates = &renderRateTracker{warn: console.Warn}
+
+func (t *renderRateTracker) enabled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rates != nil
+}
+
+func (t *renderRateTracker) configure(opts RenderRateOptions) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.opts = opts
+	t.clock = opts.Clock
+	if t.clock == nil {
+		t.clock = systemClock{}
+	}
+	if opts.SoftLimit <= 0 && opts.Throttle <= 0 {
+		for _, rate := range t.rates {
+			if rate.pending != nil {
+				rate.pending()
+			}
+		}
+		t.rates = nil
+	} else if t.rates == nil {
+		t.rates = make(map[*ComponentBase]*renderRate)
+	}
+}
+
+func (t *renderRateTracker) setTrigger(source string) (previous string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	previous, t.trigger = t.trigger, source
+	return previous
+}
+
+// identify names the component behind b for the warnings and stats. The renderer calls it
+// for each component it renders as a child.
+func (t *renderRateTracker) identify(c Component, key string) {
+	owner, ok := c.(interface{ base() *ComponentBase })
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rates == nil {
+		return
+	}
+	rate := t.rate(owner.base())
+	rate.component, rate.key = c, key
+}
+
+// rate returns the record of b, creating it. The caller must hold t.mu.
+func (t *renderRateTracker) rate(b *ComponentBase) *renderRate {
+	rate, ok := t.rates[b]
+	if !ok {
+		rate = &renderRate{}
+		t.rates[b] = rate
+	}
+	return rate
+}
+
+// admit records a render requested by b and reports whether it may run now. When the
+// throttle holds it back, a single coalesced render is scheduled for when the window has
+// room again.
+func (t *renderRateTracker) admit(b *ComponentBase) bool {
+	t.mu.Lock()
+	if t.rates == nil {
+		t.mu.Unlock()
+		return true
+	}
+	now := t.clock.Now()
+	rate := t.rate(b)
+	rate.renderer = b.renderer
+	rate.trigger = t.trigger
+	rate.slide(now)
+
+	if t.opts.Throttle > 0 && len(rate.times) >= t.opts.Throttle {
+		rate.throttled++
+		if rate.pending == nil {
+			wait := rate.times[len(rate.times)-t.opts.Throttle].Add(renderRateWindow).Sub(now)
+			rate.pending = t.clock.AfterFunc(wait, func() { t.flush(b) })
+		}
+		t.mu.Unlock()
+		return false
+	}
+
+	warning := t.record(rate, now)
+	t.mu.Unlock()
+
+	if warning != "" {
+		t.warn(warning)
+	}
+	return true
+}
+
+// flush runs the render coalesced by the throttle for b.
+func (t *renderRateTracker) flush(b *ComponentBase) {
+	t.mu.Lock()
+	rate, ok := t.rates[b]
+	if !ok || rate.pending == nil {
+		t.mu.Unlock()
+		return
+	}
+	rate.pending = nil
+	now := t.clock.Now()
+	rate.slide(now)
+	warning := t.record(rate, now)
+	t.mu.Unlock()
+
+	if warning != "" {
+		t.warn(warning)
+	}
+	if b.renderer != nil {
+		b.rerender()
+	}
+}
+
+// record counts a render at now and returns the warning to report, if any. The caller
+// must hold t.mu.
+func (t *renderRateTracker) record(rate *renderRate, now time.Time) string {
+	rate.times = append(rate.times, now)
+	rate.total++
+	if len(rate.times) > rate.peak {
+		rate.peak = len(rate.times)
+	}
+
+	limit := t.opts.SoftLimit
+	if limit <= 0 || len(rate.times) <= limit || (!rate.warnedAt.IsZero() && now.Sub(rate.warnedAt) < renderRateWindow) {
+		return ""
+	}
+	rate.warnedAt = now
+	trigger := rate.trigger
+	if trigger == "" {
+		trigger = "unknown source"
+	}
+	return fmt.Sprintf("[nojs] %s re-rendered %d times in the last second (limit %d), last triggered by %s. "+
+		"Throttle or debounce the source, or render only on meaningful changes.",
+		rate.name(), len(rate.times), limit, trigger)
+}
+
+// forget drops the record of b and its pending coalesced render. Destroy calls it.
+func (t *renderRateTracker) forget(b *ComponentBase) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if rate, ok := t.rates[b]; ok {
+		if rate.pending != nil {
+			rate.pending()
+		}
+		delete(t.rates, b)
+	}
+}
+
+// stats returns the records of the components of renderer r, or of every renderer when r
+// is nil, highest rate first.
+func (t *renderRateTracker) stats(r Renderer) []ComponentRenderStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rates == nil {
+		return nil
+	}
+	now := t.clock.Now()
+	var stats []ComponentRenderStats
+	for _, rate := range t.rates {
+		if r != nil && rate.renderer != r {
+			continue
+		}
+		rate.slide(now)
+		stats = append(stats, ComponentRenderStats{
+			Component:   rate.typeName(),
+			Key:         rate.key,
+			Rate:        len(rate.times),
+			Peak:        rate.peak,
+			Total:       rate.total,
+			Throttled:   rate.throttled,
+			LastTrigger: rate.trigger,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Rate != stats[j].Rate {
+			return stats[i].Rate > stats[j].Rate
+		}
+		return stats[i].Total > stats[j].Total
+	})
+	return stats
+}
+
+// slide drops the renders that left the window ending at now.
+func (r *renderRate) slide(now time.Time) {
+	start := now.Add(-renderRateWindow)
+	i := 0
+	for i < len(r.times) && !r.times[i].After(start) {
+		i++
+	}
+	r.times = append(r.times[:0], r.times[i:]...)
+}
+
+func (r *renderRate) typeName() string {
+	if r.component == nil {
+		return "component"
+	}
+	return fmt.Sprintf("%T", r.component)
+}
+
+// name describes the component in warnings.
+func (r *renderRate) name() string {
+	if r.key == "" {
+		return r.typeName()
+	}
+	return fmt.Sprintf("%s (key %q)", r.typeName(), r.key)
+}
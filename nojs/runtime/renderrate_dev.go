@@ -0,0 +1,11 @@
+//go:build (js || wasm) && dev
+// +build js wasm
+// +build dev
+
+package runtime
+
+// Dev builds report components that re-render more often than DefaultSoftRenderLimit times
+// per second; production builds do not track render rates unless SetRenderRate is called.
+func init() {
+	SetRenderRate(RenderRateOptions{SoftLimit: DefaultSoftRenderLimit})
+}
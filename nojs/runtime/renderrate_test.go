@@ -0,0 +1,160 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/notify"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// rateTestComponent re-renders on every StateHasChanged through a counting renderer.
+type rateTestComponent struct {
+	ComponentBase
+}
+
+func (c *rateTestComponent) Render(r Renderer) *vdom.VNode { return vdom.Div(nil) }
+
+// trackRenderRate enables tracking with opts on a manual clock and captures the warnings.
+// Tracking is switched off again when the test ends.
+func trackRenderRate(t *testing.T, opts RenderRateOptions) (*notify.ManualClock, *[]string) {
+	t.Helper()
+	clock := notify.NewManualClock(time.Unix(0, 0))
+	opts.Clock = clock
+	var warnings []string
+	warn := renderRates.warn
+	renderRates.warn = func(args ...any) { warnings = append(warnings, args[0].(string)) }
+	SetRenderRate(opts)
+	t.Cleanup(func() {
+		SetRenderRate(RenderRateOptions{})
+		renderRates.warn = warn
+	})
+	return clock, &warnings
+}
+
+// newRateComponent returns a component rendered by its own counting renderer, identified
+// under key.
+func newRateComponent(key string) (*rateTestComponent, *appTestRenderer) {
+	renderer := &appTestRenderer{}
+	c := &rateTestComponent{}
+	c.SetRenderer(renderer)
+	renderRates.identify(c, key)
+	return c, renderer
+}
+
+// TestRenderRate_SlidingWindow verifies renders are counted over the last second only and
+// that a component over the soft limit is reported once per second, with its trigger.
+func TestRenderRate_SlidingWindow(t *testing.T) {
+	// Arrange
+	clock, warnings := trackRenderRate(t, RenderRateOptions{SoftLimit: 3})
+	c, renderer := newRateComponent("cursor")
+	render := func() { Trigger("handler (*Canvas).HandleMove", c.StateHasChanged) }
+
+	// Act
+	for i := 0; i < 3; i++ {
+		render()
+		clock.Advance(300 * time.Millisecond)
+	}
+	atLimit := len(*warnings)
+	render() // Fourth render within one second
+	render()
+	clock.Advance(time.Second)
+	render()
+	stats := RenderStats()
+
+	// Assert
+	if atLimit != 0 {
+		t.Fatalf("expected no warning at the limit, got %v", *warnings)
+	}
+	if len(*warnings) != 1 {
+		t.Fatalf("expected one warning for the burst, got %v", *warnings)
+	}
+	for _, want := range []string{`*runtime.rateTestComponent (key "cursor")`, "4 times", "limit 3", "handler (*Canvas).HandleMove"} {
+		if !strings.Contains((*warnings)[0], want) {
+			t.Errorf("expected the warning to contain %q, got %q", want, (*warnings)[0])
+		}
+	}
+	if renderer.renders != 6 {
+		t.Errorf("expected every render to run without a throttle, got %d", renderer.renders)
+	}
+	if len(stats) != 1 || stats[0].Rate != 1 || stats[0].Peak != 5 || stats[0].Total != 6 || stats[0].Key != "cursor" {
+		t.Errorf("expected rate 1, peak 5 and total 6 for cursor, got %+v", stats)
+	}
+}
+
+// TestRenderRate_ThrottleCoalesces verifies the throttle runs at most N renders per second
+// per component and coalesces the requests beyond it into one render once the window allows.
+func TestRenderRate_ThrottleCoalesces(t *testing.T) {
+	// Arrange
+	clock, _ := trackRenderRate(t, RenderRateOptions{Throttle: 2})
+	noisy, noisyRenderer := newRateComponent("noisy")
+	calm, calmRenderer := newRateComponent("calm")
+
+	// Act
+	for i := 0; i < 10; i++ {
+		noisy.StateHasChanged()
+		clock.Advance(10 * time.Millisecond)
+	}
+	calm.StateHasChanged()
+	rendersInWindow := noisyRenderer.renders
+	clock.Advance(time.Second)
+
+	// Assert
+	if rendersInWindow != 2 {
+		t.Fatalf("expected 2 renders within the window, got %d", rendersInWindow)
+	}
+	if noisyRenderer.renders != 3 {
+		t.Errorf("expected the held requests to coalesce into one render, got %d renders", noisyRenderer.renders)
+	}
+	if calmRenderer.renders != 1 {
+		t.Errorf("expected the other component to render unthrottled, got %d renders", calmRenderer.renders)
+	}
+	if stats := RenderStats(); len(stats) != 2 || stats[0].Key != "noisy" || stats[0].Throttled != 8 {
+		t.Errorf("expected 8 throttled requests for noisy, got %+v", stats)
+	}
+	if clock.Pending() != 0 {
+		t.Errorf("expected no coalesced render left, got %d", clock.Pending())
+	}
+}
+
+// TestRenderRate_DestroyDropsCoalescedRender verifies a component destroyed while a render is
+// held back never renders again and leaves the stats.
+func TestRenderRate_DestroyDropsCoalescedRender(t *testing.T) {
+	// Arrange
+	clock, _ := trackRenderRate(t, RenderRateOptions{Throttle: 1})
+	c, renderer := newRateComponent("gone")
+	c.StateHasChanged()
+	c.StateHasChanged()
+
+	// Act
+	Destroy(c)
+	clock.Advance(time.Second)
+
+	// Assert
+	if renderer.renders != 1 || clock.Pending() != 0 {
+		t.Errorf("expected the held render to be dropped, got %d renders and %d pending", renderer.renders, clock.Pending())
+	}
+	if stats := RenderStats(); len(stats) != 0 {
+		t.Errorf("expected no stats for a destroyed component, got %+v", stats)
+	}
+}
+
+// TestRenderRate_OffByDefault verifies nothing is tracked unless a limit is set.
+func TestRenderRate_OffByDefault(t *testing.T) {
+	// Arrange
+	c, renderer := newRateComponent("idle")
+
+	// Act
+	for i := 0; i < 100; i++ {
+		c.StateHasChanged()
+	}
+
+	// Assert
+	if renderer.renders != 100 || TrackingRenderRate() || len(RenderStats()) != 0 {
+		t.Errorf("expected untracked renders, got %d renders and tracking=%v", renderer.renders, TrackingRenderRate())
+	}
+}
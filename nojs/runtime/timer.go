@@ -0,0 +1,102 @@
+package runtime
+
+import (
+	"sync"
+	"time"
+)
+
+// timerMu guards the state of every component timer.
+var timerMu sync.Mutex
+
+// componentTimer is one SetTimeout or SetInterval registration.
+type componentTimer struct {
+	owner         Component
+	fn            func()
+	source        string        // Trigger source of the renders it requests
+	every         time.Duration // Period of an interval; zero for a timeout
+	stop          func() bool   // Stops the pending tick; guarded by timerMu
+	stopOnDestroy func() bool
+	done          bool // Cancelled, or a timeout that fired; guarded by timerMu
+}
+
+// SetTimeout runs fn once, after d, on the main event loop, then re-renders c. The timer is
+// cancelled when c is destroyed, such as when a navigation discards it; cancel stops it
+// earlier. In non-WASM builds timers run on the clock set with UseTimerClock.
+//
+//	func (c *Toast) OnMount() {
+//	    runtime.SetTimeout(c, 3*time.Second, func() { c.Hidden = true })
+//	}
+func SetTimeout(c Component, d time.Duration, fn func()) (cancel func()) {
+	return startTimer(c, d, 0, fn, "SetTimeout")
+}
+
+// SetInterval runs fn every d, on the main event loop, re-rendering c after each run. The
+// interval is cancelled when c is destroyed, such as when a navigation discards it; cancel
+// stops it earlier. Each run is scheduled after the previous one, so runs never overlap.
+//
+//	func (c *Clock) OnMount() {
+//	    runtime.SetInterval(c, time.Second, func() { c.Now = time.Now() })
+//	}
+func SetInterval(c Component, d time.Duration, fn func()) (cancel func()) {
+	return startTimer(c, d, d, fn, "SetInterval")
+}
+
+func startTimer(c Component, d, every time.Duration, fn func(), source string) func() {
+	t := &componentTimer{owner: c, fn: fn, source: source, every: every}
+	t.stopOnDestroy = AfterDestroy(c, t.cancel)
+	t.schedule(d)
+	return t.cancel
+}
+
+// schedule arranges for the next tick after d, unless the timer is done.
+func (t *componentTimer) schedule(d time.Duration) {
+	timerMu.Lock()
+	defer timerMu.Unlock()
+	if !t.done {
+		t.stop = timerClock().AfterFunc(d, t.fire)
+	}
+}
+
+// fire runs a tick: fn, then a render of the owner, then the next tick of an interval.
+func (t *componentTimer) fire() {
+	timerMu.Lock()
+	if t.done {
+		timerMu.Unlock()
+		return
+	}
+	t.done = t.every == 0
+	timerMu.Unlock()
+
+	Trigger(t.source, func() {
+		t.fn()
+		if owner, ok := t.owner.(interface{ GetRenderer() Renderer }); ok && owner.GetRenderer() == nil {
+			return // Not mounted: its first render shows the change
+		}
+		if changer, ok := t.owner.(interface{ StateHasChanged() }); ok {
+			changer.StateHasChanged()
+		}
+	})
+
+	if t.every > 0 {
+		t.schedule(t.every)
+	} else {
+		t.stopOnDestroy()
+	}
+}
+
+// cancel stops the timer. Calling it again, or after a timeout fired, does nothing.
+func (t *componentTimer) cancel() {
+	timerMu.Lock()
+	if t.done {
+		timerMu.Unlock()
+		return
+	}
+	t.done = true
+	stop := t.stop
+	timerMu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+	t.stopOnDestroy()
+}
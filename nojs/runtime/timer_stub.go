@@ -0,0 +1,37 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import "sync"
+
+var (
+	timerClockMu  sync.Mutex
+	timerClockNow Clock = systemClock{}
+)
+
+// timerClock returns the clock SetTimeout and SetInterval schedule through.
+func timerClock() Clock {
+	timerClockMu.Lock()
+	defer timerClockMu.Unlock()
+	return timerClockNow
+}
+
+// UseTimerClock makes SetTimeout and SetInterval schedule through clock, such as a
+// notify.ManualClock whose Advance runs the timers that fall due, until restore is called.
+// Timers started before keep their clock. It exists in non-WASM builds only, for tests.
+//
+//	clock := notify.NewManualClock(time.Unix(0, 0))
+//	t.Cleanup(runtime.UseTimerClock(clock))
+//	clock.Advance(time.Second) // Runs the timers due, then their renders are batched
+func UseTimerClock(clock Clock) (restore func()) {
+	timerClockMu.Lock()
+	previous := timerClockNow
+	timerClockNow = clock
+	timerClockMu.Unlock()
+	return func() {
+		timerClockMu.Lock()
+		timerClockNow = previous
+		timerClockMu.Unlock()
+	}
+}
@@ -0,0 +1,83 @@
+//go:build !wasm
+// +build !wasm
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/notify"
+)
+
+// useManualTimers makes timers run on a manual clock until the test ends.
+func useManualTimers(t *testing.T) *notify.ManualClock {
+	clock := notify.NewManualClock(time.Unix(0, 0))
+	t.Cleanup(UseTimerClock(clock))
+	return clock
+}
+
+// TestSetInterval_RunsEachPeriodAndReRenders verifies an interval runs once per elapsed
+// period, re-rendering its component after each run, until cancelled.
+func TestSetInterval_RunsEachPeriodAndReRenders(t *testing.T) {
+	// Arrange
+	clock := useManualTimers(t)
+	c, renderer := mountedListener()
+	t.Cleanup(func() { Destroy(c) })
+	ticks := 0
+	rendersInTick := -1
+	cancel := SetInterval(c, time.Second, func() {
+		ticks++
+		rendersInTick = renderer.renders
+	})
+
+	// Act
+	clock.Advance(999 * time.Millisecond)
+	early := ticks
+	clock.Advance(2 * time.Second)
+	cancel()
+	clock.Advance(5 * time.Second)
+
+	// Assert
+	if early != 0 {
+		t.Errorf("expected no tick before the period, got %d", early)
+	}
+	if ticks != 2 {
+		t.Errorf("expected 2 ticks before cancel, got %d", ticks)
+	}
+	if renderer.renders != 2 || rendersInTick != 1 {
+		t.Errorf("expected a render after each tick, got %d renders (%d during the last tick)", renderer.renders, rendersInTick)
+	}
+	if clock.Pending() != 0 {
+		t.Errorf("expected no pending timer after cancel, got %d", clock.Pending())
+	}
+}
+
+// TestSetTimeout_CancelledOnDestroy verifies a timeout runs once, and the timers of a
+// destroyed component never run.
+func TestSetTimeout_CancelledOnDestroy(t *testing.T) {
+	// Arrange
+	clock := useManualTimers(t)
+	kept, _ := mountedListener()
+	discarded, renderer := mountedListener()
+	t.Cleanup(func() { Destroy(kept) })
+	var keptRuns, discardedRuns int
+	SetTimeout(kept, time.Second, func() { keptRuns++ })
+	SetTimeout(discarded, time.Second, func() { discardedRuns++ })
+	SetInterval(discarded, time.Second, func() { discardedRuns++ })
+
+	// Act
+	Destroy(discarded)
+	clock.Advance(3 * time.Second)
+
+	// Assert
+	if keptRuns != 1 {
+		t.Errorf("expected the timeout to run once, ran %d times", keptRuns)
+	}
+	if discardedRuns != 0 || renderer.renders != 0 {
+		t.Errorf("expected the destroyed component's timers not to run, got %d runs", discardedRuns)
+	}
+	if clock.Pending() != 0 {
+		t.Errorf("expected no pending timer, got %d", clock.Pending())
+	}
+}
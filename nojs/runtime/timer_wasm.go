@@ -0,0 +1,39 @@
+//go:build js || wasm
+// +build js wasm
+
+package runtime
+
+import (
+	"syscall/js"
+	"time"
+)
+
+// mainLoopClock schedules through the browser's setTimeout, so callbacks run on the main
+// event loop like event handlers.
+type mainLoopClock struct{}
+
+// timerClock returns the clock SetTimeout and SetInterval schedule through.
+func timerClock() Clock { return mainLoopClock{} }
+
+func (mainLoopClock) Now() time.Time { return time.Now() }
+
+func (mainLoopClock) AfterFunc(d time.Duration, f func()) func() bool {
+	pending := true
+	var cb js.Func
+	cb = js.FuncOf(func(this js.Value, args []js.Value) any {
+		pending = false
+		cb.Release()
+		f()
+		return nil
+	})
+	id := js.Global().Call("setTimeout", cb, d.Milliseconds())
+	return func() bool {
+		if !pending {
+			return false
+		}
+		pending = false
+		js.Global().Call("clearTimeout", id)
+		cb.Release()
+		return true
+	}
+}
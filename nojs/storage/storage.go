@@ -0,0 +1,158 @@
+// Package storage reads and writes the browser's Web Storage: Local keeps values across
+// reloads and browser restarts, Session until the tab is closed. In non-WASM builds both are
+// backed by in-memory maps, so components that use them can be tested with the TestRenderer.
+//
+// Storage can fail: the user may have turned it off, and writes fail once the origin's quota
+// is used up. Such failures are reported with console.Warn and otherwise ignored, so a value
+// that cannot be saved is simply not there after a reload.
+//
+//	type Sidebar struct {
+//	    runtime.ComponentBase
+//	    Collapsed bool `nojs:"state"`
+//	}
+//
+//	func (c *Sidebar) OnMount() {
+//	    storage.Persist(c, &c.Collapsed, "sidebar.collapsed")
+//	}
+//
+//	func (c *Sidebar) Toggle() {
+//	    c.Collapsed = !c.Collapsed
+//	    c.StateHasChanged() // Saved when the render of the change starts
+//	}
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ForgeLogic/nojs/console"
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Errors reported, wrapped with the browser's message, when storage fails.
+var (
+	ErrQuotaExceeded = errors.New("storage: quota exceeded")
+	ErrUnavailable   = errors.New("storage: not available")
+)
+
+// warn reports storage failures; tests replace it to observe them.
+var warn = console.Warn
+
+// Store is one of the browser's storage areas.
+type Store struct {
+	name    string // "localStorage" or "sessionStorage", as used in warnings
+	backend backend
+}
+
+// backend is the storage area behind a Store: window.localStorage or window.sessionStorage
+// in WASM builds, an in-memory map otherwise.
+type backend interface {
+	get(key string) (string, bool, error)
+	set(key, value string) error
+	remove(key string) error
+	clear() error
+}
+
+var (
+	// Local is window.localStorage: values persist across reloads and browser restarts.
+	Local = &Store{name: "localStorage", backend: newBackend("localStorage")}
+	// Session is window.sessionStorage: values persist across reloads of the tab.
+	Session = &Store{name: "sessionStorage", backend: newBackend("sessionStorage")}
+)
+
+// Get returns the value stored under key, and whether there is one.
+func (s *Store) Get(key string) (string, bool) {
+	value, ok, err := s.backend.get(key)
+	if err != nil {
+		s.warnf("reading %q: %v", key, err)
+		return "", false
+	}
+	return value, ok
+}
+
+// Set stores value under key.
+func (s *Store) Set(key, value string) {
+	if err := s.backend.set(key, value); err != nil {
+		s.warnf("writing %q: %v", key, err)
+	}
+}
+
+// Remove deletes the value stored under key, if any.
+func (s *Store) Remove(key string) {
+	if err := s.backend.remove(key); err != nil {
+		s.warnf("removing %q: %v", key, err)
+	}
+}
+
+// Clear deletes every value of the storage area, including those written by other code of
+// the same origin.
+func (s *Store) Clear() {
+	if err := s.backend.clear(); err != nil {
+		s.warnf("clearing: %v", err)
+	}
+}
+
+// warnf reports a failure of s.
+func (s *Store) warnf(format string, args ...any) {
+	warn(fmt.Sprintf("storage: %s: "+format, append([]any{s.name}, args...)...))
+}
+
+// GetJSON decodes the JSON value stored under key. It reports false when there is none or
+// it does not decode into a T, which it warns about.
+func GetJSON[T any](s *Store, key string) (T, bool) {
+	var value T
+	raw, ok := s.Get(key)
+	if !ok {
+		return value, false
+	}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		s.warnf("decoding %q: %v", key, err)
+		var zero T
+		return zero, false
+	}
+	return value, true
+}
+
+// SetJSON stores value under key, encoded as JSON.
+func SetJSON[T any](s *Store, key string, value T) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		s.warnf("encoding %q: %v", key, err)
+		return
+	}
+	s.Set(key, string(raw))
+}
+
+// Persist keeps *field in Local under key: it loads the stored value into *field at once,
+// when there is one, and saves *field each time a state change of c is committed (see
+// runtime.AfterStateChange), when it differs from what was last loaded or saved. Call it in
+// OnMount. Saving ends when c is destroyed; stop ends it earlier.
+func Persist[T any](c runtime.Component, field *T, key string) (stop func()) {
+	var saved string
+	if raw, ok := Local.Get(key); ok {
+		var value T
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			Local.warnf("decoding %q: %v", key, err)
+		} else {
+			*field = value
+			saved = raw
+		}
+	}
+
+	return runtime.AfterStateChange(c, func() {
+		raw, err := json.Marshal(*field)
+		if err != nil {
+			Local.warnf("encoding %q: %v", key, err)
+			return
+		}
+		if string(raw) == saved {
+			return
+		}
+		if err := Local.backend.set(key, string(raw)); err != nil {
+			Local.warnf("writing %q: %v", key, err)
+			return
+		}
+		saved = string(raw)
+	})
+}
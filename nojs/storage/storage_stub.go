@@ -0,0 +1,88 @@
+//go:build !wasm
+// +build !wasm
+
+package storage
+
+import "sync"
+
+// MemoryQuota is the size, in bytes of keys and values, of each storage area in non-WASM
+// builds, about what browsers allow an origin. Writes beyond it fail with ErrQuotaExceeded.
+const MemoryQuota = 5 << 20
+
+// memoryBackend is a storage area held in memory, for non-WASM builds.
+type memoryBackend struct {
+	mu       sync.Mutex
+	values   map[string]string
+	size     int
+	disabled bool
+}
+
+func newBackend(name string) backend {
+	return &memoryBackend{values: make(map[string]string)}
+}
+
+func (b *memoryBackend) get(key string) (string, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.disabled {
+		return "", false, ErrUnavailable
+	}
+	value, ok := b.values[key]
+	return value, ok, nil
+}
+
+func (b *memoryBackend) set(key, value string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.disabled {
+		return ErrUnavailable
+	}
+	size := b.size + len(key) + len(value)
+	if old, ok := b.values[key]; ok {
+		size -= len(key) + len(old)
+	}
+	if size > MemoryQuota {
+		return ErrQuotaExceeded
+	}
+	b.values[key] = value
+	b.size = size
+	return nil
+}
+
+func (b *memoryBackend) remove(key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.disabled {
+		return ErrUnavailable
+	}
+	if old, ok := b.values[key]; ok {
+		b.size -= len(key) + len(old)
+		delete(b.values, key)
+	}
+	return nil
+}
+
+func (b *memoryBackend) clear() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.disabled {
+		return ErrUnavailable
+	}
+	b.values = make(map[string]string)
+	b.size = 0
+	return nil
+}
+
+// Disable makes s fail with ErrUnavailable, as a browser with storage turned off does, until
+// the returned function is called. It exists in non-WASM builds only, for tests.
+func (s *Store) Disable() (enable func()) {
+	b := s.backend.(*memoryBackend)
+	b.mu.Lock()
+	b.disabled = true
+	b.mu.Unlock()
+	return func() {
+		b.mu.Lock()
+		b.disabled = false
+		b.mu.Unlock()
+	}
+}
@@ -0,0 +1,195 @@
+//go:build !wasm
+// +build !wasm
+
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// captureWarnings clears both storage areas and records the warnings of the test.
+func captureWarnings(t *testing.T) *[]string {
+	t.Helper()
+	var warnings []string
+	previous := warn
+	warn = func(args ...any) {
+		warnings = append(warnings, args[0].(string))
+	}
+	t.Cleanup(func() {
+		warn = previous
+		Local.Clear()
+		Session.Clear()
+	})
+	return &warnings
+}
+
+// preferences is a component persisting a theme and a collapsed flag.
+type preferences struct {
+	runtime.ComponentBase
+	Theme     string
+	Collapsed bool
+}
+
+func (c *preferences) OnMount() {
+	Persist(c, &c.Theme, "prefs.theme")
+	Persist(c, &c.Collapsed, "prefs.collapsed")
+}
+
+func (c *preferences) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("div", nil, nil, c.Theme)
+}
+
+// mountPreferences renders a preferences component, as the renderer mounts it.
+func mountPreferences(t *testing.T) (*preferences, *rendertest.TestRenderer) {
+	c := &preferences{Theme: "light"}
+	renderer := rendertest.NewTestRenderer(c)
+	c.OnMount()
+	renderer.RenderRoot()
+	t.Cleanup(func() { runtime.Destroy(c) })
+	return c, renderer
+}
+
+// TestStore_GetSetRemove verifies values round-trip per storage area, and Remove and Clear
+// delete them.
+func TestStore_GetSetRemove(t *testing.T) {
+	// Arrange
+	warnings := captureWarnings(t)
+	Local.Set("a", "1")
+	Local.Set("b", "2")
+	Session.Set("a", "session")
+
+	// Act
+	Local.Remove("a")
+	_, hasA := Local.Get("a")
+	b, hasB := Local.Get("b")
+	sessionA, _ := Session.Get("a")
+	Session.Clear()
+	_, sessionHasA := Session.Get("a")
+
+	// Assert
+	if hasA || !hasB || b != "2" {
+		t.Errorf("expected only b=2 left in Local, got a=%v b=%q", hasA, b)
+	}
+	if sessionA != "session" || sessionHasA {
+		t.Errorf("expected Session to hold its own value until cleared, got %q", sessionA)
+	}
+	if len(*warnings) != 0 {
+		t.Errorf("expected no warning, got %v", *warnings)
+	}
+}
+
+// TestGetJSON_RoundTripsAndWarnsOnBadValue verifies typed values round-trip and a value that
+// does not decode reads as missing, with a warning.
+func TestGetJSON_RoundTripsAndWarnsOnBadValue(t *testing.T) {
+	// Arrange
+	warnings := captureWarnings(t)
+	type draft struct {
+		Title string
+		Tags  []string
+	}
+	SetJSON(Session, "draft", draft{Title: "Hello", Tags: []string{"go"}})
+	Session.Set("broken", "{not json")
+
+	// Act
+	got, ok := GetJSON[draft](Session, "draft")
+	_, brokenOK := GetJSON[draft](Session, "broken")
+
+	// Assert
+	if !ok || got.Title != "Hello" || len(got.Tags) != 1 {
+		t.Errorf("expected the draft back, got %+v (%v)", got, ok)
+	}
+	if brokenOK || len(*warnings) != 1 || !strings.Contains((*warnings)[0], `"broken"`) {
+		t.Errorf("expected the broken value to read as missing with a warning, got %v", *warnings)
+	}
+}
+
+// TestStore_FailuresWarnInsteadOfPanicking verifies quota and disabled storage errors are
+// reported as warnings and leave the stored values as they were.
+func TestStore_FailuresWarnInsteadOfPanicking(t *testing.T) {
+	// Arrange
+	warnings := captureWarnings(t)
+	Local.Set("kept", "yes")
+
+	// Act
+	Local.Set("huge", strings.Repeat("x", MemoryQuota))
+	enable := Local.Disable()
+	Local.Set("kept", "no")
+	_, readWhileDisabled := Local.Get("kept")
+	enable()
+	kept, _ := Local.Get("kept")
+	_, hasHuge := Local.Get("huge")
+
+	// Assert
+	if hasHuge || kept != "yes" || readWhileDisabled {
+		t.Errorf("expected failed writes to change nothing, got kept=%q huge=%v", kept, hasHuge)
+	}
+	if len(*warnings) != 3 {
+		t.Fatalf("expected 3 warnings, got %v", *warnings)
+	}
+	if !strings.Contains((*warnings)[0], ErrQuotaExceeded.Error()) || !strings.Contains((*warnings)[1], ErrUnavailable.Error()) {
+		t.Errorf("expected a quota and an unavailable warning, got %v", *warnings)
+	}
+}
+
+// TestPersist_LoadsAndSavesOnCommit verifies Persist loads stored values on registration and
+// saves changed fields when the render of a state change starts, not before.
+func TestPersist_LoadsAndSavesOnCommit(t *testing.T) {
+	// Arrange
+	captureWarnings(t)
+	SetJSON(Local, "prefs.theme", "dark")
+	c, renderer := mountPreferences(t)
+
+	// Act
+	c.Collapsed = true
+	c.StateHasChanged()
+	_, savedBeforeRender := Local.Get("prefs.collapsed")
+	root := renderer.GetCurrentVDOM()
+	collapsed, _ := GetJSON[bool](Local, "prefs.collapsed")
+
+	// Assert
+	if c.Theme != "dark" || root.Content != "dark" {
+		t.Errorf("expected the stored theme to be loaded before the first render, got %q", root.Content)
+	}
+	if savedBeforeRender {
+		t.Error("expected nothing saved before the batched render")
+	}
+	if !collapsed {
+		t.Error("expected the collapsed flag to be saved")
+	}
+}
+
+// TestPersist_StopsOnDestroy verifies a destroyed component no longer saves, and a write
+// that fails is retried by the next commit.
+func TestPersist_StopsOnDestroy(t *testing.T) {
+	// Arrange
+	warnings := captureWarnings(t)
+	c, _ := mountPreferences(t)
+	enable := Local.Disable()
+	c.Theme = "blue"
+	c.StateHasChangedSync()
+	enable()
+
+	// Act
+	c.StateHasChangedSync()
+	retried, _ := GetJSON[string](Local, "prefs.theme")
+	runtime.Destroy(c)
+	c.Theme = "red"
+	c.StateHasChangedSync()
+	afterDestroy, _ := GetJSON[string](Local, "prefs.theme")
+
+	// Assert
+	if retried != "blue" {
+		t.Errorf("expected the failed write to be retried, got %q", retried)
+	}
+	if afterDestroy != "blue" {
+		t.Errorf("expected no save after destroy, got %q", afterDestroy)
+	}
+	if len(*warnings) != 2 {
+		t.Errorf("expected a warning per field written while disabled, got %v", *warnings)
+	}
+}
@@ -0,0 +1,72 @@
+//go:build js || wasm
+// +build js wasm
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+)
+
+// browserBackend is window.localStorage or window.sessionStorage. Every access goes through
+// call, since the browser throws when storage is turned off or the quota is used up.
+type browserBackend struct {
+	name string
+}
+
+func newBackend(name string) backend {
+	return browserBackend{name: name}
+}
+
+func (b browserBackend) get(key string) (string, bool, error) {
+	v, err := b.call("getItem", key)
+	if err != nil || v.IsNull() {
+		return "", false, err
+	}
+	return v.String(), true, nil
+}
+
+func (b browserBackend) set(key, value string) error {
+	_, err := b.call("setItem", key, value)
+	return err
+}
+
+func (b browserBackend) remove(key string) error {
+	_, err := b.call("removeItem", key)
+	return err
+}
+
+func (b browserBackend) clear() error {
+	_, err := b.call("clear")
+	return err
+}
+
+// call calls method on the storage area, turning the exceptions the browser throws into
+// errors. Reading window.localStorage itself throws when storage is turned off.
+func (b browserBackend) call(method string, args ...any) (result js.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errorFromJS(r)
+		}
+	}()
+	area := js.Global().Get(b.name)
+	if area.IsUndefined() || area.IsNull() {
+		return js.Value{}, ErrUnavailable
+	}
+	return area.Call(method, args...), nil
+}
+
+// errorFromJS converts a value thrown by the storage API into ErrQuotaExceeded or
+// ErrUnavailable, wrapped with the browser's message.
+func errorFromJS(r any) error {
+	var jsErr js.Error
+	if err, ok := r.(error); !ok || !errors.As(err, &jsErr) {
+		return fmt.Errorf("%w: %v", ErrUnavailable, r)
+	}
+	switch jsErr.Value.Get("name").String() {
+	case "QuotaExceededError", "NS_ERROR_DOM_QUOTA_REACHED":
+		return fmt.Errorf("%w: %s", ErrQuotaExceeded, jsErr.Error())
+	}
+	return fmt.Errorf("%w: %s", ErrUnavailable, jsErr.Error())
+}
@@ -0,0 +1,149 @@
+// Package store holds application state shared by components that are not related in the
+// tree: a cart, the signed-in user, a feature toggle. A Store is created once, usually as a
+// package variable, and components bind to it so that every change re-renders them.
+//
+//	var Cart = store.New(CartState{})
+//
+//	type CartBadge struct {
+//	    runtime.ComponentBase
+//	}
+//
+//	func (c *CartBadge) OnMount() {
+//	    store.Bind(c, Cart)
+//	}
+//
+//	func (c *CartBadge) Count() int { return len(Cart.Get().Items) }
+//
+//	func (c *ProductPage) AddToCart(item Item) {
+//	    Cart.Update(func(s CartState) CartState {
+//	        s.Items = append(slices.Clip(s.Items), item)
+//	        return s
+//	    })
+//	}
+//
+// A Store may be read and written from any goroutine. Subscribers run on the goroutine that
+// made the change, after it is visible to Get, and get the value current when they are
+// called: when two writers race, the last call a subscriber gets holds the final value.
+package store
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// Store holds a value of type T and notifies subscribers when it is replaced.
+type Store[T any] struct {
+	writeMu sync.Mutex // Serializes Set and Update, so an Update never loses a concurrent write
+
+	mu    sync.Mutex
+	value T
+	subs  []*subscriber[T]
+}
+
+// subscriber is one Subscribe registration.
+type subscriber[T any] struct {
+	fn      func(T)
+	removed bool // Guarded by Store.mu
+}
+
+// New returns a Store holding initial.
+func New[T any](initial T) *Store[T] {
+	return &Store[T]{value: initial}
+}
+
+// Get returns the current value.
+func (s *Store[T]) Get() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.value
+}
+
+// Set replaces the value and notifies the subscribers.
+func (s *Store[T]) Set(value T) {
+	s.writeMu.Lock()
+	s.replace(value)
+	s.writeMu.Unlock()
+	s.notify()
+}
+
+// Update replaces the value with fn applied to the current one, as a single step with
+// respect to other writers, and notifies the subscribers. fn may call Get but must not
+// call Set or Update on the same Store.
+func (s *Store[T]) Update(fn func(T) T) {
+	s.writeMu.Lock()
+	value := fn(s.Get())
+	s.replace(value)
+	s.writeMu.Unlock()
+	s.notify()
+}
+
+// Subscribe registers fn to run with the current value after every change. The returned
+// function removes it; calling it again does nothing.
+func (s *Store[T]) Subscribe(fn func(T)) (unsubscribe func()) {
+	sub := &subscriber[T]{fn: fn}
+	s.mu.Lock()
+	s.subs = append(s.subs, sub)
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if sub.removed {
+			return
+		}
+		sub.removed = true
+		for i, other := range s.subs {
+			if other == sub {
+				s.subs = append(s.subs[:i:i], s.subs[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// replace stores value. The caller holds writeMu.
+func (s *Store[T]) replace(value T) {
+	s.mu.Lock()
+	s.value = value
+	s.mu.Unlock()
+}
+
+// notify calls the subscribers registered when it starts, skipping those removed meanwhile,
+// so that a subscriber may unsubscribe itself or others. Each gets the value current when it
+// is called rather than the one written: notifications of concurrent writers may run in any
+// order, and a stale value must not be the last one a subscriber sees.
+func (s *Store[T]) notify() {
+	s.mu.Lock()
+	subs := s.subs
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		s.mu.Lock()
+		removed, value := sub.removed, s.value
+		s.mu.Unlock()
+		if !removed {
+			sub.fn(value)
+		}
+	}
+}
+
+// Bind subscribes c to s: every change re-renders c. The subscription ends when c is
+// destroyed, such as when a navigation discards it; unbind ends it earlier. Bind in OnMount.
+func Bind[T any](c runtime.Component, s *Store[T]) (unbind func()) {
+	source := "store " + reflect.TypeFor[T]().String()
+	unsubscribe := s.Subscribe(func(T) {
+		if owner, ok := c.(interface{ GetRenderer() runtime.Renderer }); ok && owner.GetRenderer() == nil {
+			return // Not mounted yet: its first render reads the new value
+		}
+		if changer, ok := c.(interface{ StateHasChanged() }); ok {
+			runtime.Trigger(source, changer.StateHasChanged)
+		}
+	})
+	stopOnDestroy := runtime.AfterDestroy(c, unsubscribe)
+	return func() {
+		stopOnDestroy()
+		unsubscribe()
+	}
+}
@@ -0,0 +1,195 @@
+//go:build !wasm
+// +build !wasm
+
+package store
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// counterBadge renders the value of a Store[int] it binds to on mount.
+type counterBadge struct {
+	runtime.ComponentBase
+	counter *Store[int]
+	unbind  func()
+	renders int
+}
+
+func (c *counterBadge) OnMount() {
+	c.unbind = Bind(c, c.counter)
+}
+
+func (c *counterBadge) Render(r runtime.Renderer) *vdom.VNode {
+	c.renders++
+	return vdom.NewVNode("span", nil, nil, strconv.Itoa(c.counter.Get()))
+}
+
+// mountBadge renders a counterBadge bound to counter, as the renderer mounts it.
+func mountBadge(counter *Store[int]) (*counterBadge, *rendertest.TestRenderer) {
+	badge := &counterBadge{counter: counter}
+	renderer := rendertest.NewTestRenderer(badge)
+	badge.OnMount()
+	renderer.RenderRoot()
+	return badge, renderer
+}
+
+// TestStore_SetAndUpdateNotifySubscribers verifies subscribers receive each new value, in
+// subscription order, and Update sees the current value.
+func TestStore_SetAndUpdateNotifySubscribers(t *testing.T) {
+	// Arrange
+	s := New(1)
+	var got []string
+	s.Subscribe(func(v int) { got = append(got, "a"+strconv.Itoa(v)) })
+	s.Subscribe(func(v int) { got = append(got, "b"+strconv.Itoa(v)) })
+
+	// Act
+	s.Set(5)
+	s.Update(func(v int) int { return v * 2 })
+
+	// Assert
+	if s.Get() != 10 {
+		t.Errorf("expected 10, got %d", s.Get())
+	}
+	want := []string{"a5", "b5", "a10", "b10"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// TestStore_UnsubscribeDuringNotify verifies a subscriber removed by an earlier one in the
+// same change is skipped, and unsubscribing twice does nothing.
+func TestStore_UnsubscribeDuringNotify(t *testing.T) {
+	// Arrange
+	s := New("")
+	var secondCalls int
+	var unsubscribeSecond func()
+	s.Subscribe(func(string) { unsubscribeSecond() })
+	unsubscribeSecond = s.Subscribe(func(string) { secondCalls++ })
+
+	// Act
+	s.Set("x")
+	unsubscribeSecond()
+	s.Set("y")
+
+	// Assert
+	if secondCalls != 0 {
+		t.Errorf("expected the removed subscriber not to run, ran %d times", secondCalls)
+	}
+}
+
+// TestStore_ConcurrentWriters verifies concurrent Updates lose no write and every change
+// reaches the subscriber. Run with -race.
+func TestStore_ConcurrentWriters(t *testing.T) {
+	// Arrange
+	s := New(0)
+	var notified atomic.Int64
+	s.Subscribe(func(int) { notified.Add(1) })
+	const writers, writes = 8, 100
+
+	// Act
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range writes {
+				s.Update(func(v int) int { return v + 1 })
+				_ = s.Get()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	if got := s.Get(); got != writers*writes {
+		t.Errorf("expected %d, got %d", writers*writes, got)
+	}
+	if got := notified.Load(); got != writers*writes {
+		t.Errorf("expected %d notifications, got %d", writers*writes, got)
+	}
+}
+
+// TestStore_OverlappingNotifications verifies a subscriber is left with the store's value
+// when the notifications of two writes overlap and the older one finishes last. Here the
+// second write is made by a subscriber of the first, which concurrent writers can also cause.
+func TestStore_OverlappingNotifications(t *testing.T) {
+	// Arrange
+	s := New(0)
+	s.Subscribe(func(v int) {
+		if v == 1 {
+			s.Set(2) // Notifies every subscriber before the notification of 1 goes on
+		}
+	})
+	var last int
+	s.Subscribe(func(v int) { last = v })
+
+	// Act
+	s.Set(1)
+
+	// Assert
+	if got := s.Get(); got != 2 || last != 2 {
+		t.Errorf("expected the store and its subscriber at 2, got %d and %d", got, last)
+	}
+}
+
+// TestBind_ReRendersOnChange verifies a bound component renders once per batch of changes
+// and shows the latest value.
+func TestBind_ReRendersOnChange(t *testing.T) {
+	// Arrange
+	counter := New(0)
+	badge, renderer := mountBadge(counter)
+	t.Cleanup(func() { runtime.Destroy(badge) })
+
+	// Act
+	counter.Set(1)
+	counter.Set(2)
+	root := renderer.GetCurrentVDOM()
+
+	// Assert
+	if badge.renders != 2 {
+		t.Errorf("expected the initial render and one batched re-render, got %d", badge.renders)
+	}
+	if root.Content != "2" {
+		t.Errorf("expected the latest value, got %q", root.Content)
+	}
+}
+
+// TestBind_ReleasedOnDestroy verifies a destroyed component, or one that unbound, no longer
+// re-renders on changes.
+func TestBind_ReleasedOnDestroy(t *testing.T) {
+	// Arrange
+	counter := New(0)
+	destroyed, destroyedRenderer := mountBadge(counter)
+	unbound, unboundRenderer := mountBadge(counter)
+	t.Cleanup(func() { runtime.Destroy(unbound) })
+
+	// Act
+	runtime.Destroy(destroyed)
+	unbound.unbind()
+	counter.Set(1)
+
+	// Assert
+	if n := len(destroyedRenderer.Renders()); n != 1 {
+		t.Errorf("expected the destroyed component not to re-render, got %d renders", n)
+	}
+	if n := len(unboundRenderer.Renders()); n != 1 {
+		t.Errorf("expected the unbound component not to re-render, got %d renders", n)
+	}
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	if len(counter.subs) != 0 {
+		t.Errorf("expected no subscriber left, got %d", len(counter.subs))
+	}
+}
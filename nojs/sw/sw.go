@@ -0,0 +1,62 @@
+// Package sw integrates the offline-first service worker generated by nojsc -sw: it
+// registers the worker, reports when a new build is ready, and switches the page to it.
+// In non-WASM builds the worker is a scriptable fake, so update banners can be tested with
+// the TestRenderer.
+package sw
+
+import (
+	"errors"
+
+	"github.com/ForgeLogic/nojs/signals"
+)
+
+// Errors reported by the package.
+var (
+	ErrUnsupported = errors.New("sw: service workers not supported by this browser")
+	ErrNoUpdate    = errors.New("sw: no update waiting")
+)
+
+// UpdateAvailable becomes true once a new build has been installed and waits for the page
+// to take it (see SkipWaiting). Components subscribe to it to offer a reload.
+//
+// Example:
+//
+//	func (c *UpdateBanner) OnMount() {
+//	    c.unsubscribe = sw.UpdateAvailable.Subscribe(c.StateHasChanged)
+//	}
+//
+//	func (c *UpdateBanner) Visible() bool { return sw.UpdateAvailable.Get() }
+//
+//	func (c *UpdateBanner) Update() {
+//	    if err := sw.SkipWaiting(); err != nil {
+//	        sw.Reload()
+//	    }
+//	}
+var UpdateAvailable = signals.NewSignal(false)
+
+// Register registers the service worker script at url, typically from main before the app
+// starts. It returns at once: the worker installs in the background, and a registration
+// failure is logged to the console. err is ErrUnsupported when the browser has no service
+// workers or the page is not served from a secure origin.
+func Register(url string) error {
+	return register(url)
+}
+
+// SkipWaiting activates the build announced by UpdateAvailable. The page reloads once the
+// new worker controls it, so the new build starts. err is ErrNoUpdate when no build is
+// waiting.
+func SkipWaiting() error {
+	return skipWaiting()
+}
+
+// Reload reloads the page.
+func Reload() {
+	reload()
+}
+
+// updateFound reports a waiting build through UpdateAvailable.
+func updateFound() {
+	if !UpdateAvailable.Get() {
+		UpdateAvailable.Set(true)
+	}
+}
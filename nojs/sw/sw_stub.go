@@ -0,0 +1,129 @@
+//go:build !wasm
+// +build !wasm
+
+package sw
+
+import "sync"
+
+// FakeServiceWorker scripts the service worker in non-WASM builds. Install one with
+// UseFakeServiceWorker; without one, Register reports ErrUnsupported like a browser lacking
+// service workers.
+//
+// Example:
+//
+//	fake := sw.UseFakeServiceWorker()
+//	t.Cleanup(fake.Uninstall)
+//	sw.Register("sw.js")
+//	fake.InstallUpdate() // UpdateAvailable becomes true
+type FakeServiceWorker struct {
+	mu         sync.Mutex
+	registered []string
+	waiting    bool
+	activated  int
+	reloads    int
+}
+
+var (
+	fakeMu sync.Mutex
+	fake   *FakeServiceWorker // Installed by UseFakeServiceWorker
+)
+
+// UseFakeServiceWorker installs a FakeServiceWorker, replacing any installed before, and
+// resets UpdateAvailable. Tests using it must not run in parallel.
+func UseFakeServiceWorker() *FakeServiceWorker {
+	f := &FakeServiceWorker{}
+	fakeMu.Lock()
+	fake = f
+	fakeMu.Unlock()
+	UpdateAvailable.Set(false)
+	return f
+}
+
+// Uninstall removes f if it is the installed fake, and resets UpdateAvailable.
+func (f *FakeServiceWorker) Uninstall() {
+	fakeMu.Lock()
+	installed := fake == f
+	if installed {
+		fake = nil
+	}
+	fakeMu.Unlock()
+	if installed {
+		UpdateAvailable.Set(false)
+	}
+}
+
+// InstallUpdate simulates a new build finishing its install while the page runs: the build
+// waits and UpdateAvailable becomes true. It does nothing before Register.
+func (f *FakeServiceWorker) InstallUpdate() {
+	f.mu.Lock()
+	registered := len(f.registered) > 0
+	f.waiting = f.waiting || registered
+	f.mu.Unlock()
+	if registered {
+		updateFound()
+	}
+}
+
+// Registered returns the script URLs passed to Register, in order.
+func (f *FakeServiceWorker) Registered() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.registered...)
+}
+
+// Activated returns the number of waiting builds SkipWaiting activated.
+func (f *FakeServiceWorker) Activated() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.activated
+}
+
+// Reloads returns the number of page reloads, by Reload or after SkipWaiting.
+func (f *FakeServiceWorker) Reloads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.reloads
+}
+
+// installedFake returns the installed fake, or nil.
+func installedFake() *FakeServiceWorker {
+	fakeMu.Lock()
+	defer fakeMu.Unlock()
+	return fake
+}
+
+func register(url string) error {
+	f := installedFake()
+	if f == nil {
+		return ErrUnsupported
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registered = append(f.registered, url)
+	return nil
+}
+
+// skipWaiting activates the waiting build, which takes control and reloads the page.
+func skipWaiting() error {
+	f := installedFake()
+	if f == nil {
+		return ErrNoUpdate
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.waiting {
+		return ErrNoUpdate
+	}
+	f.waiting = false
+	f.activated++
+	f.reloads++
+	return nil
+}
+
+func reload() {
+	if f := installedFake(); f != nil {
+		f.mu.Lock()
+		f.reloads++
+		f.mu.Unlock()
+	}
+}
@@ -0,0 +1,97 @@
+//go:build !wasm
+// +build !wasm
+
+package sw
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// updateBanner offers a reload while a new build waits.
+type updateBanner struct {
+	runtime.ComponentBase
+	unsubscribe func()
+}
+
+func (b *updateBanner) OnMount()   { b.unsubscribe = UpdateAvailable.Subscribe(b.StateHasChanged) }
+func (b *updateBanner) OnUnmount() { b.unsubscribe() }
+
+func (b *updateBanner) Render(r runtime.Renderer) *vdom.VNode {
+	if !UpdateAvailable.Get() {
+		return vdom.Div(nil)
+	}
+	return vdom.Div(nil, vdom.Paragraph("New version available", nil))
+}
+
+// TestSW_UpdateAvailableRendersBanner verifies a build installed after registration sets
+// UpdateAvailable and re-renders its subscribers.
+func TestSW_UpdateAvailableRendersBanner(t *testing.T) {
+	// Arrange
+	fake := UseFakeServiceWorker()
+	t.Cleanup(fake.Uninstall)
+	banner := &updateBanner{}
+	renderer := rendertest.NewTestRenderer(banner)
+	banner.OnMount()
+	t.Cleanup(banner.OnUnmount)
+	renderer.RenderRoot()
+	if err := Register("sw.js"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	// Act
+	fake.InstallUpdate()
+
+	// Assert
+	if !UpdateAvailable.Get() {
+		t.Fatal("expected UpdateAvailable to be set")
+	}
+	if got := renderer.GetCurrentVDOM(); len(got.Children) != 1 || got.Children[0].Content != "New version available" {
+		t.Errorf("expected the banner to render, got:\n%s", rendertest.FormatVNode(got))
+	}
+	if registered := fake.Registered(); len(registered) != 1 || registered[0] != "sw.js" {
+		t.Errorf("expected sw.js to be registered, got %v", registered)
+	}
+}
+
+// TestSW_SkipWaitingActivatesAndReloads verifies SkipWaiting activates the waiting build
+// once and reloads the page, and fails when nothing waits.
+func TestSW_SkipWaitingActivatesAndReloads(t *testing.T) {
+	// Arrange
+	fake := UseFakeServiceWorker()
+	t.Cleanup(fake.Uninstall)
+	Register("sw.js")
+	beforeUpdate := SkipWaiting()
+	fake.InstallUpdate()
+
+	// Act
+	err := SkipWaiting()
+	again := SkipWaiting()
+
+	// Assert
+	if !errors.Is(beforeUpdate, ErrNoUpdate) || !errors.Is(again, ErrNoUpdate) {
+		t.Errorf("expected ErrNoUpdate without a waiting build, got %v and %v", beforeUpdate, again)
+	}
+	if err != nil || fake.Activated() != 1 || fake.Reloads() != 1 {
+		t.Errorf("expected one activation and reload, got %v, %d activations, %d reloads", err, fake.Activated(), fake.Reloads())
+	}
+}
+
+// TestSW_UnsupportedWithoutWorker verifies Register reports ErrUnsupported without a service
+// worker and no update is announced.
+func TestSW_UnsupportedWithoutWorker(t *testing.T) {
+	// Act
+	err := Register("sw.js")
+
+	// Assert
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+	if UpdateAvailable.Get() {
+		t.Error("expected no update to be available")
+	}
+}
@@ -0,0 +1,100 @@
+//go:build js || wasm
+// +build js wasm
+
+package sw
+
+import (
+	"sync"
+	"syscall/js"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+var (
+	mu           sync.Mutex
+	registration js.Value // Set once the registration resolves
+	reloading    bool     // SkipWaiting was called: reload when the new worker takes control
+)
+
+// container returns navigator.serviceWorker, or ErrUnsupported when it is missing.
+func container() (js.Value, error) {
+	c := js.Global().Get("navigator").Get("serviceWorker")
+	if c.IsUndefined() || c.IsNull() {
+		return js.Value{}, ErrUnsupported
+	}
+	return c, nil
+}
+
+// register registers url and watches the registration for new builds. The callbacks live as
+// long as the page and are never released.
+func register(url string) error {
+	c, err := container()
+	if err != nil {
+		return err
+	}
+
+	// A worker installed while another one controls the page is an update; the first
+	// install of a page has no controller.
+	isUpdate := func() bool { return c.Get("controller").Truthy() }
+
+	c.Call("addEventListener", "controllerchange", js.FuncOf(func(this js.Value, args []js.Value) any {
+		mu.Lock()
+		reload := reloading
+		mu.Unlock()
+		if reload {
+			js.Global().Get("location").Call("reload")
+		}
+		return nil
+	}))
+
+	registered := js.FuncOf(func(this js.Value, args []js.Value) any {
+		reg := args[0]
+		mu.Lock()
+		registration = reg
+		mu.Unlock()
+
+		if reg.Get("waiting").Truthy() && isUpdate() {
+			updateFound()
+		}
+		reg.Call("addEventListener", "updatefound", js.FuncOf(func(this js.Value, args []js.Value) any {
+			worker := reg.Get("installing")
+			if !worker.Truthy() {
+				return nil
+			}
+			worker.Call("addEventListener", "statechange", js.FuncOf(func(this js.Value, args []js.Value) any {
+				if worker.Get("state").String() == "installed" && isUpdate() {
+					updateFound()
+				}
+				return nil
+			}))
+			return nil
+		}))
+		return nil
+	})
+	failed := js.FuncOf(func(this js.Value, args []js.Value) any {
+		console.Warn("[nojs] Service worker registration failed:", args[0])
+		return nil
+	})
+	c.Call("register", url).Call("then", registered, failed)
+	return nil
+}
+
+// skipWaiting asks the waiting worker to activate.
+func skipWaiting() error {
+	mu.Lock()
+	reg := registration
+	mu.Unlock()
+	if !reg.Truthy() || !reg.Get("waiting").Truthy() {
+		return ErrNoUpdate
+	}
+
+	mu.Lock()
+	reloading = true
+	mu.Unlock()
+	reg.Get("waiting").Call("postMessage", map[string]any{"type": "SKIP_WAITING"})
+	return nil
+}
+
+func reload() {
+	js.Global().Get("location").Call("reload")
+}
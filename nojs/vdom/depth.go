@@ -0,0 +1,160 @@
+package vdom
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultMaxDepth is the nesting limit the DOM patcher applies unless changed with SetMaxDepth.
+// Normal pages stay far below it; it exists so that a runaway recursive render degrades to a
+// truncated subtree and a warning instead of taking the whole app down.
+const DefaultMaxDepth = 1024
+
+var maxDepth atomic.Int64
+
+func init() {
+	maxDepth.Store(DefaultMaxDepth)
+}
+
+// SetMaxDepth sets how deep the DOM patcher descends into a VNode tree (the root is at
+// depth 1). Nodes below the limit are not created or patched; in dev builds a warning with
+// the offending path is logged. A limit of 0 or less disables the check.
+func SetMaxDepth(limit int) {
+	maxDepth.Store(int64(limit))
+}
+
+// MaxDepth returns the current nesting limit (0 or less means unlimited).
+func MaxDepth() int {
+	return int(maxDepth.Load())
+}
+
+// exceedsMaxDepth reports whether depth is beyond the current limit.
+func exceedsMaxDepth(depth int) bool {
+	limit := MaxDepth()
+	return limit > 0 && depth > limit
+}
+
+// DepthError describes a VNode nested deeper than the configured limit.
+type DepthError struct {
+	Depth int    // Depth of the offending node (the root is at depth 1)
+	Limit int    // Limit in effect when the node was found
+	Path  string // Tag path from the root to the offending node, abbreviated in the middle
+}
+
+func (e *DepthError) Error() string {
+	return fmt.Sprintf("vdom: tree depth exceeds the limit of %d at %s", e.Limit, e.Path)
+}
+
+// vnodeTrail links a node to its ancestors so iterative walks can report the path to it.
+type vnodeTrail struct {
+	node   *VNode
+	parent *vnodeTrail
+}
+
+// maxPathSegments bounds the length of paths in DepthError; longer paths keep their ends.
+const maxPathSegments = 8
+
+// path renders the trail as "div.thread > ul > … 4990 more … > li > p".
+func (t *vnodeTrail) path() string {
+	var segments []string
+	for cur := t; cur != nil; cur = cur.parent {
+		segments = append(segments, describeNode(cur.node))
+	}
+	// Collected leaf first; reverse to read from the root
+	for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+		segments[i], segments[j] = segments[j], segments[i]
+	}
+	if len(segments) > maxPathSegments {
+		half := maxPathSegments / 2
+		skipped := len(segments) - 2*half
+		segments = append(append(segments[:half:half], fmt.Sprintf("… %d more …", skipped)), segments[len(segments)-half:]...)
+	}
+	return strings.Join(segments, " > ")
+}
+
+// describeNode returns the tag of n with its id or first class, e.g. "div#main" or "li.comment".
+func describeNode(n *VNode) string {
+	if n == nil {
+		return "<nil>"
+	}
+	if id, ok := n.Attributes["id"].(string); ok && id != "" {
+		return n.Tag + "#" + id
+	}
+	if class, ok := n.Attributes["class"].(string); ok {
+		if fields := strings.Fields(class); len(fields) > 0 {
+			return n.Tag + "." + fields[0]
+		}
+	}
+	return n.Tag
+}
+
+// walkTrail is the iterative depth-first pre-order traversal behind Walk and the DOM patcher.
+// depth and parent describe where root sits in a larger tree. Returning false from visit
+// skips the node's children.
+func walkTrail(root *VNode, depth int, parent *vnodeTrail, visit func(n *VNode, depth int, trail *vnodeTrail) bool) {
+	type frame struct {
+		node  *VNode
+		depth int
+		trail *vnodeTrail
+	}
+	stack := []frame{{node: root, depth: depth, trail: parent}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if f.node == nil {
+			continue
+		}
+
+		trail := &vnodeTrail{node: f.node, parent: f.trail}
+		if !visit(f.node, f.depth, trail) {
+			continue
+		}
+		// Push children in reverse so they are visited in document order
+		for i := len(f.node.Children) - 1; i >= 0; i-- {
+			stack = append(stack, frame{node: f.node.Children[i], depth: f.depth + 1, trail: trail})
+		}
+	}
+}
+
+// Walk visits every node of the tree rooted at root in depth-first pre-order. It uses an
+// explicit stack rather than recursion, so arbitrarily deep trees cannot overflow the stack.
+// visit receives the node's depth (the root is at depth 1); returning false skips the
+// node's children. nil children are skipped.
+func Walk(root *VNode, visit func(n *VNode, depth int) bool) {
+	walkTrail(root, 1, nil, func(n *VNode, depth int, _ *vnodeTrail) bool {
+		return visit(n, depth)
+	})
+}
+
+// Depth returns the depth of the deepest node in the tree rooted at root (0 for nil).
+func Depth(root *VNode) int {
+	deepest := 0
+	Walk(root, func(n *VNode, depth int) bool {
+		if depth > deepest {
+			deepest = depth
+		}
+		return true
+	})
+	return deepest
+}
+
+// CheckDepth returns a *DepthError for the first node (in document order) nested deeper
+// than MaxDepth, or nil if the whole tree fits within the limit.
+func CheckDepth(root *VNode) error {
+	var err *DepthError
+	walkTrail(root, 1, nil, func(n *VNode, depth int, trail *vnodeTrail) bool {
+		if err != nil {
+			return false
+		}
+		if exceedsMaxDepth(depth) {
+			err = &DepthError{Depth: depth, Limit: MaxDepth(), Path: trail.path()}
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build (js || wasm) && dev
+// +build js wasm
+// +build dev
+
+package vdom
+
+import "github.com/ForgeLogic/nojs/console"
+
+// warnDepthExceeded reports a subtree the DOM patcher left out in development mode.
+func warnDepthExceeded(err *DepthError) {
+	console.Warn(err.Error() + "; the subtree below it was not rendered. Raise the limit with vdom.SetMaxDepth if the nesting is intended.")
+}
@@ -0,0 +1,8 @@
+//go:build (js || wasm) && !dev
+// +build js wasm
+// +build !dev
+
+package vdom
+
+// warnDepthExceeded is a no-op in production mode; the subtree is dropped silently.
+func warnDepthExceeded(err *DepthError) {}
@@ -0,0 +1,106 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// chain builds a linear tree of n nested divs with a <p> leaf, the root carrying id="root".
+func chain(n int) *VNode {
+	leaf := NewVNode("p", nil, nil, "leaf")
+	node := leaf
+	for i := 1; i < n; i++ {
+		node = NewVNode("div", nil, []*VNode{node}, "")
+	}
+	node.Attributes = map[string]any{"id": "root"}
+	return node
+}
+
+// TestWalk_DeepTreeDoesNotOverflow verifies Walk and Depth handle trees far deeper than the limit.
+func TestWalk_DeepTreeDoesNotOverflow(t *testing.T) {
+	// Arrange
+	root := chain(5000)
+
+	// Act
+	visited := 0
+	Walk(root, func(n *VNode, depth int) bool {
+		visited++
+		return true
+	})
+
+	// Assert
+	if visited != 5000 {
+		t.Errorf("expected 5000 visited nodes, got %d", visited)
+	}
+	if got := Depth(root); got != 5000 {
+		t.Errorf("expected depth 5000, got %d", got)
+	}
+}
+
+// TestWalk_DocumentOrderAndSkip verifies pre-order visiting, nil children and subtree skipping.
+func TestWalk_DocumentOrderAndSkip(t *testing.T) {
+	// Arrange
+	root := Div(nil,
+		NewVNode("header", nil, []*VNode{NewVNode("h1", nil, nil, "Title")}, ""),
+		nil,
+		NewVNode("main", nil, []*VNode{NewVNode("p", nil, nil, "Body")}, ""),
+	)
+
+	// Act
+	var tags []string
+	Walk(root, func(n *VNode, depth int) bool {
+		tags = append(tags, n.Tag)
+		return n.Tag != "header"
+	})
+
+	// Assert
+	if got := strings.Join(tags, ","); got != "div,header,main,p" {
+		t.Errorf("unexpected visit order: %s", got)
+	}
+}
+
+// TestCheckDepth_ReportsAbbreviatedPath verifies the error names the offending node's path.
+func TestCheckDepth_ReportsAbbreviatedPath(t *testing.T) {
+	// Arrange
+	root := chain(5000)
+
+	// Act
+	err := CheckDepth(root)
+
+	// Assert
+	var depthErr *DepthError
+	if !errors.As(err, &depthErr) {
+		t.Fatalf("expected a *DepthError, got %v", err)
+	}
+	if depthErr.Depth != DefaultMaxDepth+1 || depthErr.Limit != DefaultMaxDepth {
+		t.Errorf("expected depth %d and limit %d, got %d and %d", DefaultMaxDepth+1, DefaultMaxDepth, depthErr.Depth, depthErr.Limit)
+	}
+	want := "div#root > div > div > div > … 1017 more … > div > div > div > div"
+	if depthErr.Path != want {
+		t.Errorf("expected path %q, got %q", want, depthErr.Path)
+	}
+	if CheckDepth(chain(DefaultMaxDepth)) != nil {
+		t.Errorf("expected a tree exactly at the limit to pass")
+	}
+}
+
+// TestSetMaxDepth_ZeroDisablesCheck verifies the limit is configurable and can be turned off.
+func TestSetMaxDepth_ZeroDisablesCheck(t *testing.T) {
+	// Arrange
+	defer SetMaxDepth(DefaultMaxDepth)
+	root := chain(5000)
+
+	// Act & Assert
+	SetMaxDepth(0)
+	if err := CheckDepth(root); err != nil {
+		t.Errorf("expected no error with the limit disabled, got %v", err)
+	}
+	SetMaxDepth(10)
+	if err := CheckDepth(chain(11)); err == nil {
+		t.Errorf("expected an error for depth 11 with a limit of 10")
+	}
+}
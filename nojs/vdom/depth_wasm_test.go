@@ -0,0 +1,57 @@
+//go:build js && wasm
+// +build js,wasm
+
+package vdom
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+// domChain builds n nested divs, each holding a text paragraph with its level.
+func domChain(n int, text string) *VNode {
+	node := NewVNode("p", nil, nil, text)
+	for i := 1; i < n; i++ {
+		node = Div(nil, NewVNode("p", nil, nil, text), node)
+	}
+	return node
+}
+
+// TestPatch_DeepTree_TruncatedAtMaxDepth is a browser smoke test: a 5000-deep tree must render
+// and patch without exhausting the stack, with everything below MaxDepth left out of the DOM.
+// It is skipped where no DOM is available, such as under Node.
+func TestPatch_DeepTree_TruncatedAtMaxDepth(t *testing.T) {
+	doc := js.Global().Get("document")
+	if !doc.Truthy() {
+		t.Skip("no DOM available")
+	}
+
+	// Arrange
+	mount := doc.Call("createElement", "div")
+	mount.Set("id", "depth-smoke-test")
+	doc.Get("body").Call("appendChild", mount)
+	defer mount.Call("remove")
+
+	oldVNode := domChain(5000, "a")
+	RenderTo(mount, oldVNode)
+
+	// Act
+	newVNode := domChain(5000, "b")
+	Patch("#depth-smoke-test", oldVNode, newVNode)
+
+	// Assert
+	depth := 0
+	for el := mount.Get("firstChild"); el.Truthy(); el = el.Get("lastChild") {
+		if el.Get("nodeType").Int() == 1 {
+			depth++
+		}
+	}
+	if depth != DefaultMaxDepth {
+		t.Errorf("Expected the DOM to be cut off at depth %d, got %d", DefaultMaxDepth, depth)
+	}
+	if got := mount.Get("firstChild").Get("firstChild").Get("textContent").String(); got != "b" {
+		t.Errorf("Expected the patch to update the top of the tree, got %q", got)
+	}
+
+	Clear("#depth-smoke-test", newVNode)
+}
@@ -0,0 +1,230 @@
+package vdom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Children and Attributes have one meaning whether they are nil or empty: a node with
+// Children == nil renders exactly like one with []*VNode{}, and likewise for Attributes.
+// Generated code produces both forms (an empty {@for} yields an empty slice, a childless
+// element nil), so every patch decision below is written in terms of len, never of nil.
+// Normalize rewrites a tree into the canonical nil form.
+
+// Normalize rewrites the tree rooted at n into canonical form: empty Children and
+// Attributes become nil. It changes nothing about how the tree renders or patches; the
+// renderer applies it to every tree it renders so trees from different code paths compare
+// alike.
+func Normalize(n *VNode) {
+	Walk(n, func(v *VNode, _ int) bool {
+		if len(v.Children) == 0 {
+			v.Children = nil
+		}
+		if len(v.Attributes) == 0 {
+			v.Attributes = nil
+		}
+		return true
+	})
+}
+
+// contentAction is how an element whose tag is unchanged has its content patched.
+type contentAction int
+
+const (
+	// contentValue syncs the value of a form field; its children are never touched.
+	contentValue contentAction = iota
+	// contentText replaces the element's children with its text Content.
+	contentText
+	// contentChildren patches the element's children one by one.
+	contentChildren
+	// contentHTML replaces the element's children with its UnsafeInnerHTML markup.
+	contentHTML
+)
+
+// patchDecision describes how patchNode turns the DOM of old into that of new.
+type patchDecision struct {
+	Skip      bool          // old and new are the same node: the subtree is already in the DOM
+	Replace   bool          // Recreate the element: tag or component key changed
+	Attrs     []attrPatch   // Attribute updates, in key order
+	Content   contentAction // How the content is patched (when not replaced)
+	ResetText bool          // contentText: textContent must be (re)written
+	ClearText bool          // contentChildren: old text Content (or markup) must be cleared first
+	ResetHTML bool          // contentHTML: innerHTML must be (re)written
+
+	// SyncSelect is set for a <select> bound to a value: once its options are patched, the
+	// option at SelectedIndex (-1: none) is selected.
+	SyncSelect    bool
+	SelectedIndex int
+}
+
+// attrPatch is one attribute update.
+type attrPatch struct {
+	Key    string
+	Value  any
+	Remove bool
+}
+
+// decidePatch computes the patch of old into new. It has no DOM dependencies, so the
+// patcher's decisions can be tested natively; patchNode only carries them out.
+//
+// When new is the very node old was patched from, the subtree is skipped: VNodes are never
+// modified once rendered, so an identical pointer means an identical subtree. This is what
+// happens to slot content when a host re-renders on its own: the projected nodes are the
+// ones its parent passed last time, so they are neither diffed nor have their listeners
+// re-attached. Slot content the parent rendered again is a new tree and is patched in full.
+//
+// Nodes whose component keys differ are replaced. A keyed node with a Transition replaces
+// an unkeyed one too, so that it enters, as the page a route with a transition shows in
+// place of one without.
+func decidePatch(old, new *VNode) patchDecision {
+	if old == new {
+		return patchDecision{Skip: true}
+	}
+	if new.ComponentKey != "" && old.ComponentKey != new.ComponentKey && (old.ComponentKey != "" || new.Transition != nil) {
+		return patchDecision{Replace: true}
+	}
+	if old.Tag != new.Tag {
+		return patchDecision{Replace: true}
+	}
+
+	d := patchDecision{Attrs: diffAttributes(old.Attributes, new.Attributes)}
+	switch {
+	case new.Tag == "input" || new.Tag == "textarea":
+		d.Content = contentValue
+	case new.Tag == "select":
+		// Options are patched like any children; the selection is then re-asserted from the
+		// bound value, since a positional patch may have changed the value of the option
+		// element the browser had selected.
+		d.Attrs = withoutSelectBinding(d.Attrs)
+		d.Content = contentChildren
+		d.SelectedIndex, d.SyncSelect = SelectedOptionIndex(new)
+	case new.UnsafeInnerHTML != "":
+		// The markup is compared as a string: the DOM it parsed into is not diffed, so
+		// unchanged markup keeps its nodes and changed markup is parsed again.
+		d.Content = contentHTML
+		d.ResetHTML = old.UnsafeInnerHTML != new.UnsafeInnerHTML || old.Content != "" || hasDOMChildren(old)
+	case len(new.Children) == 0:
+		d.Content = contentText
+		// Setting textContent also removes the old children's DOM nodes, so it is needed
+		// when the text changed or when there are old children or markup to remove.
+		d.ResetText = old.Content != new.Content || hasDOMChildren(old) || old.UnsafeInnerHTML != ""
+	default:
+		d.Content = contentChildren
+		d.ClearText = old.Content != "" || old.UnsafeInnerHTML != ""
+	}
+	return d
+}
+
+// hasDOMChildren reports whether any of n's children produced a DOM node.
+func hasDOMChildren(n *VNode) bool {
+	for _, child := range n.Children {
+		if child != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// diffAttributes returns the updates turning oldAttrs into newAttrs, sorted by key. Event
+// handlers (keys starting with "on") are skipped; they are attached separately. A boolean
+// attribute is present when true and absent when false, so one that turns false is removed
+// like one the new map omits.
+func diffAttributes(oldAttrs, newAttrs map[string]any) []attrPatch {
+	var patches []attrPatch
+	for key := range oldAttrs {
+		if _, exists := newAttrs[key]; !exists && !isEventAttribute(key) {
+			patches = append(patches, attrPatch{Key: key, Remove: true})
+		}
+	}
+	for key, value := range newAttrs {
+		if isEventAttribute(key) {
+			continue
+		}
+		if oldValue, exists := oldAttrs[key]; !exists || oldValue != value {
+			patches = append(patches, attrPatch{Key: key, Value: value, Remove: value == false})
+		}
+	}
+	sort.Slice(patches, func(i, j int) bool { return patches[i].Key < patches[j].Key })
+	return patches
+}
+
+// isEventAttribute reports whether key names an event handler (e.g., "onclick").
+func isEventAttribute(key string) bool {
+	return len(key) > 2 && key[0] == 'o' && key[1] == 'n'
+}
+
+// Selection of a <select> follows its bound value alone: the value attribute the compiler
+// emits for <select value="{Field}"> (or, for hand-written nodes, a non-empty Content). The
+// option whose value equals it is selected; <option selected> plays no part. When no option
+// matches, the option matching the optional fallback attribute is selected, and failing
+// that none is (selectedIndex -1) rather than the browser's default of the first option.
+// A select with neither value nor Content is left to the browser.
+const (
+	selectValueAttr    = "value"
+	selectFallbackAttr = "fallback"
+)
+
+// isSelectBound reports whether the <select> n has a bound value.
+func isSelectBound(n *VNode) bool {
+	if _, ok := n.Attributes[selectValueAttr]; ok {
+		return true
+	}
+	return n.Content != ""
+}
+
+// SelectedOptionIndex returns the position, among the non-nil <option> children of the
+// <select> n, of the option its bound value selects, or -1 when none does. bound is false
+// when n has no bound value, in which case the renderer leaves the selection alone.
+func SelectedOptionIndex(n *VNode) (index int, bound bool) {
+	if n == nil || n.Tag != "select" || !isSelectBound(n) {
+		return -1, false
+	}
+	value := n.Content
+	if v, ok := n.Attributes[selectValueAttr]; ok {
+		value = fmt.Sprint(v)
+	}
+
+	var options []string
+	for _, child := range n.Children {
+		if child != nil && child.Tag == "option" {
+			options = append(options, optionValue(child))
+		}
+	}
+	for i, option := range options {
+		if option == value {
+			return i, true
+		}
+	}
+	if fallback, ok := n.Attributes[selectFallbackAttr]; ok {
+		for i, option := range options {
+			if option == fmt.Sprint(fallback) {
+				return i, true
+			}
+		}
+	}
+	return -1, true
+}
+
+// optionValue returns the value an <option> submits: its value attribute, or its text.
+func optionValue(n *VNode) string {
+	if v, ok := n.Attributes["value"]; ok {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(n.Content)
+}
+
+// withoutSelectBinding drops the bound value and fallback of a <select> from its attribute
+// updates; they drive the selection and are not DOM attributes.
+func withoutSelectBinding(patches []attrPatch) []attrPatch {
+	kept := patches[:0]
+	for _, p := range patches {
+		if p.Key != selectValueAttr && p.Key != selectFallbackAttr {
+			kept = append(kept, p)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
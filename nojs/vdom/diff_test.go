@@ -0,0 +1,483 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomTree builds a small random tree in which every childless node and every node
+// without attributes randomly uses the nil or the empty form.
+func randomTree(rng *rand.Rand, depth int) *VNode {
+	tags := []string{"div", "span", "p", "ul", "li", "input", "button"}
+	contents := []string{"", "", "a", "b"}
+	n := &VNode{Tag: tags[rng.Intn(len(tags))], Content: contents[rng.Intn(len(contents))]}
+
+	if rng.Intn(2) == 0 {
+		n.Attributes = map[string]any{}
+	}
+	for _, key := range []string{"class", "id", "onclick"} {
+		if rng.Intn(3) == 0 {
+			if n.Attributes == nil {
+				n.Attributes = map[string]any{}
+			}
+			n.Attributes[key] = []string{"x", "y"}[rng.Intn(2)]
+		}
+	}
+
+	if rng.Intn(2) == 0 {
+		n.Children = []*VNode{}
+	}
+	if depth > 0 {
+		for i := rng.Intn(4); i > 0; i-- {
+			if rng.Intn(5) == 0 {
+				n.Children = append(n.Children, nil) // An {@if} branch that rendered nothing
+			} else {
+				n.Children = append(n.Children, randomTree(rng, depth-1))
+			}
+		}
+	}
+	return n
+}
+
+// cloneTree deep-copies n, preserving nil and empty forms.
+func cloneTree(n *VNode) *VNode {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	if n.Attributes != nil {
+		c.Attributes = make(map[string]any, len(n.Attributes))
+		for k, v := range n.Attributes {
+			c.Attributes[k] = v
+		}
+	}
+	if n.Children != nil {
+		c.Children = make([]*VNode, len(n.Children))
+		for i, child := range n.Children {
+			c.Children[i] = cloneTree(child)
+		}
+	}
+	return &c
+}
+
+// decisionLog records every decision the patcher makes turning old into new, walking the
+// children it would patch in place.
+func decisionLog(old, new *VNode, path string, log *[]string) {
+	d := decidePatch(old, new)
+	*log = append(*log, fmt.Sprintf("%s %+v", path, d))
+	if d.Replace || d.Content != contentChildren {
+		return
+	}
+	shared := min(len(old.Children), len(new.Children))
+	for i := 0; i < shared; i++ {
+		if old.Children[i] != nil && new.Children[i] != nil {
+			decisionLog(old.Children[i], new.Children[i], fmt.Sprintf("%s/%d", path, i), log)
+		}
+	}
+	*log = append(*log, fmt.Sprintf("%s children %d->%d", path, len(old.Children), len(new.Children)))
+}
+
+// TestDecidePatch_NilAndEmptyFormsAreEquivalent verifies, over many random tree pairs, that
+// the patcher makes the same decisions for mixed nil/empty forms as for their normalized forms.
+func TestDecidePatch_NilAndEmptyFormsAreEquivalent(t *testing.T) {
+	rng := rand.New(rand.NewSource(2210))
+	for i := 0; i < 2000; i++ {
+		// Arrange
+		old, new := randomTree(rng, 3), randomTree(rng, 3)
+		normOld, normNew := cloneTree(old), cloneTree(new)
+		Normalize(normOld)
+		Normalize(normNew)
+
+		// Act
+		var mixed, normalized []string
+		decisionLog(old, new, "", &mixed)
+		decisionLog(normOld, normNew, "", &normalized)
+
+		// Assert
+		if fmt.Sprint(mixed) != fmt.Sprint(normalized) {
+			t.Fatalf("pair %d: decisions differ between mixed and normalized forms\nmixed:      %v\nnormalized: %v", i, mixed, normalized)
+		}
+	}
+}
+
+// TestNormalize_CanonicalNilForm verifies Normalize turns every empty slice and map into nil,
+// keeps nil placeholders, and is idempotent.
+func TestNormalize_CanonicalNilForm(t *testing.T) {
+	// Arrange
+	root := NewVNode("ul", map[string]any{}, []*VNode{
+		NewVNode("li", map[string]any{"class": "a"}, []*VNode{}, "one"),
+		nil,
+		NewVNode("li", nil, nil, "two"),
+	}, "")
+
+	// Act
+	Normalize(root)
+	Normalize(root)
+
+	// Assert
+	if root.Attributes != nil {
+		t.Errorf("expected nil root attributes, got %#v", root.Attributes)
+	}
+	if len(root.Children) != 3 || root.Children[1] != nil {
+		t.Fatalf("expected the nil placeholder to be kept, got %v", root.Children)
+	}
+	if root.Children[0].Children != nil {
+		t.Errorf("expected nil children, got %#v", root.Children[0].Children)
+	}
+	if root.Children[0].Attributes["class"] != "a" {
+		t.Errorf("expected attributes to be kept, got %#v", root.Children[0].Attributes)
+	}
+}
+
+// TestDecidePatch_ListEmptiedResetsText verifies that a list flipping to no items clears the
+// old items' DOM even though its text content is unchanged, whichever empty form it uses.
+func TestDecidePatch_ListEmptiedResetsText(t *testing.T) {
+	for _, empty := range [][]*VNode{nil, {}} {
+		// Arrange
+		old := NewVNode("ul", nil, []*VNode{NewVNode("li", nil, nil, "one")}, "")
+		new := NewVNode("ul", nil, empty, "")
+
+		// Act
+		d := decidePatch(old, new)
+
+		// Assert
+		if d.Content != contentText || !d.ResetText {
+			t.Errorf("children %#v: expected a text reset, got %+v", empty, d)
+		}
+	}
+}
+
+// TestDiffAttributes_NilAndEmptyMaps verifies attribute diffs ignore the nil/empty distinction
+// and skip event handlers.
+func TestDiffAttributes_NilAndEmptyMaps(t *testing.T) {
+	// Arrange
+	attrs := map[string]any{"class": "x", "onclick": "handler", "value": nil}
+
+	// Act
+	fromNil := diffAttributes(nil, attrs)
+	fromEmpty := diffAttributes(map[string]any{}, attrs)
+	toNil := diffAttributes(attrs, nil)
+
+	// Assert
+	if fmt.Sprint(fromNil) != fmt.Sprint(fromEmpty) {
+		t.Errorf("expected identical diffs, got %v and %v", fromNil, fromEmpty)
+	}
+	if len(fromNil) != 2 || fromNil[0].Key != "class" || fromNil[1].Key != "value" {
+		t.Errorf("expected class and value to be set, got %v", fromNil)
+	}
+	if len(toNil) != 2 || !toNil[0].Remove || !toNil[1].Remove {
+		t.Errorf("expected class and value to be removed, got %v", toNil)
+	}
+}
+
+// patchWork walks the patch of old into new like patchElement and counts the nodes diffed
+// and the event listeners re-attached on the way.
+func patchWork(old, new *VNode) (diffed, listeners int) {
+	d := decidePatch(old, new)
+	if d.Skip {
+		return 0, 0
+	}
+	diffed = 1
+	for key := range new.Attributes {
+		if isEventAttribute(key) {
+			listeners++
+		}
+	}
+	if d.Replace || d.Content != contentChildren {
+		return diffed, listeners
+	}
+	shared := min(len(old.Children), len(new.Children))
+	for i := 0; i < shared; i++ {
+		if old.Children[i] != nil && new.Children[i] != nil {
+			childDiffed, childListeners := patchWork(old.Children[i], new.Children[i])
+			diffed += childDiffed
+			listeners += childListeners
+		}
+	}
+	return diffed, listeners
+}
+
+// pageBody builds slot content of rows*(1+cells) nodes, each cell with a click handler.
+func pageBody(rows, cells int) []*VNode {
+	body := make([]*VNode, rows)
+	for i := range body {
+		row := make([]*VNode, cells)
+		for j := range row {
+			row[j] = NewVNode("button", map[string]any{"class": "cell", "onclick": "handler"}, nil, fmt.Sprintf("%d.%d", i, j))
+		}
+		body[i] = NewVNode("div", map[string]any{"class": "row"}, row, "")
+	}
+	return body
+}
+
+// layoutTree renders a layout host around slot, as generated code does: a new tree whose
+// main element spreads the slot content it was given.
+func layoutTree(collapsed bool, slot []*VNode) *VNode {
+	sidebarClass := "sidebar"
+	if collapsed {
+		sidebarClass = "sidebar collapsed"
+	}
+	sidebar := NewVNode("aside", map[string]any{"class": sidebarClass}, []*VNode{
+		NewVNode("button", map[string]any{"onclick": "toggle"}, nil, "Toggle"),
+	}, "")
+	main := NewVNode("main", nil, append([]*VNode(nil), slot...), "")
+	return NewVNode("div", map[string]any{"class": "layout"}, []*VNode{sidebar, main}, "")
+}
+
+// TestDecidePatch_SameNodeIsSkipped verifies a host re-rendered around the slot content it
+// already had skips the projected subtree entirely, while the host's own nodes are diffed.
+func TestDecidePatch_SameNodeIsSkipped(t *testing.T) {
+	// Arrange
+	body := pageBody(10, 9)
+	old, new := layoutTree(false, body), layoutTree(true, body)
+
+	// Act
+	d := decidePatch(body[0], body[0])
+	diffed, listeners := patchWork(old, new)
+
+	// Assert
+	if !d.Skip || len(d.Attrs) != 0 {
+		t.Errorf("expected the same node to be skipped, got %+v", d)
+	}
+	if diffed != 4 || listeners != 1 {
+		t.Errorf("expected only the 4 host nodes and the toggle listener to be patched, got %d nodes and %d listeners", diffed, listeners)
+	}
+}
+
+// TestDecidePatch_RegeneratedSlotIsDiffed verifies slot content the parent rendered again is
+// patched in full even when it is structurally identical, and that changes in it are found.
+func TestDecidePatch_RegeneratedSlotIsDiffed(t *testing.T) {
+	// Arrange
+	body := pageBody(10, 9)
+	regenerated := make([]*VNode, len(body))
+	for i, row := range body {
+		regenerated[i] = cloneTree(row)
+	}
+	regenerated[3].Children[4].Attributes = map[string]any{"class": "cell selected", "onclick": "handler"}
+	old, new := layoutTree(false, body), layoutTree(true, regenerated)
+
+	// Act
+	diffed, listeners := patchWork(old, new)
+	var log []string
+	decisionLog(old, new, "", &log)
+
+	// Assert
+	if diffed != 4+10*10 || listeners != 1+10*9 {
+		t.Errorf("expected every node and listener to be patched, got %d nodes and %d listeners", diffed, listeners)
+	}
+	if !strings.Contains(fmt.Sprint(log), "/1/3/4 {Skip:false Replace:false Attrs:[{Key:class Value:cell selected Remove:false}]") {
+		t.Errorf("expected the changed class to be patched, got %v", log)
+	}
+}
+
+// BenchmarkPatch_LayoutToggle compares toggling a layout's sidebar around a 1,000-node page
+// body when the slot content is reused with the same toggle when the parent rendered it again.
+func BenchmarkPatch_LayoutToggle(b *testing.B) {
+	body := pageBody(100, 9) // 100 rows * (1 + 9 cells) = 1,000 nodes
+	for _, bc := range []struct {
+		name string
+		slot func() []*VNode
+	}{
+		{"reused-slot", func() []*VNode { return body }},
+		{"regenerated-slot", func() []*VNode {
+			regenerated := make([]*VNode, len(body))
+			for i, row := range body {
+				regenerated[i] = cloneTree(row)
+			}
+			return regenerated
+		}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			old := layoutTree(false, body)
+			var diffed, listeners int
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				new := layoutTree(i%2 == 0, bc.slot())
+				b.StartTimer()
+				diffed, listeners = patchWork(old, new)
+				old = new
+			}
+			b.ReportMetric(float64(diffed), "nodes-diffed")
+			b.ReportMetric(float64(listeners), "listeners-reattached")
+		})
+	}
+}
+
+// languageSelect builds a <select> bound to value whose options are the given values, as a
+// filtered {@for} over them renders it.
+func languageSelect(value any, options ...string) *VNode {
+	children := make([]*VNode, len(options))
+	for i, option := range options {
+		children[i] = NewVNode("option", map[string]any{"value": option}, nil, option)
+	}
+	return NewVNode("select", map[string]any{"value": value}, children, "")
+}
+
+// TestDecidePatch_SelectFilteredAboveSelectionKeepsIt verifies removing options before the
+// selected one moves the selection with its value instead of keeping its position.
+func TestDecidePatch_SelectFilteredAboveSelectionKeepsIt(t *testing.T) {
+	// Arrange
+	old := languageSelect("Rust", "Go", "Rust", "Zig")
+	filtered := languageSelect("Rust", "Rust", "Zig")
+
+	// Act
+	d := decidePatch(old, filtered)
+
+	// Assert
+	if !d.SyncSelect || d.SelectedIndex != 0 {
+		t.Errorf("expected Rust to stay selected at its new index 0, got sync=%v index=%d", d.SyncSelect, d.SelectedIndex)
+	}
+	if d.Content != contentChildren {
+		t.Errorf("expected the options to be patched, got content action %d", d.Content)
+	}
+}
+
+// TestDecidePatch_SelectFilteredBelowSelectionKeepsIt verifies removing options after the
+// selected one leaves the selection where it is.
+func TestDecidePatch_SelectFilteredBelowSelectionKeepsIt(t *testing.T) {
+	// Arrange
+	old := languageSelect("Rust", "Go", "Rust", "Zig")
+	filtered := languageSelect("Rust", "Go", "Rust")
+
+	// Act
+	d := decidePatch(old, filtered)
+
+	// Assert
+	if !d.SyncSelect || d.SelectedIndex != 1 {
+		t.Errorf("expected Rust to stay selected at index 1, got sync=%v index=%d", d.SyncSelect, d.SelectedIndex)
+	}
+}
+
+// TestDecidePatch_SelectFilteredOutClearsSelection verifies filtering out the selected value
+// selects nothing instead of the first option, unless a fallback is given.
+func TestDecidePatch_SelectFilteredOutClearsSelection(t *testing.T) {
+	// Arrange
+	old := languageSelect("Rust", "Go", "Rust", "Zig")
+	filtered := languageSelect("Rust", "Go", "Zig")
+	withFallback := languageSelect("Rust", "Go", "Zig")
+	withFallback.Attributes["fallback"] = "Zig"
+
+	// Act
+	cleared := decidePatch(old, filtered)
+	fellBack := decidePatch(old, withFallback)
+
+	// Assert
+	if !cleared.SyncSelect || cleared.SelectedIndex != -1 {
+		t.Errorf("expected no option to be selected, got sync=%v index=%d", cleared.SyncSelect, cleared.SelectedIndex)
+	}
+	if fellBack.SelectedIndex != 1 {
+		t.Errorf("expected the fallback Zig at index 1, got %d", fellBack.SelectedIndex)
+	}
+}
+
+// TestDecidePatch_SelectBindingIsNotAnAttribute verifies the bound value and fallback of a
+// select are not written to the DOM as attributes, and an unbound select is left alone.
+func TestDecidePatch_SelectBindingIsNotAnAttribute(t *testing.T) {
+	// Arrange
+	old := languageSelect("Go", "Go", "Rust")
+	changed := languageSelect("Rust", "Go", "Rust")
+	changed.Attributes["fallback"] = "Go"
+	changed.Attributes["class"] = "picker"
+	unbound := NewVNode("select", nil, old.Children, "")
+
+	// Act
+	d := decidePatch(old, changed)
+	_, bound := SelectedOptionIndex(unbound)
+
+	// Assert
+	if fmt.Sprint(d.Attrs) != "[{class picker false}]" {
+		t.Errorf("expected only the class attribute to be patched, got %v", d.Attrs)
+	}
+	if d.SelectedIndex != 1 {
+		t.Errorf("expected Rust at index 1, got %d", d.SelectedIndex)
+	}
+	if bound {
+		t.Error("expected a select without value to be left to the browser")
+	}
+}
+
+// TestSelectedOptionIndex_OptionTextAndNonStringValues verifies options without a value
+// attribute match on their text and bound values of other types match their string form.
+func TestSelectedOptionIndex_OptionTextAndNonStringValues(t *testing.T) {
+	// Arrange
+	byText := NewVNode("select", map[string]any{"value": "Two"}, []*VNode{
+		NewVNode("option", nil, nil, "One"),
+		nil, // A filtered-out {@if} branch
+		NewVNode("option", nil, nil, " Two "),
+	}, "")
+	byNumber := languageSelect(3, "1", "2", "3")
+
+	// Act
+	textIndex, _ := SelectedOptionIndex(byText)
+	numberIndex, _ := SelectedOptionIndex(byNumber)
+
+	// Assert
+	if textIndex != 1 {
+		t.Errorf("expected the second option element to match on its text, got %d", textIndex)
+	}
+	if numberIndex != 2 {
+		t.Errorf("expected the int value 3 to match option \"3\", got %d", numberIndex)
+	}
+}
+
+// TestDecidePatch_InnerHTMLRewrittenOnlyWhenChanged verifies innerHTML is reassigned only
+// when the markup differs, and that leaving raw markup for text or children clears it.
+func TestDecidePatch_InnerHTMLRewrittenOnlyWhenChanged(t *testing.T) {
+	cases := []struct {
+		name      string
+		old, new  *VNode
+		content   contentAction
+		resetHTML bool
+	}{
+		{"same markup", RawHTML("div", nil, "<p>a</p>"), RawHTML("div", nil, "<p>a</p>"), contentHTML, false},
+		{"changed markup", RawHTML("div", nil, "<p>a</p>"), RawHTML("div", nil, "<p>b</p>"), contentHTML, true},
+		{"children to markup", Div(nil, Text("a")), RawHTML("div", nil, "<p>a</p>"), contentHTML, true},
+		{"text to markup", NewVNode("div", nil, nil, "a"), RawHTML("div", nil, "<p>a</p>"), contentHTML, true},
+	}
+	for _, c := range cases {
+		// Act
+		d := decidePatch(c.old, c.new)
+
+		// Assert
+		if d.Content != c.content || d.ResetHTML != c.resetHTML {
+			t.Errorf("%s: expected content %d with ResetHTML %v, got %+v", c.name, c.content, c.resetHTML, d)
+		}
+	}
+
+	// Markup replaced by an empty element or by children must be cleared
+	if d := decidePatch(RawHTML("div", nil, "<p>a</p>"), Div(nil)); d.Content != contentText || !d.ResetText {
+		t.Errorf("markup to empty: expected a text reset, got %+v", d)
+	}
+	if d := decidePatch(RawHTML("div", nil, "<p>a</p>"), Div(nil, Text("a"))); d.Content != contentChildren || !d.ClearText {
+		t.Errorf("markup to children: expected the markup cleared, got %+v", d)
+	}
+}
+
+// TestDiffAttributes_BooleanTurnedOffIsRemoved verifies a boolean attribute that turns false,
+// or that the new map omits, is removed rather than set, so disabled="{IsSaving}" re-enables
+// the button once the save is over.
+func TestDiffAttributes_BooleanTurnedOffIsRemoved(t *testing.T) {
+	// Arrange
+	saving := map[string]any{"class": "save", "disabled": true}
+	saved := map[string]any{"class": "save", "disabled": false}
+
+	// Act
+	turnedOff := diffAttributes(saving, saved)
+	omitted := diffAttributes(saving, map[string]any{"class": "save"})
+	turnedOn := diffAttributes(saved, saving)
+
+	// Assert
+	for _, patches := range [][]attrPatch{turnedOff, omitted} {
+		if len(patches) != 1 || patches[0].Key != "disabled" || !patches[0].Remove {
+			t.Errorf("expected disabled to be removed, got %v", patches)
+		}
+	}
+	if len(turnedOn) != 1 || turnedOn[0].Remove || turnedOn[0].Value != true {
+		t.Errorf("expected disabled to be set, got %v", turnedOn)
+	}
+}
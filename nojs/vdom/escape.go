@@ -0,0 +1,105 @@
+package vdom
+
+import (
+	"strings"
+)
+
+// OutputContext is the place in an HTML document a value is written to, which decides how
+// it must be escaped. The functions below are the single escaping policy of every output
+// backend: the DOM renderer (DOMValue), RenderToString (Escape), and the HTML the compiler
+// writes, such as preload shells.
+type OutputContext int
+
+const (
+	TextContext      OutputContext = iota // Element content
+	AttributeContext                      // Quoted attribute value
+	URLContext                            // Attribute value the browser loads or navigates to (href, src, ...)
+)
+
+// UnsafeURL replaces URLs whose scheme could run script (e.g., "javascript:").
+const UnsafeURL = "about:invalid#nojs-unsafe-url"
+
+// urlAttributes lists the attributes holding a URL.
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"poster":     true,
+	"cite":       true,
+	"background": true,
+	"xlink:href": true,
+}
+
+// safeURLSchemes lists the schemes SanitizeURL keeps. URLs without a scheme are relative
+// and always kept.
+var safeURLSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+	"tel":    true,
+}
+
+// ContextOf returns the context of the value of attribute name.
+func ContextOf(name string) OutputContext {
+	if urlAttributes[strings.ToLower(name)] {
+		return URLContext
+	}
+	return AttributeContext
+}
+
+// EscapeText escapes s for element content.
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// EscapeAttribute escapes s for a quoted attribute value.
+func EscapeAttribute(s string) string {
+	return attributeEscaper.Replace(s)
+}
+
+// SanitizeURL returns s, or UnsafeURL when s has a scheme other than http, https, mailto or
+// tel. Browsers ignore whitespace and control characters in schemes, so they are ignored
+// here too. SanitizeURL does not escape: it is applied before the escaper of the output.
+func SanitizeURL(s string) string {
+	scheme, _, found := strings.Cut(s, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return s // Relative: the colon belongs to the path, query or fragment
+	}
+	scheme = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, scheme)
+	if safeURLSchemes[strings.ToLower(scheme)] {
+		return s
+	}
+	return UnsafeURL
+}
+
+// Escape returns s escaped for ctx, for backends writing HTML text.
+func Escape(ctx OutputContext, s string) string {
+	switch ctx {
+	case TextContext:
+		return EscapeText(s)
+	case URLContext:
+		return EscapeAttribute(SanitizeURL(s))
+	default:
+		return EscapeAttribute(s)
+	}
+}
+
+// DOMValue returns s as the DOM renderer passes it to setAttribute or textContent. Those do
+// not parse HTML, so s is not escaped, which would show the entities; URLs are sanitized.
+func DOMValue(ctx OutputContext, s string) string {
+	if ctx == URLContext {
+		return SanitizeURL(s)
+	}
+	return s
+}
+
+var (
+	textEscaper      = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	attributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&#34;", "'", "&#39;")
+)
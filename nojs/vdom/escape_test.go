@@ -0,0 +1,67 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import "testing"
+
+// TestSanitizeURL verifies relative URLs and safe schemes are kept and others replaced,
+// whatever their case or the whitespace browsers ignore in them.
+func TestSanitizeURL(t *testing.T) {
+	cases := map[string]string{
+		"/users/42?tab=a:b":       "/users/42?tab=a:b",
+		"https://example.com/a":   "https://example.com/a",
+		"mailto:ops@example.com":  "mailto:ops@example.com",
+		"tel:+3120":               "tel:+3120",
+		"#section:2":              "#section:2",
+		"javascript:alert(1)":     UnsafeURL,
+		" JaVaScRiPt:alert(1)":    UnsafeURL,
+		"java\tscript:alert(1)":   UnsafeURL,
+		"data:text/html,<script>": UnsafeURL,
+		"vbscript:msgbox(1)":      UnsafeURL,
+	}
+	for in, want := range cases {
+		if got := SanitizeURL(in); got != want {
+			t.Errorf("SanitizeURL(%q) = %q, expected %q", in, got, want)
+		}
+	}
+}
+
+// TestRenderToString_Elements verifies booleans, event handlers, void elements and the
+// selection of a bound <select> are written as the DOM renderer would produce them.
+func TestRenderToString_Elements(t *testing.T) {
+	// Arrange
+	tree := NewVNode("form", map[string]any{"onSubmit": func() {}}, []*VNode{
+		NewVNode("input", map[string]any{"disabled": true, "required": false}, nil, `a "quote"`),
+		NewVNode("select", map[string]any{"value": "b"}, []*VNode{
+			NewVNode("option", map[string]any{"value": "a"}, nil, "A & co"),
+			NewVNode("option", map[string]any{"value": "b"}, nil, "B"),
+		}, ""),
+		Text("1 < 2"),
+	}, "")
+
+	// Act
+	got := RenderToString(tree)
+
+	// Assert
+	want := `<form><input disabled value="a &#34;quote&#34;"><select><option value="a">A &amp; co</option>` +
+		`<option value="b" selected>B</option></select>1 &lt; 2</form>`
+	if got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+// TestRenderToString_UnsafeInnerHTML verifies the markup of a RawHTML element is written
+// unescaped in place of its content.
+func TestRenderToString_UnsafeInnerHTML(t *testing.T) {
+	// Arrange
+	tree := RawHTML("article", map[string]any{"class": "post"}, "<h2>Intro</h2><p>1 &lt; 2</p>")
+
+	// Act
+	got := RenderToString(tree)
+
+	// Assert
+	if want := `<article class="post"><h2>Intro</h2><p>1 &lt; 2</p></article>`; got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
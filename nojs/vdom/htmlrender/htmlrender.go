@@ -0,0 +1,67 @@
+// Package htmlrender turns VNode trees into HTML outside the browser, to pre-render pages at
+// build time for search engines and a first paint before the WASM module starts. It checks
+// that a tree can be written as HTML and writes it with vdom.RenderToString, the serializer
+// the framework's tests use, so the markup matches what the DOM renderer builds.
+package htmlrender
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// tagNameRegex matches the element names RenderToString accepts, custom elements included.
+var tagNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*$`)
+
+// voidElements have no closing tag, so they cannot hold children or text.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// RenderToString returns the HTML of the tree rooted at n. Text and attribute values are
+// escaped for their context and URL attributes sanitized (see vdom.Escape); a true boolean
+// attribute is written by name alone and a false one left out. Event handlers, in the
+// Attributes map or in OnClick, and refs are not part of the output. The UnsafeInnerHTML of
+// an element is written as is. A nil tree renders as the empty string.
+//
+// It fails when the tree cannot be written as HTML: an element name that is not valid, a
+// void element (<input>, <img>, ...) with children or text, or a tree nested deeper than
+// vdom.MaxDepth.
+func RenderToString(n *vdom.VNode) (string, error) {
+	if err := vdom.CheckDepth(n); err != nil {
+		return "", err
+	}
+	var err error
+	vdom.Walk(n, func(node *vdom.VNode, depth int) bool {
+		if err == nil {
+			err = check(node, depth)
+		}
+		return err == nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return vdom.RenderToString(n), nil
+}
+
+// check reports why node, at depth, cannot be written as HTML.
+func check(node *vdom.VNode, depth int) error {
+	if node.Tag == "#text" {
+		return nil
+	}
+	if !tagNameRegex.MatchString(node.Tag) {
+		return fmt.Errorf("htmlrender: invalid element name %q at depth %d", node.Tag, depth)
+	}
+	tag := strings.ToLower(node.Tag)
+	if !voidElements[tag] {
+		return nil
+	}
+	// The content of an <input> is its value, written as the value attribute
+	if len(node.Children) > 0 || node.UnsafeInnerHTML != "" || (node.Content != "" && tag != "input") {
+		return fmt.Errorf("htmlrender: void element <%s> at depth %d cannot have content", tag, depth)
+	}
+	return nil
+}
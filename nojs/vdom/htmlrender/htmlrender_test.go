@@ -0,0 +1,125 @@
+//go:build !wasm
+// +build !wasm
+
+package htmlrender
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// TestRenderToString_Escaping verifies text, attribute values and URLs are escaped for their
+// context, so bound values cannot inject markup or script.
+func TestRenderToString_Escaping(t *testing.T) {
+	cases := []struct {
+		name string
+		tree *vdom.VNode
+		want string
+	}{
+		{
+			name: "text",
+			tree: vdom.Paragraph(`</p><script>alert("x")</script> & co`, nil),
+			want: `<p>&lt;/p&gt;&lt;script&gt;alert("x")&lt;/script&gt; &amp; co</p>`,
+		},
+		{
+			name: "text node",
+			tree: vdom.Div(nil, vdom.Text("1 < 2 > 0")),
+			want: `<div>1 &lt; 2 &gt; 0</div>`,
+		},
+		{
+			name: "attribute",
+			tree: vdom.Div(map[string]any{"title": `"><img src=x onerror=alert(1)>`, "data-n": 42}),
+			want: `<div data-n="42" title="&#34;&gt;&lt;img src=x onerror=alert(1)&gt;"></div>`,
+		},
+		{
+			name: "url",
+			tree: vdom.NewVNode("a", map[string]any{"href": "javascript:alert(1)"}, nil, "Home"),
+			want: `<a href="` + vdom.UnsafeURL + `">Home</a>`,
+		},
+		{
+			name: "input value",
+			tree: vdom.InputText(map[string]any{"placeholder": "a & b"}),
+			want: `<input placeholder="a &amp; b" type="text">`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			got, err := RenderToString(tc.tree)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("expected\n%s\ngot\n%s", tc.want, got)
+			}
+		})
+	}
+}
+
+// TestRenderToString_AttributesAndHandlers verifies boolean attributes follow HTML semantics
+// and event handlers are left out.
+func TestRenderToString_AttributesAndHandlers(t *testing.T) {
+	// Arrange
+	tree := vdom.NewVNode("button", map[string]any{
+		"disabled": true,
+		"hidden":   false,
+		"onClick":  func(any) {},
+		"onInput":  func() {},
+		"class":    "btn",
+	}, nil, "Save")
+	tree.OnClick = func() {}
+
+	// Act
+	got, err := RenderToString(tree)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `<button class="btn" disabled>Save</button>`; got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+// TestRenderToString_InvalidTrees verifies trees that cannot be written as HTML are rejected.
+func TestRenderToString_InvalidTrees(t *testing.T) {
+	cases := map[string]*vdom.VNode{
+		"invalid element name":  vdom.Div(nil, vdom.NewVNode("my tag", nil, nil, "")),
+		"void element children": vdom.NewVNode("img", nil, []*vdom.VNode{vdom.Text("caption")}, ""),
+		"void element text":     vdom.NewVNode("br", nil, nil, "text"),
+	}
+	for name, tree := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			got, err := RenderToString(tree)
+
+			// Assert
+			if err == nil || !strings.HasPrefix(err.Error(), "htmlrender: ") {
+				t.Errorf("expected an htmlrender error, got %q and %v", got, err)
+			}
+		})
+	}
+}
+
+// TestRenderToString_TooDeep verifies a tree nested beyond vdom.MaxDepth is rejected with a
+// *vdom.DepthError.
+func TestRenderToString_TooDeep(t *testing.T) {
+	// Arrange
+	vdom.SetMaxDepth(3)
+	defer vdom.SetMaxDepth(vdom.DefaultMaxDepth)
+	tree := vdom.Div(nil, vdom.Div(nil, vdom.Div(nil, vdom.Div(nil))))
+
+	// Act
+	_, err := RenderToString(tree)
+
+	// Assert
+	var depthErr *vdom.DepthError
+	if !errors.As(err, &depthErr) {
+		t.Errorf("expected a *vdom.DepthError, got %v", err)
+	}
+}
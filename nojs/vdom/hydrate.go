@@ -0,0 +1,232 @@
+package vdom
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// hydrationMismatch is a node whose pre-rendered DOM differs from the VNode rendered for it.
+type hydrationMismatch struct {
+	Path   string // Trail of the VNode, as in DepthError
+	Reason string
+}
+
+func (m *hydrationMismatch) Error() string {
+	return "hydration mismatch at " + m.Path + ": " + m.Reason
+}
+
+// hydrator adopts the DOM of a pre-rendered page as the DOM of a VNode tree: it walks both
+// in parallel and keeps every node whose kind and tag match, updating its attributes and
+// text where they differ. A node of another kind or tag is replaced, and the children of an
+// element whose number of child nodes differs are created anew, as the browser merges
+// adjacent text nodes the renderer keeps apart. Every difference is reported as a mismatch.
+// T is the platform node type (js.Value in the browser); the hydrator itself has no build
+// tags so it can be tested natively.
+type hydrator[T any] struct {
+	childNodes      func(el T) []T                                               // Child nodes of el, comments left out
+	textOf          func(node T) (string, bool)                                  // Data of a text node; false for an element
+	tagOf           func(el T) string                                            // Tag name of an element
+	attrsOf         func(el T) map[string]string                                 // Attributes of an element, by name
+	adopt           func(el T, n *VNode, patches []attrPatch)                    // Makes el the DOM of n: patches, listeners, ref and field value
+	setText         func(node T, text string)                                    // Sets the data of a text node or the textContent of an element
+	replace         func(node T, n *VNode, depth int, parent *vnodeTrail)        // Replaces node with the DOM created for n
+	rebuild         func(el T, children []*VNode, depth int, parent *vnodeTrail) // Replaces the child nodes of el with the DOM created for children
+	finish          func(el T, n *VNode)                                         // Runs once the children of n are hydrated
+	onMismatch      func(m *hydrationMismatch)
+	onDepthExceeded func(err *DepthError) // Called once for the first node beyond MaxDepth
+}
+
+// hydrationTask is a DOM node to adopt for a VNode, or an after func to run.
+type hydrationTask[T any] struct {
+	node  T
+	vnode *VNode
+	depth int
+	trail *vnodeTrail // Trail of the parent of vnode
+	after func()
+}
+
+// hydrate adopts node, which sits at depth below parent, as the DOM of n and then, in
+// document order, the child nodes below it. It walks with an explicit stack, so deep trees
+// cannot overflow the stack; nodes beyond MaxDepth are left as the server rendered them.
+func (h *hydrator[T]) hydrate(node T, n *VNode, depth int, parent *vnodeTrail) {
+	pending := []hydrationTask[T]{{node: node, vnode: n, depth: depth, trail: parent}}
+	warned := false
+	for len(pending) > 0 {
+		task := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if task.after != nil {
+			task.after()
+			continue
+		}
+		if exceedsMaxDepth(task.depth) {
+			if !warned {
+				trail := &vnodeTrail{node: task.vnode, parent: task.trail}
+				h.onDepthExceeded(&DepthError{Depth: task.depth, Limit: MaxDepth(), Path: trail.path()})
+				warned = true
+			}
+			continue
+		}
+		children := h.hydrateNode(task)
+		// Push in reverse so siblings are hydrated in document order
+		for i := len(children) - 1; i >= 0; i-- {
+			pending = append(pending, children[i])
+		}
+	}
+}
+
+// hydrateNode adopts task.node for task.vnode and returns the child nodes to adopt next.
+func (h *hydrator[T]) hydrateNode(task hydrationTask[T]) []hydrationTask[T] {
+	node, n := task.node, task.vnode
+	trail := &vnodeTrail{node: n, parent: task.trail}
+	text, isText := h.textOf(node)
+
+	if n.Tag == "#text" {
+		switch {
+		case !isText:
+			h.mismatch(trail, fmt.Sprintf("expected text, found <%s>", h.tagOf(node)))
+			h.replace(node, n, task.depth, task.trail)
+		case text != n.Content:
+			h.mismatch(trail, fmt.Sprintf("expected text %q, found %q", n.Content, text))
+			h.setText(node, n.Content)
+		}
+		return nil
+	}
+	if isText || !strings.EqualFold(h.tagOf(node), n.Tag) {
+		found := "text"
+		if !isText {
+			found = "<" + h.tagOf(node) + ">"
+		}
+		h.mismatch(trail, fmt.Sprintf("expected <%s>, found %s", n.Tag, found))
+		h.replace(node, n, task.depth, task.trail)
+		return nil
+	}
+
+	patches := hydrationAttrPatches(n, h.attrsOf(node))
+	if len(patches) > 0 {
+		keys := make([]string, len(patches))
+		for i, p := range patches {
+			keys[i] = p.Key
+		}
+		h.mismatch(trail, "attributes differ: "+strings.Join(keys, ", "))
+	}
+	h.adopt(node, n, patches)
+
+	switch {
+	case n.Tag == "input" || n.Tag == "textarea" || n.UnsafeInnerHTML != "":
+		// The value is a property adopt sets; trusted markup is kept as the browser parsed it
+		return nil
+	case len(n.Children) == 0 && n.Tag != "select":
+		nodes := h.childNodes(node)
+		current := ""
+		if len(nodes) == 1 {
+			current, _ = h.textOf(nodes[0])
+		}
+		if len(nodes) > 1 || current != n.Content {
+			h.mismatch(trail, fmt.Sprintf("expected text %q", n.Content))
+			h.setText(node, n.Content)
+		}
+		return nil
+	}
+
+	expected := hydrationChildren(n)
+	nodes := h.childNodes(node)
+	var tasks []hydrationTask[T]
+	if len(nodes) != len(expected) {
+		h.mismatch(trail, fmt.Sprintf("expected %d child nodes, found %d", len(expected), len(nodes)))
+		h.rebuild(node, expected, task.depth+1, trail)
+	} else {
+		for i, child := range expected {
+			tasks = append(tasks, hydrationTask[T]{node: nodes[i], vnode: child, depth: task.depth + 1, trail: trail})
+		}
+	}
+	if h.finish != nil {
+		// Last in the list, so it runs once every child is hydrated
+		tasks = append(tasks, hydrationTask[T]{after: func() { h.finish(node, n) }})
+	}
+	return tasks
+}
+
+// mismatch reports that the node at trail differs from its pre-rendered DOM.
+func (h *hydrator[T]) mismatch(trail *vnodeTrail, reason string) {
+	if h.onMismatch != nil {
+		h.onMismatch(&hydrationMismatch{Path: trail.path(), Reason: reason})
+	}
+}
+
+// hydrationChildren returns the VNodes standing for the child nodes of the element n in the
+// DOM: its Content as a text node, when it has both text and children, then each child that
+// produces a node. nil children and empty text nodes produce none.
+func hydrationChildren(n *VNode) []*VNode {
+	var children []*VNode
+	if n.Content != "" && n.Tag != "select" {
+		children = append(children, Text(n.Content))
+	}
+	for _, child := range n.Children {
+		if child == nil || (child.Tag == "#text" && child.Content == "") {
+			continue
+		}
+		children = append(children, child)
+	}
+	return children
+}
+
+// hydrationAttrPatches returns the updates turning dom, the attributes the server rendered
+// for the element of n, into the attributes the DOM renderer sets for n, sorted by key.
+// Attributes the server adds in place of a property are left alone: the value of an
+// <input> and the selected mark of an <option>. HTML parsers lower-case attribute names,
+// so a key of n missing from dom is looked up in lower case too.
+func hydrationAttrPatches(n *VNode, dom map[string]string) []attrPatch {
+	want := make(map[string]any, len(n.Attributes))
+	for key, value := range n.Attributes {
+		if isEventAttribute(key) || value == nil || reflect.TypeOf(value).Kind() == reflect.Func {
+			continue
+		}
+		if n.Tag == "select" && (key == selectValueAttr || key == selectFallbackAttr) {
+			continue
+		}
+		want[key] = value
+	}
+
+	var patches []attrPatch
+	for key := range dom {
+		if (n.Tag == "input" && key == "value") || (n.Tag == "option" && key == "selected") {
+			continue
+		}
+		if _, ok := want[key]; !ok && !hasKeyFold(want, key) {
+			patches = append(patches, attrPatch{Key: key, Remove: true})
+		}
+	}
+	for key, value := range want {
+		current, present := dom[key]
+		if !present {
+			current, present = dom[strings.ToLower(key)]
+		}
+		var same bool
+		switch v := value.(type) {
+		case bool:
+			same = v == present
+		case string:
+			same = present && current == DOMValue(ContextOf(key), v)
+		default:
+			same = present && current == fmt.Sprint(v)
+		}
+		if !same {
+			patches = append(patches, attrPatch{Key: key, Value: value, Remove: value == false})
+		}
+	}
+	sort.Slice(patches, func(i, j int) bool { return patches[i].Key < patches[j].Key })
+	return patches
+}
+
+// hasKeyFold reports whether m has a key equal to key under case folding.
+func hasKeyFold(m map[string]any, key string) bool {
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
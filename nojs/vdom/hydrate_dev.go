@@ -0,0 +1,13 @@
+//go:build (js || wasm) && dev
+// +build js wasm
+// +build dev
+
+package vdom
+
+import "github.com/ForgeLogic/nojs/console"
+
+// warnHydrationMismatch reports a pre-rendered node that differs from its VNode in
+// development mode.
+func warnHydrationMismatch(m *hydrationMismatch) {
+	console.Warn(m.Error() + "; the node was re-rendered. Render the same tree on the server and in the browser to keep the pre-rendered DOM.")
+}
@@ -0,0 +1,8 @@
+//go:build (js || wasm) && !dev
+// +build js wasm
+// +build !dev
+
+package vdom
+
+// warnHydrationMismatch is a no-op in production mode; the node is fixed silently.
+func warnHydrationMismatch(m *hydrationMismatch) {}
@@ -0,0 +1,177 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"strings"
+	"testing"
+)
+
+// preNode is a pre-rendered DOM node for hydrator tests: an element, or a text node when tag is empty.
+type preNode struct {
+	tag      string
+	text     string
+	attrs    map[string]string
+	children []*preNode
+	adopted  *VNode // The VNode adopt made this node the DOM of
+	created  bool   // Created by replace or rebuild rather than pre-rendered
+}
+
+// fakeHydrator returns a hydrator over fakeNodes that records mismatches.
+func fakeHydrator(mismatches *[]string) *hydrator[*preNode] {
+	var create func(n *VNode) *preNode
+	create = func(n *VNode) *preNode {
+		if n.Tag == "#text" {
+			return &preNode{text: n.Content, created: true}
+		}
+		el := &preNode{tag: n.Tag, text: n.Content, created: true}
+		for _, child := range n.Children {
+			el.children = append(el.children, create(child))
+		}
+		return el
+	}
+	return &hydrator[*preNode]{
+		childNodes: func(el *preNode) []*preNode { return el.children },
+		textOf:     func(node *preNode) (string, bool) { return node.text, node.tag == "" },
+		tagOf:      func(el *preNode) string { return el.tag },
+		attrsOf:    func(el *preNode) map[string]string { return el.attrs },
+		adopt: func(el *preNode, n *VNode, patches []attrPatch) {
+			el.adopted = n
+			for _, p := range patches {
+				if p.Remove {
+					delete(el.attrs, p.Key)
+				} else {
+					el.attrs[p.Key] = DOMValue(ContextOf(p.Key), p.Value.(string))
+				}
+			}
+		},
+		setText: func(node *preNode, text string) {
+			node.text = text
+			if node.tag != "" {
+				node.children = []*preNode{{text: text}}
+			}
+		},
+		replace: func(node *preNode, n *VNode, depth int, parent *vnodeTrail) { *node = *create(n) },
+		rebuild: func(el *preNode, children []*VNode, depth int, parent *vnodeTrail) {
+			el.children = nil
+			for _, child := range children {
+				el.children = append(el.children, create(child))
+			}
+		},
+		onMismatch:      func(m *hydrationMismatch) { *mismatches = append(*mismatches, m.Error()) },
+		onDepthExceeded: func(err *DepthError) {},
+	}
+}
+
+// preEl builds a pre-rendered element.
+func preEl(tag string, attrs map[string]string, children ...*preNode) *preNode {
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	return &preNode{tag: tag, attrs: attrs, children: children}
+}
+
+// preText builds a pre-rendered text node.
+func preText(s string) *preNode { return &preNode{text: s} }
+
+// TestHydrate_AdoptsMatchingTree verifies a tree matching its pre-rendered DOM adopts every
+// element without a mismatch, handlers and server-only attributes included.
+func TestHydrate_AdoptsMatchingTree(t *testing.T) {
+	// Arrange
+	var mismatches []string
+	clicked := false
+	button := NewVNode("button", map[string]any{"class": "buy", "disabled": false, "onClick": func() { clicked = true }}, nil, "Buy")
+	input := NewVNode("input", map[string]any{"type": "text"}, nil, "42")
+	link := NewVNode("a", map[string]any{"href": "javascript:alert(1)"}, []*VNode{Text("Home")}, "")
+	tree := Div(map[string]any{"id": "page"}, button, input, link)
+	dom := preEl("div", map[string]string{"id": "page"},
+		preEl("button", map[string]string{"class": "buy"}, preText("Buy")),
+		preEl("input", map[string]string{"type": "text", "value": "42"}),
+		preEl("a", map[string]string{"href": UnsafeURL}, preText("Home")),
+	)
+
+	// Act
+	fakeHydrator(&mismatches).hydrate(dom, tree, 1, nil)
+
+	// Assert
+	if len(mismatches) != 0 {
+		t.Fatalf("expected no mismatch, got %v", mismatches)
+	}
+	if dom.adopted != tree || dom.children[0].adopted != button || dom.children[2].adopted != link {
+		t.Error("expected every element adopted for its VNode")
+	}
+	if dom.children[0].created || clicked {
+		t.Error("expected the pre-rendered button to be kept")
+	}
+}
+
+// TestHydrate_FixesDifferencesWithPaths verifies differing text and attributes are fixed in
+// place, a node of another tag is replaced, and each difference names the path to the node.
+func TestHydrate_FixesDifferencesWithPaths(t *testing.T) {
+	// Arrange
+	var mismatches []string
+	tree := Div(map[string]any{"id": "page"},
+		NewVNode("h1", map[string]any{"class": "title"}, nil, "Cart (2)"),
+		NewVNode("ul", nil, []*VNode{NewVNode("li", nil, nil, "Tea")}, ""),
+	)
+	dom := preEl("div", map[string]string{"id": "page"},
+		preEl("h1", map[string]string{"class": "heading", "data-stale": ""}, preText("Cart (1)")),
+		preEl("ol", nil, preEl("li", nil, preText("Tea"))),
+	)
+
+	// Act
+	fakeHydrator(&mismatches).hydrate(dom, tree, 1, nil)
+
+	// Assert
+	want := []string{
+		"hydration mismatch at div#page > h1.title: attributes differ: class, data-stale",
+		`hydration mismatch at div#page > h1.title: expected text "Cart (2)"`,
+		"hydration mismatch at div#page > ul: expected <ul>, found <ol>",
+	}
+	if strings.Join(mismatches, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected mismatches:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(mismatches, "\n"))
+	}
+	h1 := dom.children[0]
+	if h1.created || h1.attrs["class"] != "title" || len(h1.attrs) != 1 || h1.text != "Cart (2)" {
+		t.Errorf("expected the heading fixed in place, got %+v", h1)
+	}
+	if list := dom.children[1]; !list.created || list.tag != "ul" {
+		t.Errorf("expected the list replaced, got %+v", list)
+	}
+}
+
+// TestHydrate_RebuildsChildrenWhenCountsDiffer verifies an element whose child nodes do not
+// line up with its children, as when the browser merged two text nodes, gets new children.
+func TestHydrate_RebuildsChildrenWhenCountsDiffer(t *testing.T) {
+	// Arrange
+	var mismatches []string
+	tree := NewVNode("p", nil, []*VNode{Text("Hello, "), Text("Ada"), NewVNode("em", nil, nil, "!")}, "")
+	dom := preEl("p", nil, preText("Hello, Ada"), preEl("em", nil, preText("!")))
+
+	// Act
+	fakeHydrator(&mismatches).hydrate(dom, tree, 1, nil)
+
+	// Assert
+	if len(mismatches) != 1 || !strings.Contains(mismatches[0], "expected 3 child nodes, found 2") {
+		t.Errorf("expected one mismatch for the child count, got %v", mismatches)
+	}
+	if len(dom.children) != 3 || !dom.children[0].created || dom.children[1].text != "Ada" {
+		t.Errorf("expected the children created anew, got %+v", dom.children)
+	}
+}
+
+// TestHydrationAttrPatches_BooleansAndCase verifies boolean attributes compare by presence
+// and names the HTML parser lower-cased still match.
+func TestHydrationAttrPatches_BooleansAndCase(t *testing.T) {
+	// Arrange
+	n := NewVNode("input", map[string]any{"checked": true, "readonly": false, "tabIndex": 3}, nil, "")
+
+	// Act
+	patches := hydrationAttrPatches(n, map[string]string{"checked": "", "readonly": "", "tabindex": "3"})
+
+	// Assert
+	if len(patches) != 1 || patches[0].Key != "readonly" || !patches[0].Remove {
+		t.Errorf("expected only readonly removed, got %+v", patches)
+	}
+}
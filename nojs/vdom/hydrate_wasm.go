@@ -0,0 +1,197 @@
+//go:build js || wasm
+// +build js wasm
+
+package vdom
+
+import (
+	"strings"
+	"syscall/js"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+// DOM node types, as in Node.nodeType.
+const (
+	textNodeType    = 3
+	commentNodeType = 8
+)
+
+// Hydrate adopts the markup under the first element matching selector, typically a page
+// pre-rendered at build time, as the DOM of n instead of recreating it: matching elements
+// keep their place, and get n's event listeners and refs. Nodes that differ are fixed in
+// place or replaced, and dev builds warn about each one with the path to it. Afterwards n
+// is the mounted tree, patched by Patch like one mounted by RenderToSelector.
+//
+// It reports false, leaving the mount element untouched, when the element does not exist or
+// holds no markup (only whitespace or comments); the caller then renders n afresh.
+func Hydrate(selector string, n *VNode) bool {
+	if n == nil || selector == "" {
+		return false
+	}
+	doc := js.Global().Get("document")
+	if !doc.Truthy() {
+		return false
+	}
+	mount := doc.Call("querySelector", selector)
+	if !mount.Truthy() {
+		console.Error("Mount element not found for selector:", selector)
+		return false
+	}
+
+	// Whitespace and comments around the pre-rendered root are left by the HTML shell
+	var roots []js.Value
+	for _, node := range nodeList(mount.Get("childNodes")) {
+		if isBlank(node) {
+			mount.Call("removeChild", node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	if len(roots) == 0 {
+		return false
+	}
+	if len(roots) > 1 {
+		warnHydrationMismatch(&hydrationMismatch{Path: describeNode(n), Reason: "the mount element holds several root nodes"})
+		mount.Set("innerHTML", "")
+		RenderTo(mount, n)
+		return true
+	}
+
+	h := &hydrator[js.Value]{
+		childNodes:      hydrationChildNodes,
+		textOf:          textOfNode,
+		tagOf:           func(el js.Value) string { return el.Get("localName").String() },
+		attrsOf:         attributesOf,
+		adopt:           adoptElement,
+		setText:         setNodeText,
+		replace:         replaceNode,
+		rebuild:         rebuildChildren,
+		finish:          finishMountNode,
+		onMismatch:      warnHydrationMismatch,
+		onDepthExceeded: warnDepthExceeded,
+	}
+	h.hydrate(roots[0], n, 1, nil)
+	return true
+}
+
+// HasMarkup reports whether the first element matching selector holds markup besides
+// whitespace and comments, such as a pre-rendered page to Hydrate.
+func HasMarkup(selector string) bool {
+	doc := js.Global().Get("document")
+	if selector == "" || !doc.Truthy() {
+		return false
+	}
+	mount := doc.Call("querySelector", selector)
+	if !mount.Truthy() {
+		return false
+	}
+	for _, node := range nodeList(mount.Get("childNodes")) {
+		if !isBlank(node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeList copies a NodeList, which is live, into a slice.
+func nodeList(list js.Value) []js.Value {
+	nodes := make([]js.Value, list.Length())
+	for i := range nodes {
+		nodes[i] = list.Index(i)
+	}
+	return nodes
+}
+
+// isBlank reports whether node is a comment or a text node holding only whitespace.
+func isBlank(node js.Value) bool {
+	switch node.Get("nodeType").Int() {
+	case commentNodeType:
+		return true
+	case textNodeType:
+		return strings.TrimSpace(node.Get("data").String()) == ""
+	}
+	return false
+}
+
+// hydrationChildNodes returns the child nodes of el, removing its comments: the patcher
+// finds elements by their position among the child nodes.
+func hydrationChildNodes(el js.Value) []js.Value {
+	var nodes []js.Value
+	for _, node := range nodeList(el.Get("childNodes")) {
+		if node.Get("nodeType").Int() == commentNodeType {
+			el.Call("removeChild", node)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// textOfNode returns the data of a text node; false for an element.
+func textOfNode(node js.Value) (string, bool) {
+	if node.Get("nodeType").Int() != textNodeType {
+		return "", false
+	}
+	return node.Get("data").String(), true
+}
+
+// attributesOf returns the attributes of el by name.
+func attributesOf(el js.Value) map[string]string {
+	attrs := el.Get("attributes")
+	values := make(map[string]string, attrs.Length())
+	for i := 0; i < attrs.Length(); i++ {
+		attr := attrs.Index(i)
+		values[attr.Get("name").String()] = attr.Get("value").String()
+	}
+	return values
+}
+
+// adoptElement makes el the DOM of n, as createDOMNode would have created it: the attribute
+// patches are applied, the listeners attached and the ref pointed at el, and a form field
+// shows n's Content as its value unless it has the focus: what the user typed before the
+// module started is kept.
+func adoptElement(el js.Value, n *VNode, patches []attrPatch) {
+	patchAttributes(el, patches)
+	for key, value := range n.Attributes {
+		if n.Tag != "select" || (key != selectValueAttr && key != selectFallbackAttr) {
+			syncControlState(el, key, value)
+		}
+	}
+	attachEventListeners(el, n)
+	if n.Ref != nil {
+		n.Ref.attach(n, el)
+	}
+	if (n.Tag == "input" || n.Tag == "textarea") && n.Content != "" && el.Get("value").String() != n.Content && !el.Call("matches", ":focus").Bool() {
+		el.Set("value", n.Content)
+	}
+}
+
+// setNodeText sets the data of a text node or the textContent of an element.
+func setNodeText(node js.Value, text string) {
+	if node.Get("nodeType").Int() == textNodeType {
+		node.Set("data", text)
+		return
+	}
+	node.Set("textContent", text)
+}
+
+// replaceNode replaces node with the DOM created for n, which sits at depth below parent.
+func replaceNode(node js.Value, n *VNode, depth int, parent *vnodeTrail) {
+	el := createElementAt(n, depth, parent)
+	if el.Truthy() {
+		node.Get("parentNode").Call("replaceChild", el, node)
+	} else {
+		node.Call("remove")
+	}
+}
+
+// rebuildChildren replaces the child nodes of el with the DOM created for children, which
+// sit at depth below parent.
+func rebuildChildren(el js.Value, children []*VNode, depth int, parent *vnodeTrail) {
+	el.Set("textContent", "")
+	for _, child := range children {
+		if node := createElementAt(child, depth, parent); node.Truthy() {
+			el.Call("appendChild", node)
+		}
+	}
+}
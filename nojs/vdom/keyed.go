@@ -0,0 +1,160 @@
+package vdom
+
+import "reflect"
+
+// Children are patched by key when every child on both sides carries one: Key, set on the
+// element a {@for ... trackBy} iteration renders, or else ComponentKey. An old and a new
+// child with the same key share a DOM node, which is moved rather than re-patched from
+// another child's node, so inserting an item at the top of a list creates one node and
+// leaves the focus, scroll position and input state of the others alone. Any unkeyed
+// child, or a key used twice on a side, falls back to patching by position.
+
+// keyedOpKind says what happens to the DOM node of a new child in a keyed patch.
+type keyedOpKind int
+
+const (
+	// keyedKeep patches the old child's node where it is.
+	keyedKeep keyedOpKind = iota
+	// keyedMove patches the old child's node and moves it into place.
+	keyedMove
+	// keyedCreate creates a node for a child no old child has the key of.
+	keyedCreate
+)
+
+// keyedOp is the step of a keyed patch for the new child at New.
+type keyedOp struct {
+	Kind keyedOpKind
+	New  int // Index in the new children
+	Old  int // Index of the old child with the same key; -1 for keyedCreate
+}
+
+// keyedPlan is the keyed patch of a list of children. Removed old nodes go first; the Ops
+// then run from the last new child to the first, each placing its node before the node
+// placed after it (a nil children entry has no node and no op). Moved and created nodes are
+// inserted that way, while kept nodes already sit in order: they are the longest run of
+// old nodes whose order the new children keep.
+type keyedPlan struct {
+	Removed []int // Old children without a new child of the same key
+	Ops     []keyedOp
+}
+
+// reconciliationKey returns the key child is matched by among its siblings.
+func reconciliationKey(child *VNode) (any, bool) {
+	if child.Key != nil {
+		return child.Key, reflect.TypeOf(child.Key).Comparable()
+	}
+	return child.ComponentKey, child.ComponentKey != ""
+}
+
+// planKeyedChildren computes the keyed patch of oldChildren into newChildren. ok is false
+// when the children are not all uniquely keyed and must be patched by position.
+func planKeyedChildren(oldChildren, newChildren []*VNode) (plan keyedPlan, ok bool) {
+	oldIndex, ok := indexByKey(oldChildren)
+	if !ok || len(oldIndex) == 0 {
+		return keyedPlan{}, false
+	}
+	newIndex, ok := indexByKey(newChildren)
+	if !ok || len(newIndex) == 0 {
+		return keyedPlan{}, false
+	}
+
+	for i, child := range oldChildren {
+		if child == nil {
+			continue
+		}
+		key, _ := reconciliationKey(child)
+		if _, kept := newIndex[key]; !kept {
+			plan.Removed = append(plan.Removed, i)
+		}
+	}
+
+	// Old positions of the new children that reuse a node, in new order; the longest
+	// increasing run of them stays in place
+	var reused []int
+	for _, child := range newChildren {
+		if child == nil {
+			continue
+		}
+		key, _ := reconciliationKey(child)
+		if j, found := oldIndex[key]; found {
+			reused = append(reused, j)
+		}
+	}
+	stays := make(map[int]bool, len(reused))
+	for _, k := range longestIncreasing(reused) {
+		stays[reused[k]] = true
+	}
+
+	for i := len(newChildren) - 1; i >= 0; i-- {
+		if newChildren[i] == nil {
+			continue
+		}
+		key, _ := reconciliationKey(newChildren[i])
+		j, found := oldIndex[key]
+		switch {
+		case !found:
+			plan.Ops = append(plan.Ops, keyedOp{Kind: keyedCreate, New: i, Old: -1})
+		case stays[j]:
+			plan.Ops = append(plan.Ops, keyedOp{Kind: keyedKeep, New: i, Old: j})
+		default:
+			plan.Ops = append(plan.Ops, keyedOp{Kind: keyedMove, New: i, Old: j})
+		}
+	}
+	return plan, true
+}
+
+// indexByKey maps the key of each non-nil child to its index. ok is false when a child has
+// no key or shares its key with another.
+func indexByKey(children []*VNode) (index map[any]int, ok bool) {
+	index = make(map[any]int, len(children))
+	for i, child := range children {
+		if child == nil {
+			continue
+		}
+		key, keyed := reconciliationKey(child)
+		if !keyed {
+			return nil, false
+		}
+		if _, duplicate := index[key]; duplicate {
+			return nil, false
+		}
+		index[key] = i
+	}
+	return index, true
+}
+
+// longestIncreasing returns the positions in s of a longest strictly increasing
+// subsequence of s, in order.
+func longestIncreasing(s []int) []int {
+	var tails []int // tails[l] is the position ending the best run of length l+1 found so far
+	prev := make([]int, len(s))
+	for i, v := range s {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if s[tails[mid]] < v {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		prev[i] = -1
+		if lo > 0 {
+			prev[i] = tails[lo-1]
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
+		}
+	}
+	run := make([]int, len(tails))
+	if len(tails) == 0 {
+		return run
+	}
+	for k, i := len(tails)-1, tails[len(tails)-1]; k >= 0; k-- {
+		run[k] = i
+		i = prev[i]
+	}
+	return run
+}
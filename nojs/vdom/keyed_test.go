@@ -0,0 +1,188 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// keyedItems returns <li> nodes keyed by keys; a 0 key stands for a nil child.
+func keyedItems(keys ...int) []*VNode {
+	items := make([]*VNode, len(keys))
+	for i, key := range keys {
+		if key != 0 {
+			items[i] = &VNode{Tag: "li", Key: key}
+		}
+	}
+	return items
+}
+
+// applyKeyedPlan carries out plan on a fake DOM holding the keys of oldChildren in order,
+// as patchKeyedChildren does on the real one, and returns the keys it ends with.
+func applyKeyedPlan(oldChildren, newChildren []*VNode, plan keyedPlan) []any {
+	var dom []any
+	for _, child := range oldChildren {
+		if child != nil {
+			dom = append(dom, child.Key)
+		}
+	}
+	for _, i := range plan.Removed {
+		dom = slices.DeleteFunc(dom, func(key any) bool { return key == oldChildren[i].Key })
+	}
+	var next any // nil: append
+	for _, op := range plan.Ops {
+		key := newChildren[op.New].Key
+		if op.Kind != keyedKeep {
+			dom = slices.DeleteFunc(dom, func(k any) bool { return k == key })
+			at := len(dom)
+			if next != nil {
+				at = slices.Index(dom, next)
+			}
+			dom = slices.Insert(dom, at, key)
+		}
+		next = key
+	}
+	return dom
+}
+
+// countOps counts the ops of plan of each kind.
+func countOps(plan keyedPlan) (kept, moved, created int) {
+	for _, op := range plan.Ops {
+		switch op.Kind {
+		case keyedKeep:
+			kept++
+		case keyedMove:
+			moved++
+		case keyedCreate:
+			created++
+		}
+	}
+	return kept, moved, created
+}
+
+// TestPlanKeyedChildren_PrependInsertsOneNode verifies an item added at the top of a keyed
+// list creates one node and keeps every other node where it is.
+func TestPlanKeyedChildren_PrependInsertsOneNode(t *testing.T) {
+	// Arrange
+	old, new := keyedItems(1, 2, 3, 4), keyedItems(9, 1, 2, 3, 4)
+
+	// Act
+	plan, ok := planKeyedChildren(old, new)
+
+	// Assert
+	if !ok {
+		t.Fatal("expected a keyed patch")
+	}
+	kept, moved, created := countOps(plan)
+	if created != 1 || moved != 0 || kept != 4 || len(plan.Removed) != 0 {
+		t.Errorf("expected 1 insertion and 4 kept nodes, got %d created, %d moved, %d kept, %d removed", created, moved, kept, len(plan.Removed))
+	}
+	if got := applyKeyedPlan(old, new, plan); !slices.Equal(got, []any{9, 1, 2, 3, 4}) {
+		t.Errorf("expected the order 9 1 2 3 4, got %v", got)
+	}
+}
+
+// TestPlanKeyedChildren_RemoveAndMove verifies a removed item only removes its node and a
+// moved item moves only its own node.
+func TestPlanKeyedChildren_RemoveAndMove(t *testing.T) {
+	// Arrange
+	old, new := keyedItems(1, 2, 3, 4, 5), keyedItems(5, 1, 3, 4)
+
+	// Act
+	plan, ok := planKeyedChildren(old, new)
+
+	// Assert
+	if !ok {
+		t.Fatal("expected a keyed patch")
+	}
+	if !slices.Equal(plan.Removed, []int{1}) {
+		t.Errorf("expected only item 2 removed, got old indices %v", plan.Removed)
+	}
+	if _, moved, created := countOps(plan); moved != 1 || created != 0 {
+		t.Errorf("expected 1 move and no creation, got %d moved, %d created", moved, created)
+	}
+	if got := applyKeyedPlan(old, new, plan); !slices.Equal(got, []any{5, 1, 3, 4}) {
+		t.Errorf("expected the order 5 1 3 4, got %v", got)
+	}
+}
+
+// TestPlanKeyedChildren_ReachesNewOrder verifies random edits of a keyed list, nil children
+// included, end in the new order.
+func TestPlanKeyedChildren_ReachesNewOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for round := 0; round < 500; round++ {
+		// Arrange
+		oldKeys := rng.Perm(8)[:rng.Intn(8)+1]
+		newKeys := rng.Perm(10)[:rng.Intn(10)+1]
+		old, new := keyedItems(plusOne(oldKeys)...), keyedItems(plusOne(newKeys)...)
+		if rng.Intn(3) == 0 {
+			new = append(new, nil) // An {@if} that rendered nothing
+		}
+
+		// Act
+		plan, ok := planKeyedChildren(old, new)
+
+		// Assert
+		if !ok {
+			t.Fatalf("round %d: expected a keyed patch", round)
+		}
+		var want []any
+		for _, child := range new {
+			if child != nil {
+				want = append(want, child.Key)
+			}
+		}
+		if got := applyKeyedPlan(old, new, plan); !slices.Equal(got, want) {
+			t.Fatalf("round %d: patching %v into %v gave %v", round, oldKeys, newKeys, got)
+		}
+	}
+}
+
+// plusOne shifts keys by one, since keyedItems reserves 0 for nil.
+func plusOne(keys []int) []int {
+	shifted := make([]int, len(keys))
+	for i, key := range keys {
+		shifted[i] = key + 1
+	}
+	return shifted
+}
+
+// TestPlanKeyedChildren_FallsBackToPositions verifies lists with an unkeyed child or a
+// repeated key are left to the positional patch.
+func TestPlanKeyedChildren_FallsBackToPositions(t *testing.T) {
+	unkeyed := append(keyedItems(1, 2), &VNode{Tag: "li"})
+	cases := map[string][2][]*VNode{
+		"unkeyed new child": {keyedItems(1, 2), unkeyed},
+		"unkeyed old child": {unkeyed, keyedItems(1, 2)},
+		"repeated key":      {keyedItems(1, 2), keyedItems(1, 1)},
+		"no children":       {keyedItems(1, 2), nil},
+		"uncomparable key":  {keyedItems(1), {{Tag: "li", Key: []int{1}}}},
+	}
+	for name, c := range cases {
+		if _, ok := planKeyedChildren(c[0], c[1]); ok {
+			t.Errorf("%s: expected a positional patch", name)
+		}
+	}
+}
+
+// TestPlanKeyedChildren_ComponentKeys verifies children keyed only by ComponentKey are
+// matched by it.
+func TestPlanKeyedChildren_ComponentKeys(t *testing.T) {
+	// Arrange
+	old := []*VNode{{Tag: "div", ComponentKey: "a"}, {Tag: "div", ComponentKey: "b"}}
+	new := []*VNode{{Tag: "div", ComponentKey: "b"}, {Tag: "div", ComponentKey: "a"}}
+
+	// Act
+	plan, ok := planKeyedChildren(old, new)
+
+	// Assert
+	if !ok {
+		t.Fatal("expected a keyed patch")
+	}
+	if kept, moved, created := countOps(plan); kept != 1 || moved != 1 || created != 0 {
+		t.Errorf("expected a swap to keep one node and move the other, got %d kept, %d moved, %d created", kept, moved, created)
+	}
+}
@@ -0,0 +1,89 @@
+package vdom
+
+import (
+	"reflect"
+	"sort"
+)
+
+// onClickField is the listener attribute standing for VNode.OnClick, which holds a click
+// handler outside the attributes map.
+const onClickField = "OnClick"
+
+// listenerSpec is an event listener a VNode asks for.
+type listenerSpec struct {
+	Attr  string // Attribute key of the handler ("onClick"), or onClickField
+	Event string // DOM event name ("click")
+	Key   string // Handler identity from HandlerKeys; empty for hand-written handlers
+}
+
+// listenerPatch is the listener update computed by diffListeners. Kept listeners stay on
+// the element and call the new VNode's handler from then on: the handler is a new closure
+// on every render, with the arguments of that render bound in.
+type listenerPatch struct {
+	Keep   []listenerSpec
+	Add    []listenerSpec
+	Remove []listenerSpec
+}
+
+// eventListeners returns the listeners n asks for: one per event handler attribute, in key
+// order, then one for OnClick.
+func eventListeners(n *VNode) []listenerSpec {
+	if n == nil {
+		return nil
+	}
+	var specs []listenerSpec
+	for key, value := range n.Attributes {
+		if isEventAttribute(key) && value != nil && reflect.TypeOf(value).Kind() == reflect.Func {
+			specs = append(specs, listenerSpec{Attr: key, Event: eventName(key), Key: n.HandlerKeys[key]})
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Attr < specs[j].Attr })
+	if n.OnClick != nil {
+		specs = append(specs, listenerSpec{Attr: onClickField, Event: "click"})
+	}
+	return specs
+}
+
+// eventName converts a handler attribute to its DOM event: "onClick" -> "click",
+// "oninput" -> "input".
+func eventName(attr string) string {
+	name := attr[2:]
+	if name[0] >= 'A' && name[0] <= 'Z' {
+		name = string(name[0]+('a'-'A')) + name[1:]
+	}
+	return name
+}
+
+// diffListeners compares the listeners of old and new. A listener is kept when new has a
+// handler under the same attribute, for the same event and with the same handler key;
+// otherwise the old listener is removed and the new one added. It has no DOM dependencies,
+// so the decisions can be tested natively; patchListeners only carries them out.
+func diffListeners(old, new *VNode) listenerPatch {
+	var p listenerPatch
+	oldSpecs := eventListeners(old)
+	kept := make(map[string]bool)
+	for _, spec := range eventListeners(new) {
+		if oldSpec, ok := findListener(oldSpecs, spec.Attr); ok && oldSpec == spec {
+			p.Keep = append(p.Keep, spec)
+			kept[spec.Attr] = true
+		} else {
+			p.Add = append(p.Add, spec)
+		}
+	}
+	for _, spec := range oldSpecs {
+		if !kept[spec.Attr] {
+			p.Remove = append(p.Remove, spec)
+		}
+	}
+	return p
+}
+
+// findListener returns the spec for attr in specs.
+func findListener(specs []listenerSpec, attr string) (listenerSpec, bool) {
+	for _, spec := range specs {
+		if spec.Attr == attr {
+			return spec, true
+		}
+	}
+	return listenerSpec{}, false
+}
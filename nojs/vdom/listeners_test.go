@@ -0,0 +1,113 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"fmt"
+	"testing"
+)
+
+// handlerNode builds a button whose handlers are new closures, as on every render.
+func handlerNode(keys map[string]string, events ...string) *VNode {
+	attrs := map[string]any{"class": "btn"}
+	for _, attr := range events {
+		attrs[attr] = func(any) {}
+	}
+	if keys != nil {
+		attrs[HandlerKeysAttr] = keys
+	}
+	return NewVNode("button", attrs, nil, "Save")
+}
+
+// attrsOf lists the attributes of specs.
+func attrsOf(specs []listenerSpec) string {
+	var attrs []string
+	for _, spec := range specs {
+		attrs = append(attrs, spec.Attr)
+	}
+	return fmt.Sprint(attrs)
+}
+
+// TestNewVNode_HandlerKeysLeaveAttributes verifies the handler keys are moved out of the
+// attributes map, so they are never rendered.
+func TestNewVNode_HandlerKeysLeaveAttributes(t *testing.T) {
+	// Act
+	n := handlerNode(map[string]string{"onClick": "Form.Save"}, "onClick")
+
+	// Assert
+	if _, ok := n.Attributes[HandlerKeysAttr]; ok {
+		t.Errorf("expected %s to be removed from the attributes", HandlerKeysAttr)
+	}
+	if got := n.HandlerKeys["onClick"]; got != "Form.Save" {
+		t.Errorf("expected the handler key Form.Save, got %q", got)
+	}
+}
+
+// TestDiffListeners_SameKeysAreKept verifies a re-render with the same handlers keeps every
+// listener, although each handler is a new closure.
+func TestDiffListeners_SameKeysAreKept(t *testing.T) {
+	// Arrange
+	keys := map[string]string{"onClick": "Form.Save", "onMouseover": "Form.Hint"}
+	old := handlerNode(keys, "onClick", "onMouseover")
+	new := handlerNode(keys, "onClick", "onMouseover")
+
+	// Act
+	p := diffListeners(old, new)
+
+	// Assert
+	if got := attrsOf(p.Keep); got != "[onClick onMouseover]" {
+		t.Errorf("expected both listeners to be kept, got %s", got)
+	}
+	if len(p.Add) != 0 || len(p.Remove) != 0 {
+		t.Errorf("expected nothing added or removed, got %+v", p)
+	}
+}
+
+// TestDiffListeners_ChangedKeyIsReplaced verifies a handler bound to another method, or to
+// another event, replaces its listener, and that handlers are added and removed with their
+// attributes.
+func TestDiffListeners_ChangedKeyIsReplaced(t *testing.T) {
+	// Arrange
+	old := handlerNode(map[string]string{"onClick": "Form.Edit", "onFocus": "Form.Hint"}, "onClick", "onFocus")
+	new := handlerNode(map[string]string{"onClick": "Form.Save", "onBlur": "Form.Hint"}, "onClick", "onBlur")
+
+	// Act
+	p := diffListeners(old, new)
+
+	// Assert
+	if len(p.Keep) != 0 {
+		t.Errorf("expected no listener to be kept, got %s", attrsOf(p.Keep))
+	}
+	if got := attrsOf(p.Add); got != "[onBlur onClick]" {
+		t.Errorf("expected onBlur and onClick to be added, got %s", got)
+	}
+	if got := attrsOf(p.Remove); got != "[onClick onFocus]" {
+		t.Errorf("expected onClick and onFocus to be removed, got %s", got)
+	}
+	if p.Add[0].Event != "blur" {
+		t.Errorf("expected onBlur to listen to blur, got %q", p.Add[0].Event)
+	}
+}
+
+// TestDiffListeners_HandWrittenHandlers verifies handlers without keys, and OnClick, are kept
+// while they stay, and that non-function event attributes are no listeners.
+func TestDiffListeners_HandWrittenHandlers(t *testing.T) {
+	// Arrange
+	old := NewVNode("a", map[string]any{"oninput": func(any) {}, "onclick": "return false", "onClick": func() {}}, nil, "")
+	new := NewVNode("a", map[string]any{"oninput": func(any) {}, "onclick": "return false"}, nil, "")
+
+	// Act
+	p := diffListeners(old, new)
+
+	// Assert
+	if got := attrsOf(p.Keep); got != "[oninput]" {
+		t.Errorf("expected oninput to be kept, got %s", got)
+	}
+	if got := attrsOf(p.Remove); got != "["+onClickField+"]" {
+		t.Errorf("expected the OnClick listener to be removed, got %s", got)
+	}
+	if len(p.Add) != 0 {
+		t.Errorf("expected nothing added, got %s", attrsOf(p.Add))
+	}
+}
@@ -0,0 +1,122 @@
+package vdom
+
+// DefaultMountBudget is the number of nodes a progressive mount creates per step when no
+// budget is configured. Around 500 nodes keeps a step well under one frame on mid-range
+// hardware.
+const DefaultMountBudget = 500
+
+// mountWalker creates the platform nodes of a VNode tree in budgeted steps. It walks the
+// tree top-down (breadth-first), so a node's children are created only after every node
+// above them: a tree attached to the page mid-walk fills in from the top. T is the platform
+// node type (js.Value in the browser), so the walker can be tested and benchmarked with any
+// other node type.
+type mountWalker[T any] struct {
+	create          func(n *VNode, ns string) (T, bool) // Creates the node for n (without children) in namespace ns; false when n produces none
+	appendChild     func(parent, child T)
+	onDepthExceeded func(err *DepthError) // Called once per walk for the first node beyond MaxDepth
+	finish          func(el T, n *VNode)  // Optional; called once all of n's children are created
+
+	root    T
+	hasRoot bool
+	queue   []mountFrame[T] // FIFO of created nodes whose children are still to be created
+	head    int
+	warned  bool
+}
+
+// mountFrame is a created node, in namespace ns, whose children are created from index
+// next onwards.
+type mountFrame[T any] struct {
+	el    T
+	node  *VNode
+	ns    string
+	depth int
+	trail *vnodeTrail
+	next  int
+}
+
+// newMountWalker creates the root node for n, which sits at depth below parent, and
+// prepares the walk over its descendants. The namespace of n follows from its ancestors in
+// parent (see childNamespace).
+func newMountWalker[T any](n *VNode, depth int, parent *vnodeTrail, create func(n *VNode, ns string) (T, bool), appendChild func(parent, child T), onDepthExceeded func(err *DepthError)) *mountWalker[T] {
+	w := &mountWalker[T]{create: create, appendChild: appendChild, onDepthExceeded: onDepthExceeded}
+	if n == nil {
+		return w
+	}
+	trail := &vnodeTrail{node: n, parent: parent}
+	if exceedsMaxDepth(depth) {
+		w.depthExceeded(&DepthError{Depth: depth, Limit: MaxDepth(), Path: trail.path()})
+		return w
+	}
+
+	ns := trailNamespace(trail)
+	w.root, w.hasRoot = create(n, ns)
+	if w.hasRoot && acceptsChildren(n) && len(n.Children) > 0 {
+		w.queue = append(w.queue, mountFrame[T]{el: w.root, node: n, ns: ns, depth: depth, trail: trail})
+	}
+	return w
+}
+
+// step creates up to budget nodes (all remaining nodes when budget <= 0) and reports
+// whether the tree is complete.
+func (w *mountWalker[T]) step(budget int) bool {
+	created := 0
+	for w.head < len(w.queue) {
+		f := &w.queue[w.head]
+		for f.next < len(f.node.Children) {
+			if budget > 0 && created >= budget {
+				return false
+			}
+			child := f.node.Children[f.next]
+			f.next++
+			if child == nil {
+				continue
+			}
+			if exceedsMaxDepth(f.depth + 1) {
+				w.depthExceeded(&DepthError{Depth: f.depth + 1, Limit: MaxDepth(), Path: (&vnodeTrail{node: child, parent: f.trail}).path()})
+				f.next = len(f.node.Children)
+				break
+			}
+			ns := childNamespace(f.node.Tag, f.ns, child.Tag)
+			el, ok := w.create(child, ns)
+			created++
+			if !ok {
+				continue
+			}
+			w.appendChild(f.el, el)
+			if acceptsChildren(child) && len(child.Children) > 0 {
+				w.queue = append(w.queue, mountFrame[T]{el: el, node: child, ns: ns, depth: f.depth + 1, trail: &vnodeTrail{node: child, parent: f.trail}})
+				f = &w.queue[w.head] // append may have moved the queue
+			}
+		}
+		if w.finish != nil {
+			w.finish(f.el, f.node)
+		}
+		w.queue[w.head] = mountFrame[T]{} // Let finished subtrees be collected
+		w.head++
+	}
+	w.queue, w.head = nil, 0
+	return true
+}
+
+// depthExceeded reports err through onDepthExceeded, once per walk.
+func (w *mountWalker[T]) depthExceeded(err *DepthError) {
+	if w.warned || w.onDepthExceeded == nil {
+		return
+	}
+	w.warned = true
+	w.onDepthExceeded(err)
+}
+
+// acceptsChildren reports whether the DOM node created for n gets n's children appended.
+// Text nodes, void elements, form fields and elements showing UnsafeInnerHTML never do; <p>
+// and <button> only when they have no Content.
+func acceptsChildren(n *VNode) bool {
+	switch {
+	case n.Tag == "#text" || voidElements[n.Tag] || n.Tag == "option" || n.Tag == "textarea" || n.UnsafeInnerHTML != "":
+		return false
+	case n.Tag == "p" || n.Tag == "button":
+		return n.Content == ""
+	default:
+		return true
+	}
+}
@@ -0,0 +1,233 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeNode stands in for a DOM node in native mount tests.
+type fakeNode struct {
+	tag      string
+	children []*fakeNode
+}
+
+// fakeMount returns mountWalker callbacks building fakeNodes, and the tags in creation order.
+func fakeMount() (func(n *VNode, _ string) (*fakeNode, bool), func(parent, child *fakeNode), *[]string) {
+	var order []string
+	create := func(n *VNode, _ string) (*fakeNode, bool) {
+		order = append(order, n.Tag)
+		return &fakeNode{tag: n.Tag}, true
+	}
+	appendChild := func(parent, child *fakeNode) {
+		parent.children = append(parent.children, child)
+	}
+	return create, appendChild, &order
+}
+
+// countFake returns the number of nodes in the fake tree rooted at n.
+func countFake(n *fakeNode) int {
+	count := 1
+	for _, c := range n.children {
+		count += countFake(c)
+	}
+	return count
+}
+
+// report builds a table of rowCount rows with cells text cells each: the shape of a large
+// reporting page.
+func report(rowCount, cells int) *VNode {
+	rows := make([]*VNode, rowCount)
+	for i := range rows {
+		row := make([]*VNode, cells)
+		for j := range row {
+			row[j] = NewVNode("td", nil, []*VNode{Text(fmt.Sprintf("%d:%d", i, j))}, "")
+		}
+		rows[i] = NewVNode("tr", nil, row, "")
+	}
+	return NewVNode("table", nil, rows, "")
+}
+
+// TestMountWalker_TopDownInBudgetedSteps verifies each step creates at most the budget and that
+// a node is created only after every node above it.
+func TestMountWalker_TopDownInBudgetedSteps(t *testing.T) {
+	// Arrange
+	root := Div(nil,
+		NewVNode("header", nil, []*VNode{NewVNode("h1", nil, nil, "Title")}, ""),
+		nil,
+		NewVNode("main", nil, []*VNode{NewVNode("section", nil, nil, "")}, ""),
+	)
+	create, appendChild, order := fakeMount()
+	w := newMountWalker(root, 1, nil, create, appendChild, nil)
+
+	// Act
+	steps := 0
+	for {
+		before := len(*order)
+		done := w.step(2)
+		steps++
+		if created := len(*order) - before; created > 2 {
+			t.Errorf("step %d created %d nodes, budget is 2", steps, created)
+		}
+		if done {
+			break
+		}
+	}
+
+	// Assert
+	want := []string{"div", "header", "main", "h1", "section"}
+	if fmt.Sprint(*order) != fmt.Sprint(want) {
+		t.Errorf("expected creation order %v, got %v", want, *order)
+	}
+	if steps != 2 {
+		t.Errorf("expected 2 steps for 4 descendants at budget 2, got %d", steps)
+	}
+	if got := w.root.children[0].children[0].tag; got != "h1" {
+		t.Errorf("expected h1 under header, got %s", got)
+	}
+}
+
+// TestMountWalker_ChunkedMatchesSinglePass verifies a chunked walk builds the same tree as an
+// unbudgeted one.
+func TestMountWalker_ChunkedMatchesSinglePass(t *testing.T) {
+	// Arrange
+	root := report(40, 50)
+	create, appendChild, _ := fakeMount()
+	single := newMountWalker(root, 1, nil, create, appendChild, nil)
+	chunked := newMountWalker(root, 1, nil, create, appendChild, nil)
+
+	// Act
+	single.step(0)
+	steps := 1
+	for !chunked.step(500) {
+		steps++
+	}
+
+	// Assert
+	if got, want := countFake(chunked.root), countFake(single.root); got != want || want != 1+40+40*50*2 {
+		t.Errorf("expected %d nodes in both trees, got %d (single pass %d)", 1+40+40*50*2, got, want)
+	}
+	if steps < 8 {
+		t.Errorf("expected the 4,040 descendants to take at least 8 steps, got %d", steps)
+	}
+}
+
+// TestMountWalker_StopsAtMaxDepth verifies nodes beyond the depth limit are left out and
+// reported once.
+func TestMountWalker_StopsAtMaxDepth(t *testing.T) {
+	// Arrange
+	SetMaxDepth(10)
+	defer SetMaxDepth(DefaultMaxDepth)
+	create, appendChild, order := fakeMount()
+	var reported []*DepthError
+
+	// Act
+	w := newMountWalker(chain(50), 1, nil, create, appendChild, func(err *DepthError) {
+		reported = append(reported, err)
+	})
+	for !w.step(3) {
+	}
+
+	// Assert
+	if len(*order) != 10 {
+		t.Errorf("expected 10 nodes created, got %d", len(*order))
+	}
+	if len(reported) != 1 || reported[0].Depth != 11 {
+		t.Errorf("expected one report at depth 11, got %v", reported)
+	}
+}
+
+// BenchmarkMountWalker_MaxBlocking compares the longest uninterrupted task of a single-pass
+// mount of an 8,000+ node page with a progressive mount at the default budget. The
+// max-block-µs metric is what the browser sees as the longest task on the main thread.
+func BenchmarkMountWalker_MaxBlocking(b *testing.B) {
+	root := report(80, 50) // 1 + 80 + 80*50*2 = 8,081 nodes
+	for _, bc := range []struct {
+		name   string
+		budget int
+	}{
+		{"single-pass", 0},
+		{"progressive", DefaultMountBudget},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			_, appendChild, _ := fakeMount()
+			create := func(n *VNode, _ string) (*fakeNode, bool) { return &fakeNode{tag: n.Tag}, true }
+			var maxBlock time.Duration
+			for i := 0; i < b.N; i++ {
+				w := newMountWalker(root, 1, nil, create, appendChild, nil)
+				for done := false; !done; {
+					start := time.Now()
+					done = w.step(bc.budget)
+					if elapsed := time.Since(start); elapsed > maxBlock {
+						maxBlock = elapsed
+					}
+				}
+			}
+			b.ReportMetric(float64(maxBlock.Microseconds()), "max-block-µs")
+		})
+	}
+}
+
+// TestMountWalker_FinishRunsAfterChildren verifies finish sees every child of a node, which is
+// when a bound select can pick its option.
+func TestMountWalker_FinishRunsAfterChildren(t *testing.T) {
+	// Arrange
+	root := Div(nil, NewVNode("select", nil, []*VNode{
+		NewVNode("option", nil, nil, "a"),
+		NewVNode("option", nil, nil, "b"),
+	}, ""))
+	create, appendChild, _ := fakeMount()
+	w := newMountWalker(root, 1, nil, create, appendChild, nil)
+	childrenAtFinish := map[string]int{}
+	w.finish = func(el *fakeNode, n *VNode) { childrenAtFinish[n.Tag] = len(el.children) }
+
+	// Act
+	for !w.step(1) {
+	}
+
+	// Assert
+	if childrenAtFinish["select"] != 2 || childrenAtFinish["div"] != 1 {
+		t.Errorf("expected finish after all children were created, got %v", childrenAtFinish)
+	}
+}
+
+// TestMountWalker_VoidElementsGetNoChildren verifies children of a void element, which the
+// browser cannot hold, are not created, while any other tag gets its children.
+func TestMountWalker_VoidElementsGetNoChildren(t *testing.T) {
+	// Arrange
+	root := Div(nil,
+		NewVNode("img", nil, []*VNode{Text("ignored")}, ""),
+		NewVNode("details", nil, []*VNode{NewVNode("summary", nil, nil, "More")}, ""),
+	)
+	create, appendChild, order := fakeMount()
+	w := newMountWalker(root, 1, nil, create, appendChild, nil)
+
+	// Act
+	for !w.step(10) {
+	}
+
+	// Assert
+	if got := fmt.Sprint(*order); got != "[div img details summary]" {
+		t.Errorf("expected the img child left out, got %s", got)
+	}
+}
+
+// TestMountWalker_InnerHTMLElementsGetNoChildren verifies an element showing raw markup does
+// not get its Children appended on top of it.
+func TestMountWalker_InnerHTMLElementsGetNoChildren(t *testing.T) {
+	// Arrange
+	raw := RawHTML("section", nil, "<p>markup</p>")
+	raw.Children = []*VNode{NewVNode("p", nil, nil, "ignored")}
+	create, appendChild, order := fakeMount()
+
+	// Act
+	newMountWalker(Div(nil, raw), 1, nil, create, appendChild, nil).step(0)
+
+	// Assert
+	if got := fmt.Sprint(*order); got != "[div section]" {
+		t.Errorf("expected [div section], got %s", got)
+	}
+}
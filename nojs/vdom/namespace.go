@@ -0,0 +1,40 @@
+package vdom
+
+// Namespaces of the elements the renderer creates.
+const (
+	htmlNamespace = "http://www.w3.org/1999/xhtml"
+	svgNamespace  = "http://www.w3.org/2000/svg"
+)
+
+// Elements take their namespace from where they sit, as the HTML parser decides it: <svg>
+// starts the SVG namespace and every element below it stays there, so <a>, <title> and
+// <style> inside an icon are SVG elements too, while the children of a <foreignObject>
+// return to HTML. An element created in the HTML namespace under an <svg> is not drawn.
+
+// childNamespace returns the namespace of a tag element whose parent is a parentTag element
+// in parentNS.
+func childNamespace(parentTag, parentNS, tag string) string {
+	switch {
+	case tag == "svg":
+		return svgNamespace
+	case parentNS == svgNamespace && parentTag != "foreignObject":
+		return svgNamespace
+	default:
+		return htmlNamespace
+	}
+}
+
+// trailNamespace returns the namespace of the element at the end of trail, working down
+// from the top of the trail. A nil trail is HTML.
+func trailNamespace(trail *vnodeTrail) string {
+	var nodes []*VNode
+	for cur := trail; cur != nil; cur = cur.parent {
+		nodes = append(nodes, cur.node)
+	}
+	ns, parentTag := htmlNamespace, ""
+	for i := len(nodes) - 1; i >= 0; i-- {
+		ns = childNamespace(parentTag, ns, nodes[i].Tag)
+		parentTag = nodes[i].Tag
+	}
+	return ns
+}
@@ -0,0 +1,79 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import "testing"
+
+// TestMountWalker_CreatesSVGSubtreesInSVGNamespace verifies every element under an <svg>,
+// including tags HTML also has, is created in the SVG namespace, and that the children of a
+// <foreignObject> and the siblings of the <svg> are HTML again.
+func TestMountWalker_CreatesSVGSubtreesInSVGNamespace(t *testing.T) {
+	// Arrange
+	root := Div(nil,
+		NewVNode("svg", map[string]any{"viewBox": "0 0 24 24"}, []*VNode{
+			NewVNode("g", nil, []*VNode{
+				NewVNode("a", nil, []*VNode{NewVNode("path", nil, nil, "")}, ""),
+				NewVNode("title", nil, nil, "Home"),
+			}, ""),
+			NewVNode("foreignObject", nil, []*VNode{NewVNode("p", nil, nil, "Caption")}, ""),
+		}, ""),
+		NewVNode("a", nil, nil, "Next"),
+	)
+	namespaces := map[string][]string{}
+	create := func(n *VNode, ns string) (*fakeNode, bool) {
+		namespaces[n.Tag] = append(namespaces[n.Tag], ns)
+		return &fakeNode{tag: n.Tag}, true
+	}
+	_, appendChild, _ := fakeMount()
+
+	// Act
+	newMountWalker(root, 1, nil, create, appendChild, nil).step(0)
+
+	// Assert
+	want := map[string][]string{
+		"div":           {htmlNamespace},
+		"svg":           {svgNamespace},
+		"g":             {svgNamespace},
+		"a":             {htmlNamespace, svgNamespace}, // Breadth-first: the outer <a> comes first
+		"path":          {svgNamespace},
+		"title":         {svgNamespace},
+		"foreignObject": {svgNamespace},
+		"p":             {htmlNamespace},
+	}
+	for tag, wantNS := range want {
+		got := namespaces[tag]
+		if len(got) != len(wantNS) {
+			t.Errorf("<%s>: expected namespaces %v, got %v", tag, wantNS, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != wantNS[i] {
+				t.Errorf("<%s> #%d: expected %s, got %s", tag, i, wantNS[i], got[i])
+			}
+		}
+	}
+}
+
+// TestMountWalker_ReplacedSVGNodeKeepsNamespace verifies a node created during a patch
+// below an <svg>, as when a <path> replaces a <circle>, is created in the SVG namespace.
+func TestMountWalker_ReplacedSVGNodeKeepsNamespace(t *testing.T) {
+	// Arrange
+	svg := NewVNode("svg", nil, nil, "")
+	g := NewVNode("g", nil, nil, "")
+	parent := &vnodeTrail{node: g, parent: &vnodeTrail{node: svg, parent: &vnodeTrail{node: Div(nil)}}}
+	var got string
+	create := func(n *VNode, ns string) (*fakeNode, bool) {
+		got = ns
+		return &fakeNode{tag: n.Tag}, true
+	}
+	_, appendChild, _ := fakeMount()
+
+	// Act
+	newMountWalker(NewVNode("path", nil, nil, ""), 4, parent, create, appendChild, nil)
+
+	// Assert
+	if got != svgNamespace {
+		t.Errorf("expected the replacement <path> in %s, got %s", svgNamespace, got)
+	}
+}
@@ -0,0 +1,75 @@
+//go:build js || wasm
+// +build js wasm
+
+package vdom
+
+import (
+	"syscall/js"
+
+	"github.com/ForgeLogic/nojs/console"
+)
+
+// ProgressiveMount builds the DOM for a VNode tree over several steps instead of one long
+// task, so mounting a very large page does not freeze the host page. Nodes are created
+// top-down with their event listeners and refs, exactly as RenderToSelector creates them.
+//
+// In streaming mode the root is attached to the mount element after the first step and the
+// page fills in from the top as later steps run. Otherwise the tree is built inside a
+// detached DocumentFragment and attached once, after the last step.
+type ProgressiveMount struct {
+	walker    *mountWalker[js.Value]
+	mount     js.Value
+	fragment  js.Value
+	streaming bool
+	attached  bool
+}
+
+// NewProgressiveMount prepares a progressive mount of n under the first element matching
+// selector. It returns nil when n is nil or the mount element does not exist.
+func NewProgressiveMount(selector string, n *VNode, streaming bool) *ProgressiveMount {
+	if n == nil || selector == "" {
+		return nil
+	}
+
+	doc := js.Global().Get("document")
+	if !doc.Truthy() {
+		return nil
+	}
+
+	mount := doc.Call("querySelector", selector)
+	if !mount.Truthy() {
+		console.Error("Mount element not found for selector:", selector)
+		return nil
+	}
+
+	m := &ProgressiveMount{
+		walker:    newMountWalker(n, 1, nil, createMountNode, appendMountNode, warnDepthExceeded),
+		mount:     mount,
+		streaming: streaming,
+	}
+	m.walker.finish = finishMountNode
+	if !streaming && m.walker.hasRoot {
+		m.fragment = doc.Call("createDocumentFragment")
+		m.fragment.Call("appendChild", m.walker.root)
+	}
+	return m
+}
+
+// Step creates up to budget nodes (DefaultMountBudget when budget <= 0) and reports
+// whether the tree is complete and attached to the mount element.
+func (m *ProgressiveMount) Step(budget int) bool {
+	if budget <= 0 {
+		budget = DefaultMountBudget
+	}
+	done := m.walker.step(budget)
+
+	if !m.attached && m.walker.hasRoot && (m.streaming || done) {
+		if m.streaming {
+			m.mount.Call("appendChild", m.walker.root)
+		} else {
+			m.mount.Call("appendChild", m.fragment)
+		}
+		m.attached = true
+	}
+	return done
+}
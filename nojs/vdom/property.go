@@ -0,0 +1,43 @@
+package vdom
+
+import "fmt"
+
+// controlProperty reports whether the attribute key of a <tag> element is also written to
+// the element's DOM property of the same name. The attributes only set the initial state
+// of a form control: once the user types, ticks a box or picks an option, the value,
+// checked and selected attributes no longer change what the control shows, so patching
+// them alone would leave the control stale. disabled is set the same way so that a control
+// is re-enabled by the patch that drops the attribute.
+func controlProperty(tag, key string) bool {
+	switch key {
+	case "value":
+		return tag == "input" || tag == "textarea"
+	case "checked":
+		return tag == "input"
+	case "selected":
+		return tag == "option"
+	case "disabled":
+		switch tag {
+		case "button", "input", "select", "textarea", "option", "optgroup", "fieldset":
+			return true
+		}
+	}
+	return false
+}
+
+// controlPropertyValue returns the property value for the attribute key set to value, nil
+// standing for a removed attribute. value is a string; checked, selected and disabled are
+// booleans that, like their attributes, are true whenever the attribute is present and not
+// false.
+func controlPropertyValue(key string, value any) any {
+	if key == "value" {
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	}
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	return value != nil
+}
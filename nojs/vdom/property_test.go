@@ -0,0 +1,57 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import "testing"
+
+// TestControlProperty_FormControlsOnly verifies only the attributes that stop tracking the
+// state of a form control once the user interacts with it are written as properties.
+func TestControlProperty_FormControlsOnly(t *testing.T) {
+	cases := []struct {
+		tag, key string
+		want     bool
+	}{
+		{"input", "value", true},
+		{"textarea", "value", true},
+		{"input", "checked", true},
+		{"option", "selected", true},
+		{"button", "disabled", true},
+		{"fieldset", "disabled", true},
+		{"select", "disabled", true},
+		{"option", "value", false},
+		{"button", "value", false},
+		{"div", "disabled", false},
+		{"a", "selected", false},
+		{"input", "class", false},
+	}
+	for _, c := range cases {
+		if got := controlProperty(c.tag, c.key); got != c.want {
+			t.Errorf("controlProperty(%q, %q) = %v, want %v", c.tag, c.key, got, c.want)
+		}
+	}
+}
+
+// TestControlPropertyValue_AttributePresence verifies boolean properties follow the presence
+// of their attribute, and value the attribute's text.
+func TestControlPropertyValue_AttributePresence(t *testing.T) {
+	cases := []struct {
+		key   string
+		value any
+		want  any
+	}{
+		{"disabled", true, true},
+		{"disabled", false, false},
+		{"disabled", nil, false},
+		{"checked", "", true},
+		{"selected", "selected", true},
+		{"value", "Ada", "Ada"},
+		{"value", 42, "42"},
+		{"value", nil, ""},
+	}
+	for _, c := range cases {
+		if got := controlPropertyValue(c.key, c.value); got != c.want {
+			t.Errorf("controlPropertyValue(%q, %#v) = %#v, want %#v", c.key, c.value, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package vdom
+
+// ElementRef gives a component access to the live DOM element rendered for a template
+// node marked with ref="FieldName". The renderer attaches the element after the node is
+// created or patched and clears it when the node is removed, so the ref is guaranteed to
+// be set inside OnAfterRender.
+// In non-WASM builds nothing attaches it, IsSet always reports false and Focus does nothing
+// (ref_stub.go). Value and Call, which return a js.Value, exist only in js/wasm builds
+// (ref_wasm.go): use them from files built for js/wasm.
+//
+// Example:
+//
+//	type SearchBox struct {
+//	    runtime.ComponentBase
+//	    Input vdom.ElementRef // <input ref="Input" />
+//	}
+//
+//	func (c *SearchBox) OnAfterRender(firstRender bool) {
+//	    if firstRender && c.Input.IsSet() {
+//	        c.Input.Focus()
+//	    }
+//	}
+type ElementRef struct {
+	element any    // js.Value of the live element (any to avoid build tag issues)
+	owner   *VNode // VNode that attached the element; used to detach only its own element
+}
+
+// IsSet reports whether the ref currently points at a live element.
+func (r *ElementRef) IsSet() bool {
+	return r != nil && r.owner != nil
+}
+
+// attach points the ref at the element rendered for owner.
+func (r *ElementRef) attach(owner *VNode, element any) {
+	r.owner = owner
+	r.element = element
+}
+
+// detach clears the ref, but only if it still points at owner's element. A ref that was
+// already re-attached to another node (e.g., its keyed item moved) is left untouched.
+func (r *ElementRef) detach(owner *VNode) {
+	if r.owner == owner {
+		r.owner = nil
+		r.element = nil
+	}
+}
+
+// ElementRefs holds one ElementRef per loop item, keyed by the item's trackBy value.
+// Use it for ref="FieldName" on elements inside {@for} blocks. The zero value is ready to use.
+type ElementRefs struct {
+	refs map[any]*ElementRef
+}
+
+// For returns the ref for key, creating it if needed. Generated code calls this while
+// rendering; components should use Get.
+func (r *ElementRefs) For(key any) *ElementRef {
+	if r.refs == nil {
+		r.refs = make(map[any]*ElementRef)
+	}
+	ref, ok := r.refs[key]
+	if !ok {
+		ref = &ElementRef{}
+		r.refs[key] = ref
+	}
+	return ref
+}
+
+// Get returns the ref for key if it currently points at a live element.
+// Refs of items that are no longer rendered are dropped.
+func (r *ElementRefs) Get(key any) (*ElementRef, bool) {
+	ref, ok := r.refs[key]
+	if !ok {
+		return nil, false
+	}
+	if !ref.IsSet() {
+		delete(r.refs, key)
+		return nil, false
+	}
+	return ref, true
+}
+
+// WithRef attaches ref to n so the renderer can populate it with n's DOM element.
+// It returns n to allow wrapping constructor calls in generated code.
+func WithRef(n *VNode, ref *ElementRef) *VNode {
+	if n != nil {
+		n.Ref = ref
+	}
+	return n
+}
@@ -0,0 +1,10 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+// Focus does nothing outside the browser, where no element is ever attached. It lets a
+// component without build tags focus a ref in OnAfterRender and still compile natively,
+// for TestRenderer tests. Value and Call return js.Value, so only files built for js/wasm
+// can use them.
+func (r *ElementRef) Focus() {}
@@ -0,0 +1,21 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import "testing"
+
+// TestElementRef_FocusNative verifies Focus compiles and does nothing in native builds, so
+// components that focus a ref in OnAfterRender can be tested with the TestRenderer.
+func TestElementRef_FocusNative(t *testing.T) {
+	// Arrange
+	var ref ElementRef
+
+	// Act
+	ref.Focus()
+
+	// Assert
+	if ref.IsSet() {
+		t.Error("expected the ref unset in a native build")
+	}
+}
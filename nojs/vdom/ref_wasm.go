@@ -0,0 +1,28 @@
+//go:build js || wasm
+// +build js wasm
+
+package vdom
+
+import "syscall/js"
+
+// Value returns the live DOM element, or js.Undefined() if the ref is not set.
+func (r *ElementRef) Value() js.Value {
+	if !r.IsSet() {
+		return js.Undefined()
+	}
+	return r.element.(js.Value)
+}
+
+// Call invokes a method on the live DOM element. It returns js.Undefined() if the ref
+// is not set.
+func (r *ElementRef) Call(method string, args ...any) js.Value {
+	if !r.IsSet() {
+		return js.Undefined()
+	}
+	return r.Value().Call(method, args...)
+}
+
+// Focus moves keyboard focus to the element. It is a no-op if the ref is not set.
+func (r *ElementRef) Focus() {
+	r.Call("focus")
+}
@@ -0,0 +1,55 @@
+//go:build js && wasm
+// +build js,wasm
+
+package vdom
+
+import (
+	"syscall/js"
+	"testing"
+)
+
+// TestElementRef_FocusAfterRerender is a browser smoke test: the ref must point at the
+// live element after the initial render and after a patch, and Focus must reach it.
+// Run it with a browser-backed wasm test runner (e.g., wasmbrowsertest); it is skipped
+// where no DOM is available, such as under Node.
+func TestElementRef_FocusAfterRerender(t *testing.T) {
+	doc := js.Global().Get("document")
+	if !doc.Truthy() {
+		t.Skip("no DOM available")
+	}
+
+	// Arrange
+	mount := doc.Call("createElement", "div")
+	mount.Set("id", "ref-smoke-test")
+	doc.Get("body").Call("appendChild", mount)
+	defer mount.Call("remove")
+
+	var input ElementRef
+	render := func(value string) *VNode {
+		return Div(nil, WithRef(NewVNode("input", map[string]any{"value": value}, nil, ""), &input))
+	}
+	oldVNode := render("a")
+	RenderTo(mount, oldVNode)
+	if !input.IsSet() {
+		t.Fatal("Expected ref to be set after the initial render")
+	}
+	initial := input.Value()
+
+	// Act
+	newVNode := render("b")
+	Patch("#ref-smoke-test", oldVNode, newVNode)
+	input.Focus()
+
+	// Assert
+	if !input.IsSet() || !input.Value().Equal(initial) {
+		t.Fatal("Expected ref to keep pointing at the patched element")
+	}
+	if !doc.Get("activeElement").Equal(input.Value()) {
+		t.Error("Expected Focus through the ref to focus the element")
+	}
+
+	Clear("#ref-smoke-test", newVNode)
+	if input.IsSet() {
+		t.Error("Expected ref to be cleared after the element is removed")
+	}
+}
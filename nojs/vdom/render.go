@@ -4,16 +4,27 @@
 package vdom
 
 import (
+	"slices"
+	"strings"
 	"syscall/js"
 
 	"github.com/ForgeLogic/nojs/console"
 )
 
 // eventBinding tracks a DOM listener so it can be removed before releasing the js.Func.
+// A listener added for a listenerSpec calls the handler in its cell, which patchListeners
+// points at the new VNode's handler when the listener is kept.
 type eventBinding struct {
 	target    js.Value
 	eventName string
 	callback  js.Func
+	attr      string
+	cell      *handlerCell
+}
+
+// handlerCell holds the handler a listener calls.
+type handlerCell struct {
+	handler func(js.Value)
 }
 
 // release detaches the listener and releases the underlying js.Func.
@@ -30,37 +41,41 @@ func releaseCallbacks(v *VNode) {
 		return
 	}
 
-	callbacks := v.GetEventCallbacks()
-	for _, cb := range callbacks {
-		switch stored := cb.(type) {
-		case eventBinding:
+	for _, cb := range v.GetEventCallbacks() {
+		releaseCallback(cb)
+	}
+	v.ClearEventCallbacks()
+}
+
+// releaseCallback releases one callback stored in a VNode.
+func releaseCallback(cb any) {
+	switch stored := cb.(type) {
+	case eventBinding:
+		stored.release()
+	case *eventBinding:
+		if stored != nil {
 			stored.release()
-		case *eventBinding:
-			if stored != nil {
-				stored.release()
-			}
-		case js.Func:
+		}
+	case js.Func:
+		stored.Release()
+	case *js.Func:
+		if stored != nil {
 			stored.Release()
-		case *js.Func:
-			if stored != nil {
-				stored.Release()
-			}
 		}
 	}
-	v.ClearEventCallbacks()
 }
 
-// deepReleaseCallbacks recursively releases all callbacks in the entire VNode tree.
+// deepReleaseCallbacks releases all callbacks in the entire VNode tree.
+// It is called whenever a subtree leaves the DOM, so it also detaches the subtree's refs.
+// The tree is walked iteratively, so arbitrarily deep trees cannot overflow the stack.
 func deepReleaseCallbacks(v *VNode) {
-	if v == nil {
-		return
-	}
-
-	releaseCallbacks(v)
-
-	for _, child := range v.Children {
-		deepReleaseCallbacks(child)
-	}
+	Walk(v, func(n *VNode, depth int) bool {
+		releaseCallbacks(n)
+		if n.Ref != nil {
+			n.Ref.detach(n)
+		}
+		return true
+	})
 }
 
 func Clear(selector string, prevVDOM *VNode) {
@@ -124,13 +139,17 @@ func RenderTo(mount js.Value, n *VNode) {
 
 // setAttributeValue sets an attribute on an element, handling boolean attributes and event handlers correctly.
 func setAttributeValue(el js.Value, key string, value any) {
+	syncControlState(el, key, value)
+
 	// Handle boolean attributes
 	if boolVal, ok := value.(bool); ok {
 		if boolVal {
 			// For boolean attributes, set them without a value (or with empty string)
 			el.Call("setAttribute", key, "")
+		} else {
+			// Absent means false; a patch from true must drop the attribute set before
+			el.Call("removeAttribute", key)
 		}
-		// If false, don't set the attribute at all
 		return
 	}
 
@@ -141,365 +160,207 @@ func setAttributeValue(el js.Value, key string, value any) {
 		return
 	}
 
+	// setAttribute does not parse HTML, so strings are not escaped; URLs are sanitized
+	if s, ok := value.(string); ok {
+		value = DOMValue(ContextOf(key), s)
+	}
+
 	// For all other types, convert to string and set normally
 	el.Call("setAttribute", key, value)
 }
 
-// attachEventListeners processes attributes and attaches event listeners for event handlers.
-// Event attributes start with "on" (e.g., onClick, onInput, onMousedown).
-// The VNode parameter is used to store js.Func objects for later cleanup.
-func attachEventListeners(el js.Value, vnode *VNode, attributes map[string]any) {
-	if attributes == nil {
+// syncControlState mirrors the attributes controlProperty selects onto the element's live
+// properties, so that a field bound with @bind and reset in code, or a button disabled while
+// saving, follows the new attributes. A focused control keeps its value, as in patchNode, so
+// that typing "1." into a bound number field is not rewritten to "1" under the caret. nil
+// stands for a removed attribute.
+func syncControlState(el js.Value, key string, value any) {
+	if !controlProperty(strings.ToLower(el.Get("tagName").String()), key) {
 		return
 	}
-
-	for key, value := range attributes {
-		// Check if this is an event handler (starts with "on")
-		if len(key) > 2 && key[0] == 'o' && key[1] == 'n' {
-			if handler, ok := value.(func(js.Value)); ok {
-				// Convert "onClick" -> "click", "onInput" -> "input", etc.
-				// Lowercase the first character after "on" if it's uppercase
-				eventName := key[2:]
-				if eventName[0] >= 'A' && eventName[0] <= 'Z' {
-					eventName = string(eventName[0]+('a'-'A')) + eventName[1:]
-				}
-
-				// Wrap the handler in js.FuncOf
-				cb := js.FuncOf(func(this js.Value, args []js.Value) any {
-					if len(args) > 0 {
-						handler(args[0])
-					}
-					return nil
-				})
-
-				el.Call("addEventListener", eventName, cb)
-
-				// Store the callback in VNode for later cleanup
-				if vnode != nil {
-					vnode.AddEventCallback(eventBinding{target: el, eventName: eventName, callback: cb})
-				}
-			}
+	property := controlPropertyValue(key, value)
+	if key == "value" {
+		if el.Get("value").String() != property && !el.Call("matches", ":focus").Bool() {
+			el.Set("value", property)
 		}
+		return
 	}
+	el.Set(key, property)
 }
 
-func createElement(n *VNode) js.Value {
-	doc := js.Global().Get("document")
-	if !doc.Truthy() || n == nil {
-		return js.Undefined()
+// attachEventListeners adds the listeners n asks for to el and stores their callbacks in n
+// for later cleanup. Event attributes start with "on" (e.g., onClick, onInput, onMousedown).
+func attachEventListeners(el js.Value, n *VNode) {
+	for _, spec := range eventListeners(n) {
+		addListener(el, n, spec)
 	}
+}
 
-	switch n.Tag {
-	case "#text":
-		// Pure text node - no HTML element wrapper
-		if n.Content == "" {
-			console.Log("[DEBUG] Text node with empty content, returning undefined")
-			return js.Undefined()
-		}
-		textNode := doc.Call("createTextNode", n.Content)
-		return textNode
-
-	case "p":
-		el := doc.Call("createElement", "p")
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Content != "" {
-			el.Set("textContent", n.Content)
-		} else if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		return el
-	case "div":
-		el := doc.Call("createElement", "div")
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Content != "" {
-			el.Set("textContent", n.Content)
-		}
-
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		return el
-	case "input":
-		el := doc.Call("createElement", "input")
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		// For text input, set value if provided in Content
-		if n.Content != "" {
-			el.Set("value", n.Content)
-		}
-
-		return el
-	case "button":
-		el := doc.Call("createElement", "button")
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Content != "" {
-			el.Set("textContent", n.Content)
-		} else if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		// Attach Go OnClick handler if present (legacy support)
-		if n.OnClick != nil {
-			cb := js.FuncOf(func(this js.Value, args []js.Value) any {
-				n.OnClick()
-				return nil
-			})
-			el.Call("addEventListener", "click", cb)
-			// Store callback for cleanup
-			n.AddEventCallback(eventBinding{target: el, eventName: "click", callback: cb})
-		}
-
-		return el
-
-	case "h1", "h2", "h3", "h4", "h5", "h6":
-		// Handle heading tags
-		el := doc.Call("createElement", n.Tag)
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Content != "" {
-			el.Set("textContent", n.Content)
-		}
-
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		return el
-
-	case "ul", "ol":
-		// Handle list container tags
-		el := doc.Call("createElement", n.Tag)
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		return el
+// handlerFor returns the handler of n that spec stands for, or nil when it is not one the
+// browser can call.
+func handlerFor(n *VNode, spec listenerSpec) func(js.Value) {
+	if spec.Attr == onClickField {
+		onClick := n.OnClick
+		return func(js.Value) { onClick() }
+	}
+	handler, _ := n.Attributes[spec.Attr].(func(js.Value))
+	return handler
+}
 
-	case "li":
-		// Handle list item tags
-		el := doc.Call("createElement", "li")
+// addListener adds the listener for spec to el, calling the handler of n.
+func addListener(el js.Value, n *VNode, spec listenerSpec) {
+	handler := handlerFor(n, spec)
+	if handler == nil {
+		return
+	}
+	cell := &handlerCell{handler: handler}
+	cb := js.FuncOf(func(this js.Value, args []js.Value) any {
+		event := js.Undefined()
+		if len(args) > 0 {
+			event = args[0]
+		}
+		cell.handler(event)
+		return nil
+	})
+	el.Call("addEventListener", spec.Event, cb)
+	n.AddEventCallback(eventBinding{target: el, eventName: spec.Event, callback: cb, attr: spec.Attr, cell: cell})
+}
 
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
+// patchListeners carries out diffListeners for the element el patched from old to new: kept
+// listeners move to new and call its handlers from then on, the others are released or
+// added. Callbacks of old that are not listeners are released.
+func patchListeners(el js.Value, old, new *VNode) {
+	p := diffListeners(old, new)
+	kept := make(map[string]bool, len(p.Keep))
+	for _, spec := range p.Keep {
+		kept[spec.Attr] = true
+	}
+
+	moved := make(map[string]bool, len(p.Keep))
+	for _, cb := range old.GetEventCallbacks() {
+		if binding, ok := cb.(eventBinding); ok && binding.cell != nil && kept[binding.attr] {
+			if handler := handlerFor(new, listenerSpec{Attr: binding.attr}); handler != nil {
+				binding.cell.handler = handler
+				new.AddEventCallback(binding)
+				moved[binding.attr] = true
+				continue
 			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Content != "" {
-			el.Set("textContent", n.Content)
 		}
+		releaseCallback(cb)
+	}
+	old.ClearEventCallbacks()
 
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
+	for _, spec := range p.Keep {
+		if !moved[spec.Attr] {
+			addListener(el, new, spec)
 		}
+	}
+	for _, spec := range p.Add {
+		addListener(el, new, spec)
+	}
+}
 
-		return el
+// createElement creates the DOM subtree for n.
+func createElement(n *VNode) js.Value {
+	return createElementAt(n, 1, nil)
+}
 
-	case "select":
-		// Handle select dropdown element
-		el := doc.Call("createElement", "select")
+// createElementAt creates the DOM subtree for n, which sits at depth below parent.
+// Children are created by a mountWalker rather than by recursion, so deep trees cannot
+// overflow the stack; nodes beyond MaxDepth are left out and reported once.
+func createElementAt(n *VNode, depth int, parent *vnodeTrail) js.Value {
+	w := newMountWalker(n, depth, parent, createMountNode, appendMountNode, warnDepthExceeded)
+	w.finish = finishMountNode
+	if !w.hasRoot {
+		return js.Undefined()
+	}
+	w.step(0)
+	return w.root
+}
 
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
+// createMountNode adapts createNode to mountWalker, skipping nodes that produce no DOM.
+func createMountNode(n *VNode, ns string) (js.Value, bool) {
+	el := createNode(n, ns)
+	return el, el.Truthy()
+}
 
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
+// appendMountNode appends child to parent for mountWalker.
+func appendMountNode(parent, child js.Value) {
+	parent.Call("appendChild", child)
+}
 
-		return el
+// finishMountNode runs for mountWalker once all children of n are created: a bound <select>
+// selects the option its value selects, or none.
+func finishMountNode(el js.Value, n *VNode) {
+	if n.Tag != "select" {
+		return
+	}
+	if index, bound := SelectedOptionIndex(n); bound {
+		el.Set("selectedIndex", index)
+	}
+}
 
-	case "option":
-		// Handle option element
-		el := doc.Call("createElement", "option")
+// createNode creates the DOM node for n (without children) in namespace ns and points n's
+// ref, if any, at it.
+func createNode(n *VNode, ns string) js.Value {
+	el := createDOMNode(n, ns)
+	if n.Ref != nil && el.Truthy() {
+		n.Ref.attach(n, el)
+	}
+	return el
+}
 
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
+// createDOMNode creates the DOM node for n without its children; createElementAt appends
+// those iteratively (see acceptsChildren). Every tag takes the same path: the element is
+// created in namespace ns (see childNamespace), gets its attributes and listeners, and shows
+// its Content as text, or its UnsafeInnerHTML as markup. Form fields show Content as their
+// value instead, and a bound <select> is left to finishMountNode.
+func createDOMNode(n *VNode, ns string) js.Value {
+	doc := js.Global().Get("document")
+	if !doc.Truthy() || n == nil {
+		return js.Undefined()
+	}
 
-		if n.Content != "" {
-			el.Set("textContent", n.Content)
+	if n.Tag == "#text" {
+		// Pure text node - no HTML element wrapper
+		if n.Content == "" {
+			console.Debug("[vdom] Text node with empty content, returning undefined")
+			return js.Undefined()
 		}
+		return doc.Call("createTextNode", n.Content)
+	}
 
-		return el
-
-	case "textarea":
-		// Handle textarea element
-		el := doc.Call("createElement", "textarea")
+	var el js.Value
+	if ns == svgNamespace {
+		el = doc.Call("createElementNS", svgNamespace, n.Tag)
+	} else {
+		el = doc.Call("createElement", n.Tag)
+	}
 
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
+	for k, v := range n.Attributes {
+		// The bound value and fallback of a <select> drive the selection once the options exist
+		if n.Tag == "select" && (k == selectValueAttr || k == selectFallbackAttr) {
+			continue
 		}
+		setAttributeValue(el, k, v)
+	}
+	attachEventListeners(el, n)
 
-		if n.Content != "" {
+	if n.Content != "" {
+		switch n.Tag {
+		case "input", "textarea":
 			el.Set("value", n.Content)
-		}
-
-		return el
-
-	case "form":
-		// Handle form element
-		el := doc.Call("createElement", "form")
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		return el
-
-	case "a", "nav", "span", "section", "article", "header", "footer", "main", "aside":
-		// Handle semantic HTML5 elements and inline elements
-		el := doc.Call("createElement", n.Tag)
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Content != "" {
+		case "select":
+			// A bound value, not text
+		default:
 			el.Set("textContent", n.Content)
 		}
-
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		return el
-
-	default:
-		// Generic fallback: create any HTML element by tag name, set attributes, append children.
-		// This handles void elements like <img>, <br>, <hr> as well as any future tags.
-		el := doc.Call("createElement", n.Tag)
-
-		if n.Attributes != nil {
-			for k, v := range n.Attributes {
-				setAttributeValue(el, k, v)
-			}
-			attachEventListeners(el, n, n.Attributes)
-		}
-
-		if n.Content != "" {
-			el.Set("textContent", n.Content)
-		}
-
-		if n.Children != nil {
-			for _, child := range n.Children {
-				childEl := createElement(child)
-				if childEl.Truthy() {
-					el.Call("appendChild", childEl)
-				}
-			}
-		}
-
-		return el
 	}
+	if n.UnsafeInnerHTML != "" {
+		el.Set("innerHTML", n.UnsafeInnerHTML)
+	}
+
+	return el
 }
 
 // Patch updates the DOM by comparing old and new VDOM trees and applying minimal changes.
@@ -520,7 +381,7 @@ func Patch(mountSelector string, oldVNode, newVNode *VNode) {
 	}
 
 	// Get the root DOM element (first child of mount point)
-	rootElement := mount.Get("firstChild")
+	rootElement := childAt(mount, 0)
 	if !rootElement.Truthy() {
 		// No existing DOM, just render fresh
 		RenderToSelector(mountSelector, newVNode)
@@ -531,125 +392,217 @@ func Patch(mountSelector string, oldVNode, newVNode *VNode) {
 	patchElement(rootElement, oldVNode, newVNode)
 }
 
-// patchElement updates a single DOM element based on VDOM differences.
-func patchElement(domElement js.Value, oldVNode, newVNode *VNode) {
-	if !domElement.Truthy() || oldVNode == nil || newVNode == nil {
-		return
+// PatchNode patches the DOM rendered for node, a node of the tree rooted at root mounted at
+// mountSelector, to next, and then makes node show next like ReplaceNode. Only node's
+// element and its subtree are touched. It reports false, changing nothing, where
+// ReplaceNode would, or when node's element is missing from the DOM.
+func PatchNode(mountSelector string, root, node, next *VNode) bool {
+	if node == nil || next == nil || node.Tag != next.Tag {
+		return false
+	}
+	path, depth, parent, ok := locate(root, node)
+	if !ok {
+		return false
 	}
 
-	// Check if component keys differ (for router navigation)
-	if oldVNode.ComponentKey != "" && newVNode.ComponentKey != "" && oldVNode.ComponentKey != newVNode.ComponentKey {
-		// Keys are different - replace entire subtree
-		console.Log("[DEBUG] Component keys differ, replacing entire tree. Old:", oldVNode.ComponentKey, "New:", newVNode.ComponentKey)
-		deepReleaseCallbacks(oldVNode)
+	doc := js.Global().Get("document")
+	if !doc.Truthy() {
+		return false
+	}
+	mount := doc.Call("querySelector", mountSelector)
+	if !mount.Truthy() {
+		return false
+	}
+	el := childAt(mount, 0)
+	for _, index := range path {
+		if !el.Truthy() {
+			return false
+		}
+		el = childAt(el, index)
+	}
+	if !el.Truthy() {
+		return false
+	}
 
-		newElement := createElement(newVNode)
-		if newElement.Truthy() {
-			parent := domElement.Get("parentNode")
-			if parent.Truthy() {
-				parent.Call("replaceChild", newElement, domElement)
+	// Patch from a copy of node, which owns node's ref for the patch to hand it over
+	old := *node
+	if old.Ref != nil && old.Ref.owner == node {
+		old.Ref.owner = &old
+	}
+	patchElementAt(el, &old, next, depth, parent)
+	adopt(node, next)
+	return true
+}
+
+// patchTask is one DOM element waiting to be patched from old to new.
+type patchTask struct {
+	el       js.Value
+	old, new *VNode
+	depth    int
+	trail    *vnodeTrail
+	after    func() // Set on tasks that only run a step once the tasks pushed after them are done
+}
+
+// patchElement updates a single DOM element and its subtree based on VDOM differences.
+// Descendants are patched from an explicit work list rather than by recursion, so deep
+// trees cannot overflow the stack; nodes beyond MaxDepth are left untouched and reported once.
+func patchElement(domElement js.Value, oldVNode, newVNode *VNode) {
+	patchElementAt(domElement, oldVNode, newVNode, 1, nil)
+}
+
+// patchElementAt is patchElement for an element that sits at depth below parent.
+func patchElementAt(domElement js.Value, oldVNode, newVNode *VNode, depth int, parent *vnodeTrail) {
+	pending := []patchTask{{el: domElement, old: oldVNode, new: newVNode, depth: depth, trail: parent}}
+	warned := false
+	for len(pending) > 0 {
+		task := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if task.after != nil {
+			task.after()
+			continue
+		}
+		if exceedsMaxDepth(task.depth) {
+			if !warned {
+				trail := &vnodeTrail{node: task.new, parent: task.trail}
+				warnDepthExceeded(&DepthError{Depth: task.depth, Limit: MaxDepth(), Path: trail.path()})
+				warned = true
 			}
+			continue
+		}
+		children := patchNode(task)
+		// Push in reverse so siblings are patched in document order.
+		for i := len(children) - 1; i >= 0; i-- {
+			pending = append(pending, children[i])
 		}
-		return
 	}
+}
 
-	// If tags are different, replace the entire element
-	if oldVNode.Tag != newVNode.Tag {
+// patchNode patches task.el itself and returns the child elements that still need patching.
+func patchNode(task patchTask) []patchTask {
+	domElement, oldVNode, newVNode := task.el, task.old, task.new
+	if !domElement.Truthy() || oldVNode == nil || newVNode == nil {
+		return nil
+	}
+
+	d := decidePatch(oldVNode, newVNode)
+	if d.Skip {
+		return nil
+	}
+
+	// Tags or component keys (router navigation) differ: replace the entire subtree
+	if d.Replace {
+		if oldVNode.Tag == newVNode.Tag {
+			console.Debug("[vdom] Component keys differ, replacing entire tree. Old:", oldVNode.ComponentKey, "New:", newVNode.ComponentKey)
+		}
 		// Release callbacks before replacing
 		deepReleaseCallbacks(oldVNode)
 
-		newElement := createElement(newVNode)
+		newElement := createElementAt(newVNode, task.depth, task.trail)
 		if newElement.Truthy() {
 			parent := domElement.Get("parentNode")
 			if parent.Truthy() {
-				parent.Call("replaceChild", newElement, domElement)
+				if t := transitionFor(oldVNode, newVNode); t != nil {
+					// The old element leaves after the new one, which takes its position
+					parent.Call("insertBefore", newElement, domElement)
+					leave(domElement, t)
+				} else {
+					parent.Call("replaceChild", newElement, domElement)
+				}
+				enter(newElement, transitionFor(newVNode, oldVNode))
 			}
 		}
-		return
+		return nil
 	}
 
 	// Same tag - update attributes
-	patchAttributes(domElement, oldVNode.Attributes, newVNode.Attributes)
-
-	// Update event listeners
-	// Release old callbacks and attach new ones
-	releaseCallbacks(oldVNode)
-	if newVNode.Attributes != nil {
-		attachEventListeners(domElement, newVNode, newVNode.Attributes)
-	}
-
-	// Update content for input/textarea elements
-	switch newVNode.Tag {
-	case "input", "textarea":
-		// Only update value if element is NOT currently focused
-		// This preserves the user's typing experience
-		isFocused := domElement.Call("matches", ":focus")
-		if !isFocused.Bool() && newVNode.Content != "" {
-			currentValue := domElement.Get("value").String()
-			if currentValue != newVNode.Content {
-				domElement.Set("value", newVNode.Content)
+	patchAttributes(domElement, d.Attrs)
+
+	// Keep the listeners whose handler is unchanged, replace the others
+	patchListeners(domElement, oldVNode, newVNode)
+
+	// The DOM element survives the patch, so hand its ref over to the new VNode.
+	// A ref that already moved to another element (keyed item shifted) is not cleared.
+	if oldVNode.Ref != nil && oldVNode.Ref != newVNode.Ref {
+		oldVNode.Ref.detach(oldVNode)
+	}
+	if newVNode.Ref != nil {
+		newVNode.Ref.attach(newVNode, domElement)
+	}
+
+	switch d.Content {
+	case contentValue:
+		switch newVNode.Tag {
+		case "input", "textarea":
+			// Only update value if element is NOT currently focused
+			// This preserves the user's typing experience
+			isFocused := domElement.Call("matches", ":focus")
+			if !isFocused.Bool() && newVNode.Content != "" {
+				currentValue := domElement.Get("value").String()
+				if currentValue != newVNode.Content {
+					domElement.Set("value", newVNode.Content)
+				}
 			}
 		}
-	case "select":
-		// For select elements, update the selected value
-		if newVNode.Content != "" {
-			domElement.Set("value", newVNode.Content)
-		}
-	default:
-		if len(newVNode.Children) == 0 {
-			// No children: update text content directly.
-			// Setting textContent wipes out all child nodes, so only do this when there are none.
-			if oldVNode.Content != newVNode.Content {
-				domElement.Set("textContent", newVNode.Content)
-			}
-			// Release callbacks on old children whose DOM nodes were cleared by textContent,
-			// then return — calling patchChildren would remove the text node we just created.
-			for _, child := range oldVNode.Children {
-				deepReleaseCallbacks(child)
-			}
-			return
-		} else {
-			if oldVNode.Content != "" {
-				// New VNode has children but old had text content set via textContent.
-				// Clear the text so children can be patched in cleanly without the
-				// old text node remaining in the DOM alongside the new child elements.
-				domElement.Set("textContent", "")
-			}
-			// Patch children
-			patchChildren(domElement, oldVNode.Children, newVNode.Children)
+	case contentText:
+		// No children: update text content directly. Setting textContent wipes out all
+		// child nodes, which also removes the DOM of any old children.
+		if d.ResetText {
+			domElement.Set("textContent", newVNode.Content)
+		}
+		// Release callbacks on old children whose DOM nodes were cleared by textContent,
+		// then return — calling patchChildren would remove the text node we just created.
+		for _, child := range oldVNode.Children {
+			deepReleaseCallbacks(child)
+		}
+	case contentHTML:
+		if d.ResetHTML {
+			domElement.Set("innerHTML", newVNode.UnsafeInnerHTML)
+		}
+		// Like textContent, innerHTML removed the DOM of any old children
+		for _, child := range oldVNode.Children {
+			deepReleaseCallbacks(child)
 		}
+	case contentChildren:
+		if d.ClearText {
+			// New VNode has children but old had text content set via textContent.
+			// Clear the text so children can be patched in cleanly without the
+			// old text node remaining in the DOM alongside the new child elements.
+			domElement.Set("textContent", "")
+		}
+		// Patch children
+		trail := &vnodeTrail{node: newVNode, parent: task.trail}
+		tasks := patchChildren(domElement, oldVNode.Children, newVNode.Children, task.depth+1, trail)
+		if d.SyncSelect {
+			// Last in the list, so patchElement runs it after every option is patched
+			index := d.SelectedIndex
+			tasks = append(tasks, patchTask{after: func() { domElement.Set("selectedIndex", index) }})
+		}
+		return tasks
 	}
+	return nil
 }
 
-// patchAttributes updates the attributes of a DOM element.
-func patchAttributes(domElement js.Value, oldAttrs, newAttrs map[string]any) {
-	// Remove old attributes that are not in new attributes
-	for key := range oldAttrs {
-		if _, exists := newAttrs[key]; !exists {
-			// Skip event handlers (they start with "on")
-			if len(key) > 2 && key[0] == 'o' && key[1] == 'n' {
-				continue
-			}
-			domElement.Call("removeAttribute", key)
+// patchAttributes applies the attribute updates computed by diffAttributes.
+func patchAttributes(domElement js.Value, patches []attrPatch) {
+	for _, p := range patches {
+		if p.Remove {
+			domElement.Call("removeAttribute", p.Key)
+			syncControlState(domElement, p.Key, nil)
+		} else {
+			setAttributeValue(domElement, p.Key, p.Value)
 		}
 	}
+}
 
-	// Set new attributes
-	for key, value := range newAttrs {
-		// Skip event handlers - they're attached separately
-		if len(key) > 2 && key[0] == 'o' && key[1] == 'n' {
-			continue
-		}
-
-		// Check if attribute changed
-		if oldAttrs == nil || oldAttrs[key] != value {
-			setAttributeValue(domElement, key, value)
-		}
+// patchChildren updates the children of a DOM element, which sit at depth below parent.
+// Inserted and removed children are handled here; children present on both sides are
+// returned as tasks for patchElement, since patching them never shifts their DOM position.
+func patchChildren(domElement js.Value, oldChildren, newChildren []*VNode, depth int, parent *vnodeTrail) []patchTask {
+	if plan, ok := planKeyedChildren(oldChildren, newChildren); ok {
+		return patchKeyedChildren(domElement, oldChildren, newChildren, plan, depth, parent)
 	}
-}
 
-// patchChildren updates the children of a DOM element.
-func patchChildren(domElement js.Value, oldChildren, newChildren []*VNode) {
 	oldLen := len(oldChildren)
 	newLen := len(newChildren)
 	minLen := oldLen
@@ -657,13 +610,12 @@ func patchChildren(domElement js.Value, oldChildren, newChildren []*VNode) {
 		minLen = newLen
 	}
 
-	// Get the DOM children (live NodeList).
-	domChildren := domElement.Get("childNodes")
-
-	// domIndex tracks the actual DOM child position. nil VNodes have no DOM counterpart,
-	// so the DOM index diverges from the VDOM index whenever nils are present.
+	// domIndex tracks the actual DOM child position (see childAt). nil VNodes have no DOM
+	// counterpart, so the DOM index diverges from the VDOM index whenever nils are present.
 	domIndex := 0
 
+	var tasks []patchTask
+
 	// Patch existing children up to minLen
 	for i := 0; i < minLen; i++ {
 		oldChild := oldChildren[i]
@@ -671,33 +623,29 @@ func patchChildren(domElement js.Value, oldChildren, newChildren []*VNode) {
 
 		if oldChild == nil && newChild != nil {
 			// Old was absent from DOM; insert new node at the current DOM position.
-			newChildEl := createElement(newChild)
+			newChildEl := createElementAt(newChild, depth, parent)
 			if newChildEl.Truthy() {
-				if domIndex < domChildren.Length() {
-					refChild := domChildren.Call("item", domIndex)
-					if refChild.Truthy() {
-						domElement.Call("insertBefore", newChildEl, refChild)
-					} else {
-						domElement.Call("appendChild", newChildEl)
-					}
+				if refChild := childAt(domElement, domIndex); refChild.Truthy() {
+					domElement.Call("insertBefore", newChildEl, refChild)
 				} else {
 					domElement.Call("appendChild", newChildEl)
 				}
+				enter(newChildEl, newChild.Transition)
 				domIndex++
 			}
 		} else if oldChild != nil && newChild == nil {
 			// Old existed in DOM; remove the node at the current DOM position.
 			deepReleaseCallbacks(oldChild)
-			childElement := domChildren.Call("item", domIndex)
-			if childElement.Truthy() {
-				domElement.Call("removeChild", childElement)
-			}
+			removeChild(domElement, childAt(domElement, domIndex), oldChild.Transition)
 			// Don't increment domIndex: after removal the next node slides into this slot.
 		} else if oldChild != nil && newChild != nil {
-			// Both exist — patch the DOM node at the current DOM position.
-			childElement := domChildren.Call("item", domIndex)
-			if childElement.Truthy() {
-				patchElement(childElement, oldChild, newChild)
+			// Both exist — patch the DOM node at the current DOM position. The same node
+			// (reused slot content) is already up to date and is skipped.
+			if oldChild != newChild {
+				childElement := childAt(domElement, domIndex)
+				if childElement.Truthy() {
+					tasks = append(tasks, patchTask{el: childElement, old: oldChild, new: newChild, depth: depth, trail: parent})
+				}
 			}
 			domIndex++
 		}
@@ -707,9 +655,10 @@ func patchChildren(domElement js.Value, oldChildren, newChildren []*VNode) {
 	// Add new children if newChildren is longer.
 	if newLen > oldLen {
 		for i := oldLen; i < newLen; i++ {
-			newChild := createElement(newChildren[i])
+			newChild := createElementAt(newChildren[i], depth, parent)
 			if newChild.Truthy() {
 				domElement.Call("appendChild", newChild)
+				enter(newChild, newChildren[i].Transition)
 			}
 		}
 	}
@@ -721,12 +670,165 @@ func patchChildren(domElement js.Value, oldChildren, newChildren []*VNode) {
 		for i := newLen; i < oldLen; i++ {
 			if oldChildren[i] != nil {
 				deepReleaseCallbacks(oldChildren[i])
-				childElement := domChildren.Call("item", domIndex)
-				if childElement.Truthy() {
-					domElement.Call("removeChild", childElement)
-				}
+				removeChild(domElement, childAt(domElement, domIndex), oldChildren[i].Transition)
 				// Don't increment domIndex after removal.
 			}
 		}
 	}
+	return tasks
+}
+
+// patchKeyedChildren carries out the keyed patch plan of the children of a DOM element:
+// it removes the nodes of the old children left out, moves and creates nodes as planned,
+// and returns the kept and moved children as tasks for patchElement, in document order.
+func patchKeyedChildren(domElement js.Value, oldChildren, newChildren []*VNode, plan keyedPlan, depth int, parent *vnodeTrail) []patchTask {
+	// Old children own the DOM nodes in order, skipping nil ones
+	oldElements := make([]js.Value, len(oldChildren))
+	domIndex := 0
+	for i, child := range oldChildren {
+		if child != nil {
+			oldElements[i] = childAt(domElement, domIndex)
+			domIndex++
+		}
+	}
+
+	for _, i := range plan.Removed {
+		deepReleaseCallbacks(oldChildren[i])
+		removeChild(domElement, oldElements[i], oldChildren[i].Transition)
+	}
+
+	var tasks []patchTask
+	next := js.Null() // The node placed after the current one; null appends
+	for _, op := range plan.Ops {
+		var element js.Value
+		if op.Kind == keyedCreate {
+			element = createElementAt(newChildren[op.New], depth, parent)
+		} else {
+			element = oldElements[op.Old]
+			if oldChildren[op.Old] != newChildren[op.New] && element.Truthy() {
+				tasks = append(tasks, patchTask{el: element, old: oldChildren[op.Old], new: newChildren[op.New], depth: depth, trail: parent})
+			}
+		}
+		if !element.Truthy() {
+			continue
+		}
+		if op.Kind != keyedKeep {
+			domElement.Call("insertBefore", element, next)
+		}
+		if op.Kind == keyedCreate {
+			enter(element, newChildren[op.New].Transition)
+		}
+		next = element
+	}
+	slices.Reverse(tasks)
+	return tasks
+}
+
+// leavingProperty marks, on a DOM element, that it is leaving with a transition.
+const leavingProperty = "__nojsLeaving"
+
+// leavingCount is the number of elements leaving with a transition. While it is zero,
+// childAt indexes childNodes directly.
+var leavingCount int
+
+// childAt returns the child node of parent at index, not counting the elements leaving with
+// a transition: those stay in the DOM until they have animated out, but no VNode stands for
+// them any more. It returns null when there is no such child.
+func childAt(parent js.Value, index int) js.Value {
+	nodes := parent.Get("childNodes")
+	if leavingCount == 0 {
+		return nodes.Call("item", index)
+	}
+	for i := 0; i < nodes.Length(); i++ {
+		node := nodes.Index(i)
+		if node.Get(leavingProperty).Truthy() {
+			continue
+		}
+		if index == 0 {
+			return node
+		}
+		index--
+	}
+	return js.Null()
+}
+
+// removeChild removes child from parent, letting it leave with t first when t is set.
+// The caller has released its callbacks.
+func removeChild(parent, child js.Value, t *Transition) {
+	if !child.Truthy() {
+		return
+	}
+	if t != nil {
+		leave(child, t)
+		return
+	}
+	parent.Call("removeChild", child)
+}
+
+// enter gives the element el, just inserted, the Enter class of t until the next frame.
+func enter(el js.Value, t *Transition) {
+	if t == nil || t.Enter == "" || el.Get("nodeType").Int() != 1 {
+		return
+	}
+	classList := el.Get("classList")
+	classList.Call("add", t.Enter)
+	raf := js.Global().Get("requestAnimationFrame")
+	if !raf.Truthy() {
+		classList.Call("remove", t.Enter)
+		return
+	}
+	// Reading the layout applies the Enter styles, so removing the class transitions from them
+	el.Get("offsetWidth")
+	var cb js.Func
+	cb = js.FuncOf(func(js.Value, []js.Value) any {
+		classList.Call("remove", t.Enter)
+		cb.Release()
+		return nil
+	})
+	raf.Invoke(cb)
+}
+
+// leave gives the element el the Leave class of t and removes it once its transitionend
+// fires or the timeout of t elapses, whichever comes first. Until then childAt skips it.
+func leave(el js.Value, t *Transition) {
+	if t.Leave == "" || el.Get("nodeType").Int() != 1 {
+		el.Call("remove")
+		return
+	}
+	el.Set(leavingProperty, true)
+	leavingCount++
+	classList := el.Get("classList")
+	if t.Enter != "" {
+		classList.Call("remove", t.Enter)
+	}
+	classList.Call("add", t.Leave)
+
+	var onEnd, onTimeout js.Func
+	var timer js.Value
+	finished := false
+	finish := func() {
+		if finished {
+			return
+		}
+		finished = true
+		leavingCount--
+		js.Global().Call("clearTimeout", timer)
+		el.Call("removeEventListener", "transitionend", onEnd)
+		el.Call("remove")
+		onEnd.Release()
+		onTimeout.Release()
+	}
+	onEnd = js.FuncOf(func(this js.Value, args []js.Value) any {
+		// transitionend bubbles: only the element's own transition ends the leave
+		if len(args) > 0 && args[0].Get("target").Equal(el) {
+			finish()
+		}
+		return nil
+	})
+	onTimeout = js.FuncOf(func(js.Value, []js.Value) any {
+		finish()
+		return nil
+	})
+	el.Call("addEventListener", "transitionend", onEnd)
+	timer = js.Global().Call("setTimeout", onTimeout, t.leaveTimeout().Milliseconds())
 }
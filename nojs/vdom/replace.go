@@ -0,0 +1,76 @@
+package vdom
+
+// A component re-rendered on its own replaces the subtree it rendered last time. The node
+// at the top of that subtree is updated in place rather than swapped for the new one: the
+// same node may also be held by a layout's slot content or a renderer's caches, which then
+// see the change, and a host re-rendering that slot later skips the node as unchanged.
+
+// ReplaceNode makes node, a node of the tree rooted at root, show next instead: next's
+// fields move into node, which keeps the Key and ComponentKey its parent gave it. It
+// reports false, changing nothing, when node is not rendered in the tree at root or next
+// has another tag; the tree must then be rendered again. The browser renderer uses
+// PatchNode, which also patches the DOM.
+func ReplaceNode(root, node, next *VNode) bool {
+	if node == nil || next == nil || node.Tag != next.Tag {
+		return false
+	}
+	if _, _, _, ok := locate(root, node); !ok {
+		return false
+	}
+	adopt(node, next)
+	return true
+}
+
+// locate finds node in the tree rooted at root. It returns the DOM child indexes leading
+// to node's element from root's, the depth of node and the trail of its parent. Like the
+// patcher, it counts no DOM node for nil children and does not look below nodes whose
+// element never gets children.
+func locate(root, node *VNode) (path []int, depth int, parent *vnodeTrail, ok bool) {
+	var found *vnodeTrail
+	walkTrail(root, 1, nil, func(n *VNode, d int, trail *vnodeTrail) bool {
+		if found != nil {
+			return false
+		}
+		if n == node {
+			found, depth = trail, d
+			return false
+		}
+		return acceptsChildren(n)
+	})
+	if found == nil {
+		return nil, 0, nil, false
+	}
+
+	// The trail runs from node up to root; the path runs down
+	path = make([]int, depth-1)
+	for t, i := found, depth-2; t.parent != nil; t, i = t.parent, i-1 {
+		path[i] = domIndex(t.parent.node.Children, t.node)
+	}
+	return path, depth, found.parent, true
+}
+
+// domIndex returns the index among its parent's DOM children of the element of child, one
+// of children: nil children before it have no DOM node.
+func domIndex(children []*VNode, child *VNode) int {
+	index := 0
+	for _, sibling := range children {
+		if sibling == child {
+			break
+		}
+		if sibling != nil {
+			index++
+		}
+	}
+	return index
+}
+
+// adopt moves next's fields into node, keeping the keys node's parent gave it. A ref
+// attached to next's element is handed over to node.
+func adopt(node, next *VNode) {
+	key, componentKey := node.Key, node.ComponentKey
+	*node = *next
+	node.Key, node.ComponentKey = key, componentKey
+	if node.Ref != nil && node.Ref.owner == next {
+		node.Ref.owner = node
+	}
+}
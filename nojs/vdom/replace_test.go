@@ -0,0 +1,104 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestLocate_SkipsNilSiblings verifies the DOM path of a node counts only the siblings
+// before it that render a DOM node.
+func TestLocate_SkipsNilSiblings(t *testing.T) {
+	// Arrange
+	target := Paragraph("target", nil)
+	list := NewVNode("ul", nil, []*VNode{nil, NewVNode("li", nil, nil, "a"), nil, target}, "")
+	root := Div(nil, Paragraph("intro", nil), nil, list)
+
+	// Act
+	path, depth, parent, ok := locate(root, target)
+
+	// Assert
+	if !ok {
+		t.Fatal("expected the target to be found")
+	}
+	if !slices.Equal(path, []int{1, 1}) {
+		t.Errorf("expected DOM path [1 1], got %v", path)
+	}
+	if depth != 3 {
+		t.Errorf("expected depth 3, got %d", depth)
+	}
+	if parent == nil || parent.node != list {
+		t.Error("expected the trail of the target's parent")
+	}
+}
+
+// TestLocate_IgnoresNodesWithoutDOMChildren verifies a node below an element that never
+// gets children, such as a <textarea>, is not found.
+func TestLocate_IgnoresNodesWithoutDOMChildren(t *testing.T) {
+	// Arrange
+	hidden := Text("draft")
+	root := Div(nil, NewVNode("textarea", nil, []*VNode{hidden}, ""))
+
+	// Act
+	_, _, _, ok := locate(root, hidden)
+
+	// Assert
+	if ok {
+		t.Error("expected a node below a <textarea> not to be found")
+	}
+}
+
+// TestReplaceNode_UpdatesNodeInPlace verifies the replaced node keeps its identity and the
+// keys its parent gave it, and takes over the ref of the new node.
+func TestReplaceNode_UpdatesNodeInPlace(t *testing.T) {
+	// Arrange
+	ref := &ElementRef{}
+	node := NewVNode("section", map[string]any{"class": "card"}, nil, "old")
+	node.Key = 7
+	root := Div(nil, node)
+	next := NewVNode("section", map[string]any{"class": "card active"}, []*VNode{Text("new")}, "")
+	next.Ref = ref
+	ref.attach(next, "element")
+
+	// Act
+	ok := ReplaceNode(root, node, next)
+
+	// Assert
+	if !ok {
+		t.Fatal("expected the node to be replaced")
+	}
+	if root.Children[0] != node {
+		t.Error("expected the tree to keep the same node")
+	}
+	if node.Attributes["class"] != "card active" || node.Content != "" || len(node.Children) != 1 {
+		t.Errorf("expected the node to show the new content, got %+v", node)
+	}
+	if node.Key != 7 {
+		t.Errorf("expected the node to keep its key, got %v", node.Key)
+	}
+	if ref.owner != node {
+		t.Error("expected the ref to be handed over to the node")
+	}
+}
+
+// TestReplaceNode_RejectsTagChangeOrMissingNode verifies nothing changes when the new node
+// has another tag or the node is not in the tree.
+func TestReplaceNode_RejectsTagChangeOrMissingNode(t *testing.T) {
+	// Arrange
+	node := Paragraph("old", nil)
+	root := Div(nil, node)
+
+	// Act
+	tagChanged := ReplaceNode(root, node, Div(nil))
+	missing := ReplaceNode(Div(nil), node, Paragraph("new", nil))
+
+	// Assert
+	if tagChanged || missing {
+		t.Errorf("expected both replacements to be rejected, got %v and %v", tagChanged, missing)
+	}
+	if node.Content != "old" {
+		t.Errorf("expected the node to be unchanged, got %q", node.Content)
+	}
+}
@@ -0,0 +1,101 @@
+package vdom
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// voidElements have no closing tag and no children.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// attributeNameRegex matches the attribute names RenderToString writes; others are dropped.
+var attributeNameRegex = regexp.MustCompile(`^[A-Za-z_:][-A-Za-z0-9_:.]*$`)
+
+// RenderToString returns the HTML of the tree rooted at n, escaping each value for its
+// context (see OutputContext): text with EscapeText, attributes with EscapeAttribute, and URL
+// attributes sanitized first. Event handlers and refs are not part of the output. Attributes
+// are written in name order, so the output of a tree is stable. A bound <select> marks the
+// option its value selects (see SelectedOptionIndex) with the selected attribute. The
+// UnsafeInnerHTML of an element is written as is.
+func RenderToString(n *VNode) string {
+	var b strings.Builder
+	writeHTML(&b, n, false)
+	return b.String()
+}
+
+func writeHTML(b *strings.Builder, n *VNode, selected bool) {
+	if n == nil {
+		return
+	}
+	if n.Tag == "#text" {
+		b.WriteString(EscapeText(n.Content))
+		return
+	}
+
+	tag := strings.ToLower(n.Tag)
+	b.WriteString("<" + tag)
+	names := make([]string, 0, len(n.Attributes))
+	for name := range n.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if tag == "select" && (name == selectValueAttr || name == selectFallbackAttr) {
+			continue // Rendered as the selected option
+		}
+		writeAttribute(b, name, n.Attributes[name])
+	}
+	if tag == "input" && n.Content != "" {
+		writeAttribute(b, "value", n.Content) // The DOM renderer sets the value property
+	}
+	if selected {
+		b.WriteString(" selected")
+	}
+	b.WriteString(">")
+	if voidElements[tag] {
+		return
+	}
+
+	if n.UnsafeInnerHTML != "" {
+		b.WriteString(n.UnsafeInnerHTML) // Trusted markup, written as the DOM renderer sets it
+		b.WriteString("</" + tag + ">")
+		return
+	}
+	if tag != "input" && tag != "select" {
+		b.WriteString(EscapeText(n.Content))
+	}
+	selectedIndex, _ := SelectedOptionIndex(n)
+	option := 0
+	for _, child := range n.Children {
+		isOption := tag == "select" && child != nil && child.Tag == "option"
+		writeHTML(b, child, isOption && option == selectedIndex)
+		if isOption {
+			option++
+		}
+	}
+	b.WriteString("</" + tag + ">")
+}
+
+// writeAttribute writes name="value", name alone for a true boolean, and nothing for false
+// booleans, functions (event handlers) and names that are not valid attribute names.
+func writeAttribute(b *strings.Builder, name string, value any) {
+	if !attributeNameRegex.MatchString(name) || value == nil {
+		return
+	}
+	if on, ok := value.(bool); ok {
+		if on {
+			b.WriteString(" " + name)
+		}
+		return
+	}
+	if reflect.TypeOf(value).Kind() == reflect.Func {
+		return
+	}
+	fmt.Fprintf(b, ` %s="%s"`, name, Escape(ContextOf(name), fmt.Sprint(value)))
+}
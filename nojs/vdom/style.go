@@ -0,0 +1,28 @@
+package vdom
+
+import (
+	"sort"
+	"strings"
+)
+
+// FormatStyle returns the style attribute value for styles ([style]="{Field}" in
+// templates): "property: value" declarations in property order, separated by "; ". The order
+// is fixed so re-rendering the same map yields the same string and the attribute is not
+// patched because of map iteration order. Properties with an empty value are left out.
+func FormatStyle(styles map[string]string) string {
+	properties := make([]string, 0, len(styles))
+	for property, value := range styles {
+		if value != "" {
+			properties = append(properties, property)
+		}
+	}
+	sort.Strings(properties)
+	var b strings.Builder
+	for i, property := range properties {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(property + ": " + styles[property])
+	}
+	return b.String()
+}
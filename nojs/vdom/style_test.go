@@ -0,0 +1,29 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import "testing"
+
+// TestFormatStyle_SortedAndStable verifies declarations come out in property order, the same
+// on every call, and that empty values and maps produce nothing.
+func TestFormatStyle_SortedAndStable(t *testing.T) {
+	// Arrange
+	styles := map[string]string{"width": "40%", "color": "red", "background-color": "#fff", "margin": ""}
+
+	// Act
+	first := FormatStyle(styles)
+
+	// Assert
+	if want := "background-color: #fff; color: red; width: 40%"; first != want {
+		t.Errorf("expected %q, got %q", want, first)
+	}
+	for i := 0; i < 20; i++ {
+		if got := FormatStyle(styles); got != first {
+			t.Fatalf("call %d gave %q after %q", i, got, first)
+		}
+	}
+	if got := FormatStyle(nil); got != "" {
+		t.Errorf("expected an empty style for nil, got %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package vdom
+
+import "time"
+
+// DefaultTransitionTimeout bounds the wait for the transitionend event of a leaving element
+// whose Transition sets no Timeout.
+const DefaultTransitionTimeout = 500 * time.Millisecond
+
+// Transition animates an element in and out with CSS classes. When the patcher inserts the
+// element, it gets the Enter class, removed on the next frame, so a CSS transition runs from
+// the Enter styles to the element's own. When the patcher removes it, it gets the Leave class
+// and stays in the DOM until its transitionend event fires or Timeout elapses. A leaving
+// element is inert: its listeners and ref are released at once, and the patcher skips it
+// when it locates children by position.
+//
+// Only the inserted or removed node animates, not the nodes of its subtree. The first render
+// of a page does not animate. When a node is replaced, because its tag or component key
+// changed as on a route change, the new node enters and the old one leaves, with the
+// Transition of either: the incoming page's Transition animates the outgoing one too.
+//
+//	.fade-enter, .fade-leave { opacity: 0; }
+//	.toast { transition: opacity 200ms; }
+//
+// Templates set it with transition="fade" (classes "fade-enter" and "fade-leave") and an
+// optional transition-timeout="300ms".
+// The DOM side lives in render.go (enter and leave).
+type Transition struct {
+	Enter   string        // Class of the element on the frame it is inserted in
+	Leave   string        // Class of the element while it leaves
+	Timeout time.Duration // Longest wait for transitionend; DefaultTransitionTimeout when zero
+}
+
+// NamedTransition returns the Transition of name, whose classes are name-enter and
+// name-leave, e.g. "fade-enter" and "fade-leave" for "fade".
+func NamedTransition(name string, timeout time.Duration) *Transition {
+	return &Transition{Enter: name + "-enter", Leave: name + "-leave", Timeout: timeout}
+}
+
+// WithTransition sets the NamedTransition of name on n and returns n. Generated code uses it
+// for the transition attribute.
+func WithTransition(n *VNode, name string, timeout time.Duration) *VNode {
+	if n != nil {
+		n.Transition = NamedTransition(name, timeout)
+	}
+	return n
+}
+
+// leaveTimeout returns how long an element leaving with t stays in the DOM at most.
+func (t *Transition) leaveTimeout() time.Duration {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+	return DefaultTransitionTimeout
+}
+
+// transitionFor returns the Transition n enters or leaves with when it replaces other, or
+// other replaces it: its own, or else that of other. nil when neither has one.
+func transitionFor(n, other *VNode) *Transition {
+	if n != nil && n.Transition != nil {
+		return n.Transition
+	}
+	if other != nil {
+		return other.Transition
+	}
+	return nil
+}
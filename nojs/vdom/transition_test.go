@@ -0,0 +1,64 @@
+//go:build !wasm
+// +build !wasm
+
+package vdom
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWithTransition_NamedClassesAndTimeout verifies a named transition gets the -enter and
+// -leave classes, and that an element without a timeout leaves within the default one.
+func TestWithTransition_NamedClassesAndTimeout(t *testing.T) {
+	// Act
+	n := WithTransition(NewVNode("li", nil, nil, "Saved"), "fade", 0)
+	slow := NamedTransition("slide", 2*time.Second)
+
+	// Assert
+	if n.Transition.Enter != "fade-enter" || n.Transition.Leave != "fade-leave" {
+		t.Errorf("expected the fade classes, got %+v", n.Transition)
+	}
+	if got := n.Transition.leaveTimeout(); got != DefaultTransitionTimeout {
+		t.Errorf("expected the default timeout, got %v", got)
+	}
+	if got := slow.leaveTimeout(); got != 2*time.Second {
+		t.Errorf("expected the timeout set, got %v", got)
+	}
+}
+
+// TestTransitionFor_EitherSideOfReplacement verifies a replaced node and its replacement
+// each animate with their own Transition, or else with the other's.
+func TestTransitionFor_EitherSideOfReplacement(t *testing.T) {
+	// Arrange
+	page := WithTransition(Div(nil), "page", 0)
+	plain := Div(nil)
+
+	// Act & Assert
+	if transitionFor(plain, page) != page.Transition || transitionFor(page, plain) != page.Transition {
+		t.Error("expected the page transition on both sides")
+	}
+	if transitionFor(plain, Div(nil)) != nil {
+		t.Error("expected no transition when neither side has one")
+	}
+}
+
+// TestDecidePatch_KeyedTransitionReplacesUnkeyed verifies a keyed node with a Transition,
+// such as the page of a route with a transition, replaces an unkeyed node instead of
+// patching it, while a keyed node without one is patched as before.
+func TestDecidePatch_KeyedTransitionReplacesUnkeyed(t *testing.T) {
+	// Arrange
+	old := Div(nil, Text("Home"))
+	page := WithTransition(Div(nil, Text("About")), "page", 0)
+	page.ComponentKey = "about"
+	keyed := Div(nil, Text("About"))
+	keyed.ComponentKey = "about"
+
+	// Act & Assert
+	if !decidePatch(old, page).Replace {
+		t.Error("expected the page with a transition to replace the old one")
+	}
+	if decidePatch(old, keyed).Replace {
+		t.Error("expected a keyed node without a transition to be patched")
+	}
+}
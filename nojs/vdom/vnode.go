@@ -3,19 +3,30 @@ package vdom
 // VNode represents a virtual DOM node.
 // This core file has NO build tags, making it available to both WASM and native test builds.
 type VNode struct {
-	Tag            string         // The HTML tag name
-	Attributes     map[string]any // The attributes of the node
-	Children       []*VNode       // The child nodes
-	Content        string         // The content of the node
-	OnClick        func()         // Optional click event handler
-	Key            any            // Optional key for list reconciliation (used in {@for} loops)
-	ComponentKey   string         // Key for component-level reconciliation (used in router navigation)
-	eventCallbacks []any          // Stores js.Func objects for cleanup (interface{} to avoid build tag issues)
+	Tag             string            // The HTML tag name
+	Attributes      map[string]any    // The attributes of the node
+	Children        []*VNode          // The child nodes
+	Content         string            // The content of the node
+	OnClick         func()            // Optional click event handler
+	Key             any               // Optional key for list reconciliation (used in {@for} loops)
+	ComponentKey    string            // Key for component-level reconciliation (used in router navigation)
+	Ref             *ElementRef       // Optional ref populated with the rendered DOM element (ref="Field" in templates)
+	UnsafeInnerHTML string            // Markup set as the element's innerHTML in place of Children and Content; never escaped
+	HandlerKeys     map[string]string // Stable identity of each event handler, by attribute key (see HandlerKeysAttr)
+	Transition      *Transition       // Optional classes animating the element in and out (transition="fade" in templates)
+	eventCallbacks  []any             // Stores js.Func objects for cleanup (interface{} to avoid build tag issues)
 }
 
+// HandlerKeysAttr is the attributes map entry, a map[string]string, that NewVNode moves to
+// HandlerKeys. Generated code uses it to name the method behind each event handler
+// ("onClick": "Counter.Increment"): handlers are closures built on every render, so the
+// patcher cannot compare them, but it can keep a listener whose handler key is unchanged.
+const HandlerKeysAttr = "#handlers"
+
 // NewVNode creates a new VNode.
 func NewVNode(tag string, attributes map[string]any, children []*VNode, content string) *VNode {
 	var onClick func()
+	var handlerKeys map[string]string
 	if attributes != nil {
 		if v, ok := attributes["onClick"]; ok {
 			if f, ok := v.(func()); ok {
@@ -24,16 +35,30 @@ func NewVNode(tag string, attributes map[string]any, children []*VNode, content
 				delete(attributes, "onClick")
 			}
 		}
+		if v, ok := attributes[HandlerKeysAttr]; ok {
+			handlerKeys, _ = v.(map[string]string)
+			delete(attributes, HandlerKeysAttr)
+		}
 	}
 	return &VNode{
-		Tag:        tag,
-		Attributes: attributes,
-		Children:   children,
-		Content:    content,
-		OnClick:    onClick,
+		Tag:         tag,
+		Attributes:  attributes,
+		Children:    children,
+		Content:     content,
+		OnClick:     onClick,
+		HandlerKeys: handlerKeys,
 	}
 }
 
+// RawHTML creates an element whose content is the markup html, set through innerHTML
+// without escaping ([innerhtml]="{Field}" in templates). html must come from a trusted
+// source or a sanitizer: any script URL or event handler attribute in it is live.
+func RawHTML(tag string, attributes map[string]any, html string) *VNode {
+	n := NewVNode(tag, attributes, nil, "")
+	n.UnsafeInnerHTML = html
+	return n
+}
+
 // SetContent updates the Content field of the VNode.
 func (v *VNode) SetContent(content string) {
 	v.Content = content
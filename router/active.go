@@ -0,0 +1,44 @@
+package router
+
+import (
+	"slices"
+	"strings"
+)
+
+// IsActive reports whether the app-relative path, e.g. the Href of a navigation link, points
+// to the path shown. With exact unset, it also does for the paths below it: /admin is active
+// on /admin/settings (but not on /administration), and / on every path. A query or fragment
+// in path is ignored.
+func (e *Engine) IsActive(path string, exact bool) bool {
+	path, _, _ = splitURL(path)
+	path = normalizeRoutePath(path)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch {
+	case e.currentPath == "":
+		return false // Not started
+	case path == e.currentPath:
+		return true
+	case exact:
+		return false
+	}
+	return path == "/" || strings.HasPrefix(e.currentPath, path+"/")
+}
+
+// SubscribePathChange registers fn to run with the app-relative path shown after every
+// navigation that changes it; navigations changing only the query are not reported. It runs
+// without the engine locked, so it may call IsActive. Components subscribe in OnMount and
+// call the returned function in OnUnmount, so that links removed from the page are not kept
+// by the engine.
+func (e *Engine) SubscribePathChange(fn func(newPath string)) (unsubscribe func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	entry := &fn
+	e.hooks.paths = append(e.hooks.paths, entry)
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.hooks.paths = slices.DeleteFunc(e.hooks.paths, func(f *func(string)) bool { return f == entry })
+	}
+}
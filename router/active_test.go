@@ -0,0 +1,97 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// TestIsActive_MatchesPathAndPrefixes verifies exact and prefix matching against the path
+// shown.
+func TestIsActive_MatchesPathAndPrefixes(t *testing.T) {
+	// Arrange
+	engine := startTestEngine(t, &memoryHistory{path: "/admin/settings", query: "?tab=2"}, interceptRoutes...)
+
+	tests := []struct {
+		path  string
+		exact bool
+		want  bool
+	}{
+		{"/admin/settings", true, true},
+		{"/admin/settings/", true, true},
+		{"/admin/settings?tab=3", true, true},
+		{"/admin", true, false},
+		{"/admin", false, true},
+		{"/adm", false, false},
+		{"/", true, false},
+		{"/", false, true},
+		{"/admin/settings/advanced", false, false},
+	}
+	for _, tt := range tests {
+		// Act
+		got := engine.IsActive(tt.path, tt.exact)
+
+		// Assert
+		if got != tt.want {
+			t.Errorf("IsActive(%q, %v) = %v, want %v", tt.path, tt.exact, got, tt.want)
+		}
+	}
+}
+
+// TestSubscribePathChange_ReportsPathChanges verifies subscribers hear about new paths only,
+// may query the engine while notified, and stop hearing after unsubscribing.
+func TestSubscribePathChange_ReportsPathChanges(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/"}
+	engine := startTestEngine(t, history, interceptRoutes...)
+	var seen []string
+	unsubscribe := engine.SubscribePathChange(func(newPath string) {
+		if engine.IsActive("/users", false) {
+			newPath += " (users active)"
+		}
+		seen = append(seen, newPath)
+	})
+
+	// Act
+	engine.Navigate("/users/1")
+	engine.Navigate("/users/1?tab=posts")
+	engine.Navigate("/login")
+	history.travel(-1)
+	unsubscribe()
+	engine.Navigate("/form")
+
+	// Assert
+	want := []string{"/users/1 (users active)", "/login", "/users/1 (users active)"}
+	if !slices.Equal(seen, want) {
+		t.Errorf("expected %v, got %v", want, seen)
+	}
+}
+
+// navLink reads the engine through injection, like a RouterLink.
+type navLink struct {
+	runtime.ComponentBase
+	Router *Engine `nojs:"inject"`
+}
+
+func (l *navLink) Render(r runtime.Renderer) *vdom.VNode { return vdom.NewVNode("a", nil, nil, "") }
+
+// TestEngine_IsInjected verifies components receive the engine through their renderer.
+func TestEngine_IsInjected(t *testing.T) {
+	// Arrange
+	renderer := newRouteTestRenderer(&guardPage{name: "root"})
+	engine := NewEngine(renderer)
+	link := &navLink{}
+
+	// Act
+	renderer.RenderChild("link", link)
+
+	// Assert
+	if link.Router != engine {
+		t.Errorf("expected the engine to be injected, got %v", link.Router)
+	}
+}
@@ -1,9 +1,9 @@
-//go:build js || wasm
-
 package router
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/ForgeLogic/nojs/console"
 	"github.com/ForgeLogic/nojs/runtime"
@@ -13,15 +13,44 @@ import (
 // AppShell is a stable root component that holds persistent layouts (app shell)
 // and swaps only the BodyContent slot when navigation occurs. This preserves
 // layout instances and their internal state across navigations including sublayouts.
+//
+// A navigation swaps the page in one step: the shell renders the new chain to a complete
+// VNode tree before handing it to the layout, so the slot is never rendered empty, and the
+// root node of every page and sublayout carries a component key, so the patcher replaces
+// the old subtree with the new one in a single DOM operation. If rendering the new chain
+// panics, the old page stays on screen. A route with a Transition cross-fades the old and
+// the new page instead.
 type AppShell struct {
 	runtime.ComponentBase
 
+	// Route is the engine's RouteContext, injected when the shell mounts. The shell reads
+	// the Transition of the route being navigated to from it.
+	Route *RouteContext `nojs:"inject"`
+
 	// persistent layout instance (app shell)
 	persistentLayout runtime.Component
 
 	// current chain of component instances (all from router, volatile)
 	currentChain []runtime.Component
 	currentKey   string
+
+	mu         sync.Mutex
+	pending    *pendingPage  // Set by SetPage until the next Render swaps it in
+	page       *vdom.VNode   // Page tree of the current chain, as last rendered
+	slot       []*vdom.VNode // Slot content: one lane, or two once a transition has run
+	live       int           // Lane of slot holding the current page
+	fading     *Transition   // Transition in progress, nil when none
+	generation int           // Incremented on every swap; a transition only ends its own swap
+
+	// after runs fn once d has elapsed (time.AfterFunc when nil); tests replace it.
+	after func(d time.Duration, fn func())
+}
+
+// pendingPage is a chain passed to SetPage and not yet rendered.
+type pendingPage struct {
+	chain      []runtime.Component
+	key        string
+	transition *Transition
 }
 
 // NewAppShell creates a new AppShell with the given persistent layout component.
@@ -36,33 +65,38 @@ func NewAppShell(persistentLayout runtime.Component) *AppShell {
 // SetPage replaces the volatile chain of component instances and triggers a re-render.
 // The chain includes components from the router (from pivot onwards).
 // When pivot > 0, the chain doesn't include the persistent layout (it's preserved).
+// The current page stays in the slot until the new chain has rendered.
 func (a *AppShell) SetPage(chain []runtime.Component, key string) {
-	console.Log("[AppShell.SetPage] Called with", len(chain), "components, key:", key)
+	console.Debug("[AppShell.SetPage] Called with", len(chain), "components, key:", key)
 	if len(chain) > 0 {
-		console.Log("[AppShell.SetPage] First component type:", fmt.Sprintf("%T", chain[0]))
+		console.Debugf("[AppShell.SetPage] First component type: %T", chain[0])
 	}
 
 	// If the chain doesn't include persistentLayout at index 0, prepend it
 	// (this happens when pivot > 0 and layouts are preserved)
 	if len(chain) == 0 || chain[0] != a.persistentLayout {
-		console.Log("[AppShell.SetPage] Prepending persistentLayout to chain")
+		console.Debug("[AppShell.SetPage] Prepending persistentLayout to chain")
 		fullChain := make([]runtime.Component, 0, len(chain)+1)
 		fullChain = append(fullChain, a.persistentLayout)
 		fullChain = append(fullChain, chain...)
-		a.currentChain = fullChain
-	} else {
-		a.currentChain = chain
+		chain = fullChain
+	}
+
+	var transition *Transition
+	if a.Route != nil {
+		transition = a.Route.Transition
 	}
-	a.currentKey = key
+	a.mu.Lock()
+	a.pending = &pendingPage{chain: chain, key: key, transition: transition}
+	a.mu.Unlock()
 
-	console.Log("[AppShell.SetPage] Calling StateHasChanged")
+	console.Debug("[AppShell.SetPage] Calling StateHasChanged")
 	a.StateHasChanged()
 }
 
-// Render composes the persistent layout with the current component chain.
+// Render composes the persistent layout with the current component chain. A chain set by
+// SetPage becomes current only once it has rendered without panicking.
 func (a *AppShell) Render(r runtime.Renderer) *vdom.VNode {
-	console.Log("[AppShell.Render] Called, chain length:", len(a.currentChain))
-
 	type rendererSetter interface {
 		SetRenderer(runtime.Renderer)
 	}
@@ -74,72 +108,194 @@ func (a *AppShell) Render(r runtime.Renderer) *vdom.VNode {
 		}
 	}
 
-	// Link the chain: inject each child into parent's BodyContent slot
-	var slotChildren []*vdom.VNode
-	if len(a.currentChain) > 0 {
-		console.Log("[AppShell.Render] Processing chain")
-		chainIndex := 0
-		if a.currentChain[0] == a.persistentLayout {
-			console.Log("[AppShell.Render] First component is persistentLayout, skipping")
-			chainIndex = 1
+	a.mu.Lock()
+	chain, key, pending := a.currentChain, a.currentKey, a.pending
+	a.pending = nil
+	a.mu.Unlock()
+	if pending != nil {
+		chain, key = pending.chain, pending.key
+	}
+	console.Debug("[AppShell.Render] Called, chain length:", len(chain))
+
+	page, err := a.renderChain(r, chain)
+
+	a.mu.Lock()
+	switch {
+	case err != nil:
+		console.Error("[AppShell.Render] Rendering", key, "failed, keeping the current page:", err.Error())
+	case pending != nil:
+		a.currentChain, a.currentKey = chain, key
+		a.swap(page, pending.transition)
+	default:
+		a.page = page
+		if len(a.slot) > 0 {
+			a.slot[a.live] = withClass(page, a.fading.enterClass())
 		}
+	}
+	slotChildren := append([]*vdom.VNode(nil), a.slot...)
+	a.mu.Unlock()
 
-		// Link bottom-up: leaf → root
-		for i := len(a.currentChain) - 1; i > chainIndex; i-- {
-			child := a.currentChain[i]
-			parent := a.currentChain[i-1]
+	// Inject into layout's BodyContent slot
+	if a.persistentLayout != nil {
+		if layout, ok := a.persistentLayout.(interface{ SetBodyContent([]*vdom.VNode) }); ok {
+			layout.SetBodyContent(slotChildren)
+		}
+		return r.RenderChild("persistent-layout", a.persistentLayout)
+	}
 
-			if rs, ok := interface{}(child).(rendererSetter); ok {
-				rs.SetRenderer(r)
-			}
+	// Fallback: no layout, the page is the root
+	if page != nil {
+		return page
+	}
+	return vdom.NewVNode("div", nil, nil, "")
+}
 
-			slotKey := fmt.Sprintf("slot-chain-%d-%T-%p", i, child, child)
-			childVNode := r.RenderChild(slotKey, child)
-			if childVNode != nil {
-				console.Log("[AppShell.Render] Linking", fmt.Sprintf("%T", child), "into", fmt.Sprintf("%T", parent))
-				if layout, ok := parent.(interface{ SetBodyContent([]*vdom.VNode) }); ok {
-					layout.SetBodyContent([]*vdom.VNode{childVNode})
-				}
-			}
+// renderChain renders chain below the persistent layout and returns the tree of its first
+// non-layout component. A panic in any Render is returned as an error, including one the
+// renderer recovered for a component of the chain (see runtime.LastRenderError).
+func (a *AppShell) renderChain(r runtime.Renderer, chain []runtime.Component) (page *vdom.VNode, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			page, err = nil, fmt.Errorf("%v", rec)
 		}
+	}()
 
-		// Render the first non-layout component in the chain
-		if chainIndex < len(a.currentChain) {
-			rootComponent := a.currentChain[chainIndex]
-			console.Log("[AppShell.Render] Rendering root component at index", chainIndex, "type:", fmt.Sprintf("%T", rootComponent))
+	type rendererSetter interface {
+		SetRenderer(runtime.Renderer)
+	}
 
-			if rs, ok := interface{}(rootComponent).(rendererSetter); ok {
-				rs.SetRenderer(r)
-			}
+	// Link the chain: inject each child into parent's BodyContent slot
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	chainIndex := 0
+	if chain[0] == a.persistentLayout {
+		chainIndex = 1
+	}
 
-			slotKey := fmt.Sprintf("slot-root-%T-%p", rootComponent, rootComponent)
-			childVNode := r.RenderChild(slotKey, rootComponent)
-			if childVNode != nil {
-				slotChildren = []*vdom.VNode{childVNode}
+	// Link bottom-up: leaf → root
+	for i := len(chain) - 1; i > chainIndex; i-- {
+		child := chain[i]
+		parent := chain[i-1]
+
+		if rs, ok := interface{}(child).(rendererSetter); ok {
+			rs.SetRenderer(r)
+		}
+		// The child renders before its layout, but sees the context values the layout provides
+		runtime.SetContextParent(child, parent)
+
+		slotKey := fmt.Sprintf("slot-chain-%d-%T-%p", i, child, child)
+		childVNode := r.RenderChild(slotKey, child)
+		if err := runtime.LastRenderError(child); err != nil {
+			return nil, err
+		}
+		if childVNode != nil {
+			childVNode.ComponentKey = slotKey
+			console.Debugf("[AppShell.Render] Linking %T into %T", child, parent)
+			if layout, ok := parent.(interface{ SetBodyContent([]*vdom.VNode) }); ok {
+				layout.SetBodyContent([]*vdom.VNode{childVNode})
 			}
 		}
 	}
 
-	// Inject into layout's BodyContent slot
-	if a.persistentLayout != nil {
-		if layout, ok := a.persistentLayout.(interface{ SetBodyContent([]*vdom.VNode) }); ok {
-			layout.SetBodyContent(slotChildren)
-		}
-		return r.RenderChild("persistent-layout", a.persistentLayout)
+	// Render the first non-layout component in the chain
+	if chainIndex >= len(chain) {
+		return nil, nil
+	}
+	rootComponent := chain[chainIndex]
+	if rs, ok := interface{}(rootComponent).(rendererSetter); ok {
+		rs.SetRenderer(r)
 	}
+	if chainIndex > 0 {
+		runtime.SetContextParent(rootComponent, a.persistentLayout)
+	}
+	slotKey := fmt.Sprintf("slot-root-%T-%p", rootComponent, rootComponent)
+	page = r.RenderChild(slotKey, rootComponent)
+	if err := runtime.LastRenderError(rootComponent); err != nil {
+		return nil, err
+	}
+	if page != nil {
+		// A new instance gets a new key, so its whole subtree replaces the old one
+		page.ComponentKey = slotKey
+	}
+	return page, nil
+}
 
-	// Fallback: render the first non-layout component from chain
-	if len(a.currentChain) > 0 {
-		chainIndex := 0
-		if a.currentChain[0] == a.persistentLayout {
-			chainIndex = 1
+// swap makes page the current page. Without a transition it takes the place of the old
+// page in the slot; with one, both stay in the slot, in lanes of their own, until the
+// transition ends. The caller must hold a.mu.
+func (a *AppShell) swap(page *vdom.VNode, t *Transition) {
+	a.generation++
+	old := a.page
+	a.page = page
+
+	if t == nil || old == nil || page == nil {
+		a.fading = nil
+		if len(a.slot) == 0 {
+			a.slot = []*vdom.VNode{nil}
 		}
-		if chainIndex < len(a.currentChain) {
-			rootComponent := a.currentChain[chainIndex]
-			slotKey := fmt.Sprintf("slot-root-%T-%p", rootComponent, rootComponent)
-			return r.RenderChild(slotKey, rootComponent)
+		for i := range a.slot {
+			a.slot[i] = nil
 		}
+		a.slot[a.live] = page
+		return
 	}
 
-	return vdom.NewVNode("div", nil, nil, "")
+	if len(a.slot) == 1 {
+		a.slot = append(a.slot, nil)
+	}
+	// The old page keeps its lane, so it is patched (its class changes) rather than replaced
+	leaving := a.live
+	a.live = 1 - leaving
+	a.slot[leaving] = withClass(old, t.leaveClass())
+	a.slot[a.live] = withClass(page, t.enterClass())
+	a.fading = t
+
+	generation := a.generation
+	after := a.after
+	if after == nil {
+		after = func(d time.Duration, fn func()) { time.AfterFunc(d, fn) }
+	}
+	after(t.Duration, func() { a.endTransition(generation) })
+}
+
+// endTransition removes the outgoing page of the transition started by swap number
+// generation, unless another swap has happened since.
+func (a *AppShell) endTransition(generation int) {
+	a.mu.Lock()
+	if generation != a.generation {
+		a.mu.Unlock()
+		return
+	}
+	a.fading = nil
+	a.slot[1-a.live] = nil
+	a.mu.Unlock()
+
+	a.StateHasChanged()
+}
+
+// withClass returns a copy of n with class added to its class attribute, or n itself when
+// class is empty. The copy shares the children of n, which are left untouched.
+func withClass(n *vdom.VNode, class string) *vdom.VNode {
+	if n == nil || class == "" {
+		return n
+	}
+	attrs := make(map[string]any, len(n.Attributes)+1)
+	for key, value := range n.Attributes {
+		attrs[key] = value
+	}
+	if existing, ok := attrs["class"].(string); ok && existing != "" {
+		class = existing + " " + class
+	}
+	attrs["class"] = class
+	return &vdom.VNode{
+		Tag:          n.Tag,
+		Attributes:   attrs,
+		Children:     n.Children,
+		Content:      n.Content,
+		OnClick:      n.OnClick,
+		Key:          n.Key,
+		ComponentKey: n.ComponentKey,
+		Ref:          n.Ref,
+	}
 }
@@ -0,0 +1,208 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/rendertest"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// shellLayout is a persistent layout rendering its slot inside <main>.
+type shellLayout struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+}
+
+func (l *shellLayout) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("main", nil, l.BodyContent, "")
+}
+
+func (l *shellLayout) SetBodyContent(content []*vdom.VNode) { l.BodyContent = content }
+
+// shellPage renders its name, or panics when broken.
+type shellPage struct {
+	runtime.ComponentBase
+	name   string
+	broken bool
+}
+
+func (p *shellPage) Render(r runtime.Renderer) *vdom.VNode {
+	if p.broken {
+		panic("page " + p.name + " failed to render")
+	}
+	return vdom.NewVNode("section", map[string]any{"class": "page"}, nil, p.name)
+}
+
+// shellHarness is an engine driving an AppShell rendered by a TestRenderer, showing "/".
+type shellHarness struct {
+	engine   *Engine
+	shell    *AppShell
+	renderer *rendertest.TestRenderer
+	timers   []func() // Transition ends scheduled by the shell, in order
+}
+
+func newShellHarness(t *testing.T, routes ...Route) *shellHarness {
+	t.Helper()
+	layout := &shellLayout{}
+	h := &shellHarness{shell: NewAppShell(layout)}
+	h.shell.after = func(d time.Duration, fn func()) { h.timers = append(h.timers, fn) }
+	h.renderer = rendertest.NewTestRenderer(h.shell)
+	h.engine = NewEngine(h.renderer)
+	h.shell.Route = h.engine.RouteContext()
+
+	layoutMeta := ComponentMetadata{TypeID: 1, Factory: func(map[string]string) runtime.Component { return layout }}
+	for i := range routes {
+		routes[i].Chain = append([]ComponentMetadata{layoutMeta}, routes[i].Chain...)
+	}
+	h.engine.RegisterRoutes(append(routes, Route{Path: "/", Chain: []ComponentMetadata{layoutMeta, shellPageMeta(2, "home", false)}}))
+	h.engine.SetRouteChangeCallback(h.shell.SetPage)
+	h.renderer.RenderRoot()
+	h.navigate(t, "/")
+	return h
+}
+
+// shellPageMeta returns the metadata of a shellPage.
+func shellPageMeta(typeID uint32, name string, broken bool) ComponentMetadata {
+	return ComponentMetadata{TypeID: typeID, Factory: func(map[string]string) runtime.Component {
+		return &shellPage{name: name, broken: broken}
+	}}
+}
+
+func (h *shellHarness) navigate(t *testing.T, path string) {
+	t.Helper()
+	if err := h.engine.Navigate(path); err != nil {
+		t.Fatalf("Navigate(%s): %v", path, err)
+	}
+	// Each navigation renders in a frame of its own, as when a user follows links
+	h.renderer.Flush()
+}
+
+// slot returns the slot content of the last render.
+func (h *shellHarness) slot() []*vdom.VNode {
+	return h.renderer.GetCurrentVDOM().Children
+}
+
+// TestAppShell_NavigationNeverRendersEmptySlot verifies every render during navigations shows
+// exactly one page, and that each new page carries a key of its own so the patcher replaces
+// the old page in one operation.
+func TestAppShell_NavigationNeverRendersEmptySlot(t *testing.T) {
+	// Arrange
+	h := newShellHarness(t,
+		Route{Path: "/a", Chain: []ComponentMetadata{shellPageMeta(3, "a", false)}},
+		Route{Path: "/b", Chain: []ComponentMetadata{shellPageMeta(4, "b", false)}},
+	)
+	home := h.slot()[0]
+
+	// Act
+	h.navigate(t, "/a")
+	pageA := h.slot()[0]
+	h.navigate(t, "/b")
+
+	// Assert
+	renders := h.renderer.Renders()
+	for i, root := range renders[1:] { // The first render precedes the first navigation
+		if len(root.Children) != 1 || root.Children[0] == nil || root.Children[0].Tag != "section" {
+			t.Fatalf("expected render %d to show exactly one page, got:\n%s", i+1, rendertest.FormatVNode(root))
+		}
+	}
+	if got := h.slot()[0].Content; got != "b" {
+		t.Errorf("expected page b to be shown, got %q", got)
+	}
+	if home.ComponentKey == "" || home.ComponentKey == pageA.ComponentKey || pageA.ComponentKey == h.slot()[0].ComponentKey {
+		t.Errorf("expected a distinct component key per page, got %q, %q and %q", home.ComponentKey, pageA.ComponentKey, h.slot()[0].ComponentKey)
+	}
+}
+
+// TestAppShell_PanickingPageKeepsOldPage verifies a page that panics while rendering leaves
+// the old page's tree in the slot, untouched, and that the next navigation still works.
+func TestAppShell_PanickingPageKeepsOldPage(t *testing.T) {
+	// Arrange
+	h := newShellHarness(t,
+		Route{Path: "/broken", Chain: []ComponentMetadata{shellPageMeta(3, "broken", true)}},
+		Route{Path: "/a", Chain: []ComponentMetadata{shellPageMeta(4, "a", false)}},
+	)
+	home := h.slot()[0]
+	rendersBefore := len(h.renderer.Renders())
+
+	// Act
+	h.navigate(t, "/broken")
+
+	// Assert
+	if len(h.renderer.Renders()) == rendersBefore {
+		t.Fatal("expected the navigation to render")
+	}
+	if slot := h.slot(); len(slot) != 1 || slot[0] != home {
+		t.Fatalf("expected the home page's tree to stay in the slot, got:\n%s", rendertest.FormatVNode(h.renderer.GetCurrentVDOM()))
+	}
+	h.navigate(t, "/a")
+	if slot := h.slot(); len(slot) != 1 || slot[0].Content != "a" {
+		t.Errorf("expected the next navigation to show page a, got:\n%s", rendertest.FormatVNode(h.renderer.GetCurrentVDOM()))
+	}
+}
+
+// TestAppShell_TransitionCrossFades verifies a route with a Transition keeps the old page next
+// to the new one, with the transition classes, until the transition ends.
+func TestAppShell_TransitionCrossFades(t *testing.T) {
+	// Arrange
+	h := newShellHarness(t, Route{
+		Path:       "/fade",
+		Chain:      []ComponentMetadata{shellPageMeta(3, "fade", false)},
+		Transition: &Transition{Duration: 200 * time.Millisecond, EnterClass: "fade-in"},
+	})
+
+	// Act
+	h.navigate(t, "/fade")
+	fading := h.slot()
+	if len(h.timers) != 1 {
+		t.Fatalf("expected one transition end to be scheduled, got %d", len(h.timers))
+	}
+	h.timers[0]()
+
+	// Assert
+	if len(fading) != 2 || fading[0].Content != "home" || fading[1].Content != "fade" {
+		t.Fatalf("expected both pages during the transition, got:\n%s", rendertest.FormatVNode(&vdom.VNode{Tag: "slot", Children: fading}))
+	}
+	if fading[0].Attributes["class"] != "page nojs-leave" || fading[1].Attributes["class"] != "page fade-in" {
+		t.Errorf("expected the transition classes, got %v and %v", fading[0].Attributes["class"], fading[1].Attributes["class"])
+	}
+	if slot := h.slot(); len(slot) != 2 || slot[0] != nil || slot[1].Content != "fade" || slot[1].Attributes["class"] != "page" {
+		t.Errorf("expected only the new page once the transition ended, got:\n%s", rendertest.FormatVNode(h.renderer.GetCurrentVDOM()))
+	}
+}
+
+// shellThemedPage renders the theme its layout provides as context.
+type shellThemedPage struct {
+	runtime.ComponentBase
+	Theme *string `nojs:"inject:theme"`
+}
+
+func (p *shellThemedPage) Render(r runtime.Renderer) *vdom.VNode {
+	theme := "none"
+	if p.Theme != nil {
+		theme = *p.Theme
+	}
+	return vdom.NewVNode("section", nil, nil, theme)
+}
+
+// TestAppShell_PageSeesLayoutContext verifies a page receives the context values of the
+// persistent layout whose slot holds it, although the page renders before the layout.
+func TestAppShell_PageSeesLayoutContext(t *testing.T) {
+	// Arrange
+	themed := ComponentMetadata{TypeID: 3, Factory: func(map[string]string) runtime.Component { return &shellThemedPage{} }}
+	h := newShellHarness(t, Route{Path: "/themed", Chain: []ComponentMetadata{themed}})
+	theme := "dark"
+	runtime.ProvideContext(h.shell.persistentLayout, "theme", &theme)
+
+	// Act
+	h.navigate(t, "/themed")
+
+	// Assert
+	if slot := h.slot(); len(slot) != 1 || slot[0].Content != "dark" {
+		t.Errorf("expected the page to show the layout's theme, got:\n%s", rendertest.FormatVNode(h.renderer.GetCurrentVDOM()))
+	}
+}
@@ -0,0 +1,82 @@
+//go:build js && wasm
+// +build js,wasm
+
+package router
+
+import (
+	"syscall/js"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// shellLayout is a persistent layout rendering its slot inside <main>.
+type shellLayout struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+}
+
+func (l *shellLayout) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("main", nil, l.BodyContent, "")
+}
+
+func (l *shellLayout) SetBodyContent(content []*vdom.VNode) { l.BodyContent = content }
+
+// shellPage renders a heading and a paragraph.
+type shellPage struct {
+	runtime.ComponentBase
+	name string
+}
+
+func (p *shellPage) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("section", nil, []*vdom.VNode{
+		vdom.NewVNode("h1", nil, nil, p.name),
+		vdom.Paragraph("Content of "+p.name, nil),
+	}, "")
+}
+
+// TestAppShell_NavigationIsOneSubtreeReplacement is a browser smoke test: swapping the page
+// must replace the old page's element with the new one in a single DOM mutation, never
+// emptying the slot in between. Run it with a browser-backed wasm test runner (e.g.,
+// wasmbrowsertest); it is skipped where no DOM is available, such as under Node.
+func TestAppShell_NavigationIsOneSubtreeReplacement(t *testing.T) {
+	doc := js.Global().Get("document")
+	if !doc.Truthy() {
+		t.Skip("no DOM available")
+	}
+
+	// Arrange
+	mount := doc.Call("createElement", "div")
+	mount.Set("id", "appshell-smoke-test")
+	doc.Get("body").Call("appendChild", mount)
+	defer mount.Call("remove")
+
+	layout := &shellLayout{}
+	shell := NewAppShell(layout)
+	renderer := runtime.NewRenderer(nil, "#appshell-smoke-test")
+	renderer.SetCurrentComponent(shell, "shell")
+	renderer.RenderRoot()
+	shell.SetPage([]runtime.Component{layout, &shellPage{name: "home"}}, "/:0")
+
+	observer := js.Global().Get("MutationObserver").New(js.FuncOf(func(this js.Value, args []js.Value) any { return nil }))
+	observer.Call("observe", mount, map[string]any{"childList": true, "subtree": true, "characterData": true})
+	defer observer.Call("disconnect")
+
+	// Act
+	shell.SetPage([]runtime.Component{layout, &shellPage{name: "about"}}, "/about:1")
+	records := observer.Call("takeRecords")
+
+	// Assert
+	if n := records.Length(); n != 1 {
+		t.Fatalf("expected a single DOM mutation, got %d", n)
+	}
+	record := records.Index(0)
+	if record.Get("addedNodes").Length() != 1 || record.Get("removedNodes").Length() != 1 {
+		t.Errorf("expected one element replaced by another, got %d added and %d removed",
+			record.Get("addedNodes").Length(), record.Get("removedNodes").Length())
+	}
+	if got := mount.Call("querySelector", "main h1").Get("textContent").String(); got != "about" {
+		t.Errorf("expected the new page in the slot, got %q", got)
+	}
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// AsyncComponentFactory starts creating a component and returns at once; the component is
+// sent on the returned channel when it is ready, from a goroutine, or the channel is closed
+// to give up. It is called with the engine locked, so it must not call the engine itself.
+// A navigation before the component arrives abandons it: the engine stops waiting and the
+// component is never shown, so the channel should have a buffer of one for the send not to
+// block.
+//
+// Example:
+//
+//	AsyncFactory: func(params map[string]string) <-chan runtime.Component {
+//	    ready := make(chan runtime.Component, 1)
+//	    go func() {
+//	        user, err := users.Load(context.Background(), params["id"])
+//	        ready <- &UserPage{User: user, Err: err}
+//	    }()
+//	    return ready
+//	},
+type AsyncComponentFactory func(params map[string]string) <-chan runtime.Component
+
+// SetPendingComponent sets the factory of the component shown in place of a component with
+// an AsyncFactory while it is being created, such as a spinner. It receives the route's
+// params. Without one, the slot of the awaited component stays empty until it arrives.
+func (e *Engine) SetPendingComponent(factory ComponentFactory) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pendingFactory = factory
+}
+
+// instantiateChain creates the components of chain from index from onwards into instances,
+// which already holds those before. It stops at the first component with an AsyncFactory,
+// starting the factory and putting the pending component, if any, in its place. It returns
+// the instances to show, how many components of chain they resolve, and the channel of the
+// factory started, nil when the whole chain resolved. The caller must hold e.mu.
+func (e *Engine) instantiateChain(chain []ComponentMetadata, params map[string]string, instances []runtime.Component, from int) ([]runtime.Component, int, <-chan runtime.Component) {
+	for i := from; i < len(chain); i++ {
+		if chain[i].AsyncFactory != nil {
+			delivered := chain[i].AsyncFactory(maps.Clone(params))
+			instances = instances[:i]
+			if e.pendingFactory != nil {
+				instances = append(instances, e.prepareInstance(e.pendingFactory(params)))
+			}
+			return instances, i, delivered
+		}
+		instances[i] = e.prepareInstance(chain[i].Factory(params))
+	}
+	return instances, len(chain), nil
+}
+
+// prepareInstance gives a new route component the renderer, so it can call
+// StateHasChanged() and Navigate(), its services and its history state. Without an AppShell,
+// whose renderer runs the lifecycle of the chain it renders, the component is also mounted
+// here: OnMount, then OnParametersSet. The caller must hold e.mu.
+func (e *Engine) prepareInstance(instance runtime.Component) runtime.Component {
+	instance.SetRenderer(e.renderer)
+	runtime.InjectServices(e.renderer, instance)
+	e.restoreHistoryState(instance)
+	if e.onRouteChange == nil {
+		runtime.CallOnMount(instance)
+		runtime.CallOnParametersSet(instance)
+	}
+	return instance
+}
+
+// awaitComponent waits in a goroutine for the component at index of the chain of route,
+// which instantiateChain started creating, and shows it unless the route shown changes
+// first. The caller must hold e.mu.
+func (e *Engine) awaitComponent(path string, route *Route, params map[string]string, index int, delivered <-chan runtime.Component) {
+	abandoned := make(chan struct{})
+	e.awaiting = abandoned
+	go func() {
+		var instance runtime.Component
+		var ok bool
+		select {
+		case instance, ok = <-delivered:
+		case <-abandoned:
+			e.log.Debug("[Engine] Stopped waiting for a component of", path)
+			return
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		switch {
+		case e.awaiting != abandoned:
+			// A navigation committed while the component was delivered
+			if ok && instance != nil {
+				runtime.Destroy(instance)
+			}
+		case !ok || instance == nil:
+			e.awaiting = nil
+			e.log.Error("[Engine] Async factory gave up on a component of", path)
+		default:
+			e.awaiting = nil
+			e.showAwaited(path, route, params, index, instance)
+		}
+	}()
+}
+
+// showAwaited replaces the pending component at index with instance, creates the rest of
+// the chain and renders it. The caller must hold e.mu.
+func (e *Engine) showAwaited(path string, route *Route, params map[string]string, index int, instance runtime.Component) {
+	e.log.Debug("[Engine] Showing the awaited component of", path)
+	instances := make([]runtime.Component, len(route.Chain))
+	copy(instances, e.liveInstances[:index])
+	if len(e.liveInstances) > index {
+		e.destroyInstance(e.liveInstances[index])
+	}
+	instances[index] = e.prepareInstance(instance)
+	instances, resolved, delivered := e.instantiateChain(route.Chain, params, instances, index+1)
+
+	e.activeChain = route.Chain[:resolved]
+	e.liveInstances = instances
+	assertUniqueInstances(instances)
+	e.showChain(instances, fmt.Sprintf("%s:%d", path, index), index, index-1)
+	e.applyHead()
+	if delivered != nil {
+		e.awaitComponent(path, route, params, resolved, delivered)
+	}
+}
+
+// abandonAwaited stops waiting for the component of the route shown, if any. The caller
+// must hold e.mu.
+func (e *Engine) abandonAwaited() {
+	if e.awaiting != nil {
+		close(e.awaiting)
+		e.awaiting = nil
+	}
+}
@@ -0,0 +1,182 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// asyncPage is a route whose page is created by an async factory; each navigation to it
+// waits on a new channel, which the test sends the page on.
+type asyncPage struct {
+	deliveries chan chan runtime.Component // One per factory call, in call order
+}
+
+func newAsyncPage() *asyncPage {
+	return &asyncPage{deliveries: make(chan chan runtime.Component, 4)}
+}
+
+func (a *asyncPage) meta() ComponentMetadata {
+	return ComponentMetadata{TypeID: 10, AsyncFactory: func(params map[string]string) <-chan runtime.Component {
+		ready := make(chan runtime.Component, 1)
+		a.deliveries <- ready
+		return ready
+	}}
+}
+
+// next returns the channel of the oldest factory call not yet taken.
+func (a *asyncPage) next(t *testing.T) chan runtime.Component {
+	t.Helper()
+	select {
+	case ready := <-a.deliveries:
+		return ready
+	default:
+		t.Fatal("the async factory was not called")
+		return nil
+	}
+}
+
+// newAsyncEngine creates an engine with /a (layout + page) and /slow (layout + async page).
+func newAsyncEngine(slow *asyncPage) (*Engine, *routeTestRenderer) {
+	layout := pageMeta(1, "layout")
+	engine := newTestEngine(
+		Route{Path: "/a", Chain: []ComponentMetadata{layout, pageMeta(2, "a")}},
+		Route{Path: "/slow", Chain: []ComponentMetadata{layout, slow.meta()}},
+	)
+	return engine, engine.renderer.(*routeTestRenderer)
+}
+
+// liveNames returns the names of the engine's live instances.
+func liveNames(e *Engine) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var names []string
+	for _, instance := range e.liveInstances {
+		names = append(names, instance.(*guardPage).name)
+	}
+	return names
+}
+
+// TestAsyncFactory_ShowsPendingUntilDelivered verifies the pending component stands in for
+// the awaited page, which then replaces it under the preserved layout.
+func TestAsyncFactory_ShowsPendingUntilDelivered(t *testing.T) {
+	// Arrange
+	slow := newAsyncPage()
+	engine, renderer := newAsyncEngine(slow)
+	engine.SetPendingComponent(func(map[string]string) runtime.Component { return &guardPage{name: "spinner"} })
+	if err := engine.Navigate("/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	if err := engine.Navigate("/slow"); err != nil {
+		t.Fatalf("Navigate(/slow): %v", err)
+	}
+	pending := liveNames(engine)
+	page := &guardPage{name: "slow"}
+	slow.next(t) <- page
+	waitFor(t, "the awaited page", func() bool { return slices.Equal(liveNames(engine), []string{"layout", "slow"}) })
+
+	// Assert
+	if !slices.Equal(pending, []string{"layout", "spinner"}) {
+		t.Errorf("expected the spinner while waiting, got %v", pending)
+	}
+	if page.GetRenderer() != renderer {
+		t.Error("expected the awaited page to get the renderer")
+	}
+	engine.mu.Lock()
+	defer engine.mu.Unlock()
+	if last := renderer.slotParents[len(renderer.slotParents)-1]; last != engine.liveInstances[0] {
+		t.Errorf("expected the layout's slot to be re-rendered, got %v", last)
+	}
+}
+
+// TestAsyncFactory_LaterNavigationAbandonsPage verifies a page delivered after the user
+// navigated on is never shown, and that the pending component is not preserved.
+func TestAsyncFactory_LaterNavigationAbandonsPage(t *testing.T) {
+	// Arrange
+	slow := newAsyncPage()
+	engine, _ := newAsyncEngine(slow)
+	engine.SetPendingComponent(func(map[string]string) runtime.Component { return &guardPage{name: "spinner"} })
+	if err := engine.Navigate("/slow"); err != nil {
+		t.Fatal(err)
+	}
+	stale := slow.next(t)
+
+	// Act
+	if err := engine.Navigate("/a"); err != nil {
+		t.Fatalf("Navigate(/a): %v", err)
+	}
+	afterLeaving := liveNames(engine)
+	stale <- &guardPage{name: "stale"}
+	if err := engine.Navigate("/slow"); err != nil {
+		t.Fatalf("Navigate(/slow): %v", err)
+	}
+	slow.next(t) <- &guardPage{name: "fresh"}
+	waitFor(t, "the fresh page", func() bool { return slices.Equal(liveNames(engine), []string{"layout", "fresh"}) })
+
+	// Assert
+	if !slices.Equal(afterLeaving, []string{"layout", "a"}) {
+		t.Errorf("expected /a to replace the spinner, got %v", afterLeaving)
+	}
+}
+
+// TestAsyncFactory_WithoutPendingComponent verifies the chain stops at the awaited page, and
+// that an AppShell receives the chain again once it arrives.
+func TestAsyncFactory_WithoutPendingComponent(t *testing.T) {
+	// Arrange
+	slow := newAsyncPage()
+	engine, _ := newAsyncEngine(slow)
+	chains := make(chan []runtime.Component, 2)
+	engine.SetRouteChangeCallback(func(chain []runtime.Component, key string) { chains <- chain })
+
+	// Act
+	if err := engine.Navigate("/slow"); err != nil {
+		t.Fatal(err)
+	}
+	slow.next(t) <- &guardPage{name: "slow"}
+	waiting, shown := <-chains, <-chains
+
+	// Assert
+	if len(waiting) != 1 {
+		t.Errorf("expected only the layout while waiting, got %d components", len(waiting))
+	}
+	if len(shown) != 2 || shown[1].(*guardPage).name != "slow" {
+		t.Errorf("expected the layout and the awaited page, got %v", shown)
+	}
+	if _, ok := engine.GetComponentForPath("/slow"); ok {
+		t.Error("expected GetComponentForPath to skip a page created asynchronously")
+	}
+}
+
+// TestAsyncFactory_GivingUpKeepsPending verifies a factory closing its channel leaves the
+// pending component on screen.
+func TestAsyncFactory_GivingUpKeepsPending(t *testing.T) {
+	// Arrange
+	slow := newAsyncPage()
+	engine, _ := newAsyncEngine(slow)
+	engine.SetPendingComponent(func(map[string]string) runtime.Component { return &guardPage{name: "spinner"} })
+	if err := engine.Navigate("/slow"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	close(slow.next(t))
+	waitFor(t, "the engine to stop waiting", func() bool {
+		engine.mu.Lock()
+		defer engine.mu.Unlock()
+		return engine.awaiting == nil
+	})
+
+	// Assert
+	if got := liveNames(engine); !slices.Equal(got, []string{"layout", "spinner"}) {
+		t.Errorf("expected the spinner to stay, got %v", got)
+	}
+	if err := engine.Navigate("/a"); err != nil {
+		t.Errorf("expected to navigate on, got %v", err)
+	}
+}
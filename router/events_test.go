@@ -0,0 +1,34 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+var testNotice = runtime.DefineEvent[string]("router-test-notice")
+
+// TestEvents_ListenerRemovedWhenNavigatingAway verifies that a page listening to an event
+// stops receiving it once navigation destroys the page.
+func TestEvents_ListenerRemovedWhenNavigatingAway(t *testing.T) {
+	// Arrange
+	h := startHistoryStateEngine(t, &memoryHistory{path: "/wizard"})
+	page := h.page
+	var received []string
+	runtime.Listen(page, testNotice, func(msg string) { received = append(received, msg) })
+	runtime.Emit(testNotice, "before")
+
+	// Act
+	if err := h.engine.Navigate("/done"); err != nil {
+		t.Fatalf("Navigate failed: %v", err)
+	}
+	runtime.Emit(testNotice, "after")
+
+	// Assert
+	if len(received) != 1 || received[0] != "before" {
+		t.Errorf("expected only the event emitted before navigating, got %v", received)
+	}
+}
@@ -0,0 +1,96 @@
+package router
+
+import (
+	"sync"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// navigationGuard makes Navigate idempotent. A call for the path already shown, or for the
+// path a navigation in flight is heading to, is a no-op; of several calls queued behind a
+// navigation in flight only the latest runs, so the final state always matches the last
+// call and the pivot is never computed against a navigation that is about to be replaced.
+type navigationGuard struct {
+	mu       sync.Mutex
+	current  string // Target of the last completed navigation
+	latest   int    // Ticket of the most recent call
+	target   string // Target of the most recent call
+	inFlight int    // Calls begun and not yet ended
+}
+
+// navigationTarget identifies a navigation by its route path and raw query.
+func navigationTarget(routePath, rawQuery string) string {
+	if rawQuery == "" {
+		return routePath
+	}
+	return routePath + "?" + rawQuery
+}
+
+// begin registers a call navigating to target and returns its ticket. Unless force is set
+// (popstate and followed paths always run), it returns ErrAlreadyNavigating when target
+// is where the most recent call in flight is heading, or ErrAlreadyCurrent when no call is
+// in flight and target is already shown. Every successful begin must be paired with end.
+func (g *navigationGuard) begin(target string, force bool) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !force {
+		if g.inFlight > 0 && target == g.target {
+			return 0, ErrAlreadyNavigating
+		}
+		if g.inFlight == 0 && target == g.current {
+			return 0, ErrAlreadyCurrent
+		}
+	}
+	g.latest++
+	g.target = target
+	g.inFlight++
+	return g.latest, nil
+}
+
+// superseded reports whether a later call began after the one holding ticket.
+func (g *navigationGuard) superseded(ticket int) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return ticket < g.latest
+}
+
+// commit records target as the path now shown.
+func (g *navigationGuard) commit(target string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.current = target
+}
+
+// isCurrent reports whether target is the path shown.
+func (g *navigationGuard) isCurrent(target string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return target == g.current
+}
+
+// forget clears the path recorded as shown, so the next call for it runs. The engine calls
+// it when the route on screen is replaced or removed.
+func (g *navigationGuard) forget() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.current = ""
+}
+
+// end marks a call begun with begin as finished.
+func (g *navigationGuard) end() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.inFlight--
+}
+
+// duplicateInstance reports the first pair of indexes holding the same component instance.
+func duplicateInstance(instances []runtime.Component) (first, second int, found bool) {
+	seen := make(map[runtime.Component]int, len(instances))
+	for i, instance := range instances {
+		if j, exists := seen[instance]; exists {
+			return j, i, true
+		}
+		seen[instance] = i
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,18 @@
+//go:build dev
+// +build dev
+
+package router
+
+import (
+	"fmt"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// assertUniqueInstances panics if a navigation left the same instance twice in the live
+// chain. In dev mode, broken router invariants fail fast.
+func assertUniqueInstances(instances []runtime.Component) {
+	if i, j, found := duplicateInstance(instances); found {
+		panic(fmt.Sprintf("router: live chain holds the same %T instance at %d and %d", instances[i], i, j))
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !dev
+// +build !dev
+
+package router
+
+import "github.com/ForgeLogic/nojs/runtime"
+
+// assertUniqueInstances is a no-op in production builds.
+func assertUniqueInstances(instances []runtime.Component) {}
@@ -0,0 +1,220 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// guardPage is a routed component that renders its name.
+type guardPage struct {
+	runtime.ComponentBase
+	name string
+}
+
+func (p *guardPage) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("p", nil, nil, p.name)
+}
+
+// asyncNavHarness is an engine whose route changes can be held open, simulating an AppShell
+// render that is still in progress while further clicks arrive.
+type asyncNavHarness struct {
+	engine *Engine
+	gate   chan struct{} // Route changes block until it is closed (nil: never block)
+
+	mu     sync.Mutex
+	chains [][]runtime.Component // Every chain passed to the route change callback
+	held   chan struct{}         // Closed once a route change is blocked on the gate
+}
+
+// newAsyncNavHarness creates an engine with routes / (layout + home), /a and /b (layout + page).
+func newAsyncNavHarness() *asyncNavHarness {
+	h := &asyncNavHarness{held: make(chan struct{})}
+	layout := pageMeta(1, "layout")
+	h.engine = newTestEngine(
+		Route{Path: "/", Chain: []ComponentMetadata{layout, pageMeta(2, "home")}},
+		Route{Path: "/a", Chain: []ComponentMetadata{layout, pageMeta(3, "a")}},
+		Route{Path: "/b", Chain: []ComponentMetadata{layout, pageMeta(4, "b")}},
+	)
+	h.engine.SetRouteChangeCallback(func(chain []runtime.Component, key string) {
+		h.mu.Lock()
+		h.chains = append(h.chains, chain)
+		gate, held := h.gate, h.held
+		h.mu.Unlock()
+		if gate != nil {
+			select {
+			case <-held:
+			default:
+				close(held)
+			}
+			<-gate
+		}
+	})
+	return h
+}
+
+// hold makes subsequent route changes block until release.
+func (h *asyncNavHarness) hold() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.gate = make(chan struct{})
+}
+
+func (h *asyncNavHarness) release() {
+	h.mu.Lock()
+	gate := h.gate
+	h.gate = nil
+	h.mu.Unlock()
+	close(gate)
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// pageName returns the name of the leaf page in the engine's live chain.
+func pageName(e *Engine) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.liveInstances[len(e.liveInstances)-1].(*guardPage).name
+}
+
+// TestNavigate_SamePathTwice_IsAlreadyCurrent verifies a second navigation to the page
+// already shown changes nothing, while a different query still navigates.
+func TestNavigate_SamePathTwice_IsAlreadyCurrent(t *testing.T) {
+	// Arrange
+	h := newAsyncNavHarness()
+	if err := h.engine.Navigate("/a"); err != nil {
+		t.Fatalf("Navigate(/a): %v", err)
+	}
+
+	// Act
+	again := h.engine.Navigate("/a")
+	withQuery := h.engine.Navigate("/a?tab=2")
+
+	// Assert
+	if !errors.Is(again, ErrAlreadyCurrent) {
+		t.Errorf("expected ErrAlreadyCurrent, got %v", again)
+	}
+	if withQuery != nil {
+		t.Errorf("expected a navigation with a new query to run, got %v", withQuery)
+	}
+	if len(h.chains) != 2 {
+		t.Errorf("expected 2 route changes, got %d", len(h.chains))
+	}
+}
+
+// TestNavigate_DoubleClickDuringNavigation_IsAlreadyNavigating verifies a second call for the
+// path being navigated to returns at once, without waiting for or repeating the navigation.
+func TestNavigate_DoubleClickDuringNavigation_IsAlreadyNavigating(t *testing.T) {
+	// Arrange
+	h := newAsyncNavHarness()
+	h.hold()
+	first := make(chan error, 1)
+	go func() { first <- h.engine.Navigate("/a") }()
+	<-h.held
+
+	// Act
+	second := h.engine.Navigate("/a")
+	h.release()
+
+	// Assert
+	if !errors.Is(second, ErrAlreadyNavigating) {
+		t.Errorf("expected ErrAlreadyNavigating, got %v", second)
+	}
+	if err := <-first; err != nil {
+		t.Errorf("expected the first navigation to succeed, got %v", err)
+	}
+	if len(h.chains) != 1 {
+		t.Errorf("expected 1 route change, got %d", len(h.chains))
+	}
+}
+
+// TestNavigate_ConcurrentCallsToAlternatingPaths_LastCallWins issues 20 Navigate calls to
+// alternating paths while the first is still rendering, and verifies the final state is that
+// of the last call, with the shared layout instance reused and never duplicated.
+func TestNavigate_ConcurrentCallsToAlternatingPaths_LastCallWins(t *testing.T) {
+	// Arrange
+	h := newAsyncNavHarness()
+	if err := h.engine.Navigate("/"); err != nil {
+		t.Fatalf("Navigate(/): %v", err)
+	}
+	h.mu.Lock()
+	layout := h.chains[0][0]
+	h.mu.Unlock()
+	h.hold()
+
+	// Act: each call starts once the previous one is registered, so the order is known
+	const calls = 20
+	errs := make([]error, calls)
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		path := "/a"
+		if i%2 == 1 {
+			path = "/b"
+		}
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			errs[i] = h.engine.Navigate(path)
+		}(i, path)
+		waitFor(t, fmt.Sprintf("call %d to register", i), func() bool {
+			h.engine.guard.mu.Lock()
+			defer h.engine.guard.mu.Unlock()
+			return h.engine.guard.latest == i+2 // The initial navigation took ticket 1
+		})
+		if i == 0 {
+			<-h.held
+		}
+	}
+	h.release()
+	wg.Wait()
+
+	// Assert
+	if got := h.engine.CurrentPath(); got != "/b" {
+		t.Errorf("expected the last call's path /b, got %s", got)
+	}
+	if got := pageName(h.engine); got != "b" {
+		t.Errorf("expected page b to be live, got %s", got)
+	}
+	if errs[0] != nil || errs[calls-1] != nil {
+		t.Errorf("expected the first and last calls to run, got %v and %v", errs[0], errs[calls-1])
+	}
+	for i := 1; i < calls-1; i++ {
+		if !errors.Is(errs[i], ErrAlreadyNavigating) {
+			t.Errorf("call %d: expected ErrAlreadyNavigating, got %v", i, errs[i])
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.chains) != 3 {
+		t.Errorf("expected 3 route changes (initial, first, last), got %d", len(h.chains))
+	}
+	for n, chain := range h.chains {
+		if i, j, found := duplicateInstance(chain); found {
+			t.Errorf("route change %d: instance duplicated at %d and %d", n, i, j)
+		}
+	}
+	if i, j, found := duplicateInstance(h.engine.liveInstances); found {
+		t.Errorf("live chain: instance duplicated at %d and %d", i, j)
+	}
+	if h.engine.liveInstances[0] != layout {
+		t.Error("expected the layout instance to be preserved across all navigations")
+	}
+}
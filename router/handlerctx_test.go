@@ -0,0 +1,83 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// TestNavigate_CancelsHandlerCtxOfDestroyedPage verifies an async operation started by a
+// handler of a page observes cancellation once navigation discards the page, and that its
+// SafeUpdate no longer touches the page.
+func TestNavigate_CancelsHandlerCtxOfDestroyedPage(t *testing.T) {
+	// Arrange
+	e := newTestEngine(
+		Route{Path: "/a", Chain: []ComponentMetadata{pageMeta(1, "a")}},
+		Route{Path: "/b", Chain: []ComponentMetadata{pageMeta(2, "b")}},
+	)
+	renderer := e.renderer.(*routeTestRenderer)
+	if err := e.Navigate("/a"); err != nil {
+		t.Fatalf("Navigate(/a): %v", err)
+	}
+	pageA := e.liveInstances[0].(*guardPage)
+
+	// A handler of page A starts an operation that finishes once released, then reports
+	// whether its context was cancelled and tries to update the page
+	ctx := runtime.NewCtx(pageA)
+	release := make(chan struct{})
+	result := make(chan error)
+	updated := false
+	go func() {
+		<-release
+		ctx.SafeUpdate(func() { updated = true })
+		result <- ctx.Err()
+	}()
+
+	// Act
+	if err := e.Navigate("/b"); err != nil {
+		t.Fatalf("Navigate(/b): %v", err)
+	}
+	reRenders := renderer.reRenders
+	close(release)
+	err := <-result
+
+	// Assert
+	if err != context.Canceled {
+		t.Errorf("expected the handler context to be cancelled, got %v", err)
+	}
+	if updated {
+		t.Error("expected SafeUpdate to skip the update of the destroyed page")
+	}
+	if renderer.reRenders != reRenders {
+		t.Errorf("expected no re-render from SafeUpdate, got %d", renderer.reRenders-reRenders)
+	}
+	if ctx.Alive() {
+		t.Error("expected Alive to report the destroyed page")
+	}
+}
+
+// TestNavigate_KeepsHandlerCtxOfStableLayout verifies a layout that survives navigation keeps
+// its handler context.
+func TestNavigate_KeepsHandlerCtxOfStableLayout(t *testing.T) {
+	// Arrange
+	h := newAsyncNavHarness()
+	if err := h.engine.Navigate("/a"); err != nil {
+		t.Fatalf("Navigate(/a): %v", err)
+	}
+	ctx := runtime.NewCtx(h.engine.liveInstances[0])
+
+	// Act
+	err := h.engine.Navigate("/b")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Navigate(/b): %v", err)
+	}
+	if ctx.Err() != nil || !ctx.Alive() {
+		t.Errorf("expected the layout context to stay alive, got %v", ctx.Err())
+	}
+}
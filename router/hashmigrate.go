@@ -0,0 +1,53 @@
+package router
+
+import "strings"
+
+// legacyHashRoute parses a hash-mode route (#/admin/settings?tab=2) into its route path and
+// raw query. ok is false for hashes that are not routes, such as fragment links (#section).
+func legacyHashRoute(hash string) (routePath, rawQuery string, ok bool) {
+	if !strings.HasPrefix(hash, "#/") {
+		return "", "", false
+	}
+	routePath, rawQuery, _ = strings.Cut(strings.TrimPrefix(hash, "#"), "?")
+	return normalizeRoutePath(routePath), rawQuery, true
+}
+
+// migrateHashURL rewrites a legacy hash-mode URL to its path form with replaceState, when
+// migration is enabled and the app was loaded at its root (routePath "/") with a route in the
+// hash. search is the current location.search; the query embedded in the hash is appended
+// to it. It returns the route path and search to navigate to, or ok false when the URL is
+// left alone. A hash route that matches no registered route is still rewritten, so the
+// initial navigation fails with ErrRouteNotFound exactly as it would for that path.
+func (e *Engine) migrateHashURL(routePath, search string) (string, string, bool) {
+	e.mu.Lock()
+	migrate := e.migrateHash
+	e.mu.Unlock()
+	if !migrate || (routePath != "/" && routePath != "") {
+		return "", "", false
+	}
+	hashPath, hashQuery, ok := legacyHashRoute(e.history.hash())
+	if !ok {
+		return "", "", false
+	}
+
+	if hashQuery != "" {
+		if search == "" {
+			search = "?" + hashQuery
+		} else {
+			search += "&" + hashQuery
+		}
+	}
+
+	e.mu.Lock()
+	matched := len(e.matchRoutes(hashPath)) > 0
+	browserPath := e.toBrowserPath(hashPath) + search
+	e.mu.Unlock()
+
+	if matched {
+		e.log.Debug("[Engine.Start] Migrating hash URL to:", browserPath)
+	} else {
+		e.log.Warn("[Engine.Start] Migrating hash URL with no matching route to:", browserPath)
+	}
+	e.history.replaceState(browserPath)
+	return hashPath, search, true
+}
@@ -0,0 +1,174 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// hashRoutes are the routes /, /docs, /admin/settings and /users/{id}.
+var hashRoutes = []Route{
+	{Path: "/", Chain: []ComponentMetadata{pageMeta(1, "home")}},
+	{Path: "/docs", Chain: []ComponentMetadata{pageMeta(2, "docs")}},
+	{Path: "/admin/settings", Chain: []ComponentMetadata{pageMeta(3, "settings")}},
+	{Path: "/users/{id}", Chain: []ComponentMetadata{pageMeta(4, "user")}},
+}
+
+// TestStart_MigratesHashRouteWithQuery verifies a /#/path?query bookmark is replaced by its
+// path form, keeping the query, before the initial render.
+func TestStart_MigratesHashRouteWithQuery(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/", fragment: "#/admin/settings?tab=2"}
+	e := newTestEngine(hashRoutes...)
+	e.history = history
+	e.SetMigrateHashURLs(true)
+
+	// Act
+	err := e.Start(func([]runtime.Component, string) {})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if e.currentPath != "/admin/settings" {
+		t.Errorf("expected initial route /admin/settings, got %q", e.currentPath)
+	}
+	if len(history.replaced) != 1 || history.replaced[0] != "/admin/settings?tab=2" {
+		t.Errorf("expected the URL to be replaced with /admin/settings?tab=2, got %v", history.replaced)
+	}
+	if got := e.RouteContext().Query["tab"]; got != "2" {
+		t.Errorf("expected query tab=2, got %q", got)
+	}
+}
+
+// TestStart_MergesHashQueryWithSearch verifies a query before the hash (/?lang=en#/docs?page=3)
+// is kept alongside the one embedded in the hash.
+func TestStart_MergesHashQueryWithSearch(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/", query: "?lang=en", fragment: "#/docs?page=3"}
+	e := newTestEngine(hashRoutes...)
+	e.history = history
+	e.SetMigrateHashURLs(true)
+
+	// Act
+	err := e.Start(func([]runtime.Component, string) {})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if history.path != "/docs" || history.query != "?lang=en&page=3" {
+		t.Errorf("expected URL /docs?lang=en&page=3, got %q%q", history.path, history.query)
+	}
+	query := e.RouteContext().Query
+	if query["lang"] != "en" || query["page"] != "3" {
+		t.Errorf("expected query lang=en and page=3, got %v", query)
+	}
+}
+
+// TestStart_MigratesHashToParameterizedRoute verifies a hash route matching a pattern is
+// migrated and its parameters are extracted.
+func TestStart_MigratesHashToParameterizedRoute(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/", fragment: "#/users/42"}
+	e := newTestEngine(hashRoutes...)
+	e.history = history
+	e.SetMigrateHashURLs(true)
+
+	// Act
+	err := e.Start(func([]runtime.Component, string) {})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if history.path != "/users/42" || history.query != "" {
+		t.Errorf("expected URL /users/42, got %q%q", history.path, history.query)
+	}
+	if got := e.RouteContext().Param("id"); got != "42" {
+		t.Errorf("expected param id=42, got %q", got)
+	}
+}
+
+// TestStart_UnknownHashRouteIsNotFound verifies a hash route matching no registered route
+// falls through to NotFound handling.
+func TestStart_UnknownHashRouteIsNotFound(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/", fragment: "#/missing"}
+	e := newTestEngine(hashRoutes...)
+	e.history = history
+	e.SetMigrateHashURLs(true)
+
+	// Act
+	err := e.Start(func([]runtime.Component, string) {})
+
+	// Assert
+	if !errors.Is(err, ErrRouteNotFound) {
+		t.Errorf("expected ErrRouteNotFound, got %v", err)
+	}
+	if history.path != "/missing" {
+		t.Errorf("expected URL /missing, got %q", history.path)
+	}
+}
+
+// TestStart_LeavesFragmentLinksAlone verifies fragment anchors, and hashes on paths other than
+// the root, are not treated as legacy routes.
+func TestStart_LeavesFragmentLinksAlone(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		hash     string
+		wantPath string
+	}{
+		{name: "anchor on a routed path", path: "/docs", hash: "#section", wantPath: "/docs"},
+		{name: "anchor on the root", path: "/", hash: "#section", wantPath: "/"},
+		{name: "hash route on a routed path", path: "/docs", hash: "#/admin/settings", wantPath: "/docs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			history := &memoryHistory{path: tt.path, fragment: tt.hash}
+			e := newTestEngine(hashRoutes...)
+			e.history = history
+			e.SetMigrateHashURLs(true)
+
+			// Act
+			err := e.Start(func([]runtime.Component, string) {})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Start failed: %v", err)
+			}
+			if e.currentPath != tt.wantPath {
+				t.Errorf("expected initial route %q, got %q", tt.wantPath, e.currentPath)
+			}
+			if len(history.replaced) != 0 {
+				t.Errorf("expected the URL not to be replaced, got %v", history.replaced)
+			}
+		})
+	}
+}
+
+// TestStart_HashMigrationDisabled verifies hash routes are ignored unless migration is enabled.
+func TestStart_HashMigrationDisabled(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/", fragment: "#/admin/settings"}
+	e := newTestEngine(hashRoutes...)
+	e.history = history
+	e.SetMigrateHashURLs(false)
+
+	// Act
+	err := e.Start(func([]runtime.Component, string) {})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if e.currentPath != "/" || len(history.replaced) != 0 {
+		t.Errorf("expected to stay on / without replacing the URL, got route %q and replaced %v", e.currentPath, history.replaced)
+	}
+}
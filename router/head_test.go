@@ -0,0 +1,69 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/head"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// headPage is a route component providing a title and meta tags.
+type headPage struct {
+	runtime.ComponentBase
+	title string
+	meta  []head.Meta
+}
+
+func (p *headPage) Render(r runtime.Renderer) *vdom.VNode { return vdom.Div(nil) }
+func (p *headPage) Title() string                         { return p.title }
+func (p *headPage) Meta() []head.Meta                     { return p.meta }
+
+// TestEngine_AppliesHeadAfterNavigation verifies each navigation sets the title and tags of
+// the chain shown, the page's overriding the layout's, and a page without a title gets the
+// one of index.html back.
+func TestEngine_AppliesHeadAfterNavigation(t *testing.T) {
+	// Arrange
+	head.ResetDocument("My App")
+	t.Cleanup(func() { head.ResetDocument("") })
+	layout := ComponentMetadata{TypeID: 1, Factory: func(map[string]string) runtime.Component {
+		return &headPage{meta: []head.Meta{{Name: "description", Content: "A demo app"}}}
+	}}
+	engine := newTestEngine(
+		Route{Path: "/", Chain: []ComponentMetadata{layout, pageMeta(2, "home")}},
+		Route{Path: "/about", Chain: []ComponentMetadata{layout, {TypeID: 3, Factory: func(map[string]string) runtime.Component {
+			return &headPage{title: "About – My App", meta: []head.Meta{
+				{Name: "description", Content: "Who we are"},
+				{Property: "og:title", Content: "About"},
+			}}
+		}}}},
+	)
+	engine.SetRouteChangeCallback(func([]runtime.Component, string) {})
+
+	// Act
+	if err := engine.Navigate("/about"); err != nil {
+		t.Fatalf("Navigate(/about): %v", err)
+	}
+	aboutTitle := head.DocumentTitle()
+	aboutDescription, _ := head.DocumentMeta("description")
+	if err := engine.Navigate("/"); err != nil {
+		t.Fatalf("Navigate(/): %v", err)
+	}
+
+	// Assert
+	if aboutTitle != "About – My App" || aboutDescription != "Who we are" {
+		t.Errorf("expected the about page's head, got %q %q", aboutTitle, aboutDescription)
+	}
+	if got := head.DocumentTitle(); got != "My App" {
+		t.Errorf("expected the title of index.html back, got %q", got)
+	}
+	if got, _ := head.DocumentMeta("description"); got != "A demo app" {
+		t.Errorf("expected the layout's description, got %q", got)
+	}
+	if _, ok := head.DocumentMeta("og:title"); ok {
+		t.Error("expected the about page's og:title to be removed")
+	}
+}
@@ -0,0 +1,148 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import "strings"
+
+// memoryHistory is an in-memory browser history for native builds and tests.
+type memoryHistory struct {
+	path     string
+	query    string   // Including the leading "?", as location.search
+	fragment string   // Including the leading "#", as location.hash
+	entries  []string // Every pushed browser path, in order
+	replaced []string // Every browser path passed to replaceState, in order
+
+	stack    []memoryEntry // The session history; created on first use from the location
+	current  int           // Index of the current entry in stack
+	popState func()        // Listener registered by onPopState
+	pageHide func()        // Listener registered by onPageHide
+
+	offset       float64            // window.scrollY
+	anchors      map[string]float64 // Offsets of the elements scrollIntoView finds, by id
+	manualScroll bool               // Set by manualScrollRestoration
+}
+
+// memoryEntry is one entry of a memoryHistory.
+type memoryEntry struct {
+	browserPath string
+	state       string // history.state of the entry, "" for none
+}
+
+func newBrowserHistory() browserHistory { return &memoryHistory{path: "/"} }
+
+func (h *memoryHistory) pathname() string { return h.path }
+
+func (h *memoryHistory) search() string { return h.query }
+
+func (h *memoryHistory) hash() string { return h.fragment }
+
+// entry returns the current entry, creating the session history from the location when it
+// is first used.
+func (h *memoryHistory) entry() *memoryEntry {
+	if len(h.stack) == 0 {
+		h.stack = []memoryEntry{{browserPath: h.path + h.query + h.fragment}}
+		h.current = 0
+	}
+	return &h.stack[h.current]
+}
+
+// pushState adds an entry without state after the current one, dropping the entries
+// forward of it.
+func (h *memoryHistory) pushState(browserPath string) {
+	h.entry()
+	h.entries = append(h.entries, browserPath)
+	h.stack = append(h.stack[:h.current+1], memoryEntry{browserPath: browserPath})
+	h.current++
+	h.setURL(browserPath)
+}
+
+// replaceState changes the current URL without adding an entry; the entry keeps its state.
+func (h *memoryHistory) replaceState(browserPath string) {
+	h.replaced = append(h.replaced, browserPath)
+	if len(h.entries) > 0 {
+		h.entries[len(h.entries)-1] = browserPath
+	}
+	h.entry().browserPath = browserPath
+	h.setURL(browserPath)
+}
+
+func (h *memoryHistory) state() string { return h.entry().state }
+
+func (h *memoryHistory) setState(state string) { h.entry().state = state }
+
+// setURL sets the location from a browser path with an optional query and fragment.
+func (h *memoryHistory) setURL(browserPath string) {
+	browserPath, fragment, hasFragment := strings.Cut(browserPath, "#")
+	path, query, hasQuery := strings.Cut(browserPath, "?")
+	h.path, h.query, h.fragment = path, "", ""
+	if hasQuery {
+		h.query = "?" + query
+	}
+	if hasFragment {
+		h.fragment = "#" + fragment
+	}
+}
+
+func (h *memoryHistory) scrollY() float64 { return h.offset }
+
+func (h *memoryHistory) scrollTo(y float64) { h.offset = y }
+
+func (h *memoryHistory) scrollIntoView(id string) bool {
+	offset, ok := h.anchors[id]
+	if ok {
+		h.offset = offset
+	}
+	return ok
+}
+
+func (h *memoryHistory) manualScrollRestoration() { h.manualScroll = true }
+
+// onPopState registers fn to run when travel moves to another entry, as the back and forward
+// buttons do.
+func (h *memoryHistory) onPopState(fn func()) func() {
+	h.popState = fn
+	return func() { h.popState = nil }
+}
+
+// onPageHide registers fn to run when hide is called, as a reload or closing the tab does.
+func (h *memoryHistory) onPageHide(fn func()) func() {
+	h.pageHide = fn
+	return func() { h.pageHide = nil }
+}
+
+// travel moves delta entries back (negative) or forward and fires popstate, like history.go.
+// It does nothing when there is no entry there.
+func (h *memoryHistory) travel(delta int) {
+	h.entry()
+	target := h.current + delta
+	if delta == 0 || target < 0 || target >= len(h.stack) {
+		return
+	}
+	h.current = target
+	h.setURL(h.stack[target].browserPath)
+	if h.popState != nil {
+		h.popState()
+	}
+}
+
+// hide fires pagehide.
+func (h *memoryHistory) hide() {
+	if h.pageHide != nil {
+		h.pageHide()
+	}
+}
+
+// reload returns the history a reload of the page starts with: the same entries and
+// position, without listeners. It fires pagehide first.
+func (h *memoryHistory) reload() *memoryHistory {
+	h.hide()
+	h.entry()
+	return &memoryHistory{
+		path:     h.path,
+		query:    h.query,
+		fragment: h.fragment,
+		stack:    append([]memoryEntry(nil), h.stack...),
+		current:  h.current,
+	}
+}
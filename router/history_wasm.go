@@ -0,0 +1,102 @@
+//go:build js || wasm
+
+package router
+
+import (
+	"net/url"
+	"syscall/js"
+)
+
+// jsHistory drives the browser's History API and reads window.location.
+type jsHistory struct{}
+
+func newBrowserHistory() browserHistory { return jsHistory{} }
+
+// historyStateKey is the property of history.state holding the engine's state.
+const historyStateKey = "nojs"
+
+func (jsHistory) pathname() string {
+	return js.Global().Get("location").Get("pathname").String()
+}
+
+func (jsHistory) search() string {
+	return js.Global().Get("location").Get("search").String()
+}
+
+func (jsHistory) hash() string {
+	return js.Global().Get("location").Get("hash").String()
+}
+
+func (jsHistory) pushState(browserPath string) {
+	js.Global().Get("history").Call("pushState", nil, "", browserPath)
+}
+
+// replaceState changes the URL of the current entry, keeping its state.
+func (jsHistory) replaceState(browserPath string) {
+	history := js.Global().Get("history")
+	history.Call("replaceState", history.Get("state"), "", browserPath)
+}
+
+func (jsHistory) state() string {
+	state := js.Global().Get("history").Get("state")
+	if state.Type() != js.TypeObject {
+		return ""
+	}
+	if value := state.Get(historyStateKey); value.Type() == js.TypeString {
+		return value.String()
+	}
+	return ""
+}
+
+// setState replaces the state of the current entry, keeping its URL.
+func (jsHistory) setState(state string) {
+	js.Global().Get("history").Call("replaceState", map[string]any{historyStateKey: state}, "")
+}
+
+func (jsHistory) scrollY() float64 {
+	return js.Global().Get("scrollY").Float()
+}
+
+func (jsHistory) scrollTo(y float64) {
+	js.Global().Call("scrollTo", 0, y)
+}
+
+// scrollIntoView scrolls to the element whose id is the fragment id, percent-decoded as the
+// browser does for fragment links.
+func (jsHistory) scrollIntoView(id string) bool {
+	if decoded, err := url.PathUnescape(id); err == nil {
+		id = decoded
+	}
+	element := js.Global().Get("document").Call("getElementById", id)
+	if element.IsNull() {
+		return false
+	}
+	element.Call("scrollIntoView")
+	return true
+}
+
+func (jsHistory) manualScrollRestoration() {
+	js.Global().Get("history").Set("scrollRestoration", "manual")
+}
+
+func (jsHistory) onPopState(fn func()) func() {
+	return addWindowListener("popstate", fn)
+}
+
+func (jsHistory) onPageHide(fn func()) func() {
+	return addWindowListener("pagehide", fn)
+}
+
+// addWindowListener calls fn on every event of type on window; the returned function removes
+// the listener.
+func addWindowListener(event string, fn func()) func() {
+	listener := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		fn()
+		return nil
+	})
+	js.Global().Call("addEventListener", event, listener)
+	return func() {
+		js.Global().Call("removeEventListener", event, listener)
+		listener.Release()
+	}
+}
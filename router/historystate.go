@@ -0,0 +1,215 @@
+package router
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// historyStateLimit is the practical size limit of history.state across browsers. Engine
+// writes larger states are refused; historyStateWarnSize and above are reported.
+const (
+	historyStateLimit    = 640 * 1024
+	historyStateWarnSize = historyStateLimit * 3 / 4
+)
+
+// ErrHistoryStateTooLarge is returned (wrapped) by SetHistoryState when the encoded state of
+// the entry would exceed the size browsers reliably keep (about 640 KB). The entry keeps the
+// state it had; the value is still available until the page is reloaded.
+var ErrHistoryStateTooLarge = errors.New("history state too large")
+
+// HistoryState is the state bag of one browser history entry. The engine keeps it in
+// history.state, as JSON, so it survives a full page reload, and swaps it with the entry on
+// back/forward navigation. A new navigation starts with an empty bag.
+//
+// The bag of the current entry is RouteContext.History. Components read it in OnMount and
+// write it in OnUnmount or event handlers, or tag fields `nojs:"state,history=key"` to have
+// the router do both. Writes through the bag are stored in the entry when the page leaves it
+// (a new navigation, a reload or closing the tab); Engine.SetHistoryState stores them at once.
+type HistoryState struct {
+	mu     sync.Mutex
+	id     string                     // Identifies the entry across back/forward navigation
+	values map[string]json.RawMessage // Encoded values by key
+}
+
+// historyStateData is the JSON form of a HistoryState in history.state.
+type historyStateData struct {
+	ID     string                     `json:"id"`
+	Values map[string]json.RawMessage `json:"values,omitempty"`
+}
+
+// Verify the bag satisfies the interface generated components save into.
+var _ runtime.HistoryBag = (*HistoryState)(nil)
+
+func newHistoryState(id string) *HistoryState {
+	return &HistoryState{id: id, values: make(map[string]json.RawMessage)}
+}
+
+// Get decodes the value stored under key into v, a pointer, and reports whether there was
+// one that decoded. It is safe to call on a nil bag.
+func (s *HistoryState) Get(key string, v any) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	raw, ok := s.values[key]
+	s.mu.Unlock()
+	return ok && json.Unmarshal(raw, v) == nil
+}
+
+// Set stores v, encoded as JSON, under key.
+func (s *HistoryState) Set(key string, v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("router: history state %q: %w", key, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = raw
+	return nil
+}
+
+// Delete removes the value under key.
+func (s *HistoryState) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// encode returns the history.state form of the bag.
+func (s *HistoryState) encode() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(historyStateData{ID: s.id, Values: s.values})
+	return string(data), err
+}
+
+// decodeHistoryState parses the history.state form of a bag; ok is false when state holds
+// none, such as the first visit of an entry.
+func decodeHistoryState(state string) (bag *HistoryState, ok bool) {
+	var data historyStateData
+	if state == "" || json.Unmarshal([]byte(state), &data) != nil || data.ID == "" {
+		return nil, false
+	}
+	bag = newHistoryState(data.ID)
+	for key, raw := range data.Values {
+		bag.values[key] = raw
+	}
+	return bag, true
+}
+
+// historyEntries tracks the bags of the entries the engine has shown in this page session.
+// The bag of an entry left by back/forward cannot be written to history.state any more (the
+// browser has already switched entries), so it is kept here for when the user returns.
+type historyEntries struct {
+	session string // Distinguishes the entry IDs of this page session from those of earlier loads
+	next    int
+	bags    map[string]*HistoryState
+}
+
+func newHistoryEntries() historyEntries {
+	return historyEntries{
+		session: strconv.FormatInt(time.Now().UnixNano(), 36),
+		bags:    make(map[string]*HistoryState),
+	}
+}
+
+// fresh returns an empty bag for a new entry.
+func (h *historyEntries) fresh() *HistoryState {
+	h.next++
+	bag := newHistoryState(h.session + "." + strconv.Itoa(h.next))
+	h.bags[bag.id] = bag
+	return bag
+}
+
+// forEntry returns the bag of the entry whose history.state is state: the one kept in this
+// session, the one stored in state after a reload, or a fresh bag for an entry without one.
+func (h *historyEntries) forEntry(state string) *HistoryState {
+	stored, ok := decodeHistoryState(state)
+	if !ok {
+		return h.fresh()
+	}
+	if bag, ok := h.bags[stored.id]; ok {
+		return bag
+	}
+	h.bags[stored.id] = stored
+	return stored
+}
+
+// SetHistoryState stores value under key in the bag of the current history entry and writes
+// the bag to history.state at once, so it survives a reload even if the page is killed.
+// Passive engines keep the bag in memory only.
+func (e *Engine) SetHistoryState(key string, value any) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.routeCtx.History.Set(key, value); err != nil {
+		return err
+	}
+	return e.writeHistoryEntry()
+}
+
+// GetHistoryState decodes the value stored under key in the bag of the current history entry
+// into v, a pointer, and reports whether there was one.
+func (e *Engine) GetHistoryState(key string, v any) bool {
+	e.mu.Lock()
+	bag := e.routeCtx.History
+	e.mu.Unlock()
+	return bag.Get(key, v)
+}
+
+// saveHistoryState has every live component implementing runtime.HistoryStateful store its
+// tagged fields in the current bag, next to the window's scroll offset. The caller must hold
+// e.mu.
+func (e *Engine) saveHistoryState() {
+	e.saveScrollPosition()
+	for _, instance := range e.liveInstances {
+		if stateful, ok := instance.(runtime.HistoryStateful); ok {
+			if err := stateful.SaveHistoryState(e.routeCtx.History); err != nil {
+				e.log.Warn("[Engine] Failed to save history state:", err.Error())
+			}
+		}
+	}
+}
+
+// restoreHistoryState sets the tagged fields of a new instance from the current bag.
+func (e *Engine) restoreHistoryState(instance runtime.Component) {
+	if stateful, ok := instance.(runtime.HistoryStateful); ok {
+		stateful.RestoreHistoryState(e.routeCtx.History)
+	}
+}
+
+// writeHistoryEntry stores the current bag in history.state. It does nothing for a passive
+// engine, which must not touch history. The caller must hold e.mu.
+func (e *Engine) writeHistoryEntry() error {
+	if mode, _ := e.settings(); mode != ModePrimary {
+		return nil
+	}
+	state, err := e.routeCtx.History.encode()
+	if err != nil {
+		return fmt.Errorf("router: history state: %w", err)
+	}
+	if len(state) > historyStateLimit {
+		e.log.Warn("[Engine] History state of", e.currentPath, "is", len(state), "bytes, over the limit of",
+			historyStateLimit, "- not saved. Keep large data in storage and only its key in history state.")
+		return fmt.Errorf("router: %w: %d bytes (limit %d)", ErrHistoryStateTooLarge, len(state), historyStateLimit)
+	}
+	if len(state) > historyStateWarnSize {
+		e.log.Warn("[Engine] History state of", e.currentPath, "is", len(state), "bytes, close to the limit of", historyStateLimit)
+	}
+	e.history.setState(state)
+	return nil
+}
+
+// leaveHistoryEntry stores the live components' state in the current bag and writes it to
+// the entry the page is about to leave, for it to be there after a reload.
+func (e *Engine) leaveHistoryEntry() {
+	e.saveHistoryState()
+	if err := e.writeHistoryEntry(); err != nil {
+		e.log.Warn("[Engine] Failed to store history state:", err.Error())
+	}
+}
@@ -0,0 +1,145 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// stepPage keeps Step in history state, as a component with a `nojs:"state,history=step"`
+// field does through its generated methods.
+type stepPage struct {
+	runtime.ComponentBase
+	Step int
+}
+
+func (p *stepPage) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("p", nil, nil, "step")
+}
+
+func (p *stepPage) SaveHistoryState(bag runtime.HistoryBag) error {
+	return bag.Set("step", p.Step)
+}
+
+func (p *stepPage) RestoreHistoryState(bag runtime.HistoryBag) {
+	bag.Get("step", &p.Step)
+}
+
+// historyStateHarness is a started engine with routes /wizard (a stepPage) and /done.
+type historyStateHarness struct {
+	engine  *Engine
+	history *memoryHistory
+	page    *stepPage // The stepPage created last
+}
+
+func startHistoryStateEngine(t *testing.T, history *memoryHistory) *historyStateHarness {
+	t.Helper()
+	h := &historyStateHarness{history: history}
+	h.engine = startTestEngine(t, history,
+		Route{Path: "/wizard", Chain: []ComponentMetadata{{TypeID: 1, Factory: func(map[string]string) runtime.Component {
+			h.page = &stepPage{}
+			return h.page
+		}}}},
+		Route{Path: "/done", Chain: []ComponentMetadata{pageMeta(2, "done")}},
+	)
+	return h
+}
+
+// TestHistoryState_SurvivesReload verifies the state of the current entry, tagged fields and
+// values set through the bag, is back after a full page reload.
+func TestHistoryState_SurvivesReload(t *testing.T) {
+	// Arrange
+	before := startHistoryStateEngine(t, &memoryHistory{path: "/wizard"})
+	before.page.Step = 2
+	if err := before.engine.RouteContext().History.Set("plan", "pro"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	after := startHistoryStateEngine(t, before.history.reload())
+
+	// Assert
+	if after.page.Step != 2 {
+		t.Errorf("expected step 2 after the reload, got %d", after.page.Step)
+	}
+	var plan string
+	if !after.engine.GetHistoryState("plan", &plan) || plan != "pro" {
+		t.Errorf("expected plan pro after the reload, got %q", plan)
+	}
+	if len(after.history.entries) != 0 {
+		t.Errorf("expected Start to take over the loaded entry, got pushes %v", after.history.entries)
+	}
+}
+
+// TestHistoryState_NewEntryStartsEmpty verifies a navigation starts with an empty bag and
+// stores the state of the entry it leaves.
+func TestHistoryState_NewEntryStartsEmpty(t *testing.T) {
+	// Arrange
+	h := startHistoryStateEngine(t, &memoryHistory{path: "/wizard"})
+	h.page.Step = 3
+
+	// Act
+	if err := h.engine.Navigate("/done"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assert
+	var step int
+	if h.engine.GetHistoryState("step", &step) {
+		t.Errorf("expected an empty bag on /done, got step %d", step)
+	}
+	if stored, ok := decodeHistoryState(h.history.stack[0].state); !ok || !stored.Get("step", &step) || step != 3 {
+		t.Errorf("expected step 3 stored in the /wizard entry, got %q", h.history.stack[0].state)
+	}
+}
+
+// TestHistoryState_BackForward verifies back/forward navigation brings back the state of the
+// entry returned to, including changes made after the entry was last written.
+func TestHistoryState_BackForward(t *testing.T) {
+	// Arrange
+	h := startHistoryStateEngine(t, &memoryHistory{path: "/wizard"})
+	h.page.Step = 1
+	if err := h.engine.Navigate("/done"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	h.history.travel(-1)
+	first := h.page.Step
+	h.page.Step = 4
+	h.history.travel(1)
+	h.history.travel(-1)
+
+	// Assert
+	if first != 1 {
+		t.Errorf("expected step 1 back on /wizard, got %d", first)
+	}
+	if h.page.Step != 4 {
+		t.Errorf("expected step 4 after forward and back, got %d", h.page.Step)
+	}
+}
+
+// TestSetHistoryState_TooLarge verifies a state over the browser limit is refused, leaving
+// the entry with the state it had.
+func TestSetHistoryState_TooLarge(t *testing.T) {
+	// Arrange
+	h := startHistoryStateEngine(t, &memoryHistory{path: "/wizard"})
+	before := h.history.state()
+
+	// Act
+	err := h.engine.SetHistoryState("blob", strings.Repeat("x", historyStateLimit))
+
+	// Assert
+	if !errors.Is(err, ErrHistoryStateTooLarge) {
+		t.Errorf("expected ErrHistoryStateTooLarge, got %v", err)
+	}
+	if h.history.state() != before {
+		t.Errorf("expected the entry to keep its state, got %d bytes", len(h.history.state()))
+	}
+}
@@ -0,0 +1,44 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// hydratingRenderer is a routeTestRenderer that records when it is asked to hydrate.
+type hydratingRenderer struct {
+	*routeTestRenderer
+	events *[]string
+}
+
+func (r *hydratingRenderer) Hydrate() bool {
+	*r.events = append(*r.events, "hydrate")
+	return true
+}
+
+// TestEngine_StartHydratesBeforeInitialRoute verifies Start asks a renderer supporting
+// hydration to adopt the pre-rendered page before the initial route is shown.
+func TestEngine_StartHydratesBeforeInitialRoute(t *testing.T) {
+	// Arrange
+	var events []string
+	renderer := &hydratingRenderer{routeTestRenderer: newRouteTestRenderer(&lifecyclePage{}), events: &events}
+	engine := NewEngine(renderer)
+	engine.RegisterRoutes([]Route{{Path: "/", Chain: []ComponentMetadata{{TypeID: 1, Factory: func(map[string]string) runtime.Component {
+		return &lifecyclePage{}
+	}}}}})
+
+	// Act
+	err := engine.Start(func([]runtime.Component, string) { events = append(events, "show") })
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if len(events) != 2 || events[0] != "hydrate" || events[1] != "show" {
+		t.Errorf("expected hydration requested before the initial route is shown, got %v", events)
+	}
+}
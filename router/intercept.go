@@ -0,0 +1,184 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// ErrNavigationCancelled is returned (wrapped) by Navigate when a BeforeNavigate guard
+// cancels the navigation; the current page stays on screen.
+var ErrNavigationCancelled = errors.New("navigation cancelled")
+
+// maxGuardRedirects bounds the redirects of one navigation, so guards redirecting to each
+// other fail instead of looping.
+const maxGuardRedirects = 10
+
+// guardAction is what a GuardResult asks the engine to do.
+type guardAction int
+
+const (
+	guardAllow guardAction = iota
+	guardCancel
+	guardRedirect
+)
+
+// GuardResult is the decision of a BeforeNavigate guard: Allow, Cancel or Redirect.
+type GuardResult struct {
+	action guardAction
+	path   string // Target of a redirect
+}
+
+var (
+	// Allow lets the navigation proceed to the next guard, or to the route.
+	Allow = GuardResult{}
+	// Cancel stops the navigation and keeps the current page.
+	Cancel = GuardResult{action: guardCancel}
+)
+
+// Redirect replaces the navigation with one to path, which is guarded in turn.
+func Redirect(path string) GuardResult {
+	return GuardResult{action: guardRedirect, path: path}
+}
+
+// GuardFunc decides about a navigation from the path on screen to the app-relative
+// path to, whose route captured params. from is "" for the first navigation of Start.
+type GuardFunc func(from, to string, params map[string]string) GuardResult
+
+// NavigationListener is told about a completed navigation; see AfterNavigate.
+type NavigationListener func(from, to string, params map[string]string)
+
+// navigationHooks holds the guards and listeners registered on an Engine. Each is kept
+// behind its own pointer so that the removal function finds it.
+type navigationHooks struct {
+	before []*GuardFunc
+	after  []*NavigationListener
+	paths  []*func(newPath string) // See SubscribePathChange
+}
+
+// BeforeNavigate registers guard to run before a navigation activates a route: Navigate
+// calls, the initial navigation of Start and back/forward. Guards run in registration order
+// without the engine locked, so they may read it, and the first result other than Allow
+// decides. A Redirect on back/forward, or at Start, replaces the URL of the entry the
+// browser moved to; a Cancel on back/forward pushes the URL of the page still shown, so the
+// address bar matches it. Paths no route matches are not guarded. It returns the function
+// removing the guard.
+func (e *Engine) BeforeNavigate(guard GuardFunc) (remove func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	entry := &guard
+	e.hooks.before = append(e.hooks.before, entry)
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.hooks.before = slices.DeleteFunc(e.hooks.before, func(g *GuardFunc) bool { return g == entry })
+	}
+}
+
+// AfterNavigate registers listener to run after every completed navigation, e.g. to record
+// page views. It is called without the engine locked, with the app-relative paths left and
+// shown and the params of the route shown. It returns the function removing the listener.
+func (e *Engine) AfterNavigate(listener NavigationListener) (remove func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	entry := &listener
+	e.hooks.after = append(e.hooks.after, entry)
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.hooks.after = slices.DeleteFunc(e.hooks.after, func(l *NavigationListener) bool { return l == entry })
+	}
+}
+
+// intercept runs the BeforeNavigate guards for a navigation to path, app-relative with an
+// optional query. It returns the path to navigate to, following redirects, and whether a
+// guard redirected; a cancelled navigation fails with ErrNavigationCancelled. It must be
+// called without holding e.mu.
+func (e *Engine) intercept(path string) (string, bool, error) {
+	redirected := false
+	for hops := 0; ; hops++ {
+		routePath, _, _ := splitURL(path)
+		routePath = e.toRoutePath(routePath)
+
+		e.mu.Lock()
+		from := e.currentPath
+		guards := slices.Clone(e.hooks.before)
+		_, params, err := e.resolveRoute(routePath)
+		e.mu.Unlock()
+		if err != nil || len(guards) == 0 {
+			return path, redirected, nil // A path without a route fails in navigateInternal
+		}
+
+		result := Allow
+		for _, guard := range guards {
+			if result = (*guard)(from, routePath, maps.Clone(params)); result.action != guardAllow {
+				break
+			}
+		}
+		switch result.action {
+		case guardAllow:
+			return path, redirected, nil
+		case guardCancel:
+			e.log.Debug("[Engine.Navigate] Navigation cancelled by a guard:", routePath)
+			return "", redirected, fmt.Errorf("%w: %s", ErrNavigationCancelled, routePath)
+		}
+		if hops == maxGuardRedirects {
+			return "", redirected, fmt.Errorf("router: more than %d guard redirects navigating to %s", maxGuardRedirects, routePath)
+		}
+		e.log.Debug("[Engine.Navigate] Guard redirected", routePath, "to", result.path)
+		path, redirected = result.path, true
+	}
+}
+
+// popState handles a popstate event: the browser has already moved to the entry of
+// routePath, so a cancelled navigation puts the URL of the page shown back and a
+// redirected one replaces the entry's URL.
+func (e *Engine) popState(routePath string) error {
+	target, redirected, err := e.intercept(routePath)
+	switch {
+	case errors.Is(err, ErrNavigationCancelled):
+		e.restoreAddressBar()
+		return err
+	case err != nil:
+		return err
+	case redirected:
+		return e.navigate(target, historyReplace)
+	}
+	return e.navigate(target, historyNone)
+}
+
+// restoreAddressBar pushes the URL of the page shown, with its history state, after the
+// browser moved to another entry.
+func (e *Engine) restoreAddressBar() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	browserPath := e.toBrowserPath(e.currentPath)
+	if e.currentQuery != "" {
+		browserPath += "?" + e.currentQuery
+	}
+	e.history.pushState(browserPath)
+	if err := e.writeHistoryEntry(); err != nil {
+		e.log.Warn("[Engine] Failed to store history state:", err.Error())
+	}
+}
+
+// afterNavigate calls the AfterNavigate listeners about the navigation just committed, and
+// the path subscribers when it changed the path. It must be called without holding e.mu.
+func (e *Engine) afterNavigate() {
+	e.mu.Lock()
+	from, to, params := e.previousPath, e.currentPath, e.currentParams
+	listeners := slices.Clone(e.hooks.after)
+	var subscribers []*func(string)
+	if from != to {
+		subscribers = slices.Clone(e.hooks.paths)
+	}
+	e.mu.Unlock()
+
+	for _, listener := range listeners {
+		(*listener)(from, to, maps.Clone(params))
+	}
+	for _, subscriber := range subscribers {
+		(*subscriber)(to)
+	}
+}
@@ -0,0 +1,225 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// interceptRoutes are the routes /, /login, /form, /admin/{section} and /users/{id}.
+var interceptRoutes = []Route{
+	{Path: "/", Chain: []ComponentMetadata{pageMeta(1, "home")}},
+	{Path: "/login", Chain: []ComponentMetadata{pageMeta(2, "login")}},
+	{Path: "/form", Chain: []ComponentMetadata{pageMeta(3, "form")}},
+	{Path: "/admin/{section}", Chain: []ComponentMetadata{pageMeta(4, "admin")}},
+	{Path: "/users/{id}", Chain: []ComponentMetadata{pageMeta(5, "user")}},
+}
+
+// requireLogin redirects the admin section to /login.
+func requireLogin(from, to string, params map[string]string) GuardResult {
+	if strings.HasPrefix(to, "/admin/") {
+		return Redirect("/login")
+	}
+	return Allow
+}
+
+// TestBeforeNavigate_RunsInOrderUntilNotAllow verifies guards run in registration order
+// with the paths and params of the navigation, and stop at the first result other than
+// Allow.
+func TestBeforeNavigate_RunsInOrderUntilNotAllow(t *testing.T) {
+	// Arrange
+	var calls []string
+	record := func(name string, result GuardResult) GuardFunc {
+		return func(from, to string, params map[string]string) GuardResult {
+			calls = append(calls, name+" "+from+"->"+to+" id="+params["id"])
+			return result
+		}
+	}
+	history := &memoryHistory{path: "/"}
+	engine := startTestEngine(t, history, interceptRoutes...)
+	engine.BeforeNavigate(record("first", Allow))
+	engine.BeforeNavigate(record("second", Cancel))
+	engine.BeforeNavigate(record("third", Allow))
+
+	// Act
+	err := engine.Navigate("/users/7")
+
+	// Assert
+	if !errors.Is(err, ErrNavigationCancelled) {
+		t.Fatalf("expected ErrNavigationCancelled, got %v", err)
+	}
+	want := []string{"first /->/users/7 id=7", "second /->/users/7 id=7"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("expected guard calls %v, got %v", want, calls)
+	}
+	if engine.CurrentPath() != "/" || len(history.entries) != 0 {
+		t.Errorf("expected to stay at / without a history entry, got %s and %v", engine.CurrentPath(), history.entries)
+	}
+}
+
+// TestBeforeNavigate_RedirectsNavigate verifies a redirect shows the guard's path instead,
+// pushing only its entry.
+func TestBeforeNavigate_RedirectsNavigate(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/"}
+	engine := startTestEngine(t, history, interceptRoutes...)
+	engine.BeforeNavigate(requireLogin)
+
+	// Act
+	err := engine.Navigate("/admin/users")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Navigate failed: %v", err)
+	}
+	if engine.CurrentPath() != "/login" || !slices.Equal(history.entries, []string{"/login"}) {
+		t.Errorf("expected /login to be shown and pushed, got %s and %v", engine.CurrentPath(), history.entries)
+	}
+}
+
+// TestBeforeNavigate_RedirectsAtStart verifies a page loaded at a guarded path shows the
+// redirect target and replaces the URL of its entry.
+func TestBeforeNavigate_RedirectsAtStart(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/admin/users"}
+	engine := newTestEngine(interceptRoutes...)
+	engine.history = history
+	engine.BeforeNavigate(requireLogin)
+
+	// Act
+	err := engine.Start(func([]runtime.Component, string) {})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if engine.CurrentPath() != "/login" {
+		t.Errorf("expected /login to be shown, got %s", engine.CurrentPath())
+	}
+	if !slices.Equal(history.replaced, []string{"/login"}) || len(history.entries) != 0 {
+		t.Errorf("expected the entry's URL to be replaced with /login, got replaced %v, pushed %v", history.replaced, history.entries)
+	}
+}
+
+// TestBeforeNavigate_CancelledBackKeepsAddressBar verifies that cancelling a back navigation
+// keeps the page and puts its URL back in the address bar.
+func TestBeforeNavigate_CancelledBackKeepsAddressBar(t *testing.T) {
+	// Arrange
+	unsaved := func(from, to string, params map[string]string) GuardResult {
+		if from == "/form" {
+			return Cancel
+		}
+		return Allow
+	}
+	history := &memoryHistory{path: "/"}
+	engine := startTestEngine(t, history, interceptRoutes...)
+	engine.BeforeNavigate(unsaved)
+	if err := engine.Navigate("/form?draft=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Act
+	history.travel(-1)
+
+	// Assert
+	if engine.CurrentPath() != "/form" {
+		t.Errorf("expected /form to stay on screen, got %s", engine.CurrentPath())
+	}
+	if history.pathname()+history.search() != "/form?draft=1" {
+		t.Errorf("expected the address bar to show /form?draft=1, got %s%s", history.pathname(), history.search())
+	}
+}
+
+// TestBeforeNavigate_RedirectedBackReplacesEntry verifies a redirected back navigation
+// replaces the URL of the entry the browser moved to.
+func TestBeforeNavigate_RedirectedBackReplacesEntry(t *testing.T) {
+	// Arrange
+	loggedIn := true
+	history := &memoryHistory{path: "/admin/users"}
+	engine := startTestEngine(t, history, interceptRoutes...)
+	engine.BeforeNavigate(func(from, to string, params map[string]string) GuardResult {
+		if loggedIn {
+			return Allow
+		}
+		return requireLogin(from, to, params)
+	})
+	if err := engine.Navigate("/"); err != nil {
+		t.Fatal(err)
+	}
+	loggedIn = false
+
+	// Act
+	history.travel(-1)
+
+	// Assert
+	if engine.CurrentPath() != "/login" || history.pathname() != "/login" {
+		t.Errorf("expected /login on screen and in the address bar, got %s and %s", engine.CurrentPath(), history.pathname())
+	}
+	if !slices.Equal(history.replaced, []string{"/login"}) {
+		t.Errorf("expected the entry to be replaced with /login, got %v", history.replaced)
+	}
+}
+
+// TestBeforeNavigate_StopsRedirectLoops verifies guards redirecting to each other fail the
+// navigation instead of looping.
+func TestBeforeNavigate_StopsRedirectLoops(t *testing.T) {
+	// Arrange
+	engine := startTestEngine(t, &memoryHistory{path: "/"}, interceptRoutes...)
+	engine.BeforeNavigate(func(from, to string, params map[string]string) GuardResult {
+		if to == "/login" {
+			return Redirect("/form")
+		}
+		if to == "/form" {
+			return Redirect("/login")
+		}
+		return Allow
+	})
+
+	// Act
+	err := engine.Navigate("/form")
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "guard redirects") {
+		t.Errorf("expected the redirect loop to fail, got %v", err)
+	}
+	if engine.CurrentPath() != "/" {
+		t.Errorf("expected to stay at /, got %s", engine.CurrentPath())
+	}
+}
+
+// TestAfterNavigate_ReportsCompletedNavigations verifies listeners are told about completed
+// navigations, including back/forward, and not about cancelled ones or after removal.
+func TestAfterNavigate_ReportsCompletedNavigations(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/"}
+	engine := startTestEngine(t, history, interceptRoutes...)
+	engine.BeforeNavigate(func(from, to string, params map[string]string) GuardResult {
+		if to == "/form" {
+			return Cancel
+		}
+		return Allow
+	})
+	var seen []string
+	remove := engine.AfterNavigate(func(from, to string, params map[string]string) {
+		seen = append(seen, from+"->"+to+" id="+params["id"])
+	})
+
+	// Act
+	engine.Navigate("/users/3")
+	engine.Navigate("/form")
+	history.travel(-1)
+	remove()
+	engine.Navigate("/login")
+
+	// Assert
+	want := []string{"/->/users/3 id=3", "/users/3->/ id="}
+	if !slices.Equal(seen, want) {
+		t.Errorf("expected %v, got %v", want, seen)
+	}
+}
@@ -0,0 +1,127 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ForgeLogic/nojs/notify"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// lifecyclePage records its lifecycle calls in a log shared by the pages of a test.
+type lifecyclePage struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+	name        string
+	log         *[]string
+}
+
+func (p *lifecyclePage) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.Div(nil, p.BodyContent...)
+}
+
+func (p *lifecyclePage) SetBodyContent(content []*vdom.VNode) { p.BodyContent = content }
+func (p *lifecyclePage) SetParams(params map[string]string)   {}
+func (p *lifecyclePage) OnMount()                             { *p.log = append(*p.log, p.name+".OnMount") }
+func (p *lifecyclePage) OnParametersSet()                     { *p.log = append(*p.log, p.name+".OnParametersSet") }
+func (p *lifecyclePage) OnUnmount()                           { *p.log = append(*p.log, p.name+".OnUnmount") }
+
+// lifecycleRoutes returns the routes / and /{id}, which show a page under a shared layout,
+// logging the lifecycle calls of both.
+func lifecycleRoutes(log *[]string) []Route {
+	layout := ComponentMetadata{TypeID: 1, Factory: func(map[string]string) runtime.Component {
+		return &lifecyclePage{name: "layout", log: log}
+	}}
+	return []Route{
+		{Path: "/", Chain: []ComponentMetadata{layout, {TypeID: 2, Factory: func(map[string]string) runtime.Component {
+			return &lifecyclePage{name: "home", log: log}
+		}}}},
+		{Path: "/{id}", Chain: []ComponentMetadata{layout, {TypeID: 3, Factory: func(params map[string]string) runtime.Component {
+			return &lifecyclePage{name: "item" + params["id"], log: log}
+		}}}},
+	}
+}
+
+// TestEngine_MountsAndUnmountsChainWithoutAppShell verifies the components a navigation
+// creates are mounted and the ones it discards unmounted, while the kept layout is not.
+func TestEngine_MountsAndUnmountsChainWithoutAppShell(t *testing.T) {
+	// Arrange
+	var log []string
+	engine := newTestEngine(lifecycleRoutes(&log)...)
+	if err := engine.Navigate("/"); err != nil {
+		t.Fatalf("Navigate(/): %v", err)
+	}
+	if want := []string{"layout.OnMount", "layout.OnParametersSet", "home.OnMount", "home.OnParametersSet"}; !slices.Equal(log, want) {
+		t.Fatalf("expected %v on the first navigation, got %v", want, log)
+	}
+	log = nil
+
+	// Act
+	if err := engine.Navigate("/7"); err != nil {
+		t.Fatalf("Navigate(/7): %v", err)
+	}
+
+	// Assert
+	if want := []string{"home.OnUnmount", "item7.OnMount", "item7.OnParametersSet", "layout.OnParametersSet"}; !slices.Equal(log, want) {
+		t.Errorf("expected %v, got %v", want, log)
+	}
+}
+
+// TestEngine_LeavesLifecycleToAppShell verifies the engine does not call the lifecycle
+// hooks itself when an AppShell renders the chain, since its renderer does.
+func TestEngine_LeavesLifecycleToAppShell(t *testing.T) {
+	// Arrange
+	var log []string
+	engine := newTestEngine(lifecycleRoutes(&log)...)
+	engine.SetRouteChangeCallback(func([]runtime.Component, string) {})
+
+	// Act
+	if err := engine.Navigate("/"); err != nil {
+		t.Fatalf("Navigate(/): %v", err)
+	}
+	if err := engine.Navigate("/7"); err != nil {
+		t.Fatalf("Navigate(/7): %v", err)
+	}
+
+	// Assert
+	if len(log) != 0 {
+		t.Errorf("expected no lifecycle calls from the engine, got %v", log)
+	}
+}
+
+// TestEngine_DiscardedPageTimersStop verifies the intervals of a page a navigation discards
+// stop with it, while those of the kept layout go on.
+func TestEngine_DiscardedPageTimersStop(t *testing.T) {
+	// Arrange
+	clock := notify.NewManualClock(time.Unix(0, 0))
+	t.Cleanup(runtime.UseTimerClock(clock))
+	var log []string
+	engine := newTestEngine(lifecycleRoutes(&log)...)
+	if err := engine.Navigate("/"); err != nil {
+		t.Fatalf("Navigate(/): %v", err)
+	}
+	var layoutTicks, homeTicks int
+	runtime.SetInterval(engine.liveInstances[0], time.Second, func() { layoutTicks++ })
+	runtime.SetInterval(engine.liveInstances[1], time.Second, func() { homeTicks++ })
+	t.Cleanup(func() { runtime.Destroy(engine.liveInstances[0]) })
+
+	// Act
+	clock.Advance(time.Second)
+	if err := engine.Navigate("/7"); err != nil {
+		t.Fatalf("Navigate(/7): %v", err)
+	}
+	clock.Advance(2 * time.Second)
+
+	// Assert
+	if homeTicks != 1 {
+		t.Errorf("expected the discarded page's interval to stop after 1 tick, got %d", homeTicks)
+	}
+	if layoutTicks != 3 {
+		t.Errorf("expected the kept layout's interval to go on, got %d ticks", layoutTicks)
+	}
+}
@@ -0,0 +1,139 @@
+package router
+
+import (
+	"sort"
+	"strings"
+)
+
+// segmentKind orders the segments of route patterns by how precisely they match: a literal
+// matches one value, a param any single segment and a wildcard any number of segments.
+type segmentKind int
+
+const (
+	literalSegment segmentKind = iota
+	paramSegment
+	wildcardSegment
+)
+
+// patternSegment is one segment of a route pattern.
+type patternSegment struct {
+	kind  segmentKind
+	value string // The literal text, or the name params are stored under
+}
+
+// routePattern is a parsed route path. Params are written {name} or {name:type}; the
+// last segment may instead be a wildcard, *name or {name...}, capturing the remaining
+// segments (possibly none) joined by "/".
+type routePattern []patternSegment
+
+// splitPath returns the segments of path, ignoring leading and trailing slashes; the root
+// has none.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// parsePattern parses a route path. A wildcard anywhere but in the last segment is taken
+// literally.
+func parsePattern(path string) routePattern {
+	parts := splitPath(path)
+	pattern := make(routePattern, len(parts))
+	for i, part := range parts {
+		last := i == len(parts)-1
+		switch {
+		case last && strings.HasPrefix(part, "*"):
+			pattern[i] = patternSegment{wildcardSegment, part[1:]}
+		case last && strings.HasPrefix(part, "{") && strings.HasSuffix(part, "...}"):
+			pattern[i] = patternSegment{wildcardSegment, strings.TrimSuffix(part[1:], "...}")}
+		case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"):
+			// Typed patterns declare params as {name:type}; the map is keyed by name only
+			name, _, _ := strings.Cut(strings.Trim(part, "{}"), ":")
+			pattern[i] = patternSegment{paramSegment, name}
+		default:
+			pattern[i] = patternSegment{literalSegment, part}
+		}
+	}
+	return pattern
+}
+
+// kind returns the least precise kind of segment in p: literal for a static path.
+func (p routePattern) kind() segmentKind {
+	kind := literalSegment
+	for _, seg := range p {
+		kind = max(kind, seg.kind)
+	}
+	return kind
+}
+
+// match reports whether path matches p and returns the params it captures.
+func (p routePattern) match(path string) (map[string]string, bool) {
+	parts := splitPath(path)
+	params := make(map[string]string)
+	for i, seg := range p {
+		if seg.kind == wildcardSegment {
+			params[seg.value] = strings.Join(parts[min(i, len(parts)):], "/")
+			return params, true
+		}
+		if i >= len(parts) {
+			return nil, false
+		}
+		switch seg.kind {
+		case literalSegment:
+			if parts[i] != seg.value {
+				return nil, false
+			}
+		case paramSegment:
+			params[seg.value] = parts[i]
+		}
+	}
+	if len(parts) != len(p) {
+		return nil, false
+	}
+	return params, true
+}
+
+// precedes reports whether p is preferred over q when both match a path. Static patterns
+// come first, then patterns with params, then wildcards; between two of a kind, the first
+// segment where they differ decides, a literal beating a param and a param a wildcard.
+func (p routePattern) precedes(q routePattern) bool {
+	if p.kind() != q.kind() {
+		return p.kind() < q.kind()
+	}
+	for i := 0; i < len(p) && i < len(q); i++ {
+		if p[i].kind != q[i].kind {
+			return p[i].kind < q[i].kind
+		}
+	}
+	// Two wildcards after the same segments: the longer fixed prefix is more precise
+	return len(p) > len(q)
+}
+
+// matchRoutes returns the routes matching path with their params, the preferred first. The
+// caller must hold e.mu.
+func (e *Engine) matchRoutes(path string) []routeMatch {
+	var matches []routeMatch
+	for _, route := range e.routes {
+		pattern := parsePattern(route.Path)
+		if params, ok := pattern.match(path); ok {
+			matches = append(matches, routeMatch{route, params, pattern})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.pattern.precedes(b.pattern) != b.pattern.precedes(a.pattern) {
+			return a.pattern.precedes(b.pattern)
+		}
+		return a.route.Path < b.route.Path // Equally precise: keep the order stable
+	})
+	return matches
+}
+
+// routeMatch is a route matching a path and the params captured from it.
+type routeMatch struct {
+	route   *Route
+	params  map[string]string
+	pattern routePattern
+}
@@ -0,0 +1,153 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"errors"
+	"maps"
+	"strconv"
+	"testing"
+)
+
+// matchRoutes returns a one-page route for each pattern.
+func matchRoutes(patterns ...string) []Route {
+	var routes []Route
+	for i, pattern := range patterns {
+		routes = append(routes, Route{Path: pattern, Chain: []ComponentMetadata{pageMeta(uint32(i+1), pattern)}})
+	}
+	return routes
+}
+
+// TestEngine_MatchPrecedence verifies a path matching several routes is shown with the most
+// precise one, and what each captures.
+func TestEngine_MatchPrecedence(t *testing.T) {
+	// Arrange
+	engine := newTestEngine(matchRoutes(
+		"/users/new",
+		"/users/{id}",
+		"/users/{id}/edit",
+		"/users/{id}/{tab}",
+		"/users/*rest",
+		"/docs/*path",
+		"/files/{rest...}",
+		"/*any",
+	)...)
+	tests := []struct {
+		path, pattern string
+		params        map[string]string
+	}{
+		{"/users/new", "/users/new", map[string]string{}},
+		{"/users/42", "/users/{id}", map[string]string{"id": "42"}},
+		{"/users/42/edit", "/users/{id}/edit", map[string]string{"id": "42"}},
+		{"/users/42/posts", "/users/{id}/{tab}", map[string]string{"id": "42", "tab": "posts"}},
+		{"/users/42/posts/7", "/users/*rest", map[string]string{"rest": "42/posts/7"}},
+		{"/docs/guide/routing", "/docs/*path", map[string]string{"path": "guide/routing"}},
+		{"/docs", "/docs/*path", map[string]string{"path": ""}},
+		{"/files/a/b.txt", "/files/{rest...}", map[string]string{"rest": "a/b.txt"}},
+		{"/pricing", "/*any", map[string]string{"any": "pricing"}},
+		{"/", "/*any", map[string]string{"any": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// Act
+			err := engine.Navigate(tt.path)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("Navigate(%s): %v", tt.path, err)
+			}
+			rc := engine.RouteContext()
+			if rc.Pattern != tt.pattern {
+				t.Errorf("expected %s to show %s, got %s", tt.path, tt.pattern, rc.Pattern)
+			}
+			if !maps.Equal(rc.Params, tt.params) {
+				t.Errorf("expected params %v, got %v", tt.params, rc.Params)
+			}
+		})
+	}
+}
+
+// TestEngine_MatchIgnoresTrailingSlash verifies paths with and without a trailing slash are
+// shown with the same route and params.
+func TestEngine_MatchIgnoresTrailingSlash(t *testing.T) {
+	for _, path := range []string{"/users/42/", "/users/new/", "/docs/guide/", "/users/"} {
+		t.Run(path, func(t *testing.T) {
+			// Arrange
+			engine := newTestEngine(matchRoutes("/users/new", "/users/{id}", "/docs/*path", "/users")...)
+			trimmed := newTestEngine(matchRoutes("/users/new", "/users/{id}", "/docs/*path", "/users")...)
+
+			// Act
+			errSlash := engine.Navigate(path)
+			errTrimmed := trimmed.Navigate(path[:len(path)-1])
+
+			// Assert
+			if errSlash != nil || errTrimmed != nil {
+				t.Fatalf("expected both paths to match, got %v and %v", errSlash, errTrimmed)
+			}
+			got, want := engine.RouteContext(), trimmed.RouteContext()
+			if got.Pattern != want.Pattern || !maps.Equal(got.Params, want.Params) {
+				t.Errorf("expected %s like %s (%v), got %s (%v)", want.Pattern, want.Path, want.Params, got.Pattern, got.Params)
+			}
+		})
+	}
+}
+
+// TestEngine_NotFoundOnlyWithoutMatch verifies ErrRouteNotFound is returned only when no
+// route matches; a catch-all route shows every other path.
+func TestEngine_NotFoundOnlyWithoutMatch(t *testing.T) {
+	// Arrange
+	engine := newTestEngine(matchRoutes("/docs/*path")...)
+
+	// Act
+	errDocs := engine.Navigate("/docs/a")
+	errBlog := engine.Navigate("/blog")
+	engine.RegisterRoutes([]Route{{Path: "/{page...}", Chain: []ComponentMetadata{pageMeta(9, "not-found")}}})
+	errAfter := engine.Navigate("/blog")
+
+	// Assert
+	if errDocs != nil {
+		t.Errorf("expected /docs/a to match, got %v", errDocs)
+	}
+	if !errors.Is(errBlog, ErrRouteNotFound) {
+		t.Errorf("expected ErrRouteNotFound for /blog, got %v", errBlog)
+	}
+	if errAfter != nil || engine.RouteContext().Params["page"] != "blog" {
+		t.Errorf("expected the catch-all to show /blog, got %v, params %v", errAfter, engine.RouteContext().Params)
+	}
+}
+
+// TestEngine_InvalidParamsFallThrough verifies a path whose params a route rejects is shown
+// with the next matching route, and fails with the validation error when none is left.
+func TestEngine_InvalidParamsFallThrough(t *testing.T) {
+	// Arrange
+	invalidYear := errors.New("year must be a number")
+	year := Route{
+		Path:  "/blog/{year:int}",
+		Chain: []ComponentMetadata{pageMeta(1, "year")},
+		Validate: func(params map[string]string) error {
+			if _, err := strconv.Atoi(params["year"]); err != nil {
+				return invalidYear
+			}
+			return nil
+		},
+	}
+	strict := newTestEngine(year)
+	lenient := newTestEngine(year, Route{Path: "/blog/*slug", Chain: []ComponentMetadata{pageMeta(2, "post")}})
+
+	// Act
+	errStrict := strict.Navigate("/blog/hello")
+	errLenient := lenient.Navigate("/blog/hello")
+
+	// Assert
+	if !errors.Is(errStrict, ErrRouteNotFound) || !errors.Is(errStrict, invalidYear) {
+		t.Errorf("expected ErrRouteNotFound wrapping the validation error, got %v", errStrict)
+	}
+	if errLenient != nil || lenient.RouteContext().Pattern != "/blog/*slug" {
+		t.Errorf("expected /blog/hello to fall through to /blog/*slug, got %v (%s)", errLenient, lenient.RouteContext().Pattern)
+	}
+	if _, ok := lenient.GetComponentForPath("/blog/2024"); !ok {
+		t.Error("expected /blog/2024 to resolve to the year page")
+	}
+}
@@ -0,0 +1,162 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// userLayout is a preserved layout that keeps the user id of the route.
+type userLayout struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+	UserID      string
+	updates     int // SetParams calls
+}
+
+func (l *userLayout) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.Div(nil, l.BodyContent...)
+}
+
+func (l *userLayout) SetBodyContent(content []*vdom.VNode) { l.BodyContent = content }
+
+func (l *userLayout) SetParams(params map[string]string) {
+	l.UserID = params["id"]
+	l.updates++
+}
+
+// postPage shows one post of a user.
+type postPage struct {
+	runtime.ComponentBase
+	UserID, PostID string
+}
+
+func (p *postPage) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("p", nil, nil, p.UserID+"/"+p.PostID)
+}
+
+// paramsHarness is an engine with the route /users/{id}/posts/{postId}, a userLayout above
+// a postPage, recording the instances its factories created.
+type paramsHarness struct {
+	engine   *Engine
+	renderer *routeTestRenderer
+	layouts  []*userLayout
+	pages    []*postPage
+	params   []map[string]string // Params passed to the page factory, in call order
+}
+
+func newParamsHarness(t *testing.T) *paramsHarness {
+	t.Helper()
+	h := &paramsHarness{}
+	h.engine = newTestEngine(Route{
+		Path: "/users/{id}/posts/{postId}",
+		Chain: []ComponentMetadata{
+			{TypeID: 1, Factory: func(params map[string]string) runtime.Component {
+				layout := &userLayout{UserID: params["id"]}
+				h.layouts = append(h.layouts, layout)
+				return layout
+			}},
+			{TypeID: 2, Factory: func(params map[string]string) runtime.Component {
+				h.params = append(h.params, maps.Clone(params))
+				page := &postPage{UserID: params["id"], PostID: params["postId"]}
+				h.pages = append(h.pages, page)
+				return page
+			}},
+		},
+	})
+	h.renderer = h.engine.renderer.(*routeTestRenderer)
+	if err := h.engine.Navigate("/users/1/posts/7"); err != nil {
+		t.Fatalf("Navigate(/users/1/posts/7): %v", err)
+	}
+	return h
+}
+
+// TestEngine_FactoriesReceiveAllParams verifies every factory of a multi-parameter route gets
+// the params extracted from the path.
+func TestEngine_FactoriesReceiveAllParams(t *testing.T) {
+	// Act
+	h := newParamsHarness(t)
+
+	// Assert
+	if len(h.params) != 1 || h.params[0]["id"] != "1" || h.params[0]["postId"] != "7" {
+		t.Fatalf("expected the page factory to get id 1 and postId 7, got %v", h.params)
+	}
+	if h.layouts[0].UserID != "1" {
+		t.Errorf("expected the layout factory to get id 1, got %q", h.layouts[0].UserID)
+	}
+}
+
+// TestEngine_ParamChangeRecreatesPageOnly verifies a navigation changing only the last
+// param keeps the layout and recreates the page with the new value.
+func TestEngine_ParamChangeRecreatesPageOnly(t *testing.T) {
+	// Arrange
+	h := newParamsHarness(t)
+
+	// Act
+	if err := h.engine.Navigate("/users/1/posts/8"); err != nil {
+		t.Fatalf("Navigate(/users/1/posts/8): %v", err)
+	}
+
+	// Assert
+	if len(h.layouts) != 1 {
+		t.Errorf("expected the layout to be kept, got %d instances", len(h.layouts))
+	}
+	if len(h.pages) != 2 || h.pages[1].PostID != "8" {
+		t.Fatalf("expected a new page for post 8, got %d pages", len(h.pages))
+	}
+	if h.layouts[0].updates != 1 || h.layouts[0].UserID != "1" {
+		t.Errorf("expected the layout to receive the params once with id 1, got %d calls, id %q", h.layouts[0].updates, h.layouts[0].UserID)
+	}
+}
+
+// TestEngine_PreservedLayoutReceivesChangedParam verifies a layout kept across a change of
+// the param it shows gets the new value and is re-rendered.
+func TestEngine_PreservedLayoutReceivesChangedParam(t *testing.T) {
+	// Arrange
+	h := newParamsHarness(t)
+	layout := h.layouts[0]
+
+	// Act
+	if err := h.engine.Navigate("/users/2/posts/7"); err != nil {
+		t.Fatalf("Navigate(/users/2/posts/7): %v", err)
+	}
+
+	// Assert
+	if len(h.layouts) != 1 {
+		t.Fatalf("expected the layout to be kept, got %d instances", len(h.layouts))
+	}
+	if layout.UserID != "2" {
+		t.Errorf("expected the kept layout to show user 2, got %q", layout.UserID)
+	}
+	if h.pages[len(h.pages)-1].UserID != "2" {
+		t.Errorf("expected the new page to get user 2, got %q", h.pages[len(h.pages)-1].UserID)
+	}
+	slotParents := h.renderer.slotParents
+	if len(slotParents) == 0 || slotParents[len(slotParents)-1] != layout {
+		t.Errorf("expected the kept layout to be re-rendered, got %v", slotParents)
+	}
+}
+
+// TestEngine_GetComponentForPathPassesParams verifies the page resolved for a path is
+// created with the path's params.
+func TestEngine_GetComponentForPathPassesParams(t *testing.T) {
+	// Arrange
+	h := newParamsHarness(t)
+
+	// Act
+	component, ok := h.engine.GetComponentForPath("/users/3/posts/9")
+
+	// Assert
+	page, isPage := component.(*postPage)
+	if !ok || !isPage {
+		t.Fatalf("expected a postPage, got %T (found %v)", component, ok)
+	}
+	if page.UserID != "3" || page.PostID != "9" {
+		t.Errorf("expected user 3 and post 9, got %q and %q", page.UserID, page.PostID)
+	}
+}
@@ -0,0 +1,290 @@
+// Package prerender renders the routes of an app to static HTML files at build time, so
+// search engines and the first paint see each page's markup before the WASM module starts.
+// When the module boots, its first render hydrates the pre-rendered markup in the mount
+// element instead of replacing it (see runtime.RendererImpl.Hydrate).
+//
+// Routes and component factories are registered by the app at run time, so the pre-renderer
+// is a small native program next to the app that passes them to Main:
+//
+//	//go:build !wasm
+//
+//	package main
+//
+//	import (
+//	    "github.com/ForgeLogic/nojs-router/prerender"
+//	    "example.com/shop/internal/app"
+//	)
+//
+//	func main() { prerender.Main(app.Routes()) }
+//
+// and is run as part of the build:
+//
+//	go run ./cmd/prerender -shell wwwroot/index.html -out dist -paths /products/1,/products/2
+//
+// The package is meant for native builds.
+package prerender
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	router "github.com/ForgeLogic/nojs-router"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+	"github.com/ForgeLogic/nojs/vdom/htmlrender"
+)
+
+// Options configures Run.
+type Options struct {
+	// OutDir receives one <path>/index.html per rendered path, e.g. dist/about/index.html for
+	// /about and dist/index.html for /. "dist" when empty.
+	OutDir string
+
+	// Shell is the HTML document the markup of each page is inserted into, typically the
+	// app's index.html. A minimal document when empty.
+	Shell string
+
+	// MountID is the id of the element of Shell the markup is inserted into, the element the
+	// app mounts in. "app" when empty.
+	MountID string
+
+	// Paths are concrete paths to render besides the routes without params, e.g.
+	// /products/42 for the route /products/{id}. Routes with params are rendered only
+	// through the paths listed here.
+	Paths []string
+
+	// Services are provided for injection into component fields tagged `nojs:"inject"`,
+	// next to the engine and its RouteContext.
+	Services []any
+}
+
+// defaultShell is the document pages are inserted into when Options.Shell is empty.
+const defaultShell = `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+</head>
+<body>
+    <div id="app"></div>
+</body>
+</html>
+`
+
+// Main renders routes with the options given on the command line and exits with a non-zero
+// status when a page fails. See the package documentation for the flags.
+func Main(routes []router.Route) {
+	outDir := flag.String("out", "dist", "Directory receiving <path>/index.html for every rendered path.")
+	shellPath := flag.String("shell", "", "HTML shell the pages are inserted into (e.g., ./wwwroot/index.html); a minimal document when empty.")
+	mountID := flag.String("mount", "app", "Id of the shell element the pages are inserted into.")
+	paths := flag.String("paths", "", "Comma-separated concrete paths of routes with params (e.g., /products/1,/products/2).")
+	flag.Parse()
+
+	opts := Options{OutDir: *outDir, MountID: *mountID}
+	if *shellPath != "" {
+		shell, err := os.ReadFile(*shellPath)
+		if err != nil {
+			log.Fatalf("Failed to read shell: %v", err)
+		}
+		opts.Shell = string(shell)
+	}
+	if *paths != "" {
+		opts.Paths = strings.Split(*paths, ",")
+	}
+	if err := Run(routes, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Pre-rendering failed:\n%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Run renders the chain of every route without params, and of every path in opts.Paths, and
+// writes it into the shell as <OutDir>/<path>/index.html. Routes whose chain has an
+// AsyncFactory are skipped, as the component they wait for is not known at build time.
+//
+// Each path renders with a renderer and an engine of its own, like a fresh page load: the
+// components get OnMount and OnParametersSet before their first render, and the markup is
+// that first render. Work they start in the background is not waited for.
+//
+// Every path is attempted; the error joins the failures, among them a Render that panicked
+// in any component of the page.
+func Run(routes []router.Route, opts Options) error {
+	if opts.OutDir == "" {
+		opts.OutDir = "dist"
+	}
+	if opts.Shell == "" {
+		opts.Shell = defaultShell
+	}
+	if opts.MountID == "" {
+		opts.MountID = "app"
+	}
+
+	var paths []string
+	for _, route := range routes {
+		if !strings.Contains(route.Path, "{") && !isAsync(route) {
+			paths = append(paths, route.Path)
+		}
+	}
+	paths = append(paths, opts.Paths...)
+
+	var errs []error
+	for _, path := range paths {
+		html, err := RenderPath(routes, path, opts.Services...)
+		if err == nil {
+			err = writePage(opts, path, html)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		fmt.Printf("Pre-rendered %s\n", path)
+	}
+	return errors.Join(errs...)
+}
+
+// RenderPath returns the HTML of the chain routes renders for path: its first component,
+// usually the root layout, with the rest of the chain linked into the layouts' slots.
+func RenderPath(routes []router.Route, path string, services ...any) (string, error) {
+	r := newStaticRenderer()
+	for _, service := range services {
+		r.services.Provide(service)
+	}
+	engine := router.NewEngine(r)
+	engine.RegisterRoutes(routes)
+
+	var chain []runtime.Component
+	engine.SetRouteChangeCallback(func(instances []runtime.Component, key string) {
+		chain = instances
+	})
+	if err := engine.Navigate(path); err != nil {
+		return "", err
+	}
+	defer engine.Cleanup()
+	if len(chain) == 0 {
+		return "", nil
+	}
+	for _, instance := range chain {
+		if instance == nil {
+			return "", fmt.Errorf("the route's chain has a component created asynchronously")
+		}
+	}
+
+	// Mount top-down, so the context values layouts provide in OnMount reach their slot,
+	// then link bottom-up, as the AppShell does: each component renders into its layout's slot
+	for i, instance := range chain {
+		if i > 0 {
+			runtime.SetContextParent(instance, chain[i-1])
+		}
+		r.mount(instance, nil)
+	}
+	for i := len(chain) - 1; i > 0; i-- {
+		node := r.RenderChild(fmt.Sprintf("slot-chain-%d", i), chain[i])
+		if layout, ok := chain[i-1].(interface{ SetBodyContent([]*vdom.VNode) }); ok && node != nil {
+			layout.SetBodyContent([]*vdom.VNode{node})
+		}
+	}
+	root := r.RenderChild("slot-root", chain[0])
+	for _, c := range r.rendered {
+		if err := runtime.LastRenderError(c); err != nil {
+			return "", err
+		}
+	}
+	return htmlrender.RenderToString(root)
+}
+
+// isAsync reports whether a component of route's chain is created asynchronously.
+func isAsync(route router.Route) bool {
+	for _, meta := range route.Chain {
+		if meta.AsyncFactory != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writePage inserts html into the mount element of the shell and writes it as the
+// index.html of path under opts.OutDir.
+func writePage(opts Options, path, html string) error {
+	page, err := InjectMarkup(opts.Shell, opts.MountID, html)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(opts.OutDir, filepath.FromSlash(strings.Trim(path, "/")))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "index.html"), []byte(page), 0644)
+}
+
+// InjectMarkup inserts html at the start of the content of the shell element whose id is
+// mountID.
+func InjectMarkup(shell, mountID, html string) (string, error) {
+	openTag := regexp.MustCompile(`<[A-Za-z][^>]*\sid=["']?` + regexp.QuoteMeta(mountID) + `["'\s>/][^>]*>`)
+	loc := openTag.FindStringIndex(shell)
+	if loc == nil {
+		return "", fmt.Errorf("shell has no element with id %q to insert the page into", mountID)
+	}
+	return shell[:loc[1]] + html + shell[loc[1]:], nil
+}
+
+// staticRenderer renders a page once, in memory. It runs the lifecycle of the components it
+// renders the way the WASM renderer does on a first render; requests to render again, from
+// StateHasChanged or a navigation, are ignored.
+type staticRenderer struct {
+	services  *runtime.Services
+	rendering []runtime.Component // Components whose Render is running, innermost last
+	rendered  []runtime.Component // Every component rendered, in order
+	mounted   map[runtime.Component]bool
+}
+
+var _ runtime.Renderer = (*staticRenderer)(nil)
+var _ runtime.ServiceProvider = (*staticRenderer)(nil)
+
+func newStaticRenderer() *staticRenderer {
+	return &staticRenderer{services: runtime.NewServices(), mounted: make(map[runtime.Component]bool)}
+}
+
+func (r *staticRenderer) Services() *runtime.Services { return r.services }
+
+// RenderChild mounts child below the component rendering it, unless it is mounted, and
+// renders it.
+func (r *staticRenderer) RenderChild(key string, child runtime.Component) *vdom.VNode {
+	var parent runtime.Component
+	if len(r.rendering) > 0 {
+		parent = r.rendering[len(r.rendering)-1]
+	}
+	r.mount(child, parent)
+
+	depth := len(r.rendering)
+	r.rendering = append(r.rendering, child)
+	defer func() { r.rendering = r.rendering[:depth] }()
+	r.rendered = append(r.rendered, child)
+	return runtime.RenderComponent(child, r)
+}
+
+// mount gives c the renderer, its services and the context values of its ancestors, then
+// calls OnMount and OnParametersSet, once per component.
+func (r *staticRenderer) mount(c, parent runtime.Component) {
+	if r.mounted[c] {
+		return
+	}
+	r.mounted[c] = true
+	c.SetRenderer(r)
+	runtime.InjectServices(r, c)
+	runtime.InjectContext(c, parent)
+	runtime.CallOnMount(c)
+	runtime.CallOnParametersSet(c)
+}
+
+func (r *staticRenderer) ReRender() {}
+
+func (r *staticRenderer) ReRenderSlot(runtime.Component) error { return nil }
+
+func (r *staticRenderer) Navigate(string) error { return nil }
+
+func (r *staticRenderer) RenderAndWait(context.Context) error { return nil }
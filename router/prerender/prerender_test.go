@@ -0,0 +1,129 @@
+//go:build !wasm
+// +build !wasm
+
+package prerender
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	router "github.com/ForgeLogic/nojs-router"
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// siteLayout renders its slot inside <main> and provides the site name to it on mount.
+type siteLayout struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+}
+
+func (l *siteLayout) OnMount() { runtime.ProvideContext(l, "site", "Shop & Co") }
+
+func (l *siteLayout) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.NewVNode("main", nil, l.BodyContent, "")
+}
+
+func (l *siteLayout) SetBodyContent(content []*vdom.VNode) { l.BodyContent = content }
+
+// productPage renders the product of its route params, under the site name.
+type productPage struct {
+	runtime.ComponentBase
+	Site   any                  `nojs:"inject:site"`
+	Route  *router.RouteContext `nojs:"inject"`
+	broken bool
+}
+
+func (p *productPage) Render(r runtime.Renderer) *vdom.VNode {
+	if p.broken {
+		panic("no product")
+	}
+	return vdom.NewVNode("h1", nil, nil, p.Site.(string)+": <"+p.Route.Params["id"]+">")
+}
+
+// siteRoutes returns /, /products/{id} and /broken, each in a siteLayout.
+func siteRoutes() []router.Route {
+	layout := router.ComponentMetadata{TypeID: 1, Factory: func(map[string]string) runtime.Component { return &siteLayout{} }}
+	page := func(typeID uint32, broken bool) router.ComponentMetadata {
+		return router.ComponentMetadata{TypeID: typeID, Factory: func(map[string]string) runtime.Component {
+			return &productPage{broken: broken}
+		}}
+	}
+	return []router.Route{
+		{Path: "/", Chain: []router.ComponentMetadata{layout, page(2, false)}},
+		{Path: "/products/{id}", Chain: []router.ComponentMetadata{layout, page(2, false)}},
+		{Path: "/broken", Chain: []router.ComponentMetadata{layout, page(3, true)}},
+	}
+}
+
+// TestRenderPath_RendersChainWithContextAndParams verifies the page renders in its layout's
+// slot with the context the layout provides on mount and the params of the path, escaped.
+func TestRenderPath_RendersChainWithContextAndParams(t *testing.T) {
+	// Arrange
+	routes := siteRoutes()
+
+	// Act
+	html, err := RenderPath(routes, "/products/a&b")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RenderPath: %v", err)
+	}
+	if want := "<main><h1>Shop &amp; Co: &lt;a&amp;b&gt;</h1></main>"; html != want {
+		t.Errorf("expected %s, got %s", want, html)
+	}
+}
+
+// TestRenderPath_RenderPanicFails verifies a page whose Render panics fails instead of
+// pre-rendering the error panel.
+func TestRenderPath_RenderPanicFails(t *testing.T) {
+	// Act
+	_, err := RenderPath(siteRoutes(), "/broken")
+
+	// Assert
+	var p *runtime.ComponentPanic
+	if !errors.As(err, &p) {
+		t.Errorf("expected the Render panic, got %v", err)
+	}
+}
+
+// TestRun_WritesPagesIntoShell verifies every route without params and every listed path is
+// written into the mount element of the shell, and that the failures are reported together.
+func TestRun_WritesPagesIntoShell(t *testing.T) {
+	// Arrange
+	out := t.TempDir()
+	shell := `<html><body><div id="app"></div><div id="toasts"></div></body></html>`
+
+	// Act
+	err := Run(siteRoutes(), Options{OutDir: out, Shell: shell, Paths: []string{"/products/7"}})
+
+	// Assert
+	if err == nil || !strings.Contains(err.Error(), "/broken") {
+		t.Errorf("expected the failure of /broken, got %v", err)
+	}
+	home, _ := os.ReadFile(filepath.Join(out, "index.html"))
+	if want := `<div id="app"><main><h1>Shop &amp; Co: &lt;&gt;</h1></main></div><div id="toasts">`; !strings.Contains(string(home), want) {
+		t.Errorf("expected the home page in the mount element, got %s", home)
+	}
+	product, _ := os.ReadFile(filepath.Join(out, "products", "7", "index.html"))
+	if !strings.Contains(string(product), "Shop &amp; Co: &lt;7&gt;") {
+		t.Errorf("expected the product page, got %s", product)
+	}
+	if _, err := os.Stat(filepath.Join(out, "broken")); !os.IsNotExist(err) {
+		t.Errorf("expected no page for /broken, got %v", err)
+	}
+}
+
+// TestInjectMarkup_MissingMountElement verifies a shell without the mount element is an error.
+func TestInjectMarkup_MissingMountElement(t *testing.T) {
+	// Act
+	_, err := InjectMarkup(`<body><div id="application"></div></body>`, "app", "<p></p>")
+
+	// Assert
+	if err == nil {
+		t.Error("expected an error for a shell without the mount element")
+	}
+}
@@ -1,8 +1,8 @@
-//go:build js || wasm
-
 package router
 
 import (
+	"time"
+
 	"github.com/ForgeLogic/nojs/runtime"
 )
 
@@ -10,12 +10,107 @@ import (
 type Route struct {
 	Path  string
 	Chain []ComponentMetadata
+
+	// Name optionally identifies the route, e.g. "admin.users". Components read it from
+	// RouteContext, for instance to map routes to breadcrumb labels.
+	Name string
+
+	// Meta holds optional static data about the route (e.g., a page title), exposed
+	// through RouteContext.
+	Meta map[string]string
+
+	// Validate optionally checks the raw path params before any factory in the
+	// chain runs. A non-nil error fails the navigation with ErrRouteNotFound, so a
+	// malformed param (e.g. /blog/abc for an int year) never reaches a component
+	// as a zero value. Typed route helpers generated by the compiler provide one.
+	Validate ParamsValidator
+
+	// Transition optionally makes an AppShell cross-fade from the page on screen to the
+	// page of this route when navigating to it. Without one, the page is swapped at once.
+	Transition *Transition
+
+	// ScrollBehavior is where the window scrolls once the route has rendered; ScrollRestore
+	// when unset.
+	ScrollBehavior ScrollBehavior
+}
+
+// ScrollBehavior selects how a primary engine scrolls the window after navigating to a
+// route. Whatever the behavior, the offset of the entry left is saved in its history state.
+type ScrollBehavior int
+
+const (
+	// ScrollRestore scrolls back to the saved offset when returning to an entry (back/forward
+	// or a reload) and otherwise to the element the URL fragment names, e.g. id "install"
+	// for /docs#install, or to the top.
+	ScrollRestore ScrollBehavior = iota
+	// ScrollTop always scrolls to the fragment's element or to the top, even on back/forward.
+	ScrollTop
+	// ScrollPreserve leaves the window where it is, e.g. for tabs switched by route.
+	ScrollPreserve
+)
+
+// Transition configures the cross-fade an AppShell plays when it swaps in the page of a
+// route: the outgoing page stays in the layout's slot next to the incoming one for
+// Duration, their root elements carrying LeaveClass and EnterClass, and is then removed.
+// The fading itself is left to CSS animations on the two classes, e.g.
+//
+//	.nojs-enter { animation: fade-in 200ms; }
+//	.nojs-leave { animation: fade-out 200ms forwards; position: absolute; }
+//
+// Without an AppShell, the page's root element is given a vdom.Transition with the two
+// classes instead: EnterClass is removed on the frame after the page is inserted, so it
+// suits CSS transitions rather than animations, and the outgoing page is removed when its
+// transition ends, or after Duration at most.
+type Transition struct {
+	Duration   time.Duration
+	EnterClass string // Class of the incoming page while fading; "nojs-enter" when empty
+	LeaveClass string // Class of the outgoing page while fading; "nojs-leave" when empty
+}
+
+// enterClass returns the class of the incoming page, or "" when t is nil.
+func (t *Transition) enterClass() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.EnterClass == "":
+		return "nojs-enter"
+	}
+	return t.EnterClass
+}
+
+// leaveClass returns the class of the outgoing page, or "" when t is nil.
+func (t *Transition) leaveClass() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.LeaveClass == "":
+		return "nojs-leave"
+	}
+	return t.LeaveClass
 }
 
-// ComponentMetadata holds the factory and compile-time type ID for a component.
+// ParamsValidator checks the path params extracted for a matched route.
+type ParamsValidator func(params map[string]string) error
+
+// ComponentMetadata holds the factory and compile-time type ID for a component. Exactly one
+// of Factory and AsyncFactory is set.
 type ComponentMetadata struct {
 	Factory ComponentFactory
 	TypeID  uint32
+
+	// AsyncFactory creates the component asynchronously, e.g. after fetching the data it is
+	// created with. Until it delivers, the engine shows the pending component in its place
+	// (see Engine.SetPendingComponent) and the components after it in the chain wait too.
+	AsyncFactory AsyncComponentFactory
+}
+
+// ParamsReceiver is implemented by route components that keep path params. A navigation
+// that only changes param values keeps the layouts before the page, e.g. the user layout
+// of /users/{id}/posts/{postId} when going from /users/1/posts/7 to /users/2/posts/7, and
+// passes them the new params through SetParams before they re-render. Recreated
+// components get the params from their factory instead.
+type ParamsReceiver interface {
+	SetParams(params map[string]string)
 }
 
 // ComponentFactory creates a new instance of a component.
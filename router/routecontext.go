@@ -0,0 +1,89 @@
+package router
+
+import "sync"
+
+// RouteContext describes the route the engine last navigated to. The engine provides it to
+// the renderer for injection and updates it in place on every navigation, so any component
+// can read the current route without props being threaded through its layouts:
+//
+//	type Breadcrumbs struct {
+//	    runtime.ComponentBase
+//	    Route *router.RouteContext `nojs:"inject"`
+//	}
+//
+// Injected components are subscribed to changes and re-render when a navigation does not
+// already re-render them (e.g., when they sit in a layout preserved by the pivot).
+type RouteContext struct {
+	Name    string            // Name of the matched Route ("" if the route is unnamed)
+	Pattern string            // Route pattern, e.g. "/admin/users/{id}"
+	Path    string            // App-relative path, e.g. "/admin/users/42"
+	Params  map[string]string // Path params keyed by name
+	Query   map[string]string // Query string values, first value per key
+	Meta    map[string]string // Meta of the matched Route
+
+	// Transition of the matched Route, nil when it has none
+	Transition *Transition
+
+	// History is the state bag of the current browser history entry. It survives a full
+	// page reload and comes back with the entry on back/forward navigation.
+	History *HistoryState
+
+	mu          sync.Mutex
+	subscribers []routeSubscriber
+	nextID      int
+}
+
+// routeSubscriber is a callback registered through RouteContext.Subscribe.
+type routeSubscriber struct {
+	id int
+	fn func()
+}
+
+// Param returns the path param name, or "" if the current route has none by that name.
+func (rc *RouteContext) Param(name string) string {
+	return rc.Params[name]
+}
+
+// Subscribe registers fn to run after every navigation and returns a function that
+// removes it. It implements runtime.Notifier.
+func (rc *RouteContext) Subscribe(fn func()) func() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.nextID++
+	id := rc.nextID
+	rc.subscribers = append(rc.subscribers, routeSubscriber{id: id, fn: fn})
+
+	return func() {
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		for i, sub := range rc.subscribers {
+			if sub.id == id {
+				rc.subscribers = append(rc.subscribers[:i], rc.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// set replaces the route match info without notifying subscribers.
+func (rc *RouteContext) set(name, pattern, path string, params, query, meta map[string]string, transition *Transition) {
+	rc.Name = name
+	rc.Pattern = pattern
+	rc.Path = path
+	rc.Params = params
+	rc.Query = query
+	rc.Meta = meta
+	rc.Transition = transition
+}
+
+// notify runs every subscriber in subscription order. Subscribers may unsubscribe while
+// being notified.
+func (rc *RouteContext) notify() {
+	rc.mu.Lock()
+	subscribers := append([]routeSubscriber(nil), rc.subscribers...)
+	rc.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.fn()
+	}
+}
@@ -0,0 +1,209 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+	"github.com/ForgeLogic/nojs/vdom"
+)
+
+// routeTestRenderer renders a root component, caches children by key like the runtime
+// renderer, injects them on first render and counts re-render requests. Like the runtime
+// renderer, it renders only the layout for ReRenderSlot and only the child for
+// ReRenderComponent.
+type routeTestRenderer struct {
+	root        runtime.Component
+	services    *runtime.Services
+	children    map[string]runtime.Component
+	rendered    map[string]*vdom.VNode // Last VDOM of each child, by key
+	reRenders   int
+	slotParents []runtime.Component // Arguments of ReRenderSlot, in call order
+	vdom        *vdom.VNode
+}
+
+func newRouteTestRenderer(root runtime.Component) *routeTestRenderer {
+	r := &routeTestRenderer{
+		root:     root,
+		services: runtime.NewServices(),
+		children: make(map[string]runtime.Component),
+		rendered: make(map[string]*vdom.VNode),
+	}
+	root.SetRenderer(r)
+	return r
+}
+
+func (r *routeTestRenderer) Services() *runtime.Services { return r.services }
+
+func (r *routeTestRenderer) RenderChild(key string, child runtime.Component) *vdom.VNode {
+	instance, exists := r.children[key]
+	if !exists {
+		instance = child
+		r.children[key] = instance
+		r.services.Inject(instance)
+	}
+	instance.SetRenderer(r)
+	r.rendered[key] = instance.Render(r)
+	return r.rendered[key]
+}
+
+func (r *routeTestRenderer) ReRender() {
+	r.reRenders++
+	r.vdom = r.root.Render(r)
+}
+
+func (r *routeTestRenderer) ReRenderSlot(slotParent runtime.Component) error {
+	r.reRenders++
+	r.slotParents = append(r.slotParents, slotParent)
+	slotParent.Render(r)
+	return nil
+}
+
+func (r *routeTestRenderer) ReRenderComponent(b *runtime.ComponentBase) bool {
+	for key, instance := range r.children {
+		if runtime.BaseOf(instance) == b {
+			r.reRenders++
+			r.rendered[key] = instance.Render(r)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *routeTestRenderer) Navigate(path string) error { return nil }
+func (r *routeTestRenderer) RenderAndWait(ctx context.Context) error {
+	r.ReRender()
+	return nil
+}
+
+// adminLayout is the top layout of the admin routes. It renders breadcrumbs above its slot,
+// receives no route information itself and counts its renders.
+type adminLayout struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+	renders     int
+}
+
+func (l *adminLayout) Render(r runtime.Renderer) *vdom.VNode {
+	l.renders++
+	return vdom.Div(nil, r.RenderChild("breadcrumbs", &breadcrumbs{}), vdom.Div(nil, l.BodyContent...))
+}
+
+func (l *adminLayout) SetBodyContent(content []*vdom.VNode) { l.BodyContent = content }
+
+// sectionLayout is a layout nested in adminLayout that only renders its slot.
+type sectionLayout struct {
+	runtime.ComponentBase
+	BodyContent []*vdom.VNode
+}
+
+func (l *sectionLayout) Render(r runtime.Renderer) *vdom.VNode {
+	return vdom.Div(nil, l.BodyContent...)
+}
+
+func (l *sectionLayout) SetBodyContent(content []*vdom.VNode) { l.BodyContent = content }
+
+// breadcrumbs maps the dotted route name to labels ("admin.users" -> Admin › Users).
+type breadcrumbs struct {
+	runtime.ComponentBase
+	Route *RouteContext `nojs:"inject"`
+}
+
+var crumbLabels = map[string]string{"admin": "Admin", "users": "Users"}
+
+func (b *breadcrumbs) Render(r runtime.Renderer) *vdom.VNode {
+	var crumbs []*vdom.VNode
+	for _, part := range strings.Split(b.Route.Name, ".") {
+		crumbs = append(crumbs, vdom.NewVNode("li", nil, nil, crumbLabels[part]))
+	}
+	return vdom.NewVNode("ul", map[string]any{"data-pattern": b.Route.Pattern}, crumbs, "")
+}
+
+func trailOf(list *vdom.VNode) string {
+	var labels []string
+	for _, li := range list.Children {
+		labels = append(labels, li.Content)
+	}
+	return strings.Join(labels, " › ")
+}
+
+// TestRouteContext_BreadcrumbsUnderPreservedLayout_FollowNavigation verifies that breadcrumbs
+// in a layout kept by a navigation from /admin to /admin/users read the injected RouteContext
+// and are re-rendered on their own, without the layout rendering again.
+func TestRouteContext_BreadcrumbsUnderPreservedLayout_FollowNavigation(t *testing.T) {
+	// Arrange
+	layout := &adminLayout{}
+	admin := ComponentMetadata{TypeID: 1, Factory: func(map[string]string) runtime.Component { return layout }}
+	section := ComponentMetadata{TypeID: 2, Factory: func(map[string]string) runtime.Component { return &sectionLayout{} }}
+	engine := newTestEngine(
+		Route{Path: "/admin", Name: "admin", Chain: []ComponentMetadata{admin, section, pageMeta(3, "dashboard")}},
+		Route{Path: "/admin/users", Name: "admin.users", Chain: []ComponentMetadata{admin, section, pageMeta(4, "users")}},
+	)
+	renderer := engine.renderer.(*routeTestRenderer)
+	if err := engine.Navigate("/admin"); err != nil {
+		t.Fatalf("Navigate(/admin): %v", err)
+	}
+	renderer.root = layout // The app renders the top layout of the chain as its root
+	renderer.ReRender()
+	crumbs := renderer.children["breadcrumbs"].(*breadcrumbs)
+	if crumbs.Route != engine.routeCtx {
+		t.Fatal("Expected the RouteContext to be injected into the breadcrumbs")
+	}
+	if got := trailOf(renderer.rendered["breadcrumbs"]); got != "Admin" {
+		t.Fatalf("Expected trail 'Admin', got %q", got)
+	}
+	layoutRenders := layout.renders
+
+	// Act
+	if err := engine.Navigate("/admin/users?page=2"); err != nil {
+		t.Fatalf("Navigate(/admin/users): %v", err)
+	}
+
+	// Assert
+	if layout.renders != layoutRenders {
+		t.Errorf("Expected the preserved layout not to render again, got %d more renders", layout.renders-layoutRenders)
+	}
+	if engine.liveInstances[0] != layout || renderer.children["breadcrumbs"] != crumbs {
+		t.Error("Expected the layout and breadcrumbs instances to be preserved")
+	}
+	if got := trailOf(renderer.rendered["breadcrumbs"]); got != "Admin › Users" {
+		t.Errorf("Expected trail 'Admin › Users', got %q", got)
+	}
+	if got := renderer.rendered["breadcrumbs"].Attributes["data-pattern"]; got != "/admin/users" {
+		t.Errorf("Expected pattern '/admin/users', got %v", got)
+	}
+	if crumbs.Route.Query["page"] != "2" {
+		t.Errorf("Expected query value '2', got %q", crumbs.Route.Query["page"])
+	}
+}
+
+// TestRouteContext_Unsubscribe_StopsNotifications verifies subscription removal, also
+// from inside a notification.
+func TestRouteContext_Unsubscribe_StopsNotifications(t *testing.T) {
+	// Arrange
+	routeCtx := &RouteContext{}
+	calls := 0
+	var unsubscribe func()
+	unsubscribe = routeCtx.Subscribe(func() {
+		calls++
+		unsubscribe()
+	})
+	other := 0
+	routeCtx.Subscribe(func() { other++ })
+
+	// Act
+	routeCtx.notify()
+	routeCtx.notify()
+
+	// Assert
+	if calls != 1 {
+		t.Errorf("Expected the self-removing subscriber to run once, got %d", calls)
+	}
+	if other != 2 {
+		t.Errorf("Expected the remaining subscriber to run twice, got %d", other)
+	}
+}
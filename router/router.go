@@ -1,43 +1,135 @@
-//go:build js || wasm
-
 package router
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"sync"
-	"syscall/js"
 
 	"github.com/ForgeLogic/nojs/console"
+	"github.com/ForgeLogic/nojs/head"
 	"github.com/ForgeLogic/nojs/runtime"
 	"github.com/ForgeLogic/nojs/vdom"
 )
 
+// ErrRouteNotFound is returned (wrapped) by Navigate when no route matches the path,
+// or when the matched route's Validate hook rejects the path params.
+var ErrRouteNotFound = errors.New("no route for path")
+
+// ErrAlreadyCurrent is returned (wrapped) by Navigate when the path and query are the ones
+// already shown; nothing is re-created or pushed to history.
+var ErrAlreadyCurrent = errors.New("already at path")
+
+// ErrAlreadyNavigating is returned (wrapped) by Navigate when the call yields to another
+// navigation in flight: one to the same path and query (a double-click on a link), or a
+// later call that supersedes it before it could run.
+var ErrAlreadyNavigating = errors.New("already navigating")
+
+// browserHistory is the part of the browser's history, location and scrolling the engine
+// drives.
+type browserHistory interface {
+	pathname() string                // location.pathname
+	search() string                  // location.search, including the leading "?"
+	hash() string                    // location.hash, including the leading "#"
+	pushState(browserPath string)    // history.pushState, with no state
+	replaceState(browserPath string) // history.replaceState of the URL; the entry keeps its state
+	state() string                   // The engine's part of history.state, "" for none
+	setState(state string)           // history.replaceState of the state; the entry keeps its URL
+	onPopState(fn func()) func()     // Listens for popstate; returns the removal function
+	onPageHide(fn func()) func()     // Listens for pagehide; returns the removal function
+	scrollY() float64                // window.scrollY
+	scrollTo(y float64)              // window.scrollTo(0, y)
+	scrollIntoView(id string) bool   // Scrolls the element with id into view; false when there is none
+	manualScrollRestoration()        // history.scrollRestoration = "manual": the engine restores offsets
+}
+
+// historyUpdate is what a navigation does to browser history.
+type historyUpdate int
+
+const (
+	historyPush    historyUpdate = iota // Add an entry (Navigate)
+	historyAdopt                        // Take over the entry the page was loaded with (Start)
+	historyNone                         // The browser is already at the entry (popstate, followed paths, re-navigation in place)
+	historyReplace                      // Like historyNone, but a guard redirected: the entry takes the new URL
+)
+
 // Engine manages routing with the app shell pattern and pivot-based layout reuse.
 // It preserves layout instances across navigations when the layout chain matches.
 type Engine struct {
-	mu               sync.Mutex
-	basePath         string
-	currentPath      string
-	currentRoute     *Route
-	currentParams    map[string]string
-	activeChain      []ComponentMetadata
-	liveInstances    []runtime.Component // Parallel to activeChain; instances are reused
-	pivotPoint       int                 // First index where chain differs between routes
-	routes           map[string]*Route
-	renderer         runtime.Renderer
-	onRouteChange    func(chain []runtime.Component, key string)
-	popstateListener js.Func
+	mu             sync.Mutex
+	basePath       string
+	currentPath    string
+	currentQuery   string // Raw query of the current path, without "?"
+	previousPath   string // Path shown before the last navigation
+	currentRoute   *Route
+	currentParams  map[string]string
+	activeChain    []ComponentMetadata
+	liveInstances  []runtime.Component // Parallel to activeChain; instances are reused
+	pivotPoint     int                 // First index where chain differs between routes
+	pendingFactory ComponentFactory    // Shown in place of a component being created; see SetPendingComponent
+	awaiting       chan struct{}       // Closed when the route shown changes before its awaited component arrives
+	routes         map[string]*Route
+	renderer       runtime.Renderer
+	onRouteChange  func(chain []runtime.Component, key string)
+	history        browserHistory
+	removePopstate func() // Set by Start in primary mode
+	removePageHide func() // Set by Start in primary mode
+	entries        historyEntries
+	guard          navigationGuard
+	hooks          navigationHooks
+	migrateHash    bool       // Rewrite legacy /#/path URLs at Start; see SetMigrateHashURLs
+	settingsMu     sync.Mutex // Guards mode and app, which Navigate reads while another navigation holds mu
+	mode           Mode
+	app            *runtime.AppInstance // Set by AttachApp; required for ModePassive
+	log            console.Logger
+	routeCtx       *RouteContext // Match info of the current route, injected into components
 }
 
+// Mode controls whether an Engine drives browser history or follows another app's router.
+type Mode int
+
+const (
+	// ModePrimary owns browser history: it calls pushState, listens for popstate and, when
+	// attached to an app instance, broadcasts every path change to the other apps on the page.
+	// This is the default.
+	ModePrimary Mode = iota
+	// ModePassive never touches history. It follows the paths broadcast by the primary
+	// router and forwards its own Navigate calls to the primary. It requires AttachApp.
+	ModePassive
+)
+
 // NewEngine creates a new router engine.
 // The renderer can be set later via SetRenderer if needed.
 func NewEngine(renderer runtime.Renderer) *Engine {
-	return &Engine{
+	e := &Engine{
 		routes:        make(map[string]*Route),
 		renderer:      renderer,
 		basePath:      "",
 		liveInstances: make([]runtime.Component, 0, 4),
+		routeCtx:      &RouteContext{},
+		history:       newBrowserHistory(),
+		entries:       newHistoryEntries(),
+	}
+	e.routeCtx.History = e.entries.fresh()
+	e.provideServices()
+	return e
+}
+
+// RouteContext returns the match info of the current route. The same instance is updated
+// on every navigation and injected into component fields of type *RouteContext tagged
+// `nojs:"inject"`. The engine itself is injected into fields of type *Engine, e.g. for
+// links calling IsActive.
+func (e *Engine) RouteContext() *RouteContext {
+	return e.routeCtx
+}
+
+// provideServices registers the RouteContext and the engine for injection with the
+// renderer, if it supports injection.
+func (e *Engine) provideServices() {
+	if provider, ok := e.renderer.(runtime.ServiceProvider); ok && provider.Services() != nil {
+		provider.Services().Provide(e.routeCtx)
+		provider.Services().Provide(e)
 	}
 }
 
@@ -51,21 +143,137 @@ func (e *Engine) SetBasePath(path string) {
 	e.basePath = normalizeBasePath(path)
 }
 
+// SetMigrateHashURLs enables migration of bookmarks from a hash-mode router: when the app
+// is loaded at its root with a route in the hash (/#/admin/settings?tab=2), Start replaces
+// the URL with its path form (/admin/settings?tab=2) before the initial render. Hashes that
+// do not start with "#/" (fragment links such as #section) and hashes on any other path
+// are left alone. It only applies to primary engines and must be called before Start.
+func (e *Engine) SetMigrateHashURLs(migrate bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.migrateHash = migrate
+}
+
+// SetMode selects primary or passive routing. It must be called before AttachApp (nojs.Run).
+func (e *Engine) SetMode(mode Mode) {
+	e.settingsMu.Lock()
+	defer e.settingsMu.Unlock()
+	e.mode = mode
+}
+
+// settings returns the engine's mode and attached app instance.
+func (e *Engine) settings() (Mode, *runtime.AppInstance) {
+	e.settingsMu.Lock()
+	defer e.settingsMu.Unlock()
+	return e.mode, e.app
+}
+
+// AttachApp binds the engine to an app instance: log output is prefixed with the app
+// name, a primary engine claims browser history for the app and answers navigation
+// requests from passive engines, and all window listeners are removed when the app is
+// unmounted. nojs.Run calls it automatically.
+func (e *Engine) AttachApp(app *runtime.AppInstance) error {
+	e.settingsMu.Lock()
+	mode := e.mode
+	e.app = app
+	e.settingsMu.Unlock()
+
+	e.mu.Lock()
+	e.log = app.Logger()
+	e.mu.Unlock()
+
+	if mode == ModePrimary {
+		if err := app.ClaimHistory(); err != nil {
+			return fmt.Errorf("router: %w (set ModePassive on all but one router)", err)
+		}
+	}
+	app.OnUnmount(e.Cleanup)
+	return nil
+}
+
 // SetRenderer sets the renderer on the engine (used after engine creation).
 func (e *Engine) SetRenderer(renderer runtime.Renderer) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.renderer = renderer
+	e.provideServices()
 }
 
-// RegisterRoutes adds routes to the engine.
-// Routes are keyed by their Path for O(1) lookup.
+// RegisterRoutes adds routes to the engine, keyed by their Path. A path matching several
+// routes is shown with the most precise: a static route before one with params, and one
+// with params before a wildcard (see routePattern).
 func (e *Engine) RegisterRoutes(routes []Route) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	for i := range routes {
 		e.routes[routes[i].Path] = &routes[i]
 	}
 }
 
+// UpdateRoute swaps the component chain of the route registered under path, keeping its
+// Name, Meta and Validate, for instance to serve a redesigned layout behind a feature flag
+// without registering it under a second path. The route table changes immediately, but
+// nothing is re-created until the route is next navigated to; when path is the route on
+// screen and renavigate is set, the engine navigates to it again in place, without touching
+// history, so the new chain takes effect at once. The pivot is computed against the old
+// chain: instances whose TypeID matches are preserved and the rest are re-created.
+func (e *Engine) UpdateRoute(path string, chain []ComponentMetadata, renavigate bool) error {
+	e.mu.Lock()
+	existing, exists := e.routes[path]
+	if !exists {
+		e.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrRouteNotFound, path)
+	}
+	updated := *existing
+	updated.Chain = append([]ComponentMetadata(nil), chain...)
+	e.mu.Unlock()
+
+	return e.ReplaceRoute(updated, renavigate)
+}
+
+// ReplaceRoute replaces the route registered under route.Path as a whole (chain, Name, Meta
+// and Validate at once), with the same behavior as UpdateRoute.
+func (e *Engine) ReplaceRoute(route Route, renavigate bool) error {
+	if len(route.Chain) == 0 {
+		return fmt.Errorf("router: route %s has an empty chain", route.Path)
+	}
+
+	e.mu.Lock()
+	if _, exists := e.routes[route.Path]; !exists {
+		e.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrRouteNotFound, route.Path)
+	}
+	e.routes[route.Path] = &route
+	active := e.currentRoute != nil && e.currentRoute.Path == route.Path
+	currentPath := e.currentPath
+	if active {
+		// Navigating to the current path again must apply the new chain, not be a no-op
+		e.guard.forget()
+	}
+	e.mu.Unlock()
+
+	if !active || !renavigate {
+		return nil
+	}
+	e.log.Debug("[Engine.ReplaceRoute] Re-navigating in place to:", currentPath)
+	return e.navigate(currentPath, historyNone)
+}
+
+// RemoveRoute unregisters the route under path. A route on screen stays rendered until
+// the next navigation; navigating to it again then fails with ErrRouteNotFound.
+func (e *Engine) RemoveRoute(path string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, exists := e.routes[path]; !exists {
+		return fmt.Errorf("%w: %s", ErrRouteNotFound, path)
+	}
+	delete(e.routes, path)
+	if e.currentRoute != nil && e.currentRoute.Path == path {
+		e.guard.forget()
+	}
+	return nil
+}
+
 // SetRouteChangeCallback sets the callback invoked when navigation occurs.
 // The callback is passed the chain of component instances (from pivot onwards, including
 // sublayouts and the leaf page) and a unique key for reconciliation.
@@ -75,52 +283,143 @@ func (e *Engine) SetRouteChangeCallback(fn func(chain []runtime.Component, key s
 
 // Navigate changes the current route and triggers appropriate updates.
 // It uses the pivot algorithm to determine which layouts can be preserved.
-// If skipPushState is true, the URL won't be updated (used for popstate events).
+// A passive engine asks the primary router to navigate instead and updates once the
+// primary broadcasts the new path. It fails with ErrNavigationCancelled when a
+// BeforeNavigate guard cancels the navigation.
 func (e *Engine) Navigate(path string) error {
-	return e.navigateInternal(path, false)
+	return e.navigateTo(path, historyPush)
 }
 
-// navigateInternal handles the navigation logic with optional skipPushState flag.
-func (e *Engine) navigateInternal(path string, skipPushState bool) error {
+// navigateTo runs a Navigate call, or the initial navigation of Start.
+func (e *Engine) navigateTo(path string, update historyUpdate) error {
+	mode, app := e.settings()
+	if mode == ModePassive {
+		e.log.Debug("[Engine.Navigate] Passive router, requesting navigation to:", path)
+		app.RequestNavigation(e.toBrowserPath(e.toRoutePath(path)))
+		return nil
+	}
+
+	routePath, rawQuery, _ := splitURL(path)
+	ticket, err := e.guard.begin(navigationTarget(e.toRoutePath(routePath), rawQuery), false)
+	if err != nil {
+		e.log.Debug("[Engine.Navigate] Ignoring navigation to", path+":", err.Error())
+		return fmt.Errorf("%w: %s", err, path)
+	}
+	defer e.guard.end()
+
+	target, redirected, err := e.intercept(path)
+	if err != nil {
+		return err
+	}
+	if redirected {
+		targetPath, targetQuery, _ := splitURL(target)
+		if e.guard.isCurrent(navigationTarget(e.toRoutePath(targetPath), targetQuery)) {
+			return fmt.Errorf("%w: %s (redirected from %s)", ErrAlreadyCurrent, target, path)
+		}
+		if update == historyAdopt {
+			// The address bar shows the path the guard redirected from
+			update = historyReplace
+		}
+	}
+
+	if err := e.navigateInternal(target, update, ticket); err != nil {
+		return err
+	}
+	e.afterNavigate()
+	e.broadcastCurrentPath()
+	return nil
+}
+
+// navigate runs a navigation that is not a Navigate call (popstate, a followed path, a
+// re-navigation in place): it always runs, superseding Navigate calls still waiting for
+// the engine. update is historyNone, or historyReplace for a redirected popstate.
+func (e *Engine) navigate(path string, update historyUpdate) error {
+	routePath, rawQuery, _ := splitURL(path)
+	ticket, _ := e.guard.begin(navigationTarget(e.toRoutePath(routePath), rawQuery), true)
+	defer e.guard.end()
+	if err := e.navigateInternal(path, update, ticket); err != nil {
+		return err
+	}
+	e.afterNavigate()
+	return nil
+}
+
+// broadcastCurrentPath announces the current browser path to passive routers of other apps.
+// It must be called without holding e.mu, since listeners run synchronously.
+func (e *Engine) broadcastCurrentPath() {
+	_, app := e.settings()
+	e.mu.Lock()
+	browserPath := e.toBrowserPath(e.currentPath)
+	e.mu.Unlock()
+
+	if app != nil {
+		app.BroadcastPath(browserPath)
+	}
+}
+
+// followPath handles a path broadcast by the primary router of another app.
+func (e *Engine) followPath(source, browserPath string) {
+	routePath := e.toRoutePath(browserPath)
+	e.log.Debug("[Engine] Following path from app", source+":", routePath)
+	if err := e.navigate(routePath, historyNone); err != nil {
+		// Passive apps often only render a subset of the routes; keep the current view
+		e.log.Warn("[Engine] Passive router has no view for path:", routePath)
+	}
+}
+
+// navigateInternal handles the navigation logic; update is what it does to browser history.
+// ticket identifies the call in the navigation guard; a call superseded while it waited
+// for the engine returns ErrAlreadyNavigating without changing anything.
+func (e *Engine) navigateInternal(path string, update historyUpdate, ticket int) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.guard.superseded(ticket) {
+		e.log.Debug("[Engine.Navigate] Superseded by a later navigation:", path)
+		return fmt.Errorf("%w: %s superseded by a later navigation", ErrAlreadyNavigating, path)
+	}
+
+	path, fragment, hasFragment := strings.Cut(path, "#")
+	path, rawQuery, hasQuery := strings.Cut(path, "?")
+	if update == historyNone {
+		// popstate and followed paths carry no query or fragment; take them from the address bar
+		if !hasQuery {
+			rawQuery = strings.TrimPrefix(e.history.search(), "?")
+		}
+		if !hasFragment {
+			fragment = strings.TrimPrefix(e.history.hash(), "#")
+		}
+	}
 	path = e.toRoutePath(path)
 
-	console.Log("[Engine.Navigate] Called with path:", path)
+	e.log.Debug("[Engine.Navigate] Called with path:", path)
 
 	if path == "" {
-		console.Warn("[Engine.Navigate] The path is empty string")
+		e.log.Warn("[Engine.Navigate] The path is empty string")
 	}
 
-	console.Log("[Engine.Navigate] Current path:", e.currentPath)
+	e.log.Debug("[Engine.Navigate] Current path:", e.currentPath)
 
-	targetRoute := e.findMatchingRoute(path)
-	if targetRoute == nil {
-		console.Error("[Engine.Navigate] No route found for path:", path)
-		return fmt.Errorf("no route for path: %s", path)
+	// Malformed params are rejected before touching history or instantiating anything
+	targetRoute, params, err := e.resolveRoute(path)
+	if err != nil {
+		e.log.Error("[Engine.Navigate] No route found for path:", path, err.Error())
+		return err
 	}
 
-	console.Log("[Engine.Navigate] Route found")
+	e.log.Debug("[Engine.Navigate] Route found:", targetRoute.Path)
+	e.log.Debugf("[Engine.Navigate] Extracted params: %v", params)
 
-	// Update browser history using pushState (unless this is a popstate navigation)
-	if !skipPushState {
-		console.Log("[Engine.Navigate] Updating URL with pushState")
-		history := js.Global().Get("history")
-		history.Call("pushState", nil, "", e.toBrowserPath(path))
-		console.Log("[Engine.Navigate] URL updated, current location:", js.Global().Get("location").Get("pathname").String())
-	} else {
-		console.Log("[Engine.Navigate] Skipping pushState (popstate event)")
-	}
+	// The components leaving store their state in the bag of the entry they belong to
+	e.saveHistoryState()
+
+	// Publish the new match before rendering so every component rendered below reads it
+	e.routeCtx.set(targetRoute.Name, targetRoute.Path, path, params, parseQuery(rawQuery), targetRoute.Meta, targetRoute.Transition)
 
 	// Calculate pivot point: first index where TypeID differs
 	pivot := e.calculatePivot(targetRoute.Chain)
 
-	console.Log("[Engine.Navigate] Pivot point (TypeID-based):", pivot, "Chain length:", len(targetRoute.Chain))
-
-	// Extract URL parameters from route pattern
-	params := e.extractParams(targetRoute.Path, path)
-	console.Log("[Engine.Navigate] Extracted params:", fmt.Sprintf("%v", params))
+	e.log.Debug("[Engine.Navigate] Pivot point (TypeID-based):", pivot, "Chain length:", len(targetRoute.Chain))
 
 	// If route parameters changed, force re-creation of the leaf component so that
 	// the factory receives the new params and OnParametersSet is triggered.
@@ -131,89 +430,222 @@ func (e *Engine) navigateInternal(path string, skipPushState bool) error {
 		if pivot > leafIdx {
 			pivot = leafIdx
 		}
-		console.Log("[Engine.Navigate] Params changed — clamping pivot to:", pivot)
+		e.log.Debug("[Engine.Navigate] Params changed — clamping pivot to:", pivot)
 	}
 
 	// Destroy volatile (new) component instances from pivot onwards
 	for i := pivot; i < len(e.liveInstances); i++ {
-		instance := e.liveInstances[i]
-
-		// Clear slot parent reference to break circular references
-		if slotTracking, ok := interface{}(instance).(interface{ SetSlotParent(runtime.Component) }); ok {
-			slotTracking.SetSlotParent(nil)
-		}
+		e.destroyInstance(e.liveInstances[i])
 	}
 
+	// Update browser history, and switch to the bag of the entry shown
+	e.updateHistory(path, rawQuery, fragment, update)
+
 	// Instantiate new chain segment (from pivot onwards)
 	newInstances := make([]runtime.Component, len(targetRoute.Chain))
 
 	// Copy stable instances (before pivot)
 	copy(newInstances[:pivot], e.liveInstances[:pivot])
 
-	// Create new instances from pivot onwards
-	for i := pivot; i < len(targetRoute.Chain); i++ {
-		instance := targetRoute.Chain[i].Factory(params)
+	// Create new instances from pivot onwards, up to the first one created asynchronously
+	newInstances, resolved, delivered := e.instantiateChain(targetRoute.Chain, params, newInstances, pivot)
+	if err := e.writeHistoryEntry(); err != nil {
+		e.log.Warn("[Engine.Navigate] Failed to store history state:", err.Error())
+	}
+
+	// Preserved components that keep params learn the new values; the scoped update below
+	// then starts at the topmost of them instead of the page's direct parent
+	slotParent := pivot - 1
+	if !mapsEqual(e.currentParams, params) {
+		for i := 0; i < pivot; i++ {
+			if receiver, ok := newInstances[i].(ParamsReceiver); ok {
+				receiver.SetParams(params)
+				if e.onRouteChange == nil {
+					runtime.CallOnParametersSet(newInstances[i])
+				}
+				slotParent = min(slotParent, i)
+			}
+		}
+	}
 
-		// Inject renderer so component can call StateHasChanged() and Navigate()
-		instance.SetRenderer(e.renderer)
+	e.showChain(newInstances, fmt.Sprintf("%s:%d", path, pivot), pivot, slotParent)
+	e.commit(path, rawQuery, targetRoute, params, newInstances, pivot, resolved)
+	e.applyHead()
 
-		newInstances[i] = instance
+	// A scoped update leaves the layouts above the pivot as rendered; let components that
+	// read the RouteContext re-render. (The AppShell re-renders from the root.)
+	if e.onRouteChange == nil && pivot > 0 {
+		e.routeCtx.notify()
 	}
+	e.scroll(targetRoute.ScrollBehavior, update, fragment)
 
-	// Link chain: inject each child into parent's BodyContent slot
-	// Skip this if using AppShell pattern (onRouteChange callback set) to prevent double-rendering
-	if e.onRouteChange == nil {
-		for i := 0; i < len(newInstances)-1; i++ {
-			parent := newInstances[i]
-			child := newInstances[i+1]
-
-			// Render child to VDOM and inject into parent's slot
-			childVNode := child.Render(e.renderer)
-			if childVNode != nil {
-				// Use duck typing to set slot content - any layout with SetBodyContent method
-				if layout, ok := parent.(interface{ SetBodyContent([]*vdom.VNode) }); ok {
-					layout.SetBodyContent([]*vdom.VNode{childVNode})
-				}
-			}
+	if delivered != nil {
+		e.awaitComponent(path, targetRoute, params, resolved, delivered)
+	}
+	return nil
+}
 
-			// Mark child as being in parent's slot (for scoped re-renders)
-			if slotTracking, ok := interface{}(child).(interface{ SetSlotParent(runtime.Component) }); ok {
-				slotTracking.SetSlotParent(parent)
+// showChain renders a new chain of instances: through the route change callback when an
+// AppShell is used, or else by linking each instance into its parent's slot, the one at pivot
+// marked with the route's transition, and re-rendering from the instance at slotParent (the
+// root when negative). The caller must hold e.mu.
+func (e *Engine) showChain(instances []runtime.Component, key string, pivot, slotParent int) {
+	// Notify route change callback to update AppShell state. The shell links the chain
+	// itself, so it is not linked here to prevent double-rendering.
+	if e.onRouteChange != nil {
+		e.log.Debug("[Engine.Navigate] Calling onRouteChange with", len(instances), "components, key:", key)
+		e.onRouteChange(instances, key)
+		e.log.Debug("[Engine.Navigate] AppShell will handle rendering via StateHasChanged")
+		return
+	}
+
+	// Link chain: inject each child into parent's BodyContent slot
+	for i := 0; i < len(instances)-1; i++ {
+		parent := instances[i]
+		child := instances[i+1]
+
+		// Render child to VDOM and inject into parent's slot, with the context parent provides
+		runtime.SetContextParent(child, parent)
+		runtime.InjectContext(child, parent)
+		childVNode := child.Render(e.renderer)
+		if i+1 == pivot {
+			e.markPage(childVNode, child)
+		}
+		if childVNode != nil {
+			// Use duck typing to set slot content - any layout with SetBodyContent method
+			if layout, ok := parent.(interface{ SetBodyContent([]*vdom.VNode) }); ok {
+				layout.SetBodyContent([]*vdom.VNode{childVNode})
 			}
 		}
-	}
 
-	// Notify route change callback to update AppShell state.
-	if e.onRouteChange != nil {
-		key := fmt.Sprintf("%s:%d", path, pivot)
-		console.Log("[Engine.Navigate] Calling onRouteChange with", len(newInstances), "components, key:", key)
-		e.onRouteChange(newInstances, key)
-		console.Log("[Engine.Navigate] AppShell will handle rendering via StateHasChanged")
-
-		e.currentPath = path
-		e.currentRoute = targetRoute
-		e.currentParams = params
-		e.activeChain = targetRoute.Chain
-		e.liveInstances = newInstances
-		e.pivotPoint = pivot
-		return nil
+		// Mark child as being in parent's slot (for scoped re-renders)
+		if slotTracking, ok := interface{}(child).(interface{ SetSlotParent(runtime.Component) }); ok {
+			slotTracking.SetSlotParent(parent)
+		}
 	}
 
 	// Fallback: if no callback (non-AppShell apps), do scoped update
-	if pivot > 0 {
-		e.renderer.ReRenderSlot(newInstances[pivot-1])
+	if slotParent >= 0 {
+		e.renderer.ReRenderSlot(instances[slotParent])
 	} else {
 		e.renderer.ReRender()
 	}
+}
+
+// markPage gives page, the root VNode of the component swapped into its layout's slot, the
+// Transition of the route shown, so the patcher replaces the outgoing page with it instead of
+// patching one into the other, and animates both: the incoming page gets EnterClass for a
+// frame and the outgoing one keeps LeaveClass until its CSS transition ends, or for Duration
+// at most. An AppShell plays its own cross-fade instead. The caller must hold e.mu.
+func (e *Engine) markPage(page *vdom.VNode, instance runtime.Component) {
+	t := e.routeCtx.Transition
+	if page == nil || t == nil {
+		return
+	}
+	if page.ComponentKey == "" {
+		page.ComponentKey = fmt.Sprintf("page-%p", instance)
+	}
+	page.Transition = &vdom.Transition{Enter: t.enterClass(), Leave: t.leaveClass(), Timeout: t.Duration}
+}
 
+// destroyInstance discards a route component leaving the screen. Without an AppShell it is
+// unmounted here, as prepareInstance mounted it. The caller must hold e.mu.
+func (e *Engine) destroyInstance(instance runtime.Component) {
+	if e.onRouteChange == nil {
+		runtime.CallOnUnmount(instance)
+	}
+	// Clear slot parent reference to break circular references
+	if slotTracking, ok := interface{}(instance).(interface{ SetSlotParent(runtime.Component) }); ok {
+		slotTracking.SetSlotParent(nil)
+	}
+	runtime.ReleaseServices(e.renderer, instance)
+	runtime.Destroy(instance)
+}
+
+// updateHistory updates browser history for a navigation to path and makes the bag of the
+// entry shown current. A new entry starts with an empty bag, after the one left has been
+// stored in it; the entry the page was loaded with and entries reached by back/forward bring
+// back their own. The caller must hold e.mu.
+func (e *Engine) updateHistory(path, rawQuery, fragment string, update historyUpdate) {
+	mode, _ := e.settings()
+	browserPath := e.toBrowserPath(path)
+	if rawQuery != "" {
+		browserPath += "?" + rawQuery
+	}
+	if fragment != "" {
+		browserPath += "#" + fragment
+	}
+
+	switch {
+	case update == historyPush:
+		e.log.Debug("[Engine.Navigate] Updating URL with pushState")
+		if err := e.writeHistoryEntry(); err != nil {
+			e.log.Warn("[Engine.Navigate] Failed to store history state:", err.Error())
+		}
+		e.history.pushState(browserPath)
+		e.routeCtx.History = e.entries.fresh()
+		e.log.Debug("[Engine.Navigate] URL updated, current location:", e.history.pathname())
+	case update == historyReplace:
+		// A guard redirected a navigation the browser had already made: the entry the
+		// browser is at takes the new URL and starts with an empty bag
+		e.history.replaceState(browserPath)
+		e.routeCtx.History = e.entries.fresh()
+	case update == historyAdopt:
+		// Start: the address bar already shows the path
+		e.routeCtx.History = e.entries.forEntry(e.history.state())
+	case mode == ModePrimary:
+		e.log.Debug("[Engine.Navigate] Skipping pushState (popstate event)")
+		e.routeCtx.History = e.entries.forEntry(e.history.state())
+	}
+}
+
+// commit records a completed navigation, whose instances resolve the first resolved
+// components of the route's chain; a component still awaited from the previous route is
+// abandoned. The caller must hold e.mu.
+func (e *Engine) commit(path, rawQuery string, route *Route, params map[string]string, instances []runtime.Component, pivot, resolved int) {
+	e.abandonAwaited()
+	e.previousPath = e.currentPath
 	e.currentPath = path
-	e.currentRoute = targetRoute
+	e.currentQuery = rawQuery
+	e.currentRoute = route
 	e.currentParams = params
-	e.activeChain = targetRoute.Chain
-	e.liveInstances = newInstances
+	e.activeChain = route.Chain[:resolved] // Awaited components are not preserved by the pivot
+	e.liveInstances = instances
 	e.pivotPoint = pivot
+	e.guard.commit(navigationTarget(path, rawQuery))
+	assertUniqueInstances(instances)
+}
 
-	return nil
+// applyHead sets the document's title and meta tags from the components shown (see
+// head.HeadProvider). Passive engines leave them to the primary one. The caller must hold
+// e.mu.
+func (e *Engine) applyHead() {
+	if mode, _ := e.settings(); mode == ModePrimary {
+		head.Apply(e.liveInstances)
+	}
+}
+
+// splitURL splits an app-relative URL into its path, raw query and fragment, each without
+// its separator.
+func splitURL(url string) (path, rawQuery, fragment string) {
+	url, fragment, _ = strings.Cut(url, "#")
+	path, rawQuery, _ = strings.Cut(url, "?")
+	return path, rawQuery, fragment
+}
+
+// parseQuery decodes a raw query string into its first value per key.
+func parseQuery(rawQuery string) map[string]string {
+	query := make(map[string]string)
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return query
+	}
+	for key, vals := range values {
+		if len(vals) > 0 {
+			query[key] = vals[0]
+		}
+	}
+	return query
 }
 
 // mapsEqual returns true if two string maps have identical keys and values.
@@ -243,80 +675,33 @@ func (e *Engine) calculatePivot(targetChain []ComponentMetadata) int {
 	return minLen
 }
 
-// findMatchingRoute searches for a route that matches the given path.
-func (e *Engine) findMatchingRoute(path string) *Route {
-	for _, route := range e.routes {
-		if e.matchesPattern(route.Path, path) {
-			return route
+// resolveRoute returns the route path is shown with and its params: of the routes matching
+// path, the preferred one whose Validate hook accepts the params. It fails with
+// ErrRouteNotFound when no route matches, wrapping the validation error of the preferred
+// match when every matching route rejects its params. The caller must hold e.mu.
+func (e *Engine) resolveRoute(path string) (*Route, map[string]string, error) {
+	var invalid error
+	for _, m := range e.matchRoutes(path) {
+		if m.route.Validate != nil {
+			if err := m.route.Validate(m.params); err != nil {
+				if invalid == nil {
+					invalid = err
+				}
+				continue
+			}
 		}
+		return m.route, m.params, nil
 	}
-	return nil
-}
-
-// matchesPattern checks if an actual path matches a route pattern.
-// The pattern can contain parameters in curly braces, e.g., "/blog/{year}".
-func (e *Engine) matchesPattern(pattern, path string) bool {
-	pattern = strings.TrimSuffix(pattern, "/")
-	path = strings.TrimSuffix(path, "/")
-
-	if pattern == "" {
-		pattern = "/"
+	if invalid != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %w", ErrRouteNotFound, path, invalid)
 	}
-	if path == "" {
-		path = "/"
-	}
-
-	if pattern == path {
-		return true
-	}
-
-	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
-	pathParts := strings.Split(strings.Trim(path, "/"), "/")
-
-	if len(patternParts) != len(pathParts) {
-		return false
-	}
-
-	for i := range patternParts {
-		if strings.HasPrefix(patternParts[i], "{") && strings.HasSuffix(patternParts[i], "}") {
-			continue
-		}
-		if patternParts[i] != pathParts[i] {
-			return false
-		}
-	}
-
-	return true
+	return nil, nil, fmt.Errorf("%w: %s", ErrRouteNotFound, path)
 }
 
-// extractParams parses URL parameters from a path based on route pattern.
-func (e *Engine) extractParams(routePath, actualPath string) map[string]string {
-	routePath = strings.TrimSuffix(routePath, "/")
-	actualPath = strings.TrimSuffix(actualPath, "/")
-
-	if routePath == "" {
-		routePath = "/"
-	}
-	if actualPath == "" {
-		actualPath = "/"
-	}
-
-	routeParts := strings.Split(strings.Trim(routePath, "/"), "/")
-	actualParts := strings.Split(strings.Trim(actualPath, "/"), "/")
-
-	params := make(map[string]string)
-
-	for i := range routeParts {
-		if i >= len(actualParts) {
-			break
-		}
-		if strings.HasPrefix(routeParts[i], "{") && strings.HasSuffix(routeParts[i], "}") {
-			paramName := strings.Trim(routeParts[i], "{}")
-			params[paramName] = actualParts[i]
-		}
-	}
-
-	return params
+// matchesPattern checks if an actual path matches a route pattern (see routePattern).
+func (e *Engine) matchesPattern(pattern, path string) bool {
+	_, ok := parsePattern(pattern).match(path)
+	return ok
 }
 
 // CurrentPath returns the current route path.
@@ -334,24 +719,66 @@ func (e *Engine) CurrentPivotPoint() int {
 }
 
 // Start initializes the router and handles browser history.
+// A passive engine instead subscribes to the primary router's path broadcasts and
+// renders the route for the current browser path.
+// A renderer with a Hydrate method, like the browser's runtime.RendererImpl, is asked to
+// adopt a page pre-rendered into the mount element with the render of the initial route.
 // This implements the NavigationManager interface.
 func (e *Engine) Start(onChange func(chain []runtime.Component, key string)) error {
 	e.mu.Lock()
 	e.onRouteChange = onChange
+	renderer := e.renderer
 	e.mu.Unlock()
+	mode, app := e.settings()
 
-	e.popstateListener = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		console.Log("[Engine] popstate event fired")
-		browserPath := js.Global().Get("location").Get("pathname").String()
-		routePath := e.toRoutePath(browserPath)
-		console.Log("[Engine] popstate path:", browserPath, "-> route:", routePath)
-		e.navigateInternal(routePath, true)
-		return nil
-	})
-	js.Global().Call("addEventListener", "popstate", e.popstateListener)
-	console.Log("[Engine] popstate listener registered")
+	// A page pre-rendered into the mount element is adopted by the render of the initial
+	// route rather than replaced
+	if hydrator, ok := renderer.(interface{ Hydrate() bool }); ok {
+		hydrator.Hydrate()
+	}
+
+	if mode == ModePassive && app == nil {
+		return fmt.Errorf("router: passive mode requires an app instance (use nojs.Run or AttachApp)")
+	}
+
+	if mode == ModePrimary {
+		e.removePopstate = e.history.onPopState(func() {
+			e.log.Debug("[Engine] popstate event fired")
+			browserPath := e.history.pathname()
+			routePath := e.toRoutePath(browserPath)
+			e.log.Debug("[Engine] popstate path:", browserPath, "-> route:", routePath)
+			if err := e.popState(routePath); err == nil {
+				e.broadcastCurrentPath()
+			}
+		})
+		e.log.Debug("[Engine] popstate listener registered")
+
+		// Offsets are saved in each entry's history state and restored by the engine once the
+		// entry's route has rendered, not by the browser before it has
+		e.history.manualScrollRestoration()
+
+		// A reload or closing the tab leaves the entry: store the components' state in it
+		e.removePageHide = e.history.onPageHide(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.leaveHistoryEntry()
+		})
+
+		if app != nil {
+			app.OnNavigationRequest(func(source, browserPath string) {
+				e.log.Debug("[Engine] Navigation requested by app", source+":", browserPath)
+				err := e.Navigate(browserPath)
+				if err != nil && !errors.Is(err, ErrAlreadyCurrent) && !errors.Is(err, ErrAlreadyNavigating) {
+					e.log.Error("[Engine] Requested navigation failed:", err.Error())
+				}
+			})
+		}
+	} else {
+		app.OnPathBroadcast(e.followPath)
+		e.log.Debug("[Engine] Passive router following path broadcasts")
+	}
 
-	initialBrowserPath := js.Global().Get("location").Get("pathname").String()
+	initialBrowserPath := e.history.pathname()
 	e.mu.Lock()
 	if e.basePath == "" {
 		e.basePath = e.inferBasePath(initialBrowserPath)
@@ -360,39 +787,56 @@ func (e *Engine) Start(onChange func(chain []runtime.Component, key string)) err
 	basePath := e.basePath
 	e.mu.Unlock()
 
-	console.Log("[Engine.Start] Initial path:", initialBrowserPath, "base path:", basePath, "route path:", routePath)
+	search, hash := e.history.search(), e.history.hash()
+	if mode == ModePrimary {
+		if hashPath, hashSearch, ok := e.migrateHashURL(routePath, search); ok {
+			routePath, search, hash = hashPath, hashSearch, ""
+		}
+	}
+
+	e.log.Debug("[Engine.Start] Initial path:", initialBrowserPath, "base path:", basePath, "route path:", routePath)
 	if routePath == "" {
 		routePath = "/"
 	}
-	return e.Navigate(routePath)
+	if mode == ModePassive {
+		e.followPath(app.Name(), e.toBrowserPath(routePath))
+		return nil
+	}
+	// Keep the initial query string so it reaches RouteContext.Query, and the fragment for
+	// scrolling. The page's entry is taken over, with the state it kept across a reload.
+	return e.navigateTo(routePath+search+hash, historyAdopt)
 }
 
-// GetComponentForPath resolves a URL path to its component.
+// GetComponentForPath resolves a URL path to its component, created with the path's params.
+// Paths whose params the route's Validate hook rejects resolve to nothing, as in Navigate,
+// as do routes whose page has an AsyncFactory.
 // This implements the NavigationManager interface.
 func (e *Engine) GetComponentForPath(path string) (runtime.Component, bool) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	targetRoute, ok := e.routes[path]
-	if !ok {
+	targetRoute, params, err := e.resolveRoute(path)
+	if err != nil || len(targetRoute.Chain) == 0 {
 		return nil, false
 	}
-
-	if len(targetRoute.Chain) == 0 {
+	leaf := targetRoute.Chain[len(targetRoute.Chain)-1]
+	if leaf.Factory == nil {
 		return nil, false
 	}
-
-	params := e.extractParams(targetRoute.Path, path)
-	leaf := targetRoute.Chain[len(targetRoute.Chain)-1]
 	return leaf.Factory(params), true
 }
 
 // Cleanup releases resources held by the engine.
+// Listeners registered through an attached app instance are removed when the app unmounts.
 func (e *Engine) Cleanup() {
-	if !e.popstateListener.IsUndefined() {
-		js.Global().Call("removeEventListener", "popstate", e.popstateListener)
-		e.popstateListener.Release()
-		console.Log("[Engine] popstate listener cleaned up")
+	if e.removePopstate != nil {
+		e.removePopstate()
+		e.removePopstate = nil
+		e.log.Debug("[Engine] popstate listener cleaned up")
+	}
+	if e.removePageHide != nil {
+		e.removePageHide()
+		e.removePageHide = nil
 	}
 }
 
@@ -0,0 +1,33 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// pageMeta returns the metadata of a guardPage named name.
+func pageMeta(typeID uint32, name string) ComponentMetadata {
+	return ComponentMetadata{TypeID: typeID, Factory: func(map[string]string) runtime.Component { return &guardPage{name: name} }}
+}
+
+// newTestEngine returns an engine with routes, rendering through a routeTestRenderer.
+func newTestEngine(routes ...Route) *Engine {
+	engine := NewEngine(newRouteTestRenderer(&guardPage{name: "root"}))
+	engine.RegisterRoutes(routes)
+	return engine
+}
+
+// startTestEngine returns an engine with routes, started at the location of history.
+func startTestEngine(t *testing.T, history *memoryHistory, routes ...Route) *Engine {
+	t.Helper()
+	engine := newTestEngine(routes...)
+	engine.history = history
+	if err := engine.Start(func([]runtime.Component, string) {}); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	return engine
+}
@@ -0,0 +1,177 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ForgeLogic/nojs/runtime"
+)
+
+// routeUpdateHarness is an engine showing /admin (layout + dashboard) and recording the
+// chains it passes to the route change callback.
+type routeUpdateHarness struct {
+	engine *Engine
+	chains [][]runtime.Component
+}
+
+func newRouteUpdateHarness(t *testing.T) *routeUpdateHarness {
+	t.Helper()
+	h := &routeUpdateHarness{engine: newTestEngine(
+		Route{Path: "/", Chain: []ComponentMetadata{pageMeta(1, "layout"), pageMeta(2, "home")}},
+		Route{
+			Path:  "/admin",
+			Name:  "admin",
+			Meta:  map[string]string{"title": "Admin"},
+			Chain: []ComponentMetadata{pageMeta(1, "layout"), pageMeta(3, "dashboard")},
+		},
+	)}
+	h.engine.SetRouteChangeCallback(func(chain []runtime.Component, key string) {
+		h.chains = append(h.chains, chain)
+	})
+	if err := h.engine.Navigate("/admin"); err != nil {
+		t.Fatalf("Navigate(/admin): %v", err)
+	}
+	return h
+}
+
+// shown returns the chain of the last route change.
+func (h *routeUpdateHarness) shown() []runtime.Component {
+	return h.chains[len(h.chains)-1]
+}
+
+// names returns the names of the pages in chain.
+func names(chain []runtime.Component) []string {
+	var out []string
+	for _, c := range chain {
+		out = append(out, c.(*guardPage).name)
+	}
+	return out
+}
+
+// TestEngine_UpdateRouteRenavigatesInPlace verifies updating the active route with
+// re-navigation shows the new chain at once, preserving the instances whose TypeID matches
+// and keeping the route's name and metadata.
+func TestEngine_UpdateRouteRenavigatesInPlace(t *testing.T) {
+	// Arrange
+	h := newRouteUpdateHarness(t)
+	before := h.shown()
+	pushed := len(h.engine.history.(*memoryHistory).entries)
+
+	// Act
+	err := h.engine.UpdateRoute("/admin", []ComponentMetadata{pageMeta(1, "layout"), pageMeta(4, "dashboard-v2")}, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("UpdateRoute: %v", err)
+	}
+	after := h.shown()
+	if got := names(after); len(got) != 2 || got[1] != "dashboard-v2" {
+		t.Fatalf("expected the new dashboard to be shown, got %v", got)
+	}
+	if after[0] != before[0] {
+		t.Error("expected the layout with the same TypeID to be preserved")
+	}
+	if after[1] == before[1] {
+		t.Error("expected the page with a new TypeID to be re-created")
+	}
+	if ctx := h.engine.RouteContext(); ctx.Name != "admin" || ctx.Meta["title"] != "Admin" {
+		t.Errorf("expected the route name and metadata to survive, got %q %v", ctx.Name, ctx.Meta)
+	}
+	if entries := len(h.engine.history.(*memoryHistory).entries); entries != pushed {
+		t.Errorf("expected no history entry for the in-place navigation, got %d new", entries-pushed)
+	}
+}
+
+// TestEngine_UpdateRouteRecreatesChangedLayout verifies a new layout TypeID re-creates the
+// whole chain from the layout down.
+func TestEngine_UpdateRouteRecreatesChangedLayout(t *testing.T) {
+	// Arrange
+	h := newRouteUpdateHarness(t)
+	before := h.shown()
+
+	// Act
+	err := h.engine.UpdateRoute("/admin", []ComponentMetadata{pageMeta(5, "layout-v2"), pageMeta(3, "dashboard")}, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("UpdateRoute: %v", err)
+	}
+	after := h.shown()
+	if got := names(after); got[0] != "layout-v2" || got[1] != "dashboard" {
+		t.Fatalf("expected the new layout over the dashboard, got %v", got)
+	}
+	if after[1] == before[1] {
+		t.Error("expected the page under a re-created layout to be re-created")
+	}
+}
+
+// TestEngine_UpdateRouteWithoutRenavigation verifies the new chain waits for the next
+// navigation to the route.
+func TestEngine_UpdateRouteWithoutRenavigation(t *testing.T) {
+	// Arrange
+	h := newRouteUpdateHarness(t)
+	changes := len(h.chains)
+
+	// Act
+	err := h.engine.UpdateRoute("/admin", []ComponentMetadata{pageMeta(1, "layout"), pageMeta(4, "dashboard-v2")}, false)
+	unchanged := len(h.chains) == changes
+	navErr := h.engine.Navigate("/admin")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("UpdateRoute: %v", err)
+	}
+	if !unchanged {
+		t.Error("expected nothing to be re-created before the next navigation")
+	}
+	if navErr != nil {
+		t.Fatalf("expected navigating to the updated route to run, got %v", navErr)
+	}
+	if got := names(h.shown()); got[1] != "dashboard-v2" {
+		t.Errorf("expected the next navigation to show the new chain, got %v", got)
+	}
+}
+
+// TestEngine_RemoveActiveRoute verifies a removed route stays on screen until the next
+// navigation to it, which fails with ErrRouteNotFound.
+func TestEngine_RemoveActiveRoute(t *testing.T) {
+	// Arrange
+	h := newRouteUpdateHarness(t)
+	changes := len(h.chains)
+
+	// Act
+	err := h.engine.RemoveRoute("/admin")
+	navErr := h.engine.Navigate("/admin")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("RemoveRoute: %v", err)
+	}
+	if !errors.Is(navErr, ErrRouteNotFound) {
+		t.Errorf("expected ErrRouteNotFound, got %v", navErr)
+	}
+	if len(h.chains) != changes {
+		t.Error("expected the current view to be left alone")
+	}
+	if h.engine.CurrentPath() != "/admin" {
+		t.Errorf("expected the current path to stay /admin, got %q", h.engine.CurrentPath())
+	}
+}
+
+// TestEngine_UpdateUnknownRoute verifies updating or removing an unregistered path fails.
+func TestEngine_UpdateUnknownRoute(t *testing.T) {
+	// Arrange
+	h := newRouteUpdateHarness(t)
+
+	// Act
+	updateErr := h.engine.UpdateRoute("/missing", []ComponentMetadata{pageMeta(9, "missing")}, true)
+	removeErr := h.engine.RemoveRoute("/missing")
+
+	// Assert
+	if !errors.Is(updateErr, ErrRouteNotFound) || !errors.Is(removeErr, ErrRouteNotFound) {
+		t.Errorf("expected ErrRouteNotFound, got %v and %v", updateErr, removeErr)
+	}
+}
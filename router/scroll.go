@@ -0,0 +1,35 @@
+package router
+
+// scrollStateKey is the key the window's scroll offset is saved under in the history state
+// of each entry.
+const scrollStateKey = "nojs.scroll"
+
+// saveScrollPosition saves the window's scroll offset in the bag of the entry shown. It does
+// nothing for a passive engine, which leaves scrolling to the primary. The caller must hold
+// e.mu.
+func (e *Engine) saveScrollPosition() {
+	if mode, _ := e.settings(); mode != ModePrimary {
+		return
+	}
+	if err := e.routeCtx.History.Set(scrollStateKey, e.history.scrollY()); err != nil {
+		e.log.Warn("[Engine] Failed to save the scroll position:", err.Error())
+	}
+}
+
+// scroll scrolls the window for a navigation that has just rendered, following behavior:
+// back to the offset saved in the entry shown when returning to it, else to the element
+// fragment names, else to the top. The caller must hold e.mu.
+func (e *Engine) scroll(behavior ScrollBehavior, update historyUpdate, fragment string) {
+	if mode, _ := e.settings(); mode != ModePrimary || behavior == ScrollPreserve {
+		return
+	}
+	returning := update == historyNone || update == historyAdopt // back/forward, reload, in place
+	var offset float64
+	switch {
+	case behavior == ScrollRestore && returning && e.routeCtx.History.Get(scrollStateKey, &offset):
+		e.history.scrollTo(offset)
+	case fragment != "" && e.history.scrollIntoView(fragment):
+	default:
+		e.history.scrollTo(0)
+	}
+}
@@ -0,0 +1,117 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import "testing"
+
+// scrollRoutes are the routes /, /docs, /feed (ScrollTop) and /tabs/{tab} (ScrollPreserve).
+var scrollRoutes = []Route{
+	{Path: "/", Chain: []ComponentMetadata{pageMeta(1, "home")}},
+	{Path: "/docs", Chain: []ComponentMetadata{pageMeta(2, "docs")}},
+	{Path: "/feed", Chain: []ComponentMetadata{pageMeta(3, "feed")}, ScrollBehavior: ScrollTop},
+	{Path: "/tabs/{tab}", Chain: []ComponentMetadata{pageMeta(4, "tabs")}, ScrollBehavior: ScrollPreserve},
+}
+
+// TestScroll_TopOnNavigateAndRestoredOnBack verifies a new page starts at the top and going
+// back returns to the offset the user left the page at.
+func TestScroll_TopOnNavigateAndRestoredOnBack(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/"}
+	engine := startTestEngine(t, history, scrollRoutes...)
+	history.offset = 1200
+
+	// Act
+	engine.Navigate("/docs")
+	onDocs := history.offset
+	history.offset = 300
+	history.travel(-1)
+
+	// Assert
+	if !history.manualScroll {
+		t.Error("expected Start to take over scroll restoration from the browser")
+	}
+	if onDocs != 0 {
+		t.Errorf("expected /docs to start at the top, got %v", onDocs)
+	}
+	if history.offset != 1200 {
+		t.Errorf("expected back to restore offset 1200, got %v", history.offset)
+	}
+	history.travel(1)
+	if history.offset != 300 {
+		t.Errorf("expected forward to restore offset 300, got %v", history.offset)
+	}
+}
+
+// TestScroll_FragmentScrollsToElement verifies /docs#install keeps the fragment in the URL
+// and scrolls to the element with id install, falling back to the top for unknown ids.
+func TestScroll_FragmentScrollsToElement(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/", anchors: map[string]float64{"install": 640}}
+	engine := startTestEngine(t, history, scrollRoutes...)
+	history.offset = 90
+
+	// Act
+	err := engine.Navigate("/docs#install")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Navigate failed: %v", err)
+	}
+	if history.offset != 640 {
+		t.Errorf("expected the install section at offset 640, got %v", history.offset)
+	}
+	if got := history.entries[len(history.entries)-1]; got != "/docs#install" {
+		t.Errorf("expected /docs#install to be pushed, got %s", got)
+	}
+	if engine.CurrentPath() != "/docs" {
+		t.Errorf("expected route path /docs, got %s", engine.CurrentPath())
+	}
+	engine.Navigate("/feed#missing")
+	if history.offset != 0 {
+		t.Errorf("expected an unknown fragment to scroll to the top, got %v", history.offset)
+	}
+}
+
+// TestScroll_RouteBehaviors verifies ScrollTop ignores the saved offset on back and
+// ScrollPreserve leaves the window alone.
+func TestScroll_RouteBehaviors(t *testing.T) {
+	// Arrange
+	history := &memoryHistory{path: "/feed"}
+	engine := startTestEngine(t, history, scrollRoutes...)
+	history.offset = 800
+	engine.Navigate("/tabs/info")
+	history.offset = 500
+
+	// Act
+	engine.Navigate("/tabs/reviews")
+	preserved := history.offset
+	history.travel(-2)
+
+	// Assert
+	if preserved != 500 {
+		t.Errorf("expected switching tabs to keep offset 500, got %v", preserved)
+	}
+	if history.offset != 0 {
+		t.Errorf("expected /feed to start at the top on back, got %v", history.offset)
+	}
+}
+
+// TestScroll_RestoredAfterReload verifies the offset saved when the page is hidden is
+// restored by the engine started after the reload.
+func TestScroll_RestoredAfterReload(t *testing.T) {
+	// Arrange
+	before := &memoryHistory{path: "/"}
+	engine := startTestEngine(t, before, scrollRoutes...)
+	engine.Navigate("/docs")
+	before.offset = 2400
+
+	// Act
+	after := before.reload()
+	startTestEngine(t, after, scrollRoutes...)
+
+	// Assert
+	if after.offset != 2400 {
+		t.Errorf("expected offset 2400 after the reload, got %v", after.offset)
+	}
+}
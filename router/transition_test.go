@@ -0,0 +1,40 @@
+//go:build !wasm
+// +build !wasm
+
+package router
+
+import (
+	"testing"
+	"time"
+)
+
+// TestEngine_MarksPageWithRouteTransition verifies that, without an AppShell, the root VNode
+// of a page swapped into the layout carries the route's transition and a key of its own, so
+// the patcher replaces the outgoing page with it, while a route without one leaves it as is.
+func TestEngine_MarksPageWithRouteTransition(t *testing.T) {
+	// Arrange
+	var log []string
+	engine := newTestEngine(lifecycleRoutes(&log)...)
+	engine.routes["/{id}"].Transition = &Transition{Duration: 300 * time.Millisecond, LeaveClass: "slide-out"}
+	if err := engine.Navigate("/"); err != nil {
+		t.Fatalf("Navigate(/): %v", err)
+	}
+	layout := engine.liveInstances[0].(*lifecyclePage)
+	if home := layout.BodyContent[0]; home.Transition != nil || home.ComponentKey != "" {
+		t.Fatalf("expected the page of a route without transition unmarked, got %+v", home)
+	}
+
+	// Act
+	if err := engine.Navigate("/7"); err != nil {
+		t.Fatalf("Navigate(/7): %v", err)
+	}
+
+	// Assert
+	page := layout.BodyContent[0]
+	if page.Transition == nil || page.Transition.Enter != "nojs-enter" || page.Transition.Leave != "slide-out" || page.Transition.Timeout != 300*time.Millisecond {
+		t.Errorf("expected the route's transition on the page, got %+v", page.Transition)
+	}
+	if page.ComponentKey == "" {
+		t.Error("expected the page keyed so it replaces the outgoing one")
+	}
+}